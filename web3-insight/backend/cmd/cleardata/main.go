@@ -34,13 +34,30 @@ func main() {
 
 	// Clear tables in order (respecting foreign keys)
 	tables := []string{
+		"research_sessions",
+		"research_schedules",
 		"article_versions",
+		"article_chunks",
+		"article_prerequisites",
+		"article_links",
+		"article_relations",
+		"term_articles",
+		"terms",
+		"slug_redirects",
+		"article_reviews",
+		"wiki_pages",
+		"consistency_reports",
 		"chat_messages",
 		"articles",
+		"category_duplicates",
+		"category_merges",
 		"categories",
+		"tags",
 		"tasks",
 		"news_items",
 		"data_sources",
+		"content_changes",
+		"search_logs",
 	}
 
 	for _, table := range tables {
@@ -0,0 +1,113 @@
+package main
+
+// evalCategoryTree is the fixed category tree classification cases are run against
+const evalCategoryTree = `- 基础技术
+- 基础技术/区块链原理
+- 基础技术/区块链原理/共识机制
+- 基础技术/密码学
+- 基础技术/密码学/零知识证明
+- 扩容方案
+- 扩容方案/Layer 2
+- 扩容方案/Layer 2/Optimistic Rollup
+- 扩容方案/Layer 2/ZK Rollup
+- DeFi
+- DeFi/去中心化交易所
+- DeFi/借贷协议
+- DeFi/稳定币
+- 智能合约
+- 智能合约/安全审计
+- 智能合约/Solidity
+- 监管合规`
+
+// classificationCase is an article with the category path it should be filed under
+type classificationCase struct {
+	Title        string
+	Summary      string
+	ExpectedPath string
+}
+
+var classificationCases = []classificationCase{
+	{
+		Title:        "Proof of Stake 与 Proof of Work 的安全性对比",
+		Summary:      "比较 PoS 与 PoW 两种共识机制在 51% 攻击、长程攻击和最终性方面的差异，以及以太坊合并后验证者的惩罚机制。",
+		ExpectedPath: "基础技术/区块链原理/共识机制",
+	},
+	{
+		Title:        "zk-SNARK 电路入门",
+		Summary:      "介绍 R1CS 约束系统、可信设置以及 Groth16 证明的生成与验证流程，并用 Circom 编写一个简单电路。",
+		ExpectedPath: "基础技术/密码学/零知识证明",
+	},
+	{
+		Title:        "Arbitrum 的欺诈证明是如何工作的",
+		Summary:      "讲解 Optimistic Rollup 的挑战期、交互式欺诈证明的二分协议，以及 7 天提款延迟的由来。",
+		ExpectedPath: "扩容方案/Layer 2/Optimistic Rollup",
+	},
+	{
+		Title:        "Uniswap v3 集中流动性详解",
+		Summary:      "解释恒定乘积 AMM 的局限、v3 的价格区间与 tick 设计，以及 LP 面临的无常损失。",
+		ExpectedPath: "DeFi/去中心化交易所",
+	},
+	{
+		Title:        "Aave 清算机制与健康因子",
+		Summary:      "分析借贷协议中抵押率、健康因子与清算奖励的计算方式，以及闪电贷在清算中的作用。",
+		ExpectedPath: "DeFi/借贷协议",
+	},
+	{
+		Title:        "重入攻击：从 The DAO 到今天",
+		Summary:      "回顾 The DAO 事件，说明重入漏洞的原理、checks-effects-interactions 模式和审计时的检查要点。",
+		ExpectedPath: "智能合约/安全审计",
+	},
+	{
+		Title:        "MiCA 法规对稳定币发行方的要求",
+		Summary:      "梳理欧盟 MiCA 对电子货币代币与资产参考代币的牌照、储备和披露要求。",
+		ExpectedPath: "监管合规",
+	},
+}
+
+// summaryCase is an English news item to be summarized in Chinese
+type summaryCase struct {
+	Title   string
+	Content string
+}
+
+var summaryCases = []summaryCase{
+	{
+		Title: "Ethereum developers set date for Pectra upgrade on mainnet",
+		Content: `Ethereum core developers agreed on Thursday to activate the Pectra upgrade on mainnet in May, following successful deployments on the Holesky and Sepolia testnets. ` +
+			`The upgrade bundles EIP-7702, which lets externally owned accounts temporarily act as smart contract wallets, and EIP-7251, which raises the maximum effective balance of a validator from 32 ETH to 2,048 ETH. ` +
+			`Developers said the higher balance cap should reduce the number of validators on the network and ease the load on the peer-to-peer layer.`,
+	},
+	{
+		Title: "Circle files for IPO on the New York Stock Exchange",
+		Content: `Circle, the issuer of the USDC stablecoin, has filed for an initial public offering on the New York Stock Exchange under the ticker CRCL. ` +
+			`According to the S-1 filing, the company earned $1.68 billion in revenue last year, most of it from interest on the reserves backing USDC. ` +
+			`USDC has a circulating supply of about $60 billion, making it the second-largest stablecoin after Tether's USDT.`,
+	},
+	{
+		Title: "Lido introduces dual governance to give stETH holders a veto",
+		Content: `Lido DAO has approved a dual governance mechanism that allows stETH holders to delay and ultimately block proposals from LDO token holders. ` +
+			`If enough stETH is locked in an escrow contract, proposals are paused and stakers are given time to withdraw before a change takes effect. ` +
+			`The design aims to protect stakers from governance decisions that could harm them, a long-standing criticism of liquid staking protocols.`,
+	},
+}
+
+// judgePrompt asks the judge model to rate a Chinese summary of an English news item
+const judgePrompt = `你是一个严格的 Web3 新闻编辑评审。请评估下面的中文摘要相对于英文原文的质量。
+
+评分标准（1-5 分）：
+- 准确性：数字、公司名、技术术语是否与原文一致，是否有编造内容
+- 完整性：是否涵盖原文的关键信息
+- 表达：中文是否通顺，专业术语是否使用「英文 (中文)」格式
+
+原文标题：%s
+
+原文内容：
+%s
+
+中文标题：%s
+
+中文摘要：
+%s
+
+请只返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{"score": 4, "reason": "简要评分理由"}`
@@ -0,0 +1,280 @@
+// backend/cmd/llmeval/main.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/service"
+)
+
+// judgeSchema is the JSON schema the judge model's verdict must match
+var judgeSchema = llm.MustJSONSchema("summary_judgement", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"score":  map[string]interface{}{"type": "number", "minimum": 1, "maximum": 5},
+		"reason": map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"score"},
+})
+
+// AdapterReport aggregates the evaluation results of one adapter
+type AdapterReport struct {
+	Adapter                string  `json:"adapter"`
+	Type                   string  `json:"type"`
+	ClassificationCases    int     `json:"classificationCases"`
+	ClassificationCorrect  int     `json:"classificationCorrect"`
+	ClassificationAccuracy float64 `json:"classificationAccuracy"`
+	JSONCalls              int     `json:"jsonCalls"`
+	JSONValid              int     `json:"jsonValid"`
+	JSONValidityRate       float64 `json:"jsonValidityRate"`
+	SummariesJudged        int     `json:"summariesJudged"`
+	AvgJudgeScore          float64 `json:"avgJudgeScore"`
+	AvgLatencyMs           int64   `json:"avgLatencyMs"`
+	InputTokens            int     `json:"inputTokens"`
+	OutputTokens           int     `json:"outputTokens"`
+	CostUSD                float64 `json:"costUsd"`
+	Errors                 int     `json:"errors"`
+
+	judgeTotal float64
+	latency    time.Duration
+	calls      int
+}
+
+func main() {
+	adaptersFlag := flag.String("adapters", "", "Comma-separated adapters to evaluate (default: all available)")
+	judgeFlag := flag.String("judge", "", "Adapter used as summary judge (default: configured Claude or OpenAI model)")
+	format := flag.String("format", "table", "Report format: table or json")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	router := llm.NewRouterFromConfig(&cfg.LLM)
+
+	names := selectAdapters(router, *adaptersFlag)
+	if len(names) == 0 {
+		log.Fatal("No available adapters to evaluate")
+	}
+
+	judge := *judgeFlag
+	if judge == "" {
+		judge = defaultJudge(router, &cfg.LLM)
+	}
+	if judge == "" {
+		log.Println("No judge model available, summary quality will not be scored")
+	}
+
+	reports := make([]*AdapterReport, 0, len(names))
+	for _, name := range names {
+		log.Printf("Evaluating %s...", name)
+		reports = append(reports, evaluate(router, name, judge))
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{"judge": judge, "adapters": reports}); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	default:
+		printTable(reports, judge)
+	}
+}
+
+// selectAdapters returns the requested adapters, or every available one, sorted by name
+func selectAdapters(router *llm.Router, requested string) []string {
+	var names []string
+	if requested != "" {
+		for _, name := range strings.Split(requested, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, ok := router.GetAdapter(name); !ok {
+				log.Printf("Unknown adapter %q, skipping", name)
+				continue
+			}
+			names = append(names, name)
+		}
+	} else {
+		for name, adapter := range router.ListAdapters() {
+			if adapter.IsAvailable() {
+				names = append(names, name)
+			} else {
+				log.Printf("Adapter %s is not available, skipping", name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultJudge prefers the configured cloud models as judge
+func defaultJudge(router *llm.Router, cfg *config.LLMConfig) string {
+	for _, name := range []string{cfg.Claude.DefaultModel, cfg.OpenAI.DefaultModel} {
+		if name == "" {
+			continue
+		}
+		if adapter, ok := router.GetAdapter(name); ok && adapter.IsAvailable() {
+			return name
+		}
+	}
+	return ""
+}
+
+// evaluate runs every case against one adapter
+func evaluate(router *llm.Router, name, judge string) *AdapterReport {
+	adapter, _ := router.GetAdapter(name)
+	report := &AdapterReport{Adapter: name, Type: adapter.Type()}
+
+	for _, tc := range classificationCases {
+		prompt := fmt.Sprintf(service.PromptClassification, evalCategoryTree, tc.Title, tc.Summary)
+		document, ok := report.generateJSON(router, name, prompt, service.ClassificationSchema, 500)
+		report.ClassificationCases++
+		if !ok {
+			continue
+		}
+
+		var result service.ClassificationResult
+		if err := json.Unmarshal([]byte(document), &result); err != nil {
+			continue
+		}
+		if matchesPath(result.CategoryPath, tc.ExpectedPath) {
+			report.ClassificationCorrect++
+		}
+	}
+
+	for _, tc := range summaryCases {
+		prompt := fmt.Sprintf(service.PromptNewsSummary, tc.Title, tc.Content)
+		document, ok := report.generateJSON(router, name, prompt, service.SummarySchema, 1000)
+		if !ok || judge == "" {
+			continue
+		}
+
+		var summary service.SummaryResult
+		if err := json.Unmarshal([]byte(document), &summary); err != nil {
+			continue
+		}
+		score, err := judgeSummary(router, judge, tc, &summary)
+		if err != nil {
+			log.Printf("Judging summary from %s failed: %v", name, err)
+			continue
+		}
+		report.SummariesJudged++
+		report.judgeTotal += score
+	}
+
+	report.finalize()
+	return report
+}
+
+// generateJSON makes one JSON-mode call, records its metrics and reports whether the output is schema-valid
+func (r *AdapterReport) generateJSON(router *llm.Router, name, prompt string, schema *llm.JSONSchema, maxTokens int) (string, bool) {
+	startedAt := time.Now()
+	result, err := router.GenerateJSONWithModel(name, prompt, schema, &llm.GenerateOptions{
+		Temperature: 0.2,
+		MaxTokens:   maxTokens,
+		NoCache:     true,
+	})
+	r.latency += time.Since(startedAt)
+	r.calls++
+	r.JSONCalls++
+	if err != nil {
+		log.Printf("%s call failed: %v", name, err)
+		r.Errors++
+		return "", false
+	}
+
+	r.InputTokens += result.Usage.InputTokens
+	r.OutputTokens += result.Usage.OutputTokens
+	r.CostUSD += result.CostUSD
+
+	if err := schema.Validate([]byte(result.Content)); err != nil {
+		return result.Content, false
+	}
+	r.JSONValid++
+	return result.Content, true
+}
+
+// finalize computes rates and averages from the running totals
+func (r *AdapterReport) finalize() {
+	if r.ClassificationCases > 0 {
+		r.ClassificationAccuracy = float64(r.ClassificationCorrect) / float64(r.ClassificationCases)
+	}
+	if r.JSONCalls > 0 {
+		r.JSONValidityRate = float64(r.JSONValid) / float64(r.JSONCalls)
+	}
+	if r.SummariesJudged > 0 {
+		r.AvgJudgeScore = r.judgeTotal / float64(r.SummariesJudged)
+	}
+	if r.calls > 0 {
+		r.AvgLatencyMs = (r.latency / time.Duration(r.calls)).Milliseconds()
+	}
+}
+
+// judgeSummary asks the judge model to score a summary from 1 to 5
+func judgeSummary(router *llm.Router, judge string, tc summaryCase, summary *service.SummaryResult) (float64, error) {
+	prompt := fmt.Sprintf(judgePrompt, tc.Title, tc.Content, summary.Title, summary.Summary)
+	result, err := router.GenerateJSONWithModel(judge, prompt, judgeSchema, &llm.GenerateOptions{
+		Temperature: 0,
+		MaxTokens:   300,
+		NoCache:     true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := judgeSchema.Validate([]byte(result.Content)); err != nil {
+		return 0, fmt.Errorf("invalid verdict: %w", err)
+	}
+
+	var verdict struct {
+		Score float64 `json:"score"`
+	}
+	if err := json.Unmarshal([]byte(result.Content), &verdict); err != nil {
+		return 0, err
+	}
+	return verdict.Score, nil
+}
+
+// matchesPath compares category paths, ignoring surrounding whitespace and slashes
+func matchesPath(got, expected string) bool {
+	return strings.Trim(strings.TrimSpace(got), "/") == expected
+}
+
+// printTable writes the comparison report as an aligned table
+func printTable(reports []*AdapterReport, judge string) {
+	if judge == "" {
+		judge = "-"
+	}
+	fmt.Printf("LLM evaluation: %d classification cases, %d summary cases, judge: %s\n\n",
+		len(classificationCases), len(summaryCases), judge)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ADAPTER\tTYPE\tCLASSIFY ACC\tJSON VALID\tJUDGE SCORE\tAVG LATENCY\tTOKENS IN/OUT\tCOST\tERRORS")
+	for _, r := range reports {
+		judgeScore := "-"
+		if r.SummariesJudged > 0 {
+			judgeScore = fmt.Sprintf("%.2f/5 (%d)", r.AvgJudgeScore, r.SummariesJudged)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.0f%% (%d/%d)\t%.0f%% (%d/%d)\t%s\t%dms\t%d/%d\t$%.4f\t%d\n",
+			r.Adapter, r.Type,
+			r.ClassificationAccuracy*100, r.ClassificationCorrect, r.ClassificationCases,
+			r.JSONValidityRate*100, r.JSONValid, r.JSONCalls,
+			judgeScore, r.AvgLatencyMs, r.InputTokens, r.OutputTokens, r.CostUSD, r.Errors)
+	}
+	w.Flush()
+}
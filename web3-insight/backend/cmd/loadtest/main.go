@@ -0,0 +1,178 @@
+// backend/cmd/loadtest/main.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/llm"
+)
+
+// Report is the load test result, printed as a table or JSON
+type Report struct {
+	BaseURL     string            `json:"baseUrl"`
+	Duration    string            `json:"duration"`
+	Concurrency int               `json:"concurrency"`
+	Articles    int               `json:"articles"` // Articles the traffic picked IDs from
+	Scenarios   []*ScenarioReport `json:"scenarios"`
+	Passed      bool              `json:"passed"`
+}
+
+func main() {
+	seedArticlesFlag := flag.Int("articles", 0, "Synthetic articles to insert before the run")
+	seedNewsFlag := flag.Int("news", 0, "Synthetic news items to insert before the run")
+	cleanup := flag.Bool("cleanup", false, "Delete all synthetic articles and news, then exit")
+	baseURL := flag.String("url", "", "API base URL (default: loadtest.base_url)")
+	duration := flag.Duration("duration", 30*time.Second, "How long to replay traffic")
+	concurrency := flag.Int("concurrency", 10, "Concurrent clients")
+	mixFlag := flag.String("mix", "", "Scenario weights, e.g. search=50,article_get=50 (default mix otherwise)")
+	maxErrorRate := flag.Float64("max-error-rate", 0.01, "Highest error rate a scenario may have and pass")
+	seed := flag.Int64("seed", 1, "Random seed for synthetic data and traffic")
+	format := flag.String("format", "table", "Report format: table or json")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Connect to database
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if *cleanup {
+		articles, news, err := cleanupSeed(db)
+		if err != nil {
+			log.Fatalf("Cleanup failed: %v", err)
+		}
+		log.Printf("Deleted %d synthetic articles and %d news items", articles, news)
+		return
+	}
+
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		log.Fatalf("Invalid -mix: %v", err)
+	}
+
+	// Fake embeddings must match the vector columns, which match the configured adapter
+	rng := rand.New(rand.NewSource(*seed))
+	dimensions := llm.NewEmbeddingAdapterFromConfig(&cfg.LLM).Dimensions()
+	if *seedArticlesFlag > 0 {
+		start := time.Now()
+		if err := seedArticles(db, *seedArticlesFlag, dimensions, rng); err != nil {
+			log.Fatalf("Seeding articles failed: %v", err)
+		}
+		log.Printf("Inserted %d synthetic articles in %v", *seedArticlesFlag, time.Since(start).Round(time.Millisecond))
+	}
+	if *seedNewsFlag > 0 {
+		start := time.Now()
+		if err := seedNews(db, *seedNewsFlag, dimensions, rng); err != nil {
+			log.Fatalf("Seeding news failed: %v", err)
+		}
+		log.Printf("Inserted %d synthetic news items in %v", *seedNewsFlag, time.Since(start).Round(time.Millisecond))
+	}
+
+	// Article requests pick from a sample of all articles, synthetic or not
+	var ids []string
+	if err := db.Raw("SELECT id::text FROM articles WHERE status = ? ORDER BY random() LIMIT 5000", "published").Scan(&ids).Error; err != nil {
+		log.Fatalf("Failed to sample articles: %v", err)
+	}
+	if len(ids) == 0 {
+		log.Fatal("No published articles to request; seed some with -articles")
+	}
+
+	url := *baseURL
+	if url == "" {
+		url = cfg.LoadTest.BaseURL
+	}
+	if url == "" {
+		url = "http://localhost:8080"
+	}
+	url = strings.TrimRight(url, "/")
+
+	r := &runner{
+		baseURL: url,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency},
+		},
+		mix:     mix,
+		ids:     ids,
+		reports: make(map[string]*ScenarioReport),
+	}
+
+	log.Printf("Replaying traffic against %s for %v with %d clients", url, *duration, *concurrency)
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	start := time.Now()
+	r.run(ctx, *concurrency, *seed)
+	cancel()
+	elapsed := time.Since(start)
+
+	report := &Report{
+		BaseURL:     url,
+		Duration:    elapsed.Round(time.Millisecond).String(),
+		Concurrency: *concurrency,
+		Articles:    len(ids),
+		Scenarios:   r.results(elapsed, cfg.LoadTest.Budgets, *maxErrorRate),
+		Passed:      true,
+	}
+	for _, s := range report.Scenarios {
+		report.Passed = report.Passed && s.Passed
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		printTable(report)
+	}
+
+	// A non-zero exit lets CI fail the release on a budget regression
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+// printTable writes the latency report as an aligned table
+func printTable(report *Report) {
+	fmt.Printf("Load test: %s, %s, %d clients, %d articles\n\n", report.BaseURL, report.Duration, report.Concurrency, report.Articles)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCENARIO\tREQUESTS\tERRORS\tRPS\tP50\tP95\tP99\tMAX\tBUDGET\tRESULT")
+	for _, s := range report.Scenarios {
+		budget := "-"
+		if s.BudgetMs > 0 {
+			budget = fmt.Sprintf("%dms", s.BudgetMs)
+		}
+		result := "PASS"
+		if !s.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.1fms\t%.1fms\t%.1fms\t%.1fms\t%s\t%s\n",
+			s.Scenario, s.Requests, s.Errors, s.RPS, s.P50Ms, s.P95Ms, s.P99Ms, s.MaxMs, budget, result)
+	}
+	w.Flush()
+
+	if report.Passed {
+		fmt.Println("\nAll scenarios within budget")
+	} else {
+		fmt.Println("\nPerformance budget exceeded")
+	}
+}
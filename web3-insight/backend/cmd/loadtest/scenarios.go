@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scenario is one kind of request in the traffic mix
+type scenario struct {
+	name   string
+	weight int // Share of requests in the default mix
+	path   func(rng *rand.Rand, ids []string) string
+}
+
+// scenarios approximates reader traffic: mostly browsing, some search, little semantic
+// search since each one also calls the embedding provider
+var scenarios = []scenario{
+	{"article_list", 25, func(rng *rand.Rand, _ []string) string {
+		order := []string{"newest", "difficulty"}[rng.Intn(2)]
+		return fmt.Sprintf("/api/articles?page=%d&page_size=20&sort=%s", 1+rng.Intn(5), order)
+	}},
+	{"article_get", 30, func(rng *rand.Rand, ids []string) string {
+		return "/api/articles/" + ids[rng.Intn(len(ids))]
+	}},
+	{"category_tree", 10, func(*rand.Rand, []string) string {
+		return "/api/categories/tree"
+	}},
+	{"search", 12, func(rng *rand.Rand, _ []string) string {
+		return "/api/search?q=" + url.QueryEscape(pick(rng, 1)) + "&limit=20"
+	}},
+	{"semantic_search", 3, func(rng *rand.Rand, _ []string) string {
+		return "/api/search/semantic?q=" + url.QueryEscape(pick(rng, 2)) + "&limit=10"
+	}},
+	{"related", 8, func(rng *rand.Rand, ids []string) string {
+		return "/api/articles/" + ids[rng.Intn(len(ids))] + "/related?limit=5"
+	}},
+	{"news_list", 10, func(rng *rand.Rand, _ []string) string {
+		return fmt.Sprintf("/api/news?page=%d&limit=20", 1+rng.Intn(5))
+	}},
+	{"changes", 2, func(*rand.Rand, []string) string {
+		return "/api/changes?limit=500"
+	}},
+}
+
+// parseMix overrides scenario weights from "name=weight,..."; unlisted scenarios keep their weight
+func parseMix(raw string) ([]scenario, error) {
+	mix := make([]scenario, len(scenarios))
+	copy(mix, scenarios)
+	if raw == "" {
+		return mix, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		weight, err := strconv.Atoi(value)
+		if !ok || err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid mix entry %q, expected name=weight", part)
+		}
+		found := false
+		for i := range mix {
+			if mix[i].name == name {
+				mix[i].weight = weight
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown scenario %q", name)
+		}
+	}
+
+	total := 0
+	for _, s := range mix {
+		total += s.weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("every scenario has weight 0")
+	}
+	return mix, nil
+}
+
+// ScenarioReport summarizes the latencies of one scenario
+type ScenarioReport struct {
+	Scenario string  `json:"scenario"`
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"` // Transport errors and non-2xx responses
+	RPS      float64 `json:"rps"`
+	P50Ms    float64 `json:"p50Ms"`
+	P95Ms    float64 `json:"p95Ms"`
+	P99Ms    float64 `json:"p99Ms"`
+	MaxMs    float64 `json:"maxMs"`
+	BudgetMs int     `json:"budgetMs,omitempty"` // p95 budget; 0 if none
+	Passed   bool    `json:"passed"`
+
+	latencies []time.Duration
+}
+
+// runner replays the traffic mix with a fixed number of concurrent clients
+type runner struct {
+	baseURL string
+	client  *http.Client
+	mix     []scenario
+	ids     []string
+
+	mu      sync.Mutex
+	reports map[string]*ScenarioReport
+}
+
+// run sends requests until ctx is done
+func (r *runner) run(ctx context.Context, concurrency int, seed int64) {
+	total := 0
+	for _, s := range r.mix {
+		total += s.weight
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(rng *rand.Rand) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				r.do(ctx, r.choose(rng, total), rng)
+			}
+		}(rand.New(rand.NewSource(seed + int64(i))))
+	}
+	wg.Wait()
+}
+
+// choose picks a scenario by weight
+func (r *runner) choose(rng *rand.Rand, total int) scenario {
+	n := rng.Intn(total)
+	for _, s := range r.mix {
+		if n < s.weight {
+			return s
+		}
+		n -= s.weight
+	}
+	return r.mix[len(r.mix)-1]
+}
+
+// do sends one request and records its latency
+func (r *runner) do(ctx context.Context, s scenario, rng *rand.Rand) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+s.path(rng, r.ids), nil)
+	if err != nil {
+		r.record(s.name, 0, false)
+		return
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return // Interrupted by the end of the run; not a server error
+		}
+		r.record(s.name, time.Since(start), false)
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	r.record(s.name, time.Since(start), resp.StatusCode < 300)
+}
+
+// record adds one request to its scenario's report
+func (r *runner) record(name string, latency time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report, exists := r.reports[name]
+	if !exists {
+		report = &ScenarioReport{Scenario: name}
+		r.reports[name] = report
+	}
+	report.Requests++
+	if !ok {
+		report.Errors++
+		return
+	}
+	report.latencies = append(report.latencies, latency)
+}
+
+// results computes percentiles and checks budgets, in scenario order
+func (r *runner) results(elapsed time.Duration, budgets map[string]int, maxErrorRate float64) []*ScenarioReport {
+	var reports []*ScenarioReport
+	for _, s := range r.mix {
+		report, ok := r.reports[s.name]
+		if !ok {
+			continue
+		}
+		sort.Slice(report.latencies, func(i, j int) bool { return report.latencies[i] < report.latencies[j] })
+		report.RPS = float64(report.Requests) / elapsed.Seconds()
+		report.P50Ms = percentile(report.latencies, 0.50)
+		report.P95Ms = percentile(report.latencies, 0.95)
+		report.P99Ms = percentile(report.latencies, 0.99)
+		report.MaxMs = percentile(report.latencies, 1)
+		report.BudgetMs = budgets[s.name]
+
+		errorRate := float64(report.Errors) / float64(report.Requests)
+		report.Passed = errorRate <= maxErrorRate && (report.BudgetMs == 0 || report.P95Ms <= float64(report.BudgetMs))
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies in milliseconds
+func percentile(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(float64(len(sorted))*p+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return float64(sorted[rank].Microseconds()) / 1000
+}
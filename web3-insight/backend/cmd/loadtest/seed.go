@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Synthetic rows are recognizable by these prefixes so -cleanup removes only them
+const (
+	seedSlugPrefix = "loadtest-"
+	seedNewsURL    = "https://loadtest.invalid/news/"
+	seedBatchSize  = 200
+)
+
+// vocabulary is used for synthetic titles and content, and as search queries
+var vocabulary = []string{
+	"Ethereum", "Rollup", "ZK Proof", "Optimistic Rollup", "EVM", "Solidity", "Uniswap", "AMM",
+	"Liquidity", "Staking", "Restaking", "MEV", "Account Abstraction", "Bridge", "Layer 2",
+	"Data Availability", "Consensus", "Validator", "Smart Contract", "Oracle", "Stablecoin",
+	"DeFi", "NFT", "DAO", "Governance", "Gas", "Sharding", "Blob", "Sequencer", "Wallet",
+	"以太坊", "零知识证明", "流动性", "质押", "跨链桥", "智能合约", "预言机", "稳定币",
+}
+
+// seedArticles inserts n synthetic published articles with random embeddings spread over
+// the existing categories, then refreshes the categories' article counts
+func seedArticles(db *gorm.DB, n, dimensions int, rng *rand.Rand) error {
+	var categoryIDs []uuid.UUID
+	if err := db.Model(&model.Category{}).Pluck("id", &categoryIDs).Error; err != nil {
+		return fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	runID := time.Now().Format("20060102150405")
+	for start := 0; start < n; start += seedBatchSize {
+		batch := make([]model.Article, 0, seedBatchSize)
+		for i := start; i < n && i < start+seedBatchSize; i++ {
+			topic := pick(rng, 3)
+			article := model.Article{
+				Title:      topic + " 详解",
+				Slug:       fmt.Sprintf("%s%s-%d", seedSlugPrefix, runID, i),
+				Content:    syntheticContent(rng, topic, 40),
+				Summary:    syntheticSentence(rng),
+				Tags:       pq.StringArray{pick(rng, 1), pick(rng, 1)},
+				Status:     "published",
+				Difficulty: []string{"beginner", "intermediate", "advanced"}[rng.Intn(3)],
+				ModelUsed:  "loadtest",
+				ViewCount:  rng.Intn(1000),
+				Embedding:  randomEmbedding(rng, dimensions),
+			}
+			if len(categoryIDs) > 0 {
+				id := categoryIDs[rng.Intn(len(categoryIDs))]
+				article.CategoryID = &id
+			}
+			batch = append(batch, article)
+		}
+		if err := db.Create(&batch).Error; err != nil {
+			return fmt.Errorf("failed to insert articles: %w", err)
+		}
+	}
+
+	return refreshCategoryCounts(db, categoryIDs)
+}
+
+// seedNews inserts n synthetic summarized news items with random embeddings
+func seedNews(db *gorm.DB, n, dimensions int, rng *rand.Rand) error {
+	runID := time.Now().Format("20060102150405")
+	for start := 0; start < n; start += seedBatchSize {
+		batch := make([]model.NewsItem, 0, seedBatchSize)
+		for i := start; i < n && i < start+seedBatchSize; i++ {
+			publishedAt := time.Now().Add(-time.Duration(rng.Intn(30*24)) * time.Hour)
+			batch = append(batch, model.NewsItem{
+				Title:          pick(rng, 3),
+				Content:        syntheticContent(rng, pick(rng, 1), 10),
+				Summary:        syntheticSentence(rng),
+				SourceURL:      fmt.Sprintf("%s%s/%d", seedNewsURL, runID, i),
+				SourceName:     "loadtest",
+				SourceLanguage: "en",
+				Tags:           pq.StringArray{pick(rng, 1)},
+				PublishedAt:    &publishedAt,
+				Processed:      true,
+				Embedding:      randomEmbedding(rng, dimensions),
+			})
+		}
+		if err := db.Create(&batch).Error; err != nil {
+			return fmt.Errorf("failed to insert news: %w", err)
+		}
+	}
+	return nil
+}
+
+// cleanupSeed deletes every synthetic article and news item and refreshes category counts
+func cleanupSeed(db *gorm.DB) (articles, news int64, err error) {
+	var categoryIDs []uuid.UUID
+	if err := db.Model(&model.Category{}).Pluck("id", &categoryIDs).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	result := db.Where("slug LIKE ?", seedSlugPrefix+"%").Delete(&model.Article{})
+	if result.Error != nil {
+		return 0, 0, fmt.Errorf("failed to delete articles: %w", result.Error)
+	}
+	articles = result.RowsAffected
+
+	result = db.Where("source_url LIKE ?", seedNewsURL+"%").Delete(&model.NewsItem{})
+	if result.Error != nil {
+		return articles, 0, fmt.Errorf("failed to delete news: %w", result.Error)
+	}
+	news = result.RowsAffected
+
+	return articles, news, refreshCategoryCounts(db, categoryIDs)
+}
+
+// refreshCategoryCounts recomputes article counts through the repository
+func refreshCategoryCounts(db *gorm.DB, categoryIDs []uuid.UUID) error {
+	categoryRepo := repository.NewCategoryRepository(db)
+	for _, id := range categoryIDs {
+		if err := categoryRepo.UpdateArticleCount(id); err != nil {
+			return fmt.Errorf("failed to update article count: %w", err)
+		}
+	}
+	return nil
+}
+
+// pick joins n random vocabulary terms
+func pick(rng *rand.Rand, n int) string {
+	terms := make([]string, n)
+	for i := range terms {
+		terms[i] = vocabulary[rng.Intn(len(vocabulary))]
+	}
+	return strings.Join(terms, " ")
+}
+
+// syntheticSentence returns a sentence of random vocabulary terms
+func syntheticSentence(rng *rand.Rand) string {
+	return pick(rng, 8) + "。"
+}
+
+// syntheticContent returns a markdown article of the given number of paragraphs
+func syntheticContent(rng *rand.Rand, topic string, paragraphs int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", topic)
+	for i := 0; i < paragraphs; i++ {
+		if i > 0 && i%8 == 0 {
+			fmt.Fprintf(&b, "## %s\n\n", pick(rng, 2))
+		}
+		for j := 0; j < 5; j++ {
+			b.WriteString(syntheticSentence(rng))
+		}
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// randomEmbedding returns a random unit vector, so cosine distances are well spread
+func randomEmbedding(rng *rand.Rand, dimensions int) *pgvector.Vector {
+	values := make([]float32, dimensions)
+	var norm float64
+	for i := range values {
+		v := rng.NormFloat64()
+		values[i] = float32(v)
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	for i := range values {
+		values[i] = float32(float64(values[i]) / norm)
+	}
+	vector := pgvector.NewVector(values)
+	return &vector
+}
@@ -0,0 +1,84 @@
+// backend/cmd/reindex/main.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+)
+
+// Re-embeds all articles with the embedding model in the config, then resizes the vector columns.
+// Change llm.embedding first; restart the server and worker once the re-index has completed.
+func main() {
+	batchSize := flag.Int("batch", 32, "Articles per embedding request")
+	resume := flag.String("task", "", "ID of an earlier re-index task record to continue (default: start a new one)")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	time.Local = time.UTC
+
+	// Connect to database
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	adapter := llm.NewEmbeddingAdapterFromConfig(&cfg.LLM)
+	if !adapter.IsAvailable() {
+		log.Fatalf("Embedding model %s is not available", adapter.Name())
+	}
+	taskRepo := repository.NewTaskRepository(db)
+
+	var taskID uuid.UUID
+	if *resume != "" {
+		if taskID, err = uuid.Parse(*resume); err != nil {
+			log.Fatalf("Invalid -task: %v", err)
+		}
+	} else {
+		task, created, err := service.CreateEmbeddingReindexTask(taskRepo)
+		if err != nil {
+			log.Fatalf("Failed to create task record: %v", err)
+		}
+		if !created {
+			log.Fatalf("Re-index %s is already %s; cancel it or pass -task to take it over", task.ID, task.Status)
+		}
+		taskID = task.ID
+	}
+	log.Printf("Re-index task %s: embedding articles with %s (%d dimensions)", taskID, adapter.Name(), adapter.Dimensions())
+
+	// Interrupting leaves the new vectors in place; run again with -task to continue
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	progress, err := service.NewEmbeddingReindexer(db, adapter, taskRepo).Run(ctx, taskID, *batchSize)
+	if errors.Is(err, service.ErrReindexCancelled) {
+		log.Fatalf("Re-index cancelled after %d articles", progress.Embedded)
+	}
+	if err != nil {
+		if progress != nil {
+			log.Printf("Embedded %d articles before stopping; run with -task %s to continue", progress.Embedded, taskID)
+		}
+		log.Fatalf("Re-index failed: %v", err)
+	}
+
+	log.Printf("Re-indexed %d articles in %v (%d failed); vector columns are now %d-dimensional",
+		progress.Embedded, time.Since(start).Round(time.Second), progress.Failed, progress.Dimensions)
+	log.Println("Restart the server and worker to pick up the new embedding model")
+}
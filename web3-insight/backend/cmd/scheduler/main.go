@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/worker"
+)
+
+// The scheduler enqueues periodic tasks for the workers to run. Run exactly one: every
+// running scheduler enqueues each task again.
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Timestamps are stored and served in UTC (RFC3339); the display timezone only affects generated content
+	time.Local = time.UTC
+
+	// Connect to database for research schedules
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	log.Println("Database connected for scheduler")
+
+	redisOpt := asynq.RedisClientOpt{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	}
+
+	// Keep research schedules from the database registered with the cron scheduler
+	scheduleManager, err := worker.NewResearchScheduleManager(redisOpt, db, cfg.Server.DisplayLocation())
+	if err != nil {
+		log.Fatalf("Failed to create research schedule manager: %v", err)
+	}
+	if err := scheduleManager.Start(); err != nil {
+		log.Fatalf("Failed to start research schedule manager: %v", err)
+	}
+	defer scheduleManager.Shutdown()
+
+	scheduler := worker.NewScheduler(redisOpt)
+	if err := scheduler.RegisterTasks(); err != nil {
+		log.Fatalf("Failed to register periodic tasks: %v", err)
+	}
+	if err := scheduler.Run(); err != nil {
+		log.Fatalf("Scheduler failed: %v", err)
+	}
+}
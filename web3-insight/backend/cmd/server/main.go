@@ -3,10 +3,13 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/user/web3-insight/internal/api"
 	"github.com/user/web3-insight/internal/config"
 	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/service"
 )
 
 func main() {
@@ -15,6 +18,10 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Timestamps are stored and served in UTC (RFC3339); the display timezone only affects generated content
+	time.Local = time.UTC
+	service.SetDisplayLocation(cfg.Server.DisplayLocation())
+
 	// Connect to database
 	db, err := database.Connect(&cfg.Database)
 	if err != nil {
@@ -28,6 +35,11 @@ func main() {
 	}
 	log.Println("Migrations completed")
 
+	// Embeddings of the wrong size fail on every write; semantic search falls back to keyword search
+	if err := database.ValidateEmbeddingDimensions(db, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM).Dimensions()); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	// Seed initial data
 	if err := database.Seed(db); err != nil {
 		log.Fatalf("Failed to seed data: %v", err)
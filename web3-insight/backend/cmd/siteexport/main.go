@@ -0,0 +1,73 @@
+// backend/cmd/siteexport/main.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+)
+
+// Exports published articles as a Hugo site, into a directory or a zip archive
+func main() {
+	out := flag.String("out", "site", "Directory to write the site into, or a path ending in .zip")
+	category := flag.String("category", "", "Only export this category ID and its subcategories")
+	baseURL := flag.String("base-url", "", "URL the site is published at (default: server.public_url)")
+	title := flag.String("title", "Web3 Insight", "Site title")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if *baseURL == "" {
+		*baseURL = cfg.Server.PublicURL
+	}
+
+	var categoryID *uuid.UUID
+	if *category != "" {
+		parsed, err := uuid.Parse(*category)
+		if err != nil {
+			log.Fatalf("Invalid -category: %v", err)
+		}
+		categoryID = &parsed
+	}
+
+	// Connect to database
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	exporter := service.NewSiteExporter(repository.NewArticleRepository(db), repository.NewCategoryRepository(db), *baseURL, *title)
+	bundle, err := exporter.Build(categoryID)
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+
+	if strings.HasSuffix(*out, ".zip") {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		if err := bundle.WriteZip(f); err != nil {
+			f.Close()
+			log.Fatalf("Failed to write %s: %v", *out, err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("Failed to write %s: %v", *out, err)
+		}
+	} else if err := bundle.WriteDir(*out); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+
+	log.Printf("Exported %d articles in %d sections with %d tags to %s (%d images listed in assets.json)",
+		bundle.Manifest.Articles, bundle.Manifest.Sections, bundle.Manifest.Tags, *out, len(bundle.Manifest.Assets))
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/user/web3-insight/internal/config"
 	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/service"
 	"github.com/user/web3-insight/internal/worker"
 )
 
@@ -17,6 +20,10 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Timestamps are stored and served in UTC (RFC3339); the display timezone only affects generated content
+	time.Local = time.UTC
+	service.SetDisplayLocation(cfg.Server.DisplayLocation())
+
 	// Connect to database for worker dependencies
 	db, err := database.Connect(&cfg.Database)
 	if err != nil {
@@ -24,9 +31,10 @@ func main() {
 	}
 	log.Println("Database connected for worker")
 
-	// Initialize worker dependencies (RSS collector, web crawler, embedding service, etc.)
-	worker.InitWorkerDependencies(db, &cfg.LLM)
-	log.Println("Worker dependencies initialized")
+	// Embeddings of the wrong size fail on every write; semantic search falls back to keyword search
+	if err := database.ValidateEmbeddingDimensions(db, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM).Dimensions()); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 
 	redisOpt := asynq.RedisClientOpt{
 		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
@@ -46,6 +54,15 @@ func main() {
 		}),
 	})
 
+	// Client for handlers that enqueue follow-up tasks (e.g. backfill pages)
+	taskClient := asynq.NewClient(redisOpt)
+	defer taskClient.Close()
+	worker.InitTaskClient(taskClient)
+
+	// Initialize worker dependencies (RSS collector, web crawler, embedding service, etc.)
+	worker.InitWorkerDependencies(db, cfg)
+	log.Println("Worker dependencies initialized")
+
 	mux := worker.NewTaskMux()
 
 	log.Printf("Worker starting with concurrency %d", cfg.Worker.Concurrency)
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"github.com/user/web3-insight/internal/worker"
+	"gorm.io/gorm"
+)
+
+type AdminHandler struct {
+	pipelineRepo *repository.PipelineRepository
+	queues       *worker.QueueMonitor
+	reports      *repository.ConsistencyReportRepository
+	taskRepo     *repository.TaskRepository
+	taskClient   worker.TaskEnqueuer
+}
+
+func NewAdminHandler(pipelineRepo *repository.PipelineRepository, queues *worker.QueueMonitor, reports *repository.ConsistencyReportRepository, taskRepo *repository.TaskRepository, taskClient worker.TaskEnqueuer) *AdminHandler {
+	return &AdminHandler{pipelineRepo: pipelineRepo, queues: queues, reports: reports, taskRepo: taskRepo, taskClient: taskClient}
+}
+
+// GetPipeline godoc
+// @Summary Get content pipeline status
+// @Description Get per-stage counts and the age of the oldest unprocessed item (ingested → summarized → embedded → published)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} repository.PipelineStats
+// @Router /api/admin/pipeline [get]
+func (h *AdminHandler) GetPipeline(c *gin.Context) {
+	stats, err := h.pipelineRepo.GetStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetQueues godoc
+// @Summary Get task queue depths
+// @Description Get per-queue pending, in-flight and retry counts, processing rates and worker utilization,
+// @Description with a scale_up / scale_down / hold recommendation for worker replicas.
+// @Description Scale up when the oldest pending task waited longer than scaleUpLatencySeconds or pending tasks
+// @Description per worker slot exceed scaleUpBacklogPerSlot; scale down when nothing is pending and worker
+// @Description utilization is below scaleDownUtilization. Thresholds are set under worker.autoscale.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} worker.QueueReport
+// @Failure 503 {object} map[string]string "Local worker mode"
+// @Router /api/admin/queues [get]
+func (h *AdminHandler) GetQueues(c *gin.Context) {
+	if h.queues == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "queue monitoring needs worker.mode redis; tasks run in-process"})
+		return
+	}
+
+	report, err := h.queues.Snapshot()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListConsistencyReports godoc
+// @Summary List consistency reports
+// @Description Get the latest reports of the nightly consistency check, newest first
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of reports (default 20, max 100)"
+// @Success 200 {array} model.ConsistencyReport
+// @Router /api/admin/consistency [get]
+func (h *AdminHandler) ListConsistencyReports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	reports, err := h.reports.List(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetConsistencyReport godoc
+// @Summary Get a consistency report
+// @Description Get one report with per-check counts, sample IDs and fixes applied
+// @Tags admin
+// @Produce json
+// @Param id path string true "Report ID"
+// @Success 200 {object} model.ConsistencyReport
+// @Failure 404 {object} map[string]string
+// @Router /api/admin/consistency/{id} [get]
+func (h *AdminHandler) GetConsistencyReport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	report, err := h.reports.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// RunConsistencyCheck godoc
+// @Summary Run the consistency check
+// @Description Enqueue a consistency check now; the report appears under GET /api/admin/consistency
+// @Tags admin
+// @Produce json
+// @Param auto_fix query bool false "Apply safe fixes (default: worker.consistency.auto_fix)"
+// @Success 202 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "A check is already queued"
+// @Router /api/admin/consistency/run [post]
+func (h *AdminHandler) RunConsistencyCheck(c *gin.Context) {
+	var autoFix *bool
+	if raw := c.Query("auto_fix"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid auto_fix"})
+			return
+		}
+		autoFix = &parsed
+	}
+
+	info, err := worker.EnqueueConsistencyCheck(h.taskClient, autoFix)
+	if err != nil {
+		if errors.Is(err, asynq.ErrDuplicateTask) {
+			c.JSON(http.StatusConflict, gin.H{"error": "a consistency check is already queued"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "consistency check enqueued",
+		"taskId":  info.ID,
+	})
+}
+
+// ReindexEmbeddings godoc
+// @Summary Re-embed all articles
+// @Description Enqueue a re-index of all article embeddings with the worker's embedding model, for use after
+// @Description changing llm.embedding. Vectors are written to a shadow column and swapped in at the end, resizing
+// @Description the vector columns; semantic search keeps using the old vectors until then. Follow progress with
+// @Description GET /api/tasks/{taskId} and stop it with POST /api/tasks/{taskId}/cancel; a new run resumes.
+// @Tags admin
+// @Produce json
+// @Param batch_size query int false "Articles per embedding request (default 32)"
+// @Success 202 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{} "A re-index is already pending or running"
+// @Router /api/admin/embeddings/reindex [post]
+func (h *AdminHandler) ReindexEmbeddings(c *gin.Context) {
+	batchSize, _ := strconv.Atoi(c.Query("batch_size"))
+	if batchSize < 0 || batchSize > 512 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "batch_size must be between 1 and 512"})
+		return
+	}
+
+	task, created, err := service.CreateEmbeddingReindexTask(h.taskRepo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !created {
+		c.JSON(http.StatusConflict, gin.H{"error": "an embedding re-index is already " + task.Status, "taskId": task.ID})
+		return
+	}
+
+	if _, err := worker.EnqueueEmbeddingReindex(h.taskClient, task.ID.String(), batchSize); err != nil {
+		task.Status = model.TaskStatusFailed
+		task.Error = err.Error()
+		h.taskRepo.Update(task)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "embedding re-index enqueued",
+		"taskId":  task.ID,
+	})
+}
@@ -1,21 +1,35 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/markdown"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
 )
 
 type ArticleHandler struct {
-	repo *repository.ArticleRepository
+	repo  *repository.ArticleRepository
+	hooks *service.ArticleHooks
+	views *service.ViewCounter
+	seo   *service.SEOService
 }
 
-func NewArticleHandler(repo *repository.ArticleRepository) *ArticleHandler {
-	return &ArticleHandler{repo: repo}
+func NewArticleHandler(repo *repository.ArticleRepository, hooks *service.ArticleHooks, views *service.ViewCounter) *ArticleHandler {
+	return &ArticleHandler{repo: repo, hooks: hooks, views: views}
+}
+
+// SetSEOService enables generating meta descriptions and OpenGraph fields with the LLM
+func (h *ArticleHandler) SetSEOService(seo *service.SEOService) {
+	h.seo = seo
 }
 
 // ListArticles godoc
@@ -25,17 +39,41 @@ func NewArticleHandler(repo *repository.ArticleRepository) *ArticleHandler {
 // @Accept json
 // @Produce json
 // @Param category_id query string false "Filter by category ID"
-// @Param status query string false "Filter by status (draft, published)"
+// @Param status query string false "Filter by status (draft, in_review, changes_requested, approved, published, scheduled; comma-separated)"
+// @Param tag query string false "Filter by tags, matching all (comma-separated); aliases match their tag"
 // @Param search query string false "Search in title and summary"
+// @Param difficulty query string false "Filter by difficulty (beginner, intermediate, advanced; comma-separated)"
+// @Param sort query string false "Sort order: newest (default) or difficulty"
 // @Param page query int false "Page number (default: 1)"
 // @Param page_size query int false "Page size (default: 20)"
 // @Success 200 {object} repository.ArticleListResult
 // @Router /api/articles [get]
 func (h *ArticleHandler) List(c *gin.Context) {
 	params := repository.ArticleListParams{
-		Status: c.Query("status"),
-		Search: c.Query("search"),
+		Statuses: queryList(c, "status"),
+		Tags:     queryList(c, "tag"),
+		Search:   c.Query("search"),
+		Sort:     c.DefaultQuery("sort", repository.ArticleSortNewest),
+	}
+
+	for _, status := range params.Statuses {
+		if !model.IsValidArticleStatus(status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+	}
+
+	if params.Sort != repository.ArticleSortNewest && params.Sort != repository.ArticleSortDifficulty {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort"})
+		return
+	}
+
+	difficulties, ok := queryDifficulties(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty"})
+		return
 	}
+	params.Difficulties = difficulties
 
 	if categoryID := c.Query("category_id"); categoryID != "" {
 		id, err := uuid.Parse(categoryID)
@@ -65,17 +103,34 @@ func (h *ArticleHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// queryDifficulties reads the difficulty filter, reporting false if it names an unknown level
+func queryDifficulties(c *gin.Context) ([]string, bool) {
+	difficulties := queryList(c, "difficulty")
+	for _, d := range difficulties {
+		if !model.IsValidDifficulty(d) {
+			return nil, false
+		}
+	}
+	return difficulties, true
+}
+
 // GetArticle godoc
 // @Summary Get article by ID or slug
-// @Description Get a single article by its ID or slug
+// @Description Get a single article by its ID or slug, with its table of contents, its prerequisites, its place in each series it belongs to (previous and next articles) and its deep-dive links. With render=html the content is returned as sanitized HTML in contentHtml instead of markdown. An old slug of a renamed article returns the article with redirectedFrom set, a Content-Location header with its current URL and X-Redirect-Status 301.
 // @Tags articles
 // @Accept json
 // @Produce json
 // @Param id path string true "Article ID or slug"
+// @Param render query string false "html: return the content rendered to sanitized HTML instead of markdown"
 // @Success 200 {object} model.Article
 // @Router /api/articles/{id} [get]
 func (h *ArticleHandler) Get(c *gin.Context) {
 	idParam := c.Param("id")
+	render := c.Query("render")
+	if render != "" && render != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid render"})
+		return
+	}
 
 	var article *model.Article
 	var err error
@@ -86,6 +141,15 @@ func (h *ArticleHandler) Get(c *gin.Context) {
 	} else {
 		// Treat as slug
 		article, err = h.repo.GetBySlug(idParam)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// An old slug of a renamed article is answered with the article under its current
+			// slug, flagged as moved permanently so clients can update their links
+			if article, err = h.repo.GetBySlugRedirect(idParam); err == nil {
+				article.RedirectedFrom = idParam
+				c.Header("Content-Location", "/api/articles/"+article.Slug)
+				c.Header("X-Redirect-Status", strconv.Itoa(http.StatusMovedPermanently))
+			}
+		}
 	}
 
 	if err != nil {
@@ -93,27 +157,56 @@ func (h *ArticleHandler) Get(c *gin.Context) {
 		return
 	}
 
-	// Increment view count asynchronously
-	go func() {
-		_ = h.repo.IncrementViewCount(article.ID)
-	}()
+	if err := h.repo.LoadPrerequisites(article); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.LoadRelations(article); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.LoadTOC(article); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if render == "html" {
+		// Rendered fresh, since articles saved before rendering on save may hold HTML as imported
+		if article.ContentHTML, err = markdown.RenderHTML(article.Content); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		article.Content = ""
+	}
+
+	// Buffer the view in Redis; the worker flushes counts to the database
+	if h.views != nil {
+		h.views.Increment(article.ID)
+	} else {
+		go func() {
+			_ = h.repo.IncrementViewCount(article.ID)
+		}()
+	}
 
 	c.JSON(http.StatusOK, article)
 }
 
 type CreateArticleRequest struct {
-	Title      string     `json:"title" binding:"required"`
-	Slug       string     `json:"slug" binding:"required"`
-	Content    string     `json:"content" binding:"required"`
-	Summary    string     `json:"summary"`
-	CategoryID *uuid.UUID `json:"categoryId"`
-	Tags       []string   `json:"tags"`
-	Status     string     `json:"status"`
+	Title       string     `json:"title" binding:"required"`
+	Slug        string     `json:"slug" binding:"required"`
+	Content     string     `json:"content" binding:"required"`
+	Summary     string     `json:"summary"`
+	CategoryID  *uuid.UUID `json:"categoryId"`
+	Tags        []string   `json:"tags"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publishAt"` // Schedules the article for publishing; implies status scheduled
+	License     string     `json:"license"`
+	LicenseURL  string     `json:"licenseUrl"`
+	Attribution string     `json:"attribution"`
 }
 
 // CreateArticle godoc
 // @Summary Create a new article
-// @Description Create a new article
+// @Description Create a new article. Give publishAt to schedule it; the worker publishes it when the time arrives.
 // @Tags articles
 // @Accept json
 // @Produce json
@@ -128,40 +221,65 @@ func (h *ArticleHandler) Create(c *gin.Context) {
 	}
 
 	article := &model.Article{
-		Title:      req.Title,
-		Slug:       req.Slug,
-		Content:    req.Content,
-		Summary:    req.Summary,
-		CategoryID: req.CategoryID,
-		Tags:       req.Tags,
-		Status:     req.Status,
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Content:     req.Content,
+		Summary:     req.Summary,
+		CategoryID:  req.CategoryID,
+		Tags:        req.Tags,
+		Status:      req.Status,
+		License:     req.License,
+		LicenseURL:  req.LicenseURL,
+		Attribution: req.Attribution,
+		EditedBy:    model.EditedByManual,
 	}
 
 	if article.Status == "" {
 		article.Status = "draft"
 	}
+	// New articles can be submitted for review, but not reviewed yet
+	if model.IsReviewStatus(article.Status) && article.Status != model.ArticleStatusInReview {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "new articles can only be submitted for review"})
+		return
+	}
+	if !applySchedule(c, article, req.Status, req.PublishAt) {
+		return
+	}
 
 	if err := h.repo.Create(article); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.hooks.AfterCreate(article)
 
 	c.JSON(http.StatusCreated, article)
 }
 
 type UpdateArticleRequest struct {
-	Title      string     `json:"title"`
-	Slug       string     `json:"slug"`
-	Content    string     `json:"content"`
-	Summary    string     `json:"summary"`
-	CategoryID *uuid.UUID `json:"categoryId"`
-	Tags       []string   `json:"tags"`
-	Status     string     `json:"status"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Content     string     `json:"content"`
+	Summary     string     `json:"summary"`
+	CategoryID  *uuid.UUID `json:"categoryId"`
+	Tags        []string   `json:"tags"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publishAt"` // Schedules the article for publishing; implies status scheduled
+	License     string     `json:"license"`
+	LicenseURL  string     `json:"licenseUrl"`
+	Attribution string     `json:"attribution"`
+
+	// Meta and OpenGraph overrides; setting any keeps them through later content edits.
+	// SEOSource "auto" rederives them from the content instead, unless overrides are also given.
+	MetaDescription *string `json:"metaDescription"`
+	OGTitle         *string `json:"ogTitle"`
+	OGDescription   *string `json:"ogDescription"`
+	OGImage         *string `json:"ogImage"`
+	SEOSource       string  `json:"seoSource"`
 }
 
 // UpdateArticle godoc
 // @Summary Update an article
-// @Description Update an existing article
+// @Description Update an existing article. Give publishAt to schedule it; publishing a scheduled article early starts its classification and embedding. Review statuses are set with the review endpoint, and status changes follow the review workflow: a draft or an article under review cannot be published until approved.
 // @Tags articles
 // @Accept json
 // @Produce json
@@ -187,6 +305,13 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	wasScheduled := article.Status == model.ArticleStatusScheduled
+	previousStatus := article.Status
+
+	if model.IsReviewStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "review statuses are set with /api/articles/{id}/review"})
+		return
+	}
 
 	if req.Title != "" {
 		article.Title = req.Title
@@ -194,8 +319,9 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 	if req.Slug != "" {
 		article.Slug = req.Slug
 	}
-	if req.Content != "" {
+	if req.Content != "" && req.Content != article.Content {
 		article.Content = req.Content
+		article.EditedBy = model.EditedByManual
 	}
 	if req.Summary != "" {
 		article.Summary = req.Summary
@@ -209,11 +335,117 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 	if req.Status != "" {
 		article.Status = req.Status
 	}
+	if !applySchedule(c, article, req.Status, req.PublishAt) {
+		return
+	}
+	// Status changes follow the review workflow, so drafts and articles under review are
+	// only published once approved
+	if article.Status != previousStatus && !model.CanTransitionArticle(previousStatus, article.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("invalid status transition: %s to %s", previousStatus, article.Status)})
+		return
+	}
+	if req.License != "" {
+		article.License = req.License
+		article.LicenseURL = req.LicenseURL
+	}
+	if req.Attribution != "" {
+		article.Attribution = req.Attribution
+	}
+	if !applySEO(c, article, req) {
+		return
+	}
 
 	if err := h.repo.Update(article); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// Scheduled articles skipped the post-create pipeline; publishing by hand starts it
+	if wasScheduled && article.Status == model.ArticleStatusPublished {
+		h.hooks.AfterPublish(article)
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// applySchedule sets the publish time of an article from a request. A publish time without
+// a status schedules the article; a scheduled article needs a publish time. Responds with an
+// error and returns false if the request is invalid.
+func applySchedule(c *gin.Context, article *model.Article, status string, publishAt *time.Time) bool {
+	if publishAt != nil {
+		article.PublishAt = publishAt
+		if status == "" {
+			article.Status = model.ArticleStatusScheduled
+		}
+	}
+	if article.Status == model.ArticleStatusScheduled && article.PublishAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publishAt is required for scheduled articles"})
+		return false
+	}
+	return true
+}
+
+// applySEO copies meta and OpenGraph overrides from an update request onto an article,
+// responding with an error and returning false if the request is invalid. Overrides given
+// in the same request as a reset to auto are kept: resetting would rederive them on save.
+func applySEO(c *gin.Context, article *model.Article, req UpdateArticleRequest) bool {
+	overrides := map[*string]*string{
+		&article.MetaDescription: req.MetaDescription,
+		&article.OGTitle:         req.OGTitle,
+		&article.OGDescription:   req.OGDescription,
+		&article.OGImage:         req.OGImage,
+	}
+	overridden := false
+	for field, value := range overrides {
+		if value != nil {
+			*field = *value
+			overridden = true
+		}
+	}
+
+	switch req.SEOSource {
+	case "":
+	case model.SEOSourceAuto:
+		if !overridden {
+			article.SEOSource = model.SEOSourceAuto
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "seoSource can only be reset to auto"})
+		return false
+	}
+	if overridden {
+		article.SEOSource = model.SEOSourceManual
+	}
+	return true
+}
+
+// GenerateSEO godoc
+// @Summary Generate article SEO metadata
+// @Description Write the meta description and OpenGraph title and description with the summarization model. Later content edits keep them until seoSource is reset to auto.
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {object} model.Article
+// @Router /api/articles/{id}/seo/generate [post]
+func (h *ArticleHandler) GenerateSEO(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if h.seo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SEO generation is not configured"})
+		return
+	}
+
+	if !articlesExist(c, h.repo, id) {
+		return
+	}
+
+	article, err := h.seo.GenerateAndUpdate(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, article)
 }
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ResolveRevisionRequest struct {
+	Reviewer string `json:"reviewer"`
+	Note     string `json:"note"`
+}
+
+// ListRevisions godoc
+// @Summary List proposed article revisions
+// @Description Get the changes the news pipeline proposed for an article, newest first. A revision is appended to the article only once a reviewer applies it.
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param status query string false "Filter by status (pending, applied, rejected)"
+// @Success 200 {array} model.ArticleRevision
+// @Router /api/articles/{id}/revisions [get]
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	revisions, err := h.repo.ListRevisions(id, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// ApplyRevision godoc
+// @Summary Apply a proposed article revision
+// @Description Append a pending revision to its article. The replaced content is kept as a version, so an applied revision can be undone by restoring it.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param revisionId path string true "Revision ID"
+// @Param request body ResolveRevisionRequest false "Reviewer and note"
+// @Success 200 {object} model.Article
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/articles/{id}/revisions/{revisionId}/apply [post]
+func (h *ArticleHandler) ApplyRevision(c *gin.Context) {
+	h.resolveRevision(c, model.RevisionStatusApplied)
+}
+
+// RejectRevision godoc
+// @Summary Reject a proposed article revision
+// @Description Discard a pending revision, leaving its article as it is
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param revisionId path string true "Revision ID"
+// @Param request body ResolveRevisionRequest false "Reviewer and note"
+// @Success 200 {object} model.ArticleRevision
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/articles/{id}/revisions/{revisionId}/reject [post]
+func (h *ArticleHandler) RejectRevision(c *gin.Context) {
+	h.resolveRevision(c, model.RevisionStatusRejected)
+}
+
+// resolveRevision moves the revision named by the request path to status, responding with
+// the updated article when it was applied and with the revision when it was rejected
+func (h *ArticleHandler) resolveRevision(c *gin.Context, status string) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	revisionID, err := uuid.Parse(c.Param("revisionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision id"})
+		return
+	}
+
+	var req ResolveRevisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	revision, err := h.repo.GetRevision(id, revisionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	revision.Status = status
+	revision.Reviewer = strings.TrimSpace(req.Reviewer)
+	revision.Note = strings.TrimSpace(req.Note)
+	article, err := h.repo.ResolveRevision(revision)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRevisionResolved):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if article != nil {
+		c.JSON(http.StatusOK, article)
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
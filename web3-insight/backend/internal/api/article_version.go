@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+// ListVersions godoc
+// @Summary List article versions
+// @Description Get the earlier versions of an article, newest first, with who or what produced each (ai, manual, import, restore). Content is left out; fetch a version to read it.
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticleVersion
+// @Router /api/articles/{id}/versions [get]
+func (h *ArticleHandler) ListVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	versions, err := h.repo.ListVersions(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetVersion godoc
+// @Summary Get an article version
+// @Description Get an earlier version of an article with its content
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} model.ArticleVersion
+// @Failure 404 {object} map[string]string
+// @Router /api/articles/{id}/versions/{version} [get]
+func (h *ArticleHandler) GetVersion(c *gin.Context) {
+	version, ok := h.findVersion(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// RestoreVersion godoc
+// @Summary Restore an article version
+// @Description Replace the content of an article with an earlier version. The replaced content is kept as a new version, so a restore can be undone.
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} model.Article
+// @Failure 404 {object} map[string]string
+// @Router /api/articles/{id}/versions/{version}/restore [post]
+func (h *ArticleHandler) RestoreVersion(c *gin.Context) {
+	version, ok := h.findVersion(c)
+	if !ok {
+		return
+	}
+
+	article, err := h.repo.GetByID(version.ArticleID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		return
+	}
+	if article.Content == version.Content {
+		c.JSON(http.StatusOK, article)
+		return
+	}
+
+	article.Content = version.Content
+	article.EditedBy = model.EditedByRestore
+	if err := h.repo.Update(article); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// findVersion loads the version named by the request path, responding with an error if there is none
+func (h *ArticleHandler) findVersion(c *gin.Context) (*model.ArticleVersion, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return nil, false
+	}
+
+	version, err := h.repo.GetVersion(id, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return nil, false
+	}
+	return version, true
+}
@@ -1,25 +1,41 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
 )
 
 type CategoryHandler struct {
-	repo *repository.CategoryRepository
+	repo     *repository.CategoryRepository
+	enricher *service.CategoryEnricher
+	deduper  *service.CategoryDeduper
 }
 
 func NewCategoryHandler(repo *repository.CategoryRepository) *CategoryHandler {
 	return &CategoryHandler{repo: repo}
 }
 
+// SetEnricher enables generating category descriptions and icons with the LLM
+func (h *CategoryHandler) SetEnricher(enricher *service.CategoryEnricher) {
+	h.enricher = enricher
+}
+
+// SetDeduper enables detecting duplicate categories by embedding similarity
+func (h *CategoryHandler) SetDeduper(deduper *service.CategoryDeduper) {
+	h.deduper = deduper
+}
+
 // ListCategories godoc
 // @Summary List all categories
-// @Description Get flat list of all categories
+// @Description Get flat list of all categories, archived ones included
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -37,14 +53,16 @@ func (h *CategoryHandler) List(c *gin.Context) {
 
 // GetCategoryTree godoc
 // @Summary Get category tree
-// @Description Get hierarchical category tree structure
+// @Description Get hierarchical category tree structure. Archived categories and everything below them are left out unless include_archived is set.
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Param include_archived query bool false "Include archived categories"
 // @Success 200 {array} model.Category
 // @Router /api/categories/tree [get]
 func (h *CategoryHandler) GetTree(c *gin.Context) {
-	tree, err := h.repo.GetTree()
+	includeArchived, _ := strconv.ParseBool(c.Query("include_archived"))
+	tree, err := h.repo.GetTree(includeArchived)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -55,7 +73,7 @@ func (h *CategoryHandler) GetTree(c *gin.Context) {
 
 // GetCategory godoc
 // @Summary Get category by ID or slug
-// @Description Get a single category by its ID or slug
+// @Description Get a single category by its ID or slug. The ID or slug of a merged category returns the category it was merged into, with redirectedFrom set, a Content-Location header with its current URL and X-Redirect-Status 301.
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -76,6 +94,16 @@ func (h *CategoryHandler) Get(c *gin.Context) {
 		category, err = h.repo.GetBySlug(idParam)
 	}
 
+	// A merged category is answered with the category it was merged into, flagged as moved
+	// permanently so clients can update their links
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if category, err = h.repo.GetByMergedRef(idParam); err == nil {
+			category.RedirectedFrom = idParam
+			c.Header("Content-Location", "/api/categories/"+category.Slug)
+			c.Header("X-Redirect-Status", strconv.Itoa(http.StatusMovedPermanently))
+		}
+	}
+
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
 		return
@@ -136,6 +164,7 @@ type UpdateCategoryRequest struct {
 	Description string     `json:"description"`
 	Icon        string     `json:"icon"`
 	SortOrder   *int       `json:"sortOrder"`
+	Archived    *bool      `json:"archived"` // Archiving hides the category from the tree and the classifier; its articles keep it
 }
 
 // UpdateCategory godoc
@@ -188,9 +217,16 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 	if req.SortOrder != nil {
 		category.SortOrder = *req.SortOrder
 	}
+	if req.Archived != nil {
+		category.Archived = *req.Archived
+	}
 
 	if err := h.repo.Update(category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, repository.ErrCategoryCycle) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -220,3 +256,339 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+type MoveCategoryRequest struct {
+	ParentID  *uuid.UUID `json:"parentId"`  // New parent; null makes the category a root category
+	SortOrder *int       `json:"sortOrder"` // Position among the new siblings; last when omitted
+}
+
+// MoveCategory godoc
+// @Summary Move a category
+// @Description Give a category a new parent, with its subtree. Moving a category below itself is rejected.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param request body MoveCategoryRequest true "New parent"
+// @Success 200 {object} model.Category
+// @Router /api/categories/{id}/move [post]
+func (h *CategoryHandler) Move(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ParentID != nil {
+		if _, err := h.repo.GetByID(*req.ParentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent category not found"})
+			return
+		}
+	}
+
+	category, err := h.repo.Move(id, req.ParentID, req.SortOrder)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCategoryCycle):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// EnrichCategory godoc
+// @Summary Describe a category with the LLM
+// @Description Summarize the category's articles into a description and pick an icon. Only an empty description or icon is filled in, unless force is set.
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param force query bool false "Replace an existing description and icon"
+// @Success 200 {object} model.Category
+// @Router /api/categories/{id}/enrich [post]
+func (h *CategoryHandler) Enrich(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if h.enricher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "category enrichment is not configured"})
+		return
+	}
+
+	force, _ := strconv.ParseBool(c.Query("force"))
+	if _, err := h.repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	category, err := h.enricher.Enrich(c.Request.Context(), id, force)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+type ReorderCategoriesRequest struct {
+	Categories []repository.CategoryOrder `json:"categories" binding:"required,min=1,dive"`
+}
+
+// ReorderCategories godoc
+// @Summary Reorder categories
+// @Description Set the sort order of several categories at once. Nothing changes if any category does not exist.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body ReorderCategoriesRequest true "Sort orders"
+// @Success 200 {array} model.Category
+// @Router /api/categories/reorder [put]
+func (h *CategoryHandler) Reorder(c *gin.Context) {
+	var req ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Reorder(req.Categories); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	tree, err := h.repo.GetTree(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+type MergeCategoryRequest struct {
+	TargetID uuid.UUID `json:"targetId" binding:"required"`
+}
+
+// MergeCategory godoc
+// @Summary Merge a category into another
+// @Description Move the articles and child categories of a category into the target, update the target's article count and delete the merged category. Its ID and slug keep resolving to the target.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID to merge"
+// @Param request body MergeCategoryRequest true "Target category"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/categories/{id}/merge [post]
+func (h *CategoryHandler) Merge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	merge, err := h.repo.Merge(id, req.TargetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidMerge):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	target, err := h.repo.GetByID(merge.TargetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merge":  merge,
+		"target": target,
+	})
+}
+
+// ListCategoryDuplicates godoc
+// @Summary List likely duplicate categories
+// @Description Get pairs of categories whose names and descriptions are so similar they are likely synonyms, most similar first. Pairs are found by the nightly detection job or POST /api/categories/duplicates/detect.
+// @Tags categories
+// @Produce json
+// @Param status query string false "open (default) or dismissed"
+// @Success 200 {array} model.CategoryDuplicate
+// @Router /api/categories/duplicates [get]
+func (h *CategoryHandler) ListDuplicates(c *gin.Context) {
+	status := c.DefaultQuery("status", model.DuplicateStatusOpen)
+	if status != model.DuplicateStatusOpen && status != model.DuplicateStatusDismissed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	duplicates, err := h.repo.ListDuplicates(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, duplicates)
+}
+
+// DetectCategoryDuplicates godoc
+// @Summary Detect duplicate categories
+// @Description Embed every category that is not archived and replace the open duplicate pairs with the pairs at least threshold similar. Dismissed pairs are not reopened.
+// @Tags categories
+// @Produce json
+// @Param threshold query number false "Minimum cosine similarity (default: 0.88)"
+// @Success 200 {array} model.CategoryDuplicate
+// @Router /api/categories/duplicates/detect [post]
+func (h *CategoryHandler) DetectDuplicates(c *gin.Context) {
+	if h.deduper == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "duplicate detection is not configured"})
+		return
+	}
+
+	threshold := service.DefaultDuplicateSimilarity
+	if raw := c.Query("threshold"); raw != "" {
+		t, err := strconv.ParseFloat(raw, 64)
+		if err != nil || t <= 0 || t > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threshold"})
+			return
+		}
+		threshold = t
+	}
+
+	duplicates, err := h.deduper.Detect(c.Request.Context(), threshold)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, duplicates)
+}
+
+type MergeDuplicateRequest struct {
+	TargetID *uuid.UUID `json:"targetId"` // Category of the pair to keep; the one with more articles when omitted
+}
+
+// MergeCategoryDuplicate godoc
+// @Summary Merge a duplicate category pair
+// @Description Merge one category of a duplicate pair into the other, keeping the one with more articles unless targetId picks one. Works like POST /api/categories/{id}/merge.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Duplicate pair ID"
+// @Param request body MergeDuplicateRequest false "Category to keep"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/categories/duplicates/{id}/merge [post]
+func (h *CategoryHandler) MergeDuplicate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req MergeDuplicateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	duplicate, err := h.repo.GetDuplicate(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "duplicate pair not found"})
+		return
+	}
+
+	sourceID, targetID := service.MergeTarget(duplicate)
+	if req.TargetID != nil {
+		switch *req.TargetID {
+		case duplicate.CategoryID:
+			sourceID, targetID = duplicate.DuplicateID, duplicate.CategoryID
+		case duplicate.DuplicateID:
+			sourceID, targetID = duplicate.CategoryID, duplicate.DuplicateID
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "targetId is not in the pair"})
+			return
+		}
+	}
+
+	merge, err := h.repo.Merge(sourceID, targetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidMerge):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	target, err := h.repo.GetByID(merge.TargetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merge":  merge,
+		"target": target,
+	})
+}
+
+// DismissCategoryDuplicate godoc
+// @Summary Dismiss a duplicate category pair
+// @Description Mark the categories of a pair as distinct, so detection no longer reports them
+// @Tags categories
+// @Param id path string true "Duplicate pair ID"
+// @Success 204
+// @Router /api/categories/duplicates/{id}/dismiss [post]
+func (h *CategoryHandler) DismissDuplicate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.DismissDuplicate(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "duplicate pair not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
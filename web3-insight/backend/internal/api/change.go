@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+type ChangeHandler struct {
+	repo *repository.ChangeRepository
+}
+
+func NewChangeHandler(repo *repository.ChangeRepository) *ChangeHandler {
+	return &ChangeHandler{repo: repo}
+}
+
+// ListChanges godoc
+// @Summary List recent content changes
+// @Description Get articles and categories created, updated or deleted since a point in time, for incremental sync.
+// @Description Each entity appears once with its latest change. Page with the returned cursor until hasMore is false.
+// @Tags changes
+// @Produce json
+// @Param since query string false "Only changes after this time (RFC 3339)"
+// @Param cursor query int false "Only changes after this cursor from a previous response"
+// @Param type query string false "Filter by entity type (article, category)"
+// @Param limit query int false "Maximum changes (default: 500, max: 5000)"
+// @Success 200 {object} repository.ChangeListResult
+// @Router /api/changes [get]
+func (h *ChangeHandler) List(c *gin.Context) {
+	params := repository.ChangeListParams{
+		EntityType: c.Query("type"),
+	}
+
+	if params.EntityType != "" && params.EntityType != model.ChangeEntityArticle && params.EntityType != model.ChangeEntityCategory {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type"})
+		return
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since, expected RFC 3339"})
+			return
+		}
+		params.Since = &t
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		params.After = after
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if parsed, err := strconv.Atoi(limit); err == nil {
+			params.Limit = parsed
+		}
+	}
+
+	result, err := h.repo.List(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Default chat limits, used for limits left at zero in the config
+const (
+	defaultChatMessagesPerMinute   = 10
+	defaultChatIPMessagesPerMinute = 30
+	defaultChatMaxMessageLength    = 4000
+	defaultChatMaxConcurrent       = 3
+)
+
+// Error codes of chat error frames, so clients can tell limits from generation failures
+const (
+	ChatErrorRateLimited        = "rate_limited"
+	ChatErrorMessageTooLong     = "message_too_long"
+	ChatErrorTooManyGenerations = "too_many_generations"
+	ChatErrorInvalidRequest     = "invalid_request"
+	ChatErrorGenerationFailed   = "generation_failed"
+	ChatErrorReplyInProgress    = "reply_in_progress"
+)
+
+// chatLimitError is a rejected chat message, sent to the client as an error frame
+type chatLimitError struct {
+	code    string
+	message string
+}
+
+func (e *chatLimitError) Error() string { return e.message }
+
+// messageWindow counts the messages sent in the last minute
+type messageWindow struct {
+	sent []time.Time
+}
+
+// prune drops messages older than a minute and returns how many remain
+func (w *messageWindow) prune(now time.Time) int {
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(w.sent) && !w.sent[i].After(cutoff) {
+		i++
+	}
+	w.sent = w.sent[i:]
+	return len(w.sent)
+}
+
+// chatClient is the usage of one client IP
+type chatClient struct {
+	window messageWindow
+	active int // Replies generating
+}
+
+// chatLimiter enforces chat limits per connection and per client IP in this process
+type chatLimiter struct {
+	messagesPerMinute   int
+	ipMessagesPerMinute int
+	maxMessageLength    int
+	maxConcurrent       int
+
+	mu        sync.Mutex
+	clients   map[string]*chatClient
+	lastSweep time.Time
+}
+
+// newChatLimiter creates a chat limiter, filling in defaults for unset limits
+func newChatLimiter(cfg config.ChatLimitsConfig) *chatLimiter {
+	orDefault := func(value, fallback int) int {
+		if value <= 0 {
+			return fallback
+		}
+		return value
+	}
+	return &chatLimiter{
+		messagesPerMinute:   orDefault(cfg.MessagesPerMinute, defaultChatMessagesPerMinute),
+		ipMessagesPerMinute: orDefault(cfg.IPMessagesPerMinute, defaultChatIPMessagesPerMinute),
+		maxMessageLength:    orDefault(cfg.MaxMessageLength, defaultChatMaxMessageLength),
+		maxConcurrent:       orDefault(cfg.MaxConcurrent, defaultChatMaxConcurrent),
+		clients:             make(map[string]*chatClient),
+	}
+}
+
+// readLimit is the largest WebSocket frame worth reading: a maximum-length message in
+// 4-byte runes plus room for the other request fields
+func (l *chatLimiter) readLimit() int64 {
+	return int64(l.maxMessageLength)*4 + 4096
+}
+
+// acquire admits a chat message from an IP, and from a WebSocket connection if conn is not
+// nil. The returned release must be called once the reply has finished generating.
+func (l *chatLimiter) acquire(ip string, conn *messageWindow, req ChatRequest) (func(), error) {
+	if length := utf8.RuneCountInString(req.Message) + utf8.RuneCountInString(req.SelectedText); length > l.maxMessageLength {
+		return nil, &chatLimitError{ChatErrorMessageTooLong, fmt.Sprintf("Message is too long (%d characters, max %d)", length, l.maxMessageLength)}
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	if conn != nil && conn.prune(now) >= l.messagesPerMinute {
+		return nil, &chatLimitError{ChatErrorRateLimited, fmt.Sprintf("Too many messages; at most %d per minute per connection", l.messagesPerMinute)}
+	}
+	client, ok := l.clients[ip]
+	if !ok {
+		client = &chatClient{}
+		l.clients[ip] = client
+	}
+	if client.window.prune(now) >= l.ipMessagesPerMinute {
+		return nil, &chatLimitError{ChatErrorRateLimited, fmt.Sprintf("Too many messages; at most %d per minute", l.ipMessagesPerMinute)}
+	}
+	if client.active >= l.maxConcurrent {
+		return nil, &chatLimitError{ChatErrorTooManyGenerations, fmt.Sprintf("Too many replies in progress; at most %d at once", l.maxConcurrent)}
+	}
+
+	if conn != nil {
+		conn.sent = append(conn.sent, now)
+	}
+	client.window.sent = append(client.window.sent, now)
+	client.active++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			client.active--
+		})
+	}, nil
+}
+
+// sweep forgets idle clients once a minute so the map does not grow with every IP seen
+func (l *chatLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for ip, client := range l.clients {
+		if client.active == 0 && client.window.prune(now) == 0 {
+			delete(l.clients, ip)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stream godoc
+// @Summary Stream a chat reply over Server-Sent Events
+// @Description Fallback for clients behind proxies without WebSocket support. Takes the same request as /ws/chat and sends each ChatResponse as an SSE event named after its type: chunk events, an error event if generation fails, then done.
+// @Tags chat
+// @Accept json
+// @Produce text/event-stream
+// @Param request body ChatRequest true "Chat request"
+// @Success 200 {object} ChatResponse
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /api/chat/stream [post]
+func (h *ChatHandler) Stream(c *gin.Context) {
+	var req ChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	if req.Message == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message cannot be empty"})
+		return
+	}
+
+	// Per-connection limits do not apply: every SSE request is a new connection
+	release, err := h.limiter.acquire(c.ClientIP(), nil, req)
+	if err != nil {
+		frame := limitErrorResponse(err)
+		status := http.StatusTooManyRequests
+		if frame.Code == ChatErrorMessageTooLong {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": frame.Content, "code": frame.Code})
+		return
+	}
+
+	stream, model, sources, err := h.startChat(c.Request.Context(), req)
+	if err != nil {
+		release()
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+
+	send := func(resp ChatResponse) {
+		c.SSEvent(resp.Type, resp)
+		c.Writer.Flush()
+	}
+
+	disconnected := c.Request.Context().Done()
+	for {
+		select {
+		case <-disconnected:
+			// The request context cancels the reply; the provider still blocks until its last chunks are read
+			go func() {
+				for range stream {
+				}
+				release()
+			}()
+			return
+		case chunk, ok := <-stream:
+			if !ok || chunk.Done {
+				release()
+				send(ChatResponse{Type: "done", Model: model, Sources: sources})
+				return
+			}
+			if chunk.Error != nil {
+				release()
+				send(ChatResponse{Type: "error", Code: ChatErrorGenerationFailed, Content: chunk.Error.Error()})
+				send(ChatResponse{Type: "done", Model: model, Sources: sources})
+				return
+			}
+			if chunk.Content != "" {
+				send(ChatResponse{Type: "chunk", Content: chunk.Content})
+			}
+		}
+	}
+}
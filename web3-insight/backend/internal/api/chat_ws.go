@@ -1,16 +1,28 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/llm"
 	"github.com/user/web3-insight/internal/service"
 )
 
+// Chat modes
+const (
+	ChatModeArticle = "article" // About the article in articleId, or general chat without one (default)
+	ChatModeCorpus  = "corpus"  // Answered from the whole knowledge base; articleId is ignored
+)
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -19,34 +31,63 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// Chat request types
+const (
+	ChatRequestMessage = "message" // A message to reply to (default)
+	ChatRequestCancel  = "cancel"  // Stops the reply in progress; the other fields are ignored
+)
+
 // ChatRequest represents a chat message from the client
 type ChatRequest struct {
+	Type         string `json:"type,omitempty"` // message (default) or cancel; WebSocket only
 	ArticleID    string `json:"articleId"`
 	Message      string `json:"message"`
 	SelectedText string `json:"selectedText,omitempty"`
 	SessionID    string `json:"sessionId"`
+	Mode         string `json:"mode,omitempty"`  // article (default) or corpus
+	Model        string `json:"model,omitempty"` // Model from GET /api/chat/models; routed like any chat when empty
 }
 
 // ChatResponse represents a response chunk sent to the client
 type ChatResponse struct {
-	Type    string `json:"type"` // "chunk", "done", "error"
-	Content string `json:"content,omitempty"`
-	Model   string `json:"model,omitempty"`
+	Type    string               `json:"type"`           // "chunk", "done", "error", "suggestions"
+	Code    string               `json:"code,omitempty"` // On "error": one of the ChatError codes
+	Content string               `json:"content,omitempty"`
+	Model   string               `json:"model,omitempty"`
+	Sources []service.ChatSource `json:"sources,omitempty"` // On "done": the excerpts the reply cites as [n]
+	// On "done": the reply was stopped by a cancel request before it finished
+	Cancelled bool `json:"cancelled,omitempty"`
+	// On "suggestions", sent after "done": follow-up questions the user might ask next
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 // ChatHandler handles WebSocket chat connections
 type ChatHandler struct {
 	chatService *service.ChatService
+	limiter     *chatLimiter
 }
 
-// NewChatHandler creates a new chat handler
-func NewChatHandler(chatService *service.ChatService) *ChatHandler {
+// NewChatHandler creates a new chat handler with abuse limits
+func NewChatHandler(chatService *service.ChatService, limits config.ChatLimitsConfig) *ChatHandler {
 	return &ChatHandler{
 		chatService: chatService,
+		limiter:     newChatLimiter(limits),
 	}
 }
 
-// HandleWebSocket handles WebSocket connections for chat
+// ListModels godoc
+// @Summary List chat models
+// @Description List the available models a chat request can select with its model field, with their type and cost class (free, low, medium, high)
+// @Tags chat
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/chat/models [get]
+func (h *ChatHandler) ListModels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"models": h.chatService.ListModels()})
+}
+
+// HandleWebSocket handles WebSocket connections for chat. A "cancel" request stops the reply
+// in progress; closing the connection stops it too.
 func (h *ChatHandler) HandleWebSocket(c *gin.Context) {
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -54,6 +95,10 @@ func (h *ChatHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 	defer conn.Close()
+	// Oversized frames close the connection instead of being buffered
+	conn.SetReadLimit(h.limiter.readLimit())
+	ip := c.ClientIP()
+	var window messageWindow
 
 	// Use mutex to prevent concurrent writes
 	var writeMu sync.Mutex
@@ -64,50 +109,175 @@ func (h *ChatHandler) HandleWebSocket(c *gin.Context) {
 		return conn.WriteJSON(resp)
 	}
 
-	for {
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket error: %v", err)
+	// Frames are read in the background so a cancel arrives while a reply is streaming
+	connCtx, closeConn := context.WithCancel(context.Background())
+	defer closeConn()
+	incoming := make(chan []byte)
+	go func() {
+		defer close(incoming)
+		defer closeConn() // A closed connection stops the reply in progress
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+					log.Printf("WebSocket error: %v", err)
+				}
+				return
+			}
+			select {
+			case incoming <- message:
+			case <-connCtx.Done():
+				return
 			}
-			break
 		}
+	}()
 
-		var req ChatRequest
-		if err := json.Unmarshal(message, &req); err != nil {
-			writeJSON(ChatResponse{Type: "error", Content: "Invalid request format"})
+	for message := range incoming {
+		req, errResp := parseChatRequest(message)
+		if errResp != nil {
+			writeJSON(*errResp)
 			continue
 		}
+		if req.Type == ChatRequestCancel {
+			continue // Nothing in progress: the reply already finished
+		}
 
-		if req.Message == "" {
-			writeJSON(ChatResponse{Type: "error", Content: "Message cannot be empty"})
+		release, err := h.limiter.acquire(ip, &window, req)
+		if err != nil {
+			writeJSON(limitErrorResponse(err))
 			continue
 		}
 
 		// Stream response from LLM
-		stream, model, err := h.chatService.Chat(req.ArticleID, req.Message, req.SelectedText)
+		replyCtx, cancelReply := context.WithCancel(connCtx)
+		stream, model, sources, err := h.startChat(replyCtx, req)
 		if err != nil {
-			writeJSON(ChatResponse{Type: "error", Content: err.Error()})
+			cancelReply()
+			release()
+			code := ChatErrorGenerationFailed
+			if errors.Is(err, service.ErrChatTooLong) {
+				code = ChatErrorInvalidRequest
+			}
+			writeJSON(ChatResponse{Type: "error", Code: code, Content: err.Error()})
 			continue
 		}
 
-		// Send each chunk to the client
-		for chunk := range stream {
+		answer, failed, cancelled := h.streamReply(stream, incoming, cancelReply, writeJSON)
+		cancelReply()
+		release()
+		if connCtx.Err() != nil {
+			return // Disconnected; nobody to send the rest to
+		}
+		writeJSON(ChatResponse{Type: "done", Model: model, Sources: sources, Cancelled: cancelled})
+
+		// Suggestions come from a separate, smaller model call; the client can send the next message meanwhile
+		if !failed && !cancelled && answer != "" {
+			go func(question, answer string) {
+				suggestions, err := h.chatService.SuggestFollowUps(question, answer)
+				if err != nil {
+					log.Printf("Failed to suggest follow-up questions: %v", err)
+					return
+				}
+				if len(suggestions) > 0 {
+					writeJSON(ChatResponse{Type: "suggestions", Suggestions: suggestions})
+				}
+			}(req.Message, answer)
+		}
+	}
+}
+
+// streamReply sends the chunks of a reply to the client until the stream closes, cancelling
+// the reply when the client asks to or the connection closes. The stream is always read to
+// the end, as the provider blocks until its chunks are read. Returns the text sent and
+// whether generation failed or was cancelled.
+func (h *ChatHandler) streamReply(stream <-chan llm.StreamChunk, incoming <-chan []byte, cancelReply func(), writeJSON func(ChatResponse) error) (string, bool, bool) {
+	var answer strings.Builder
+	failed, cancelled, finished := false, false, false
+	for {
+		select {
+		case chunk, ok := <-stream:
+			if !ok {
+				return answer.String(), failed, cancelled
+			}
+			if finished || cancelled {
+				continue
+			}
 			if chunk.Error != nil {
-				writeJSON(ChatResponse{Type: "error", Content: chunk.Error.Error()})
-				break
+				writeJSON(ChatResponse{Type: "error", Code: ChatErrorGenerationFailed, Content: chunk.Error.Error()})
+				failed, finished = true, true
+				continue
 			}
 			if chunk.Done {
-				break
+				finished = true
+				continue
 			}
 			if chunk.Content != "" {
+				answer.WriteString(chunk.Content)
 				if err := writeJSON(ChatResponse{Type: "chunk", Content: chunk.Content}); err != nil {
 					log.Printf("Failed to write chunk: %v", err)
-					break
+					cancelReply()
+					cancelled = true
 				}
 			}
+		case message, ok := <-incoming:
+			if !ok {
+				// The connection closed and cancelled the reply; read what is left of the stream
+				incoming = nil
+				cancelled = true
+				continue
+			}
+			req, errResp := parseChatRequest(message)
+			switch {
+			case errResp != nil:
+				writeJSON(*errResp)
+			case req.Type == ChatRequestCancel:
+				if !finished {
+					cancelReply()
+					cancelled = true
+				}
+			default:
+				writeJSON(ChatResponse{Type: "error", Code: ChatErrorReplyInProgress, Content: "A reply is in progress; wait for it or cancel it first"})
+			}
 		}
+	}
+}
+
+// parseChatRequest decodes a WebSocket chat frame, or returns the error frame to send back
+func parseChatRequest(message []byte) (ChatRequest, *ChatResponse) {
+	var req ChatRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return req, &ChatResponse{Type: "error", Code: ChatErrorInvalidRequest, Content: "Invalid request format"}
+	}
+	switch req.Type {
+	case "", ChatRequestMessage:
+		if req.Message == "" {
+			return req, &ChatResponse{Type: "error", Code: ChatErrorInvalidRequest, Content: "Message cannot be empty"}
+		}
+		req.Type = ChatRequestMessage
+	case ChatRequestCancel:
+	default:
+		return req, &ChatResponse{Type: "error", Code: ChatErrorInvalidRequest, Content: fmt.Sprintf("Unknown request type %q", req.Type)}
+	}
+	return req, nil
+}
+
+// startChat starts streaming the reply to a chat request in its mode; cancelling ctx stops it
+func (h *ChatHandler) startChat(ctx context.Context, req ChatRequest) (<-chan llm.StreamChunk, string, []service.ChatSource, error) {
+	switch req.Mode {
+	case "", ChatModeArticle:
+		return h.chatService.Chat(ctx, req.ArticleID, req.Message, req.SelectedText, req.Model)
+	case ChatModeCorpus:
+		return h.chatService.ChatCorpus(ctx, req.Message, req.Model)
+	default:
+		return nil, "", nil, fmt.Errorf("unknown chat mode %q", req.Mode)
+	}
+}
 
-		writeJSON(ChatResponse{Type: "done", Model: model})
+// limitErrorResponse builds the error frame for a message rejected by the chat limits
+func limitErrorResponse(err error) ChatResponse {
+	var limitErr *chatLimitError
+	if errors.As(err, &limitErr) {
+		return ChatResponse{Type: "error", Code: limitErr.code, Content: limitErr.message}
 	}
+	return ChatResponse{Type: "error", Code: ChatErrorInvalidRequest, Content: err.Error()}
 }
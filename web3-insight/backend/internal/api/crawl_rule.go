@@ -0,0 +1,204 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+type CrawlRuleHandler struct {
+	repo *repository.CrawlRuleRepository
+}
+
+func NewCrawlRuleHandler(repo *repository.CrawlRuleRepository) *CrawlRuleHandler {
+	return &CrawlRuleHandler{repo: repo}
+}
+
+// CrawlRuleRequest represents the request body for creating or updating a crawl rule
+type CrawlRuleRequest struct {
+	Domain          string   `json:"domain" binding:"required"` // Host or URL; the scheme, path and www. are dropped
+	ContentSelector string   `json:"contentSelector"`
+	TitleSelector   string   `json:"titleSelector"`
+	RemoveSelectors []string `json:"removeSelectors"`
+	RenderMode      string   `json:"renderMode"` // static (default) or amp
+}
+
+// List godoc
+// @Summary List crawl rules
+// @Description Get the per-domain extraction rules used by the crawler
+// @Tags crawl-rules
+// @Produce json
+// @Success 200 {array} model.CrawlRule
+// @Router /api/crawl-rules [get]
+func (h *CrawlRuleHandler) List(c *gin.Context) {
+	rules, err := h.repo.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// Get godoc
+// @Summary Get a crawl rule
+// @Tags crawl-rules
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} model.CrawlRule
+// @Router /api/crawl-rules/{id} [get]
+func (h *CrawlRuleHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	rule, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crawl rule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// Create godoc
+// @Summary Create a crawl rule
+// @Description Add extraction selectors for a domain. Crawlers pick up rule changes within a minute.
+// @Tags crawl-rules
+// @Accept json
+// @Produce json
+// @Param request body CrawlRuleRequest true "Rule"
+// @Success 201 {object} model.CrawlRule
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/crawl-rules [post]
+func (h *CrawlRuleHandler) Create(c *gin.Context) {
+	var req CrawlRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := &model.CrawlRule{}
+	if err := applyCrawlRule(rule, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if existing, err := h.repo.FindByDomain(rule.Domain); err == nil && existing != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "a rule for this domain already exists", "id": existing.ID})
+		return
+	}
+
+	if err := h.repo.Create(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// Update godoc
+// @Summary Update a crawl rule
+// @Tags crawl-rules
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param request body CrawlRuleRequest true "Rule"
+// @Success 200 {object} model.CrawlRule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/crawl-rules/{id} [put]
+func (h *CrawlRuleHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	rule, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crawl rule not found"})
+		return
+	}
+
+	var req CrawlRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyCrawlRule(rule, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if existing, err := h.repo.FindByDomain(rule.Domain); err == nil && existing.ID != rule.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "a rule for this domain already exists", "id": existing.ID})
+		return
+	}
+
+	if err := h.repo.Update(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// Delete godoc
+// @Summary Delete a crawl rule
+// @Tags crawl-rules
+// @Param id path string true "Rule ID"
+// @Success 204
+// @Router /api/crawl-rules/{id} [delete]
+func (h *CrawlRuleHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := h.repo.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// applyCrawlRule validates a request and copies it onto a rule
+func applyCrawlRule(rule *model.CrawlRule, req CrawlRuleRequest) error {
+	domain := strings.ToLower(strings.TrimSpace(req.Domain))
+	domain = strings.TrimPrefix(strings.TrimPrefix(domain, "https://"), "http://")
+	domain = strings.TrimPrefix(domain, "www.")
+	if idx := strings.IndexAny(domain, "/?#"); idx >= 0 {
+		domain = domain[:idx]
+	}
+	if domain == "" {
+		return fmt.Errorf("domain is required")
+	}
+
+	renderMode := req.RenderMode
+	if renderMode == "" {
+		renderMode = model.RenderModeStatic
+	}
+	if renderMode != model.RenderModeStatic && renderMode != model.RenderModeAMP {
+		return fmt.Errorf("renderMode must be %s or %s", model.RenderModeStatic, model.RenderModeAMP)
+	}
+
+	selectors := append([]string{req.ContentSelector, req.TitleSelector}, req.RemoveSelectors...)
+	for _, selector := range selectors {
+		if strings.TrimSpace(selector) == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			return fmt.Errorf("invalid selector %q: %v", selector, err)
+		}
+	}
+
+	rule.Domain = domain
+	rule.ContentSelector = strings.TrimSpace(req.ContentSelector)
+	rule.TitleSelector = strings.TrimSpace(req.TitleSelector)
+	rule.RemoveSelectors = req.RemoveSelectors
+	rule.RenderMode = renderMode
+	return nil
+}
@@ -1,28 +1,50 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"github.com/user/web3-insight/internal/collector"
+	"github.com/user/web3-insight/internal/config"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"github.com/user/web3-insight/internal/worker"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 type DataSourceHandler struct {
-	repo         *repository.DataSourceRepository
-	rssCollector *collector.RSSCollector
+	repo        *repository.DataSourceRepository
+	statsRepo   *repository.SourceStatsRepository
+	collectors  *collector.Registry
+	taskClient  worker.TaskEnqueuer
+	backfillCfg *config.BackfillConfig
+	discovery   *service.SourceDiscoveryService
 }
 
-func NewDataSourceHandler(db *gorm.DB) *DataSourceHandler {
+func NewDataSourceHandler(db *gorm.DB, taskClient worker.TaskEnqueuer, backfillCfg *config.BackfillConfig, collectorCfg *config.CollectorConfig, discovery *service.SourceDiscoveryService) *DataSourceHandler {
 	repo := repository.NewDataSourceRepository(db)
 	newsRepo := repository.NewNewsRepository(db)
+	fetchCache := collector.NewFetchCache(repository.NewFetchValidatorRepository(db))
+	webCrawler := collector.NewWebCrawler(newsRepo, repository.NewTaskRepository(db), fetchCache)
+	webCrawler.SetCrawlRules(repository.NewCrawlRuleRepository(db))
+	webCrawler.SetPDFExtractor(collector.NewPDFExtractorFromConfig(collectorCfg))
+	webCrawler.SetArchiveFetcher(collector.NewArchiveFetcherFromConfig(collectorCfg))
 	return &DataSourceHandler{
-		repo:         repo,
-		rssCollector: collector.NewRSSCollector(newsRepo, repo),
+		repo:        repo,
+		statsRepo:   repository.NewSourceStatsRepository(db),
+		collectors:  collector.NewDefaultRegistry(collector.NewRSSCollector(newsRepo, repo, fetchCache), webCrawler, newsRepo, repo, collectorCfg),
+		taskClient:  taskClient,
+		backfillCfg: backfillCfg,
+		discovery:   discovery,
 	}
 }
 
@@ -52,14 +74,51 @@ func (h *DataSourceHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, source)
 }
 
+// Stats returns a source's daily reliability stats for the last days (default 30, at most
+// 365) and their totals: duplicate, discard and correction rates and mean relevance score
+func (h *DataSourceHandler) Stats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+
+	source, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "data source not found"})
+		return
+	}
+
+	daily, err := h.statsRepo.ListBySource(id, time.Now().AddDate(0, 0, 1-days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sourceId": source.ID,
+		"name":     source.Name,
+		"days":     days,
+		"summary":  repository.SummarizeSourceStats(daily),
+		"daily":    daily,
+	})
+}
+
 // CreateDataSourceRequest represents the request body for creating a data source
 type CreateDataSourceRequest struct {
 	Name          string         `json:"name" binding:"required"`
-	Type          string         `json:"type" binding:"required,oneof=rss api crawl"`
+	Type          string         `json:"type" binding:"required"`
 	URL           string         `json:"url" binding:"required,url"`
 	Config        datatypes.JSON `json:"config,omitempty"`
 	Enabled       *bool          `json:"enabled,omitempty"`
 	FetchInterval int            `json:"fetchInterval,omitempty"`
+	Backfill      bool           `json:"backfill,omitempty"`      // Walk historical pages after creation
+	BackfillPages int            `json:"backfillPages,omitempty"` // Overrides the configured max pages
 }
 
 // Create creates a new data source
@@ -70,13 +129,13 @@ func (h *DataSourceHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// Validate RSS URL if type is RSS
-	if req.Type == model.DataSourceTypeRSS {
-		_, err := h.rssCollector.ValidateFeedURL(req.URL)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid RSS feed URL: " + err.Error()})
-			return
-		}
+	if err := h.validateSource(c.Request.Context(), req.Type, req.URL, req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if existing, err := h.repo.FindByName(req.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "a data source with this name already exists", "id": existing.ID})
+		return
 	}
 
 	source := &model.DataSource{
@@ -103,6 +162,12 @@ func (h *DataSourceHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if req.Backfill && supportsBackfill(source.Type) {
+		if _, err := h.enqueueBackfill(source.ID, req.BackfillPages); err != nil {
+			log.Printf("Failed to enqueue backfill for source %s: %v", source.ID, err)
+		}
+	}
+
 	c.JSON(http.StatusCreated, source)
 }
 
@@ -126,15 +191,28 @@ func (h *DataSourceHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// Validate RSS URL if type is RSS and URL changed
-	if req.Type == model.DataSourceTypeRSS && req.URL != source.URL {
-		_, err := h.rssCollector.ValidateFeedURL(req.URL)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid RSS feed URL: " + err.Error()})
+	// Re-validate with the collector if the type or URL changed
+	if req.Type != source.Type || req.URL != source.URL {
+		if err := h.validateSource(c.Request.Context(), req.Type, req.URL, req.Config); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
+	if _, err := collector.ParseItemFilter(req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := service.ParseRelevanceConfig(req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if existing, err := h.repo.FindByName(req.Name); err == nil && existing.ID != source.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "a data source with this name already exists", "id": existing.ID})
+		return
+	}
+
 	source.Name = req.Name
 	source.Type = req.Type
 	source.URL = req.URL
@@ -186,22 +264,128 @@ func (h *DataSourceHandler) TriggerSync(c *gin.Context) {
 		return
 	}
 
-	// For RSS sources, trigger immediate sync
-	if source.Type == model.DataSourceTypeRSS {
-		result, err := h.rssCollector.Collect(c.Request.Context(), id)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if _, ok := h.collectors.Get(source.Type); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sync not supported for this source type yet"})
+		return
+	}
+
+	result, err := h.collectors.SyncSource(c.Request.Context(), source)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "sync completed",
+		"itemsFound":    result.ItemsFound,
+		"itemsNew":      result.ItemsNew,
+		"itemsFiltered": result.ItemsFiltered,
+	})
+}
+
+// Preview fetches the source and returns what would be ingested without writing anything
+func (h *DataSourceHandler) Preview(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	source, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "data source not found"})
+		return
+	}
+
+	// Allow previewing unsaved config changes (e.g. filters) before updating the source
+	var req struct {
+		Config datatypes.JSON `json:"config,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+	if req.Config != nil {
+		source.Config = req.Config
+	}
+
+	if _, ok := h.collectors.Get(source.Type); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "preview not supported for this source type"})
+		return
+	}
+
+	result, err := h.collectors.Preview(c.Request.Context(), source)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Backfill starts a rate-limited crawl of the source's historical pages
+func (h *DataSourceHandler) Backfill(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	source, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "data source not found"})
+		return
+	}
+
+	if !supportsBackfill(source.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "backfill not supported for this source type"})
+		return
+	}
+
+	var req struct {
+		MaxPages int `json:"maxPages"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{
-			"message":    "sync completed",
-			"itemsFound": result.ItemsFound,
-			"itemsNew":   result.ItemsNew,
-		})
+	}
+
+	info, err := h.enqueueBackfill(source.ID, req.MaxPages)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusBadRequest, gin.H{"error": "sync not supported for this source type yet"})
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "backfill enqueued",
+		"taskId":  info.ID,
+	})
+}
+
+// supportsBackfill reports whether sources of a type have historical pages to walk
+func supportsBackfill(sourceType string) bool {
+	return sourceType == model.DataSourceTypeRSS || sourceType == model.DataSourceTypeCrawl
+}
+
+// enqueueBackfill enqueues the first page of a backfill task series
+func (h *DataSourceHandler) enqueueBackfill(sourceID uuid.UUID, maxPages int) (*asynq.TaskInfo, error) {
+	if maxPages <= 0 {
+		maxPages = h.backfillCfg.MaxPages
+	}
+	if maxPages <= 0 {
+		maxPages = 10
+	}
+
+	delay := h.backfillCfg.PageDelay
+	if delay <= 0 {
+		delay = 300
+	}
+
+	return worker.EnqueueSourceBackfill(h.taskClient, worker.SourceBackfillPayload{
+		SourceID:        sourceID.String(),
+		Page:            1,
+		MaxPages:        maxPages,
+		ArticlesPerPage: h.backfillCfg.ArticlesPerPage,
+		DelaySeconds:    delay,
+	})
 }
 
 // ValidateURL validates a feed URL without creating a source
@@ -215,23 +399,71 @@ func (h *DataSourceHandler) ValidateURL(c *gin.Context) {
 		return
 	}
 
-	if req.Type == model.DataSourceTypeRSS {
-		feed, err := h.rssCollector.ValidateFeedURL(req.URL)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"valid": false,
-				"error": err.Error(),
-			})
-			return
-		}
-		c.JSON(http.StatusOK, gin.H{
-			"valid":       true,
-			"title":       feed.Title,
-			"description": feed.Description,
-			"itemCount":   len(feed.Items),
+	if _, ok := h.collectors.Get(req.Type); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validation not supported for this type"})
+		return
+	}
+
+	result, err := h.collectors.Validate(c.Request.Context(), &model.DataSource{Type: req.Type, URL: req.URL})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"valid": false,
+			"error": err.Error(),
 		})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"valid":       true,
+		"title":       result.Title,
+		"description": result.Description,
+		"itemCount":   result.ItemCount,
+	})
+}
+
+// Types returns the source types that have a registered collector
+func (h *DataSourceHandler) Types(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"types": h.collectors.Types()})
+}
+
+// validateSource checks the filters and relevance settings and lets the type's collector
+// validate the URL
+func (h *DataSourceHandler) validateSource(ctx context.Context, sourceType, url string, sourceConfig datatypes.JSON) error {
+	if _, err := collector.ParseItemFilter(sourceConfig); err != nil {
+		return err
+	}
+	if _, err := service.ParseRelevanceConfig(sourceConfig); err != nil {
+		return err
+	}
+	if _, ok := h.collectors.Get(sourceType); !ok {
+		return fmt.Errorf("unsupported source type: %s", sourceType)
+	}
+
+	if _, err := h.collectors.Validate(ctx, &model.DataSource{Type: sourceType, URL: url, Config: sourceConfig}); err != nil {
+		return fmt.Errorf("invalid %s source: %w", sourceType, err)
+	}
+	return nil
+}
+
+// Discover proposes new data sources for a topic, returning validated drafts ready to approve
+func (h *DataSourceHandler) Discover(c *gin.Context) {
+	var req struct {
+		Topic string `json:"topic" binding:"required"`
+		Limit int    `json:"limit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, modelUsed, err := h.discovery.Discover(c.Request.Context(), req.Topic, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	c.JSON(http.StatusBadRequest, gin.H{"error": "validation not supported for this type"})
+	c.JSON(http.StatusOK, gin.H{
+		"topic":       req.Topic,
+		"suggestions": suggestions,
+		"modelUsed":   modelUsed,
+	})
 }
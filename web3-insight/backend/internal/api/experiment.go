@@ -0,0 +1,219 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+)
+
+type ExperimentHandler struct {
+	repo        *repository.ExperimentRepository
+	experiments *service.ExperimentService
+}
+
+func NewExperimentHandler(repo *repository.ExperimentRepository, experiments *service.ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{
+		repo:        repo,
+		experiments: experiments,
+	}
+}
+
+// ExperimentVariantRequest describes one variant of a new experiment
+type ExperimentVariantRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Prompt  string `json:"prompt"`
+	Model   string `json:"model"`
+	Traffic int    `json:"traffic"`
+}
+
+// CreateExperimentRequest represents the request body for creating an experiment
+type CreateExperimentRequest struct {
+	Name        string                     `json:"name" binding:"required"`
+	Description string                     `json:"description"`
+	Task        string                     `json:"task" binding:"required"`
+	Variants    []ExperimentVariantRequest `json:"variants" binding:"required,dive"`
+}
+
+// List godoc
+// @Summary List experiments
+// @Tags experiments
+// @Produce json
+// @Param status query string false "Filter by status (active, paused, completed)"
+// @Success 200 {array} model.Experiment
+// @Router /api/experiments [get]
+func (h *ExperimentHandler) List(c *gin.Context) {
+	experiments, err := h.repo.List(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, experiments)
+}
+
+// Get godoc
+// @Summary Get an experiment with per-variant results
+// @Tags experiments
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} repository.ExperimentResults
+// @Router /api/experiments/{id} [get]
+func (h *ExperimentHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid experiment ID"})
+		return
+	}
+
+	results, err := h.repo.GetResults(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "experiment not found"})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// Create godoc
+// @Summary Create an experiment
+// @Description Variants split traffic for a task; an empty prompt or model keeps the default
+// @Tags experiments
+// @Accept json
+// @Produce json
+// @Param request body CreateExperimentRequest true "Experiment"
+// @Success 201 {object} model.Experiment
+// @Router /api/experiments [post]
+func (h *ExperimentHandler) Create(c *gin.Context) {
+	var req CreateExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	experiment := &model.Experiment{
+		Name:        req.Name,
+		Description: req.Description,
+		Task:        req.Task,
+		Status:      model.ExperimentStatusActive,
+	}
+	for _, v := range req.Variants {
+		experiment.Variants = append(experiment.Variants, model.ExperimentVariant{
+			Name:    v.Name,
+			Prompt:  v.Prompt,
+			Model:   v.Model,
+			Traffic: v.Traffic,
+		})
+	}
+
+	if err := h.experiments.Validate(experiment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Create(experiment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, experiment)
+}
+
+// UpdateStatus godoc
+// @Summary Pause, resume or complete an experiment
+// @Tags experiments
+// @Accept json
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Param request body map[string]string true "Status"
+// @Success 200 {object} map[string]string
+// @Router /api/experiments/{id}/status [put]
+func (h *ExperimentHandler) UpdateStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid experiment ID"})
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required,oneof=active paused completed"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.repo.GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "experiment not found"})
+		return
+	}
+
+	if err := h.repo.UpdateStatus(id, req.Status); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "status updated"})
+}
+
+// ScoreRun godoc
+// @Summary Override the quality score of an experiment run
+// @Tags experiments
+// @Accept json
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Param runId path string true "Run ID"
+// @Param request body map[string]float64 true "Score between 0 and 1"
+// @Success 200 {object} map[string]string
+// @Router /api/experiments/{id}/runs/{runId}/score [put]
+func (h *ExperimentHandler) ScoreRun(c *gin.Context) {
+	experimentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid experiment ID"})
+		return
+	}
+	runID, err := uuid.Parse(c.Param("runId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid run ID"})
+		return
+	}
+
+	var req struct {
+		Score *float64 `json:"score" binding:"required,min=0,max=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := h.repo.GetRun(runID)
+	if err != nil || run.ExperimentID != experimentID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "experiment run not found"})
+		return
+	}
+
+	if err := h.repo.SetRunScore(runID, *req.Score); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "score updated"})
+}
+
+// Delete godoc
+// @Summary Delete an experiment and its runs
+// @Tags experiments
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} map[string]string
+// @Router /api/experiments/{id} [delete]
+func (h *ExperimentHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid experiment ID"})
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -42,39 +44,93 @@ type CreateExplorerRequest struct {
 	PopularityScore float64                `json:"popularityScore,omitempty"`
 	ResearchStatus  string                 `json:"researchStatus,omitempty"`
 	ResearchNotes   string                 `json:"researchNotes,omitempty"`
+	Assignee        *string                `json:"assignee,omitempty"` // Omitted on update keeps the current assignee
+	DueDate         *time.Time             `json:"dueDate,omitempty"`  // Omitted on update keeps the current due date
+}
+
+// BulkAssignRequest assigns several explorers to a team member
+type BulkAssignRequest struct {
+	IDs      []uuid.UUID `json:"ids" binding:"required,min=1"`
+	Assignee string      `json:"assignee"` // Empty unassigns
+	DueDate  *time.Time  `json:"dueDate"`  // Null clears the due date
+}
+
+// BulkStatusRequest moves several explorers to a research status
+type BulkStatusRequest struct {
+	IDs    []uuid.UUID `json:"ids" binding:"required,min=1"`
+	Status string      `json:"status" binding:"required,oneof=pending in_progress completed"`
 }
 
 // List godoc
 // @Summary List explorer research entries
-// @Description Get all explorer research entries with optional filters
+// @Description Get a page of explorer research entries with optional filters. Filters accept repeated or comma-separated values.
 // @Tags explorers
 // @Accept json
 // @Produce json
-// @Param chain query string false "Filter by chain name"
-// @Param status query string false "Filter by research status"
-// @Param limit query int false "Maximum results"
-// @Success 200 {array} model.ExplorerResearch
+// @Param chain query string false "Filter by chain names"
+// @Param chainType query string false "Filter by chain types (L1, L2, sidechain)"
+// @Param status query string false "Filter by research statuses"
+// @Param assignee query string false "Filter by assignees"
+// @Param sort query string false "Sort by chain (default), popularity, last_updated or due_date"
+// @Param order query string false "asc or desc"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Page size (default: 50, max: 200)"
+// @Success 200 {object} map[string]interface{}
 // @Router /api/explorers [get]
 func (h *ExplorerHandler) List(c *gin.Context) {
-	chainName := c.Query("chain")
-	status := c.Query("status")
-	limit := 0
-	if l := c.Query("limit"); l != "" {
-		limit, _ = strconv.Atoi(l)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	sort := c.Query("sort")
+	switch sort {
+	case "", repository.ExplorerSortChain, repository.ExplorerSortPopularity, repository.ExplorerSortLastUpdated, repository.ExplorerSortDueDate:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort: must be chain, popularity, last_updated or due_date"})
+		return
+	}
+	order := c.Query("order")
+	if order != "" && order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order: must be asc or desc"})
+		return
 	}
 
-	explorers, err := h.explorerRepo.List(chainName, status, limit)
+	result, err := h.explorerRepo.List(repository.ExplorerListParams{
+		ChainNames: queryList(c, "chain"),
+		ChainTypes: queryList(c, "chainType"),
+		Statuses:   queryList(c, "status"),
+		Assignees:  queryList(c, "assignee"),
+		Sort:       sort,
+		Order:      order,
+		Page:       page,
+		PageSize:   limit,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":  explorers,
-		"count": len(explorers),
+		"data":     result.Explorers,
+		"count":    len(result.Explorers),
+		"total":    result.Total,
+		"page":     result.Page,
+		"pageSize": result.PageSize,
 	})
 }
 
+// queryList collects a multi-value query parameter given as repeated and/or comma-separated values
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
 // Get godoc
 // @Summary Get explorer research by ID
 // @Description Get a single explorer research entry
@@ -136,6 +192,10 @@ func (h *ExplorerHandler) Create(c *gin.Context) {
 		ResearchStatus:  req.ResearchStatus,
 		ResearchNotes:   req.ResearchNotes,
 		Screenshots:     req.Screenshots,
+		DueDate:         req.DueDate,
+	}
+	if req.Assignee != nil {
+		explorer.Assignee = *req.Assignee
 	}
 
 	if req.Features != nil {
@@ -162,7 +222,7 @@ func (h *ExplorerHandler) Create(c *gin.Context) {
 
 // Update godoc
 // @Summary Update explorer research entry
-// @Description Update an existing explorer research entry
+// @Description Update an existing explorer research entry. An omitted assignee or due date is kept; bulk assign clears them.
 // @Tags explorers
 // @Accept json
 // @Produce json
@@ -202,6 +262,12 @@ func (h *ExplorerHandler) Update(c *gin.Context) {
 	explorer.ResearchNotes = req.ResearchNotes
 	explorer.Screenshots = req.Screenshots
 
+	if req.Assignee != nil {
+		explorer.Assignee = *req.Assignee
+	}
+	if req.DueDate != nil {
+		explorer.DueDate = req.DueDate
+	}
 	if req.ResearchStatus != "" {
 		explorer.ResearchStatus = req.ResearchStatus
 	}
@@ -355,6 +421,56 @@ func (h *ExplorerHandler) UpdateStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "status updated", "status": req.Status})
 }
 
+// BulkAssign godoc
+// @Summary Assign explorer research entries
+// @Description Set the assignee and due date of several explorers at once
+// @Tags explorers
+// @Accept json
+// @Produce json
+// @Param body body BulkAssignRequest true "Explorer IDs and assignment"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/explorers/bulk/assign [post]
+func (h *ExplorerHandler) BulkAssign(c *gin.Context) {
+	var req BulkAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.explorerRepo.BulkAssign(req.IDs, req.Assignee, req.DueDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "assigned", "updated": updated})
+}
+
+// BulkUpdateStatus godoc
+// @Summary Update research status of explorer entries
+// @Description Move several explorers to a research status at once
+// @Tags explorers
+// @Accept json
+// @Produce json
+// @Param body body BulkStatusRequest true "Explorer IDs and status"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/explorers/bulk/status [post]
+func (h *ExplorerHandler) BulkUpdateStatus(c *gin.Context) {
+	var req BulkStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.explorerRepo.BulkUpdateStatus(req.IDs, req.Status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "status updated", "status": req.Status, "updated": updated})
+}
+
 // Compare godoc
 // @Summary Compare explorers
 // @Description Compare multiple explorers side by side
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
+)
+
+type ExportHandler struct {
+	site      *service.SiteExporter
+	documents *service.DocumentExporter
+}
+
+func NewExportHandler(site *service.SiteExporter, documents *service.DocumentExporter) *ExportHandler {
+	return &ExportHandler{site: site, documents: documents}
+}
+
+// ExportSite godoc
+// @Summary Export a static site
+// @Description Download published articles as a Hugo site zip: content/ with a section per category, a page per article with YAML frontmatter and a page per tag, plus hugo.toml and an assets.json manifest of the images the articles refer to
+// @Tags export
+// @Produce application/zip
+// @Param categoryId query string false "Only export this category and its subcategories"
+// @Success 200 {file} file "Site zip"
+// @Router /api/export/site [get]
+func (h *ExportHandler) Site(c *gin.Context) {
+	var categoryID *uuid.UUID
+	if raw := c.Query("categoryId"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid categoryId"})
+			return
+		}
+		categoryID = &parsed
+	}
+
+	bundle, err := h.site.Build(categoryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := bundle.WriteZip(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=site-"+bundle.Manifest.GeneratedAt.Format("20060102")+".zip")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// ExportArticle godoc
+// @Summary Export an article as PDF or EPUB
+// @Description Download an article as a styled document with CJK fonts and a table of contents of its headings. PDF export needs export.pdf_renderer_url in the config.
+// @Tags export
+// @Produce application/pdf
+// @Produce application/epub+zip
+// @Param id path string true "Article ID"
+// @Param format query string false "pdf (default) or epub"
+// @Success 200 {file} file "Document"
+// @Failure 503 {object} map[string]string "PDF rendering is not configured"
+// @Router /api/articles/{id}/export [get]
+func (h *ExportHandler) Article(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	format, ok := documentFormat(c)
+	if !ok {
+		return
+	}
+
+	doc, err := h.documents.ArticleDocument(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	h.writeDocument(c, doc, format)
+}
+
+// ExportCategory godoc
+// @Summary Export a category as PDF or EPUB
+// @Description Download the published articles of a category and its subcategories as one document, a chapter per article with prerequisites first, and a table of contents. PDF export needs export.pdf_renderer_url in the config.
+// @Tags export
+// @Produce application/pdf
+// @Produce application/epub+zip
+// @Param id path string true "Category ID"
+// @Param format query string false "pdf (default) or epub"
+// @Success 200 {file} file "Document"
+// @Failure 503 {object} map[string]string "PDF rendering is not configured"
+// @Router /api/categories/{id}/export [get]
+func (h *ExportHandler) Category(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	format, ok := documentFormat(c)
+	if !ok {
+		return
+	}
+
+	doc, err := h.documents.CategoryDocument(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if len(doc.Chapters) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category has no published articles"})
+		return
+	}
+
+	h.writeDocument(c, doc, format)
+}
+
+// documentFormat reads the format query parameter, responding 400 if it is unsupported
+func documentFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", service.DocumentFormatPDF)
+	if !service.IsValidDocumentFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be pdf or epub"})
+		return "", false
+	}
+	return format, true
+}
+
+// writeDocument renders a document and sends it as a download
+func (h *ExportHandler) writeDocument(c *gin.Context, doc *service.Document, format string) {
+	var data []byte
+	var contentType string
+	var err error
+	if format == service.DocumentFormatEPUB {
+		data, err = h.documents.EPUB(doc)
+		contentType = "application/epub+zip"
+	} else {
+		data, err = h.documents.PDF(c.Request.Context(), doc)
+		contentType = "application/pdf"
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrPDFNotConfigured) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.Title + "." + format}))
+	c.Data(http.StatusOK, contentType, data)
+}
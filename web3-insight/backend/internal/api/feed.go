@@ -0,0 +1,248 @@
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/web3-insight/internal/markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Output feed settings
+const (
+	feedTitle   = "Web3 Insight"
+	feedEntries = 50
+	feedMaxAge  = "public, max-age=300"
+)
+
+// atomFeed is an Atom 1.0 feed document (RFC 4287)
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   atomPerson  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Rights     string         `xml:"rights,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type FeedHandler struct {
+	articleRepo  *repository.ArticleRepository
+	categoryRepo *repository.CategoryRepository
+	publicURL    string
+}
+
+func NewFeedHandler(articleRepo *repository.ArticleRepository, categoryRepo *repository.CategoryRepository, publicURL string) *FeedHandler {
+	return &FeedHandler{
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		publicURL:    strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// GlobalFeed godoc
+// @Summary Atom feed of all articles
+// @Description Get the most recently updated published articles as an Atom feed
+// @Tags feeds
+// @Produce xml
+// @Success 200 {string} string "Atom feed"
+// @Success 304 "Not modified since If-Modified-Since"
+// @Router /feeds.xml [get]
+func (h *FeedHandler) Global(c *gin.Context) {
+	articles, err := h.articleRepo.ListPublished(nil, feedEntries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.write(c, feedTitle, "", articles)
+}
+
+// CategoryFeed godoc
+// @Summary Atom feed of a category
+// @Description Get the most recently updated published articles of a category and its subcategories as an Atom feed. The feed of a merged category redirects to the feed of the category it was merged into.
+// @Tags feeds
+// @Produce xml
+// @Param file path string true "Category slug followed by .xml"
+// @Success 200 {string} string "Atom feed"
+// @Success 301 "Moved to the feed of the category a merged category went into"
+// @Success 304 "Not modified since If-Modified-Since"
+// @Router /feeds/{file} [get]
+func (h *FeedHandler) Category(c *gin.Context) {
+	categorySlug, ok := strings.CutSuffix(c.Param("file"), ".xml")
+	if !ok || categorySlug == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+		return
+	}
+
+	category, err := h.categoryRepo.GetBySlug(categorySlug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if target, mergeErr := h.categoryRepo.GetByMergedRef(categorySlug); mergeErr == nil {
+			c.Redirect(http.StatusMovedPermanently, "/feeds/"+target.Slug+".xml")
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	categoryIDs, err := h.categoryRepo.SubtreeIDs(category.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	articles, err := h.articleRepo.ListPublished(categoryIDs, feedEntries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.write(c, feedTitle+" - "+category.Name, category.Description, articles)
+}
+
+// write responds with an Atom feed of articles, or 304 if none changed since If-Modified-Since
+func (h *FeedHandler) write(c *gin.Context, title, subtitle string, articles []model.Article) {
+	var updated time.Time
+	for _, a := range articles {
+		if a.UpdatedAt.After(updated) {
+			updated = a.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	updated = updated.UTC().Truncate(time.Second)
+
+	if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !updated.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	self := requestBaseURL(c) + c.Request.URL.Path
+	site := h.publicURL
+	if site == "" {
+		site = requestBaseURL(c)
+	}
+
+	feed := atomFeed{
+		ID:       self,
+		Title:    title,
+		Subtitle: subtitle,
+		Updated:  updated.Format(time.RFC3339),
+		Author:   atomPerson{Name: feedTitle},
+		Links: []atomLink{
+			{Href: self, Rel: "self", Type: "application/atom+xml"},
+			{Href: site + "/knowledge", Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, 0, len(articles)),
+	}
+	for i := range articles {
+		feed.Entries = append(feed.Entries, atomEntryFor(&articles[i], site))
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", feedMaxAge)
+	c.Header("Last-Modified", updated.Format(http.TimeFormat))
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// atomEntryFor builds the feed entry of an article, linking to its page on the site
+func atomEntryFor(article *model.Article, site string) atomEntry {
+	entry := atomEntry{
+		ID:      "urn:uuid:" + article.ID.String(),
+		Title:   article.Title,
+		Updated: article.UpdatedAt.UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Href: site + "/knowledge/" + article.Slug, Rel: "alternate", Type: "text/html"}},
+		Rights:  article.Attribution,
+	}
+
+	published := article.CreatedAt
+	if article.PublishAt != nil {
+		published = *article.PublishAt
+	}
+	entry.Published = published.UTC().Format(time.RFC3339)
+
+	if article.Category != nil {
+		entry.Categories = append(entry.Categories, atomCategory{Term: article.Category.Slug, Label: article.Category.Name})
+	}
+	for _, tag := range article.Tags {
+		entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+	}
+
+	summary := article.MetaDescription
+	if summary == "" {
+		summary = article.Summary
+	}
+	if summary != "" {
+		entry.Summary = &atomText{Type: "text", Body: summary}
+	}
+
+	content := article.ContentHTML
+	if content == "" && article.Content != "" {
+		content, _ = markdown.RenderHTML(article.Content)
+	}
+	if content != "" {
+		entry.Content = &atomText{Type: "html", Body: content}
+	}
+	return entry
+}
+
+// requestBaseURL returns the scheme and host the request was sent to, honouring
+// X-Forwarded-Proto from a reverse proxy
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
+)
+
+// Limits of a graph request
+const (
+	defaultGraphDepth = 2
+	maxGraphDepth     = 4
+	defaultGraphNodes = 100
+	maxGraphNodes     = 500
+)
+
+type GraphHandler struct {
+	repo  *repository.ArticleRepository
+	links *service.ConceptLinkService
+}
+
+func NewGraphHandler(repo *repository.ArticleRepository, links *service.ConceptLinkService) *GraphHandler {
+	return &GraphHandler{repo: repo, links: links}
+}
+
+type LinkRequest struct {
+	LinkedID uuid.UUID `json:"linkedId" binding:"required"`
+	Type     string    `json:"type" binding:"required"` // mentions, depends_on or contrasts_with
+	Reason   string    `json:"reason"`
+}
+
+// GetGraph godoc
+// @Summary Get the concept graph around an article
+// @Description Get the articles within depth links of the root article, following concept links and prerequisites in either direction, as nodes and edges for visualization. Edges point from an article to the one it mentions, depends on or contrasts with; prerequisites appear as depends_on edges.
+// @Tags graph
+// @Produce json
+// @Param root query string true "Root article ID or slug"
+// @Param depth query int false "Number of links to follow (default: 2, max: 4)"
+// @Param types query string false "Link types to follow (comma-separated: mentions, depends_on, contrasts_with)"
+// @Param limit query int false "Maximum number of nodes (default: 100, max: 500)"
+// @Success 200 {object} model.Graph
+// @Router /api/graph [get]
+func (h *GraphHandler) Get(c *gin.Context) {
+	rootParam := c.Query("root")
+	if rootParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "root is required"})
+		return
+	}
+
+	var root *model.Article
+	var err error
+	if id, parseErr := uuid.Parse(rootParam); parseErr == nil {
+		root, err = h.repo.GetByID(id)
+	} else {
+		root, err = h.repo.GetBySlug(rootParam)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	depth := defaultGraphDepth
+	if raw := c.Query("depth"); raw != "" {
+		d, err := strconv.Atoi(raw)
+		if err != nil || d < 1 || d > maxGraphDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid depth"})
+			return
+		}
+		depth = d
+	}
+
+	limit := defaultGraphNodes
+	if raw := c.Query("limit"); raw != "" {
+		if l, err := strconv.Atoi(raw); err == nil && l > 0 {
+			limit = min(l, maxGraphNodes)
+		}
+	}
+
+	types := queryList(c, "types")
+	for _, t := range types {
+		if !model.IsValidLinkType(t) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type: " + t})
+			return
+		}
+	}
+
+	graph, err := h.repo.Graph(root.ID, depth, types, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// ListLinks godoc
+// @Summary List article concept links
+// @Description Get the concept graph links of an article, in either direction
+// @Tags graph
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticleLink
+// @Router /api/articles/{id}/links [get]
+func (h *GraphHandler) ListLinks(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	links, err := h.repo.ListLinks(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// AddLink godoc
+// @Summary Add an article concept link
+// @Description Link the article to another that it mentions, depends on or contrasts with. An existing link of the same type is updated and becomes manual.
+// @Tags graph
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param request body LinkRequest true "Link"
+// @Success 201 {object} model.ArticleLink
+// @Router /api/articles/{id}/links [post]
+func (h *GraphHandler) AddLink(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req LinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !model.IsValidLinkType(req.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type"})
+		return
+	}
+	if req.LinkedID == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "an article cannot link to itself"})
+		return
+	}
+	if !articlesExist(c, h.repo, id, req.LinkedID) {
+		return
+	}
+
+	link := &model.ArticleLink{
+		ArticleID:  id,
+		LinkedID:   req.LinkedID,
+		Type:       req.Type,
+		Source:     model.LinkSourceManual,
+		Confidence: 1,
+		Reason:     req.Reason,
+	}
+	if err := h.repo.AddLink(link); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// RemoveLink godoc
+// @Summary Remove an article concept link
+// @Tags graph
+// @Param id path string true "Article ID"
+// @Param linkId path string true "Link ID"
+// @Success 204
+// @Router /api/articles/{id}/links/{linkId} [delete]
+func (h *GraphHandler) RemoveLink(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid link id"})
+		return
+	}
+
+	if err := h.repo.RemoveLink(id, linkID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExtractLinks godoc
+// @Summary Extract article concept links
+// @Description Ask the LLM how the article relates to similar articles; replaces earlier extractions and keeps manual links
+// @Tags graph
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticleLink
+// @Router /api/articles/{id}/links/extract [post]
+func (h *GraphHandler) ExtractLinks(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if !articlesExist(c, h.repo, id) {
+		return
+	}
+
+	links, err := h.links.ExtractAndUpdate(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
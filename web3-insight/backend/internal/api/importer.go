@@ -1,37 +1,64 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/cache"
+	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
 	"github.com/user/web3-insight/internal/service"
+	"github.com/user/web3-insight/internal/worker"
 	"gorm.io/gorm"
 )
 
 type ImportHandler struct {
-	importer *service.ArticleImporter
+	importer   *service.ArticleImporter
+	taskRepo   *repository.TaskRepository
+	taskClient worker.TaskEnqueuer
 }
 
-func NewImportHandler(db *gorm.DB) *ImportHandler {
+func NewImportHandler(db *gorm.DB, hooks *service.ArticleHooks, sharedCache *cache.Cache, taskClient worker.TaskEnqueuer) *ImportHandler {
 	articleRepo := repository.NewArticleRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
+	categoryRepo.SetCache(sharedCache)
+
+	importer := service.NewArticleImporter(articleRepo, categoryRepo)
+	importer.SetArticleHooks(hooks)
 
 	return &ImportHandler{
-		importer: service.NewArticleImporter(articleRepo, categoryRepo),
+		importer:   importer,
+		taskRepo:   repository.NewTaskRepository(db),
+		taskClient: taskClient,
 	}
 }
 
+// ImportJob is the status of a background import
+type ImportJob struct {
+	ID          uuid.UUID             `json:"id"`
+	Status      string                `json:"status"` // pending, running, completed, failed or cancelled
+	Result      *service.ImportResult `json:"result,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	StartedAt   *time.Time            `json:"startedAt,omitempty"`
+	CompletedAt *time.Time            `json:"completedAt,omitempty"`
+}
+
 // Import godoc
 // @Summary Import articles from JSON
-// @Description Import multiple articles from JSON format
+// @Description Start a background import of multiple articles from JSON format. Follow its progress with GET /api/import/jobs/{id}.
 // @Tags import
 // @Accept json
 // @Produce json
 // @Param body body service.ImportBatch true "Import batch"
-// @Success 200 {object} service.ImportResult
+// @Success 202 {object} ImportJob
 // @Failure 400 {object} map[string]string
 // @Router /api/import [post]
 func (h *ImportHandler) Import(c *gin.Context) {
@@ -52,18 +79,12 @@ func (h *ImportHandler) Import(c *gin.Context) {
 		return
 	}
 
-	result, err := h.importer.Import(*batch)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, result)
+	h.startJob(c, *batch, nil)
 }
 
 // Validate godoc
 // @Summary Validate import data
-// @Description Validate import data without actually importing
+// @Description Validate import data without actually importing. The plan reports per article whether it would be created, updated or skipped, with the fields updates change on articles matched by slug.
 // @Tags import
 // @Accept json
 // @Produce json
@@ -85,12 +106,19 @@ func (h *ImportHandler) Validate(c *gin.Context) {
 	}
 
 	errors := h.importer.ValidateImport(*batch)
+	dryRun := h.importer.DryRun(*batch)
 
 	c.JSON(http.StatusOK, gin.H{
-		"valid":       len(errors) == 0,
-		"errors":      errors,
-		"errorCount":  len(errors),
-		"totalCount":  len(batch.Articles),
+		"valid":          len(errors) == 0,
+		"errors":         errors,
+		"errorCount":     len(errors),
+		"totalCount":     len(batch.Articles),
+		"plan":           dryRun.Plan,
+		"createCount":    dryRun.CreateCount,
+		"updateCount":    dryRun.UpdateCount,
+		"skipCount":      dryRun.SkipCount,
+		"unchangedCount": dryRun.UnchangedCount,
+		"invalidCount":   dryRun.InvalidCount,
 	})
 }
 
@@ -115,7 +143,10 @@ func (h *ImportHandler) GetTemplate(c *gin.Context) {
 // @Produce json
 // @Param categoryId query string false "Filter by category ID"
 // @Param status query string false "Filter by status"
+// @Param updatedSince query string false "Only articles changed after this time (RFC 3339)"
+// @Param createdSince query string false "Only articles created after this time (RFC 3339)"
 // @Success 200 {object} service.ImportBatch
+// @Failure 400 {object} map[string]string
 // @Router /api/import/export [get]
 func (h *ImportHandler) Export(c *gin.Context) {
 	var categoryID *uuid.UUID
@@ -125,9 +156,24 @@ func (h *ImportHandler) Export(c *gin.Context) {
 		}
 	}
 
-	status := c.Query("status")
+	filter := service.ExportFilter{
+		CategoryID: categoryID,
+		Status:     c.Query("status"),
+	}
+	for key, target := range map[string]**time.Time{"updatedSince": &filter.UpdatedSince, "createdSince": &filter.CreatedSince} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + key + ", expected RFC 3339"})
+			return
+		}
+		*target = &t
+	}
 
-	data, err := h.importer.BatchExportToJSON(categoryID, status)
+	data, err := h.importer.BatchExportToJSON(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -140,14 +186,14 @@ func (h *ImportHandler) Export(c *gin.Context) {
 
 // UploadFile godoc
 // @Summary Upload and import JSON file
-// @Description Upload a JSON file containing articles to import
+// @Description Upload a JSON file containing articles and start a background import of them. Follow its progress with GET /api/import/jobs/{id}.
 // @Tags import
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formance file true "JSON file to import"
 // @Param skipDuplicates formData bool false "Skip duplicate articles"
 // @Param updateExisting formData bool false "Update existing articles"
-// @Success 200 {object} service.ImportResult
+// @Success 202 {object} ImportJob
 // @Failure 400 {object} map[string]string
 // @Router /api/import/upload [post]
 func (h *ImportHandler) UploadFile(c *gin.Context) {
@@ -196,12 +242,163 @@ func (h *ImportHandler) UploadFile(c *gin.Context) {
 	if c.PostForm("updateExisting") == "true" {
 		batch.Options.UpdateExisting = true
 	}
+	if len(batch.Articles) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no articles to import"})
+		return
+	}
+
+	h.startJob(c, *batch, nil)
+}
+
+// ImportMarkdown godoc
+// @Summary Import markdown files
+// @Description Upload a zip of .md files, or a single .md file, and start a background import of one article per file; follow it with GET /api/import/jobs/{id}. Files that cannot be parsed are reported as errors with index -1. YAML frontmatter may set title, slug, tags, category (path), status, summary and sources; without a title the leading "# " heading or file name is used. Relative image links are embedded from the zip, or rewritten under imageBaseUrl.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Zip archive or markdown file"
+// @Param skipDuplicates formData bool false "Skip duplicate articles"
+// @Param updateExisting formData bool false "Update existing articles"
+// @Param defaultStatus formData string false "Status of articles without one in frontmatter (default: draft)"
+// @Param dirsAsCategories formData bool false "Use the directory of a file as its category path when frontmatter has none"
+// @Param imageBaseUrl formData string false "Base URL where the zip's images are hosted"
+// @Success 202 {object} ImportJob
+// @Failure 400 {object} map[string]string
+// @Router /api/import/markdown [post]
+func (h *ImportHandler) ImportMarkdown(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	ext := strings.ToLower(path.Ext(file.Filename))
+	if ext != ".zip" && ext != ".md" && ext != ".markdown" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only .zip and .md files are supported"})
+		return
+	}
+
+	// Limit file size to 50MB, archives may carry images
+	if file.Size > 50*1024*1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds 50MB limit"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	opts := service.ImportOptions{
+		SkipDuplicates: c.PostForm("skipDuplicates") == "true",
+		UpdateExisting: c.PostForm("updateExisting") == "true",
+		DefaultStatus:  c.PostForm("defaultStatus"),
+	}
+	if opts.DefaultStatus != "" && opts.DefaultStatus != "draft" && opts.DefaultStatus != "published" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid defaultStatus"})
+		return
+	}
+	mdOpts := service.MarkdownImportOptions{
+		ImageBaseURL:     strings.TrimSpace(c.PostForm("imageBaseUrl")),
+		DirsAsCategories: c.PostForm("dirsAsCategories") == "true",
+	}
+
+	batch := &service.ImportBatch{}
+	var parseErrors []service.ImportError
+	if ext == ".zip" {
+		batch, parseErrors, err = service.ParseMarkdownZip(data, mdOpts)
+	} else {
+		var article *service.ImportArticle
+		article, err = service.ParseMarkdownArticle(path.Base(file.Filename), data, mdOpts)
+		if err == nil {
+			batch.Articles = append(batch.Articles, *article)
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(batch.Articles) == 0 && len(parseErrors) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no markdown files to import"})
+		return
+	}
+	batch.Options = opts
+
+	h.startJob(c, *batch, parseErrors)
+}
+
+// GetImportJob godoc
+// @Summary Get import job status
+// @Description Get the status and progress of a background import. The result counts the articles processed so far and lists the ones that failed.
+// @Tags import
+// @Produce json
+// @Param id path string true "Import job ID"
+// @Success 200 {object} ImportJob
+// @Failure 404 {object} map[string]string
+// @Router /api/import/jobs/{id} [get]
+func (h *ImportHandler) GetJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	task, err := h.taskRepo.GetByID(id)
+	if err == nil && task.Type != model.TaskTypeImport {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, importJob(task))
+}
 
-	result, err := h.importer.Import(*batch)
+// startJob records an import of the batch, enqueues it and responds 202 with the job
+func (h *ImportHandler) startJob(c *gin.Context, batch service.ImportBatch, parseErrors []service.ImportError) {
+	task, err := service.CreateImportTask(h.taskRepo, batch, parseErrors)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	if _, err := worker.EnqueueImport(h.taskClient, task.ID.String()); err != nil {
+		task.Status = model.TaskStatusFailed
+		task.Error = err.Error()
+		h.taskRepo.Update(task)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, importJob(task))
+}
+
+// importJob describes an import task record
+func importJob(task *model.Task) ImportJob {
+	job := ImportJob{
+		ID:          task.ID,
+		Status:      task.Status,
+		Error:       task.Error,
+		CreatedAt:   task.CreatedAt,
+		StartedAt:   task.StartedAt,
+		CompletedAt: task.CompletedAt,
+	}
+	var result service.ImportResult
+	if len(task.Result) > 0 && json.Unmarshal(task.Result, &result) == nil {
+		job.Result = &result
+	}
+	return job
 }
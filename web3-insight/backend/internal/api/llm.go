@@ -0,0 +1,163 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+type LLMHandler struct {
+	llmRouter *llm.Router
+	callRepo  *repository.LLMCallRepository
+}
+
+func NewLLMHandler(llmRouter *llm.Router, callRepo *repository.LLMCallRepository) *LLMHandler {
+	return &LLMHandler{llmRouter: llmRouter, callRepo: callRepo}
+}
+
+// GetBudget godoc
+// @Summary Get LLM budget status
+// @Description Get spend and remaining budget for each configured provider and task limit
+// @Tags llm
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/llm/budget [get]
+func (h *LLMHandler) GetBudget(c *gin.Context) {
+	budget := h.llmRouter.Budget()
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": budget != nil,
+		"budgets": budget.Status(),
+	})
+}
+
+// GetConcurrency godoc
+// @Summary Get LLM concurrency status
+// @Description Get the limit, in-flight calls and queued calls of each provider or model semaphore in this process
+// @Tags llm
+// @Produce json
+// @Success 200 {array} llm.ConcurrencyStatus
+// @Router /api/llm/concurrency [get]
+func (h *LLMHandler) GetConcurrency(c *gin.Context) {
+	c.JSON(http.StatusOK, h.llmRouter.ConcurrencyStatus())
+}
+
+// ListCalls godoc
+// @Summary List audited LLM calls
+// @Description Get paginated LLM calls, newest first, without prompt and response text
+// @Tags llm
+// @Produce json
+// @Param task query string false "Filter by routing task"
+// @Param model query string false "Filter by model"
+// @Param provider query string false "Filter by provider"
+// @Param status query string false "Filter by status (success, error)"
+// @Param prompt_hash query string false "Filter by prompt SHA-256"
+// @Param since query string false "Only calls at or after this time (RFC 3339)"
+// @Param until query string false "Only calls before this time (RFC 3339)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 50, max: 200)"
+// @Success 200 {object} repository.LLMCallListResult
+// @Router /api/llm/calls [get]
+func (h *LLMHandler) ListCalls(c *gin.Context) {
+	params, ok := callListParams(c)
+	if !ok {
+		return
+	}
+	params.Page, _ = strconv.Atoi(c.Query("page"))
+	params.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+
+	result, err := h.callRepo.List(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCall godoc
+// @Summary Get an audited LLM call
+// @Description Get one LLM call including its prompt and response
+// @Tags llm
+// @Produce json
+// @Param id path string true "Call ID"
+// @Success 200 {object} model.LLMCall
+// @Router /api/llm/calls/{id} [get]
+func (h *LLMHandler) GetCall(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	call, err := h.callRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "call not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, call)
+}
+
+// CallStats godoc
+// @Summary Get LLM latency statistics
+// @Description Get call count, error count, latency percentiles, tokens and cost per provider and model
+// @Tags llm
+// @Produce json
+// @Param task query string false "Filter by routing task"
+// @Param since query string false "Only calls at or after this time (RFC 3339, default: 24 hours ago)"
+// @Param until query string false "Only calls before this time (RFC 3339)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/llm/calls/stats [get]
+func (h *LLMHandler) CallStats(c *gin.Context) {
+	params, ok := callListParams(c)
+	if !ok {
+		return
+	}
+	if params.Since == nil {
+		since := time.Now().Add(-24 * time.Hour)
+		params.Since = &since
+	}
+
+	stats, err := h.callRepo.LatencyStats(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"since": params.Since,
+		"until": params.Until,
+		"stats": stats,
+	})
+}
+
+// callListParams reads the call filters, writing a 400 response and returning false if a time is invalid
+func callListParams(c *gin.Context) (repository.LLMCallListParams, bool) {
+	params := repository.LLMCallListParams{
+		Task:       c.Query("task"),
+		Model:      c.Query("model"),
+		Provider:   c.Query("provider"),
+		Status:     c.Query("status"),
+		PromptHash: c.Query("prompt_hash"),
+	}
+
+	for key, target := range map[string]**time.Time{"since": &params.Since, "until": &params.Until} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + key + ", expected RFC 3339"})
+			return params, false
+		}
+		*target = &t
+	}
+	return params, true
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
+	"github.com/user/web3-insight/internal/config"
 )
 
 func corsMiddleware() gin.HandlerFunc {
@@ -19,3 +22,16 @@ func corsMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// trustProxies limits the proxies whose forwarding headers set c.ClientIP() to the configured
+// ones. Gin trusts every proxy by default, which lets any client pick the IP it is limited by.
+func trustProxies(router *gin.Engine, cfg *config.Config) {
+	var proxies []string
+	if cfg != nil {
+		proxies = cfg.Server.TrustedProxies
+	}
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		log.Printf("Invalid server.trusted_proxies, trusting no proxy: %v", err)
+		router.SetTrustedProxies(nil)
+	}
+}
@@ -1,26 +1,71 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/collector"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/worker"
 	"gorm.io/gorm"
 )
 
 type NewsHandler struct {
-	repo *repository.NewsRepository
+	repo       *repository.NewsRepository
+	trendRepo  *repository.TrendingTopicRepository
+	pdf        *collector.PDFExtractor
+	taskClient worker.TaskEnqueuer
+	embedding  llm.EmbeddingAdapter // Embeds semantic search queries; nil disables them
 }
 
-func NewNewsHandler(db *gorm.DB) *NewsHandler {
+func NewNewsHandler(db *gorm.DB, pdf *collector.PDFExtractor, taskClient worker.TaskEnqueuer) *NewsHandler {
 	return &NewsHandler{
-		repo: repository.NewNewsRepository(db),
+		repo:       repository.NewNewsRepository(db),
+		trendRepo:  repository.NewTrendingTopicRepository(db),
+		pdf:        pdf,
+		taskClient: taskClient,
 	}
 }
 
-// List returns a paginated list of news items
+// SetEmbeddingAdapter enables semantic news search with the adapter that embedded the news
+func (h *NewsHandler) SetEmbeddingAdapter(adapter llm.EmbeddingAdapter) {
+	h.embedding = adapter
+}
+
+// List godoc
+// @Summary List and search news items
+// @Description List news items, newest first, or search them. q matches titles, summaries and content; with semantic=true, items are instead ranked by embedding similarity to q, and only items embedded for story clustering (those of the last week at collection time) are searched. Items filtered for low relevance are left out unless filtered is given. needsReview=true lists crawled items whose extraction confidence is low or whose page was blocked; collapse=true lists one item per story, leaving out near-duplicates from other feeds.
+// @Tags news
+// @Produce json
+// @Param q query string false "Keyword, or the query of a semantic search"
+// @Param semantic query bool false "Rank by similarity to q instead of matching it"
+// @Param source query string false "Filter by source name"
+// @Param category query string false "Filter by category (tech, finance, product, company, regulation)"
+// @Param tag query string false "Filter by tags, matching all (comma-separated)"
+// @Param from query string false "Published on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Published before (RFC 3339 or YYYY-MM-DD, which includes that day)"
+// @Param processed query bool false "Filter by whether the item was summarized"
+// @Param needsReview query bool false "Only crawled items needing review"
+// @Param collapse query bool false "One item per story"
+// @Param filtered query string false "List items filtered for low relevance instead: quarantined or discarded"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/news [get]
 func (h *NewsHandler) List(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
@@ -40,7 +85,79 @@ func (h *NewsHandler) List(c *gin.Context) {
 		processed = &p
 	}
 
-	items, total, err := h.repo.List(page, limit, sourceName, processed)
+	filter := repository.NewsListFilter{
+		SourceName:  sourceName,
+		Processed:   processed,
+		NeedsReview: c.Query("needsReview") == "true",
+		Collapse:    c.Query("collapse") == "true",
+		Category:    c.Query("category"),
+		Tags:        queryList(c, "tag"),
+		Filtered:    c.Query("filtered"),
+	}
+	if filter.Filtered != "" && filter.Filtered != model.NewsFilteredQuarantine && filter.Filtered != model.NewsFilteredDiscard {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filtered, expected quarantined or discarded"})
+		return
+	}
+	for key, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			// A plain date covers the whole day
+			day, dayErr := time.Parse("2006-01-02", value)
+			if dayErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + key + ", expected RFC 3339 or YYYY-MM-DD"})
+				return
+			}
+			if key == "to" {
+				day = day.AddDate(0, 0, 1)
+			}
+			t = day
+		}
+		*target = &t
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if c.Query("semantic") == "true" {
+		h.searchSemantic(c, q, filter, page, limit)
+		return
+	}
+	filter.Search = q
+
+	items, total, err := h.repo.List(page, limit, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// searchSemantic writes the page of news items most similar to a query
+func (h *NewsHandler) searchSemantic(c *gin.Context, q string, filter repository.NewsListFilter, page, limit int) {
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required for semantic search"})
+		return
+	}
+	if h.embedding == nil || !h.embedding.IsAvailable() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "semantic search unavailable"})
+		return
+	}
+
+	vector, err := h.embedding.GenerateEmbedding(q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, total, err := h.repo.SearchSimilar(llm.Float32ToVector(vector), filter, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -54,6 +171,88 @@ func (h *NewsHandler) List(c *gin.Context) {
 	})
 }
 
+// ListStories godoc
+// @Summary List news stories
+// @Description List groups of near-duplicate news items from different feeds, most recently grown first. Each story has its canonical item (the first collected) and the source URLs of all its items.
+// @Tags news
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/news/stories [get]
+func (h *NewsHandler) ListStories(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	stories, total, err := h.repo.ListStories(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  stories,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// Trending godoc
+// @Summary List trending topics
+// @Description List the topics of recent news ranked by mentions, feed spread and velocity, as of the latest hourly analysis. A topic has the article generated from it, if any, and up to ten of its news items.
+// @Tags news
+// @Produce json
+// @Param limit query int false "Max topics" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/news/trending [get]
+func (h *NewsHandler) Trending(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	topics, err := h.trendRepo.List(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": topics})
+}
+
+// GetStory godoc
+// @Summary Get a news story
+// @Description Get a story with all its news items, the canonical item first
+// @Tags news
+// @Produce json
+// @Param id path string true "Story ID"
+// @Success 200 {object} model.NewsStory
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/news/stories/{id} [get]
+func (h *NewsHandler) GetStory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	story, err := h.repo.GetStory(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "story not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, story)
+}
+
 // Get returns a single news item
 func (h *NewsHandler) Get(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -103,6 +302,147 @@ func (h *NewsHandler) MarkProcessed(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "marked as processed"})
 }
 
+// RunPipeline godoc
+// @Summary Run the news-to-article pipeline on a news item
+// @Description Queue a news item for the pipeline: summarize it if needed, let the LLM decide whether it warrants a new article or an update to a related one, then write the new article for review and classify and embed it, or propose the update as a revision of the related article. Each step is recorded as a news_pipeline task. Works whether or not worker.news_pipeline.enabled is set.
+// @Tags news
+// @Produce json
+// @Param id path string true "News item ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/news/{id}/pipeline [post]
+func (h *NewsHandler) RunPipeline(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	item, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "news item not found"})
+		return
+	}
+	if item.PipelineDecision != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "news item already went through the pipeline", "decision": item.PipelineDecision, "articleId": item.ArticleID})
+		return
+	}
+
+	info, err := worker.EnqueueNewsPipeline(h.taskClient, worker.NewsPipelinePayload{NewsID: item.ID.String()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"taskId": info.ID, "queue": info.Queue})
+}
+
+// Release godoc
+// @Summary Release a quarantined news item
+// @Description Let a news item quarantined for low relevance through to summarization, keeping its score
+// @Tags news
+// @Produce json
+// @Param id path string true "News item ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/news/{id}/release [post]
+func (h *NewsHandler) Release(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	item, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "news item not found"})
+		return
+	}
+	if item.Filtered != model.NewsFilteredQuarantine {
+		c.JSON(http.StatusConflict, gin.H{"error": "news item is not quarantined", "filtered": item.Filtered})
+		return
+	}
+
+	if err := h.repo.Release(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	info, err := worker.EnqueueSummarize(h.taskClient, item.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "released", "taskId": info.ID})
+}
+
+// Translate godoc
+// @Summary Translate a news item in full
+// @Description Queue a full Chinese translation of a news item's title and content through the translation model route. The translation is stored alongside the original and replaces any earlier one.
+// @Tags news
+// @Produce json
+// @Param id path string true "News item ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/news/{id}/translate [post]
+func (h *NewsHandler) Translate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	item, err := h.repo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "news item not found"})
+		return
+	}
+	if strings.TrimSpace(item.Content) == "" || item.SourceLanguage == "zh" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "news item has no foreign-language content to translate"})
+		return
+	}
+
+	info, err := worker.EnqueueTranslate(h.taskClient, item.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"taskId": info.ID, "queue": info.Queue})
+}
+
+// GetTranslation godoc
+// @Summary Get the translation of a news item
+// @Description Get the full Chinese translation of a news item
+// @Tags news
+// @Produce json
+// @Param id path string true "News item ID"
+// @Success 200 {object} model.NewsTranslation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/news/{id}/translation [get]
+func (h *NewsHandler) GetTranslation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	translation, err := h.repo.FindTranslation(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "translation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
 // GetUnprocessed returns unprocessed news items
 func (h *NewsHandler) GetUnprocessed(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
@@ -121,3 +461,160 @@ func (h *NewsHandler) GetUnprocessed(c *gin.Context) {
 		"count": len(items),
 	})
 }
+
+// CrawlRequest is the body of a crawl request
+type CrawlRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	Depth      int      `json:"depth"`    // Link hops to follow within the URL's domain (at most 5)
+	Include    []string `json:"include"`  // Regexes; when set, followed URLs must match one
+	Exclude    []string `json:"exclude"`  // Regexes of URLs not to follow
+	MaxPages   int      `json:"maxPages"` // Page cap of a multi-page crawl (default 50, at most 500)
+	SourceName string   `json:"sourceName"`
+}
+
+// Crawl godoc
+// @Summary Crawl a page or site
+// @Description Queue a crawl of a URL into news items. With depth > 0 the crawler follows links within the URL's domain breadth first, filtered by the include/exclude regexes and capped at maxPages, so a whole docs site can be ingested in one task.
+// @Tags news
+// @Accept json
+// @Produce json
+// @Param request body CrawlRequest true "Crawl request"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /api/news/crawl [post]
+func (h *NewsHandler) Crawl(c *gin.Context) {
+	var req CrawlRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if parsed, err := url.Parse(req.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url must be an absolute http(s) URL"})
+		return
+	}
+	if _, err := collector.NewSiteCrawlScope(req.Depth, req.Include, req.Exclude, req.MaxPages); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	info, err := worker.EnqueueWebCrawl(h.taskClient, worker.WebCrawlPayload{
+		URL:        req.URL,
+		Depth:      req.Depth,
+		Include:    req.Include,
+		Exclude:    req.Exclude,
+		MaxPages:   req.MaxPages,
+		SourceName: req.SourceName,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"taskId": info.ID, "queue": info.Queue})
+}
+
+// UploadPDF godoc
+// @Summary Upload a PDF as a news item
+// @Description Extract the text of an uploaded PDF (whitepaper, audit report, paper) into markdown with a "## Page N" heading per page, store it as a news item and queue it for summarization. Uploading the same file twice returns the existing item. Needs collector.pdf_text_url.
+// @Tags news
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "PDF file"
+// @Param title formData string false "Title, instead of the PDF's own"
+// @Param sourceUrl formData string false "Where the PDF was published"
+// @Param sourceName formData string false "Source name (default: upload)"
+// @Success 201 {object} model.NewsItem
+// @Success 200 {object} model.NewsItem
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/news/pdf [post]
+func (h *NewsHandler) UploadPDF(c *gin.Context) {
+	if !h.pdf.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "PDF text extraction is not configured"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if strings.ToLower(path.Ext(file.Filename)) != ".pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only PDF files are supported"})
+		return
+	}
+
+	// Limit file size to 50MB
+	if file.Size > 50*1024*1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds 50MB limit"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	// Without a published URL the file's hash identifies it, so re-uploads are recognised
+	sourceURL := strings.TrimSpace(c.PostForm("sourceUrl"))
+	if sourceURL == "" {
+		sum := sha256.Sum256(data)
+		sourceURL = "upload:sha256:" + hex.EncodeToString(sum[:])
+	}
+	if existing, err := h.repo.FindBySourceURL(sourceURL); err == nil && existing != nil {
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+
+	doc, err := h.pdf.ExtractDocument(c.Request.Context(), data)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(strings.Join(doc.Pages, "")) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the PDF has no extractable text"})
+		return
+	}
+
+	title := strings.TrimSpace(c.PostForm("title"))
+	if title == "" {
+		title = doc.TitleOr(file.Filename)
+	}
+	sourceName := c.DefaultPostForm("sourceName", "upload")
+
+	item := &model.NewsItem{
+		Title:          title,
+		OriginalTitle:  title,
+		Content:        doc.Markdown(),
+		SourceURL:      sourceURL,
+		SourceName:     sourceName,
+		SourceLanguage: doc.Language,
+		Author:         doc.Author,
+		FetchedAt:      time.Now(),
+	}
+	created, err := h.repo.CreateOrIgnore(item)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !created {
+		// Uploaded concurrently
+		if existing, err := h.repo.FindBySourceURL(sourceURL); err == nil {
+			c.JSON(http.StatusOK, existing)
+			return
+		}
+	}
+
+	if _, err := worker.EnqueueSummarize(h.taskClient, item.ID.String()); err != nil {
+		log.Printf("Failed to enqueue summarize task for uploaded PDF %s: %v", item.ID, err)
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
+)
+
+type PrerequisiteHandler struct {
+	repo          *repository.ArticleRepository
+	prerequisites *service.PrerequisiteService
+}
+
+func NewPrerequisiteHandler(repo *repository.ArticleRepository, prerequisites *service.PrerequisiteService) *PrerequisiteHandler {
+	return &PrerequisiteHandler{repo: repo, prerequisites: prerequisites}
+}
+
+type PrerequisiteRequest struct {
+	PrerequisiteID uuid.UUID `json:"prerequisiteId" binding:"required"`
+	Reason         string    `json:"reason"`
+}
+
+type SetPrerequisitesRequest struct {
+	Prerequisites []PrerequisiteRequest `json:"prerequisites"`
+}
+
+// ListPrerequisites godoc
+// @Summary List article prerequisites
+// @Description Get the articles that should be read before this one
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticlePrerequisite
+// @Router /api/articles/{id}/prerequisites [get]
+func (h *PrerequisiteHandler) List(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	prerequisites, err := h.repo.ListPrerequisites(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prerequisites)
+}
+
+// AddPrerequisite godoc
+// @Summary Add an article prerequisite
+// @Description Mark another article as required reading before this one
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param request body PrerequisiteRequest true "Prerequisite"
+// @Success 201 {object} model.ArticlePrerequisite
+// @Failure 409 {object} map[string]string "Would create a cycle"
+// @Router /api/articles/{id}/prerequisites [post]
+func (h *PrerequisiteHandler) Add(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req PrerequisiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !articlesExist(c, h.repo, id, req.PrerequisiteID) {
+		return
+	}
+
+	prerequisite := &model.ArticlePrerequisite{
+		ArticleID:      id,
+		PrerequisiteID: req.PrerequisiteID,
+		Source:         model.PrerequisiteSourceManual,
+		Confidence:     1,
+		Reason:         req.Reason,
+	}
+	if err := h.repo.AddPrerequisite(prerequisite); err != nil {
+		if errors.Is(err, repository.ErrPrerequisiteCycle) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, prerequisite)
+}
+
+// SetPrerequisites godoc
+// @Summary Replace manual article prerequisites
+// @Description Replace the manually set prerequisites of an article; LLM suggestions are kept
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param request body SetPrerequisitesRequest true "Prerequisites"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/articles/{id}/prerequisites [put]
+func (h *PrerequisiteHandler) Set(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req SetPrerequisitesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ids := []uuid.UUID{id}
+	prerequisites := make([]model.ArticlePrerequisite, 0, len(req.Prerequisites))
+	for _, p := range req.Prerequisites {
+		ids = append(ids, p.PrerequisiteID)
+		prerequisites = append(prerequisites, model.ArticlePrerequisite{
+			PrerequisiteID: p.PrerequisiteID,
+			Confidence:     1,
+			Reason:         p.Reason,
+		})
+	}
+	if !articlesExist(c, h.repo, ids...) {
+		return
+	}
+
+	skipped, err := h.repo.SetPrerequisites(id, model.PrerequisiteSourceManual, prerequisites)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current, err := h.repo.ListPrerequisites(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prerequisites": current,
+		"skipped":       skipped,
+	})
+}
+
+// RemovePrerequisite godoc
+// @Summary Remove an article prerequisite
+// @Tags articles
+// @Param id path string true "Article ID"
+// @Param prerequisiteId path string true "Prerequisite article ID"
+// @Success 204
+// @Router /api/articles/{id}/prerequisites/{prerequisiteId} [delete]
+func (h *PrerequisiteHandler) Remove(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	prerequisiteID, err := uuid.Parse(c.Param("prerequisiteId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prerequisite id"})
+		return
+	}
+
+	if err := h.repo.RemovePrerequisite(id, prerequisiteID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SuggestPrerequisites godoc
+// @Summary Suggest article prerequisites
+// @Description Ask the LLM which related articles should be read first; replaces earlier suggestions
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticlePrerequisite
+// @Router /api/articles/{id}/prerequisites/suggest [post]
+func (h *PrerequisiteHandler) Suggest(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if !articlesExist(c, h.repo, id) {
+		return
+	}
+
+	prerequisites, err := h.prerequisites.SuggestAndUpdate(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prerequisites)
+}
+
+// LearningPath godoc
+// @Summary Get a learning path
+// @Description Order articles so each comes after its prerequisites, easier articles first
+// @Tags articles
+// @Produce json
+// @Param ids query string false "Article IDs (comma-separated)"
+// @Param category_id query string false "Include articles of a category"
+// @Param difficulty query string false "Filter by difficulty (comma-separated)"
+// @Param include_prerequisites query bool false "Add missing prerequisites (default: true)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/articles/learning-path [get]
+func (h *PrerequisiteHandler) LearningPath(c *gin.Context) {
+	var ids []uuid.UUID
+	for _, raw := range queryList(c, "ids") {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ids"})
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	var categoryID *uuid.UUID
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+			return
+		}
+		categoryID = &id
+	}
+
+	if len(ids) == 0 && categoryID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids or category_id is required"})
+		return
+	}
+
+	difficulties, ok := queryDifficulties(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty"})
+		return
+	}
+
+	includePrerequisites := true
+	if raw := c.Query("include_prerequisites"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include_prerequisites"})
+			return
+		}
+		includePrerequisites = parsed
+	}
+
+	articles, err := h.prerequisites.LearningPath(ids, categoryID, difficulties, includePrerequisites)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles": articles,
+		"total":    len(articles),
+	})
+}
+
+// articlesExist responds 404 and returns false if any of the articles is missing
+func articlesExist(c *gin.Context, repo *repository.ArticleRepository, ids ...uuid.UUID) bool {
+	for _, id := range ids {
+		if _, err := repo.GetByID(id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return false
+		}
+	}
+	return true
+}
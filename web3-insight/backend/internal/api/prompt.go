@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/web3-insight/internal/service"
+)
+
+type PromptHandler struct {
+	prompts *service.PromptStore
+}
+
+func NewPromptHandler(prompts *service.PromptStore) *PromptHandler {
+	return &PromptHandler{prompts: prompts}
+}
+
+// UpdatePromptRequest represents the request body for overriding a prompt
+type UpdatePromptRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// List godoc
+// @Summary List persona prompts
+// @Description Get the chat and generation prompts of a persona, with the active persona and all known personas
+// @Tags prompts
+// @Produce json
+// @Param persona query string false "Persona (default: the configured llm.persona)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/prompts [get]
+func (h *PromptHandler) List(c *gin.Context) {
+	persona := h.persona(c)
+	prompts, err := h.prompts.List(persona)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	personas, err := h.prompts.Personas()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active":   h.prompts.Persona(),
+		"persona":  persona,
+		"personas": personas,
+		"prompts":  prompts,
+	})
+}
+
+// Get godoc
+// @Summary Get a persona prompt
+// @Tags prompts
+// @Produce json
+// @Param name path string true "Prompt name, e.g. chat_general"
+// @Param persona query string false "Persona (default: the configured llm.persona)"
+// @Success 200 {object} service.PromptInfo
+// @Failure 404 {object} map[string]string
+// @Router /api/prompts/{name} [get]
+func (h *PromptHandler) Get(c *gin.Context) {
+	info, err := h.prompts.Resolve(h.persona(c), c.Param("name"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// Update godoc
+// @Summary Override a persona prompt
+// @Description Save a persona's own text for a prompt. It must keep the built-in prompt's %s placeholders.
+// @Description Overrides for a persona without built-in prompts define a new persona.
+// @Tags prompts
+// @Accept json
+// @Produce json
+// @Param name path string true "Prompt name, e.g. chat_general"
+// @Param persona query string false "Persona (default: the configured llm.persona)"
+// @Param request body UpdatePromptRequest true "Prompt content"
+// @Success 200 {object} service.PromptInfo
+// @Router /api/prompts/{name} [put]
+func (h *PromptHandler) Update(c *gin.Context) {
+	var req UpdatePromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	info, err := h.prompts.Set(h.persona(c), c.Param("name"), req.Content)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// Reset godoc
+// @Summary Reset a persona prompt
+// @Description Remove a persona's override of a prompt, restoring the built-in text
+// @Tags prompts
+// @Produce json
+// @Param name path string true "Prompt name, e.g. chat_general"
+// @Param persona query string false "Persona (default: the configured llm.persona)"
+// @Success 200 {object} service.PromptInfo
+// @Router /api/prompts/{name} [delete]
+func (h *PromptHandler) Reset(c *gin.Context) {
+	info, err := h.prompts.Reset(h.persona(c), c.Param("name"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// persona returns the persona query parameter, defaulting to the active persona
+func (h *PromptHandler) persona(c *gin.Context) string {
+	if persona := c.Query("persona"); persona != "" {
+		return persona
+	}
+	return h.prompts.Persona()
+}
+
+// respondError maps prompt store errors to status codes
+func (h *PromptHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownPrompt):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidPrompt):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+type RelationHandler struct {
+	repo *repository.ArticleRepository
+}
+
+func NewRelationHandler(repo *repository.ArticleRepository) *RelationHandler {
+	return &RelationHandler{repo: repo}
+}
+
+type RelationRequest struct {
+	Type      string     `json:"type" binding:"required"` // series or deep_dive
+	RelatedID *uuid.UUID `json:"relatedId"`               // deep_dive: the article this one goes deeper into
+	Series    string     `json:"series"`                  // series: name of the series
+	Position  int        `json:"position"`                // series: order within the series; appended when 0
+}
+
+// ListRelations godoc
+// @Summary List article relations
+// @Description Get the series memberships and deep-dive links of an article, in either direction
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticleRelation
+// @Router /api/articles/{id}/relations [get]
+func (h *RelationHandler) List(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	relations, err := h.repo.ListRelations(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+// AddRelation godoc
+// @Summary Add an article relation
+// @Description Add the article to a series, or mark it as a deep dive into another article. Adding the article to a series it is already in moves it. Prerequisites have their own endpoints.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param request body RelationRequest true "Relation"
+// @Success 201 {object} model.ArticleRelation
+// @Router /api/articles/{id}/relations [post]
+func (h *RelationHandler) Add(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req RelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	relation := &model.ArticleRelation{ArticleID: id}
+	if !h.applyRequest(c, relation, req) {
+		return
+	}
+	if err := h.repo.SaveRelation(relation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, relation)
+}
+
+// UpdateRelation godoc
+// @Summary Update an article relation
+// @Description Change the type, series, position or related article of a relation
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param relationId path string true "Relation ID"
+// @Param request body RelationRequest true "Relation"
+// @Success 200 {object} model.ArticleRelation
+// @Router /api/articles/{id}/relations/{relationId} [put]
+func (h *RelationHandler) Update(c *gin.Context) {
+	relation, ok := h.findRelation(c)
+	if !ok {
+		return
+	}
+
+	var req RelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.applyRequest(c, relation, req) {
+		return
+	}
+	if err := h.repo.SaveRelation(relation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, relation)
+}
+
+// RemoveRelation godoc
+// @Summary Remove an article relation
+// @Tags articles
+// @Param id path string true "Article ID"
+// @Param relationId path string true "Relation ID"
+// @Success 204
+// @Router /api/articles/{id}/relations/{relationId} [delete]
+func (h *RelationHandler) Remove(c *gin.Context) {
+	relation, ok := h.findRelation(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteRelation(relation.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSeries godoc
+// @Summary Get an article series
+// @Description Get the articles of a series in reading order
+// @Tags articles
+// @Produce json
+// @Param name path string true "Series name"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/articles/series/{name} [get]
+func (h *RelationHandler) GetSeries(c *gin.Context) {
+	name := strings.TrimSpace(c.Param("name"))
+
+	members, err := h.repo.ListSeries(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(members) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "series not found"})
+		return
+	}
+
+	articles := make([]*model.Article, 0, len(members))
+	for _, member := range members {
+		articles = append(articles, member.Article)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"series":   name,
+		"articles": articles,
+		"total":    len(articles),
+	})
+}
+
+// applyRequest validates a relation request and copies it onto the relation, responding
+// with an error and returning false if it is invalid
+func (h *RelationHandler) applyRequest(c *gin.Context, relation *model.ArticleRelation, req RelationRequest) bool {
+	relation.Type = req.Type
+	switch req.Type {
+	case model.RelationTypeSeries:
+		series := strings.TrimSpace(req.Series)
+		if series == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "series is required"})
+			return false
+		}
+		if req.Position < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid position"})
+			return false
+		}
+		relation.Series, relation.Position, relation.RelatedID = series, req.Position, nil
+		return articlesExist(c, h.repo, relation.ArticleID)
+	case model.RelationTypeDeepDive:
+		if req.RelatedID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "relatedId is required"})
+			return false
+		}
+		if *req.RelatedID == relation.ArticleID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "an article cannot be a deep dive into itself"})
+			return false
+		}
+		relation.RelatedID, relation.Series, relation.Position = req.RelatedID, "", 0
+		return articlesExist(c, h.repo, relation.ArticleID, *req.RelatedID)
+	case "prerequisite":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prerequisites are managed with /api/articles/{id}/prerequisites"})
+		return false
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid relation type"})
+		return false
+	}
+}
+
+// findRelation loads the relation named by the request path, responding with an error if
+// it does not exist or belongs to another article
+func (h *RelationHandler) findRelation(c *gin.Context) (*model.ArticleRelation, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	relationID, err := uuid.Parse(c.Param("relationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid relation id"})
+		return nil, false
+	}
+
+	relation, err := h.repo.GetRelation(relationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "relation not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return nil, false
+	}
+	if relation.ArticleID != id {
+		c.JSON(http.StatusNotFound, gin.H{"error": "relation not found"})
+		return nil, false
+	}
+	return relation, true
+}
@@ -0,0 +1,377 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+)
+
+type ResearchHandler struct {
+	researchService *service.ResearchService
+	sessionRepo     *repository.ResearchSessionRepository
+	scheduleRepo    *repository.ResearchScheduleRepository
+}
+
+func NewResearchHandler(
+	researchService *service.ResearchService,
+	sessionRepo *repository.ResearchSessionRepository,
+	scheduleRepo *repository.ResearchScheduleRepository,
+) *ResearchHandler {
+	return &ResearchHandler{
+		researchService: researchService,
+		sessionRepo:     sessionRepo,
+		scheduleRepo:    scheduleRepo,
+	}
+}
+
+// ResearchRequest represents the request body for instant research
+type ResearchRequest struct {
+	Query        string `json:"query" binding:"required"`
+	SaveArticle  bool   `json:"saveArticle"`
+	UseWebSearch bool   `json:"useWebSearch"`
+	CitationMode string `json:"citationMode" binding:"omitempty,oneof=flag strip off"`
+}
+
+// Research godoc
+// @Summary Run instant research
+// @Tags research
+// @Accept json
+// @Produce json
+// @Param request body ResearchRequest true "Research request"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/research [post]
+func (h *ResearchHandler) Research(c *gin.Context) {
+	var req ResearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.researchService.Research(c.Request.Context(), &service.ResearchRequest{
+		Query:        req.Query,
+		SaveArticle:  req.SaveArticle,
+		UseWebSearch: req.UseWebSearch,
+		CitationMode: req.CitationMode,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, researchResponseJSON(resp))
+}
+
+// ListSessions godoc
+// @Summary List research sessions
+// @Tags research
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param search query string false "Search in query text"
+// @Param scheduleId query string false "Filter by research schedule"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} repository.ResearchSessionListResult
+// @Router /api/research/sessions [get]
+func (h *ResearchHandler) ListSessions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	params := repository.ResearchSessionListParams{
+		Status: c.Query("status"),
+		Search: c.Query("search"),
+		Page:   page,
+		Limit:  limit,
+	}
+	if scheduleID := c.Query("scheduleId"); scheduleID != "" {
+		id, err := uuid.Parse(scheduleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+			return
+		}
+		params.ScheduleID = &id
+	}
+
+	result, err := h.sessionRepo.List(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSession godoc
+// @Summary Get a research session
+// @Tags research
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} model.ResearchSession
+// @Router /api/research/sessions/{id} [get]
+func (h *ResearchHandler) GetSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "research session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// RerunSession godoc
+// @Summary Re-run a research session with its original parameters
+// @Tags research
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/research/sessions/{id}/rerun [post]
+func (h *ResearchHandler) RerunSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	if _, err := h.sessionRepo.GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "research session not found"})
+		return
+	}
+
+	resp, err := h.researchService.RerunSession(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, researchResponseJSON(resp))
+}
+
+// ResearchScheduleRequest represents the request body for creating or updating a research schedule
+type ResearchScheduleRequest struct {
+	Name            string     `json:"name" binding:"required"`
+	Query           string     `json:"query" binding:"required"`
+	CronExpr        string     `json:"cronExpr" binding:"required"`
+	UseWebSearch    *bool      `json:"useWebSearch"`
+	CitationMode    string     `json:"citationMode" binding:"omitempty,oneof=flag strip off"`
+	TargetArticleID *uuid.UUID `json:"targetArticleId"`
+	Enabled         *bool      `json:"enabled"`
+}
+
+// ListSchedules godoc
+// @Summary List research schedules
+// @Tags research
+// @Produce json
+// @Success 200 {array} model.ResearchSchedule
+// @Router /api/research/schedules [get]
+func (h *ResearchHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.scheduleRepo.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetSchedule godoc
+// @Summary Get a research schedule
+// @Tags research
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} model.ResearchSchedule
+// @Router /api/research/schedules/{id} [get]
+func (h *ResearchHandler) GetSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	schedule, err := h.scheduleRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "research schedule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring research schedule
+// @Tags research
+// @Accept json
+// @Produce json
+// @Param request body ResearchScheduleRequest true "Schedule"
+// @Success 201 {object} model.ResearchSchedule
+// @Router /api/research/schedules [post]
+func (h *ResearchHandler) CreateSchedule(c *gin.Context) {
+	var req ResearchScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := &model.ResearchSchedule{
+		UseWebSearch: true,
+		Enabled:      true,
+	}
+	if err := applyScheduleRequest(schedule, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.scheduleRepo.Create(schedule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary Update a research schedule
+// @Tags research
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param request body ResearchScheduleRequest true "Schedule"
+// @Success 200 {object} model.ResearchSchedule
+// @Router /api/research/schedules/{id} [put]
+func (h *ResearchHandler) UpdateSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	schedule, err := h.scheduleRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "research schedule not found"})
+		return
+	}
+
+	var req ResearchScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := applyScheduleRequest(schedule, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.scheduleRepo.Update(schedule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a research schedule
+// @Tags research
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} map[string]string
+// @Router /api/research/schedules/{id} [delete]
+func (h *ResearchHandler) DeleteSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	if err := h.scheduleRepo.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// RunSchedule godoc
+// @Summary Run a research schedule immediately
+// @Tags research
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/research/schedules/{id}/run [post]
+func (h *ResearchHandler) RunSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	schedule, err := h.scheduleRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "research schedule not found"})
+		return
+	}
+
+	resp, runErr := h.researchService.RunSchedule(c.Request.Context(), schedule)
+	var sessionID *uuid.UUID
+	if resp != nil {
+		sessionID = resp.SessionID
+	}
+	if err := h.scheduleRepo.RecordRun(schedule.ID, sessionID, runErr); err != nil {
+		log.Printf("Failed to record run for research schedule %s: %v", schedule.ID, err)
+	}
+
+	if runErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": runErr.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, researchResponseJSON(resp))
+}
+
+// scheduleParser accepts only five-field cron expressions; descriptors such as
+// @every or @hourly are rejected so schedules stay readable in the UI
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// applyScheduleRequest validates the request and copies it onto the schedule
+func applyScheduleRequest(schedule *model.ResearchSchedule, req *ResearchScheduleRequest) error {
+	if _, err := scheduleParser.Parse(req.CronExpr); err != nil {
+		return fmt.Errorf("invalid cron expression (expected five fields: minute hour day month weekday): %w", err)
+	}
+
+	schedule.Name = req.Name
+	schedule.Query = req.Query
+	schedule.CronExpr = req.CronExpr
+	schedule.CitationMode = req.CitationMode
+	schedule.TargetArticleID = req.TargetArticleID
+	if req.UseWebSearch != nil {
+		schedule.UseWebSearch = *req.UseWebSearch
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	return nil
+}
+
+// researchResponseJSON converts a research response to its API representation
+func researchResponseJSON(resp *service.ResearchResponse) gin.H {
+	return gin.H{
+		"sessionId":       resp.SessionID,
+		"content":         resp.Content,
+		"modelUsed":       resp.ModelUsed,
+		"sources":         resp.Sources,
+		"relatedArticles": resp.RelatedArticles,
+		"savedArticleId":  resp.SavedArticleID,
+		"citations":       resp.Citations,
+		"durationMs":      resp.Duration.Milliseconds(),
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ReviewRequest struct {
+	Status    string     `json:"status" binding:"required"` // Status to move the article to
+	Reviewer  string     `json:"reviewer"`
+	Note      string     `json:"note"`      // Required when requesting changes
+	PublishAt *time.Time `json:"publishAt"` // Required when scheduling an approved article
+}
+
+// ReviewArticle godoc
+// @Summary Move an article through review
+// @Description Submit an article for review (in_review), request changes (changes_requested), approve it (approved), or publish or schedule an approved article. A draft must be reviewed and approved before it is published this way.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param request body ReviewRequest true "Transition"
+// @Success 200 {object} model.Article
+// @Router /api/articles/{id}/review [post]
+func (h *ArticleHandler) Review(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !model.IsValidArticleStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	note := strings.TrimSpace(req.Note)
+	if req.Status == model.ArticleStatusChangesRequested && note == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a note is required when requesting changes"})
+		return
+	}
+
+	article, err := h.repo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		return
+	}
+	wasScheduled := article.Status == model.ArticleStatusScheduled
+
+	article.Status = req.Status
+	if req.Status != model.ArticleStatusScheduled {
+		article.PublishAt = nil
+	}
+	if !applySchedule(c, article, req.Status, req.PublishAt) {
+		return
+	}
+
+	review := &model.ArticleReview{
+		ToStatus: req.Status,
+		Reviewer: strings.TrimSpace(req.Reviewer),
+		Note:     note,
+	}
+	if err := h.repo.TransitionStatus(article, review); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	// Scheduled articles skipped the post-create pipeline; publishing early starts it
+	if wasScheduled && article.Status == model.ArticleStatusPublished {
+		h.hooks.AfterPublish(article)
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// ListReviews godoc
+// @Summary List article reviews
+// @Description Get the review history of an article with the reviewers' notes, oldest first
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.ArticleReview
+// @Router /api/articles/{id}/reviews [get]
+func (h *ArticleHandler) ListReviews(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	reviews, err := h.repo.ListReviews(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
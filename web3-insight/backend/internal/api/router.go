@@ -1,24 +1,54 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/cache"
+	"github.com/user/web3-insight/internal/collector"
 	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/metrics"
 	"github.com/user/web3-insight/internal/repository"
 	"github.com/user/web3-insight/internal/service"
+	"github.com/user/web3-insight/internal/wiki"
+	"github.com/user/web3-insight/internal/worker"
 	"gorm.io/gorm"
 )
 
 type Server struct {
-	config          *config.Config
-	db              *gorm.DB
-	articleHandler  *ArticleHandler
-	categoryHandler *CategoryHandler
-	configHandler   *ConfigHandler
-	taskHandler     *TaskHandler
-	searchHandler   *SearchHandler
-	chatHandler     *ChatHandler
+	config              *config.Config
+	db                  *gorm.DB
+	articleHandler      *ArticleHandler
+	categoryHandler     *CategoryHandler
+	configHandler       *ConfigHandler
+	taskHandler         *TaskHandler
+	searchHandler       *SearchHandler
+	chatHandler         *ChatHandler
+	researchHandler     *ResearchHandler
+	llmHandler          *LLMHandler
+	adminHandler        *AdminHandler
+	experimentHandler   *ExperimentHandler
+	prerequisiteHandler *PrerequisiteHandler
+	relationHandler     *RelationHandler
+	tagHandler          *TagHandler
+	termHandler         *TermHandler
+	graphHandler        *GraphHandler
+	feedHandler         *FeedHandler
+	exportHandler       *ExportHandler
+	changeHandler       *ChangeHandler
+	wikiHandler         *WikiHandler
+	promptHandler       *PromptHandler
+	crawlRuleHandler    *CrawlRuleHandler
+	pipelineRepo        *repository.PipelineRepository
+	taskClient          worker.TaskEnqueuer
+	articleHooks        *service.ArticleHooks
+	sourceDiscovery     *service.SourceDiscoveryService
+	sharedCache         *cache.Cache
 }
 
 func NewServer(cfg *config.Config, db *gorm.DB) *Server {
@@ -27,25 +57,135 @@ func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 	categoryRepo := repository.NewCategoryRepository(db)
 	configRepo := repository.NewConfigRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
+	newsRepo := repository.NewNewsRepository(db)
+	researchSessionRepo := repository.NewResearchSessionRepository(db)
+	researchScheduleRepo := repository.NewResearchScheduleRepository(db)
+	pipelineRepo := repository.NewPipelineRepository(db)
+	experimentRepo := repository.NewExperimentRepository(db)
+	llmCallRepo := repository.NewLLMCallRepository(db)
+
+	redisOpt := asynq.RedisClientOpt{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	}
+	var sharedCache *cache.Cache
+	var taskClient worker.TaskEnqueuer
+	var queueMonitor *worker.QueueMonitor
+	if cfg.Worker.Local() {
+		// Safe mode without Redis: tasks run in-process on the worker handlers
+		executor := worker.NewLocalExecutor(cfg.Worker.Concurrency, cfg.Worker.LocalQueueSize)
+		worker.InitTaskClient(executor)
+		worker.InitWorkerDependencies(db, cfg)
+		taskClient = executor
+		scheduler := worker.NewLocalScheduler(executor)
+		if err := scheduler.RegisterTasks(); err != nil {
+			log.Printf("Failed to register periodic tasks: %v", err)
+		} else if err := scheduler.Start(); err != nil {
+			log.Printf("Failed to start periodic tasks: %v", err)
+		}
+	} else {
+		// Category and config reads are shared across instances; writes anywhere invalidate them
+		sharedCache = cache.NewFromConfig(&cfg.Redis)
+		go sharedCache.Listen(context.Background())
+		taskClient = asynq.NewClient(redisOpt)
+		queueMonitor = worker.NewQueueMonitor(redisOpt, cfg.Worker.Autoscale)
+	}
+	categoryRepo.SetCache(sharedCache)
+	configRepo.SetCache(sharedCache)
+	articleHooks := service.NewArticleHooks(worker.NewArticleTaskEnqueuer(taskClient))
 
 	// Initialize services
-	chatService := service.NewChatService(db, &cfg.LLM)
+	callLogger := service.NewLLMCallLoggerFromConfig(llmCallRepo, &cfg.LLM.Audit)
+	prompts := service.NewPromptStore(repository.NewPromptTemplateRepository(db), cfg.LLM.Persona)
 	semanticSearchService := service.NewSemanticSearchService(articleRepo, &cfg.LLM)
 
+	llmRouter := llm.NewRouterFromConfig(&cfg.LLM)
+	llmRouter.SetCache(llm.NewResponseCacheFromConfig(&cfg.LLM.Cache, &cfg.Redis))
+	llmRouter.SetBudget(llm.NewBudgetTrackerFromConfig(&cfg.LLM.Budget, &cfg.Redis))
+	llmRouter.SetCallLogger(callLogger)
+	chatService := service.NewChatService(llmRouter, db, &cfg.LLM)
+	chatService.SetPromptStore(prompts)
+	searchRouter := collector.NewSearchRouter(
+		collector.NewTavilyProvider(cfg.Search.Tavily.APIKey, cfg.Search.Tavily.Enabled),
+		collector.NewSerpAPIProvider(cfg.Search.SerpAPI.APIKey, cfg.Search.SerpAPI.Enabled),
+	)
+	usageRecorder := service.NewUsageRecorder(taskRepo)
+	classifier := service.NewClassifier(llmRouter, articleRepo, categoryRepo)
+	classifier.SetUsageRecorder(usageRecorder)
+	generator := service.NewGenerator(llmRouter, articleRepo, newsRepo, classifier)
+	generator.SetUsageRecorder(usageRecorder)
+	generator.SetArticleHooks(articleHooks)
+	experiments := service.NewExperimentService(experimentRepo)
+	generator.SetExperiments(experiments)
+	generator.SetPromptStore(prompts)
+	researchService := service.NewResearchService(llmRouter, articleRepo, searchRouter, generator, researchSessionRepo)
+	researchService.SetUsageRecorder(usageRecorder)
+	researchService.SetArticleHooks(articleHooks)
+	researchService.SetPromptStore(prompts)
+	dsRepo := repository.NewDataSourceRepository(db)
+	sourceDiscovery := service.NewSourceDiscoveryService(llmRouter, searchRouter, collector.NewRSSCollector(newsRepo, dsRepo, nil), dsRepo)
+	sourceDiscovery.SetUsageRecorder(usageRecorder)
+	prerequisiteService := service.NewPrerequisiteService(llmRouter, articleRepo)
+	prerequisiteService.SetUsageRecorder(usageRecorder)
+	seoService := service.NewSEOService(llmRouter, articleRepo)
+	seoService.SetUsageRecorder(usageRecorder)
+	glossaryService := service.NewGlossaryService(llmRouter, articleRepo, repository.NewTermRepository(db))
+	glossaryService.SetUsageRecorder(usageRecorder)
+	conceptLinkService := service.NewConceptLinkService(llmRouter, articleRepo)
+	conceptLinkService.SetUsageRecorder(usageRecorder)
+	categoryEnricher := service.NewCategoryEnricher(llmRouter, categoryRepo, articleRepo)
+	categoryEnricher.SetUsageRecorder(usageRecorder)
+	categoryHandler := NewCategoryHandler(categoryRepo)
+	categoryHandler.SetEnricher(categoryEnricher)
+	categoryHandler.SetDeduper(service.NewCategoryDeduper(categoryRepo, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM)))
+	viewCounter := service.NewDirectViewCounter(articleRepo)
+	if !cfg.Worker.Local() {
+		viewCounter = service.NewViewCounterFromConfig(&cfg.Redis, articleRepo)
+	}
+	articleHandler := NewArticleHandler(articleRepo, articleHooks, viewCounter)
+	articleHandler.SetSEOService(seoService)
+	wikiPageRepo := repository.NewWikiPageRepository(db)
+	wikiExport := service.NewWikiExportService(articleRepo, categoryRepo, wikiPageRepo, wiki.NewPublishersFromConfig(&cfg.Wiki))
+	searchHandler := NewSearchHandlerWithSemantic(articleRepo, categoryRepo, semanticSearchService)
+	searchHandler.SetSearchIndexer(service.NewSearchIndexerFromConfig(&cfg.Search.Engine, db))
+	searchHandler.SetSearchLogs(repository.NewSearchLogRepository(db))
+
 	return &Server{
-		config:          cfg,
-		db:              db,
-		articleHandler:  NewArticleHandler(articleRepo),
-		categoryHandler: NewCategoryHandler(categoryRepo),
-		configHandler:   NewConfigHandler(configRepo),
-		taskHandler:     NewTaskHandler(taskRepo),
-		searchHandler:   NewSearchHandlerWithSemantic(articleRepo, categoryRepo, semanticSearchService),
-		chatHandler:     NewChatHandler(chatService),
+		config:              cfg,
+		db:                  db,
+		articleHandler:      articleHandler,
+		categoryHandler:     categoryHandler,
+		configHandler:       NewConfigHandler(configRepo),
+		taskHandler:         NewTaskHandler(taskRepo),
+		searchHandler:       searchHandler,
+		chatHandler:         NewChatHandler(chatService, cfg.Server.ChatLimits),
+		researchHandler:     NewResearchHandler(researchService, researchSessionRepo, researchScheduleRepo),
+		llmHandler:          NewLLMHandler(llmRouter, llmCallRepo),
+		adminHandler:        NewAdminHandler(pipelineRepo, queueMonitor, repository.NewConsistencyReportRepository(db), taskRepo, taskClient),
+		experimentHandler:   NewExperimentHandler(experimentRepo, experiments),
+		prerequisiteHandler: NewPrerequisiteHandler(articleRepo, prerequisiteService),
+		relationHandler:     NewRelationHandler(articleRepo),
+		tagHandler:          NewTagHandler(repository.NewTagRepository(db)),
+		termHandler:         NewTermHandler(repository.NewTermRepository(db), articleRepo, glossaryService),
+		graphHandler:        NewGraphHandler(articleRepo, conceptLinkService),
+		feedHandler:         NewFeedHandler(articleRepo, categoryRepo, cfg.Server.PublicURL),
+		exportHandler:       NewExportHandler(service.NewSiteExporter(articleRepo, categoryRepo, cfg.Server.PublicURL, feedTitle), service.NewDocumentExporter(articleRepo, categoryRepo, cfg.Export.PDFRendererURL)),
+		changeHandler:       NewChangeHandler(repository.NewChangeRepository(db)),
+		wikiHandler:         NewWikiHandler(wikiPageRepo, wikiExport, taskClient),
+		promptHandler:       NewPromptHandler(prompts),
+		crawlRuleHandler:    NewCrawlRuleHandler(repository.NewCrawlRuleRepository(db)),
+		pipelineRepo:        pipelineRepo,
+		taskClient:          taskClient,
+		articleHooks:        articleHooks,
+		sourceDiscovery:     sourceDiscovery,
+		sharedCache:         sharedCache,
 	}
 }
 
 func NewRouter(cfg *config.Config) *gin.Engine {
 	router := gin.Default()
+	trustProxies(router, cfg)
 
 	// CORS middleware
 	router.Use(corsMiddleware())
@@ -62,6 +202,7 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 
 func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	router := gin.Default()
+	trustProxies(router, cfg)
 
 	// CORS middleware
 	router.Use(corsMiddleware())
@@ -76,6 +217,13 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		})
 	})
 
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.NewHandler(server.pipelineRepo)))
+
+	// Atom feeds of published articles
+	router.GET("/feeds.xml", server.feedHandler.Global)
+	router.GET("/feeds/:file", server.feedHandler.Category)
+
 	// API routes
 	api := router.Group("/api")
 	{
@@ -83,11 +231,37 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		articles := api.Group("/articles")
 		{
 			articles.GET("", server.articleHandler.List)
+			articles.GET("/learning-path", server.prerequisiteHandler.LearningPath)
+			articles.GET("/series/:name", server.relationHandler.GetSeries)
 			articles.GET("/:id", server.articleHandler.Get)
 			articles.POST("", server.articleHandler.Create)
 			articles.PUT("/:id", server.articleHandler.Update)
 			articles.DELETE("/:id", server.articleHandler.Delete)
 			articles.POST("/:id/regenerate", server.articleHandler.Regenerate)
+			articles.POST("/:id/seo/generate", server.articleHandler.GenerateSEO)
+			articles.POST("/:id/review", server.articleHandler.Review)
+			articles.GET("/:id/reviews", server.articleHandler.ListReviews)
+			articles.GET("/:id/versions", server.articleHandler.ListVersions)
+			articles.GET("/:id/versions/:version", server.articleHandler.GetVersion)
+			articles.POST("/:id/versions/:version/restore", server.articleHandler.RestoreVersion)
+			articles.GET("/:id/revisions", server.articleHandler.ListRevisions)
+			articles.POST("/:id/revisions/:revisionId/apply", server.articleHandler.ApplyRevision)
+			articles.POST("/:id/revisions/:revisionId/reject", server.articleHandler.RejectRevision)
+			articles.GET("/:id/prerequisites", server.prerequisiteHandler.List)
+			articles.POST("/:id/prerequisites", server.prerequisiteHandler.Add)
+			articles.PUT("/:id/prerequisites", server.prerequisiteHandler.Set)
+			articles.POST("/:id/prerequisites/suggest", server.prerequisiteHandler.Suggest)
+			articles.DELETE("/:id/prerequisites/:prerequisiteId", server.prerequisiteHandler.Remove)
+			articles.GET("/:id/relations", server.relationHandler.List)
+			articles.POST("/:id/relations", server.relationHandler.Add)
+			articles.PUT("/:id/relations/:relationId", server.relationHandler.Update)
+			articles.DELETE("/:id/relations/:relationId", server.relationHandler.Remove)
+			articles.GET("/:id/links", server.graphHandler.ListLinks)
+			articles.POST("/:id/links", server.graphHandler.AddLink)
+			articles.POST("/:id/links/extract", server.graphHandler.ExtractLinks)
+			articles.DELETE("/:id/links/:linkId", server.graphHandler.RemoveLink)
+			articles.POST("/:id/terms/extract", server.termHandler.Extract)
+			articles.GET("/:id/export", server.exportHandler.Article)
 		}
 
 		// Categories
@@ -95,15 +269,61 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		{
 			categories.GET("", server.categoryHandler.List)
 			categories.GET("/tree", server.categoryHandler.GetTree)
+			categories.PUT("/reorder", server.categoryHandler.Reorder)
+			categories.GET("/duplicates", server.categoryHandler.ListDuplicates)
+			categories.POST("/duplicates/detect", server.categoryHandler.DetectDuplicates)
+			categories.POST("/duplicates/:id/merge", server.categoryHandler.MergeDuplicate)
+			categories.POST("/duplicates/:id/dismiss", server.categoryHandler.DismissDuplicate)
 			categories.GET("/:id", server.categoryHandler.Get)
 			categories.POST("", server.categoryHandler.Create)
 			categories.PUT("/:id", server.categoryHandler.Update)
 			categories.DELETE("/:id", server.categoryHandler.Delete)
+			categories.POST("/:id/merge", server.categoryHandler.Merge)
+			categories.POST("/:id/move", server.categoryHandler.Move)
+			categories.POST("/:id/enrich", server.categoryHandler.Enrich)
+			categories.GET("/:id/export", server.exportHandler.Category)
+		}
+
+		// Tags
+		tags := api.Group("/tags")
+		{
+			tags.GET("", server.tagHandler.List)
+			tags.POST("/backfill", server.tagHandler.Backfill)
+			tags.GET("/:id", server.tagHandler.Get)
+			tags.PUT("/:id", server.tagHandler.Update)
+			tags.POST("/:id/merge", server.tagHandler.Merge)
+		}
+
+		// Concept graph
+		api.GET("/graph", server.graphHandler.Get)
+
+		// Glossary terms
+		terms := api.Group("/terms")
+		{
+			terms.GET("", server.termHandler.List)
+			terms.GET("/lookup", server.termHandler.Lookup)
+			terms.GET("/:id", server.termHandler.Get)
+			terms.POST("", server.termHandler.Create)
+			terms.PUT("/:id", server.termHandler.Update)
+			terms.DELETE("/:id", server.termHandler.Delete)
+		}
+
+		// Change feed for incremental sync
+		api.GET("/changes", server.changeHandler.List)
+
+		// Export to external wikis
+		wikiGroup := api.Group("/wiki")
+		{
+			wikiGroup.GET("/targets", server.wikiHandler.Targets)
+			wikiGroup.POST("/export", server.wikiHandler.Export)
+			wikiGroup.GET("/pages", server.wikiHandler.ListPages)
 		}
 
 		// Search
 		api.GET("/search", server.searchHandler.Search)
 		api.GET("/search/semantic", server.searchHandler.SemanticSearch)
+		api.POST("/search/click", server.searchHandler.RecordClick)
+		api.GET("/search/analytics", server.searchHandler.Analytics)
 
 		// Related articles (under articles group would be better, but registered here for simplicity)
 		articles.GET("/:id/related", server.searchHandler.RelatedArticles)
@@ -127,15 +347,75 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 			tasks.POST("/:id/cancel", server.taskHandler.Cancel)
 		}
 
-		// Instant research (placeholder for now)
-		api.POST("/research", func(c *gin.Context) {
-			c.JSON(http.StatusAccepted, gin.H{
-				"message": "research endpoint - to be implemented with LLM integration",
-			})
-		})
+		// LLM
+		api.GET("/llm/budget", server.llmHandler.GetBudget)
+		api.GET("/llm/concurrency", server.llmHandler.GetConcurrency)
+		api.GET("/llm/calls", server.llmHandler.ListCalls)
+		api.GET("/llm/calls/stats", server.llmHandler.CallStats)
+		api.GET("/llm/calls/:id", server.llmHandler.GetCall)
+
+		// Admin
+		admin := api.Group("/admin")
+		{
+			admin.GET("/pipeline", server.adminHandler.GetPipeline)
+			admin.GET("/queues", server.adminHandler.GetQueues)
+			admin.GET("/consistency", server.adminHandler.ListConsistencyReports)
+			admin.POST("/consistency/run", server.adminHandler.RunConsistencyCheck)
+			admin.GET("/consistency/:id", server.adminHandler.GetConsistencyReport)
+			admin.POST("/embeddings/reindex", server.adminHandler.ReindexEmbeddings)
+		}
+
+		// Prompt and model experiments
+		experimentsGroup := api.Group("/experiments")
+		{
+			experimentsGroup.GET("", server.experimentHandler.List)
+			experimentsGroup.POST("", server.experimentHandler.Create)
+			experimentsGroup.GET("/:id", server.experimentHandler.Get)
+			experimentsGroup.PUT("/:id/status", server.experimentHandler.UpdateStatus)
+			experimentsGroup.PUT("/:id/runs/:runId/score", server.experimentHandler.ScoreRun)
+			experimentsGroup.DELETE("/:id", server.experimentHandler.Delete)
+		}
+
+		// Persona prompts for chat and generation
+		prompts := api.Group("/prompts")
+		{
+			prompts.GET("", server.promptHandler.List)
+			prompts.GET("/:name", server.promptHandler.Get)
+			prompts.PUT("/:name", server.promptHandler.Update)
+			prompts.DELETE("/:name", server.promptHandler.Reset)
+		}
+
+		// Per-domain extraction rules for the web crawler
+		crawlRules := api.Group("/crawl-rules")
+		{
+			crawlRules.GET("", server.crawlRuleHandler.List)
+			crawlRules.POST("", server.crawlRuleHandler.Create)
+			crawlRules.GET("/:id", server.crawlRuleHandler.Get)
+			crawlRules.PUT("/:id", server.crawlRuleHandler.Update)
+			crawlRules.DELETE("/:id", server.crawlRuleHandler.Delete)
+		}
+
+		// Chat over Server-Sent Events, for clients that cannot use the WebSocket
+		api.POST("/chat/stream", server.chatHandler.Stream)
+		api.GET("/chat/models", server.chatHandler.ListModels)
+
+		// Instant research
+		research := api.Group("/research")
+		{
+			research.POST("", server.researchHandler.Research)
+			research.GET("/sessions", server.researchHandler.ListSessions)
+			research.GET("/sessions/:id", server.researchHandler.GetSession)
+			research.POST("/sessions/:id/rerun", server.researchHandler.RerunSession)
+			research.GET("/schedules", server.researchHandler.ListSchedules)
+			research.GET("/schedules/:id", server.researchHandler.GetSchedule)
+			research.POST("/schedules", server.researchHandler.CreateSchedule)
+			research.PUT("/schedules/:id", server.researchHandler.UpdateSchedule)
+			research.DELETE("/schedules/:id", server.researchHandler.DeleteSchedule)
+			research.POST("/schedules/:id/run", server.researchHandler.RunSchedule)
+		}
 
 		// Data Sources
-		dsHandler := NewDataSourceHandler(db)
+		dsHandler := NewDataSourceHandler(db, server.taskClient, &cfg.Worker.Backfill, &cfg.Collector, server.sourceDiscovery)
 		sources := api.Group("/sources")
 		{
 			sources.GET("", dsHandler.List)
@@ -144,22 +424,37 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 			sources.PUT("/:id", dsHandler.Update)
 			sources.DELETE("/:id", dsHandler.Delete)
 			sources.POST("/:id/sync", dsHandler.TriggerSync)
+			sources.POST("/:id/backfill", dsHandler.Backfill)
+			sources.POST("/:id/preview", dsHandler.Preview)
+			sources.GET("/:id/stats", dsHandler.Stats)
 		}
+		api.GET("/sources/types", dsHandler.Types)
 		api.POST("/sources/validate", dsHandler.ValidateURL)
+		api.POST("/sources/discover", dsHandler.Discover)
 
 		// News Items
-		newsHandler := NewNewsHandler(db)
+		newsHandler := NewNewsHandler(db, collector.NewPDFExtractorFromConfig(&cfg.Collector), server.taskClient)
+		newsHandler.SetEmbeddingAdapter(llm.NewEmbeddingAdapterFromConfig(&cfg.LLM))
 		news := api.Group("/news")
 		{
 			news.GET("", newsHandler.List)
+			news.POST("/crawl", newsHandler.Crawl)
+			news.POST("/pdf", newsHandler.UploadPDF)
 			news.GET("/unprocessed", newsHandler.GetUnprocessed)
+			news.GET("/stories", newsHandler.ListStories)
+			news.GET("/stories/:id", newsHandler.GetStory)
+			news.GET("/trending", newsHandler.Trending)
 			news.GET("/:id", newsHandler.Get)
 			news.DELETE("/:id", newsHandler.Delete)
 			news.POST("/:id/processed", newsHandler.MarkProcessed)
+			news.POST("/:id/pipeline", newsHandler.RunPipeline)
+			news.POST("/:id/release", newsHandler.Release)
+			news.POST("/:id/translate", newsHandler.Translate)
+			news.GET("/:id/translation", newsHandler.GetTranslation)
 		}
 
 		// Import/Export
-		importHandler := NewImportHandler(db)
+		importHandler := NewImportHandler(db, server.articleHooks, server.sharedCache, server.taskClient)
 		importGroup := api.Group("/import")
 		{
 			importGroup.POST("", importHandler.Import)
@@ -167,8 +462,13 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 			importGroup.GET("/template", importHandler.GetTemplate)
 			importGroup.GET("/export", importHandler.Export)
 			importGroup.POST("/upload", importHandler.UploadFile)
+			importGroup.POST("/markdown", importHandler.ImportMarkdown)
+			importGroup.GET("/jobs/:id", importHandler.GetJob)
 		}
 
+		// Static site export
+		api.GET("/export/site", server.exportHandler.Site)
+
 		// Explorer Research
 		explorerHandler := NewExplorerHandler(db)
 		explorers := api.Group("/explorers")
@@ -184,6 +484,8 @@ func NewRouterWithDB(cfg *config.Config, db *gorm.DB) *gin.Engine {
 			explorers.PUT("/:id", explorerHandler.Update)
 			explorers.DELETE("/:id", explorerHandler.Delete)
 			explorers.POST("/:id/status", explorerHandler.UpdateStatus)
+			explorers.POST("/bulk/assign", explorerHandler.BulkAssign)
+			explorers.POST("/bulk/status", explorerHandler.BulkUpdateStatus)
 		}
 	}
 
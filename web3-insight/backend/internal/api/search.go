@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,12 +14,15 @@ import (
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
 	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
 )
 
 type SearchHandler struct {
 	articleRepo    *repository.ArticleRepository
 	categoryRepo   *repository.CategoryRepository
 	semanticSearch *service.SemanticSearchService
+	searchIndexer  *service.SearchIndexer // Optional; nil searches the database
+	searchLogs     *repository.SearchLogRepository
 }
 
 func NewSearchHandler(articleRepo *repository.ArticleRepository, categoryRepo *repository.CategoryRepository) *SearchHandler {
@@ -35,21 +41,35 @@ func NewSearchHandlerWithSemantic(articleRepo *repository.ArticleRepository, cat
 	}
 }
 
+// SetSearchIndexer makes keyword search use an external search engine, falling back to
+// the database when the engine fails
+func (h *SearchHandler) SetSearchIndexer(indexer *service.SearchIndexer) {
+	h.searchIndexer = indexer
+}
+
+// SetSearchLogs enables logging of searches and clicks for search analytics
+func (h *SearchHandler) SetSearchLogs(searchLogs *repository.SearchLogRepository) {
+	h.searchLogs = searchLogs
+}
+
 type SearchResult struct {
 	Articles   []model.Article  `json:"articles"`
 	Categories []model.Category `json:"categories"`
 	TotalHits  int              `json:"totalHits"`
+	Engine     string           `json:"engine"`             // Where articles were searched: "database" or the search engine
+	SearchID   string           `json:"searchId,omitempty"` // Pass to POST /api/search/click when a result is opened
 }
 
 // Search godoc
 // @Summary Search across articles and categories
-// @Description Full-text search across articles and categories
+// @Description Full-text search across articles and categories. Articles are searched in the configured search engine, or the database without one.
 // @Tags search
 // @Accept json
 // @Produce json
 // @Param q query string true "Search query"
 // @Param limit query int false "Maximum results (default: 20)"
 // @Param type query string false "Filter by type (articles, categories)"
+// @Param difficulty query string false "Filter articles by difficulty (comma-separated)"
 // @Success 200 {object} SearchResult
 // @Router /api/search [get]
 func (h *SearchHandler) Search(c *gin.Context) {
@@ -73,16 +93,39 @@ func (h *SearchHandler) Search(c *gin.Context) {
 
 	searchType := c.Query("type")
 
+	difficulties, ok := queryDifficulties(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty"})
+		return
+	}
+
 	result := SearchResult{
 		Articles:   []model.Article{},
 		Categories: []model.Category{},
+		Engine:     "database",
 	}
 
-	// Search articles using database ILIKE
 	if searchType == "" || searchType == "articles" {
-		articles, err := h.articleRepo.Search(query, limit)
-		if err == nil {
-			result.Articles = articles
+		searched := false
+		if h.searchIndexer != nil {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+			articles, err := h.searchIndexer.SearchArticles(ctx, query, limit, difficulties)
+			cancel()
+			if err == nil {
+				result.Articles = articles
+				result.Engine = h.searchIndexer.Engine()
+				searched = true
+			} else {
+				log.Printf("Search engine query failed, searching the database: %v", err)
+			}
+		}
+
+		// Search articles using database ILIKE
+		if !searched {
+			articles, err := h.articleRepo.Search(query, limit, difficulties...)
+			if err == nil {
+				result.Articles = articles
+			}
 		}
 	}
 
@@ -95,6 +138,7 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	}
 
 	result.TotalHits = len(result.Articles) + len(result.Categories)
+	result.SearchID = h.logSearch(query, model.SearchKindKeyword, result.Engine, result.TotalHits)
 
 	c.JSON(http.StatusOK, result)
 }
@@ -108,6 +152,7 @@ func (h *SearchHandler) Search(c *gin.Context) {
 // @Param q query string true "Search query"
 // @Param limit query int false "Maximum results (default: 10)"
 // @Param categoryId query string false "Filter by category ID"
+// @Param difficulty query string false "Filter by difficulty (comma-separated)"
 // @Param mode query string false "Search mode: semantic, keyword, or hybrid (default: hybrid)"
 // @Success 200 {array} model.Article
 // @Router /api/search/semantic [get]
@@ -136,6 +181,12 @@ func (h *SearchHandler) SemanticSearch(c *gin.Context) {
 		}
 	}
 
+	difficulties, ok := queryDifficulties(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty"})
+		return
+	}
+
 	mode := c.DefaultQuery("mode", "hybrid")
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
@@ -147,7 +198,7 @@ func (h *SearchHandler) SemanticSearch(c *gin.Context) {
 	// Check if semantic search is available
 	if h.semanticSearch == nil || !h.semanticSearch.IsAvailable() {
 		// Fall back to keyword search
-		articles, err = h.articleRepo.Search(query, limit)
+		articles, err = h.articleRepo.Search(query, limit, difficulties...)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
 			return
@@ -156,6 +207,7 @@ func (h *SearchHandler) SemanticSearch(c *gin.Context) {
 			"articles": articles,
 			"mode":     "keyword",
 			"fallback": true,
+			"searchId": h.logSearch(query, model.SearchKindSemantic, "keyword", len(articles)),
 		})
 		return
 	}
@@ -163,14 +215,15 @@ func (h *SearchHandler) SemanticSearch(c *gin.Context) {
 	switch mode {
 	case "semantic":
 		articles, err = h.semanticSearch.Search(ctx, service.SearchRequest{
-			Query:      query,
-			CategoryID: categoryID,
-			Limit:      limit,
+			Query:        query,
+			CategoryID:   categoryID,
+			Difficulties: difficulties,
+			Limit:        limit,
 		})
 	case "keyword":
-		articles, err = h.articleRepo.Search(query, limit)
+		articles, err = h.articleRepo.Search(query, limit, difficulties...)
 	default: // hybrid
-		articles, err = h.semanticSearch.HybridSearch(ctx, query, limit, categoryID)
+		articles, err = h.semanticSearch.HybridSearch(ctx, query, limit, categoryID, difficulties)
 	}
 
 	if err != nil {
@@ -182,6 +235,7 @@ func (h *SearchHandler) SemanticSearch(c *gin.Context) {
 		"articles": articles,
 		"mode":     mode,
 		"count":    len(articles),
+		"searchId": h.logSearch(query, model.SearchKindSemantic, mode, len(articles)),
 	})
 }
 
@@ -237,3 +291,113 @@ func (h *SearchHandler) RelatedArticles(c *gin.Context) {
 		"available": true,
 	})
 }
+
+// RecordClickRequest identifies the search a result was opened from
+type RecordClickRequest struct {
+	SearchID string `json:"searchId" binding:"required"`
+}
+
+// RecordClick godoc
+// @Summary Record a search result click
+// @Description Count a click on a result of a logged search, for click-through analytics
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body RecordClickRequest true "Search ID from the search response"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/search/click [post]
+func (h *SearchHandler) RecordClick(c *gin.Context) {
+	var req RecordClickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := uuid.Parse(req.SearchID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid searchId"})
+		return
+	}
+	if h.searchLogs == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := h.searchLogs.RecordClick(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "search not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Analytics godoc
+// @Summary Get search analytics
+// @Description Get search totals, the most frequent queries, queries that never returned results, and a daily trend.
+// @Description Queries are grouped case-insensitively; click-through is the share of searches with a clicked result.
+// @Tags search
+// @Produce json
+// @Param days query int false "Days to cover (default 30, max 365)"
+// @Param kind query string false "Only keyword or semantic searches"
+// @Param limit query int false "Queries per list (default 20, max 100)"
+// @Success 200 {object} repository.SearchAnalytics
+// @Router /api/search/analytics [get]
+func (h *SearchHandler) Analytics(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+	kind := c.Query("kind")
+	if kind != "" && kind != model.SearchKindKeyword && kind != model.SearchKindSemantic {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be keyword or semantic"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if h.searchLogs == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "search logging is disabled"})
+		return
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	analytics, err := h.searchLogs.Analytics(since, kind, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, analytics)
+}
+
+// logSearch records a search and returns its ID, or "" when logging is off or failed. The
+// entry is written before the response, so a click on a result always finds its search.
+func (h *SearchHandler) logSearch(query, kind, mode string, hits int) string {
+	if h.searchLogs == nil {
+		return ""
+	}
+	entry := &model.SearchLog{
+		ID:         uuid.New(),
+		Query:      truncateRunes(query, 500),
+		Normalized: truncateRunes(strings.Join(strings.Fields(strings.ToLower(query)), " "), 500),
+		Kind:       kind,
+		Mode:       mode,
+		HitCount:   hits,
+		ZeroResult: hits == 0,
+	}
+	if err := h.searchLogs.Create(entry); err != nil {
+		log.Printf("Failed to log search: %v", err)
+		return ""
+	}
+	return entry.ID.String()
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TagHandler struct {
+	repo *repository.TagRepository
+}
+
+func NewTagHandler(repo *repository.TagRepository) *TagHandler {
+	return &TagHandler{repo: repo}
+}
+
+// ListTags godoc
+// @Summary List tags
+// @Description Get tags, most used first
+// @Tags tags
+// @Produce json
+// @Param search query string false "Search in names and aliases"
+// @Param limit query int false "Maximum number of tags (default: 100)"
+// @Success 200 {array} model.Tag
+// @Router /api/tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		if l, err := strconv.Atoi(raw); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	tags, err := h.repo.List(c.Query("search"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// GetTag godoc
+// @Summary Get tag by ID or slug
+// @Tags tags
+// @Produce json
+// @Param id path string true "Tag ID or slug"
+// @Success 200 {object} model.Tag
+// @Router /api/tags/{id} [get]
+func (h *TagHandler) Get(c *gin.Context) {
+	idParam := c.Param("id")
+
+	var tag *model.Tag
+	var err error
+	if id, parseErr := uuid.Parse(idParam); parseErr == nil {
+		tag, err = h.repo.GetByID(id)
+	} else {
+		tag, err = h.repo.GetBySlug(idParam)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+type UpdateTagRequest struct {
+	Name    string   `json:"name"`    // Renames the tag in every article; the old name becomes an alias
+	Aliases []string `json:"aliases"` // Replaces the aliases when given
+}
+
+// UpdateTag godoc
+// @Summary Rename a tag or set its aliases
+// @Description Rename a tag in every article, keeping the old name as an alias, or replace its aliases. Articles tagged with a new alias get the tag's name.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param request body UpdateTagRequest true "Tag data"
+// @Success 200 {object} model.Tag
+// @Router /api/tags/{id} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.repo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+		return
+	}
+	if req.Name != "" {
+		if tag, err = h.repo.Rename(id, req.Name); err != nil {
+			tagError(c, err)
+			return
+		}
+	}
+	if req.Aliases != nil {
+		if tag, err = h.repo.SetAliases(id, req.Aliases); err != nil {
+			tagError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+type MergeTagRequest struct {
+	TargetID uuid.UUID `json:"targetId" binding:"required"`
+}
+
+// MergeTag godoc
+// @Summary Merge a tag into another
+// @Description Replace the tag with the target in every article and delete it. Its name and aliases become aliases of the target.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID to merge"
+// @Param request body MergeTagRequest true "Target tag"
+// @Success 200 {object} model.Tag
+// @Router /api/tags/{id}/merge [post]
+func (h *TagHandler) Merge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, err := h.repo.Merge(id, req.TargetID)
+	if err != nil {
+		tagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// BackfillTags godoc
+// @Summary Normalize article tags
+// @Description Rewrite the tags of every article in canonical form, creating tags for names not seen before, and recount tag usage. Safe to run again.
+// @Tags tags
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/tags/backfill [post]
+func (h *TagHandler) Backfill(c *gin.Context) {
+	changed, err := h.repo.NormalizeArticleTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "articlesChanged": changed})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"articlesChanged": changed})
+}
+
+// tagError responds with the status matching a tag change error
+func tagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTagExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrInvalidTagOp):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"gorm.io/gorm"
+)
+
+// maxLookupTerms caps the names resolved by one lookup request
+const maxLookupTerms = 100
+
+type TermHandler struct {
+	repo        *repository.TermRepository
+	articleRepo *repository.ArticleRepository
+	glossary    *service.GlossaryService
+}
+
+func NewTermHandler(repo *repository.TermRepository, articleRepo *repository.ArticleRepository, glossary *service.GlossaryService) *TermHandler {
+	return &TermHandler{repo: repo, articleRepo: articleRepo, glossary: glossary}
+}
+
+type TermRequest struct {
+	NameZh     string      `json:"nameZh"`
+	NameEn     string      `json:"nameEn"`
+	Aliases    []string    `json:"aliases"`
+	Definition string      `json:"definition" binding:"required"`
+	ArticleIDs []uuid.UUID `json:"articleIds"` // Replaces the related articles when given
+}
+
+// ListTerms godoc
+// @Summary List glossary terms
+// @Description Get glossary terms by name
+// @Tags terms
+// @Produce json
+// @Param search query string false "Search in names, aliases and definitions"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 50)"
+// @Success 200 {object} repository.TermListResult
+// @Router /api/terms [get]
+func (h *TermHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+
+	result, err := h.repo.List(c.Query("search"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// LookupTerms godoc
+// @Summary Look up glossary terms
+// @Description Resolve names, English names or aliases to their terms for hover definitions, keyed by the name as given; unknown names are left out. With articleId, returns the terms linked to the article instead, longest names first.
+// @Tags terms
+// @Produce json
+// @Param q query string false "Names to look up (comma-separated)"
+// @Param articleId query string false "Article ID"
+// @Success 200 {object} map[string]model.Term
+// @Router /api/terms/lookup [get]
+func (h *TermHandler) Lookup(c *gin.Context) {
+	if raw := c.Query("articleId"); raw != "" {
+		articleID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid articleId"})
+			return
+		}
+		terms, err := h.repo.ListForArticle(articleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, terms)
+		return
+	}
+
+	names := queryList(c, "q")
+	if len(names) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q or articleId is required"})
+		return
+	}
+	if len(names) > maxLookupTerms {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many names"})
+		return
+	}
+
+	terms, err := h.repo.Lookup(names)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// GetTerm godoc
+// @Summary Get glossary term by ID or slug
+// @Description Get a glossary term with its related articles
+// @Tags terms
+// @Produce json
+// @Param id path string true "Term ID or slug"
+// @Success 200 {object} model.Term
+// @Router /api/terms/{id} [get]
+func (h *TermHandler) Get(c *gin.Context) {
+	idParam := c.Param("id")
+
+	var term *model.Term
+	var err error
+	if id, parseErr := uuid.Parse(idParam); parseErr == nil {
+		term, err = h.repo.GetByID(id)
+	} else {
+		term, err = h.repo.GetBySlug(idParam)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "term not found"})
+		return
+	}
+
+	if err := h.repo.LoadArticles(term); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// CreateTerm godoc
+// @Summary Create glossary term
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param request body TermRequest true "Term data"
+// @Success 201 {object} model.Term
+// @Failure 409 {object} map[string]string "A name is used by another term"
+// @Router /api/terms [post]
+func (h *TermHandler) Create(c *gin.Context) {
+	var req TermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	term := &model.Term{Source: model.TermSourceManual}
+	if !applyTermRequest(c, term, &req) {
+		return
+	}
+	if req.ArticleIDs != nil && !articlesExist(c, h.articleRepo, req.ArticleIDs...) {
+		return
+	}
+
+	if err := h.repo.Create(term); err != nil {
+		termError(c, err)
+		return
+	}
+	if err := h.repo.SetArticles(term.ID, req.ArticleIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.LoadArticles(term); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, term)
+}
+
+// UpdateTerm godoc
+// @Summary Update glossary term
+// @Description Replace a term's names, aliases and definition, and its related articles when articleIds is given. An edited term counts as manual, so extraction no longer changes it.
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Term ID"
+// @Param request body TermRequest true "Term data"
+// @Success 200 {object} model.Term
+// @Failure 409 {object} map[string]string "A name is used by another term"
+// @Router /api/terms/{id} [put]
+func (h *TermHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req TermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	term, err := h.repo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "term not found"})
+		return
+	}
+	if !applyTermRequest(c, term, &req) {
+		return
+	}
+	term.Source = model.TermSourceManual
+	if req.ArticleIDs != nil && !articlesExist(c, h.articleRepo, req.ArticleIDs...) {
+		return
+	}
+
+	if err := h.repo.Update(term); err != nil {
+		termError(c, err)
+		return
+	}
+	if req.ArticleIDs != nil {
+		if err := h.repo.SetArticles(term.ID, req.ArticleIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := h.repo.LoadArticles(term); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// DeleteTerm godoc
+// @Summary Delete glossary term
+// @Tags terms
+// @Param id path string true "Term ID"
+// @Success 204
+// @Router /api/terms/{id} [delete]
+func (h *TermHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExtractTerms godoc
+// @Summary Extract glossary terms from an article
+// @Description Ask the LLM for the jargon in an article, add terms missing from the glossary and link them all to the article. Existing definitions are kept.
+// @Tags terms
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {array} model.Term
+// @Router /api/articles/{id}/terms/extract [post]
+func (h *TermHandler) Extract(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if !articlesExist(c, h.articleRepo, id) {
+		return
+	}
+
+	terms, err := h.glossary.ExtractAndSave(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// applyTermRequest copies a term request onto a term. Responds with 400 and returns false
+// if the term would have no name.
+func applyTermRequest(c *gin.Context, term *model.Term, req *TermRequest) bool {
+	term.NameZh = strings.TrimSpace(req.NameZh)
+	term.NameEn = strings.TrimSpace(req.NameEn)
+	term.Definition = strings.TrimSpace(req.Definition)
+	if term.NameZh == "" && term.NameEn == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nameZh or nameEn is required"})
+		return false
+	}
+	aliases := pq.StringArray{}
+	for _, alias := range req.Aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	term.Aliases = aliases
+	return true
+}
+
+// termError responds with the status matching a term change error
+func termError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTermExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "term not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/service"
+	"github.com/user/web3-insight/internal/worker"
+)
+
+type WikiHandler struct {
+	pages      *repository.WikiPageRepository
+	exporter   *service.WikiExportService
+	taskClient worker.TaskEnqueuer
+}
+
+func NewWikiHandler(pages *repository.WikiPageRepository, exporter *service.WikiExportService, taskClient worker.TaskEnqueuer) *WikiHandler {
+	return &WikiHandler{pages: pages, exporter: exporter, taskClient: taskClient}
+}
+
+type WikiExportRequest struct {
+	Target               string   `json:"target" binding:"required"`
+	ArticleIDs           []string `json:"articleIds"`
+	CategoryID           string   `json:"categoryId"`
+	IncludeSubcategories bool     `json:"includeSubcategories"`
+	Force                bool     `json:"force"`
+}
+
+// ListWikiTargets godoc
+// @Summary List wiki export targets
+// @Description Get the external wikis configured for export
+// @Tags wiki
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/wiki/targets [get]
+func (h *WikiHandler) Targets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"targets": h.exporter.Targets()})
+}
+
+// ExportToWiki godoc
+// @Summary Export articles to a wiki
+// @Description Push published articles, or all published articles of a category, to Confluence or Feishu.
+// @Description Articles exported before update their existing page; unchanged articles are skipped unless force is set.
+// @Tags wiki
+// @Accept json
+// @Produce json
+// @Param request body WikiExportRequest true "Export selection"
+// @Success 202 {object} map[string]interface{}
+// @Router /api/wiki/export [post]
+func (h *WikiHandler) Export(c *gin.Context) {
+	var req WikiExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.exporter.HasTarget(req.Target) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "wiki target not configured: " + req.Target})
+		return
+	}
+	if len(req.ArticleIDs) == 0 && req.CategoryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "articleIds or categoryId is required"})
+		return
+	}
+	for _, id := range req.ArticleIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid articleIds"})
+			return
+		}
+	}
+	if req.CategoryID != "" {
+		if _, err := uuid.Parse(req.CategoryID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid categoryId"})
+			return
+		}
+	}
+
+	info, err := worker.EnqueueWikiExport(h.taskClient, worker.WikiExportPayload{
+		Target:               req.Target,
+		ArticleIDs:           req.ArticleIDs,
+		CategoryID:           req.CategoryID,
+		IncludeSubcategories: req.IncludeSubcategories,
+		Force:                req.Force,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "wiki export enqueued",
+		"taskId":  info.ID,
+	})
+}
+
+// ListWikiPages godoc
+// @Summary List exported wiki pages
+// @Description Get the wiki pages articles were exported to
+// @Tags wiki
+// @Produce json
+// @Param target query string false "Filter by target (confluence, feishu)"
+// @Param article_id query string false "Filter by article"
+// @Success 200 {array} model.WikiPage
+// @Router /api/wiki/pages [get]
+func (h *WikiHandler) ListPages(c *gin.Context) {
+	var articleID *uuid.UUID
+	if raw := c.Query("article_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid article_id"})
+			return
+		}
+		articleID = &id
+	}
+
+	pages, err := h.pages.List(c.Query("target"), articleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pages)
+}
@@ -0,0 +1,183 @@
+// Package cache provides a Redis-backed cache shared by all server and worker instances.
+// Each instance keeps a short-lived local copy of hot entries; writes invalidate the
+// Redis entry and broadcast the key over pub/sub so every instance drops its copy.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/user/web3-insight/internal/config"
+)
+
+const (
+	invalidationChannel = "cache:invalidate"
+	keyPrefix           = "cache:"
+
+	// defaultTTL bounds staleness if an invalidation is lost, e.g. a write racing a reload
+	defaultTTL = 5 * time.Minute
+	// localTTL bounds staleness of the in-process copy if a pub/sub message is missed
+	localTTL = 30 * time.Second
+	// redisTimeout keeps requests fast when Redis is down; callers fall back to the database
+	redisTimeout = 500 * time.Millisecond
+)
+
+// Cache is a two-level cache: in-process copies in front of shared Redis entries.
+// A nil *Cache is valid and never hits, so callers work without Redis.
+type Cache struct {
+	client *redis.Client
+	ttl    time.Duration
+
+	mu    sync.RWMutex
+	local map[string]localEntry
+}
+
+type localEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// New creates a cache using the given Redis client
+func New(client *redis.Client, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Cache{
+		client: client,
+		ttl:    ttl,
+		local:  make(map[string]localEntry),
+	}
+}
+
+// NewFromConfig creates a cache with its own Redis client
+func NewFromConfig(redisCfg *config.RedisConfig) *Cache {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisCfg.Host, redisCfg.Port),
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+	return New(client, defaultTTL)
+}
+
+// Get decodes the cached value of key into dest and reports whether it was found
+func (c *Cache) Get(key string, dest interface{}) bool {
+	if c == nil {
+		return false
+	}
+
+	data, ok := c.localGet(key)
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		defer cancel()
+
+		var err error
+		data, err = c.client.Get(ctx, keyPrefix+key).Bytes()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				log.Printf("Cache read failed for %s: %v", key, err)
+			}
+			return false
+		}
+		c.localSet(key, data)
+	}
+
+	// Decode on every hit so callers never share, and can't mutate, the cached value
+	return json.Unmarshal(data, dest) == nil
+}
+
+// Set stores value under key in Redis and the local copy
+func (c *Cache) Set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Cache encode failed for %s: %v", key, err)
+		return
+	}
+	c.localSet(key, data)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := c.client.Set(ctx, keyPrefix+key, data, c.ttl).Err(); err != nil {
+		log.Printf("Cache write failed for %s: %v", key, err)
+	}
+}
+
+// Invalidate removes keys from Redis and tells every instance to drop its local copy
+func (c *Cache) Invalidate(keys ...string) {
+	if c == nil || len(keys) == 0 {
+		return
+	}
+	c.dropLocal(keys)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = keyPrefix + key
+	}
+	if err := c.client.Del(ctx, redisKeys...).Err(); err != nil {
+		log.Printf("Cache invalidation failed for %v: %v", keys, err)
+	}
+	if err := c.client.Publish(ctx, invalidationChannel, strings.Join(keys, ",")).Err(); err != nil {
+		log.Printf("Cache invalidation broadcast failed for %v: %v", keys, err)
+	}
+}
+
+// Listen drops local copies invalidated by other instances until ctx is done.
+// Run it in a goroutine on every instance that reads from the cache.
+func (c *Cache) Listen(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	pubsub := c.client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			c.dropLocal(strings.Split(msg.Payload, ","))
+		}
+	}
+}
+
+func (c *Cache) localGet(key string) ([]byte, bool) {
+	c.mu.RLock()
+	entry, ok := c.local[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+func (c *Cache) localSet(key string, data []byte) {
+	c.mu.Lock()
+	c.local[key] = localEntry{data: data, expires: time.Now().Add(localTTL)}
+	c.mu.Unlock()
+}
+
+func (c *Cache) dropLocal(keys []string) {
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.local, key)
+	}
+	c.mu.Unlock()
+}
@@ -0,0 +1,585 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// API source defaults
+const (
+	defaultAPIMaxPages = 3
+	defaultAPIPageSize = 20
+	// Window used for {since} before a source's first sync
+	defaultAPISinceWindow = 7 * 24 * time.Hour
+)
+
+// envReference matches ${NAME} references to environment variables in URLs and headers
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// SourceSecretPrefix is the prefix of the environment variables API and email sources may read.
+// Source URLs are set through the API, so any other variable (LLM keys, the database
+// password) could be sent to a host of the caller's choosing.
+const SourceSecretPrefix = "WEB3_SOURCE_"
+
+// APICollector ingests any JSON REST API described by the source config: the source URL is a
+// request template, and JSONPath mappings turn response items into news items, so new REST
+// sources need no code.
+//
+// URL and body templates may use {page}, {offset}, {limit}, {cursor}, {since} (RFC 3339) and
+// {sinceUnix}. URL and header values may reference secrets as ${WEB3_SOURCE_NAME}.
+type APICollector struct {
+	newsRepo  *repository.NewsRepository
+	client    *http.Client
+	converter *md.Converter
+}
+
+// NewAPICollector creates a new generic JSON API collector
+func NewAPICollector(newsRepo *repository.NewsRepository) *APICollector {
+	return &APICollector{
+		newsRepo:  newsRepo,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		converter: md.NewConverter("", true, nil),
+	}
+}
+
+// APIConfig holds API-specific configuration
+type APIConfig struct {
+	DefaultCategory string            `json:"defaultCategory,omitempty"`
+	Language        string            `json:"language,omitempty"`
+	Method          string            `json:"method,omitempty"`        // GET or POST; defaults to GET
+	Body            string            `json:"body,omitempty"`          // Request body template, sent as JSON
+	Headers         map[string]string `json:"headers,omitempty"`       // Extra request headers, such as Authorization
+	ItemsPath       string            `json:"itemsPath"`               // JSONPath to the items of a response, e.g. $.data.results
+	Fields          APIFieldMapping   `json:"fields"`                  // JSONPaths within an item
+	ContentFormat   string            `json:"contentFormat,omitempty"` // "html" converts content to markdown; otherwise kept as-is
+	Pagination      APIPagination     `json:"pagination,omitempty"`
+}
+
+// APIFieldMapping maps item fields to news item fields with JSONPaths relative to an item
+type APIFieldMapping struct {
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"` // Either url or id is required to recognize stored items
+	ID          string `json:"id,omitempty"`
+	Content     string `json:"content,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	Author      string `json:"author,omitempty"`
+	PublishedAt string `json:"publishedAt,omitempty"` // RFC 3339, common date layouts, or Unix seconds or milliseconds
+	Tags        string `json:"tags,omitempty"`        // A string array, or a path with [*] over objects
+	Language    string `json:"language,omitempty"`
+}
+
+// APIPagination describes how further pages are requested
+type APIPagination struct {
+	Type       string `json:"type,omitempty"`       // "page", "offset" or "cursor"; a single request if empty
+	Start      int    `json:"start,omitempty"`      // First page number (default 1) or offset (default 0)
+	PageSize   int    `json:"pageSize,omitempty"`   // Value of {limit} and the offset step; defaults to 20
+	MaxPages   int    `json:"maxPages,omitempty"`   // Requests per sync; defaults to 3
+	CursorPath string `json:"cursorPath,omitempty"` // JSONPath to the next cursor in a response
+}
+
+// apiMapping is a source config with its JSONPaths compiled
+type apiMapping struct {
+	config APIConfig
+	items  jsonPath
+	fields map[string]jsonPath
+	cursor jsonPath
+}
+
+// Type returns the data source type handled by this collector
+func (c *APICollector) Type() string {
+	return model.DataSourceTypeAPI
+}
+
+// Validate checks the mapping and that the first page yields items with titles and URLs
+func (c *APICollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	mapping, err := compileAPIMapping(source)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := c.fetchPage(ctx, source, mapping, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	items := mapping.itemsOf(doc)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("itemsPath %s matched no items", mapping.config.ItemsPath)
+	}
+	newsItem := c.toNewsItem(items[0], source, mapping)
+	if newsItem.Title == "" || newsItem.SourceURL == "" {
+		return nil, fmt.Errorf("first item has no title or url after mapping")
+	}
+	return &ValidationResult{
+		Title:       source.Name,
+		Description: "First item: " + newsItem.Title,
+		ItemCount:   len(items),
+	}, nil
+}
+
+// Collect requests pages of the API and stores new items as news items. Paging stops early
+// once a page holds nothing new.
+func (c *APICollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+
+	if source.Type != model.DataSourceTypeAPI {
+		return nil, fmt.Errorf("data source is not api type: %s", source.Type)
+	}
+
+	mapping, err := compileAPIMapping(source)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+
+	var newsItems []model.NewsItem
+	err = c.eachPage(ctx, source, mapping, func(items []any) bool {
+		result.ItemsFound += len(items)
+		fresh := 0
+		for _, item := range items {
+			newsItem := c.toNewsItem(item, source, mapping)
+			if newsItem.Title == "" || newsItem.SourceURL == "" {
+				result.ItemsFailed++
+				continue
+			}
+			if existing, err := c.newsRepo.FindBySourceURL(newsItem.SourceURL); err == nil && existing != nil {
+				continue
+			}
+			fresh++
+			if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+				log.Printf("Filtered item from %s: %s (%s)", source.Name, newsItem.Title, reason)
+				result.ItemsFiltered++
+				continue
+			}
+			newsItems = append(newsItems, *newsItem)
+		}
+		return fresh > 0
+	})
+	if err != nil && result.ItemsFound == 0 {
+		return nil, err
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew = newCount
+
+	log.Printf("API sync completed for %s: found=%d, new=%d, filtered=%d, failed=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered, result.ItemsFailed)
+
+	return result, nil
+}
+
+// Preview maps the items of the first page without writing anything
+func (c *APICollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	mapping, err := compileAPIMapping(source)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := c.fetchPage(ctx, source, mapping, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	items := mapping.itemsOf(doc)
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(items),
+		Items:      make([]PreviewItem, 0, len(items)),
+	}
+	for _, item := range items {
+		newsItem := c.toNewsItem(item, source, mapping)
+		preview := PreviewItem{
+			Title:       newsItem.Title,
+			URL:         newsItem.SourceURL,
+			PublishedAt: newsItem.PublishedAt,
+			Excerpt:     previewExcerpt(newsItem.Content),
+			Language:    newsItem.SourceLanguage,
+		}
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			preview.Filtered = true
+			preview.FilterReason = reason
+			result.ItemsFiltered++
+		} else if existing, err := c.newsRepo.FindBySourceURL(preview.URL); err == nil && existing != nil {
+			preview.Exists = true
+		} else {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// compileAPIMapping reads a source's config, applying defaults and compiling its JSONPaths
+func compileAPIMapping(source *model.DataSource) (*apiMapping, error) {
+	var config APIConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			return nil, fmt.Errorf("invalid api config: %w", err)
+		}
+	}
+	if config.ItemsPath == "" {
+		return nil, fmt.Errorf("itemsPath is required")
+	}
+	if config.Fields.Title == "" {
+		return nil, fmt.Errorf("fields.title is required")
+	}
+	if config.Fields.URL == "" && config.Fields.ID == "" {
+		return nil, fmt.Errorf("fields.url or fields.id is required")
+	}
+	if err := checkEnvReferences(source.URL); err != nil {
+		return nil, err
+	}
+	for _, value := range config.Headers {
+		if err := checkEnvReferences(value); err != nil {
+			return nil, err
+		}
+	}
+	config.Method = strings.ToUpper(config.Method)
+	if config.Method == "" {
+		config.Method = http.MethodGet
+	}
+	if config.Method != http.MethodGet && config.Method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method: %s", config.Method)
+	}
+
+	pagination := &config.Pagination
+	switch pagination.Type {
+	case "":
+		pagination.MaxPages = 1
+	case "page":
+		if pagination.Start == 0 {
+			pagination.Start = 1
+		}
+	case "offset":
+	case "cursor":
+		if pagination.CursorPath == "" {
+			return nil, fmt.Errorf("pagination.cursorPath is required for cursor pagination")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported pagination type: %s", pagination.Type)
+	}
+	if pagination.PageSize <= 0 {
+		pagination.PageSize = defaultAPIPageSize
+	}
+	if pagination.MaxPages <= 0 {
+		pagination.MaxPages = defaultAPIMaxPages
+	}
+
+	mapping := &apiMapping{config: config, fields: make(map[string]jsonPath)}
+	var err error
+	if mapping.items, err = compileJSONPath(config.ItemsPath); err != nil {
+		return nil, err
+	}
+	if pagination.CursorPath != "" {
+		if mapping.cursor, err = compileJSONPath(pagination.CursorPath); err != nil {
+			return nil, err
+		}
+	}
+	fields := map[string]string{
+		"title":       config.Fields.Title,
+		"url":         config.Fields.URL,
+		"id":          config.Fields.ID,
+		"content":     config.Fields.Content,
+		"summary":     config.Fields.Summary,
+		"author":      config.Fields.Author,
+		"publishedAt": config.Fields.PublishedAt,
+		"tags":        config.Fields.Tags,
+		"language":    config.Fields.Language,
+	}
+	for name, expr := range fields {
+		if expr == "" {
+			continue
+		}
+		if mapping.fields[name], err = compileJSONPath(expr); err != nil {
+			return nil, fmt.Errorf("fields.%s: %w", name, err)
+		}
+	}
+	return mapping, nil
+}
+
+// itemsOf returns the items of a response. A path to an array yields its elements, so
+// $.results and $.results[*] are equivalent.
+func (m *apiMapping) itemsOf(doc any) []any {
+	items := m.items.Select(doc)
+	if len(items) == 1 {
+		if list, ok := items[0].([]any); ok {
+			return list
+		}
+	}
+	return items
+}
+
+// eachPage requests pages in turn, passing their items to fn until fn returns false, a page is
+// empty, the cursor runs out or MaxPages is reached
+func (c *APICollector) eachPage(ctx context.Context, source *model.DataSource, mapping *apiMapping, fn func(items []any) bool) error {
+	cursor := ""
+	for page := 0; page < mapping.config.Pagination.MaxPages; page++ {
+		doc, err := c.fetchPage(ctx, source, mapping, page, cursor)
+		if err != nil {
+			return err
+		}
+		items := mapping.itemsOf(doc)
+		if len(items) == 0 || !fn(items) {
+			return nil
+		}
+		if mapping.cursor != nil {
+			cursor = apiString(mapping.cursor.First(doc))
+			if cursor == "" {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// fetchPage requests one page, page being 0 for the first request
+func (c *APICollector) fetchPage(ctx context.Context, source *model.DataSource, mapping *apiMapping, page int, cursor string) (any, error) {
+	config := mapping.config
+	pagination := config.Pagination
+	since := time.Now().Add(-defaultAPISinceWindow)
+	if source.LastFetchedAt != nil {
+		since = *source.LastFetchedAt
+	}
+	values := map[string]string{
+		"page":      strconv.Itoa(pagination.Start + page),
+		"offset":    strconv.Itoa(pagination.Start + page*pagination.PageSize),
+		"limit":     strconv.Itoa(pagination.PageSize),
+		"cursor":    cursor,
+		"since":     since.UTC().Format(time.RFC3339),
+		"sinceUnix": strconv.FormatInt(since.Unix(), 10),
+	}
+
+	target := expandTemplate(expandEnv(source.URL), values, url.QueryEscape)
+	var body io.Reader
+	if config.Body != "" {
+		body = strings.NewReader(expandTemplate(config.Body, values, jsonEscape))
+	}
+	req, err := http.NewRequestWithContext(ctx, config.Method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range config.Headers {
+		req.Header.Set(name, expandEnv(value))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, 20<<20))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return doc, nil
+}
+
+// toNewsItem maps one response item to a news item
+func (c *APICollector) toNewsItem(item any, source *model.DataSource, mapping *apiMapping) *model.NewsItem {
+	field := func(name string) any {
+		if path, ok := mapping.fields[name]; ok {
+			return path.First(item)
+		}
+		return nil
+	}
+
+	config := mapping.config
+	content := apiString(field("content"))
+	summary := apiString(field("summary"))
+	if config.ContentFormat == "html" {
+		if converted, err := c.converter.ConvertString(content); err == nil {
+			content = strings.TrimSpace(converted)
+		}
+	}
+	if content == "" {
+		content = summary
+	}
+
+	sourceURL := apiString(field("url"))
+	if sourceURL != "" {
+		if base, err := url.Parse(source.URL); err == nil {
+			if ref, err := base.Parse(sourceURL); err == nil {
+				sourceURL = ref.String()
+			}
+		}
+	} else if id := apiString(field("id")); id != "" {
+		sourceURL = strings.SplitN(source.URL, "?", 2)[0] + "#" + id
+	}
+
+	var tags []string
+	if path, ok := mapping.fields["tags"]; ok {
+		for _, value := range path.Select(item) {
+			if list, ok := value.([]any); ok {
+				for _, tag := range list {
+					if s := apiString(tag); s != "" {
+						tags = append(tags, s)
+					}
+				}
+			} else if s := apiString(value); s != "" {
+				tags = append(tags, s)
+			}
+		}
+	}
+
+	title := strings.TrimSpace(apiString(field("title")))
+	newsItem := &model.NewsItem{
+		Title:          title,
+		OriginalTitle:  title,
+		Content:        content,
+		SourceURL:      sourceURL,
+		SourceName:     source.Name,
+		SourceLanguage: apiString(field("language")),
+		Author:         apiString(field("author")),
+		Category:       config.DefaultCategory,
+		Tags:           tags,
+		PublishedAt:    apiFieldTime(field("publishedAt")),
+		FetchedAt:      time.Now(),
+	}
+	if newsItem.SourceLanguage == "" {
+		newsItem.SourceLanguage = config.Language
+	}
+	if newsItem.SourceLanguage == "" {
+		newsItem.SourceLanguage = detectLanguage(newsItem.Title + " " + newsItem.Content)
+	}
+	return newsItem
+}
+
+// apiString formats a scalar JSON value as a string; objects and arrays give ""
+func apiString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// apiTimeLayouts are the date layouts tried for string publishedAt values
+var apiTimeLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// apiFieldTime parses a mapped date given as a string or as Unix seconds or milliseconds
+func apiFieldTime(value any) *time.Time {
+	text := strings.TrimSpace(apiString(value))
+	if text == "" {
+		return nil
+	}
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	unix, err := strconv.ParseFloat(text, 64)
+	if err != nil || unix <= 0 {
+		return nil
+	}
+	var t time.Time
+	if unix > 1e12 {
+		t = time.UnixMilli(int64(unix)).UTC()
+	} else {
+		t = time.Unix(int64(unix), 0).UTC()
+	}
+	return &t
+}
+
+// expandTemplate replaces {name} placeholders with escaped values, leaving other braces, such
+// as those of a JSON body, alone
+func expandTemplate(template string, values map[string]string, escape func(string) string) string {
+	var b bytes.Buffer
+	for {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(template[open:], '}')
+		if end < 0 {
+			break
+		}
+		if value, ok := values[template[open+1:open+end]]; ok {
+			b.WriteString(template[:open])
+			b.WriteString(escape(value))
+			template = template[open+end+1:]
+		} else {
+			b.WriteString(template[:open+1])
+			template = template[open+1:]
+		}
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// expandEnv replaces ${NAME} references with environment variables, so credentials stay out
+// of the database. Only names with SourceSecretPrefix are expanded; others become empty.
+func expandEnv(s string) string {
+	return envReference.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envReference.FindStringSubmatch(ref)[1]
+		if !strings.HasPrefix(name, SourceSecretPrefix) {
+			return ""
+		}
+		return os.Getenv(name)
+	})
+}
+
+// checkEnvReferences rejects ${NAME} references to variables without SourceSecretPrefix
+func checkEnvReferences(s string) error {
+	for _, match := range envReference.FindAllStringSubmatch(s, -1) {
+		if !strings.HasPrefix(match[1], SourceSecretPrefix) {
+			return fmt.Errorf("environment variable %s may not be referenced; source secrets must start with %s", match[1], SourceSecretPrefix)
+		}
+	}
+	return nil
+}
+
+// jsonEscape escapes a value substituted inside a JSON string of a body template
+func jsonEscape(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted[1 : len(quoted)-1])
+}
@@ -0,0 +1,315 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+var (
+	feedNextLinkPattern = regexp.MustCompile(`(?i)<(?:atom:)?link\b[^>]*\brel=["']next["'][^>]*>`)
+	hrefAttrPattern     = regexp.MustCompile(`(?i)\bhref=["']([^"']+)["']`)
+)
+
+// Backfiller walks historical feed pages and archive listings of a newly added source
+type Backfiller struct {
+	rssCollector *RSSCollector
+	webCrawler   *WebCrawler
+	dsRepo       *repository.DataSourceRepository
+	newsRepo     *repository.NewsRepository
+	rateLimiter  *RateLimiter
+	client       *http.Client
+}
+
+// NewBackfiller creates a new backfiller
+func NewBackfiller(rssCollector *RSSCollector, webCrawler *WebCrawler, newsRepo *repository.NewsRepository, dsRepo *repository.DataSourceRepository) *Backfiller {
+	return &Backfiller{
+		rssCollector: rssCollector,
+		webCrawler:   webCrawler,
+		dsRepo:       dsRepo,
+		newsRepo:     newsRepo,
+		rateLimiter:  NewRateLimiter(10*time.Second, 30*time.Second),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// BackfillPageResult represents the outcome of backfilling one historical page
+type BackfillPageResult struct {
+	PageURL    string
+	NextURL    string // Empty when there are no older pages
+	FirstURL   string // Link of the first item, used to detect pages that repeat
+	ItemsFound int
+	ItemsNew   int
+}
+
+// BackfillPage ingests one historical page of a source. pageURL may be empty for the
+// first page; prevFirstURL is the first item link of the previous page.
+func (b *Backfiller) BackfillPage(ctx context.Context, sourceID uuid.UUID, pageURL string, page int, maxArticles int, prevFirstURL string) (*BackfillPageResult, error) {
+	source, err := b.dsRepo.FindByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data source: %w", err)
+	}
+
+	if pageURL == "" {
+		pageURL = source.URL
+	}
+
+	switch source.Type {
+	case model.DataSourceTypeRSS:
+		return b.backfillFeedPage(ctx, source, pageURL, page, prevFirstURL)
+	case model.DataSourceTypeCrawl:
+		return b.backfillListingPage(ctx, source, pageURL, maxArticles, prevFirstURL)
+	default:
+		return nil, fmt.Errorf("backfill not supported for source type: %s", source.Type)
+	}
+}
+
+// backfillFeedPage fetches an older feed page, following rel="next" links (RFC 5005)
+// or falling back to WordPress-style ?paged=N pagination
+func (b *Backfiller) backfillFeedPage(ctx context.Context, source *model.DataSource, pageURL string, page int, prevFirstURL string) (*BackfillPageResult, error) {
+	result := &BackfillPageResult{PageURL: pageURL}
+
+	body, err := b.fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return result, nil
+	}
+
+	feed, err := b.rssCollector.parser.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed page: %w", err)
+	}
+
+	result.ItemsFound = len(feed.Items)
+	if len(feed.Items) == 0 {
+		return result, nil
+	}
+	result.FirstURL = feed.Items[0].Link
+	if result.FirstURL == prevFirstURL {
+		// Server ignored the page parameter and returned the same items again
+		return result, nil
+	}
+
+	var config RSSConfig
+	if source.Config != nil {
+		json.Unmarshal(source.Config, &config)
+	}
+	filter, _ := ParseItemFilter(source.Config)
+
+	license := NormalizeLicense(feed.Copyright)
+	licenseURL := LicenseURL(license, feed.Copyright)
+	newsItems := make([]model.NewsItem, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		newsItem := b.rssCollector.convertFeedItem(item, source.Name, config)
+		newsItem.License = license
+		newsItem.LicenseURL = licenseURL
+		if ok, _ := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			continue
+		}
+		newsItems = append(newsItems, newsItem)
+	}
+
+	newCount, err := b.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		return result, fmt.Errorf("failed to save feed items: %w", err)
+	}
+	result.ItemsNew = newCount
+
+	if next := findFeedNextLink(body, pageURL); next != "" {
+		result.NextURL = next
+	} else {
+		result.NextURL = pagedFeedURL(source.URL, page+1)
+	}
+
+	log.Printf("Backfill feed page %d for %s: found=%d, new=%d", page, source.Name, result.ItemsFound, result.ItemsNew)
+	return result, nil
+}
+
+// backfillListingPage crawls article links on an archive listing page and finds the next listing page
+func (b *Backfiller) backfillListingPage(ctx context.Context, source *model.DataSource, pageURL string, maxArticles int, prevFirstURL string) (*BackfillPageResult, error) {
+	result := &BackfillPageResult{PageURL: pageURL}
+
+	body, err := b.fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return result, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse listing page: %w", err)
+	}
+
+	links := extractArticleLinks(doc, pageURL)
+	if maxArticles > 0 && len(links) > maxArticles {
+		links = links[:maxArticles]
+	}
+
+	result.ItemsFound = len(links)
+	if len(links) == 0 {
+		return result, nil
+	}
+	result.FirstURL = links[0]
+	if result.FirstURL == prevFirstURL {
+		return result, nil
+	}
+
+	filter, _ := ParseItemFilter(source.Config)
+
+	for _, link := range links {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		if existing, err := b.newsRepo.FindBySourceURL(link); err == nil && existing != nil {
+			continue
+		}
+		// WebCrawler applies its own per-domain rate limit between requests
+		if _, err := b.webCrawler.CrawlAndSaveFiltered(ctx, link, source.Name, filter); err != nil {
+			if err != ErrItemFiltered {
+				log.Printf("Backfill crawl failed for %s: %v", link, err)
+			}
+			continue
+		}
+		result.ItemsNew++
+	}
+
+	result.NextURL = findListingNextLink(doc, pageURL)
+
+	log.Printf("Backfill listing %s for %s: found=%d, new=%d", pageURL, source.Name, result.ItemsFound, result.ItemsNew)
+	return result, nil
+}
+
+// fetch downloads a page politely, honoring the per-domain rate limit
+func (b *Backfiller) fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	b.rateLimiter.Wait(parsed.Host)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Web3-Insight/1.0 (Backfill)")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Past the last archive page
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", pageURL, resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+}
+
+// findFeedNextLink looks for an RFC 5005 <link rel="next"> in raw feed XML
+func findFeedNextLink(body []byte, base string) string {
+	tag := feedNextLinkPattern.Find(body)
+	if tag == nil {
+		return ""
+	}
+	m := hrefAttrPattern.FindSubmatch(tag)
+	if m == nil {
+		return ""
+	}
+	return resolveURL(base, string(m[1]))
+}
+
+// pagedFeedURL builds a WordPress-style paginated feed URL
+func pagedFeedURL(feedURL string, page int) string {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("paged", strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// findListingNextLink finds the "older posts" link on an archive page
+func findListingNextLink(doc *goquery.Document, base string) string {
+	selectors := []string{
+		`link[rel="next"]`,
+		`a[rel="next"]`,
+		`.pagination a.next`,
+		`a.next`,
+		`.nav-previous a`,
+	}
+	for _, sel := range selectors {
+		if href, ok := doc.Find(sel).First().Attr("href"); ok && href != "" {
+			return resolveURL(base, href)
+		}
+	}
+	return ""
+}
+
+// extractArticleLinks collects same-host links that look like articles below the listing
+func extractArticleLinks(doc *goquery.Document, base string) []string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var links []string
+	doc.Find("article a[href], main a[href], .post a[href], .entry-title a[href], h2 a[href], h3 a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		link := resolveURL(base, href)
+		u, err := url.Parse(link)
+		if err != nil || u.Host != baseURL.Host {
+			return
+		}
+		u.Fragment = ""
+		link = u.String()
+		path := strings.Trim(u.Path, "/")
+		if path == "" || link == base || seen[link] ||
+			strings.Contains(path, "/page/") || strings.HasPrefix(path, "tag/") || strings.HasPrefix(path, "category/") {
+			return
+		}
+		seen[link] = true
+		links = append(links, link)
+	})
+	return links
+}
+
+// resolveURL resolves href against base
+func resolveURL(base, href string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+	return baseURL.ResolveReference(ref).String()
+}
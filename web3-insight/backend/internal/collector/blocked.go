@@ -0,0 +1,218 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// ErrBotChallenge is returned when a page only served an anti-bot challenge
+var ErrBotChallenge = errors.New("page is behind an anti-bot challenge")
+
+// maxArchivedPageBytes caps the size of pages read from the Wayback Machine
+const maxArchivedPageBytes = 10 << 20
+
+var (
+	// challengeMarkers appear on anti-bot interstitials (Cloudflare, DDoS-Guard, PerimeterX,
+	// Imperva, DataDome) but not on the pages behind them
+	challengeMarkers = []string{
+		"cf-browser-verification",
+		"/cdn-cgi/challenge-platform/",
+		"cf_chl_opt",
+		"<title>just a moment...</title>",
+		"attention required! | cloudflare",
+		"checking your browser before accessing",
+		"enable javascript and cookies to continue",
+		"ddos-guard",
+		"px-captcha",
+		"_incapsula_resource",
+		"captcha-delivery.com",
+		"please verify you are a human",
+	}
+	// noScriptMarkers are only telling on pages with almost no text
+	noScriptMarkers = []string{
+		"please enable javascript",
+		"you need to enable javascript to run this app",
+		"javascript is disabled in your browser",
+	}
+	consentMarkers = []string{
+		"consent.google.com",
+		"before you continue to",
+		"we value your privacy",
+		"consent-wall",
+		"manage your privacy settings",
+		"accept all cookies",
+	}
+	paywallMarkers = []string{
+		"subscribe to continue reading",
+		"subscribe to read the full",
+		"this article is for subscribers",
+		"this content is for subscribers",
+		"subscriber-only",
+		"to continue reading, subscribe",
+		"already a subscriber? sign in",
+		"class=\"paywall",
+		"id=\"paywall",
+	}
+	// Schema.org markup for paywalled articles, and the content tier meta tag of news sites
+	notFreePattern    = regexp.MustCompile(`"isaccessibleforfree"\s*:\s*"?false`)
+	lockedTierPattern = regexp.MustCompile(`<meta[^>]+content_tier[^>]+content="(locked|metered)"`)
+)
+
+// detectBlock reports why a fetched page does not show its article: model.ContentBlockChallenge,
+// ContentBlockConsent or ContentBlockPaywall, or "" when the content looks complete. Wording
+// that also shows up on readable pages only counts when little text was extracted.
+func detectBlock(htmlContent string, extracted *ExtractedContent) string {
+	lower := strings.ToLower(htmlContent)
+	textLen := 0
+	if extracted != nil {
+		textLen = len([]rune(strings.Join(strings.Fields(extracted.Content), " ")))
+	}
+
+	if textLen < 2000 && containsAny(lower, challengeMarkers) {
+		return model.ContentBlockChallenge
+	}
+	if textLen < 300 && containsAny(lower, noScriptMarkers) {
+		return model.ContentBlockChallenge
+	}
+	if notFreePattern.MatchString(lower) || lockedTierPattern.MatchString(lower) {
+		return model.ContentBlockPaywall
+	}
+	if textLen < 1000 && containsAny(lower, consentMarkers) {
+		return model.ContentBlockConsent
+	}
+	if textLen < 1500 && containsAny(lower, paywallMarkers) {
+		return model.ContentBlockPaywall
+	}
+	return ""
+}
+
+// isChallengeResponse reports whether an error response is an anti-bot interstitial
+func isChallengeResponse(status int, body []byte) bool {
+	switch status {
+	case http.StatusForbidden, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return containsAny(strings.ToLower(string(body)), challengeMarkers)
+	}
+	return false
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchiveFetcher reads pages from their latest Wayback Machine snapshot. A nil fetcher means
+// the fallback is off.
+type ArchiveFetcher struct {
+	client *http.Client
+}
+
+// NewArchiveFetcherFromConfig returns a fetcher, or nil unless archive_fallback is on
+func NewArchiveFetcherFromConfig(cfg *config.CollectorConfig) *ArchiveFetcher {
+	if cfg == nil || !cfg.ArchiveFallback {
+		return nil
+	}
+	return &ArchiveFetcher{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Enabled reports whether blocked pages are retried from the archive
+func (a *ArchiveFetcher) Enabled() bool {
+	return a != nil
+}
+
+// Fetch returns the original HTML of the latest snapshot of a page and the snapshot's URL
+func (a *ArchiveFetcher) Fetch(ctx context.Context, pageURL string) (string, string, error) {
+	availability := "https://archive.org/wayback/available?url=" + url.QueryEscape(pageURL)
+	body, err := a.get(ctx, availability, 1<<20)
+	if err != nil {
+		return "", "", fmt.Errorf("wayback availability lookup failed: %w", err)
+	}
+
+	var resp struct {
+		ArchivedSnapshots struct {
+			Closest struct {
+				Available bool   `json:"available"`
+				Timestamp string `json:"timestamp"`
+				Status    string `json:"status"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", "", fmt.Errorf("failed to parse wayback availability: %w", err)
+	}
+	closest := resp.ArchivedSnapshots.Closest
+	if !closest.Available || closest.Timestamp == "" || closest.Status != "200" {
+		return "", "", fmt.Errorf("no archived snapshot of %s", pageURL)
+	}
+
+	// The id_ suffix serves the page as captured, without the Wayback toolbar or rewritten links
+	snapshot := fmt.Sprintf("https://web.archive.org/web/%sid_/%s", closest.Timestamp, pageURL)
+	page, err := a.get(ctx, snapshot, maxArchivedPageBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch snapshot: %w", err)
+	}
+	return string(page), snapshot, nil
+}
+
+func (a *ArchiveFetcher) get(ctx context.Context, target string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+// crawlArchived crawls the Wayback Machine's copy of a blocked page, failing if the archived
+// copy is blocked as well
+func (c *WebCrawler) crawlArchived(ctx context.Context, targetURL string) (*CrawlResult, error) {
+	if !c.archive.Enabled() {
+		return nil, errors.New("archive fallback is off")
+	}
+	htmlContent, snapshot, err := c.archive.Fetch(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
+	extracted, err := c.contentParser.Parse(htmlContent, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse archived content: %w", err)
+	}
+	if block := detectBlock(htmlContent, extracted); block != "" {
+		return nil, fmt.Errorf("archived copy is blocked too (%s)", block)
+	}
+
+	return &CrawlResult{
+		URL:         targetURL,
+		Title:       extracted.Title,
+		Content:     extracted.Content,
+		ContentHTML: extracted.ContentHTML,
+		Description: extracted.Description,
+		Language:    extracted.Language,
+		License:     extracted.License,
+		Links:       pageLinks(htmlContent, targetURL),
+		ArchivedURL: snapshot,
+		Confidence:  extracted.Confidence,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// ErrNotModified is returned when a conditional fetch finds the content unchanged
+var ErrNotModified = errors.New("content not modified since last fetch")
+
+// FetchValidatorMaxAge is how long validators of a URL that is no longer fetched are kept
+const FetchValidatorMaxAge = 90 * 24 * time.Hour
+
+// FetchCache stores the ETag, Last-Modified and body hash of fetched URLs so refetches can be
+// conditional and unchanged content is not parsed again. A nil cache fetches unconditionally.
+type FetchCache struct {
+	repo *repository.FetchValidatorRepository
+}
+
+// NewFetchCache creates a fetch cache backed by the fetch_validators table
+func NewFetchCache(repo *repository.FetchValidatorRepository) *FetchCache {
+	return &FetchCache{repo: repo}
+}
+
+// Prepare adds If-None-Match and If-Modified-Since headers for a URL fetched before
+func (c *FetchCache) Prepare(target string, header http.Header) {
+	if c == nil {
+		return
+	}
+	validator, err := c.repo.FindByURL(target)
+	if err != nil {
+		return
+	}
+	if validator.ETag != "" {
+		header.Set("If-None-Match", validator.ETag)
+	}
+	if validator.LastModified != "" {
+		header.Set("If-Modified-Since", validator.LastModified)
+	}
+}
+
+// Changed reports whether a response carries new content: false for 304 Not Modified or a body
+// identical to the last one. New validators of a 200 response are stored; their update time
+// records when the URL was last confirmed, for re-crawls.
+func (c *FetchCache) Changed(target string, status int, header http.Header, body []byte) bool {
+	if status == http.StatusNotModified {
+		c.Touch(target)
+		return false
+	}
+	if c == nil || status != http.StatusOK {
+		return true
+	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	changed := true
+	if previous, err := c.repo.FindByURL(target); err == nil && previous.ContentHash == hash {
+		changed = false
+	}
+
+	validator := &model.FetchValidator{
+		URL:          target,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		ContentHash:  hash,
+		UpdatedAt:    time.Now(),
+	}
+	if err := c.repo.Save(validator); err != nil {
+		log.Printf("Warning: failed to store fetch validators for %s: %v", target, err)
+	}
+	return changed
+}
+
+// Touch marks a URL's validators as just checked without changing them
+func (c *FetchCache) Touch(target string) {
+	if c == nil {
+		return
+	}
+	if err := c.repo.Touch(target); err != nil {
+		log.Printf("Warning: failed to update fetch validators for %s: %v", target, err)
+	}
+}
+
+// Children returns the child sitemaps last listed by a sitemap index
+func (c *FetchCache) Children(target string) []string {
+	if c == nil {
+		return nil
+	}
+	validator, err := c.repo.FindByURL(target)
+	if err != nil {
+		return nil
+	}
+	return validator.Children
+}
+
+// SetChildren records the child sitemaps a sitemap index lists, so they are followed while
+// the index itself is unchanged
+func (c *FetchCache) SetChildren(target string, children []string) {
+	if c == nil {
+		return
+	}
+	if err := c.repo.SetChildren(target, children); err != nil {
+		log.Printf("Warning: failed to store child sitemaps of %s: %v", target, err)
+	}
+}
+
+// Prune drops the validators of URLs not fetched within FetchValidatorMaxAge, returning how
+// many were dropped
+func (c *FetchCache) Prune() (int64, error) {
+	if c == nil {
+		return 0, nil
+	}
+	return c.repo.DeleteCheckedBefore(time.Now().Add(-FetchValidatorMaxAge))
+}
+
+// Forget drops the validators of a URL, so content that failed to be stored is fetched and
+// parsed again next time
+func (c *FetchCache) Forget(target string) {
+	if c == nil {
+		return
+	}
+	if err := c.repo.Delete(target); err != nil {
+		log.Printf("Warning: failed to drop fetch validators for %s: %v", target, err)
+	}
+}
@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+)
+
+// ThinContentChars is the content length below which a news item is taken to hold only a
+// feed summary rather than the article
+const ThinContentChars = 500
+
+// backfillPerDomain caps the pages fetched from one site per backfill run; the crawler waits
+// 30-60 seconds between requests to a domain
+const backfillPerDomain = 3
+
+// ContentBackfillResult counts the outcomes of a content backfill run. It is stored as the
+// result of the run's task record.
+type ContentBackfillResult struct {
+	Checked  int `json:"checked"`
+	Filled   int `json:"filled"`  // Items whose content was replaced by the page's
+	Thin     int `json:"thin"`    // Pages with no more text than the feed gave
+	Blocked  int `json:"blocked"` // Pages behind an anti-bot challenge, consent wall or paywall
+	Failed   int `json:"failed"`
+	Deferred int `json:"deferred"` // Candidates left for later runs by the per-domain cap
+}
+
+// BackfillThinContent crawls the source pages of up to limit RSS items fetched after since
+// whose content is shorter than minChars, and stores the page content in place of the feed
+// summary. Each item is tried once. The run is recorded as a content_backfill task with its
+// counts as the result.
+func (c *WebCrawler) BackfillThinContent(ctx context.Context, since time.Time, minChars, limit int) (*ContentBackfillResult, error) {
+	if minChars <= 0 {
+		minChars = ThinContentChars
+	}
+
+	// Fetch extra candidates so a feed with many thin items doesn't use up the run
+	candidates, err := c.newsRepo.FindThinContent(since, minChars, limit*backfillPerDomain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find items with thin content: %w", err)
+	}
+
+	result := &ContentBackfillResult{}
+	task := c.startContentBackfillTask(since, minChars, limit)
+	perDomain := make(map[string]int)
+	for i := range candidates {
+		item := &candidates[i]
+		domain := extractDomain(item.SourceURL)
+		if result.Checked >= limit || perDomain[domain] >= backfillPerDomain {
+			result.Deferred++
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		perDomain[domain]++
+		result.Checked++
+
+		before := len([]rune(item.Content))
+		fillErr := c.FillMissingContent(ctx, item)
+		filled := fillErr == nil && len([]rune(item.Content)) > before
+		switch {
+		case fillErr != nil:
+			log.Printf("Content backfill of %s failed: %v", item.SourceURL, fillErr)
+			result.Failed++
+		case item.ContentBlock != "":
+			result.Blocked++
+		case !filled:
+			result.Thin++
+		}
+		if filled {
+			result.Filled++
+		}
+
+		content := ""
+		if filled {
+			content = item.Content
+		}
+		if saveErr := c.newsRepo.FinishBackfill(item.ID, content, item.ContentBlock); saveErr != nil {
+			log.Printf("Failed to save content backfill of news %s: %v", item.ID, saveErr)
+		}
+	}
+
+	c.finishContentBackfillTask(task, result, err)
+	return result, err
+}
+
+// startContentBackfillTask records a running content_backfill task, returning nil if it can't
+func (c *WebCrawler) startContentBackfillTask(since time.Time, minChars, limit int) *model.Task {
+	if c.taskRepo == nil {
+		return nil
+	}
+	now := time.Now()
+	payload, _ := json.Marshal(map[string]interface{}{"since": since, "minChars": minChars, "limit": limit})
+	task := &model.Task{
+		Type:      model.TaskTypeContentBackfill,
+		Status:    model.TaskStatusRunning,
+		Payload:   payload,
+		StartedAt: &now,
+	}
+	if err := c.taskRepo.Create(task); err != nil {
+		log.Printf("Failed to record content backfill task: %v", err)
+		return nil
+	}
+	return task
+}
+
+// finishContentBackfillTask stores the counts of a run on its task record
+func (c *WebCrawler) finishContentBackfillTask(task *model.Task, result *ContentBackfillResult, runErr error) {
+	if task == nil {
+		return
+	}
+	now := time.Now()
+	task.CompletedAt = &now
+	task.Status = model.TaskStatusCompleted
+	if runErr != nil {
+		task.Status = model.TaskStatusFailed
+		task.Error = runErr.Error()
+	}
+	task.Result, _ = json.Marshal(result)
+	if err := c.taskRepo.Update(task); err != nil {
+		log.Printf("Failed to record content backfill task %s: %v", task.ID, err)
+	}
+}
@@ -1,18 +1,28 @@
 package collector
 
 import (
+	"log"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/user/web3-insight/internal/repository"
 )
 
+// crawlRuleTTL is how long loaded crawl rules are used before they are reloaded
+const crawlRuleTTL = time.Minute
+
 // ContentParser handles HTML content extraction and conversion
 type ContentParser struct {
 	converter *md.Converter
-	// Site-specific selectors for known sites
+	// Site-specific selectors, hot-loaded from the crawl_rules table
+	ruleRepo      *repository.CrawlRuleRepository
 	siteSelectors map[string]SiteSelector
+	loadedAt      time.Time
+	mu            sync.Mutex
 }
 
 // SiteSelector defines how to extract content from a specific site
@@ -20,32 +30,54 @@ type SiteSelector struct {
 	ContentSelector string   // CSS selector for main content
 	TitleSelector   string   // CSS selector for title
 	RemoveSelectors []string // Elements to remove before extraction
+	RenderMode      string   // model.RenderModeStatic or model.RenderModeAMP
 }
 
-// NewContentParser creates a new content parser
+// NewContentParser creates a new content parser. It has no site-specific selectors until
+// SetRuleRepository is called.
 func NewContentParser() *ContentParser {
 	converter := md.NewConverter("", true, nil)
 
 	return &ContentParser{
-		converter: converter,
-		siteSelectors: map[string]SiteSelector{
-			"blog.ethereum.org": {
-				ContentSelector: "article, .post-content, main",
-				TitleSelector:   "h1",
-				RemoveSelectors: []string{"nav", "footer", "aside", ".comments", ".share-buttons"},
-			},
-			"vitalik.eth.limo": {
-				ContentSelector: "article, .post, main",
-				TitleSelector:   "h1",
-				RemoveSelectors: []string{"nav", "footer"},
-			},
-			"paradigm.xyz": {
-				ContentSelector: "article, .content, main",
-				TitleSelector:   "h1",
-				RemoveSelectors: []string{"nav", "footer", "aside"},
-			},
-		},
+		converter:     converter,
+		siteSelectors: map[string]SiteSelector{},
+	}
+}
+
+// SetRuleRepository makes the parser load site selectors from crawl rules, reloading them
+// every minute so edits apply without a restart
+func (p *ContentParser) SetRuleRepository(repo *repository.CrawlRuleRepository) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ruleRepo = repo
+	p.loadedAt = time.Time{}
+}
+
+// SiteSelector returns the selectors of a domain (without www.), reloading stale rules
+func (p *ContentParser) SiteSelector(domain string) (SiteSelector, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ruleRepo != nil && time.Since(p.loadedAt) > crawlRuleTTL {
+		p.loadedAt = time.Now()
+		if rules, err := p.ruleRepo.List(); err != nil {
+			log.Printf("Warning: failed to load crawl rules, keeping %d loaded: %v", len(p.siteSelectors), err)
+		} else {
+			selectors := make(map[string]SiteSelector, len(rules))
+			for _, rule := range rules {
+				selectors[rule.Domain] = SiteSelector{
+					ContentSelector: rule.ContentSelector,
+					TitleSelector:   rule.TitleSelector,
+					RemoveSelectors: rule.RemoveSelectors,
+					RenderMode:      rule.RenderMode,
+				}
+			}
+			p.siteSelectors = selectors
+		}
 	}
+
+	selector, ok := p.siteSelectors[domain]
+	return selector, ok
 }
 
 // ExtractedContent represents parsed content from a web page
@@ -55,6 +87,8 @@ type ExtractedContent struct {
 	ContentHTML string // Original HTML content
 	Description string
 	Language    string
+	License     LicenseInfo
+	Confidence  float64 // 0-1, how likely Content is the article rather than page chrome
 }
 
 // Parse extracts content from HTML
@@ -67,6 +101,9 @@ func (p *ContentParser) Parse(html string, url string) (*ExtractedContent, error
 	// Get domain for site-specific handling
 	domain := extractDomain(url)
 
+	// License links usually sit in the footer, which is removed below
+	license := detectLicense(doc)
+
 	// Remove unwanted elements first
 	p.removeUnwantedElements(doc, domain)
 
@@ -95,11 +132,14 @@ func (p *ContentParser) Parse(html string, url string) (*ExtractedContent, error
 		ContentHTML: content.ContentHTML,
 		Description: description,
 		Language:    detectLanguage(markdown),
+		License:     license,
+		Confidence:  content.Confidence,
 	}, nil
 }
 
 type contentResult struct {
 	ContentHTML string
+	Confidence  float64
 }
 
 // removeUnwantedElements removes navigation, footer, ads, etc.
@@ -123,32 +163,29 @@ func (p *ContentParser) removeUnwantedElements(doc *goquery.Document, domain str
 	}
 
 	// Site-specific removals
-	if siteSelector, ok := p.siteSelectors[domain]; ok {
+	if siteSelector, ok := p.SiteSelector(domain); ok {
 		for _, selector := range siteSelector.RemoveSelectors {
 			doc.Find(selector).Remove()
 		}
 	}
 }
 
-// extractContent extracts the main content
+// extractContent extracts the main content. Selector matches that hold little text or mostly
+// links are skipped; without a usable match the content is picked by readability scoring.
 func (p *ContentParser) extractContent(doc *goquery.Document, domain string) contentResult {
-	var contentHTML string
-
 	// Try site-specific selector first
-	if siteSelector, ok := p.siteSelectors[domain]; ok {
+	if siteSelector, ok := p.SiteSelector(domain); ok {
 		if siteSelector.ContentSelector != "" {
 			sel := doc.Find(siteSelector.ContentSelector).First()
-			if sel.Length() > 0 {
-				contentHTML, _ = sel.Html()
-				if len(contentHTML) > 100 {
-					return contentResult{ContentHTML: contentHTML}
-				}
+			if sel.Length() > 0 && looksLikeContent(sel) {
+				contentHTML, _ := sel.Html()
+				return contentResult{ContentHTML: contentHTML, Confidence: confidenceSiteSelector}
 			}
 		}
 	}
 
 	// Generic extraction strategy
-	// Priority order: article > main > .content > .post > body
+	// Priority order: article > main > .content > .post
 	selectors := []string{
 		"article",
 		"main",
@@ -163,25 +200,28 @@ func (p *ContentParser) extractContent(doc *goquery.Document, domain string) con
 
 	for _, selector := range selectors {
 		sel := doc.Find(selector).First()
-		if sel.Length() > 0 {
+		if sel.Length() > 0 && looksLikeContent(sel) {
 			html, _ := sel.Html()
-			if len(html) > 100 {
-				return contentResult{ContentHTML: html}
-			}
+			return contentResult{ContentHTML: html, Confidence: confidenceGenericSelector}
 		}
 	}
 
+	if sel, confidence := readabilityContent(doc); sel != nil {
+		html, _ := sel.Html()
+		return contentResult{ContentHTML: html, Confidence: confidence}
+	}
+
 	// Fallback: use body
 	body := doc.Find("body")
-	contentHTML, _ = body.Html()
+	contentHTML, _ := body.Html()
 
-	return contentResult{ContentHTML: contentHTML}
+	return contentResult{ContentHTML: contentHTML, Confidence: confidenceBody}
 }
 
 // extractTitle extracts the page title
 func (p *ContentParser) extractTitle(doc *goquery.Document, domain string) string {
 	// Try site-specific selector
-	if siteSelector, ok := p.siteSelectors[domain]; ok {
+	if siteSelector, ok := p.SiteSelector(domain); ok {
 		if siteSelector.TitleSelector != "" {
 			title := doc.Find(siteSelector.TitleSelector).First().Text()
 			if title != "" {
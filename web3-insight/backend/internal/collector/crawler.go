@@ -2,25 +2,38 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
 )
 
-// WebCrawler handles web page crawling
+// ErrItemFiltered is returned when a crawled page is dropped by source filters
+var ErrItemFiltered = errors.New("item dropped by source filters")
+
+// WebCrawler handles web page crawling. It obeys robots.txt, including Crawl-delay, and can
+// discover article URLs from a site's sitemaps.
 type WebCrawler struct {
 	newsRepo      *repository.NewsRepository
+	taskRepo      *repository.TaskRepository
+	fetchCache    *FetchCache
 	contentParser *ContentParser
+	pdf           *PDFExtractor   // Reads .pdf URLs; nil leaves them uncrawlable
+	archive       *ArchiveFetcher // Retries blocked pages from the Wayback Machine; nil disables
 	rateLimiter   *RateLimiter
-	userAgents    []string
-	uaIndex       int
-	uaMutex       sync.Mutex
+	robots        *RobotsCache
+	client        *http.Client // Fetches sitemaps
+	// Sitemaps are small and few, so they are fetched faster than pages
+	sitemapLimiter *RateLimiter
 }
 
 // RateLimiter manages per-domain rate limiting
@@ -42,40 +55,58 @@ func NewRateLimiter(minDelay, maxDelay time.Duration) *RateLimiter {
 
 // Wait waits if necessary before making a request to the domain
 func (r *RateLimiter) Wait(domain string) {
+	r.WaitAtLeast(domain, 0)
+}
+
+// WaitAtLeast waits like Wait, but for at least delay (such as a robots.txt Crawl-delay)
+// since the last request to the domain
+func (r *RateLimiter) WaitAtLeast(domain string, delay time.Duration) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if delay < r.minDelay {
+		delay = r.minDelay
+	}
 	if lastTime, ok := r.lastRequest[domain]; ok {
 		elapsed := time.Since(lastTime)
-		if elapsed < r.minDelay {
-			time.Sleep(r.minDelay - elapsed)
+		if elapsed < delay {
+			time.Sleep(delay - elapsed)
 		}
 	}
 
 	r.lastRequest[domain] = time.Now()
 }
 
-// NewWebCrawler creates a new web crawler
-func NewWebCrawler(newsRepo *repository.NewsRepository) *WebCrawler {
+// NewWebCrawler creates a new web crawler. taskRepo holds the URLs queued by sitemap sources;
+// fetchCache makes refetches of pages and sitemaps conditional.
+func NewWebCrawler(newsRepo *repository.NewsRepository, taskRepo *repository.TaskRepository, fetchCache *FetchCache) *WebCrawler {
 	return &WebCrawler{
-		newsRepo:      newsRepo,
-		contentParser: NewContentParser(),
-		rateLimiter:   NewRateLimiter(30*time.Second, 60*time.Second),
-		userAgents: []string{
-			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
-		},
+		newsRepo:       newsRepo,
+		taskRepo:       taskRepo,
+		fetchCache:     fetchCache,
+		contentParser:  NewContentParser(),
+		rateLimiter:    NewRateLimiter(30*time.Second, 60*time.Second),
+		robots:         NewRobotsCache(),
+		client:         &http.Client{Timeout: 30 * time.Second},
+		sitemapLimiter: NewRateLimiter(2*time.Second, 5*time.Second),
 	}
 }
 
-// getNextUserAgent returns the next user agent in rotation
-func (c *WebCrawler) getNextUserAgent() string {
-	c.uaMutex.Lock()
-	defer c.uaMutex.Unlock()
-	ua := c.userAgents[c.uaIndex]
-	c.uaIndex = (c.uaIndex + 1) % len(c.userAgents)
-	return ua
+// SetCrawlRules makes the crawler's parser use the site rules of the crawl_rules table
+func (c *WebCrawler) SetCrawlRules(repo *repository.CrawlRuleRepository) {
+	c.contentParser.SetRuleRepository(repo)
+}
+
+// SetPDFExtractor lets the crawler ingest URLs ending in .pdf, such as whitepapers and audit
+// reports, as markdown with a heading per page
+func (c *WebCrawler) SetPDFExtractor(pdf *PDFExtractor) {
+	c.pdf = pdf
+}
+
+// SetArchiveFetcher makes the crawler retry pages that only show an anti-bot challenge,
+// consent wall or paywall from the Wayback Machine
+func (c *WebCrawler) SetArchiveFetcher(archive *ArchiveFetcher) {
+	c.archive = archive
 }
 
 // CrawlResult represents the result of crawling a URL
@@ -86,25 +117,187 @@ type CrawlResult struct {
 	ContentHTML string
 	Description string
 	Language    string
+	License     LicenseInfo
+	Links       []string // Absolute http(s) links on the page, without fragments
+	Blocked     string   // model.ContentBlockConsent or ContentBlockPaywall when the content is incomplete
+	Confidence  float64  // Extraction confidence, see ExtractedContent
+	ArchivedURL string   // Wayback Machine snapshot the content was read from, if the page was blocked
 	Error       error
 }
 
 // Crawl fetches and parses a single URL
 func (c *WebCrawler) Crawl(ctx context.Context, targetURL string) (*CrawlResult, error) {
+	return c.crawl(ctx, targetURL, false)
+}
+
+// CrawlIfModified refetches a URL crawled before with If-None-Match and If-Modified-Since,
+// returning ErrNotModified when the page is unchanged
+func (c *WebCrawler) CrawlIfModified(ctx context.Context, targetURL string) (*CrawlResult, error) {
+	return c.crawl(ctx, targetURL, true)
+}
+
+// crawl fetches and parses a URL, recording its validators for later conditional crawls
+func (c *WebCrawler) crawl(ctx context.Context, targetURL string, conditional bool) (*CrawlResult, error) {
+	if IsPDFURL(targetURL) {
+		return c.crawlPDF(ctx, targetURL, conditional)
+	}
+
 	result := &CrawlResult{URL: targetURL}
 
-	// Parse URL to get domain for rate limiting
-	parsedURL, err := url.Parse(targetURL)
+	htmlContent, changed, err := c.fetch(ctx, targetURL, conditional)
+	if errors.Is(err, ErrBotChallenge) {
+		if archived, archiveErr := c.crawlArchived(ctx, targetURL); archiveErr == nil {
+			log.Printf("Crawled %s from its archived copy %s", targetURL, archived.ArchivedURL)
+			return archived, nil
+		}
+	}
+	if err != nil {
+		result.Error = err
+		return result, err
+	}
+	if conditional && !changed {
+		return nil, ErrNotModified
+	}
+
+	if htmlContent == "" {
+		return nil, fmt.Errorf("no content received from URL")
+	}
+	result.Links = pageLinks(htmlContent, targetURL)
+
+	// Sites that render articles client-side are read from their AMP version
+	if selector, ok := c.contentParser.SiteSelector(extractDomain(targetURL)); ok && selector.RenderMode == model.RenderModeAMP {
+		if ampURL := ampLink(htmlContent, targetURL); ampURL != "" {
+			if ampHTML, _, err := c.fetch(ctx, ampURL, false); err == nil && ampHTML != "" {
+				htmlContent = ampHTML
+			} else if err != nil {
+				log.Printf("AMP fetch failed for %s, using the page itself: %v", targetURL, err)
+			}
+		}
+	}
+
+	// Parse content
+	extracted, err := c.contentParser.Parse(htmlContent, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content: %w", err)
+	}
+
+	result.Title = extracted.Title
+	result.Content = extracted.Content
+	result.ContentHTML = extracted.ContentHTML
+	result.Description = extracted.Description
+	result.Language = extracted.Language
+	result.License = extracted.License
+	result.Confidence = extracted.Confidence
+
+	if block := detectBlock(htmlContent, extracted); block != "" {
+		// Refetch the page next time instead of trusting validators of the wall
+		c.fetchCache.Forget(targetURL)
+
+		archived, archiveErr := c.crawlArchived(ctx, targetURL)
+		if archiveErr == nil {
+			log.Printf("Crawled %s from its archived copy %s (page showed a %s)", targetURL, archived.ArchivedURL, block)
+			archived.Links = result.Links
+			return archived, nil
+		}
+		if c.archive.Enabled() {
+			log.Printf("Archive fallback failed for %s: %v", targetURL, archiveErr)
+		}
+
+		switch block {
+		case model.ContentBlockChallenge:
+			return nil, fmt.Errorf("%w: %s", ErrBotChallenge, targetURL)
+		case model.ContentBlockConsent:
+			// The extracted text is the consent dialog itself
+			result.Content = ""
+			result.ContentHTML = ""
+		}
+		result.Blocked = block
+	}
+
+	return result, nil
+}
+
+// Type returns the data source type handled by this collector
+func (c *WebCrawler) Type() string {
+	return model.DataSourceTypeCrawl
+}
+
+// Validate crawls the source page to check that content can be extracted. Sitemap sources
+// are checked by reading their sitemaps instead.
+func (c *WebCrawler) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	if _, err := ParseItemFilter(source.Config); err != nil {
+		return nil, err
+	}
+
+	if config := parseCrawlConfig(source); config.Mode == CrawlModeSitemap {
+		discovered, _, err := c.discoverSitemapURLs(ctx, source, config, false)
+		if err != nil {
+			return nil, err
+		}
+		return &ValidationResult{
+			Title:       source.URL,
+			Description: fmt.Sprintf("%d recent URLs in sitemaps", len(discovered)),
+			ItemCount:   len(discovered),
+		}, nil
+	} else if config.Mode != CrawlModePage {
+		return nil, fmt.Errorf("unsupported crawl mode: %s", config.Mode)
+	}
+
+	crawled, err := c.Crawl(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidationResult{
+		Title:       crawled.Title,
+		Description: crawled.Description,
+		ItemCount:   1,
+	}, nil
+}
+
+// Collect crawls the source page and stores it as a news item, or in sitemap mode crawls
+// article URLs queued from the site's sitemaps
+func (c *WebCrawler) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	if source.Type != model.DataSourceTypeCrawl {
+		return nil, fmt.Errorf("data source is not crawl type: %s", source.Type)
+	}
+
+	filter, err := ParseItemFilter(source.Config)
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+	if config := parseCrawlConfig(source); config.Mode == CrawlModeSitemap {
+		return c.collectSitemap(ctx, source, config, filter)
 	}
 
-	// Wait for rate limit
-	c.rateLimiter.Wait(parsedURL.Host)
+	result := &CollectResult{SourceID: source.ID, ItemsFound: 1}
+	if existing, err := c.newsRepo.FindBySourceURL(source.URL); err == nil && existing != nil {
+		return result, nil
+	}
+
+	if _, err := c.CrawlAndSaveFiltered(ctx, source.URL, source.Name, filter); err != nil {
+		if err == ErrItemFiltered {
+			result.ItemsFiltered++
+			return result, nil
+		}
+		result.ItemsFailed++
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew++
+
+	return result, nil
+}
+
+// fetch downloads a page after checking robots.txt and waiting out the domain's rate limit.
+// changed is false when a conditional fetch finds the page unchanged.
+func (c *WebCrawler) fetch(ctx context.Context, targetURL string, conditional bool) (string, bool, error) {
+	if err := c.waitTurn(ctx, targetURL); err != nil {
+		return "", false, err
+	}
 
 	// Create collector
 	collector := colly.NewCollector(
-		colly.UserAgent(c.getNextUserAgent()),
+		colly.UserAgent(crawlerUserAgent),
 		colly.AllowURLRevisit(),
 	)
 
@@ -112,50 +305,153 @@ func (c *WebCrawler) Crawl(ctx context.Context, targetURL string) (*CrawlResult,
 	collector.SetRequestTimeout(30 * time.Second)
 
 	var htmlContent string
+	var crawlErr error
+	changed := true
+	notModified := false
+
+	if conditional {
+		collector.OnRequest(func(r *colly.Request) {
+			c.fetchCache.Prepare(targetURL, *r.Headers)
+		})
+	}
 
 	// Handle response
 	collector.OnResponse(func(r *colly.Response) {
 		htmlContent = string(r.Body)
+		changed = c.fetchCache.Changed(targetURL, r.StatusCode, *r.Headers, r.Body)
 	})
 
-	// Handle errors
+	// Handle errors; colly reports 304 Not Modified as one
 	collector.OnError(func(r *colly.Response, err error) {
-		result.Error = fmt.Errorf("crawl failed: %w (status: %d)", err, r.StatusCode)
+		if r.StatusCode == http.StatusNotModified {
+			c.fetchCache.Touch(targetURL)
+			changed = false
+			notModified = true
+			return
+		}
+		if isChallengeResponse(r.StatusCode, r.Body) {
+			crawlErr = fmt.Errorf("%w: %s (status: %d)", ErrBotChallenge, targetURL, r.StatusCode)
+			return
+		}
+		crawlErr = fmt.Errorf("crawl failed: %w (status: %d)", err, r.StatusCode)
 	})
 
-	// Visit URL
-	if err := collector.Visit(targetURL); err != nil {
-		return nil, fmt.Errorf("failed to visit URL: %w", err)
-	}
+	// Visit URL; it also returns the errors handled above
+	visitErr := collector.Visit(targetURL)
 
 	// Wait for collector to finish
 	collector.Wait()
 
-	if result.Error != nil {
-		return result, result.Error
+	if crawlErr != nil {
+		return "", false, crawlErr
+	}
+	if notModified {
+		return "", false, nil
+	}
+	if visitErr != nil {
+		return "", false, fmt.Errorf("failed to visit URL: %w", visitErr)
 	}
+	return htmlContent, changed, nil
+}
 
-	if htmlContent == "" {
-		return nil, fmt.Errorf("no content received from URL")
+// waitTurn checks robots.txt for a URL and waits out its domain's rate limit, including
+// any robots.txt crawl delay
+func (c *WebCrawler) waitTurn(ctx context.Context, targetURL string) error {
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
 	}
 
-	// Parse content
-	extracted, err := c.contentParser.Parse(htmlContent, targetURL)
+	robots := c.robots.Rules(ctx, parsedURL.Scheme, parsedURL.Host)
+	if !robots.Allowed(parsedURL.RequestURI()) {
+		return fmt.Errorf("%w: %s", ErrDisallowedByRobots, targetURL)
+	}
+
+	c.rateLimiter.WaitAtLeast(parsedURL.Host, robots.CrawlDelay)
+	return nil
+}
+
+// crawlPDF downloads a PDF and converts its text to markdown with a heading per page
+func (c *WebCrawler) crawlPDF(ctx context.Context, targetURL string, conditional bool) (*CrawlResult, error) {
+	if !c.pdf.Enabled() {
+		return nil, fmt.Errorf("cannot crawl %s: PDF text extraction is not configured", targetURL)
+	}
+	if err := c.waitTurn(ctx, targetURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse content: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", crawlerUserAgent)
+	if conditional {
+		c.fetchCache.Prepare(targetURL, req.Header)
+	}
+	resp, err := c.pdf.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download PDF: %w", err)
+	}
+	defer resp.Body.Close()
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		c.fetchCache.Touch(targetURL)
+		return nil, ErrNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PDF download returned status %d", resp.StatusCode)
+	}
+	data, err := readPDF(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if changed := c.fetchCache.Changed(targetURL, resp.StatusCode, resp.Header, data); conditional && !changed {
+		return nil, ErrNotModified
 	}
 
-	result.Title = extracted.Title
-	result.Content = extracted.Content
-	result.ContentHTML = extracted.ContentHTML
-	result.Description = extracted.Description
-	result.Language = extracted.Language
+	doc, err := c.pdf.ExtractDocument(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(strings.Join(doc.Pages, "")) == "" {
+		return nil, fmt.Errorf("PDF %s has no extractable text", targetURL)
+	}
 
-	return result, nil
+	content := doc.Markdown()
+	return &CrawlResult{
+		URL:         targetURL,
+		Title:       doc.TitleOr(targetURL),
+		Content:     content,
+		Description: previewExcerpt(doc.Pages[0]),
+		Language:    doc.Language,
+		License:     LicenseInfo{Author: doc.Author},
+		Confidence:  confidencePDF,
+	}, nil
 }
 
-// CrawlAndSave crawls a URL and saves it to the database
-func (c *WebCrawler) CrawlAndSave(ctx context.Context, targetURL string, sourceName string) (*model.NewsItem, error) {
+// ampLink returns the absolute URL of a page's rel="amphtml" version, if it has one
+func ampLink(htmlContent, pageURL string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return ""
+	}
+	href, ok := doc.Find("link[rel='amphtml']").First().Attr("href")
+	if !ok || href == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := base.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return ref.String()
+}
+
+// CrawlAndSaveFiltered crawls a URL and saves it unless the filter drops it.
+// Returns a nil item and ErrItemFiltered when the page was filtered out.
+func (c *WebCrawler) CrawlAndSaveFiltered(ctx context.Context, targetURL string, sourceName string, filter *ItemFilter) (*model.NewsItem, error) {
 	// Check if already exists
 	existing, err := c.newsRepo.FindBySourceURL(targetURL)
 	if err == nil && existing != nil {
@@ -169,23 +465,38 @@ func (c *WebCrawler) CrawlAndSave(ctx context.Context, targetURL string, sourceN
 		return nil, err
 	}
 
-	// Create news item
+	newsItem, _, err := c.save(targetURL, result, sourceName, filter)
+	return newsItem, err
+}
+
+// save stores a crawled page as a news item unless the filter drops it, returning
+// ErrItemFiltered then; created is false if the URL was already stored
+func (c *WebCrawler) save(targetURL string, result *CrawlResult, sourceName string, filter *ItemFilter) (*model.NewsItem, bool, error) {
+	if ok, reason := filter.Allow(result.Title, result.Content); !ok {
+		log.Printf("Filtered crawled page %s (%s)", targetURL, reason)
+		return nil, false, ErrItemFiltered
+	}
+
 	newsItem := &model.NewsItem{
-		Title:          result.Title,
-		OriginalTitle:  result.Title,
-		Content:        result.Content,
-		Summary:        result.Description,
-		SourceURL:      targetURL,
-		SourceName:     sourceName,
-		SourceLanguage: result.Language,
-		FetchedAt:      time.Now(),
-		Processed:      false,
-	}
-
-	// Save to database
+		Title:                result.Title,
+		OriginalTitle:        result.Title,
+		Content:              result.Content,
+		Summary:              result.Description,
+		SourceURL:            targetURL,
+		SourceName:           sourceName,
+		SourceLanguage:       result.Language,
+		Author:               result.License.Author,
+		License:              result.License.License,
+		LicenseURL:           result.License.LicenseURL,
+		ContentBlock:         result.Blocked,
+		ExtractionConfidence: &result.Confidence,
+		FetchedAt:            time.Now(),
+		Processed:            false,
+	}
+
 	created, err := c.newsRepo.CreateOrIgnore(newsItem)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save news item: %w", err)
+		return nil, false, fmt.Errorf("failed to save news item: %w", err)
 	}
 
 	if !created {
@@ -194,12 +505,12 @@ func (c *WebCrawler) CrawlAndSave(ctx context.Context, targetURL string, sourceN
 
 	log.Printf("Crawled and saved: %s", result.Title)
 
-	return newsItem, nil
+	return newsItem, created, nil
 }
 
 // FillMissingContent crawls URLs that have no content (e.g., from RSS feeds with only summaries)
 func (c *WebCrawler) FillMissingContent(ctx context.Context, item *model.NewsItem) error {
-	if item.Content != "" && len(item.Content) > 500 {
+	if len([]rune(item.Content)) >= ThinContentChars {
 		// Already has sufficient content
 		return nil
 	}
@@ -209,8 +520,11 @@ func (c *WebCrawler) FillMissingContent(ctx context.Context, item *model.NewsIte
 		return err
 	}
 
-	// Update the item
-	item.Content = result.Content
+	// Update the item; a paywall snippet only replaces a shorter one
+	item.ContentBlock = result.Blocked
+	if result.Blocked == "" || len(result.Content) > len(item.Content) {
+		item.Content = result.Content
+	}
 	if item.Title == "" {
 		item.Title = result.Title
 	}
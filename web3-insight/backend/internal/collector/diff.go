@@ -0,0 +1,190 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the line-matching table of a diff; larger changes are reported as the
+// whole changed region being replaced
+const maxDiffCells = 4_000_000
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is one line of a line diff: ' ' kept, '-' removed or '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// ContentDiff is a line diff between two versions of a markdown document
+type ContentDiff struct {
+	ops          []diffOp
+	AddedLines   int
+	RemovedLines int
+	ChangedChars int // Characters on added and removed lines, ignoring whitespace
+}
+
+// DiffContent compares two versions of a document line by line
+func DiffContent(oldText, newText string) *ContentDiff {
+	oldLines := strings.Split(strings.TrimSpace(oldText), "\n")
+	newLines := strings.Split(strings.TrimSpace(newText), "\n")
+	d := &ContentDiff{ops: diffLines(oldLines, newLines)}
+	for _, op := range d.ops {
+		switch op.kind {
+		case '+':
+			d.AddedLines++
+		case '-':
+			d.RemovedLines++
+		default:
+			continue
+		}
+		d.ChangedChars += len([]rune(strings.Join(strings.Fields(op.line), "")))
+	}
+	return d
+}
+
+// Changed reports whether any line was added or removed
+func (d *ContentDiff) Changed() bool {
+	return d.AddedLines > 0 || d.RemovedLines > 0
+}
+
+// Unified renders the diff in unified format with a few lines of context per hunk
+func (d *ContentDiff) Unified() string {
+	var sb strings.Builder
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(d.ops); {
+		// Find the next change
+		first := start
+		for first < len(d.ops) && d.ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(d.ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within two contexts of each other
+		hunkStart := max(first-diffContext, start)
+		end, kept := first, 0
+		for end < len(d.ops) {
+			if d.ops[end].kind == ' ' {
+				kept++
+				if kept > 2*diffContext {
+					break
+				}
+			} else {
+				kept = 0
+			}
+			end++
+		}
+		hunkEnd := end - max(kept-diffContext, 0)
+
+		// Line numbers of the hunk start
+		for _, op := range d.ops[start:hunkStart] {
+			oldLine, newLine = advanceLines(op, oldLine, newLine)
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range d.ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range d.ops[hunkStart:hunkEnd] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+			oldLine, newLine = advanceLines(op, oldLine, newLine)
+		}
+		start = hunkEnd
+	}
+	return sb.String()
+}
+
+func advanceLines(op diffOp, oldLine, newLine int) (int, int) {
+	if op.kind != '+' {
+		oldLine++
+	}
+	if op.kind != '-' {
+		newLine++
+	}
+	return oldLine, newLine
+}
+
+// diffLines returns a minimal line diff via the longest common subsequence of the lines
+// between the common prefix and suffix
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, line := range midA {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range midB {
+			ops = append(ops, diffOp{'+', line})
+		}
+	} else {
+		ops = append(ops, lcsDiff(midA, midB)...)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+func lcsDiff(a, b []string) []diffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
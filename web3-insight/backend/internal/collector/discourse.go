@@ -0,0 +1,447 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Discourse source defaults
+const (
+	defaultDiscourseTopics  = 30
+	defaultDiscourseReplies = 3
+)
+
+// A topic's first page carries about 20 posts; the rest are loaded this many at a time, up
+// to a cap, so the top replies of long threads are ranked on all of them
+const (
+	discoursePostsPerRequest = 20
+	maxDiscoursePosts        = 500
+)
+
+// DiscourseCollector ingests topics of Discourse forums, such as governance forums, through
+// their JSON API. A topic is stored with its opening post and its most liked replies.
+type DiscourseCollector struct {
+	newsRepo  *repository.NewsRepository
+	client    *http.Client
+	converter *md.Converter
+}
+
+// NewDiscourseCollector creates a new Discourse forum collector
+func NewDiscourseCollector(newsRepo *repository.NewsRepository) *DiscourseCollector {
+	return &DiscourseCollector{
+		newsRepo:  newsRepo,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		converter: md.NewConverter("", true, nil),
+	}
+}
+
+// DiscourseConfig holds Discourse-specific configuration
+type DiscourseConfig struct {
+	DefaultCategory string   `json:"defaultCategory,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Categories      []string `json:"categories,omitempty"` // Forum category slugs (parent/child for subcategories) or IDs to watch; all topics if empty
+	MinReplies      int      `json:"minReplies,omitempty"` // Topics with fewer replies are skipped until they get more
+	TopReplies      int      `json:"topReplies,omitempty"` // Most liked replies stored with a topic; defaults to 3
+	MaxTopics       int      `json:"maxTopics,omitempty"`  // Most recently active topics looked at per category; defaults to 30
+}
+
+// discourseTopic is a topic of a topic list
+type discourseTopic struct {
+	ID         int       `json:"id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	PostsCount int       `json:"posts_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	Pinned     bool      `json:"pinned"`
+	Archetype  string    `json:"archetype"`
+	Tags       []any     `json:"tags"` // Names, or objects with a name on newer Discourse versions
+}
+
+// discoursePost is a post of a topic
+type discoursePost struct {
+	Username       string  `json:"username"`
+	Cooked         string  `json:"cooked"` // Rendered HTML
+	PostNumber     int     `json:"post_number"`
+	Score          float64 `json:"score"`
+	ActionsSummary []struct {
+		ID    int `json:"id"`
+		Count int `json:"count"`
+	} `json:"actions_summary"`
+}
+
+// likes returns the like count of a post
+func (p discoursePost) likes() int {
+	for _, action := range p.ActionsSummary {
+		if action.ID == 2 { // Like
+			return action.Count
+		}
+	}
+	return 0
+}
+
+// Type returns the data source type handled by this collector
+func (c *DiscourseCollector) Type() string {
+	return model.DataSourceTypeDiscourse
+}
+
+// Validate checks that the source URL is a Discourse forum and its watched categories exist
+func (c *DiscourseCollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	base, err := discourseBase(source.URL)
+	if err != nil {
+		return nil, err
+	}
+	var about struct {
+		About struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"about"`
+	}
+	if err := c.getJSON(ctx, base+"/about.json", &about); err != nil {
+		return nil, fmt.Errorf("not a Discourse forum: %w", err)
+	}
+	topics, err := c.listTopics(ctx, base, parseDiscourseConfig(source))
+	if err != nil {
+		return nil, err
+	}
+	return &ValidationResult{
+		Title:       about.About.Title,
+		Description: about.About.Description,
+		ItemCount:   len(topics),
+	}, nil
+}
+
+// Collect fetches new topics with their top replies and stores them as news items
+func (c *DiscourseCollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+
+	if source.Type != model.DataSourceTypeDiscourse {
+		return nil, fmt.Errorf("data source is not discourse type: %s", source.Type)
+	}
+
+	config := parseDiscourseConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+	base, err := discourseBase(source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := c.listTopics(ctx, base, config)
+	if err != nil {
+		return nil, err
+	}
+	result.ItemsFound = len(topics)
+
+	var newsItems []model.NewsItem
+	for _, topic := range topics {
+		if existing, err := c.newsRepo.FindBySourceURL(topicURL(base, topic)); err == nil && existing != nil {
+			continue
+		}
+		newsItem, err := c.fetchTopic(ctx, base, topic, source.Name, config)
+		if err != nil {
+			log.Printf("Failed to fetch topic %s: %v", topicURL(base, topic), err)
+			result.ItemsFailed++
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			log.Printf("Filtered item from %s: %s (%s)", source.Name, newsItem.Title, reason)
+			result.ItemsFiltered++
+			continue
+		}
+		newsItems = append(newsItems, *newsItem)
+	}
+
+	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew = newCount
+
+	log.Printf("Discourse sync completed for %s: found=%d, new=%d, filtered=%d, failed=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered, result.ItemsFailed)
+
+	return result, nil
+}
+
+// Preview lists the topics a sync would store, judging filters on titles only
+func (c *DiscourseCollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	config := parseDiscourseConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+	base, err := discourseBase(source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := c.listTopics(ctx, base, config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(topics),
+		Items:      make([]PreviewItem, 0, len(topics)),
+	}
+	for _, topic := range topics {
+		createdAt := topic.CreatedAt
+		preview := PreviewItem{
+			Title:       topic.Title,
+			URL:         topicURL(base, topic),
+			PublishedAt: &createdAt,
+			Excerpt:     fmt.Sprintf("%d replies", topic.PostsCount-1),
+			Language:    config.Language,
+		}
+		if ok, reason := filter.Allow(topic.Title, ""); !ok {
+			preview.Filtered = true
+			preview.FilterReason = reason
+			result.ItemsFiltered++
+		} else if existing, err := c.newsRepo.FindBySourceURL(preview.URL); err == nil && existing != nil {
+			preview.Exists = true
+		} else {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// parseDiscourseConfig reads a source's config, applying defaults
+func parseDiscourseConfig(source *model.DataSource) DiscourseConfig {
+	var config DiscourseConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			log.Printf("Warning: failed to parse discourse config: %v", err)
+		}
+	}
+	if config.TopReplies <= 0 {
+		config.TopReplies = defaultDiscourseReplies
+	}
+	if config.MaxTopics <= 0 {
+		config.MaxTopics = defaultDiscourseTopics
+	}
+	return config
+}
+
+// discourseBase returns the forum root of a source URL
+func discourseBase(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid URL: %s", rawURL)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// topicURL returns the canonical URL of a topic
+func topicURL(base string, topic discourseTopic) string {
+	return fmt.Sprintf("%s/t/%s/%d", base, topic.Slug, topic.ID)
+}
+
+// listTopics returns the most recently active regular topics of the watched categories with
+// enough replies. Listing by activity rather than creation brings back older topics once they
+// reach MinReplies.
+func (c *DiscourseCollector) listTopics(ctx context.Context, base string, config DiscourseConfig) ([]discourseTopic, error) {
+	paths := []string{"/latest.json"}
+	if len(config.Categories) > 0 {
+		paths = paths[:0]
+		for _, category := range config.Categories {
+			paths = append(paths, "/c/"+strings.Trim(category, "/")+"/l/latest.json")
+		}
+	}
+
+	seen := make(map[int]bool)
+	var topics []discourseTopic
+	for _, path := range paths {
+		var list struct {
+			TopicList struct {
+				Topics []discourseTopic `json:"topics"`
+			} `json:"topic_list"`
+		}
+		if err := c.getJSON(ctx, base+path, &list); err != nil {
+			return nil, fmt.Errorf("failed to list topics: %w", err)
+		}
+		listed := 0
+		for _, topic := range list.TopicList.Topics {
+			if listed >= config.MaxTopics {
+				break
+			}
+			listed++
+			if seen[topic.ID] || topic.Pinned || (topic.Archetype != "" && topic.Archetype != "regular") {
+				continue
+			}
+			if topic.PostsCount-1 < config.MinReplies {
+				continue
+			}
+			seen[topic.ID] = true
+			topics = append(topics, topic)
+		}
+	}
+	return topics, nil
+}
+
+// fetchTopic loads a topic's posts and converts the opening post and top replies to a news item
+func (c *DiscourseCollector) fetchTopic(ctx context.Context, base string, topic discourseTopic, sourceName string, config DiscourseConfig) (*model.NewsItem, error) {
+	posts, err := c.topicPosts(ctx, base, topic.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var opening *discoursePost
+	var replies []discoursePost
+	for i, post := range posts {
+		if post.PostNumber == 1 {
+			opening = &posts[i]
+		} else if post.Cooked != "" {
+			replies = append(replies, post)
+		}
+	}
+	if opening == nil {
+		return nil, fmt.Errorf("topic %d has no opening post", topic.ID)
+	}
+
+	sort.SliceStable(replies, func(i, j int) bool {
+		if replies[i].likes() != replies[j].likes() {
+			return replies[i].likes() > replies[j].likes()
+		}
+		return replies[i].Score > replies[j].Score
+	})
+	if len(replies) > config.TopReplies {
+		replies = replies[:config.TopReplies]
+	}
+
+	var content strings.Builder
+	content.WriteString(c.markdown(opening.Cooked, base))
+	if len(replies) > 0 {
+		content.WriteString("\n\n## Top replies\n")
+		for _, reply := range replies {
+			fmt.Fprintf(&content, "\n**%s** (%d likes):\n\n%s\n", reply.Username, reply.likes(), c.markdown(reply.Cooked, base))
+		}
+	}
+
+	createdAt := topic.CreatedAt
+	newsItem := &model.NewsItem{
+		Title:          topic.Title,
+		OriginalTitle:  topic.Title,
+		Content:        content.String(),
+		SourceURL:      topicURL(base, topic),
+		SourceName:     sourceName,
+		SourceLanguage: config.Language,
+		Author:         opening.Username,
+		Category:       config.DefaultCategory,
+		Tags:           topicTags(topic.Tags),
+		PublishedAt:    &createdAt,
+		FetchedAt:      time.Now(),
+	}
+	if newsItem.SourceLanguage == "" {
+		newsItem.SourceLanguage = detectLanguage(newsItem.Content)
+	}
+	return newsItem, nil
+}
+
+// topicPosts loads the posts of a topic: those of its first page, then the rest of its post
+// stream up to maxDiscoursePosts
+func (c *DiscourseCollector) topicPosts(ctx context.Context, base string, topicID int) ([]discoursePost, error) {
+	var detail struct {
+		PostStream struct {
+			Posts  []discoursePost `json:"posts"`
+			Stream []int           `json:"stream"` // IDs of all posts, in order
+		} `json:"post_stream"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/t/%d.json", base, topicID), &detail); err != nil {
+		return nil, err
+	}
+
+	posts := detail.PostStream.Posts
+	stream := detail.PostStream.Stream
+	if len(stream) > maxDiscoursePosts {
+		stream = stream[:maxDiscoursePosts]
+	}
+	if len(stream) <= len(posts) {
+		return posts, nil
+	}
+	for start := len(posts); start < len(stream); start += discoursePostsPerRequest {
+		end := start + discoursePostsPerRequest
+		if end > len(stream) {
+			end = len(stream)
+		}
+		query := url.Values{}
+		for _, id := range stream[start:end] {
+			query.Add("post_ids[]", fmt.Sprint(id))
+		}
+		var page struct {
+			PostStream struct {
+				Posts []discoursePost `json:"posts"`
+			} `json:"post_stream"`
+		}
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/t/%d/posts.json?%s", base, topicID, query.Encode()), &page); err != nil {
+			// Rank the replies loaded so far rather than losing the topic
+			log.Printf("Failed to load more posts of topic %d: %v", topicID, err)
+			break
+		}
+		posts = append(posts, page.PostStream.Posts...)
+	}
+	return posts, nil
+}
+
+// markdown converts a post's HTML, resolving forum-relative links
+func (c *DiscourseCollector) markdown(cooked, base string) string {
+	text, err := c.converter.ConvertString(cooked)
+	if err != nil {
+		return strings.TrimSpace(previewExcerpt(cooked))
+	}
+	text = strings.ReplaceAll(text, "](/", "]("+base+"/")
+	return strings.TrimSpace(text)
+}
+
+// topicTags returns tag names given as strings or as objects with a name
+func topicTags(tags []any) []string {
+	var names []string
+	for _, tag := range tags {
+		switch t := tag.(type) {
+		case string:
+			names = append(names, t)
+		case map[string]any:
+			if name, ok := t["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// getJSON fetches a Discourse API path into v
+func (c *DiscourseCollector) getJSON(ctx context.Context, target string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", req.URL.Path, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 20<<20)).Decode(v)
+}
@@ -0,0 +1,450 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"golang.org/x/net/html/charset"
+)
+
+// Email source defaults
+const (
+	defaultEmailSinceDays   = 7
+	defaultEmailMaxMessages = 50
+)
+
+// EmailCollector ingests newsletters, such as Bankless, The Defiant or 律动, from an IMAP
+// mailbox. The source URL names the mailbox (imaps://user@imap.example.com/INBOX) and only
+// messages from the configured senders are read; they are never marked as seen.
+type EmailCollector struct {
+	newsRepo *repository.NewsRepository
+	parser   *ContentParser
+}
+
+// NewEmailCollector creates a new IMAP newsletter collector
+func NewEmailCollector(newsRepo *repository.NewsRepository) *EmailCollector {
+	return &EmailCollector{
+		newsRepo: newsRepo,
+		parser:   NewContentParser(),
+	}
+}
+
+// EmailConfig holds email-specific configuration
+type EmailConfig struct {
+	DefaultCategory string   `json:"defaultCategory,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Senders         []string `json:"senders"`               // Sender addresses or domains (@bankless.com) to read newsletters from
+	PasswordEnv     string   `json:"passwordEnv"`           // Environment variable holding the mailbox password, kept out of the database; must start with WEB3_SOURCE_
+	SinceDays       int      `json:"sinceDays,omitempty"`   // Messages received in the last N days are looked at; defaults to 7
+	MaxMessages     int      `json:"maxMessages,omitempty"` // Newest messages read per sync; defaults to 50
+}
+
+// emailMessage is a parsed newsletter
+type emailMessage struct {
+	MessageID string
+	Subject   string
+	From      string
+	Date      *time.Time
+	HTML      string
+	Text      string
+}
+
+// Type returns the data source type handled by this collector
+func (c *EmailCollector) Type() string {
+	return model.DataSourceTypeEmail
+}
+
+// Validate checks that the mailbox can be opened and counts the matching messages
+func (c *EmailCollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	config := parseEmailConfig(source)
+	mailbox, err := parseMailboxURL(source.URL)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.open(ctx, mailbox, config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	uids, err := searchSenders(conn, config)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidationResult{
+		Title:       mailbox.User + "/" + mailbox.Name,
+		Description: "Newsletters from " + strings.Join(config.Senders, ", "),
+		ItemCount:   len(uids),
+	}, nil
+}
+
+// Collect reads new newsletters from the mailbox and stores them as news items
+func (c *EmailCollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+
+	if source.Type != model.DataSourceTypeEmail {
+		return nil, fmt.Errorf("data source is not email type: %s", source.Type)
+	}
+
+	config := parseEmailConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+
+	messages, err := c.fetchMessages(ctx, source, config)
+	if err != nil {
+		return nil, err
+	}
+	result.ItemsFound = len(messages)
+
+	var newsItems []model.NewsItem
+	for _, message := range messages {
+		newsItem := c.toNewsItem(message, source.Name, config)
+		if existing, err := c.newsRepo.FindBySourceURL(newsItem.SourceURL); err == nil && existing != nil {
+			continue
+		}
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			log.Printf("Filtered item from %s: %s (%s)", source.Name, newsItem.Title, reason)
+			result.ItemsFiltered++
+			continue
+		}
+		newsItems = append(newsItems, *newsItem)
+	}
+
+	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew = newCount
+
+	log.Printf("Email sync completed for %s: found=%d, new=%d, filtered=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered)
+
+	return result, nil
+}
+
+// Preview lists the newsletters a sync would store
+func (c *EmailCollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	config := parseEmailConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := c.fetchMessages(ctx, source, config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(messages),
+		Items:      make([]PreviewItem, 0, len(messages)),
+	}
+	for _, message := range messages {
+		newsItem := c.toNewsItem(message, source.Name, config)
+		preview := PreviewItem{
+			Title:       newsItem.Title,
+			URL:         newsItem.SourceURL,
+			PublishedAt: newsItem.PublishedAt,
+			Excerpt:     previewExcerpt(newsItem.Content),
+			Language:    newsItem.SourceLanguage,
+		}
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			preview.Filtered = true
+			preview.FilterReason = reason
+			result.ItemsFiltered++
+		} else if existing, err := c.newsRepo.FindBySourceURL(preview.URL); err == nil && existing != nil {
+			preview.Exists = true
+		} else {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// parseEmailConfig reads a source's config, applying defaults
+func parseEmailConfig(source *model.DataSource) EmailConfig {
+	var config EmailConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			log.Printf("Warning: failed to parse email config: %v", err)
+		}
+	}
+	if config.SinceDays <= 0 {
+		config.SinceDays = defaultEmailSinceDays
+	}
+	if config.MaxMessages <= 0 {
+		config.MaxMessages = defaultEmailMaxMessages
+	}
+	return config
+}
+
+// mailboxURL is a parsed imap:// or imaps:// source URL
+type mailboxURL struct {
+	Addr     string
+	StartTLS bool // imap:// connects in plain text and upgrades with STARTTLS before logging in
+	User     string
+	Name     string
+}
+
+// parseMailboxURL reads the server, user and mailbox of a source URL
+func parseMailboxURL(rawURL string) (*mailboxURL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid mailbox URL, expected imaps://user@host/mailbox: %s", rawURL)
+	}
+	mailbox := &mailboxURL{User: u.User.Username(), Name: strings.Trim(u.Path, "/")}
+	if mailbox.Name == "" {
+		mailbox.Name = "INBOX"
+	}
+	port := "993"
+	switch u.Scheme {
+	case "imaps":
+	case "imap":
+		mailbox.StartTLS = true
+		port = "143"
+	default:
+		return nil, fmt.Errorf("unsupported mailbox scheme: %s", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	mailbox.Addr = net.JoinHostPort(u.Hostname(), port)
+	return mailbox, nil
+}
+
+// open logs in to the mailbox server and opens the mailbox read-only
+func (c *EmailCollector) open(ctx context.Context, mailbox *mailboxURL, config EmailConfig) (*imapConn, error) {
+	if len(config.Senders) == 0 {
+		return nil, fmt.Errorf("no newsletter senders configured")
+	}
+	if config.PasswordEnv == "" {
+		return nil, fmt.Errorf("passwordEnv is not configured")
+	}
+	// Sources are set through the API, so any other variable could be sent in LOGIN to a
+	// server of the caller's choosing
+	if !strings.HasPrefix(config.PasswordEnv, SourceSecretPrefix) {
+		return nil, fmt.Errorf("passwordEnv %s may not be read; source secrets must start with %s", config.PasswordEnv, SourceSecretPrefix)
+	}
+	password := os.Getenv(config.PasswordEnv)
+	if password == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", config.PasswordEnv)
+	}
+
+	conn, err := dialIMAP(ctx, mailbox.Addr, mailbox.StartTLS)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Login(mailbox.User, password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Examine(mailbox.Name); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// searchSenders returns the UIDs of recent messages from any configured sender, newest first
+func searchSenders(conn *imapConn, config EmailConfig) ([]uint32, error) {
+	since := time.Now().AddDate(0, 0, -config.SinceDays)
+	seen := make(map[uint32]bool)
+	var uids []uint32
+	for _, sender := range config.Senders {
+		found, err := conn.SearchFrom(strings.TrimSpace(sender), since)
+		if err != nil {
+			return nil, err
+		}
+		for _, uid := range found {
+			if !seen[uid] {
+				seen[uid] = true
+				uids = append(uids, uid)
+			}
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] > uids[j] })
+	return uids, nil
+}
+
+// fetchMessages reads and parses the newest matching messages of a source's mailbox
+func (c *EmailCollector) fetchMessages(ctx context.Context, source *model.DataSource, config EmailConfig) ([]*emailMessage, error) {
+	mailbox, err := parseMailboxURL(source.URL)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.open(ctx, mailbox, config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	uids, err := searchSenders(conn, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(uids) > config.MaxMessages {
+		uids = uids[:config.MaxMessages]
+	}
+
+	messages := make([]*emailMessage, 0, len(uids))
+	for _, uid := range uids {
+		raw, err := conn.Fetch(uid)
+		if err != nil {
+			return nil, err
+		}
+		message, err := parseEmail(raw)
+		if err != nil {
+			log.Printf("Failed to parse message %d of %s: %v", uid, source.Name, err)
+			continue
+		}
+		if message.MessageID == "" {
+			message.MessageID = fmt.Sprintf("%d.%s", uid, mailbox.Addr)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// toNewsItem converts a newsletter to a news item, extracting the main content of its HTML body
+func (c *EmailCollector) toNewsItem(message *emailMessage, sourceName string, config EmailConfig) *model.NewsItem {
+	content := strings.TrimSpace(message.Text)
+	if message.HTML != "" {
+		if extracted, err := c.parser.Parse(message.HTML, ""); err == nil && extracted.Content != "" {
+			content = extracted.Content
+		}
+	}
+
+	newsItem := &model.NewsItem{
+		Title:          message.Subject,
+		OriginalTitle:  message.Subject,
+		Content:        content,
+		SourceURL:      "mid:" + message.MessageID,
+		SourceName:     sourceName,
+		SourceLanguage: config.Language,
+		Author:         message.From,
+		Category:       config.DefaultCategory,
+		PublishedAt:    message.Date,
+		FetchedAt:      time.Now(),
+	}
+	if newsItem.SourceLanguage == "" {
+		newsItem.SourceLanguage = detectLanguage(newsItem.Content)
+	}
+	return newsItem
+}
+
+// parseEmail reads the headers and the HTML and plain text bodies of a message
+func parseEmail(raw []byte) (*emailMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+	message := &emailMessage{
+		MessageID: strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>"),
+		Subject:   msg.Header.Get("Subject"),
+	}
+	if subject, err := decoder.DecodeHeader(message.Subject); err == nil {
+		message.Subject = subject
+	}
+	if from, err := (&mail.AddressParser{WordDecoder: decoder}).Parse(msg.Header.Get("From")); err == nil {
+		message.From = from.Name
+		if message.From == "" {
+			message.From = from.Address
+		}
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		message.Date = &date
+	}
+
+	message.HTML, message.Text, err = emailBodies(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	if message.HTML == "" && message.Text == "" {
+		return nil, fmt.Errorf("message has no text body")
+	}
+	return message, nil
+}
+
+// emailBodies returns the first HTML and plain text parts of a message body, walking
+// multipart bodies and decoding transfer encodings and charsets
+func emailBodies(contentType, encoding string, body io.Reader) (html, text string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return html, text, err
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			partHTML, partText, err := emailBodies(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return html, text, err
+			}
+			if html == "" {
+				html = partHTML
+			}
+			if text == "" {
+				text = partText
+			}
+		}
+		return html, text, nil
+	}
+
+	if mediaType != "text/html" && mediaType != "text/plain" {
+		return "", "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	decoded, err := charset.NewReader(body, contentType)
+	if err != nil {
+		return "", "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(decoded, 10<<20))
+	if err != nil {
+		return "", "", err
+	}
+
+	if mediaType == "text/html" {
+		return string(data), "", nil
+	}
+	return "", string(data), nil
+}
@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxFeedClockSkew is how far in the future a feed date may be before it is treated as bogus
+const maxFeedClockSkew = time.Hour
+
+// zoneSuffix matches the timezone at the end of a feed date: Z, an offset or an abbreviation like GMT
+var zoneSuffix = regexp.MustCompile(`(?i)(z|[+-]\d{2}:?\d{2}|\b[a-z]{2,5})$`)
+
+// location returns the timezone for feed dates without an offset, defaulting to UTC
+func (c RSSConfig) location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		log.Printf("Warning: invalid feed timezone %q, using UTC: %v", c.Timezone, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// feedTime normalizes a parsed feed date to UTC. Dates written without a timezone are parsed
+// as UTC by gofeed; they are reinterpreted as wall-clock time in loc. Dates more than an hour
+// in the future (usually a wrong zone) are clamped to now.
+func feedTime(raw string, parsed *time.Time, loc *time.Location) *time.Time {
+	if parsed == nil {
+		return nil
+	}
+
+	t := *parsed
+	if !zoneSuffix.MatchString(strings.TrimSpace(raw)) && loc != time.UTC {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+	t = t.UTC()
+
+	if now := time.Now().UTC(); t.After(now.Add(maxFeedClockSkew)) {
+		t = now
+	}
+	return &t
+}
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+// ItemFilterConfig holds per-source include/exclude rules, stored under "filters" in DataSource.Config
+type ItemFilterConfig struct {
+	IncludeKeywords []string `json:"includeKeywords,omitempty"`
+	ExcludeKeywords []string `json:"excludeKeywords,omitempty"`
+	IncludePatterns []string `json:"includePatterns,omitempty"` // Go regular expressions
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+}
+
+// ItemFilter decides whether a collected item should be kept, evaluated against title and content
+type ItemFilter struct {
+	includeKeywords []string
+	excludeKeywords []string
+	includePatterns []*regexp.Regexp
+	excludePatterns []*regexp.Regexp
+}
+
+// NewItemFilter compiles a filter config; returns nil if the config has no rules
+func NewItemFilter(cfg *ItemFilterConfig) (*ItemFilter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	f := &ItemFilter{
+		includeKeywords: lowerAll(cfg.IncludeKeywords),
+		excludeKeywords: lowerAll(cfg.ExcludeKeywords),
+	}
+
+	var err error
+	if f.includePatterns, err = compileAll(cfg.IncludePatterns); err != nil {
+		return nil, err
+	}
+	if f.excludePatterns, err = compileAll(cfg.ExcludePatterns); err != nil {
+		return nil, err
+	}
+
+	if len(f.includeKeywords)+len(f.excludeKeywords)+len(f.includePatterns)+len(f.excludePatterns) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// ParseItemFilter reads the "filters" section of a data source config
+func ParseItemFilter(config datatypes.JSON) (*ItemFilter, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+
+	var wrapper struct {
+		Filters *ItemFilterConfig `json:"filters"`
+	}
+	if err := json.Unmarshal(config, &wrapper); err != nil {
+		return nil, fmt.Errorf("invalid source config: %w", err)
+	}
+	return NewItemFilter(wrapper.Filters)
+}
+
+// Allow reports whether an item passes the filter, with the reason when it is dropped.
+// A nil filter allows everything.
+func (f *ItemFilter) Allow(title, content string) (bool, string) {
+	if f == nil {
+		return true, ""
+	}
+
+	text := title + "\n" + content
+	lower := strings.ToLower(text)
+
+	for _, kw := range f.excludeKeywords {
+		if strings.Contains(lower, kw) {
+			return false, "excluded keyword: " + kw
+		}
+	}
+	for _, re := range f.excludePatterns {
+		if re.MatchString(text) {
+			return false, "excluded pattern: " + re.String()
+		}
+	}
+
+	if len(f.includeKeywords) == 0 && len(f.includePatterns) == 0 {
+		return true, ""
+	}
+	for _, kw := range f.includeKeywords {
+		if strings.Contains(lower, kw) {
+			return true, ""
+		}
+	}
+	for _, re := range f.includePatterns {
+		if re.MatchString(text) {
+			return true, ""
+		}
+	}
+	return false, "no include rule matched"
+}
+
+func lowerAll(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter pattern %q: %w", p, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
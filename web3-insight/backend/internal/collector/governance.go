@@ -0,0 +1,572 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// GovernanceTag is added to every news item from a governance source
+const GovernanceTag = "governance"
+
+// Governance platforms
+const (
+	PlatformSnapshot = "snapshot"
+	PlatformTally    = "tally"
+)
+
+// defaultGovernanceProposals is how many of the latest proposals per space a sync looks at
+const defaultGovernanceProposals = 20
+
+// GovernanceCollector ingests DAO proposals from Snapshot spaces and Tally organizations,
+// with their voting period and results. Proposals are stored when first seen and refreshed
+// while their vote runs, so the final results replace the early counts.
+type GovernanceCollector struct {
+	newsRepo    *repository.NewsRepository
+	client      *http.Client
+	snapshotURL string // Snapshot hub GraphQL endpoint
+	tallyURL    string // Tally GraphQL endpoint
+	tallyAPIKey string
+}
+
+// NewGovernanceCollector creates a new Snapshot and Tally collector. Tally sources fail
+// without an API key.
+func NewGovernanceCollector(newsRepo *repository.NewsRepository, tallyAPIKey string) *GovernanceCollector {
+	return &GovernanceCollector{
+		newsRepo:    newsRepo,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		snapshotURL: "https://hub.snapshot.org/graphql",
+		tallyURL:    "https://api.tally.xyz/query",
+		tallyAPIKey: tallyAPIKey,
+	}
+}
+
+// GovernanceConfig holds governance-specific configuration
+type GovernanceConfig struct {
+	DefaultCategory string   `json:"defaultCategory,omitempty"`
+	Spaces          []string `json:"spaces,omitempty"`       // More Snapshot spaces or Tally organization slugs, besides the one in the URL
+	MaxProposals    int      `json:"maxProposals,omitempty"` // Latest proposals per space looked at per sync; defaults to 20
+}
+
+// proposal is a governance proposal of either platform
+type proposal struct {
+	Title   string
+	Body    string
+	URL     string
+	Space   string // Display name of the space or organization
+	SpaceID string
+	Author  string
+	State   string
+	Start   *time.Time
+	End     *time.Time
+	Results []proposalResult
+}
+
+// proposalResult is the vote count of one choice
+type proposalResult struct {
+	Choice  string
+	Votes   float64
+	Percent float64
+}
+
+// Type returns the data source type handled by this collector
+func (c *GovernanceCollector) Type() string {
+	return model.DataSourceTypeGovernance
+}
+
+// Validate checks that the source URL is a Snapshot space or Tally organization with proposals
+func (c *GovernanceCollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	platform, spaces, err := governanceSpaces(source.URL, parseGovernanceConfig(source))
+	if err != nil {
+		return nil, err
+	}
+	proposals, err := c.listProposals(ctx, platform, spaces, 5)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.Join(spaces, ", ")
+	if len(proposals) > 0 {
+		title = proposals[0].Space
+	}
+	return &ValidationResult{
+		Title:       title,
+		Description: fmt.Sprintf("%s governance", platform),
+		ItemCount:   len(proposals),
+	}, nil
+}
+
+// Collect stores new proposals and refreshes stored ones whose state or results changed
+func (c *GovernanceCollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+
+	if source.Type != model.DataSourceTypeGovernance {
+		return nil, fmt.Errorf("data source is not governance type: %s", source.Type)
+	}
+
+	config := parseGovernanceConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+	platform, spaces, err := governanceSpaces(source.URL, config)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals, err := c.listProposals(ctx, platform, spaces, config.MaxProposals)
+	if err != nil {
+		return nil, err
+	}
+	result.ItemsFound = len(proposals)
+
+	var newsItems []model.NewsItem
+	for _, p := range proposals {
+		newsItem := p.newsItem(source.Name, config)
+		if existing, err := c.newsRepo.FindBySourceURL(p.URL); err == nil && existing != nil {
+			if existing.Content != newsItem.Content {
+				// Tallies move on every sync while voting is open; only a new state or text
+				// is summarized again
+				update := c.newsRepo.RefreshContent
+				if p.changedFrom(existing.Content) {
+					update = c.newsRepo.UpdateContent
+				}
+				if err := update(existing.ID, newsItem.Content); err != nil {
+					result.Errors = append(result.Errors, err)
+					continue
+				}
+				result.ItemsUpdated++
+			}
+			continue
+		}
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			log.Printf("Filtered item from %s: %s (%s)", source.Name, newsItem.Title, reason)
+			result.ItemsFiltered++
+			continue
+		}
+		newsItems = append(newsItems, newsItem)
+	}
+
+	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew = newCount
+
+	log.Printf("Governance sync completed for %s: found=%d, new=%d, updated=%d, filtered=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsUpdated, result.ItemsFiltered)
+
+	return result, nil
+}
+
+// Preview lists the proposals a sync would store without writing anything
+func (c *GovernanceCollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	config := parseGovernanceConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+	platform, spaces, err := governanceSpaces(source.URL, config)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals, err := c.listProposals(ctx, platform, spaces, config.MaxProposals)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(proposals),
+		Items:      make([]PreviewItem, 0, len(proposals)),
+	}
+	for _, p := range proposals {
+		newsItem := p.newsItem(source.Name, config)
+		preview := PreviewItem{
+			Title:       newsItem.Title,
+			URL:         newsItem.SourceURL,
+			PublishedAt: newsItem.PublishedAt,
+			Excerpt:     previewExcerpt(newsItem.Content),
+			Language:    newsItem.SourceLanguage,
+		}
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			preview.Filtered = true
+			preview.FilterReason = reason
+			result.ItemsFiltered++
+		} else if existing, err := c.newsRepo.FindBySourceURL(preview.URL); err == nil && existing != nil {
+			preview.Exists = true
+		} else {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// parseGovernanceConfig reads a source's config, applying defaults
+func parseGovernanceConfig(source *model.DataSource) GovernanceConfig {
+	var config GovernanceConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			log.Printf("Warning: failed to parse governance config: %v", err)
+		}
+	}
+	if config.MaxProposals <= 0 {
+		config.MaxProposals = defaultGovernanceProposals
+	}
+	return config
+}
+
+// governanceSpaces returns the platform of a source URL and the spaces to poll: the one in the
+// URL, like https://snapshot.org/#/uniswapgovernance.eth or https://www.tally.xyz/gov/arbitrum,
+// followed by those in the config
+func governanceSpaces(rawURL string, config GovernanceConfig) (string, []string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", nil, fmt.Errorf("invalid URL: %s", rawURL)
+	}
+
+	var platform, space string
+	switch host := strings.TrimPrefix(strings.ToLower(u.Host), "www."); host {
+	case "snapshot.org", "snapshot.box", "hub.snapshot.org":
+		platform = PlatformSnapshot
+		// Spaces sit in the fragment of the app's hash routes: #/<space> or #/s:<space>
+		path := strings.Trim(u.Fragment, "/")
+		if path == "" {
+			path = strings.Trim(u.Path, "/")
+		}
+		space = strings.TrimPrefix(strings.Split(path, "/")[0], "s:")
+		if space == "graphql" {
+			space = ""
+		}
+	case "tally.xyz":
+		platform = PlatformTally
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segments) >= 2 && segments[0] == "gov" {
+			space = segments[1]
+		}
+	default:
+		return "", nil, fmt.Errorf("not a Snapshot or Tally URL: %s", u.Host)
+	}
+
+	var spaces []string
+	if space != "" {
+		spaces = append(spaces, space)
+	}
+	for _, s := range config.Spaces {
+		if s = strings.TrimSpace(s); s != "" && s != space {
+			spaces = append(spaces, s)
+		}
+	}
+	if len(spaces) == 0 {
+		return "", nil, fmt.Errorf("no %s space in the URL or config", platform)
+	}
+	return platform, spaces, nil
+}
+
+// listProposals returns the latest proposals of each space, newest first
+func (c *GovernanceCollector) listProposals(ctx context.Context, platform string, spaces []string, limit int) ([]proposal, error) {
+	if platform == PlatformSnapshot {
+		return c.listSnapshotProposals(ctx, spaces, limit)
+	}
+	var proposals []proposal
+	for _, slug := range spaces {
+		list, err := c.listTallyProposals(ctx, slug, limit)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, list...)
+	}
+	return proposals, nil
+}
+
+// snapshotQuery lists the latest proposals of Snapshot spaces
+const snapshotQuery = `query($spaces: [String], $first: Int) {
+  proposals(first: $first, where: {space_in: $spaces}, orderBy: "created", orderDirection: desc) {
+    id title body choices start end state author scores scores_total link
+    space { id name }
+  }
+}`
+
+// listSnapshotProposals queries the Snapshot hub
+func (c *GovernanceCollector) listSnapshotProposals(ctx context.Context, spaces []string, limit int) ([]proposal, error) {
+	var resp struct {
+		Proposals []struct {
+			ID          string    `json:"id"`
+			Title       string    `json:"title"`
+			Body        string    `json:"body"`
+			Choices     []string  `json:"choices"`
+			Start       int64     `json:"start"`
+			End         int64     `json:"end"`
+			State       string    `json:"state"`
+			Author      string    `json:"author"`
+			Scores      []float64 `json:"scores"`
+			ScoresTotal float64   `json:"scores_total"`
+			Link        string    `json:"link"`
+			Space       struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"space"`
+		} `json:"proposals"`
+	}
+	variables := map[string]any{"spaces": spaces, "first": limit * len(spaces)}
+	if err := c.graphQL(ctx, c.snapshotURL, nil, snapshotQuery, variables, &resp); err != nil {
+		return nil, fmt.Errorf("failed to query Snapshot: %w", err)
+	}
+
+	proposals := make([]proposal, 0, len(resp.Proposals))
+	for _, p := range resp.Proposals {
+		link := p.Link
+		if link == "" {
+			link = fmt.Sprintf("https://snapshot.org/#/%s/proposal/%s", p.Space.ID, p.ID)
+		}
+		item := proposal{
+			Title:   p.Title,
+			Body:    p.Body,
+			URL:     link,
+			Space:   p.Space.Name,
+			SpaceID: p.Space.ID,
+			Author:  p.Author,
+			State:   p.State,
+			Start:   unixTime(p.Start),
+			End:     unixTime(p.End),
+		}
+		for i, choice := range p.Choices {
+			r := proposalResult{Choice: choice}
+			if i < len(p.Scores) {
+				r.Votes = p.Scores[i]
+				if p.ScoresTotal > 0 {
+					r.Percent = p.Scores[i] / p.ScoresTotal * 100
+				}
+			}
+			item.Results = append(item.Results, r)
+		}
+		proposals = append(proposals, item)
+	}
+	return proposals, nil
+}
+
+// tallyOrganizationQuery looks up a Tally organization by slug
+const tallyOrganizationQuery = `query($slug: String!) {
+  organization(input: {slug: $slug}) { id name slug }
+}`
+
+// tallyProposalsQuery lists the latest proposals of a Tally organization
+const tallyProposalsQuery = `query($organizationId: IntID!, $limit: Int!) {
+  proposals(input: {filters: {organizationId: $organizationId}, page: {limit: $limit}, sort: {isDescending: true, sortBy: id}}) {
+    nodes {
+      ... on Proposal {
+        onchainId status
+        metadata { title description }
+        proposer { address }
+        start { ... on Block { timestamp } ... on BlocklessTimestamp { timestamp } }
+        end { ... on Block { timestamp } ... on BlocklessTimestamp { timestamp } }
+        voteStats { type votesCount percent }
+      }
+    }
+  }
+}`
+
+// listTallyProposals queries Tally for an organization's proposals
+func (c *GovernanceCollector) listTallyProposals(ctx context.Context, slug string, limit int) ([]proposal, error) {
+	if c.tallyAPIKey == "" {
+		return nil, fmt.Errorf("Tally sources need collector.tally_api_key in the config")
+	}
+	headers := map[string]string{"Api-Key": c.tallyAPIKey}
+
+	var org struct {
+		Organization struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			Slug string `json:"slug"`
+		} `json:"organization"`
+	}
+	if err := c.graphQL(ctx, c.tallyURL, headers, tallyOrganizationQuery, map[string]any{"slug": slug}, &org); err != nil {
+		return nil, fmt.Errorf("failed to find Tally organization %s: %w", slug, err)
+	}
+
+	var resp struct {
+		Proposals struct {
+			Nodes []struct {
+				OnchainID string `json:"onchainId"`
+				Status    string `json:"status"`
+				Metadata  struct {
+					Title       string `json:"title"`
+					Description string `json:"description"`
+				} `json:"metadata"`
+				Proposer struct {
+					Address string `json:"address"`
+				} `json:"proposer"`
+				Start struct {
+					Timestamp *time.Time `json:"timestamp"`
+				} `json:"start"`
+				End struct {
+					Timestamp *time.Time `json:"timestamp"`
+				} `json:"end"`
+				VoteStats []struct {
+					Type       string  `json:"type"`
+					VotesCount string  `json:"votesCount"` // Token amount in wei, as a decimal string
+					Percent    float64 `json:"percent"`
+				} `json:"voteStats"`
+			} `json:"nodes"`
+		} `json:"proposals"`
+	}
+	variables := map[string]any{"organizationId": org.Organization.ID, "limit": limit}
+	if err := c.graphQL(ctx, c.tallyURL, headers, tallyProposalsQuery, variables, &resp); err != nil {
+		return nil, fmt.Errorf("failed to query Tally: %w", err)
+	}
+
+	proposals := make([]proposal, 0, len(resp.Proposals.Nodes))
+	for _, p := range resp.Proposals.Nodes {
+		item := proposal{
+			Title:   p.Metadata.Title,
+			Body:    p.Metadata.Description,
+			URL:     fmt.Sprintf("https://www.tally.xyz/gov/%s/proposal/%s", org.Organization.Slug, p.OnchainID),
+			Space:   org.Organization.Name,
+			SpaceID: org.Organization.Slug,
+			Author:  p.Proposer.Address,
+			State:   strings.ToLower(p.Status),
+			Start:   p.Start.Timestamp,
+			End:     p.End.Timestamp,
+		}
+		for _, stat := range p.VoteStats {
+			item.Results = append(item.Results, proposalResult{Choice: stat.Type, Votes: tokenVotes(stat.VotesCount), Percent: stat.Percent})
+		}
+		proposals = append(proposals, item)
+	}
+	return proposals, nil
+}
+
+// newsItem converts a proposal to a news item: the state, voting period and results above the
+// proposal text
+func (p proposal) newsItem(sourceName string, config GovernanceConfig) model.NewsItem {
+	var content strings.Builder
+	content.WriteString(p.headline() + "\n\n")
+	if p.Start != nil && p.End != nil {
+		fmt.Fprintf(&content, "**Voting:** %s – %s\n\n", p.Start.UTC().Format("2006-01-02 15:04 UTC"), p.End.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	if len(p.Results) > 0 {
+		content.WriteString("## Results\n\n")
+		for _, r := range p.Results {
+			fmt.Fprintf(&content, "- %s: %s (%.1f%%)\n", r.Choice, formatVotes(r.Votes), r.Percent)
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString(strings.TrimSpace(p.Body))
+
+	tags := []string{GovernanceTag}
+	if p.SpaceID != "" {
+		tags = append(tags, p.SpaceID)
+	}
+
+	newsItem := model.NewsItem{
+		Title:         p.Title,
+		OriginalTitle: p.Title,
+		Content:       content.String(),
+		SourceURL:     p.URL,
+		SourceName:    sourceName,
+		Author:        p.Author,
+		Category:      config.DefaultCategory,
+		Tags:          tags,
+		PublishedAt:   p.Start,
+		FetchedAt:     time.Now(),
+	}
+	newsItem.SourceLanguage = detectLanguage(newsItem.Content)
+	return newsItem
+}
+
+// headline is the first line of a proposal's content, naming its space and state
+func (p proposal) headline() string {
+	return fmt.Sprintf("**Space:** %s · **State:** %s", p.Space, p.State)
+}
+
+// changedFrom reports whether a proposal's state or text differs from the stored content,
+// as opposed to only its vote counts
+func (p proposal) changedFrom(content string) bool {
+	return !strings.HasPrefix(content, p.headline()+"\n") || !strings.HasSuffix(content, strings.TrimSpace(p.Body))
+}
+
+// unixTime converts Unix seconds, returning nil for zero
+func unixTime(seconds int64) *time.Time {
+	if seconds <= 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
+
+// tokenVotes converts a vote count in wei to whole tokens, assuming 18 decimals
+func tokenVotes(wei string) float64 {
+	var f float64
+	if _, err := fmt.Sscan(wei, &f); err != nil {
+		return 0
+	}
+	return f / 1e18
+}
+
+// formatVotes renders a vote count with thousands separators
+func formatVotes(votes float64) string {
+	digits := fmt.Sprintf("%.0f", votes)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// graphQL runs a query and decodes its data into v
+func (c *GovernanceCollector) graphQL(ctx context.Context, endpoint string, headers map[string]string, query string, variables map[string]any, v any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("%s", envelope.Errors[0].Message)
+	}
+	return json.Unmarshal(envelope.Data, v)
+}
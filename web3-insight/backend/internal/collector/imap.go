@@ -0,0 +1,330 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// imapConn is a minimal read-only IMAP4rev1 client: enough to log in, open a mailbox, search
+// it and fetch whole messages without marking them seen
+type imapConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// maxIMAPLiteral caps the size of a literal read from the server; newsletters are far smaller
+const maxIMAPLiteral = 25 << 20
+
+// imapResponse is an untagged response line with the literals it carried
+type imapResponse struct {
+	Line     string
+	Literals [][]byte
+}
+
+// dialIMAP connects to an IMAP server and reads its greeting. The connection is over TLS
+// from the start, or with startTLS over plain TCP upgraded with STARTTLS; a server that
+// cannot upgrade is refused, so the password is never sent in cleartext.
+func dialIMAP(ctx context.Context, addr string, startTLS bool) (*imapConn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	host, _, _ := net.SplitHostPort(addr)
+	tlsConfig := &tls.Config{ServerName: host}
+	var conn net.Conn
+	var err error
+	if startTLS {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(5 * time.Minute))
+	}
+
+	c := &imapConn{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := c.r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", strings.TrimSpace(greeting))
+	}
+	if startTLS {
+		if err := c.startTLS(ctx, tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// startTLS upgrades a plain connection to TLS
+func (c *imapConn) startTLS(ctx context.Context, tlsConfig *tls.Config) error {
+	if _, err := c.command("STARTTLS"); err != nil {
+		return fmt.Errorf("IMAP STARTTLS failed, refusing to log in without TLS: %w", err)
+	}
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("IMAP TLS handshake failed: %w", err)
+	}
+	c.conn = tlsConn
+	c.r = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// Close logs out and closes the connection
+func (c *imapConn) Close() error {
+	c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// Login authenticates with a user name and password
+func (c *imapConn) Login(user, password string) error {
+	if _, err := c.command("LOGIN", user, password); err != nil {
+		return fmt.Errorf("IMAP login failed: %w", err)
+	}
+	return nil
+}
+
+// Examine opens a mailbox read-only
+func (c *imapConn) Examine(mailbox string) error {
+	if _, err := c.command("EXAMINE", encodeMailboxName(mailbox)); err != nil {
+		return fmt.Errorf("failed to open mailbox %s: %w", mailbox, err)
+	}
+	return nil
+}
+
+// SearchFrom returns the UIDs of messages from a sender received since a date. A non-ASCII
+// sender, such as a newsletter's display name, is searched for in UTF-8.
+func (c *imapConn) SearchFrom(sender string, since time.Time) ([]uint32, error) {
+	search := "UID SEARCH"
+	if !isASCII(sender) {
+		search += " CHARSET UTF-8"
+	}
+	responses, err := c.command(search+" SINCE "+since.Format("2-Jan-2006")+" FROM", sender)
+	if err != nil {
+		return nil, fmt.Errorf("IMAP search failed: %w", err)
+	}
+	var uids []uint32
+	for _, resp := range responses {
+		fields := strings.Fields(resp.Line)
+		if len(fields) < 2 || fields[1] != "SEARCH" {
+			continue
+		}
+		for _, f := range fields[2:] {
+			if uid, err := strconv.ParseUint(f, 10, 32); err == nil {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// Fetch returns the full source of a message without setting its \Seen flag
+func (c *imapConn) Fetch(uid uint32) ([]byte, error) {
+	responses, err := c.command(fmt.Sprintf("UID FETCH %d BODY.PEEK[]", uid))
+	if err != nil {
+		return nil, fmt.Errorf("IMAP fetch failed: %w", err)
+	}
+	for _, resp := range responses {
+		if strings.Contains(resp.Line, "FETCH") && len(resp.Literals) > 0 {
+			return resp.Literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("message %d not found", uid)
+}
+
+// command sends a command followed by string arguments and reads its untagged responses up
+// to the tagged completion. ASCII arguments are quoted; others are sent as literals, waiting
+// for the server's continuation before each. Arguments come from source configuration, so
+// control characters, which could end the command and start another, are refused.
+func (c *imapConn) command(cmd string, args ...string) ([]imapResponse, error) {
+	for _, arg := range args {
+		if i := strings.IndexFunc(arg, isControl); i >= 0 {
+			return nil, fmt.Errorf("IMAP argument contains control character %q", arg[i])
+		}
+	}
+
+	c.tag++
+	tag := fmt.Sprintf("A%03d", c.tag)
+	var responses []imapResponse
+	line := tag + " " + cmd
+	for _, arg := range args {
+		if isASCII(arg) {
+			line += " " + imapQuote(arg)
+			continue
+		}
+		if _, err := io.WriteString(c.conn, fmt.Sprintf("%s {%d}\r\n", line, len(arg))); err != nil {
+			return nil, err
+		}
+		untagged, err := c.awaitContinuation(tag)
+		responses = append(responses, untagged...)
+		if err != nil {
+			return nil, err
+		}
+		line = arg
+	}
+	if _, err := io.WriteString(c.conn, line+"\r\n"); err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(resp.Line, tag+" ") {
+			status := strings.TrimPrefix(resp.Line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("%s", status)
+			}
+			return responses, nil
+		}
+		responses = append(responses, resp)
+	}
+}
+
+// awaitContinuation reads responses up to the server's "+" request for a literal, returning
+// the untagged ones read before it
+func (c *imapConn) awaitContinuation(tag string) ([]imapResponse, error) {
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return responses, err
+		}
+		if strings.HasPrefix(resp.Line, "+") {
+			return responses, nil
+		}
+		if strings.HasPrefix(resp.Line, tag+" ") {
+			return responses, fmt.Errorf("%s", strings.TrimPrefix(resp.Line, tag+" "))
+		}
+		responses = append(responses, resp)
+	}
+}
+
+// readResponse reads one response line, including any literals ({n} followed by n bytes)
+// embedded in it
+func (c *imapConn) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var line strings.Builder
+	for {
+		part, err := c.r.ReadString('\n')
+		if err != nil {
+			return resp, err
+		}
+		part = strings.TrimRight(part, "\r\n")
+		n, ok := literalSize(part)
+		if !ok {
+			line.WriteString(part)
+			resp.Line = line.String()
+			return resp, nil
+		}
+		line.WriteString(part)
+		if n > maxIMAPLiteral {
+			return resp, fmt.Errorf("IMAP literal of %d bytes exceeds the %d byte limit", n, maxIMAPLiteral)
+		}
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return resp, err
+		}
+		resp.Literals = append(resp.Literals, literal)
+	}
+}
+
+// literalSize returns the size of a literal announced at the end of a line
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	open := strings.LastIndexByte(line, '{')
+	if open < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(line[open+1:len(line)-1], "+"))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// isControl reports whether r is an ASCII control character
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// isASCII reports whether s is plain ASCII
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// mailboxBase64 is the base64 alphabet of modified UTF-7, with "," in place of "/"
+var mailboxBase64 = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+,").WithPadding(base64.NoPadding)
+
+// encodeMailboxName encodes a mailbox name in the modified UTF-7 of IMAP4rev1 (RFC 3501
+// section 5.1.3), so non-ASCII names are sent as ASCII
+func encodeMailboxName(name string) string {
+	var b strings.Builder
+	var pending []rune
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		units := utf16.Encode(pending)
+		raw := make([]byte, 0, len(units)*2)
+		for _, u := range units {
+			raw = append(raw, byte(u>>8), byte(u))
+		}
+		b.WriteString("&" + mailboxBase64.EncodeToString(raw) + "-")
+		pending = pending[:0]
+	}
+	for _, r := range name {
+		if r >= 0x80 {
+			pending = append(pending, r)
+			continue
+		}
+		flush()
+		if r == '&' {
+			b.WriteString("&-")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// imapQuote quotes a string argument
+func imapQuote(s string) string {
+	var b bytes.Buffer
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
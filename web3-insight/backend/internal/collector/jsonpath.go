@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is one step of a compiled JSONPath: a member name, an array index or a wildcard
+type jsonPathStep struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// jsonPath is a compiled JSONPath expression. The supported subset is enough for API response
+// mappings: $.a.b, $['a b'], $.list[0] and $.list[*] (or $.object.*).
+type jsonPath []jsonPathStep
+
+// compileJSONPath parses a JSONPath expression; the leading $ is optional
+func compileJSONPath(expr string) (jsonPath, error) {
+	rest := strings.TrimSpace(expr)
+	rest = strings.TrimPrefix(rest, "$")
+	var path jsonPath
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", expr)
+			}
+			if name == "*" {
+				path = append(path, jsonPathStep{wildcard: true})
+			} else {
+				path = append(path, jsonPathStep{name: name})
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed bracket", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				path = append(path, jsonPathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				path = append(path, jsonPathStep{name: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSONPath %q: bad index %q", expr, inner)
+				}
+				path = append(path, jsonPathStep{index: index, isIndex: true})
+			}
+		default:
+			// A bare leading member name, as in "data.items"
+			if len(path) > 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q near %q", expr, rest)
+			}
+			rest = "." + rest
+		}
+	}
+	return path, nil
+}
+
+// Select returns every value the path matches in a decoded JSON document
+func (p jsonPath) Select(doc any) []any {
+	values := []any{doc}
+	for _, step := range p {
+		var next []any
+		for _, value := range values {
+			switch v := value.(type) {
+			case map[string]any:
+				if step.wildcard {
+					for _, child := range v {
+						next = append(next, child)
+					}
+				} else if child, ok := v[step.name]; ok && !step.isIndex {
+					next = append(next, child)
+				}
+			case []any:
+				if step.wildcard {
+					next = append(next, v...)
+				} else if step.isIndex {
+					index := step.index
+					if index < 0 {
+						index += len(v)
+					}
+					if index >= 0 && index < len(v) {
+						next = append(next, v[index])
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// First returns the first value the path matches, or nil
+func (p jsonPath) First(doc any) any {
+	if values := p.Select(doc); len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// LicenseInfo is the reuse license and author detected for a page or feed item
+type LicenseInfo struct {
+	License    string // SPDX-style identifier, e.g. CC-BY-4.0; empty if unknown
+	LicenseURL string
+	Author     string
+}
+
+var (
+	// creativeCommonsURL matches license deeds such as creativecommons.org/licenses/by-sa/4.0/
+	creativeCommonsURL = regexp.MustCompile(`(?i)creativecommons\.org/(licenses|publicdomain)/([a-z-]+)/(\d\.\d)`)
+	// creativeCommonsText matches names such as "CC BY-NC 4.0" or "CC-BY-4.0"
+	creativeCommonsText = regexp.MustCompile(`(?i)\bCC[ -]?(BY(?:[ -](?:SA|ND|NC))*)[ -]?(\d\.\d)?\b`)
+	ccZeroText          = regexp.MustCompile(`(?i)\bCC0\b|public domain`)
+	mitText             = regexp.MustCompile(`(?i)\bMIT licen[cs]e\b`)
+	allRightsText       = regexp.MustCompile(`(?i)all rights reserved|版权所有`)
+	noticeURL           = regexp.MustCompile(`https?://[^\s"'<>]+`)
+)
+
+// NormalizeLicense maps a license URL or notice to an identifier, returning "" if none is recognized
+func NormalizeLicense(text string) string {
+	if m := creativeCommonsURL.FindStringSubmatch(text); m != nil {
+		if strings.EqualFold(m[2], "zero") {
+			return model.LicenseCC0
+		}
+		if strings.EqualFold(m[1], "licenses") {
+			return "CC-" + strings.ToUpper(m[2]) + "-" + m[3]
+		}
+	}
+	if ccZeroText.MatchString(text) {
+		return model.LicenseCC0
+	}
+	if m := creativeCommonsText.FindStringSubmatch(text); m != nil {
+		license := "CC-" + strings.ToUpper(strings.ReplaceAll(m[1], " ", "-"))
+		if m[2] != "" {
+			license += "-" + m[2]
+		}
+		return license
+	}
+	if mitText.MatchString(text) {
+		return "MIT"
+	}
+	if allRightsText.MatchString(text) {
+		return model.LicenseAllRightsReserved
+	}
+	return ""
+}
+
+// LicenseURL returns the link to a license: the first URL in the notice it was read from,
+// or for Creative Commons licenses the deed. Returns "" when there is neither.
+func LicenseURL(license, notice string) string {
+	if license == "" || license == model.LicenseAllRightsReserved {
+		return ""
+	}
+	if u := noticeURL.FindString(notice); u != "" {
+		return strings.TrimRight(u, ".,;)")
+	}
+	if license == model.LicenseCC0 {
+		return "https://creativecommons.org/publicdomain/zero/1.0/"
+	}
+	if rest, ok := strings.CutPrefix(license, "CC-"); ok {
+		if i := strings.LastIndex(rest, "-"); i > 0 {
+			return "https://creativecommons.org/licenses/" + strings.ToLower(rest[:i]) + "/" + rest[i+1:] + "/"
+		}
+	}
+	return ""
+}
+
+// detectLicense reads license links, rights metadata and the copyright notice of a page.
+// Call it before footers are stripped, since license links usually live there.
+func detectLicense(doc *goquery.Document) LicenseInfo {
+	var info LicenseInfo
+
+	// rel="license" is the standard marker, used by Creative Commons badges
+	doc.Find("link[rel~='license'], a[rel~='license']").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		href, _ := s.Attr("href")
+		if license := NormalizeLicense(href + " " + s.Text()); license != "" {
+			info.License, info.LicenseURL = license, href
+			return false
+		}
+		return true
+	})
+
+	if info.License == "" {
+		for _, selector := range []string{"meta[name='license']", "meta[name='dc.rights']", "meta[name='DC.rights']", "meta[name='copyright']", "meta[property='og:license']"} {
+			if content, ok := doc.Find(selector).First().Attr("content"); ok {
+				if license := NormalizeLicense(content); license != "" {
+					info.License = license
+					break
+				}
+			}
+		}
+	}
+
+	if info.License == "" {
+		// Copyright notices in the footer
+		footer := doc.Find("footer, .footer, .copyright, [class*='license']").Text()
+		info.License = NormalizeLicense(footer)
+	}
+	if info.LicenseURL == "" {
+		info.LicenseURL = LicenseURL(info.License, "")
+	}
+
+	for _, selector := range []string{"meta[name='author']", "meta[property='article:author']", "meta[name='twitter:creator']"} {
+		if content, ok := doc.Find(selector).First().Attr("content"); ok && strings.TrimSpace(content) != "" {
+			info.Author = strings.TrimSpace(content)
+			break
+		}
+	}
+	return info
+}
@@ -0,0 +1,215 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/user/web3-insight/internal/config"
+)
+
+// maxPDFBytes caps the size of PDFs downloaded for text extraction
+const maxPDFBytes = 50 << 20
+
+// PDFExtractor turns PDFs into plain text through an Apache Tika server. A nil extractor
+// means full-text extraction is off.
+type PDFExtractor struct {
+	tikaURL string
+	client  *http.Client
+}
+
+// NewPDFExtractorFromConfig returns an extractor, or nil if no Tika server is configured
+func NewPDFExtractorFromConfig(cfg *config.CollectorConfig) *PDFExtractor {
+	if cfg == nil || cfg.PDFTextURL == "" {
+		return nil
+	}
+	return &PDFExtractor{
+		tikaURL: strings.TrimRight(cfg.PDFTextURL, "/"),
+		client:  &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Enabled reports whether PDFs can be converted to text
+func (e *PDFExtractor) Enabled() bool {
+	return e != nil
+}
+
+// ExtractURL downloads a PDF and returns its text
+func (e *PDFExtractor) ExtractURL(ctx context.Context, pdfURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pdfURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download PDF: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("PDF download returned status %d", resp.StatusCode)
+	}
+	data, err := readPDF(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return e.Extract(ctx, data)
+}
+
+// readPDF reads a downloaded PDF, refusing ones over maxPDFBytes
+func readPDF(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPDFBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download PDF: %w", err)
+	}
+	if len(data) > maxPDFBytes {
+		return nil, fmt.Errorf("PDF is larger than %d MB", maxPDFBytes>>20)
+	}
+	return data, nil
+}
+
+// Extract returns the text of a PDF
+func (e *PDFExtractor) Extract(ctx context.Context, pdf []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, e.tikaURL+"/tika", bytes.NewReader(pdf))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/pdf")
+	req.Header.Set("Accept", "text/plain")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("PDF text extraction failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("PDF text extraction returned status %d", resp.StatusCode)
+	}
+	text, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extracted text: %w", err)
+	}
+	return strings.TrimSpace(string(text)), nil
+}
+
+// PDFDocument is the text of a PDF split into pages
+type PDFDocument struct {
+	Title    string
+	Author   string
+	Language string
+	Pages    []string // Paragraphs separated by blank lines
+}
+
+// ExtractDocument returns the metadata and per-page text of a PDF
+func (e *PDFExtractor) ExtractDocument(ctx context.Context, pdf []byte) (*PDFDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, e.tikaURL+"/tika", bytes.NewReader(pdf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/pdf")
+	// The XHTML rendering wraps each page in <div class="page">
+	req.Header.Set("Accept", "text/html")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("PDF text extraction failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PDF text extraction returned status %d", resp.StatusCode)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse extracted text: %w", err)
+	}
+
+	result := &PDFDocument{
+		Title:  pdfMeta(doc, "dc:title", "title"),
+		Author: pdfMeta(doc, "dc:creator", "Author", "meta:author"),
+	}
+	if result.Title == "" {
+		result.Title = strings.TrimSpace(doc.Find("head title").First().Text())
+	}
+
+	pages := doc.Find("div.page")
+	if pages.Length() == 0 {
+		pages = doc.Find("body")
+	}
+	pages.Each(func(_ int, page *goquery.Selection) {
+		result.Pages = append(result.Pages, pdfPageText(page))
+	})
+	result.Language = detectLanguage(strings.Join(result.Pages, "\n"))
+	return result, nil
+}
+
+// pdfMeta returns the first non-empty <meta> value among names
+func pdfMeta(doc *goquery.Document, names ...string) string {
+	for _, name := range names {
+		if content, ok := doc.Find(fmt.Sprintf("meta[name=%q]", name)).First().Attr("content"); ok {
+			if content = strings.TrimSpace(content); content != "" {
+				return content
+			}
+		}
+	}
+	return ""
+}
+
+// pdfPageText joins the paragraphs of a page, unwrapping the line breaks inside them
+func pdfPageText(page *goquery.Selection) string {
+	var paragraphs []string
+	page.Find("p").Each(func(_ int, p *goquery.Selection) {
+		if text := strings.Join(strings.Fields(p.Text()), " "); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+	if len(paragraphs) == 0 {
+		return strings.TrimSpace(page.Text())
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// Markdown renders the document with a "## Page N" heading before each page, so pages can
+// be linked to as #page-n. Empty pages keep their heading to keep the numbering intact.
+func (d *PDFDocument) Markdown() string {
+	var sb strings.Builder
+	for i, page := range d.Pages {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "## Page %d\n\n%s", i+1, page)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// TitleOr returns the PDF's title, falling back to the first short line of its first page
+// and then to the file name of pdfURL
+func (d *PDFDocument) TitleOr(pdfURL string) string {
+	if d.Title != "" {
+		return d.Title
+	}
+	if len(d.Pages) > 0 {
+		line, _, _ := strings.Cut(strings.TrimSpace(d.Pages[0]), "\n")
+		if line = strings.TrimSpace(line); line != "" && len([]rune(line)) <= 200 {
+			return line
+		}
+	}
+	name := pdfURL
+	if parsed, err := url.Parse(pdfURL); err == nil {
+		name = path.Base(parsed.Path)
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// IsPDFURL reports whether a URL's path names a PDF file
+func IsPDFURL(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(parsed.Path), ".pdf")
+}
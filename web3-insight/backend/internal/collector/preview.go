@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// PreviewItem describes an item that a collection run would ingest
+type PreviewItem struct {
+	Title        string     `json:"title"`
+	URL          string     `json:"url"`
+	PublishedAt  *time.Time `json:"publishedAt,omitempty"`
+	Excerpt      string     `json:"excerpt"`
+	Language     string     `json:"language,omitempty"`
+	Exists       bool       `json:"exists"`   // Already stored (would be skipped as duplicate)
+	Filtered     bool       `json:"filtered"` // Dropped by the source's filters
+	FilterReason string     `json:"filterReason,omitempty"`
+}
+
+// PreviewResult is the dry-run outcome of collecting a source
+type PreviewResult struct {
+	SourceType    string        `json:"sourceType"`
+	ItemsFound    int           `json:"itemsFound"`
+	ItemsNew      int           `json:"itemsNew"`
+	ItemsFiltered int           `json:"itemsFiltered"`
+	Items         []PreviewItem `json:"items"`
+}
+
+// Preview fetches and parses the feed without writing anything
+func (c *RSSCollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	var config RSSConfig
+	if source.Config != nil {
+		json.Unmarshal(source.Config, &config)
+	}
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := c.parser.ParseURLWithContext(source.URL, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(feed.Items),
+		Items:      make([]PreviewItem, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		newsItem := c.convertFeedItem(item, source.Name, config)
+		preview := PreviewItem{
+			Title:       newsItem.Title,
+			URL:         newsItem.SourceURL,
+			PublishedAt: newsItem.PublishedAt,
+			Excerpt:     previewExcerpt(newsItem.Content),
+			Language:    newsItem.SourceLanguage,
+		}
+		c.markPreviewStatus(&preview, filter, newsItem.Content, result)
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// markPreviewStatus fills in dedup and filter status and updates the counters
+func (c *RSSCollector) markPreviewStatus(preview *PreviewItem, filter *ItemFilter, content string, result *PreviewResult) {
+	if ok, reason := filter.Allow(preview.Title, content); !ok {
+		preview.Filtered = true
+		preview.FilterReason = reason
+		result.ItemsFiltered++
+		return
+	}
+	if existing, err := c.newsRepo.FindBySourceURL(preview.URL); err == nil && existing != nil {
+		preview.Exists = true
+		return
+	}
+	result.ItemsNew++
+}
+
+// Preview crawls the source page and returns the extracted item without saving it. Sitemap
+// sources list the URLs they would queue.
+func (c *WebCrawler) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+	if config := parseCrawlConfig(source); config.Mode == CrawlModeSitemap {
+		return c.previewSitemap(ctx, source, config)
+	}
+
+	crawled, err := c.Crawl(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := PreviewItem{
+		Title:    crawled.Title,
+		URL:      source.URL,
+		Excerpt:  previewExcerpt(crawled.Content),
+		Language: crawled.Language,
+	}
+
+	result := &PreviewResult{SourceType: source.Type, ItemsFound: 1}
+	if ok, reason := filter.Allow(crawled.Title, crawled.Content); !ok {
+		preview.Filtered = true
+		preview.FilterReason = reason
+		result.ItemsFiltered++
+	} else if existing, err := c.newsRepo.FindBySourceURL(source.URL); err == nil && existing != nil {
+		preview.Exists = true
+	} else {
+		result.ItemsNew++
+	}
+	result.Items = []PreviewItem{preview}
+
+	return result, nil
+}
+
+// previewExcerpt returns the first 200 characters of text, stripping HTML if present
+func previewExcerpt(content string) string {
+	text := content
+	if strings.Contains(content, "<") {
+		if doc, err := goquery.NewDocumentFromReader(strings.NewReader(content)); err == nil {
+			text = doc.Text()
+		}
+	}
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if len(runes) > 200 {
+		return string(runes[:200]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,534 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Publication platforms
+const (
+	PlatformMirror    = "mirror"
+	PlatformParagraph = "paragraph"
+)
+
+// defaultPublicationPosts is how many of the latest posts a sync looks at by default
+const defaultPublicationPosts = 20
+
+var (
+	ethAddress         = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	mirrorPageAddress  = regexp.MustCompile(`"address"\s*:\s*"(0x[0-9a-fA-F]{40})"`)
+	mirrorSubdomainURL = regexp.MustCompile(`^([a-z0-9-]+)\.mirror\.xyz$`)
+)
+
+// PublicationCollector ingests Mirror and Paragraph publications, which keep their posts on
+// Arweave and offer no feed. Mirror posts are listed through Arweave transaction tags and
+// Paragraph posts through Paragraph's public API; both come with their full markdown.
+type PublicationCollector struct {
+	newsRepo     *repository.NewsRepository
+	client       *http.Client
+	arweaveURL   string // Gateway serving Arweave GraphQL and transaction data
+	paragraphURL string // Paragraph public API
+}
+
+// NewPublicationCollector creates a new Mirror and Paragraph collector
+func NewPublicationCollector(newsRepo *repository.NewsRepository) *PublicationCollector {
+	return &PublicationCollector{
+		newsRepo:     newsRepo,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		arweaveURL:   "https://arweave.net",
+		paragraphURL: "https://public.api.paragraph.com/api",
+	}
+}
+
+// PublicationConfig holds publication-specific configuration
+type PublicationConfig struct {
+	DefaultCategory string `json:"defaultCategory,omitempty"`
+	Language        string `json:"language,omitempty"`
+	MaxPosts        int    `json:"maxPosts,omitempty"` // Latest posts looked at per sync; defaults to 20
+}
+
+// publication identifies a publication from its URL: a Mirror address or name, or a
+// Paragraph slug
+type publication struct {
+	platform string
+	address  string // Mirror contributor address, resolved from the name if not in the URL
+	name     string // Mirror ENS or subdomain name
+	slug     string // Paragraph publication slug
+	pageURL  string
+}
+
+// publicationPost is a post of a publication. Body is only fetched for posts not yet stored.
+type publicationPost struct {
+	Title       string
+	Body        string // Markdown
+	URL         string
+	Author      string
+	PublishedAt *time.Time
+	fetch       func(ctx context.Context) (*publicationPost, error) // Loads the full post if Body is empty
+}
+
+// Type returns the data source type handled by this collector
+func (c *PublicationCollector) Type() string {
+	return model.DataSourceTypePublication
+}
+
+// Validate checks that the source URL is a Mirror or Paragraph publication with posts
+func (c *PublicationCollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	pub, err := c.resolve(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+	posts, title, err := c.listPosts(ctx, pub, defaultPublicationPosts)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidationResult{
+		Title:       title,
+		Description: fmt.Sprintf("%s publication", pub.platform),
+		ItemCount:   len(posts),
+	}, nil
+}
+
+// Collect fetches new posts with their markdown and stores them as news items
+func (c *PublicationCollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+
+	if source.Type != model.DataSourceTypePublication {
+		return nil, fmt.Errorf("data source is not publication type: %s", source.Type)
+	}
+
+	config := parsePublicationConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+
+	pub, err := c.resolve(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+	posts, _, err := c.listPosts(ctx, pub, config.MaxPosts)
+	if err != nil {
+		return nil, err
+	}
+	result.ItemsFound = len(posts)
+
+	var newsItems []model.NewsItem
+	for _, post := range posts {
+		if existing, err := c.newsRepo.FindBySourceURL(post.URL); err == nil && existing != nil {
+			continue
+		}
+		full, err := post.load(ctx)
+		if err != nil {
+			log.Printf("Failed to fetch post %s: %v", post.URL, err)
+			result.ItemsFailed++
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		newsItem := full.newsItem(source.Name, config)
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			log.Printf("Filtered item from %s: %s (%s)", source.Name, newsItem.Title, reason)
+			result.ItemsFiltered++
+			continue
+		}
+		newsItems = append(newsItems, newsItem)
+	}
+
+	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew = newCount
+
+	log.Printf("Publication sync completed for %s: found=%d, new=%d, filtered=%d, failed=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered, result.ItemsFailed)
+
+	return result, nil
+}
+
+// Preview lists the publication's latest posts without writing anything
+func (c *PublicationCollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	config := parsePublicationConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := c.resolve(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+	posts, _, err := c.listPosts(ctx, pub, config.MaxPosts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(posts),
+		Items:      make([]PreviewItem, 0, len(posts)),
+	}
+	for _, post := range posts {
+		preview := PreviewItem{Title: post.Title, URL: post.URL, PublishedAt: post.PublishedAt}
+		if existing, err := c.newsRepo.FindBySourceURL(post.URL); err == nil && existing != nil {
+			preview.Title = existing.Title
+			preview.Exists = true
+			result.Items = append(result.Items, preview)
+			continue
+		}
+		full, err := post.load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch post %s: %w", post.URL, err)
+		}
+		newsItem := full.newsItem(source.Name, config)
+		preview.Title = newsItem.Title
+		preview.PublishedAt = newsItem.PublishedAt
+		preview.Excerpt = previewExcerpt(newsItem.Content)
+		preview.Language = newsItem.SourceLanguage
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			preview.Filtered = true
+			preview.FilterReason = reason
+			result.ItemsFiltered++
+		} else {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// parsePublicationConfig reads a source's config, applying defaults
+func parsePublicationConfig(source *model.DataSource) PublicationConfig {
+	var config PublicationConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			log.Printf("Warning: failed to parse publication config: %v", err)
+		}
+	}
+	if config.MaxPosts <= 0 {
+		config.MaxPosts = defaultPublicationPosts
+	}
+	return config
+}
+
+// load returns the post with its markdown, fetching it if the listing had none
+func (p publicationPost) load(ctx context.Context) (*publicationPost, error) {
+	if p.Body != "" || p.fetch == nil {
+		return &p, nil
+	}
+	return p.fetch(ctx)
+}
+
+// newsItem converts a fetched post to a news item
+func (p *publicationPost) newsItem(sourceName string, config PublicationConfig) model.NewsItem {
+	newsItem := model.NewsItem{
+		Title:          p.Title,
+		OriginalTitle:  p.Title,
+		Content:        p.Body,
+		SourceURL:      p.URL,
+		SourceName:     sourceName,
+		SourceLanguage: config.Language,
+		Author:         p.Author,
+		Category:       config.DefaultCategory,
+		PublishedAt:    p.PublishedAt,
+		FetchedAt:      time.Now(),
+	}
+	if newsItem.SourceLanguage == "" {
+		newsItem.SourceLanguage = detectLanguage(newsItem.Content)
+	}
+	return newsItem
+}
+
+// resolve works out the platform of a publication URL, looking up a Mirror name's address
+func (c *PublicationCollector) resolve(ctx context.Context, rawURL string) (*publication, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %s", rawURL)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	switch {
+	case host == "paragraph.xyz" || host == "paragraph.com":
+		if len(segments) == 0 || !strings.HasPrefix(segments[0], "@") {
+			return nil, fmt.Errorf("expected a Paragraph publication URL like https://paragraph.com/@name")
+		}
+		return &publication{platform: PlatformParagraph, slug: strings.TrimPrefix(segments[0], "@"), pageURL: rawURL}, nil
+
+	case host == "mirror.xyz":
+		if len(segments) == 0 {
+			return nil, fmt.Errorf("expected a Mirror publication URL like https://mirror.xyz/0x... or https://mirror.xyz/name.eth")
+		}
+		pub := &publication{platform: PlatformMirror, pageURL: "https://mirror.xyz/" + segments[0]}
+		if ethAddress.MatchString(segments[0]) {
+			pub.address = strings.ToLower(segments[0])
+			return pub, nil
+		}
+		pub.name = segments[0]
+		return pub, c.resolveMirrorAddress(ctx, pub)
+
+	case mirrorSubdomainURL.MatchString(host):
+		pub := &publication{platform: PlatformMirror, name: mirrorSubdomainURL.FindStringSubmatch(host)[1], pageURL: "https://" + host}
+		return pub, c.resolveMirrorAddress(ctx, pub)
+	}
+	return nil, fmt.Errorf("not a Mirror or Paragraph publication: %s", host)
+}
+
+// resolveMirrorAddress looks up the address of a Mirror publication known by name from the
+// data embedded in its page
+func (c *PublicationCollector) resolveMirrorAddress(ctx context.Context, pub *publication) error {
+	body, err := c.get(ctx, pub.pageURL)
+	if err != nil {
+		return fmt.Errorf("failed to load Mirror publication: %w", err)
+	}
+	match := mirrorPageAddress.FindSubmatch(body)
+	if match == nil {
+		return fmt.Errorf("no publication address found on %s", pub.pageURL)
+	}
+	pub.address = strings.ToLower(string(match[1]))
+	return nil
+}
+
+// listPosts returns the latest posts of a publication, newest first, and its display name
+func (c *PublicationCollector) listPosts(ctx context.Context, pub *publication, limit int) ([]publicationPost, string, error) {
+	if pub.platform == PlatformParagraph {
+		return c.listParagraphPosts(ctx, pub, limit)
+	}
+	posts, err := c.listMirrorPosts(ctx, pub, limit)
+	title := pub.name
+	if title == "" {
+		title = pub.address
+	}
+	return posts, title, err
+}
+
+// arweaveQuery lists Mirror entry transactions of a contributor, newest first
+const arweaveQuery = `query($contributor: String!, $first: Int!) {
+  transactions(first: $first, sort: HEIGHT_DESC, tags: [
+    {name: "App-Name", values: ["MirrorXYZ"]},
+    {name: "Contributor", values: [$contributor]}
+  ]) {
+    edges { node { id tags { name value } } }
+  }
+}`
+
+// mirrorEntry is the JSON of a Mirror entry stored on Arweave
+type mirrorEntry struct {
+	Content struct {
+		Title     string      `json:"title"`
+		Body      string      `json:"body"`
+		Timestamp json.Number `json:"timestamp"`
+	} `json:"content"`
+	Authorship struct {
+		Contributor string `json:"contributor"`
+	} `json:"authorship"`
+	OriginalDigest string `json:"originalDigest"`
+}
+
+// listMirrorPosts lists a Mirror publication's entries from Arweave. Every edit of an entry is
+// a new transaction, so only the newest revision of each original digest is kept.
+func (c *PublicationCollector) listMirrorPosts(ctx context.Context, pub *publication, limit int) ([]publicationPost, error) {
+	query, err := json.Marshal(map[string]any{
+		"query":     arweaveQuery,
+		"variables": map[string]any{"contributor": pub.address, "first": min(limit*3, 100)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.post(ctx, c.arweaveURL+"/graphql", query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Arweave: %w", err)
+	}
+
+	var resp struct {
+		Data struct {
+			Transactions struct {
+				Edges []struct {
+					Node struct {
+						ID   string `json:"id"`
+						Tags []struct {
+							Name  string `json:"name"`
+							Value string `json:"value"`
+						} `json:"tags"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"transactions"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode Arweave response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var posts []publicationPost
+	for _, edge := range resp.Data.Transactions.Edges {
+		digest := ""
+		for _, tag := range edge.Node.Tags {
+			if tag.Name == "Original-Content-Digest" {
+				digest = tag.Value
+			}
+		}
+		if digest == "" || seen[digest] {
+			continue
+		}
+		seen[digest] = true
+
+		txID := edge.Node.ID
+		postURL := "https://mirror.xyz/" + pub.address + "/" + digest
+		posts = append(posts, publicationPost{
+			Title: digest,
+			URL:   postURL,
+			fetch: func(ctx context.Context) (*publicationPost, error) {
+				return c.fetchMirrorEntry(ctx, txID, postURL)
+			},
+		})
+		if len(posts) >= limit {
+			break
+		}
+	}
+	return posts, nil
+}
+
+// fetchMirrorEntry loads a Mirror entry's markdown from its Arweave transaction
+func (c *PublicationCollector) fetchMirrorEntry(ctx context.Context, txID, postURL string) (*publicationPost, error) {
+	body, err := c.get(ctx, c.arweaveURL+"/"+txID)
+	if err != nil {
+		return nil, err
+	}
+	var entry mirrorEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode Mirror entry: %w", err)
+	}
+	if entry.Content.Body == "" {
+		return nil, fmt.Errorf("Mirror entry %s has no content", txID)
+	}
+	post := &publicationPost{
+		Title:  entry.Content.Title,
+		Body:   entry.Content.Body,
+		URL:    postURL,
+		Author: entry.Authorship.Contributor,
+	}
+	if seconds, err := entry.Content.Timestamp.Int64(); err == nil && seconds > 0 {
+		published := time.Unix(seconds, 0).UTC()
+		post.PublishedAt = &published
+	}
+	return post, nil
+}
+
+// listParagraphPosts lists a Paragraph publication's posts with their markdown
+func (c *PublicationCollector) listParagraphPosts(ctx context.Context, pub *publication, limit int) ([]publicationPost, string, error) {
+	body, err := c.get(ctx, c.paragraphURL+"/v1/publications/slug/"+url.PathEscape(pub.slug))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load Paragraph publication: %w", err)
+	}
+	var publication struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &publication); err != nil || publication.ID == "" {
+		return nil, "", fmt.Errorf("unknown Paragraph publication: %s", pub.slug)
+	}
+
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("includeContent", "true")
+	body, err = c.get(ctx, c.paragraphURL+"/v1/publications/"+url.PathEscape(publication.ID)+"/posts?"+q.Encode())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list Paragraph posts: %w", err)
+	}
+	var resp struct {
+		Items []struct {
+			Title       string          `json:"title"`
+			Slug        string          `json:"slug"`
+			Markdown    string          `json:"markdown"`
+			PublishedAt json.RawMessage `json:"publishedAt"`
+			Authors     []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to decode Paragraph posts: %w", err)
+	}
+
+	posts := make([]publicationPost, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		if item.Markdown == "" {
+			continue
+		}
+		post := publicationPost{
+			Title:       item.Title,
+			Body:        item.Markdown,
+			URL:         "https://paragraph.com/@" + pub.slug + "/" + item.Slug,
+			PublishedAt: apiTime(item.PublishedAt),
+		}
+		if len(item.Authors) > 0 {
+			post.Author = item.Authors[0].Name
+		}
+		posts = append(posts, post)
+	}
+	return posts, publication.Name, nil
+}
+
+// apiTime parses a timestamp given either as an RFC 3339 string or as Unix milliseconds
+func apiTime(raw json.RawMessage) *time.Time {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return &t
+		}
+		raw = json.RawMessage(text)
+	}
+	millis, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || millis <= 0 {
+		return nil
+	}
+	t := time.UnixMilli(millis).UTC()
+	return &t
+}
+
+// get fetches a URL, failing on non-200 responses
+func (c *PublicationCollector) get(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.do(req)
+}
+
+// post sends a JSON body to a URL, failing on non-200 responses
+func (c *PublicationCollector) post(ctx context.Context, target string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req)
+}
+
+func (c *PublicationCollector) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+}
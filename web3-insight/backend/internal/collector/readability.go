@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Extraction confidence by strategy. Readability picks score between the generic selector and
+// the body fallback, depending on how much text they hold and how little of it is links.
+const (
+	confidenceSiteSelector    = 0.95
+	confidenceGenericSelector = 0.8
+	confidenceReadabilityMax  = 0.7
+	confidenceBody            = 0.1
+	confidencePDF             = 0.9 // No page chrome, though running headers and footers remain
+)
+
+// minContentChars is the least text a selector match needs to count as the article
+const minContentChars = 100
+
+// maxContentLinkDensity is the largest share of link text a selector match may have; more
+// means it matched navigation or a link list
+const maxContentLinkDensity = 0.5
+
+var (
+	positiveClassPattern = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story|prose|markdown|docs?`)
+	negativeClassPattern = regexp.MustCompile(`(?i)comment|meta|footer|footnote|foot|masthead|media|nav|sidebar|menu|share|social|related|promo|sponsor|ad-|ads|widget|breadcrumb|banner|subscribe|newsletter|cookie|popup|modal|toc`)
+)
+
+// textStats returns the whitespace-collapsed text length of a selection and the share of
+// it inside links
+func textStats(sel *goquery.Selection) (int, float64) {
+	textLen := len([]rune(strings.Join(strings.Fields(sel.Text()), " ")))
+	if textLen == 0 {
+		return 0, 0
+	}
+	linkLen := 0
+	sel.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLen += len([]rune(strings.Join(strings.Fields(a.Text()), " ")))
+	})
+	return textLen, math.Min(float64(linkLen)/float64(textLen), 1)
+}
+
+// looksLikeContent reports whether a selector match holds article text rather than
+// navigation or an empty wrapper
+func looksLikeContent(sel *goquery.Selection) bool {
+	textLen, linkDensity := textStats(sel)
+	return textLen >= minContentChars && linkDensity <= maxContentLinkDensity
+}
+
+// readabilityContent finds the element most likely to hold the article by scoring paragraphs
+// and crediting their parents and grandparents, in the manner of Arc90's Readability. It
+// returns nil when no element holds enough text.
+func readabilityContent(doc *goquery.Document) (*goquery.Selection, float64) {
+	scores := make(map[*html.Node]float64)
+	var candidates []*goquery.Selection
+
+	addScore := func(sel *goquery.Selection, score float64) {
+		if sel.Length() == 0 {
+			return
+		}
+		node := sel.Get(0)
+		if _, ok := scores[node]; !ok {
+			scores[node] = classWeight(sel) + tagWeight(node.Data)
+			candidates = append(candidates, sel)
+		}
+		scores[node] += score
+	}
+
+	doc.Find("p, pre, td, blockquote, li").Each(func(_ int, block *goquery.Selection) {
+		text := strings.Join(strings.Fields(block.Text()), " ")
+		if len([]rune(text)) < 25 {
+			return
+		}
+		// One point per block, per comma and per 100 characters (up to 3)
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")) +
+			math.Min(float64(len([]rune(text)))/100, 3)
+		addScore(block.Parent(), score)
+		addScore(block.Parent().Parent(), score/2)
+	})
+
+	var best *goquery.Selection
+	bestScore := 0.0
+	for _, sel := range candidates {
+		_, linkDensity := textStats(sel)
+		score := scores[sel.Get(0)] * (1 - linkDensity)
+		if score > bestScore {
+			best, bestScore = sel, score
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+
+	textLen, linkDensity := textStats(best)
+	if textLen < minContentChars {
+		return nil, 0
+	}
+	// More text and fewer links make the pick more trustworthy
+	confidence := 0.2 + 0.35*math.Min(float64(textLen)/1500, 1) + 0.15*(1-linkDensity)
+	return best, math.Min(confidence, confidenceReadabilityMax)
+}
+
+// classWeight scores an element's class and id: +25 for article-like names, -25 for
+// boilerplate names
+func classWeight(sel *goquery.Selection) float64 {
+	weight := 0.0
+	for _, attr := range []string{"class", "id"} {
+		value, ok := sel.Attr(attr)
+		if !ok || value == "" {
+			continue
+		}
+		if negativeClassPattern.MatchString(value) {
+			weight -= 25
+		}
+		if positiveClassPattern.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func tagWeight(tag string) float64 {
+	switch tag {
+	case "article", "main":
+		return 10
+	case "div", "section":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
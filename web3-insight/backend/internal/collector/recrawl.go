@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+)
+
+// DefaultMinChangedChars is how many characters must change on a page before a re-crawl
+// reports it as updated; smaller edits are typo fixes and layout noise
+const DefaultMinChangedChars = 200
+
+// maxDiffChars caps the diff stored on an "updated" item
+const maxDiffChars = 20000
+
+// ErrUnchanged is returned by Recrawl when a page did not change meaningfully
+var ErrUnchanged = errors.New("page did not change meaningfully")
+
+// Recrawl refetches a crawled item's page and diffs its markdown against the stored content.
+// When at least minChangedChars changed, it stores an "updated" news item holding the diff,
+// linked to the original through UpdateOf, and replaces the original's content. Returns
+// ErrNotModified or ErrUnchanged when there is nothing to report. The check is recorded on
+// the item whatever its outcome, so failing pages do not hold up the others.
+func (c *WebCrawler) Recrawl(ctx context.Context, item *model.NewsItem, minChangedChars int) (*model.NewsItem, error) {
+	if minChangedChars <= 0 {
+		minChangedChars = DefaultMinChangedChars
+	}
+
+	update, err := c.recrawl(ctx, item, minChangedChars)
+	if update == nil {
+		if markErr := c.newsRepo.FinishRecrawl(item.ID, ""); markErr != nil {
+			log.Printf("Warning: failed to record re-crawl of %s: %v", item.SourceURL, markErr)
+		}
+	}
+	return update, err
+}
+
+// recrawl does the work of Recrawl, leaving the item's check unrecorded unless its content
+// was replaced
+func (c *WebCrawler) recrawl(ctx context.Context, item *model.NewsItem, minChangedChars int) (*model.NewsItem, error) {
+	result, err := c.CrawlIfModified(ctx, item.SourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if result.Blocked != "" {
+		// Never diff against a consent wall or paywall snippet
+		return nil, fmt.Errorf("page now shows a %s", result.Blocked)
+	}
+	if strings.TrimSpace(result.Content) == "" {
+		return nil, fmt.Errorf("no content extracted from %s", item.SourceURL)
+	}
+
+	diff := DiffContent(item.Content, result.Content)
+	if diff.ChangedChars < minChangedChars {
+		return nil, ErrUnchanged
+	}
+
+	title := result.Title
+	if title == "" {
+		title = item.Title
+	}
+	now := time.Now()
+	update := &model.NewsItem{
+		Title:          "Updated: " + title,
+		OriginalTitle:  title,
+		Content:        updateContent(item.SourceURL, diff),
+		SourceURL:      fmt.Sprintf("%s#updated-%s", strings.SplitN(item.SourceURL, "#", 2)[0], now.UTC().Format("20060102T150405")),
+		SourceName:     item.SourceName,
+		SourceLanguage: item.SourceLanguage,
+		Author:         item.Author,
+		License:        item.License,
+		LicenseURL:     item.LicenseURL,
+		Category:       item.Category,
+		Tags:           item.Tags,
+		PublishedAt:    &now,
+		FetchedAt:      now,
+		UpdateOf:       &item.ID,
+	}
+	if _, err := c.newsRepo.CreateOrIgnore(update); err != nil {
+		return nil, fmt.Errorf("failed to save update of %s: %w", item.SourceURL, err)
+	}
+	if err := c.newsRepo.FinishRecrawl(item.ID, result.Content); err != nil {
+		return update, fmt.Errorf("failed to store new content of %s: %w", item.SourceURL, err)
+	}
+
+	log.Printf("Page changed since last crawl: %s (+%d/-%d lines)", item.SourceURL, diff.AddedLines, diff.RemovedLines)
+	return update, nil
+}
+
+// RecrawlResult counts the outcomes of a re-crawl run
+type RecrawlResult struct {
+	Checked   int
+	Unchanged int
+	Updated   int
+	Failed    int
+}
+
+// RecrawlStale re-crawls up to limit crawled items last checked before the given time
+func (c *WebCrawler) RecrawlStale(ctx context.Context, before time.Time, limit, minChangedChars int) (*RecrawlResult, error) {
+	items, err := c.newsRepo.FindRecrawlCandidates(before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pages to re-crawl: %w", err)
+	}
+
+	result := &RecrawlResult{}
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result.Checked++
+		_, err := c.Recrawl(ctx, &items[i], minChangedChars)
+		switch {
+		case err == nil:
+			result.Updated++
+		case errors.Is(err, ErrNotModified), errors.Is(err, ErrUnchanged):
+			result.Unchanged++
+		default:
+			log.Printf("Re-crawl of %s failed: %v", items[i].SourceURL, err)
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// updateContent renders the markdown body of an "updated" item
+func updateContent(pageURL string, diff *ContentDiff) string {
+	unified := diff.Unified()
+	if len(unified) > maxDiffChars {
+		cut := strings.LastIndex(unified[:maxDiffChars], "\n")
+		unified = unified[:cut+1] + "... (diff truncated)\n"
+	}
+	// A fence longer than any backtick run in the diff keeps it intact
+	fence := "```"
+	for strings.Contains(unified, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("The page at %s changed: %d lines added, %d removed.\n\n%sdiff\n%s%s",
+		pageURL, diff.AddedLines, diff.RemovedLines, fence, unified, fence)
+}
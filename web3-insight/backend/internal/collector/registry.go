@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// ErrUnsupportedSourceType is returned when no collector is registered for a source type
+var ErrUnsupportedSourceType = errors.New("no collector registered for source type")
+
+// Registry maps DataSource.Type to the collector that ingests it, and records
+// fetch health on the data source after every collection
+type Registry struct {
+	collectors map[string]Collector
+	dsRepo     *repository.DataSourceRepository
+	mu         sync.RWMutex
+}
+
+// NewRegistry creates an empty collector registry
+func NewRegistry(dsRepo *repository.DataSourceRepository) *Registry {
+	return &Registry{
+		collectors: make(map[string]Collector),
+		dsRepo:     dsRepo,
+	}
+}
+
+// NewDefaultRegistry creates a registry with the built-in collectors
+func NewDefaultRegistry(rssCollector *RSSCollector, webCrawler *WebCrawler, newsRepo *repository.NewsRepository, dsRepo *repository.DataSourceRepository, cfg *config.CollectorConfig) *Registry {
+	r := NewRegistry(dsRepo)
+	r.Register(rssCollector)
+	r.Register(webCrawler)
+	r.Register(NewPublicationCollector(newsRepo))
+	r.Register(NewResearchCollector(newsRepo, NewPDFExtractorFromConfig(cfg)))
+	r.Register(NewDiscourseCollector(newsRepo))
+	r.Register(NewGovernanceCollector(newsRepo, cfg.TallyAPIKey))
+	r.Register(NewEmailCollector(newsRepo))
+	r.Register(NewAPICollector(newsRepo))
+	return r
+}
+
+// Register adds a collector, replacing any existing one for the same type
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[c.Type()] = c
+}
+
+// Get returns the collector for a source type
+func (r *Registry) Get(sourceType string) (Collector, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.collectors[sourceType]
+	return c, ok
+}
+
+// Types returns the registered source types, sorted
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.collectors))
+	for t := range r.collectors {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// collectorFor returns the collector for a source or ErrUnsupportedSourceType
+func (r *Registry) collectorFor(source *model.DataSource) (Collector, error) {
+	c, ok := r.Get(source.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSourceType, source.Type)
+	}
+	return c, nil
+}
+
+// Validate validates a source with its type's collector
+func (r *Registry) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	c, err := r.collectorFor(source)
+	if err != nil {
+		return nil, err
+	}
+	return c.Validate(ctx, source)
+}
+
+// Preview previews a source with its type's collector
+func (r *Registry) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	c, err := r.collectorFor(source)
+	if err != nil {
+		return nil, err
+	}
+	return c.Preview(ctx, source)
+}
+
+// Sync collects a source by ID
+func (r *Registry) Sync(ctx context.Context, sourceID uuid.UUID) (*CollectResult, error) {
+	source, err := r.dsRepo.FindByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data source: %w", err)
+	}
+	return r.SyncSource(ctx, source)
+}
+
+// SyncSource collects a source and records the fetch time and error on it
+func (r *Registry) SyncSource(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	c, err := r.collectorFor(source)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.Collect(ctx, source)
+
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	if updateErr := r.dsRepo.UpdateLastFetched(source.ID, time.Now(), lastError); updateErr != nil {
+		log.Printf("Failed to update fetch status for %s: %v", source.Name, updateErr)
+	}
+
+	return result, err
+}
+
+// SyncType collects all enabled sources of one type
+func (r *Registry) SyncType(ctx context.Context, sourceType string) ([]*CollectResult, error) {
+	sources, err := r.dsRepo.FindByType(sourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s sources: %w", sourceType, err)
+	}
+	return r.syncAll(ctx, sources), nil
+}
+
+// SyncDue collects every enabled source whose fetch interval has elapsed
+func (r *Registry) SyncDue(ctx context.Context) ([]*CollectResult, error) {
+	sources, err := r.dsRepo.FindDueForFetch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due sources: %w", err)
+	}
+
+	supported := sources[:0]
+	for _, source := range sources {
+		if _, ok := r.Get(source.Type); ok {
+			supported = append(supported, source)
+		}
+	}
+	return r.syncAll(ctx, supported), nil
+}
+
+// syncAll collects sources one after another, logging failures
+func (r *Registry) syncAll(ctx context.Context, sources []model.DataSource) []*CollectResult {
+	var results []*CollectResult
+	for i := range sources {
+		if ctx.Err() != nil {
+			break
+		}
+		source := &sources[i]
+		result, err := r.SyncSource(ctx, source)
+		if err != nil {
+			log.Printf("Failed to collect from %s: %v", source.Name, err)
+			result = &CollectResult{
+				SourceID: source.ID,
+				Errors:   []error{err},
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
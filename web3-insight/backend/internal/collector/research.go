@@ -0,0 +1,361 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/user/web3-insight/internal/markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// ResearchTag is added to every news item from a research source
+const ResearchTag = "research"
+
+// Research source defaults
+const (
+	defaultResearchResults  = 50
+	defaultFullTextMaxRunes = 100000
+	arxivRequestDelay       = 3 * time.Second // arXiv asks API clients to wait 3 seconds between requests
+)
+
+var arxivVersion = regexp.MustCompile(`v\d+$`)
+
+// ResearchCollector ingests papers from arXiv API queries and the IACR ePrint feed. Abstracts
+// are stored with a link to the PDF, and with the paper's full text when a PDF extractor is
+// configured and the source asks for it.
+type ResearchCollector struct {
+	parser      *gofeed.Parser
+	newsRepo    *repository.NewsRepository
+	pdf         *PDFExtractor
+	rateLimiter *RateLimiter
+}
+
+// NewResearchCollector creates a new paper collector. pdf may be nil to store abstracts only.
+func NewResearchCollector(newsRepo *repository.NewsRepository, pdf *PDFExtractor) *ResearchCollector {
+	parser := gofeed.NewParser()
+	parser.UserAgent = "Web3-Insight/1.0 (Research Reader)"
+
+	return &ResearchCollector{
+		parser:      parser,
+		newsRepo:    newsRepo,
+		pdf:         pdf,
+		rateLimiter: NewRateLimiter(arxivRequestDelay, arxivRequestDelay),
+	}
+}
+
+// ResearchConfig holds research-specific configuration
+type ResearchConfig struct {
+	DefaultCategory  string `json:"defaultCategory,omitempty"`
+	MaxResults       int    `json:"maxResults,omitempty"`       // arXiv results per sync; defaults to 50
+	FullText         bool   `json:"fullText,omitempty"`         // Extract the PDF text, if a PDF extractor is configured
+	FullTextMaxRunes int    `json:"fullTextMaxRunes,omitempty"` // Longest full text kept; defaults to 100000
+}
+
+// paper is a paper listed by a research source
+type paper struct {
+	Title       string
+	Abstract    string
+	URL         string
+	PDFURL      string
+	Authors     []string
+	Categories  []string
+	PublishedAt *time.Time
+}
+
+// Type returns the data source type handled by this collector
+func (c *ResearchCollector) Type() string {
+	return model.DataSourceTypeResearch
+}
+
+// Validate checks that the source URL is an arXiv API query or the ePrint feed
+func (c *ResearchCollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	feed, papers, err := c.fetch(ctx, source.URL, parseResearchConfig(source))
+	if err != nil {
+		return nil, err
+	}
+	return &ValidationResult{
+		Title:       feed.Title,
+		Description: feed.Description,
+		ItemCount:   len(papers),
+	}, nil
+}
+
+// Collect fetches new papers and stores them as news items tagged research
+func (c *ResearchCollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+
+	if source.Type != model.DataSourceTypeResearch {
+		return nil, fmt.Errorf("data source is not research type: %s", source.Type)
+	}
+
+	config := parseResearchConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+
+	_, papers, err := c.fetch(ctx, source.URL, config)
+	if err != nil {
+		return nil, err
+	}
+	result.ItemsFound = len(papers)
+
+	var newsItems []model.NewsItem
+	for _, p := range papers {
+		if ok, reason := filter.Allow(p.Title, p.Abstract); !ok {
+			log.Printf("Filtered item from %s: %s (%s)", source.Name, p.Title, reason)
+			result.ItemsFiltered++
+			continue
+		}
+		if existing, err := c.newsRepo.FindBySourceURL(p.URL); err == nil && existing != nil {
+			continue
+		}
+
+		fullText := ""
+		if config.FullText && c.pdf.Enabled() && p.PDFURL != "" {
+			c.rateLimiter.Wait(pdfHost(p.PDFURL))
+			if fullText, err = c.pdf.ExtractURL(ctx, p.PDFURL); err != nil {
+				// The abstract is still worth keeping
+				log.Printf("Failed to extract text of %s: %v", p.PDFURL, err)
+				result.Errors = append(result.Errors, err)
+			}
+		}
+		newsItems = append(newsItems, p.newsItem(source.Name, config, fullText))
+	}
+
+	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+	result.ItemsNew = newCount
+
+	log.Printf("Research sync completed for %s: found=%d, new=%d, filtered=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered)
+
+	return result, nil
+}
+
+// Preview lists the papers a sync would store, without their full text
+func (c *ResearchCollector) Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error) {
+	config := parseResearchConfig(source)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	_, papers, err := c.fetch(ctx, source.URL, config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(papers),
+		Items:      make([]PreviewItem, 0, len(papers)),
+	}
+	for _, p := range papers {
+		preview := PreviewItem{
+			Title:       p.Title,
+			URL:         p.URL,
+			PublishedAt: p.PublishedAt,
+			Excerpt:     previewExcerpt(p.Abstract),
+			Language:    "en",
+		}
+		if ok, reason := filter.Allow(p.Title, p.Abstract); !ok {
+			preview.Filtered = true
+			preview.FilterReason = reason
+			result.ItemsFiltered++
+		} else if existing, err := c.newsRepo.FindBySourceURL(p.URL); err == nil && existing != nil {
+			preview.Exists = true
+		} else {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+
+	return result, nil
+}
+
+// parseResearchConfig reads a source's config, applying defaults
+func parseResearchConfig(source *model.DataSource) ResearchConfig {
+	var config ResearchConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			log.Printf("Warning: failed to parse research config: %v", err)
+		}
+	}
+	if config.MaxResults <= 0 {
+		config.MaxResults = defaultResearchResults
+	}
+	if config.FullTextMaxRunes <= 0 {
+		config.FullTextMaxRunes = defaultFullTextMaxRunes
+	}
+	return config
+}
+
+// fetch loads the papers of an arXiv API query or the ePrint feed, newest first
+func (c *ResearchCollector) fetch(ctx context.Context, sourceURL string, config ResearchConfig) (*gofeed.Feed, []paper, error) {
+	u, err := url.Parse(sourceURL)
+	if err != nil || u.Host == "" {
+		return nil, nil, fmt.Errorf("invalid URL: %s", sourceURL)
+	}
+
+	switch host := strings.ToLower(u.Host); {
+	case host == "export.arxiv.org" || host == "arxiv.org":
+		if u.Path != "/api/query" {
+			return nil, nil, fmt.Errorf("expected an arXiv API query like https://export.arxiv.org/api/query?search_query=cat:cs.CR+AND+abs:blockchain")
+		}
+		q := u.Query()
+		if q.Get("search_query") == "" && q.Get("id_list") == "" {
+			return nil, nil, fmt.Errorf("arXiv query needs search_query or id_list")
+		}
+		// Newest papers first, so each sync sees what was submitted since the last one
+		if q.Get("sortBy") == "" {
+			q.Set("sortBy", "submittedDate")
+			q.Set("sortOrder", "descending")
+		}
+		if q.Get("max_results") == "" {
+			q.Set("max_results", strconv.Itoa(config.MaxResults))
+		}
+		u.RawQuery = q.Encode()
+
+		c.rateLimiter.Wait(u.Host)
+		feed, err := c.parser.ParseURLWithContext(u.String(), ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query arXiv: %w", err)
+		}
+		papers := make([]paper, 0, len(feed.Items))
+		for _, item := range feed.Items {
+			papers = append(papers, arxivPaper(item))
+		}
+		return feed, papers, nil
+
+	case host == "eprint.iacr.org":
+		feed, err := c.parser.ParseURLWithContext(sourceURL, ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse ePrint feed: %w", err)
+		}
+		papers := make([]paper, 0, len(feed.Items))
+		for _, item := range feed.Items {
+			papers = append(papers, eprintPaper(item))
+		}
+		return feed, papers, nil
+	}
+	return nil, nil, fmt.Errorf("not an arXiv API query or IACR ePrint feed: %s", u.Host)
+}
+
+// arxivPaper converts an arXiv API entry. The abstract page URL without its version is used
+// as the source URL, so revisions of a paper are stored once, and the PDF link points to the
+// latest revision.
+func arxivPaper(item *gofeed.Item) paper {
+	p := paper{
+		Title:      strings.Join(strings.Fields(item.Title), " "),
+		Abstract:   strings.TrimSpace(item.Description),
+		URL:        arxivVersion.ReplaceAllString(httpsURL(item.Link), ""),
+		Categories: item.Categories,
+		Authors:    authorNames(item.Authors),
+	}
+	if p.Abstract == "" {
+		p.Abstract = strings.TrimSpace(item.Content)
+	}
+	// The feed only keeps the abstract page link; the PDF sits at the same ID under /pdf/
+	if strings.Contains(p.URL, "/abs/") {
+		p.PDFURL = strings.Replace(p.URL, "/abs/", "/pdf/", 1)
+	}
+	if item.PublishedParsed != nil {
+		published := item.PublishedParsed.UTC()
+		p.PublishedAt = &published
+	}
+	return p
+}
+
+// eprintPaper converts an ePrint feed item; papers at eprint.iacr.org/<year>/<number> have
+// their PDF at the same path with .pdf
+func eprintPaper(item *gofeed.Item) paper {
+	p := paper{
+		Title:      strings.TrimSpace(item.Title),
+		Abstract:   strings.TrimSpace(item.Description),
+		URL:        httpsURL(item.Link),
+		Categories: item.Categories,
+		Authors:    authorNames(item.Authors),
+	}
+	if p.URL != "" && !strings.HasSuffix(p.URL, ".pdf") {
+		p.PDFURL = p.URL + ".pdf"
+	}
+	if item.PublishedParsed != nil {
+		published := item.PublishedParsed.UTC()
+		p.PublishedAt = &published
+	} else if item.UpdatedParsed != nil {
+		updated := item.UpdatedParsed.UTC()
+		p.PublishedAt = &updated
+	}
+	return p
+}
+
+// newsItem converts a paper to a news item: the abstract with a link to the PDF, followed by
+// the full text if it was extracted
+func (p paper) newsItem(sourceName string, config ResearchConfig, fullText string) model.NewsItem {
+	var content strings.Builder
+	content.WriteString(p.Abstract)
+	if p.PDFURL != "" {
+		fmt.Fprintf(&content, "\n\n[PDF](%s)", p.PDFURL)
+	}
+	if fullText != "" {
+		content.WriteString("\n\n## Full text\n\n")
+		content.WriteString(markdown.Truncate(fullText, config.FullTextMaxRunes))
+	}
+
+	tags := []string{ResearchTag}
+	for _, category := range p.Categories {
+		if category != "" && !strings.EqualFold(category, ResearchTag) {
+			tags = append(tags, category)
+		}
+	}
+
+	return model.NewsItem{
+		Title:          p.Title,
+		OriginalTitle:  p.Title,
+		Content:        content.String(),
+		SourceURL:      p.URL,
+		SourceName:     sourceName,
+		SourceLanguage: "en",
+		Author:         markdown.Truncate(strings.Join(p.Authors, ", "), 200),
+		Category:       config.DefaultCategory,
+		Tags:           tags,
+		PublishedAt:    p.PublishedAt,
+		FetchedAt:      time.Now(),
+	}
+}
+
+// authorNames returns the names of feed authors
+func authorNames(authors []*gofeed.Person) []string {
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		if a != nil && a.Name != "" {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// httpsURL upgrades an http URL; arXiv lists its links with http
+func httpsURL(link string) string {
+	return strings.Replace(strings.TrimSpace(link), "http://", "https://", 1)
+}
+
+// pdfHost returns the host of a PDF URL for rate limiting
+func pdfHost(pdfURL string) string {
+	if u, err := url.Parse(pdfURL); err == nil {
+		return u.Host
+	}
+	return pdfURL
+}
@@ -0,0 +1,236 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsAgent is the product token matched against User-agent lines of robots.txt
+const robotsAgent = "web3-insight"
+
+// crawlerUserAgent is sent with every page the crawler fetches. It names robotsAgent, so the
+// rules a site writes for this crawler are the ones it sees being obeyed.
+const crawlerUserAgent = "Mozilla/5.0 (compatible; Web3-Insight/1.0)"
+
+// How long a host's robots.txt is cached, and how long an unreachable one blocks the host
+const (
+	robotsTTL      = 12 * time.Hour
+	robotsErrorTTL = 30 * time.Minute
+)
+
+// ErrDisallowedByRobots is returned when robots.txt forbids crawling a URL
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// robotsRule is one Allow or Disallow line
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// RobotsRules are the robots.txt rules of a host that apply to this crawler
+type RobotsRules struct {
+	rules      []robotsRule
+	CrawlDelay time.Duration
+	Sitemaps   []string
+}
+
+// Allowed reports whether a path (with its query) may be crawled. The longest matching rule
+// wins and Allow wins ties, as in RFC 9309.
+func (r *RobotsRules) Allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+	if path == "" {
+		path = "/"
+	}
+	best := -1
+	allowed := true
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > best || (len(rule.pattern) == best && rule.allow) {
+			best = len(rule.pattern)
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// robotsMatch matches a path against a robots.txt pattern supporting * and a trailing $
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// parseRobots reads the group for our agent, falling back to the * group
+func parseRobots(r io.Reader) *RobotsRules {
+	type group struct {
+		rules []robotsRule
+		delay time.Duration
+	}
+	var specific, wildcard *group
+	var current []*group
+	inAgents := false
+	var sitemaps []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			agent := strings.ToLower(value)
+			switch {
+			case strings.Contains(agent, robotsAgent):
+				if specific == nil {
+					specific = &group{}
+				}
+				current = append(current, specific)
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &group{}
+				}
+				current = append(current, wildcard)
+			}
+		case "allow", "disallow":
+			inAgents = false
+			if value == "" {
+				continue // An empty Disallow allows everything
+			}
+			for _, g := range current {
+				g.rules = append(g.rules, robotsRule{pattern: value, allow: key == "allow"})
+			}
+		case "crawl-delay":
+			inAgents = false
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				for _, g := range current {
+					g.delay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		case "sitemap":
+			sitemaps = append(sitemaps, value)
+		default:
+			inAgents = false
+		}
+	}
+
+	rules := &RobotsRules{Sitemaps: sitemaps}
+	g := specific
+	if g == nil {
+		g = wildcard
+	}
+	if g != nil {
+		rules.rules = g.rules
+		rules.CrawlDelay = g.delay
+	}
+	return rules
+}
+
+// robotsEntry is a cached robots.txt
+type robotsEntry struct {
+	rules   *RobotsRules
+	expires time.Time
+}
+
+// RobotsCache fetches robots.txt once per host and caches the parsed rules
+type RobotsCache struct {
+	client  *http.Client
+	entries map[string]robotsEntry
+	mu      sync.Mutex
+}
+
+// NewRobotsCache creates an empty robots.txt cache
+func NewRobotsCache() *RobotsCache {
+	return &RobotsCache{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		entries: make(map[string]robotsEntry),
+	}
+}
+
+// Rules returns the robots.txt rules of scheme://host. A missing robots.txt (4xx) allows
+// everything; a server error or unreachable host disallows everything until the entry expires.
+func (c *RobotsCache) Rules(ctx context.Context, scheme, host string) *RobotsRules {
+	key := scheme + "://" + host
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.rules
+	}
+
+	rules, err := c.fetch(ctx, key+"/robots.txt")
+	ttl := robotsTTL
+	if err != nil {
+		rules = &RobotsRules{rules: []robotsRule{{pattern: "/"}}}
+		if ctx.Err() != nil {
+			// The caller gave up, which says nothing about the host
+			return rules
+		}
+		ttl = robotsErrorTTL
+	}
+	c.mu.Lock()
+	c.entries[key] = robotsEntry{rules: rules, expires: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return rules
+}
+
+// fetch downloads and parses a robots.txt
+func (c *RobotsCache) fetch(ctx context.Context, target string) (*RobotsRules, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return parseRobots(io.LimitReader(resp.Body, 512<<10)), nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &RobotsRules{}, nil
+	default:
+		return nil, fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+	}
+}
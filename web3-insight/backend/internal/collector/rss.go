@@ -1,13 +1,16 @@
 package collector
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/mmcdole/gofeed"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
@@ -15,20 +18,25 @@ import (
 
 // RSSCollector handles RSS feed collection
 type RSSCollector struct {
-	parser   *gofeed.Parser
-	newsRepo *repository.NewsRepository
-	dsRepo   *repository.DataSourceRepository
+	parser     *gofeed.Parser
+	newsRepo   *repository.NewsRepository
+	dsRepo     *repository.DataSourceRepository
+	fetchCache *FetchCache
+	client     *http.Client
 }
 
-// NewRSSCollector creates a new RSS collector
-func NewRSSCollector(newsRepo *repository.NewsRepository, dsRepo *repository.DataSourceRepository) *RSSCollector {
+// NewRSSCollector creates a new RSS collector. With a fetch cache, syncs refetch feeds
+// conditionally and skip unchanged ones.
+func NewRSSCollector(newsRepo *repository.NewsRepository, dsRepo *repository.DataSourceRepository, fetchCache *FetchCache) *RSSCollector {
 	parser := gofeed.NewParser()
 	parser.UserAgent = "Web3-Insight/1.0 (RSS Reader)"
 
 	return &RSSCollector{
-		parser:   parser,
-		newsRepo: newsRepo,
-		dsRepo:   dsRepo,
+		parser:     parser,
+		newsRepo:   newsRepo,
+		dsRepo:     dsRepo,
+		fetchCache: fetchCache,
+		client:     &http.Client{Timeout: 60 * time.Second},
 	}
 }
 
@@ -36,17 +44,30 @@ func NewRSSCollector(newsRepo *repository.NewsRepository, dsRepo *repository.Dat
 type RSSConfig struct {
 	DefaultCategory string `json:"defaultCategory,omitempty"`
 	Language        string `json:"language,omitempty"`
+	Timezone        string `json:"timezone,omitempty"` // IANA zone for item dates without an offset; defaults to UTC
 }
 
-// Collect fetches and stores items from an RSS feed
-func (c *RSSCollector) Collect(ctx context.Context, sourceID uuid.UUID) (*CollectResult, error) {
-	result := &CollectResult{SourceID: sourceID}
+// Type returns the data source type handled by this collector
+func (c *RSSCollector) Type() string {
+	return model.DataSourceTypeRSS
+}
 
-	// Get data source
-	source, err := c.dsRepo.FindByID(sourceID)
+// Validate checks that the source URL is a parseable feed
+func (c *RSSCollector) Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error) {
+	feed, err := c.ValidateFeedURL(source.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find data source: %w", err)
+		return nil, err
 	}
+	return &ValidationResult{
+		Title:       feed.Title,
+		Description: feed.Description,
+		ItemCount:   len(feed.Items),
+	}, nil
+}
+
+// Collect fetches and stores items from an RSS feed
+func (c *RSSCollector) Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
 
 	if source.Type != model.DataSourceTypeRSS {
 		return nil, fmt.Errorf("data source is not RSS type: %s", source.Type)
@@ -60,41 +81,85 @@ func (c *RSSCollector) Collect(ctx context.Context, sourceID uuid.UUID) (*Collec
 		}
 	}
 
-	// Fetch and parse feed
-	feed, err := c.parser.ParseURLWithContext(source.URL, ctx)
+	filter, err := ParseItemFilter(source.Config)
+	if err != nil {
+		log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+	}
+
+	// Fetch and parse feed, unless it is unchanged since the last sync
+	feed, err := c.fetchFeed(ctx, source.URL)
+	if errors.Is(err, ErrNotModified) {
+		log.Printf("RSS feed unchanged for %s", source.Name)
+		return result, nil
+	}
 	if err != nil {
-		// Update source with error
-		c.dsRepo.UpdateLastFetched(sourceID, time.Now(), err.Error())
 		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
 	}
 
 	result.ItemsFound = len(feed.Items)
 
+	// Feed-level rights apply to every item
+	license := NormalizeLicense(feed.Copyright)
+	licenseURL := LicenseURL(license, feed.Copyright)
+
 	// Convert feed items to news items
 	var newsItems []model.NewsItem
 	for _, item := range feed.Items {
 		newsItem := c.convertFeedItem(item, source.Name, config)
+		newsItem.License = license
+		newsItem.LicenseURL = licenseURL
+		if ok, reason := filter.Allow(newsItem.Title, newsItem.Content); !ok {
+			log.Printf("Filtered item from %s: %s (%s)", source.Name, newsItem.Title, reason)
+			result.ItemsFiltered++
+			continue
+		}
 		newsItems = append(newsItems, newsItem)
 	}
 
 	// Batch insert, ignoring duplicates
 	newCount, err := c.newsRepo.BatchCreateOrIgnore(newsItems)
 	if err != nil {
+		c.fetchCache.Forget(source.URL)
 		result.Errors = append(result.Errors, err)
-		c.dsRepo.UpdateLastFetched(sourceID, time.Now(), err.Error())
 		return result, err
 	}
 
 	result.ItemsNew = newCount
 
-	// Update last fetched
-	c.dsRepo.UpdateLastFetched(sourceID, time.Now(), "")
-
-	log.Printf("RSS sync completed for %s: found=%d, new=%d", source.Name, result.ItemsFound, result.ItemsNew)
+	log.Printf("RSS sync completed for %s: found=%d, new=%d, filtered=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered)
 
 	return result, nil
 }
 
+// fetchFeed downloads a feed conditionally and parses it, returning ErrNotModified when the
+// server answers 304 or the body is unchanged
+func (c *RSSCollector) fetchFeed(ctx context.Context, feedURL string) (*gofeed.Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.parser.UserAgent)
+	c.fetchCache.Prepare(feedURL, req.Header)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotModified {
+		return nil, gofeed.HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+	if err != nil {
+		return nil, err
+	}
+	if !c.fetchCache.Changed(feedURL, resp.StatusCode, resp.Header, body) {
+		return nil, ErrNotModified
+	}
+	return c.parser.Parse(bytes.NewReader(body))
+}
+
 // convertFeedItem converts a gofeed.Item to model.NewsItem
 func (c *RSSCollector) convertFeedItem(item *gofeed.Item, sourceName string, config RSSConfig) model.NewsItem {
 	newsItem := model.NewsItem{
@@ -117,9 +182,13 @@ func (c *RSSCollector) convertFeedItem(item *gofeed.Item, sourceName string, con
 
 	// Parse published date
 	if item.PublishedParsed != nil {
-		newsItem.PublishedAt = item.PublishedParsed
+		newsItem.PublishedAt = feedTime(item.Published, item.PublishedParsed, config.location())
 	} else if item.UpdatedParsed != nil {
-		newsItem.PublishedAt = item.UpdatedParsed
+		newsItem.PublishedAt = feedTime(item.Updated, item.UpdatedParsed, config.location())
+	}
+
+	if item.Author != nil {
+		newsItem.Author = item.Author.Name
 	}
 
 	// Extract tags from categories
@@ -157,29 +226,6 @@ func detectLanguage(content string) string {
 	return "en"
 }
 
-// CollectAll collects from all enabled RSS sources
-func (c *RSSCollector) CollectAll(ctx context.Context) ([]*CollectResult, error) {
-	sources, err := c.dsRepo.FindByType(model.DataSourceTypeRSS)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find RSS sources: %w", err)
-	}
-
-	var results []*CollectResult
-	for _, source := range sources {
-		result, err := c.Collect(ctx, source.ID)
-		if err != nil {
-			log.Printf("Failed to collect from %s: %v", source.Name, err)
-			result = &CollectResult{
-				SourceID: source.ID,
-				Errors:   []error{err},
-			}
-		}
-		results = append(results, result)
-	}
-
-	return results, nil
-}
-
 // ValidateFeedURL checks if a URL is a valid RSS feed
 func (c *RSSCollector) ValidateFeedURL(url string) (*gofeed.Feed, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
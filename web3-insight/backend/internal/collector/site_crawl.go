@@ -0,0 +1,212 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	defaultSiteCrawlPages = 50
+	maxSiteCrawlPages     = 500
+	maxSiteCrawlDepth     = 5
+)
+
+// skippedLinkExtensions are file types never followed by site crawls
+var skippedLinkExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true,
+	".css": true, ".js": true, ".json": true, ".xml": true, ".txt": true, ".woff": true, ".woff2": true,
+	".zip": true, ".gz": true, ".tar": true, ".mp3": true, ".mp4": true, ".webm": true,
+}
+
+// SiteCrawlScope limits how far CrawlSite follows links from its start page. Links are only
+// followed within the start page's host (with or without "www.").
+type SiteCrawlScope struct {
+	Depth    int              // Link hops from the start page; 0 crawls only the start page
+	Include  []*regexp.Regexp // When set, followed URLs must match one of these
+	Exclude  []*regexp.Regexp // URLs matching any of these are not followed
+	MaxPages int              // Pages fetched per crawl
+}
+
+// NewSiteCrawlScope compiles the include and exclude patterns of a site crawl and clamps its
+// depth and page cap
+func NewSiteCrawlScope(depth int, include, exclude []string, maxPages int) (*SiteCrawlScope, error) {
+	scope := &SiteCrawlScope{Depth: depth, MaxPages: maxPages}
+	if scope.Depth < 0 {
+		scope.Depth = 0
+	}
+	if scope.Depth > maxSiteCrawlDepth {
+		scope.Depth = maxSiteCrawlDepth
+	}
+	if scope.MaxPages <= 0 {
+		scope.MaxPages = defaultSiteCrawlPages
+	}
+	if scope.MaxPages > maxSiteCrawlPages {
+		scope.MaxPages = maxSiteCrawlPages
+	}
+
+	var err error
+	if scope.Include, err = compilePatterns(include); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if scope.Exclude, err = compilePatterns(exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	return scope, nil
+}
+
+// compilePatterns compiles non-empty regular expressions
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// follows reports whether a link found on a page should be crawled
+func (s *SiteCrawlScope) follows(host, link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	if strings.TrimPrefix(strings.ToLower(parsed.Host), "www.") != host {
+		return false
+	}
+	if skippedLinkExtensions[strings.ToLower(path.Ext(parsed.Path))] {
+		return false
+	}
+	for _, re := range s.Exclude {
+		if re.MatchString(link) {
+			return false
+		}
+	}
+	if len(s.Include) == 0 {
+		return true
+	}
+	for _, re := range s.Include {
+		if re.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
+
+// SiteCrawlResult summarizes a site crawl
+type SiteCrawlResult struct {
+	PagesCrawled  int
+	PagesFailed   int
+	PagesFiltered int
+	ItemsNew      int
+}
+
+// CrawlSite crawls a start page and the pages it links to, breadth first, up to the scope's
+// depth and page cap, saving each page the filter keeps as a news item. Links of filtered
+// pages are still followed. Pages already stored are only fetched again when their links are
+// still needed.
+func (c *WebCrawler) CrawlSite(ctx context.Context, startURL, sourceName string, scope *SiteCrawlScope, filter *ItemFilter) (*SiteCrawlResult, error) {
+	start, err := url.Parse(startURL)
+	if err != nil || start.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %s", startURL)
+	}
+	host := strings.TrimPrefix(strings.ToLower(start.Host), "www.")
+	start.Fragment = ""
+
+	type queuedPage struct {
+		url   string
+		depth int
+	}
+	queue := []queuedPage{{url: start.String()}}
+	seen := map[string]bool{start.String(): true}
+	result := &SiteCrawlResult{}
+
+	for len(queue) > 0 && result.PagesCrawled < scope.MaxPages {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		page := queue[0]
+		queue = queue[1:]
+		followLinks := page.depth < scope.Depth
+
+		existing, err := c.newsRepo.FindBySourceURL(page.url)
+		stored := err == nil && existing != nil
+		if stored && !followLinks {
+			continue
+		}
+
+		crawled, err := c.Crawl(ctx, page.url)
+		result.PagesCrawled++
+		if err != nil {
+			log.Printf("Site crawl of %s: failed to crawl %s: %v", startURL, page.url, err)
+			result.PagesFailed++
+			continue
+		}
+
+		if !stored && strings.TrimSpace(crawled.Content) != "" {
+			if _, created, err := c.save(page.url, crawled, sourceName, filter); errors.Is(err, ErrItemFiltered) {
+				result.PagesFiltered++
+			} else if err != nil {
+				log.Printf("Site crawl of %s: %v", startURL, err)
+				result.PagesFailed++
+			} else if created {
+				result.ItemsNew++
+			}
+		}
+
+		if !followLinks {
+			continue
+		}
+		for _, link := range crawled.Links {
+			if seen[link] || !scope.follows(host, link) {
+				continue
+			}
+			seen[link] = true
+			queue = append(queue, queuedPage{url: link, depth: page.depth + 1})
+		}
+	}
+
+	return result, nil
+}
+
+// pageLinks returns the distinct absolute http(s) links of a page, without fragments
+func pageLinks(htmlContent, pageURL string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		ref, err := base.Parse(strings.TrimSpace(href))
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			return
+		}
+		ref.Fragment = ""
+		link := ref.String()
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	})
+	return links
+}
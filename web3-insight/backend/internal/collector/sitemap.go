@@ -0,0 +1,377 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/model"
+)
+
+// Crawl modes
+const (
+	CrawlModePage    = "page"    // Crawl the source URL itself
+	CrawlModeSitemap = "sitemap" // Crawl article URLs listed in the site's sitemaps
+)
+
+// Sitemap source defaults
+const (
+	defaultSitemapMaxAgeDays = 30
+	defaultSitemapMaxPerSync = 5
+	maxSitemapQueue          = 200 // URLs queued per sync
+	maxSitemapFiles          = 20  // Sitemaps read per sync, including index children
+)
+
+// CrawlConfig holds crawl-specific configuration
+type CrawlConfig struct {
+	Mode       string `json:"mode,omitempty"`       // "page" (default) or "sitemap"
+	SitemapURL string `json:"sitemapUrl,omitempty"` // Defaults to the sitemaps listed in robots.txt, then /sitemap.xml
+	URLPattern string `json:"urlPattern,omitempty"` // Regexp discovered URLs must match, e.g. /blog/
+	MaxAgeDays int    `json:"maxAgeDays,omitempty"` // Entries last modified earlier are skipped; defaults to 30
+	MaxPerSync int    `json:"maxPerSync,omitempty"` // Queued URLs crawled per sync; defaults to 5
+}
+
+// sitemapEntry is a <url> or <sitemap> entry
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapDoc is either a <urlset> or a <sitemapindex>
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// sitemapURL is an article URL discovered from a sitemap
+type sitemapURL struct {
+	URL     string
+	LastMod *time.Time
+}
+
+// parseCrawlConfig reads a source's config, applying defaults
+func parseCrawlConfig(source *model.DataSource) CrawlConfig {
+	var config CrawlConfig
+	if source.Config != nil {
+		if err := json.Unmarshal(source.Config, &config); err != nil {
+			log.Printf("Warning: failed to parse crawl config: %v", err)
+		}
+	}
+	if config.Mode == "" {
+		config.Mode = CrawlModePage
+	}
+	if config.MaxAgeDays <= 0 {
+		config.MaxAgeDays = defaultSitemapMaxAgeDays
+	}
+	if config.MaxPerSync <= 0 {
+		config.MaxPerSync = defaultSitemapMaxPerSync
+	}
+	return config
+}
+
+// discoverSitemapURLs lists the recent article URLs of a sitemap source that robots.txt allows,
+// newest first, and the sitemaps it read. Conditional discovery skips sitemaps unchanged since
+// the last sync, whose URLs were queued then.
+func (c *WebCrawler) discoverSitemapURLs(ctx context.Context, source *model.DataSource, config CrawlConfig, conditional bool) ([]sitemapURL, []string, error) {
+	base, err := url.Parse(source.URL)
+	if err != nil || base.Host == "" {
+		return nil, nil, fmt.Errorf("invalid URL: %s", source.URL)
+	}
+	var pattern *regexp.Regexp
+	if config.URLPattern != "" {
+		if pattern, err = regexp.Compile(config.URLPattern); err != nil {
+			return nil, nil, fmt.Errorf("invalid urlPattern: %w", err)
+		}
+	}
+
+	robots := c.robots.Rules(ctx, base.Scheme, base.Host)
+	pending := robots.Sitemaps
+	if config.SitemapURL != "" {
+		pending = []string{config.SitemapURL}
+	}
+	if len(pending) == 0 {
+		pending = []string{base.Scheme + "://" + base.Host + "/sitemap.xml"}
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -config.MaxAgeDays)
+	seen := make(map[string]bool)
+	var urls []sitemapURL
+	var read []string
+	for len(pending) > 0 && len(read) < maxSitemapFiles {
+		sitemap := pending[0]
+		pending = pending[1:]
+		read = append(read, sitemap)
+		doc, err := c.fetchSitemap(ctx, sitemap, conditional)
+		if errors.Is(err, ErrNotModified) {
+			// An unchanged index may still list changed sitemaps, which have validators of their own
+			pending = append(pending, c.fetchCache.Children(sitemap)...)
+			continue
+		}
+		if err != nil {
+			if len(read) == 1 && len(pending) == 0 {
+				return nil, nil, err
+			}
+			log.Printf("Failed to read sitemap %s: %v", sitemap, err)
+			continue
+		}
+
+		// Follow the index's recently modified child sitemaps, newest first
+		children := doc.Sitemaps
+		sort.SliceStable(children, func(i, j int) bool { return children[i].LastMod > children[j].LastMod })
+		var followed []string
+		for _, child := range children {
+			if lastMod := sitemapTime(child.LastMod); lastMod != nil && lastMod.Before(cutoff) {
+				continue
+			}
+			followed = append(followed, strings.TrimSpace(child.Loc))
+		}
+		pending = append(pending, followed...)
+		if conditional && len(children) > 0 {
+			c.fetchCache.SetChildren(sitemap, followed)
+		}
+
+		for _, entry := range doc.URLs {
+			loc := strings.TrimSpace(entry.Loc)
+			u, err := url.Parse(loc)
+			if err != nil || u.Host != base.Host || seen[loc] {
+				continue
+			}
+			lastMod := sitemapTime(entry.LastMod)
+			if lastMod != nil && lastMod.Before(cutoff) {
+				continue
+			}
+			if pattern != nil && !pattern.MatchString(loc) {
+				continue
+			}
+			if !robots.Allowed(u.RequestURI()) {
+				continue
+			}
+			seen[loc] = true
+			urls = append(urls, sitemapURL{URL: loc, LastMod: lastMod})
+		}
+	}
+
+	sort.SliceStable(urls, func(i, j int) bool {
+		if urls[i].LastMod == nil || urls[j].LastMod == nil {
+			return urls[j].LastMod == nil && urls[i].LastMod != nil
+		}
+		return urls[i].LastMod.After(*urls[j].LastMod)
+	})
+	return urls, read, nil
+}
+
+// fetchSitemap downloads and parses a sitemap, gunzipping .xml.gz files. A conditional fetch
+// returns ErrNotModified for an unchanged sitemap.
+func (c *WebCrawler) fetchSitemap(ctx context.Context, target string, conditional bool) (*sitemapDoc, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	robots := c.robots.Rules(ctx, u.Scheme, u.Host)
+	c.sitemapLimiter.WaitAtLeast(u.Host, robots.CrawlDelay)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Web3-Insight/1.0")
+	if conditional {
+		c.fetchCache.Prepare(target, req.Header)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && !(conditional && resp.StatusCode == http.StatusNotModified) {
+		return nil, fmt.Errorf("sitemap %s returned status %d", target, resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 50<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sitemap: %w", err)
+	}
+	if changed := c.fetchCache.Changed(target, resp.StatusCode, resp.Header, raw); conditional && !changed {
+		return nil, ErrNotModified
+	}
+
+	var body io.Reader = bytes.NewReader(raw)
+	if strings.HasSuffix(u.Path, ".gz") || resp.Header.Get("Content-Type") == "application/x-gzip" {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to gunzip sitemap: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var doc sitemapDoc
+	if err := xml.NewDecoder(body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse sitemap: %w", err)
+	}
+	if doc.XMLName.Local != "urlset" && doc.XMLName.Local != "sitemapindex" {
+		return nil, fmt.Errorf("not a sitemap: <%s>", doc.XMLName.Local)
+	}
+	return &doc, nil
+}
+
+// sitemapTime parses a W3C datetime lastmod
+func sitemapTime(raw string) *time.Time {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+// collectSitemap queues newly discovered sitemap URLs as pending crawl tasks, then crawls the
+// oldest pending ones, at most MaxPerSync per run
+func (c *WebCrawler) collectSitemap(ctx context.Context, source *model.DataSource, config CrawlConfig, filter *ItemFilter) (*CollectResult, error) {
+	result := &CollectResult{SourceID: source.ID}
+	if c.taskRepo == nil {
+		return nil, fmt.Errorf("sitemap crawling needs a task repository")
+	}
+
+	discovered, sitemaps, err := c.discoverSitemapURLs(ctx, source, config, true)
+	if err != nil {
+		return nil, err
+	}
+	result.ItemsFound = len(discovered)
+	complete, err := c.queueSitemapURLs(source, discovered)
+	if err != nil {
+		return nil, err
+	}
+	if !complete {
+		// URLs left out now must be found again, so the sitemaps are read in full next time
+		for _, sitemap := range sitemaps {
+			c.fetchCache.Forget(sitemap)
+		}
+	}
+
+	tasks, err := c.taskRepo.FindPendingCrawls(source.ID, config.MaxPerSync)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		var payload model.CrawlTaskPayload
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			continue
+		}
+
+		started := time.Now()
+		task.Status = model.TaskStatusRunning
+		task.StartedAt = &started
+		c.taskRepo.Update(task)
+
+		_, err := c.CrawlAndSaveFiltered(ctx, payload.URL, source.Name, filter)
+		completed := time.Now()
+		task.CompletedAt = &completed
+		task.Status = model.TaskStatusCompleted
+		switch {
+		case err == nil:
+			result.ItemsNew++
+		case errors.Is(err, ErrItemFiltered):
+			result.ItemsFiltered++
+		default:
+			task.Status = model.TaskStatusFailed
+			task.Error = err.Error()
+			result.ItemsFailed++
+			result.Errors = append(result.Errors, err)
+		}
+		if err := c.taskRepo.Update(task); err != nil {
+			log.Printf("Failed to update crawl task %s: %v", task.ID, err)
+		}
+	}
+
+	log.Printf("Sitemap sync completed for %s: found=%d, new=%d, filtered=%d, failed=%d", source.Name, result.ItemsFound, result.ItemsNew, result.ItemsFiltered, result.ItemsFailed)
+	return result, nil
+}
+
+// queueSitemapURLs stores discovered URLs that are neither stored nor queued yet as pending
+// web_crawl tasks. It reports false when the per-sync cap left some out.
+func (c *WebCrawler) queueSitemapURLs(source *model.DataSource, discovered []sitemapURL) (bool, error) {
+	urls := make([]string, len(discovered))
+	for i, d := range discovered {
+		urls[i] = d.URL
+	}
+	stored, err := c.newsRepo.ExistingSourceURLs(urls)
+	if err != nil {
+		return false, err
+	}
+	queued, err := c.taskRepo.QueuedCrawlURLs(urls)
+	if err != nil {
+		return false, err
+	}
+
+	added := 0
+	for _, d := range discovered {
+		if stored[d.URL] || queued[d.URL] {
+			continue
+		}
+		if added >= maxSitemapQueue {
+			log.Printf("Queued %d sitemap URLs for %s, leaving the rest for later", added, source.Name)
+			return false, nil
+		}
+		payload, _ := json.Marshal(model.CrawlTaskPayload{URL: d.URL, SourceID: source.ID, LastMod: d.LastMod})
+		task := &model.Task{
+			Type:    model.TaskTypeWebCrawl,
+			Status:  model.TaskStatusPending,
+			Payload: payload,
+		}
+		if err := c.taskRepo.Create(task); err != nil {
+			return false, fmt.Errorf("failed to queue %s: %w", d.URL, err)
+		}
+		added++
+	}
+	if added > 0 {
+		log.Printf("Queued %d sitemap URLs for %s", added, source.Name)
+	}
+	return true, nil
+}
+
+// previewSitemap lists the URLs a sitemap sync would queue, without crawling them
+func (c *WebCrawler) previewSitemap(ctx context.Context, source *model.DataSource, config CrawlConfig) (*PreviewResult, error) {
+	discovered, _, err := c.discoverSitemapURLs(ctx, source, config, false)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(discovered))
+	for i, d := range discovered {
+		urls[i] = d.URL
+	}
+	stored, err := c.newsRepo.ExistingSourceURLs(urls)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		SourceType: source.Type,
+		ItemsFound: len(discovered),
+		Items:      make([]PreviewItem, 0, len(discovered)),
+	}
+	for _, d := range discovered {
+		preview := PreviewItem{Title: d.URL, URL: d.URL, PublishedAt: d.LastMod, Exists: stored[d.URL]}
+		if !preview.Exists {
+			result.ItemsNew++
+		}
+		result.Items = append(result.Items, preview)
+	}
+	return result, nil
+}
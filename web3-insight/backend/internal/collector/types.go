@@ -1,9 +1,11 @@
 package collector
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
 )
 
 // CollectedItem represents a single item collected from any source
@@ -21,14 +23,31 @@ type CollectedItem struct {
 
 // CollectResult represents the result of a collection operation
 type CollectResult struct {
-	SourceID    uuid.UUID
-	ItemsFound  int
-	ItemsNew    int
-	ItemsFailed int
-	Errors      []error
+	SourceID      uuid.UUID
+	ItemsFound    int
+	ItemsNew      int
+	ItemsUpdated  int // Stored items refreshed because their source changed
+	ItemsFailed   int
+	ItemsFiltered int // Dropped by the source's include/exclude filters
+	Errors        []error
 }
 
-// Collector interface for all data collectors
+// ValidationResult describes a source that passed validation
+type ValidationResult struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ItemCount   int    `json:"itemCount"`
+}
+
+// Collector is implemented by every data source type that can be ingested.
+// Collectors are registered in a Registry keyed by DataSource.Type.
 type Collector interface {
-	Collect(sourceID uuid.UUID) (*CollectResult, error)
+	// Type returns the DataSource.Type this collector handles
+	Type() string
+	// Validate checks that a source's URL and config are usable before it is saved
+	Validate(ctx context.Context, source *model.DataSource) (*ValidationResult, error)
+	// Collect fetches and stores new items from the source
+	Collect(ctx context.Context, source *model.DataSource) (*CollectResult, error)
+	// Preview fetches the source without writing anything
+	Preview(ctx context.Context, source *model.DataSource) (*PreviewResult, error)
 }
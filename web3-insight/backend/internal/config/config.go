@@ -1,23 +1,55 @@
 package config
 
 import (
+	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	LLM      LLMConfig      `mapstructure:"llm"`
-	Worker   WorkerConfig   `mapstructure:"worker"`
-	Search   SearchConfig   `mapstructure:"search"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	LLM       LLMConfig       `mapstructure:"llm"`
+	Worker    WorkerConfig    `mapstructure:"worker"`
+	Search    SearchConfig    `mapstructure:"search"`
+	Wiki      WikiConfig      `mapstructure:"wiki"`
+	Export    ExportConfig    `mapstructure:"export"`
+	Collector CollectorConfig `mapstructure:"collector"`
+	LoadTest  LoadTestConfig  `mapstructure:"loadtest"`
 }
 
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Host            string           `mapstructure:"host"`
+	Port            int              `mapstructure:"port"`
+	DisplayTimezone string           `mapstructure:"display_timezone"` // IANA zone for dates shown in generated content; storage and API stay UTC
+	PublicURL       string           `mapstructure:"public_url"`       // Frontend base URL used in output feed links, e.g. https://kb.example.com; defaults to the request's host
+	ChatLimits      ChatLimitsConfig `mapstructure:"chat_limits"`
+	TrustedProxies  []string         `mapstructure:"trusted_proxies"` // IPs or CIDRs whose X-Forwarded-For is believed; none by default, so the client IP is the peer address
+}
+
+// ChatLimitsConfig caps unauthenticated chat use. Limits are kept in memory, so with several
+// API replicas a client can use each replica's allowance.
+type ChatLimitsConfig struct {
+	MessagesPerMinute   int `mapstructure:"messages_per_minute"`    // Per WebSocket connection (default 10)
+	IPMessagesPerMinute int `mapstructure:"ip_messages_per_minute"` // Per client IP across connections and SSE requests (default 30)
+	MaxMessageLength    int `mapstructure:"max_message_length"`     // Characters of message plus selected text (default 4000)
+	MaxConcurrent       int `mapstructure:"max_concurrent"`         // Replies generating at once per client IP (default 3)
+}
+
+// DisplayLocation returns the display timezone, falling back to UTC if unset or invalid
+func (c ServerConfig) DisplayLocation() *time.Location {
+	if c.DisplayTimezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.DisplayTimezone)
+	if err != nil {
+		log.Printf("Warning: invalid display_timezone %q, using UTC: %v", c.DisplayTimezone, err)
+		return time.UTC
+	}
+	return loc
 }
 
 type DatabaseConfig struct {
@@ -37,10 +69,80 @@ type RedisConfig struct {
 }
 
 type LLMConfig struct {
-	DefaultLocal string       `mapstructure:"default_local"`
-	OllamaHost   string       `mapstructure:"ollama_host"`
-	Claude       ClaudeConfig `mapstructure:"claude"`
-	OpenAI       OpenAIConfig `mapstructure:"openai"`
+	Persona      string               `mapstructure:"persona"` // Prompt persona: default, english, trading or one defined via /api/prompts
+	DefaultLocal string               `mapstructure:"default_local"`
+	OllamaHost   string               `mapstructure:"ollama_host"`
+	Claude       ClaudeConfig         `mapstructure:"claude"`
+	OpenAI       OpenAIConfig         `mapstructure:"openai"`
+	Bedrock      BedrockConfig        `mapstructure:"bedrock"`
+	Cache        LLMCacheConfig       `mapstructure:"cache"`
+	Budget       LLMBudgetConfig      `mapstructure:"budget"`
+	Retry        LLMRetryConfig       `mapstructure:"retry"`
+	Audit        LLMAuditConfig       `mapstructure:"audit"`
+	Concurrency  LLMConcurrencyConfig `mapstructure:"concurrency"`
+	Embedding    EmbeddingConfig      `mapstructure:"embedding"`
+	Rerank       RerankConfig         `mapstructure:"rerank"`
+	// OpenAICompatible lists extra endpoints speaking the OpenAI chat-completions protocol
+	OpenAICompatible []OpenAICompatibleConfig `mapstructure:"openai_compatible"`
+}
+
+type EmbeddingConfig struct {
+	Provider   string `mapstructure:"provider"`   // ollama (default), openai, cohere or voyage
+	Model      string `mapstructure:"model"`      // Defaults to nomic-embed-text, text-embedding-3-small, embed-multilingual-v3.0 or voyage-3
+	Dimensions int    `mapstructure:"dimensions"` // Vector size; 0 uses the model's native size. Checked against the vector columns at startup
+	APIKey     string `mapstructure:"api_key"`    // Required for cohere and voyage; openai defaults to llm.openai.api_key
+	BaseURL    string `mapstructure:"base_url"`   // Optional OpenAI-compatible endpoint, e.g. https://example.com/v1
+}
+
+type RerankConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Provider   string `mapstructure:"provider"`   // ollama (default) or cohere
+	Model      string `mapstructure:"model"`      // Required for ollama; defaults to rerank-v3.5 for cohere
+	APIKey     string `mapstructure:"api_key"`    // Required for cohere
+	BaseURL    string `mapstructure:"base_url"`   // Optional Cohere-compatible endpoint
+	Candidates int    `mapstructure:"candidates"` // Vector hits re-scored per query; defaults to 50
+}
+
+type LLMCacheConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	TTL     int      `mapstructure:"ttl"`   // Seconds; defaults to 24h
+	Tasks   []string `mapstructure:"tasks"` // Tasks to cache; defaults to classification and summarization
+}
+
+type LLMBudgetConfig struct {
+	Enabled   bool                   `mapstructure:"enabled"`
+	Providers map[string]BudgetLimit `mapstructure:"providers"` // claude, openai, bedrock or an openai_compatible name
+	Tasks     map[string]BudgetLimit `mapstructure:"tasks"`     // Routing task, e.g. content_generation
+}
+
+type BudgetLimit struct {
+	Daily   float64 `mapstructure:"daily"`   // USD per day; 0 means unlimited
+	Monthly float64 `mapstructure:"monthly"` // USD per calendar month; 0 means unlimited
+}
+
+type LLMRetryConfig struct {
+	Default RetryPolicy            `mapstructure:"default"`
+	Models  map[string]RetryPolicy `mapstructure:"models"` // Per-model overrides keyed by adapter name
+}
+
+type LLMConcurrencyConfig struct {
+	Providers    map[string]int `mapstructure:"providers"`     // Max in-flight calls per provider; local providers default to 1
+	Models       map[string]int `mapstructure:"models"`        // Per-model overrides keyed by adapter name
+	QueueTimeout int            `mapstructure:"queue_timeout"` // Seconds a call waits for a slot before falling back; defaults to 300
+}
+
+type LLMAuditConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	RetentionDays int  `mapstructure:"retention_days"`  // Calls older than this are deleted daily; defaults to 30
+	StorePrompts  bool `mapstructure:"store_prompts"`   // Keep prompt and response text, not just the prompt hash
+	MaxTextLength int  `mapstructure:"max_text_length"` // Characters kept of each prompt and response; defaults to 20000
+}
+
+type RetryPolicy struct {
+	MaxRetries     int `mapstructure:"max_retries"`     // Retries on 429/5xx before falling back to the next model
+	InitialBackoff int `mapstructure:"initial_backoff"` // Milliseconds; doubles after each retry
+	MaxBackoff     int `mapstructure:"max_backoff"`     // Milliseconds
+	Timeout        int `mapstructure:"timeout"`         // Seconds per attempt; 0 uses the adapter's HTTP timeout
 }
 
 type ClaudeConfig struct {
@@ -55,14 +157,101 @@ type OpenAIConfig struct {
 	DefaultModel string `mapstructure:"default_model"`
 }
 
+type BedrockConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	Region          string   `mapstructure:"region"`
+	AccessKeyID     string   `mapstructure:"access_key_id"`
+	SecretAccessKey string   `mapstructure:"secret_access_key"`
+	SessionToken    string   `mapstructure:"session_token"`
+	DefaultModel    string   `mapstructure:"default_model"`
+	Models          []string `mapstructure:"models"` // Additional model IDs to register (e.g. Llama)
+}
+
+type OpenAICompatibleConfig struct {
+	Name        string            `mapstructure:"name"` // Adapter name used in routes; defaults to model
+	BaseURL     string            `mapstructure:"base_url"`
+	APIKey      string            `mapstructure:"api_key"`
+	Model       string            `mapstructure:"model"`
+	Type        string            `mapstructure:"type"` // "local" or "cloud"
+	Headers     map[string]string `mapstructure:"headers"`
+	InputPrice  float64           `mapstructure:"input_price"`  // USD per 1K tokens
+	OutputPrice float64           `mapstructure:"output_price"` // USD per 1K tokens
+	Tasks       []string          `mapstructure:"tasks"`        // Tasks to append this adapter to as a fallback
+}
+
+// Worker modes
+const (
+	WorkerModeRedis = "redis" // Tasks go through Redis to cmd/worker
+	WorkerModeLocal = "local" // Tasks run inside the API server; for small deployments without Redis
+)
+
 type WorkerConfig struct {
-	Concurrency int            `mapstructure:"concurrency"`
-	Queues      map[string]int `mapstructure:"queues"`
+	Mode           string             `mapstructure:"mode"` // redis (default) or local
+	Concurrency    int                `mapstructure:"concurrency"`
+	LocalQueueSize int                `mapstructure:"local_queue_size"` // Tasks held in memory in local mode before enqueueing fails (default 1000)
+	Queues         map[string]int     `mapstructure:"queues"`
+	Backfill       BackfillConfig     `mapstructure:"backfill"`
+	Autoscale      AutoscaleConfig    `mapstructure:"autoscale"`
+	Consistency    ConsistencyConfig  `mapstructure:"consistency"`
+	NewsPipeline   NewsPipelineConfig `mapstructure:"news_pipeline"`
+	Relevance      RelevanceConfig    `mapstructure:"relevance"`
+}
+
+// NewsPipelineConfig controls the news-to-article pipeline run after summarization
+type NewsPipelineConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	BatchSize   int  `mapstructure:"batch_size"`    // Items per batch run after batch summarization (default 10)
+	MaxAgeHours int  `mapstructure:"max_age_hours"` // Older items are left out of batch runs (default 72)
+	Translate   bool `mapstructure:"translate"`     // Translate the full content of items that become or update an article
+}
+
+// RelevanceConfig controls LLM relevance scoring of incoming news before summarization. A
+// data source overrides it with a "relevance" section in its config.
+type RelevanceConfig struct {
+	Enabled   bool    `mapstructure:"enabled"`
+	Threshold float64 `mapstructure:"threshold"` // Items scoring below it (0-1) are filtered (default 0.3)
+	Action    string  `mapstructure:"action"`    // quarantine (default) keeps filtered items for review; discard drops them
+}
+
+// ConsistencyConfig controls the nightly consistency check
+type ConsistencyConfig struct {
+	AutoFix bool `mapstructure:"auto_fix"` // Apply the safe fixes; otherwise only report
+}
+
+// Local reports whether tasks run in-process instead of through Redis
+func (c WorkerConfig) Local() bool {
+	return c.Mode == WorkerModeLocal
+}
+
+// AutoscaleConfig sets the thresholds behind the scaling recommendation of GET /api/admin/queues
+type AutoscaleConfig struct {
+	ScaleUpLatency        int     `mapstructure:"scale_up_latency"`          // Seconds; scale up when the oldest pending task waited longer (default 60)
+	ScaleUpBacklogPerSlot int     `mapstructure:"scale_up_backlog_per_slot"` // Scale up when pending tasks per worker slot exceed this (default 10)
+	ScaleDownUtilization  float64 `mapstructure:"scale_down_utilization"`    // Scale down when nothing is pending and fewer slots are busy (default 0.3)
+}
+
+type BackfillConfig struct {
+	MaxPages        int `mapstructure:"max_pages"`         // Historical pages to walk per source
+	PageDelay       int `mapstructure:"page_delay"`        // Seconds between page tasks
+	ArticlesPerPage int `mapstructure:"articles_per_page"` // Max articles crawled per listing page
 }
 
 type SearchConfig struct {
-	Tavily  TavilyConfig  `mapstructure:"tavily"`
-	SerpAPI SerpAPIConfig `mapstructure:"serpapi"`
+	Tavily  TavilyConfig       `mapstructure:"tavily"`
+	SerpAPI SerpAPIConfig      `mapstructure:"serpapi"`
+	Engine  SearchEngineConfig `mapstructure:"engine"`
+}
+
+// SearchEngineConfig configures the optional full-text search engine behind /api/search.
+// Without a provider, search runs ILIKE queries against the database.
+type SearchEngineConfig struct {
+	Provider    string `mapstructure:"provider"` // "meilisearch", "elasticsearch", or empty
+	URL         string `mapstructure:"url"`      // e.g. http://localhost:7700 or http://localhost:9200
+	APIKey      string `mapstructure:"api_key"`  // Meilisearch key, or Elasticsearch API key
+	Username    string `mapstructure:"username"` // Elasticsearch basic auth, used when api_key is empty
+	Password    string `mapstructure:"password"`
+	IndexPrefix string `mapstructure:"index_prefix"` // Prepended to index names so deployments can share a cluster
+	Analyzer    string `mapstructure:"analyzer"`     // Elasticsearch text analyzer (default: cjk)
 }
 
 type TavilyConfig struct {
@@ -75,6 +264,43 @@ type SerpAPIConfig struct {
 	APIKey  string `mapstructure:"api_key"`
 }
 
+// WikiConfig configures exporting articles to external wikis
+type WikiConfig struct {
+	Confluence ConfluenceConfig `mapstructure:"confluence"`
+	Feishu     FeishuConfig     `mapstructure:"feishu"`
+}
+
+type ConfluenceConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	BaseURL      string `mapstructure:"base_url"` // e.g. https://example.atlassian.net/wiki
+	Username     string `mapstructure:"username"` // Cloud account email; leave empty to send api_token as a bearer token (Server/Data Center)
+	APIToken     string `mapstructure:"api_token"`
+	SpaceKey     string `mapstructure:"space_key"`
+	ParentPageID string `mapstructure:"parent_page_id"` // Optional page to create exported pages under
+}
+
+type FeishuConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	BaseURL         string `mapstructure:"base_url"` // Defaults to https://open.feishu.cn; use https://open.larksuite.com for Lark
+	WebURL          string `mapstructure:"web_url"`  // Tenant domain for page links, e.g. https://example.feishu.cn
+	AppID           string `mapstructure:"app_id"`
+	AppSecret       string `mapstructure:"app_secret"`
+	SpaceID         string `mapstructure:"space_id"`          // 知识库 space the app has edit access to
+	ParentNodeToken string `mapstructure:"parent_node_token"` // Optional node to create exported pages under
+}
+
+// ExportConfig configures document exports of articles and categories
+type ExportConfig struct {
+	PDFRendererURL string `mapstructure:"pdf_renderer_url"` // Gotenberg base URL, e.g. http://gotenberg:3000; PDF export is off when empty
+}
+
+// CollectorConfig configures data source collection
+type CollectorConfig struct {
+	PDFTextURL      string `mapstructure:"pdf_text_url"`     // Apache Tika server base URL, e.g. http://tika:9998; full-text PDF extraction is off when empty
+	TallyAPIKey     string `mapstructure:"tally_api_key"`    // Needed for Tally governance sources
+	ArchiveFallback bool   `mapstructure:"archive_fallback"` // Refetch pages blocked by challenges, consent walls or paywalls from the Wayback Machine
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -97,3 +323,9 @@ func Load() (*Config, error) {
 
 	return &cfg, nil
 }
+
+// LoadTestConfig sets the performance budget checked by cmd/loadtest
+type LoadTestConfig struct {
+	BaseURL string         `mapstructure:"base_url"` // API under test (default http://localhost:8080)
+	Budgets map[string]int `mapstructure:"budgets"`  // p95 latency budget in milliseconds, keyed by scenario
+}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+
 	"github.com/user/web3-insight/internal/model"
 	"gorm.io/gorm"
 )
@@ -13,13 +15,131 @@ func Migrate(db *gorm.DB) error {
 
 	return db.AutoMigrate(
 		&model.Category{},
+		&model.CategoryMerge{},
+		&model.CategoryDuplicate{},
+		&model.Tag{},
+		&model.Term{},
+		&model.TermArticle{},
 		&model.Article{},
 		&model.ArticleVersion{},
+		&model.ArticlePrerequisite{},
+		&model.ArticleRelation{},
+		&model.ArticleLink{},
+		&model.SlugRedirect{},
+		&model.ArticleReview{},
+		&model.ArticleRevision{},
+		&model.ArticleChunk{},
 		&model.ChatMessage{},
 		&model.NewsItem{},
+		&model.NewsStory{},
+		&model.TrendingTopic{},
+		&model.NewsTranslation{},
+		&model.SourceStats{},
 		&model.ExplorerResearch{},
 		&model.Task{},
+		&model.ImportBatchData{},
 		&model.Config{},
 		&model.DataSource{},
+		&model.FetchValidator{},
+		&model.CrawlRule{},
+		&model.ResearchSession{},
+		&model.ResearchSchedule{},
+		&model.Experiment{},
+		&model.ExperimentVariant{},
+		&model.ExperimentRun{},
+		&model.LLMCall{},
+		&model.ContentChange{},
+		&model.WikiPage{},
+		&model.PromptTemplate{},
+		&model.ConsistencyReport{},
+		&model.SearchLog{},
 	)
 }
+
+// embeddingColumns are the pgvector columns filled by the configured embedding adapter
+var embeddingColumns = []struct{ table, column string }{
+	{"articles", "embedding"},
+	{"news_items", "embedding"},
+	{"article_chunks", "embedding"},
+}
+
+// EmbeddingReindexColumn is the shadow column articles are re-embedded into before it
+// replaces articles.embedding
+const EmbeddingReindexColumn = "embedding_next"
+
+// ValidateEmbeddingDimensions checks that the vector columns match the embedding size.
+// A mismatch makes every embedding write fail, so it should be reported at startup.
+func ValidateEmbeddingDimensions(db *gorm.DB, dimensions int) error {
+	if dimensions <= 0 {
+		return fmt.Errorf("embedding dimensions unknown; set llm.embedding.dimensions")
+	}
+	for _, col := range embeddingColumns {
+		typmod, err := columnDimensions(db, col.table, col.column)
+		if err != nil {
+			return err
+		}
+		if typmod > 0 && typmod != dimensions {
+			return fmt.Errorf("%s.%s is vector(%d) but the embedding model produces %d dimensions", col.table, col.column, typmod, dimensions)
+		}
+	}
+	return nil
+}
+
+// PrepareEmbeddingReindex adds the shadow column for re-embedding articles at a new size.
+// A shadow column of the same size is kept, so an interrupted re-index resumes where it stopped.
+func PrepareEmbeddingReindex(db *gorm.DB, dimensions int) error {
+	if dimensions <= 0 {
+		return fmt.Errorf("embedding dimensions unknown; set llm.embedding.dimensions")
+	}
+	typmod, err := columnDimensions(db, "articles", EmbeddingReindexColumn)
+	if err != nil {
+		return err
+	}
+	if typmod > 0 && typmod != dimensions {
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE articles DROP COLUMN %s", EmbeddingReindexColumn)).Error; err != nil {
+			return fmt.Errorf("failed to drop stale %s: %w", EmbeddingReindexColumn, err)
+		}
+	}
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE articles ADD COLUMN IF NOT EXISTS %s vector(%d)", EmbeddingReindexColumn, dimensions)).Error; err != nil {
+		return fmt.Errorf("failed to add %s: %w", EmbeddingReindexColumn, err)
+	}
+	return nil
+}
+
+// SwapEmbeddingColumns makes the shadow column the article embedding column and resizes the
+// other vector columns, clearing their now incompatible vectors, which the caller has to
+// embed again. It runs in one transaction.
+func SwapEmbeddingColumns(db *gorm.DB, dimensions int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE articles DROP COLUMN embedding").Error; err != nil {
+			return fmt.Errorf("failed to drop articles.embedding: %w", err)
+		}
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE articles RENAME COLUMN %s TO embedding", EmbeddingReindexColumn)).Error; err != nil {
+			return fmt.Errorf("failed to rename %s: %w", EmbeddingReindexColumn, err)
+		}
+		for _, col := range embeddingColumns {
+			if col.table == "articles" {
+				continue
+			}
+			stmt := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE vector(%d) USING NULL", col.table, col.column, dimensions)
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to resize %s.%s: %w", col.table, col.column, err)
+			}
+		}
+		return nil
+	})
+}
+
+// columnDimensions returns the declared size of a vector column, or 0 if the column does not exist
+func columnDimensions(db *gorm.DB, table, column string) (int, error) {
+	// pgvector stores the declared dimension as the column's type modifier
+	var typmod int
+	err := db.Raw(
+		"SELECT atttypmod FROM pg_attribute WHERE attrelid = ?::regclass AND attname = ? AND NOT attisdropped",
+		table, column,
+	).Scan(&typmod).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s.%s dimensions: %w", table, column, err)
+	}
+	return typmod, nil
+}
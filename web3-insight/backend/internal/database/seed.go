@@ -60,5 +60,17 @@ func Seed(db *gorm.DB) error {
 		}
 	}
 
+	// Extraction rules for sites whose layout the generic parser gets wrong
+	crawlRules := []model.CrawlRule{
+		{Domain: "blog.ethereum.org", ContentSelector: "article, .post-content, main", TitleSelector: "h1", RemoveSelectors: []string{"nav", "footer", "aside", ".comments", ".share-buttons"}},
+		{Domain: "vitalik.eth.limo", ContentSelector: "article, .post, main", TitleSelector: "h1", RemoveSelectors: []string{"nav", "footer"}},
+		{Domain: "paradigm.xyz", ContentSelector: "article, .content, main", TitleSelector: "h1", RemoveSelectors: []string{"nav", "footer", "aside"}},
+	}
+
+	for _, rule := range crawlRules {
+		rule.RenderMode = model.RenderModeStatic
+		db.Where("domain = ?", rule.Domain).FirstOrCreate(&rule)
+	}
+
 	return nil
 }
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"strings"
+	"time"
+)
+
+// CallRecord describes one call to a model, including its retries, for the audit log
+type CallRecord struct {
+	Task     string // Empty for calls that bypass routing
+	Model    string
+	Provider string
+	Prompt   string // System prompt and user input as sent
+	Response string
+	Usage    Usage
+	CostUSD  float64
+	Duration time.Duration
+	Retries  int
+	Stream   bool
+	Err      error
+}
+
+// CallLogger receives a record of every model call the router makes
+type CallLogger interface {
+	LogCall(record CallRecord)
+}
+
+// SetCallLogger enables the LLM call audit log (nil disables it)
+func (r *Router) SetCallLogger(logger CallLogger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.callLogger = logger
+}
+
+// recordCall passes one finished call to the audit log, estimating usage the provider didn't report
+func (r *Router) recordCall(task, modelName, input, content string, usage Usage, startedAt time.Time, retries int, stream bool, err error) {
+	r.mu.RLock()
+	logger := r.callLogger
+	adapter := r.adapters[modelName]
+	r.mu.RUnlock()
+	if logger == nil || adapter == nil {
+		return
+	}
+
+	record := CallRecord{
+		Task:     task,
+		Model:    modelName,
+		Provider: ProviderOf(adapter),
+		Prompt:   input,
+		Response: content,
+		Duration: time.Since(startedAt),
+		Retries:  retries,
+		Stream:   stream,
+		Err:      err,
+	}
+	if err == nil {
+		result := buildResult(adapter, modelName, input, content, usage)
+		record.Usage, record.CostUSD = result.Usage, result.CostUSD
+	}
+	logger.LogCall(record)
+}
+
+// auditStream relays a stream, recording the call once it completes
+func (r *Router) auditStream(task, modelName, input string, stream <-chan StreamChunk) <-chan StreamChunk {
+	r.mu.RLock()
+	logger := r.callLogger
+	r.mu.RUnlock()
+	if logger == nil {
+		return stream
+	}
+
+	startedAt := time.Now()
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		var content strings.Builder
+		var streamErr error
+		for chunk := range stream {
+			content.WriteString(chunk.Content)
+			if chunk.Error != nil {
+				streamErr = chunk.Error
+			}
+			out <- chunk
+		}
+		r.recordCall(task, modelName, input, content.String(), Usage{}, startedAt, 0, true, streamErr)
+	}()
+	return out
+}
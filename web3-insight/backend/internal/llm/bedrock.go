@@ -0,0 +1,398 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const bedrockSigningService = "bedrock"
+
+// BedrockAdapter implements LLMAdapter for models hosted on AWS Bedrock (Claude, Llama, ...)
+// using the Converse API, so one request format covers every model family.
+type BedrockAdapter struct {
+	model    string // Bedrock model ID, e.g. "anthropic.claude-3-5-sonnet-20240620-v1:0"
+	region   string
+	creds    awsCredentials
+	endpoint string
+	client   *http.Client
+}
+
+// NewBedrockAdapter creates a new Bedrock adapter
+func NewBedrockAdapter(region, accessKeyID, secretAccessKey, sessionToken, model string) *BedrockAdapter {
+	return &BedrockAdapter{
+		model:  model,
+		region: region,
+		creds: awsCredentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    sessionToken,
+		},
+		endpoint: fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region),
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+	}
+}
+
+func (b *BedrockAdapter) Name() string { return b.model }
+func (b *BedrockAdapter) Type() string { return "cloud" }
+
+// Generate performs non-streaming generation
+func (b *BedrockAdapter) Generate(prompt string, opts *GenerateOptions) (string, Usage, error) {
+	messages := []Message{
+		{Role: "user", Content: prompt},
+	}
+	return b.GenerateChat(messages, opts)
+}
+
+// GenerateStream performs streaming generation
+func (b *BedrockAdapter) GenerateStream(prompt string, opts *GenerateOptions) (<-chan StreamChunk, error) {
+	messages := []Message{
+		{Role: "user", Content: prompt},
+	}
+	return b.GenerateChatStream(messages, opts)
+}
+
+// GenerateChat performs chat completion via the Converse API
+func (b *BedrockAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, Usage, error) {
+	body, err := b.buildPayload(messages, opts)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	req, err := b.newSignedRequest(requestContext(opts), "converse", body)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("bedrock request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return "", Usage{}, &APIError{Provider: "bedrock", StatusCode: resp.StatusCode, Message: errResp.Message}
+	}
+
+	var result struct {
+		Output struct {
+			Message struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"message"`
+		} `json:"output"`
+		StopReason string `json:"stopReason"`
+		Usage      struct {
+			InputTokens  int `json:"inputTokens"`
+			OutputTokens int `json:"outputTokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, block := range result.Output.Message.Content {
+		sb.WriteString(block.Text)
+	}
+	if sb.Len() == 0 {
+		return "", Usage{}, fmt.Errorf("empty response from bedrock")
+	}
+
+	return sb.String(), Usage{InputTokens: result.Usage.InputTokens, OutputTokens: result.Usage.OutputTokens}, nil
+}
+
+// GenerateChatStream performs streaming chat completion via the ConverseStream API
+func (b *BedrockAdapter) GenerateChatStream(messages []Message, opts *GenerateOptions) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk)
+
+	body, err := b.buildPayload(messages, opts)
+	if err != nil {
+		close(ch)
+		return ch, err
+	}
+
+	go func() {
+		defer close(ch)
+
+		req, err := b.newSignedRequest(requestContext(opts), "converse-stream", body)
+		if err != nil {
+			ch <- StreamChunk{Error: err}
+			return
+		}
+
+		resp, err := b.client.Do(req)
+		if err != nil {
+			ch <- StreamChunk{Error: err}
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			ch <- StreamChunk{Error: fmt.Errorf("bedrock returned status %d", resp.StatusCode)}
+			return
+		}
+
+		reader := bufio.NewReader(resp.Body)
+		for {
+			msg, err := readEventStreamMessage(reader)
+			if err == io.EOF {
+				ch <- StreamChunk{Done: true}
+				return
+			}
+			if err != nil {
+				ch <- StreamChunk{Error: err}
+				return
+			}
+
+			switch msg.headers[":message-type"] {
+			case "exception", "error":
+				var errPayload struct {
+					Message string `json:"message"`
+				}
+				json.Unmarshal(msg.payload, &errPayload)
+				ch <- StreamChunk{Error: fmt.Errorf("bedrock stream %s: %s", msg.headers[":exception-type"], errPayload.Message)}
+				return
+			}
+
+			switch msg.headers[":event-type"] {
+			case "contentBlockDelta":
+				var event struct {
+					Delta struct {
+						Text string `json:"text"`
+					} `json:"delta"`
+				}
+				if err := json.Unmarshal(msg.payload, &event); err != nil {
+					continue
+				}
+				if event.Delta.Text != "" {
+					ch <- StreamChunk{Content: event.Delta.Text}
+				}
+			case "messageStop":
+				ch <- StreamChunk{Done: true}
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// buildPayload builds a Converse request body
+func (b *BedrockAdapter) buildPayload(messages []Message, opts *GenerateOptions) ([]byte, error) {
+	if opts == nil {
+		opts = DefaultGenerateOptions()
+	}
+
+	var system []map[string]string
+	if opts.SystemPrompt != "" {
+		system = append(system, map[string]string{"text": opts.SystemPrompt})
+	}
+
+	converseMessages := make([]map[string]interface{}, 0, len(messages))
+	for _, m := range messages {
+		// Converse takes system prompts separately from the messages array
+		if m.Role == "system" {
+			system = append(system, map[string]string{"text": m.Content})
+			continue
+		}
+		converseMessages = append(converseMessages, map[string]interface{}{
+			"role":    m.Role,
+			"content": []map[string]string{{"text": m.Content}},
+		})
+	}
+
+	inferenceConfig := map[string]interface{}{}
+	if opts.MaxTokens > 0 {
+		inferenceConfig["maxTokens"] = opts.MaxTokens
+	}
+	if opts.Temperature > 0 {
+		inferenceConfig["temperature"] = opts.Temperature
+	}
+	if opts.TopP > 0 && opts.TopP < 1 {
+		inferenceConfig["topP"] = opts.TopP
+	}
+	if len(opts.StopWords) > 0 {
+		inferenceConfig["stopSequences"] = opts.StopWords
+	}
+
+	payload := map[string]interface{}{
+		"messages":        converseMessages,
+		"inferenceConfig": inferenceConfig,
+	}
+	if len(system) > 0 {
+		payload["system"] = system
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return body, nil
+}
+
+// newSignedRequest creates a SigV4-signed request for the given Converse action
+func (b *BedrockAdapter) newSignedRequest(ctx context.Context, action string, body []byte) (*http.Request, error) {
+	url := fmt.Sprintf("%s/model/%s/%s", b.endpoint, awsURIEncode(b.model), action)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if action == "converse-stream" {
+		req.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	signSigV4(req, body, b.creds, b.region, bedrockSigningService, time.Now())
+	return req, nil
+}
+
+// IsAvailable checks if AWS credentials and region are configured
+func (b *BedrockAdapter) IsAvailable() bool {
+	return b.region != "" && b.creds.AccessKeyID != "" && b.creds.SecretAccessKey != ""
+}
+
+// EstimateCost estimates the cost based on Bedrock on-demand pricing
+func (b *BedrockAdapter) EstimateCost(inputTokens, outputTokens int) float64 {
+	var inputPrice, outputPrice float64
+
+	switch {
+	case strings.Contains(b.model, "opus"):
+		inputPrice = 0.015 // per 1K tokens
+		outputPrice = 0.075
+	case strings.Contains(b.model, "haiku"):
+		inputPrice = 0.00025
+		outputPrice = 0.00125
+	case strings.Contains(b.model, "sonnet"):
+		inputPrice = 0.003
+		outputPrice = 0.015
+	case strings.Contains(b.model, "llama") && strings.Contains(b.model, "405b"):
+		inputPrice = 0.0024
+		outputPrice = 0.0024
+	case strings.Contains(b.model, "llama") && strings.Contains(b.model, "70b"):
+		inputPrice = 0.00072
+		outputPrice = 0.00072
+	case strings.Contains(b.model, "llama"):
+		inputPrice = 0.00022
+		outputPrice = 0.00022
+	default:
+		// Default to sonnet pricing
+		inputPrice = 0.003
+		outputPrice = 0.015
+	}
+
+	return (float64(inputTokens)/1000)*inputPrice + (float64(outputTokens)/1000)*outputPrice
+}
+
+// eventStreamMessage is a decoded application/vnd.amazon.eventstream frame
+type eventStreamMessage struct {
+	headers map[string]string
+	payload []byte
+}
+
+// readEventStreamMessage reads one binary event stream frame:
+// total length (4) | headers length (4) | prelude CRC (4) | headers | payload | message CRC (4)
+func readEventStreamMessage(r io.Reader) (*eventStreamMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated event stream prelude")
+		}
+		return nil, err
+	}
+
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, fmt.Errorf("event stream prelude checksum mismatch")
+	}
+	if totalLen < 16 || headersLen > totalLen-16 {
+		return nil, fmt.Errorf("invalid event stream frame length")
+	}
+
+	rest := make([]byte, totalLen-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("truncated event stream frame: %w", err)
+	}
+
+	crc := crc32.NewIEEE()
+	crc.Write(prelude)
+	crc.Write(rest[:len(rest)-4])
+	if crc.Sum32() != binary.BigEndian.Uint32(rest[len(rest)-4:]) {
+		return nil, fmt.Errorf("event stream message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(rest[:headersLen])
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventStreamMessage{
+		headers: headers,
+		payload: rest[headersLen : len(rest)-4],
+	}, nil
+}
+
+// parseEventStreamHeaders decodes frame headers, keeping only string-valued ones
+func parseEventStreamHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(data) > 0 {
+		nameLen := int(data[0])
+		if len(data) < 1+nameLen+1 {
+			return nil, fmt.Errorf("malformed event stream header")
+		}
+		name := string(data[1 : 1+nameLen])
+		valueType := data[1+nameLen]
+		data = data[2+nameLen:]
+
+		var size int
+		switch valueType {
+		case 0, 1: // bool true / false
+			size = 0
+		case 2: // byte
+			size = 1
+		case 3: // short
+			size = 2
+		case 4: // int
+			size = 4
+		case 5, 8: // long, timestamp
+			size = 8
+		case 9: // uuid
+			size = 16
+		case 6, 7: // byte array, string
+			if len(data) < 2 {
+				return nil, fmt.Errorf("malformed event stream header %s", name)
+			}
+			size = 2 + int(binary.BigEndian.Uint16(data[:2]))
+		default:
+			return nil, fmt.Errorf("unknown event stream header type %d", valueType)
+		}
+
+		if len(data) < size {
+			return nil, fmt.Errorf("malformed event stream header %s", name)
+		}
+		if valueType == 7 {
+			headers[name] = string(data[2:size])
+		}
+		data = data[size:]
+	}
+	return headers, nil
+}
@@ -0,0 +1,214 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/user/web3-insight/internal/config"
+)
+
+const budgetKeyPrefix = "llm:budget:"
+
+// Budget scopes and periods
+const (
+	BudgetScopeProvider = "provider"
+	BudgetScopeTask     = "task"
+
+	BudgetPeriodDaily   = "daily"
+	BudgetPeriodMonthly = "monthly"
+)
+
+// BudgetTracker tracks LLM spend in Redis so limits are shared by the API and workers
+type BudgetTracker struct {
+	client    *redis.Client
+	providers map[string]config.BudgetLimit
+	tasks     map[string]config.BudgetLimit
+}
+
+// BudgetStatus reports spend against one configured limit
+type BudgetStatus struct {
+	Scope        string  `json:"scope"`
+	Name         string  `json:"name"`
+	Period       string  `json:"period"`
+	LimitUSD     float64 `json:"limitUsd"`
+	SpentUSD     float64 `json:"spentUsd"`
+	RemainingUSD float64 `json:"remainingUsd"`
+	Exhausted    bool    `json:"exhausted"`
+}
+
+// NewBudgetTracker creates a budget tracker with the given limits
+func NewBudgetTracker(client *redis.Client, budgetCfg *config.LLMBudgetConfig) *BudgetTracker {
+	return &BudgetTracker{
+		client:    client,
+		providers: budgetCfg.Providers,
+		tasks:     budgetCfg.Tasks,
+	}
+}
+
+// NewBudgetTrackerFromConfig creates a budget tracker, or returns nil if budgets are disabled
+func NewBudgetTrackerFromConfig(budgetCfg *config.LLMBudgetConfig, redisCfg *config.RedisConfig) *BudgetTracker {
+	if !budgetCfg.Enabled {
+		return nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisCfg.Host, redisCfg.Port),
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+	return NewBudgetTracker(client, budgetCfg)
+}
+
+// ProviderOf returns the provider name an adapter is billed under
+func ProviderOf(adapter LLMAdapter) string {
+	switch a := adapter.(type) {
+	case *ClaudeAdapter:
+		return "claude"
+	case *OpenAICompatibleAdapter:
+		return a.Name()
+	case *OpenAIAdapter:
+		return "openai"
+	case *BedrockAdapter:
+		return "bedrock"
+	case *OllamaAdapter:
+		return "ollama"
+	default:
+		return adapter.Name()
+	}
+}
+
+// Exhausted reports whether the provider or task has used up a daily or monthly budget.
+// A nil tracker never blocks; Redis errors fail open.
+func (b *BudgetTracker) Exhausted(provider, task string) (bool, string) {
+	if b == nil {
+		return false, ""
+	}
+
+	for _, status := range b.check(provider, task) {
+		if status.Exhausted {
+			return true, fmt.Sprintf("%s %s %s budget of $%.2f", status.Scope, status.Name, status.Period, status.LimitUSD)
+		}
+	}
+	return false, ""
+}
+
+// Record adds cost to the running totals of the provider and task
+func (b *BudgetTracker) Record(provider, task string, cost float64) {
+	if b == nil || cost <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	pipe := b.client.TxPipeline()
+	for _, target := range []struct{ scope, name string }{{BudgetScopeProvider, provider}, {BudgetScopeTask, task}} {
+		for _, period := range []string{BudgetPeriodDaily, BudgetPeriodMonthly} {
+			key, ttl := budgetKey(target.scope, target.name, period, now)
+			pipe.IncrByFloat(ctx, key, cost)
+			pipe.Expire(ctx, key, ttl)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("LLM budget record failed: %v", err)
+	}
+}
+
+// Status returns spend for every configured limit in the current day and month
+func (b *BudgetTracker) Status() []BudgetStatus {
+	if b == nil {
+		return []BudgetStatus{}
+	}
+
+	var statuses []BudgetStatus
+	statuses = append(statuses, b.statusFor(BudgetScopeProvider, b.providers)...)
+	statuses = append(statuses, b.statusFor(BudgetScopeTask, b.tasks)...)
+	return statuses
+}
+
+// check returns the status of the limits that apply to one provider and task
+func (b *BudgetTracker) check(provider, task string) []BudgetStatus {
+	var statuses []BudgetStatus
+	if limit, ok := b.providers[provider]; ok {
+		statuses = append(statuses, b.limitStatus(BudgetScopeProvider, provider, limit)...)
+	}
+	if limit, ok := b.tasks[task]; ok {
+		statuses = append(statuses, b.limitStatus(BudgetScopeTask, task, limit)...)
+	}
+	return statuses
+}
+
+// statusFor returns the status of all limits in a scope, sorted by name
+func (b *BudgetTracker) statusFor(scope string, limits map[string]config.BudgetLimit) []BudgetStatus {
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var statuses []BudgetStatus
+	for _, name := range names {
+		statuses = append(statuses, b.limitStatus(scope, name, limits[name])...)
+	}
+	return statuses
+}
+
+// limitStatus reads the current daily and monthly spend for one limit
+func (b *BudgetTracker) limitStatus(scope, name string, limit config.BudgetLimit) []BudgetStatus {
+	now := time.Now()
+	var statuses []BudgetStatus
+	for _, p := range []struct {
+		period string
+		limit  float64
+	}{{BudgetPeriodDaily, limit.Daily}, {BudgetPeriodMonthly, limit.Monthly}} {
+		if p.limit <= 0 {
+			continue
+		}
+		key, _ := budgetKey(scope, name, p.period, now)
+		spent := b.spent(key)
+		remaining := p.limit - spent
+		if remaining < 0 {
+			remaining = 0
+		}
+		statuses = append(statuses, BudgetStatus{
+			Scope:        scope,
+			Name:         name,
+			Period:       p.period,
+			LimitUSD:     p.limit,
+			SpentUSD:     spent,
+			RemainingUSD: remaining,
+			Exhausted:    spent >= p.limit,
+		})
+	}
+	return statuses
+}
+
+// spent reads a spend counter, treating missing keys and Redis errors as zero
+func (b *BudgetTracker) spent(key string) float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	value, err := b.client.Get(ctx, key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("LLM budget read failed: %v", err)
+		}
+		return 0
+	}
+	spent, _ := strconv.ParseFloat(value, 64)
+	return spent
+}
+
+// budgetKey returns the counter key for a scope and period, and how long to keep it
+func budgetKey(scope, name, period string, now time.Time) (string, time.Duration) {
+	if period == BudgetPeriodMonthly {
+		return budgetKeyPrefix + scope + ":" + name + ":" + now.Format("2006-01"), 32 * 24 * time.Hour
+	}
+	return budgetKeyPrefix + scope + ":" + name + ":" + now.Format("2006-01-02"), 48 * time.Hour
+}
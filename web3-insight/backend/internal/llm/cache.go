@@ -0,0 +1,113 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/user/web3-insight/internal/config"
+)
+
+const cacheKeyPrefix = "llm:cache:"
+
+// defaultCachedTasks are cached when no task list is configured
+var defaultCachedTasks = []string{TaskClassification, TaskSummarization}
+
+// ResponseCache stores LLM responses in Redis so identical prompts don't re-spend tokens
+type ResponseCache struct {
+	client *redis.Client
+	ttl    time.Duration
+	tasks  map[string]bool
+}
+
+// NewResponseCache creates a Redis-backed response cache for the given tasks
+func NewResponseCache(client *redis.Client, ttl time.Duration, tasks []string) *ResponseCache {
+	if len(tasks) == 0 {
+		tasks = defaultCachedTasks
+	}
+	taskSet := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		taskSet[t] = true
+	}
+	return &ResponseCache{
+		client: client,
+		ttl:    ttl,
+		tasks:  taskSet,
+	}
+}
+
+// NewResponseCacheFromConfig creates a response cache, or returns nil if caching is disabled
+func NewResponseCacheFromConfig(cacheCfg *config.LLMCacheConfig, redisCfg *config.RedisConfig) *ResponseCache {
+	if !cacheCfg.Enabled {
+		return nil
+	}
+
+	ttl := time.Duration(cacheCfg.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisCfg.Host, redisCfg.Port),
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+	return NewResponseCache(client, ttl, cacheCfg.Tasks)
+}
+
+// key builds a cache key from task, model, input and generation options
+func (c *ResponseCache) key(task, modelName string, input interface{}, opts *GenerateOptions) (string, bool) {
+	if c == nil || !c.tasks[task] || (opts != nil && opts.NoCache) {
+		return "", false
+	}
+	if opts == nil {
+		opts = DefaultGenerateOptions()
+	}
+
+	data, err := json.Marshal(struct {
+		Task         string      `json:"task"`
+		Model        string      `json:"model"`
+		Input        interface{} `json:"input"`
+		SystemPrompt string      `json:"systemPrompt"`
+		MaxTokens    int         `json:"maxTokens"`
+		Temperature  float64     `json:"temperature"`
+		TopP         float64     `json:"topP"`
+		StopWords    []string    `json:"stopWords"`
+	}{task, modelName, input, opts.SystemPrompt, opts.MaxTokens, opts.Temperature, opts.TopP, opts.StopWords})
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256(data)
+	return cacheKeyPrefix + task + ":" + hex.EncodeToString(sum[:]), true
+}
+
+// get returns a cached response, treating Redis errors as misses
+func (c *ResponseCache) get(key string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("LLM cache get failed: %v", err)
+		}
+		return "", false
+	}
+	return value, true
+}
+
+// set stores a response with the configured TTL
+func (c *ResponseCache) set(key, value string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.client.Set(ctx, key, value, c.ttl).Err(); err != nil {
+		log.Printf("LLM cache set failed: %v", err)
+	}
+}
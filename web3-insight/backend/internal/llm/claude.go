@@ -35,7 +35,7 @@ func (c *ClaudeAdapter) Name() string { return c.model }
 func (c *ClaudeAdapter) Type() string { return "cloud" }
 
 // Generate performs non-streaming generation
-func (c *ClaudeAdapter) Generate(prompt string, opts *GenerateOptions) (string, error) {
+func (c *ClaudeAdapter) Generate(prompt string, opts *GenerateOptions) (string, Usage, error) {
 	messages := []Message{
 		{Role: "user", Content: prompt},
 	}
@@ -51,7 +51,7 @@ func (c *ClaudeAdapter) GenerateStream(prompt string, opts *GenerateOptions) (<-
 }
 
 // GenerateChat performs chat completion
-func (c *ClaudeAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, error) {
+func (c *ClaudeAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, Usage, error) {
 	if opts == nil {
 		opts = DefaultGenerateOptions()
 	}
@@ -77,12 +77,12 @@ func (c *ClaudeAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", claudeAPIURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(requestContext(opts), "POST", claudeAPIURL, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -91,7 +91,7 @@ func (c *ClaudeAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("claude request failed: %w", err)
+		return "", Usage{}, fmt.Errorf("claude request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -102,7 +102,7 @@ func (c *ClaudeAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 			} `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return "", fmt.Errorf("claude returned status %d: %s", resp.StatusCode, errResp.Error.Message)
+		return "", Usage{}, &APIError{Provider: "claude", StatusCode: resp.StatusCode, Message: errResp.Error.Message}
 	}
 
 	var result struct {
@@ -116,14 +116,14 @@ func (c *ClaudeAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 		} `json:"usage"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(result.Content) > 0 && result.Content[0].Type == "text" {
-		return result.Content[0].Text, nil
+		return result.Content[0].Text, Usage{InputTokens: result.Usage.InputTokens, OutputTokens: result.Usage.OutputTokens}, nil
 	}
 
-	return "", fmt.Errorf("empty response from claude")
+	return "", Usage{}, fmt.Errorf("empty response from claude")
 }
 
 // GenerateChatStream performs streaming chat completion
@@ -157,7 +157,7 @@ func (c *ClaudeAdapter) GenerateChatStream(messages []Message, opts *GenerateOpt
 	go func() {
 		defer close(ch)
 
-		req, err := http.NewRequest("POST", claudeAPIURL, bytes.NewReader(body))
+		req, err := http.NewRequestWithContext(requestContext(opts), "POST", claudeAPIURL, bytes.NewReader(body))
 		if err != nil {
 			ch <- StreamChunk{Error: err}
 			return
@@ -260,3 +260,200 @@ func (c *ClaudeAdapter) EstimateCost(inputTokens, outputTokens int) float64 {
 
 	return (float64(inputTokens)/1000)*inputPrice + (float64(outputTokens)/1000)*outputPrice
 }
+
+// GenerateChatWithTools performs chat completion with tool use enabled
+func (c *ClaudeAdapter) GenerateChatWithTools(messages []Message, opts *GenerateOptions) (*GenerateResult, error) {
+	if opts == nil {
+		opts = DefaultGenerateOptions()
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 4096
+	}
+
+	tools := make([]map[string]interface{}, 0, len(opts.Tools))
+	for _, t := range opts.Tools {
+		tools = append(tools, map[string]interface{}{
+			"name":         t.Name,
+			"description":  t.Description,
+			"input_schema": t.Parameters,
+		})
+	}
+
+	payload := map[string]interface{}{
+		"model":      c.model,
+		"max_tokens": maxTokens,
+		"messages":   c.convertToolMessages(messages),
+		"tools":      tools,
+	}
+	if opts.SystemPrompt != "" {
+		payload["system"] = opts.SystemPrompt
+	}
+	if opts.Temperature > 0 {
+		payload["temperature"] = opts.Temperature
+	}
+	switch opts.ToolChoice {
+	case ToolChoiceRequired:
+		payload["tool_choice"] = map[string]string{"type": "any"}
+	case ToolChoiceNone:
+		payload["tool_choice"] = map[string]string{"type": "none"}
+	case "", ToolChoiceAuto:
+	default:
+		payload["tool_choice"] = map[string]string{"type": "tool", "name": opts.ToolChoice}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(requestContext(opts), "POST", claudeAPIURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", claudeAPIVersion)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("claude request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &APIError{Provider: "claude", StatusCode: resp.StatusCode, Message: errResp.Error.Message}
+	}
+
+	var result struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			ID    string          `json:"id"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	generated := &GenerateResult{
+		Usage: Usage{InputTokens: result.Usage.InputTokens, OutputTokens: result.Usage.OutputTokens},
+	}
+	var sb strings.Builder
+	for _, block := range result.Content {
+		switch block.Type {
+		case "text":
+			sb.WriteString(block.Text)
+		case "tool_use":
+			generated.ToolCalls = append(generated.ToolCalls, ToolCall{ID: block.ID, Name: block.Name, Arguments: block.Input})
+		}
+	}
+	generated.Content = sb.String()
+
+	switch result.StopReason {
+	case "tool_use":
+		generated.FinishReason = FinishReasonToolCall
+	case "max_tokens":
+		generated.FinishReason = FinishReasonLength
+	default:
+		generated.FinishReason = FinishReasonStop
+	}
+
+	return generated, nil
+}
+
+// convertToolMessages converts messages to Claude content blocks, mapping tool calls
+// to tool_use blocks and tool results to tool_result blocks in a user turn
+func (c *ClaudeAdapter) convertToolMessages(messages []Message) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(messages))
+	for _, m := range messages {
+		switch {
+		case m.Role == "system":
+			continue
+		case m.Role == "tool":
+			block := map[string]interface{}{
+				"type":        "tool_result",
+				"tool_use_id": m.ToolCallID,
+				"content":     m.Content,
+			}
+			// Consecutive tool results belong in the same user turn
+			if n := len(result); n > 0 && result[n-1]["role"] == "user" {
+				if blocks, ok := result[n-1]["content"].([]map[string]interface{}); ok {
+					result[n-1]["content"] = append(blocks, block)
+					continue
+				}
+			}
+			result = append(result, map[string]interface{}{
+				"role":    "user",
+				"content": []map[string]interface{}{block},
+			})
+		case len(m.ToolCalls) > 0:
+			blocks := make([]map[string]interface{}, 0, len(m.ToolCalls)+1)
+			if m.Content != "" {
+				blocks = append(blocks, map[string]interface{}{"type": "text", "text": m.Content})
+			}
+			for _, call := range m.ToolCalls {
+				input := call.Arguments
+				if len(input) == 0 {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, map[string]interface{}{
+					"type":  "tool_use",
+					"id":    call.ID,
+					"name":  call.Name,
+					"input": input,
+				})
+			}
+			result = append(result, map[string]interface{}{
+				"role":    m.Role,
+				"content": blocks,
+			})
+		default:
+			result = append(result, map[string]interface{}{
+				"role":    m.Role,
+				"content": m.Content,
+			})
+		}
+	}
+	return result
+}
+
+// GenerateStructured forces a single tool call whose input schema is the output schema
+func (c *ClaudeAdapter) GenerateStructured(prompt string, schema *JSONSchema, opts *GenerateOptions) (string, Usage, error) {
+	if opts == nil {
+		opts = DefaultGenerateOptions()
+	}
+
+	toolOpts := *opts
+	toolOpts.Tools = []Tool{{
+		Name:        schema.Name,
+		Description: "Return the result in this exact structure.",
+		Parameters:  schema.Schema,
+	}}
+	toolOpts.ToolChoice = schema.Name
+
+	result, err := c.GenerateChatWithTools([]Message{{Role: "user", Content: prompt}}, &toolOpts)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	for _, call := range result.ToolCalls {
+		if call.Name == schema.Name {
+			return string(call.Arguments), result.Usage, nil
+		}
+	}
+	return "", result.Usage, fmt.Errorf("claude did not return %s output", schema.Name)
+}
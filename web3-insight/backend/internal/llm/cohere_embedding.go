@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	cohereEmbedURL = "https://api.cohere.com/v2/embed"
+
+	// DefaultCohereEmbeddingModel handles the mixed Chinese and English content of the knowledge base
+	DefaultCohereEmbeddingModel = "embed-multilingual-v3.0"
+	cohereEmbeddingBatchSize    = 96 // API limit on texts per request
+)
+
+// CohereEmbeddingAdapter generates embeddings using the Cohere embed API
+type CohereEmbeddingAdapter struct {
+	apiKey     string
+	model      string
+	dimensions int
+	endpoint   string
+	client     *http.Client
+}
+
+// NewCohereEmbeddingAdapter creates a new Cohere embedding adapter. Cohere models have a fixed
+// size, so dimensions only needs setting for models missing from the known model list.
+func NewCohereEmbeddingAdapter(apiKey, model string, dimensions int) *CohereEmbeddingAdapter {
+	if model == "" {
+		model = DefaultCohereEmbeddingModel
+	}
+	if dimensions <= 0 {
+		dimensions = EmbeddingModelDimensions(model)
+	}
+	return &CohereEmbeddingAdapter{
+		apiKey:     apiKey,
+		model:      model,
+		dimensions: dimensions,
+		endpoint:   cohereEmbedURL,
+		client: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// WithBaseURL points the adapter at another Cohere-compatible API, e.g. https://example.com/v2
+func (c *CohereEmbeddingAdapter) WithBaseURL(baseURL string) *CohereEmbeddingAdapter {
+	if baseURL != "" {
+		c.endpoint = strings.TrimRight(baseURL, "/") + "/embed"
+	}
+	return c
+}
+
+func (c *CohereEmbeddingAdapter) Name() string    { return c.model }
+func (c *CohereEmbeddingAdapter) Dimensions() int { return c.dimensions }
+
+// GenerateEmbedding generates embedding for a single text
+func (c *CohereEmbeddingAdapter) GenerateEmbedding(text string) ([]float32, error) {
+	embeddings, err := c.embed([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// GenerateBatchEmbeddings generates embeddings for multiple texts, batching requests
+func (c *CohereEmbeddingAdapter) GenerateBatchEmbeddings(texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += cohereEmbeddingBatchSize {
+		end := start + cohereEmbeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batch, err := c.embed(texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings for texts %d-%d: %w", start, end-1, err)
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+// IsAvailable reports whether the adapter has credentials; the API itself is not probed
+func (c *CohereEmbeddingAdapter) IsAvailable() bool {
+	return c.apiKey != ""
+}
+
+// embed sends one embed request and returns the vectors in input order
+func (c *CohereEmbeddingAdapter) embed(texts []string) ([][]float32, error) {
+	payload := map[string]interface{}{
+		"model": c.model,
+		"texts": texts,
+		// The same vectors serve as documents and queries, so use the document input type for both
+		"input_type":      "search_document",
+		"embedding_types": []string{"float"},
+		"truncate":        "END",
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cohere embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &APIError{Provider: "cohere", StatusCode: resp.StatusCode, Message: errResp.Message}
+	}
+
+	var result struct {
+		Embeddings struct {
+			Float [][]float32 `json:"float"`
+		} `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Embeddings.Float) != len(texts) {
+		return nil, fmt.Errorf("cohere returned %d embeddings for %d inputs", len(result.Embeddings.Float), len(texts))
+	}
+	return result.Embeddings.Float, nil
+}
@@ -0,0 +1,199 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Concurrency defaults used when no limits are configured
+const (
+	defaultLocalConcurrency = 1 // One heavy generation at a time on a local Ollama host
+	defaultQueueTimeout     = 5 * time.Minute
+)
+
+// ErrQueueTimeout is returned when a call waited too long for a free slot; the router falls back to the next model
+var ErrQueueTimeout = errors.New("timed out waiting for a free llm slot")
+
+// ConcurrencyLimits caps in-flight calls per provider, with per-model overrides.
+// Providers without a limit are unbounded, except local ones which default to one call.
+type ConcurrencyLimits struct {
+	Providers    map[string]int // Keyed by ProviderOf, e.g. ollama, claude, openai
+	Models       map[string]int // Keyed by adapter name; takes precedence over the provider limit
+	QueueTimeout time.Duration  // How long a call waits for a slot; 0 uses the default
+}
+
+// ConcurrencyStatus reports the usage of one semaphore
+type ConcurrencyStatus struct {
+	Key     string `json:"key"` // provider:<name> or model:<name>
+	Limit   int    `json:"limit"`
+	Active  int    `json:"active"`
+	Waiting int    `json:"waiting"`
+}
+
+// semaphore is a counting semaphore that tracks queued callers
+type semaphore struct {
+	key     string
+	slots   chan struct{}
+	mu      sync.Mutex
+	waiting int
+}
+
+// limiter holds the concurrency limits and semaphores of a process. Every router shares it,
+// so the API, chat and an in-process worker together stay within one set of caps.
+type limiter struct {
+	mu         sync.RWMutex
+	limits     ConcurrencyLimits
+	semaphores map[string]*semaphore // Keyed by provider:<name> or model:<name>
+}
+
+// processLimiter is the limiter shared by all routers
+var processLimiter = &limiter{semaphores: make(map[string]*semaphore)}
+
+// concurrencyLimitsFromConfig converts configured limits
+func concurrencyLimitsFromConfig(cfg config.LLMConcurrencyConfig) ConcurrencyLimits {
+	return ConcurrencyLimits{
+		Providers:    cfg.Providers,
+		Models:       cfg.Models,
+		QueueTimeout: time.Duration(cfg.QueueTimeout) * time.Second,
+	}
+}
+
+// SetConcurrencyLimits replaces the concurrency limits of the process, which every router
+// shares. Setting the limits already in place keeps the semaphores; otherwise calls already
+// holding a slot keep it.
+func (r *Router) SetConcurrencyLimits(limits ConcurrencyLimits) {
+	if limits.QueueTimeout <= 0 {
+		limits.QueueTimeout = defaultQueueTimeout
+	}
+	l := processLimiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if reflect.DeepEqual(l.limits, limits) {
+		return
+	}
+	l.limits = limits
+	l.semaphores = make(map[string]*semaphore)
+}
+
+// ConcurrencyStatus returns the usage of every semaphore of the process created so far
+func (r *Router) ConcurrencyStatus() []ConcurrencyStatus {
+	l := processLimiter
+	l.mu.RLock()
+	sems := make([]*semaphore, 0, len(l.semaphores))
+	for _, sem := range l.semaphores {
+		sems = append(sems, sem)
+	}
+	l.mu.RUnlock()
+
+	statuses := make([]ConcurrencyStatus, 0, len(sems))
+	for _, sem := range sems {
+		sem.mu.Lock()
+		waiting := sem.waiting
+		sem.mu.Unlock()
+		statuses = append(statuses, ConcurrencyStatus{
+			Key:     sem.key,
+			Limit:   cap(sem.slots),
+			Active:  len(sem.slots),
+			Waiting: waiting,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Key < statuses[j].Key })
+	return statuses
+}
+
+// semaphoreFor returns the semaphore limiting a model, or nil if it is unbounded
+func (r *Router) semaphoreFor(modelName string) (*semaphore, time.Duration) {
+	r.mu.RLock()
+	adapter := r.adapters[modelName]
+	r.mu.RUnlock()
+	if adapter == nil {
+		return nil, 0
+	}
+	l := processLimiter
+	l.mu.RLock()
+	limits := l.limits
+	l.mu.RUnlock()
+
+	key, limit := "", 0
+	if n, ok := limits.Models[modelName]; ok {
+		key, limit = "model:"+modelName, n
+	} else {
+		provider := ProviderOf(adapter)
+		n, ok := limits.Providers[provider]
+		if !ok && adapter.Type() == "local" {
+			n = defaultLocalConcurrency
+		}
+		key, limit = "provider:"+provider, n
+	}
+	if limit <= 0 {
+		return nil, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.semaphores[key]
+	if !ok {
+		sem = &semaphore{key: key, slots: make(chan struct{}, limit)}
+		l.semaphores[key] = sem
+	}
+	queueTimeout := limits.QueueTimeout
+	if queueTimeout <= 0 {
+		queueTimeout = defaultQueueTimeout
+	}
+	return sem, queueTimeout
+}
+
+// acquireSlot waits for a free slot for the model, queueing behind other callers.
+// The returned release must be called once the call has finished.
+func (r *Router) acquireSlot(modelName string) (func(), error) {
+	sem, queueTimeout := r.semaphoreFor(modelName)
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	release := func() { <-sem.slots }
+	select {
+	case sem.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	sem.mu.Lock()
+	sem.waiting++
+	sem.mu.Unlock()
+	defer func() {
+		sem.mu.Lock()
+		sem.waiting--
+		sem.mu.Unlock()
+	}()
+
+	log.Printf("waiting for %s slot (limit %d)", sem.key, cap(sem.slots))
+	timer := time.NewTimer(queueTimeout)
+	defer timer.Stop()
+	select {
+	case sem.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("%w: %s after %v", ErrQueueTimeout, sem.key, queueTimeout)
+	}
+}
+
+// releaseAfterStream relays a stream and releases its slot once the stream closes
+func releaseAfterStream(stream <-chan StreamChunk, release func()) <-chan StreamChunk {
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		defer release()
+		for chunk := range stream {
+			out <- chunk
+		}
+	}()
+	return out
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"log"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Embedding providers selectable with llm.embedding.provider
+const (
+	EmbeddingProviderOllama = "ollama"
+	EmbeddingProviderOpenAI = "openai"
+	EmbeddingProviderCohere = "cohere"
+	EmbeddingProviderVoyage = "voyage"
+)
+
+// embeddingModelDimensions is the native vector size of well-known embedding models
+var embeddingModelDimensions = map[string]int{
+	"nomic-embed-text":              768,
+	"mxbai-embed-large":             1024,
+	"bge-m3":                        1024,
+	"text-embedding-3-small":        1536,
+	"text-embedding-3-large":        3072,
+	"text-embedding-ada-002":        1536,
+	"embed-english-v3.0":            1024,
+	"embed-multilingual-v3.0":       1024,
+	"embed-english-light-v3.0":      384,
+	"embed-multilingual-light-v3.0": 384,
+	"voyage-3":                      1024,
+	"voyage-3-lite":                 512,
+	"voyage-3-large":                1024,
+	"voyage-3.5":                    1024,
+	"voyage-code-3":                 1024,
+	"voyage-multilingual-2":         1024,
+}
+
+// EmbeddingModelDimensions returns the native vector size of a model, or 0 if it is unknown
+func EmbeddingModelDimensions(model string) int {
+	return embeddingModelDimensions[model]
+}
+
+// NewEmbeddingAdapterFromConfig creates the embedding adapter selected in the config
+func NewEmbeddingAdapterFromConfig(cfg *config.LLMConfig) EmbeddingAdapter {
+	e := cfg.Embedding
+	switch e.Provider {
+	case EmbeddingProviderOpenAI:
+		apiKey := e.APIKey
+		if apiKey == "" {
+			apiKey = cfg.OpenAI.APIKey
+		}
+		return NewOpenAIEmbeddingAdapter(apiKey, e.Model, e.Dimensions).WithBaseURL(e.BaseURL)
+	case EmbeddingProviderCohere:
+		return NewCohereEmbeddingAdapter(e.APIKey, e.Model, e.Dimensions).WithBaseURL(e.BaseURL)
+	case EmbeddingProviderVoyage:
+		return NewVoyageEmbeddingAdapter(e.APIKey, e.Model, e.Dimensions).WithBaseURL(e.BaseURL)
+	case "", EmbeddingProviderOllama:
+	default:
+		log.Printf("Warning: unknown embedding provider %q, using ollama", e.Provider)
+	}
+
+	if e.Model == "" {
+		return DefaultOllamaEmbeddingAdapter(cfg.OllamaHost)
+	}
+	dimensions := e.Dimensions
+	if dimensions <= 0 {
+		dimensions = EmbeddingModelDimensions(e.Model)
+	}
+	return NewOllamaEmbeddingAdapter(cfg.OllamaHost, e.Model, dimensions)
+}
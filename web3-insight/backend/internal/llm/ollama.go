@@ -3,6 +3,7 @@ package llm
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -31,12 +32,25 @@ func (o *OllamaAdapter) Name() string { return o.model }
 func (o *OllamaAdapter) Type() string { return "local" }
 
 // Generate performs non-streaming generation
-func (o *OllamaAdapter) Generate(prompt string, opts *GenerateOptions) (string, error) {
+func (o *OllamaAdapter) Generate(prompt string, opts *GenerateOptions) (string, Usage, error) {
+	return o.generate(prompt, opts, nil)
+}
+
+// GenerateStructured constrains output to the schema via Ollama's format parameter
+func (o *OllamaAdapter) GenerateStructured(prompt string, schema *JSONSchema, opts *GenerateOptions) (string, Usage, error) {
+	return o.generate(prompt, opts, schema.Schema)
+}
+
+// generate calls /api/generate, optionally with a JSON schema output format
+func (o *OllamaAdapter) generate(prompt string, opts *GenerateOptions, format interface{}) (string, Usage, error) {
 	payload := map[string]interface{}{
 		"model":  o.model,
 		"prompt": prompt,
 		"stream": false,
 	}
+	if format != nil {
+		payload["format"] = format
+	}
 
 	if opts != nil {
 		if opts.SystemPrompt != "" {
@@ -51,28 +65,30 @@ func (o *OllamaAdapter) Generate(prompt string, opts *GenerateOptions) (string,
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := o.client.Post(o.host+"/api/generate", "application/json", bytes.NewReader(body))
+	resp, err := o.post(requestContext(opts), "/api/generate", body)
 	if err != nil {
-		return "", fmt.Errorf("ollama request failed: %w", err)
+		return "", Usage{}, fmt.Errorf("ollama request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama returned status %d", resp.StatusCode)
+		return "", Usage{}, &APIError{Provider: "ollama", StatusCode: resp.StatusCode}
 	}
 
 	var result struct {
-		Response string `json:"response"`
-		Done     bool   `json:"done"`
+		Response        string `json:"response"`
+		Done            bool   `json:"done"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result.Response, nil
+	return result.Response, Usage{InputTokens: result.PromptEvalCount, OutputTokens: result.EvalCount}, nil
 }
 
 // GenerateStream performs streaming generation
@@ -105,7 +121,7 @@ func (o *OllamaAdapter) GenerateStream(prompt string, opts *GenerateOptions) (<-
 	go func() {
 		defer close(ch)
 
-		resp, err := o.client.Post(o.host+"/api/generate", "application/json", bytes.NewReader(body))
+		resp, err := o.post(requestContext(opts), "/api/generate", body)
 		if err != nil {
 			ch <- StreamChunk{Error: err}
 			return
@@ -137,7 +153,7 @@ func (o *OllamaAdapter) GenerateStream(prompt string, opts *GenerateOptions) (<-
 }
 
 // GenerateChat performs chat completion
-func (o *OllamaAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, error) {
+func (o *OllamaAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, Usage, error) {
 	payload := map[string]interface{}{
 		"model":    o.model,
 		"messages": messages,
@@ -152,29 +168,31 @@ func (o *OllamaAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := o.client.Post(o.host+"/api/chat", "application/json", bytes.NewReader(body))
+	resp, err := o.post(requestContext(opts), "/api/chat", body)
 	if err != nil {
-		return "", fmt.Errorf("ollama chat request failed: %w", err)
+		return "", Usage{}, fmt.Errorf("ollama chat request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama returned status %d", resp.StatusCode)
+		return "", Usage{}, &APIError{Provider: "ollama", StatusCode: resp.StatusCode}
 	}
 
 	var result struct {
 		Message struct {
 			Content string `json:"content"`
 		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result.Message.Content, nil
+	return result.Message.Content, Usage{InputTokens: result.PromptEvalCount, OutputTokens: result.EvalCount}, nil
 }
 
 // GenerateChatStream performs streaming chat completion
@@ -202,7 +220,7 @@ func (o *OllamaAdapter) GenerateChatStream(messages []Message, opts *GenerateOpt
 	go func() {
 		defer close(ch)
 
-		resp, err := o.client.Post(o.host+"/api/chat", "application/json", bytes.NewReader(body))
+		resp, err := o.post(requestContext(opts), "/api/chat", body)
 		if err != nil {
 			ch <- StreamChunk{Error: err}
 			return
@@ -235,6 +253,16 @@ func (o *OllamaAdapter) GenerateChatStream(messages []Message, opts *GenerateOpt
 	return ch, nil
 }
 
+// post sends a JSON request to an Ollama API path
+func (o *OllamaAdapter) post(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", o.host+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return o.client.Do(req)
+}
+
 // IsAvailable checks if Ollama is running and the model is available
 func (o *OllamaAdapter) IsAvailable() bool {
 	client := &http.Client{Timeout: 5 * time.Second}
@@ -14,16 +14,19 @@ const openaiAPIURL = "https://api.openai.com/v1/chat/completions"
 
 // OpenAIAdapter implements LLMAdapter for OpenAI models
 type OpenAIAdapter struct {
-	apiKey string
-	model  string
-	client *http.Client
+	apiKey   string
+	model    string
+	endpoint string
+	headers  map[string]string // Extra headers sent with every request
+	client   *http.Client
 }
 
 // NewOpenAIAdapter creates a new OpenAI adapter
 func NewOpenAIAdapter(apiKey, model string) *OpenAIAdapter {
 	return &OpenAIAdapter{
-		apiKey: apiKey,
-		model:  model,
+		apiKey:   apiKey,
+		model:    model,
+		endpoint: openaiAPIURL,
 		client: &http.Client{
 			Timeout: 5 * time.Minute,
 		},
@@ -34,7 +37,7 @@ func (o *OpenAIAdapter) Name() string { return o.model }
 func (o *OpenAIAdapter) Type() string { return "cloud" }
 
 // Generate performs non-streaming generation
-func (o *OpenAIAdapter) Generate(prompt string, opts *GenerateOptions) (string, error) {
+func (o *OpenAIAdapter) Generate(prompt string, opts *GenerateOptions) (string, Usage, error) {
 	messages := []Message{
 		{Role: "user", Content: prompt},
 	}
@@ -50,7 +53,7 @@ func (o *OpenAIAdapter) GenerateStream(prompt string, opts *GenerateOptions) (<-
 }
 
 // GenerateChat performs chat completion
-func (o *OpenAIAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, error) {
+func (o *OpenAIAdapter) GenerateChat(messages []Message, opts *GenerateOptions) (string, Usage, error) {
 	if opts == nil {
 		opts = DefaultGenerateOptions()
 	}
@@ -77,20 +80,19 @@ func (o *OpenAIAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", openaiAPIURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(requestContext(opts), "POST", o.endpoint, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	o.setHeaders(req)
 
 	resp, err := o.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("openai request failed: %w", err)
+		return "", Usage{}, fmt.Errorf("openai request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -101,7 +103,7 @@ func (o *OpenAIAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 			} `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return "", fmt.Errorf("openai returned status %d: %s", resp.StatusCode, errResp.Error.Message)
+		return "", Usage{}, &APIError{Provider: "openai", StatusCode: resp.StatusCode, Message: errResp.Error.Message}
 	}
 
 	var result struct {
@@ -116,14 +118,14 @@ func (o *OpenAIAdapter) GenerateChat(messages []Message, opts *GenerateOptions)
 		} `json:"usage"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(result.Choices) > 0 {
-		return result.Choices[0].Message.Content, nil
+		return result.Choices[0].Message.Content, Usage{InputTokens: result.Usage.PromptTokens, OutputTokens: result.Usage.CompletionTokens}, nil
 	}
 
-	return "", fmt.Errorf("empty response from openai")
+	return "", Usage{}, fmt.Errorf("empty response from openai")
 }
 
 // GenerateChatStream performs streaming chat completion
@@ -158,14 +160,13 @@ func (o *OpenAIAdapter) GenerateChatStream(messages []Message, opts *GenerateOpt
 	go func() {
 		defer close(ch)
 
-		req, err := http.NewRequest("POST", openaiAPIURL, bytes.NewReader(body))
+		req, err := http.NewRequestWithContext(requestContext(opts), "POST", o.endpoint, bytes.NewReader(body))
 		if err != nil {
 			ch <- StreamChunk{Error: err}
 			return
 		}
 
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+		o.setHeaders(req)
 
 		resp, err := o.client.Do(req)
 		if err != nil {
@@ -220,6 +221,17 @@ func (o *OpenAIAdapter) GenerateChatStream(messages []Message, opts *GenerateOpt
 	return ch, nil
 }
 
+// setHeaders sets content type, auth and any extra headers on a request
+func (o *OpenAIAdapter) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	for key, value := range o.headers {
+		req.Header.Set(key, value)
+	}
+}
+
 // convertMessages converts our Message type to OpenAI's format
 func (o *OpenAIAdapter) convertMessages(messages []Message, systemPrompt string) []map[string]string {
 	result := make([]map[string]string, 0, len(messages)+1)
@@ -274,3 +286,235 @@ func (o *OpenAIAdapter) EstimateCost(inputTokens, outputTokens int) float64 {
 
 	return (float64(inputTokens)/1000)*inputPrice + (float64(outputTokens)/1000)*outputPrice
 }
+
+// GenerateChatWithTools performs chat completion with function tools enabled
+func (o *OpenAIAdapter) GenerateChatWithTools(messages []Message, opts *GenerateOptions) (*GenerateResult, error) {
+	if opts == nil {
+		opts = DefaultGenerateOptions()
+	}
+
+	tools := make([]map[string]interface{}, 0, len(opts.Tools))
+	for _, t := range opts.Tools {
+		tools = append(tools, map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        t.Name,
+				"description": t.Description,
+				"parameters":  t.Parameters,
+			},
+		})
+	}
+
+	payload := map[string]interface{}{
+		"model":    o.model,
+		"messages": o.convertToolMessages(messages, opts.SystemPrompt),
+		"tools":    tools,
+	}
+	if opts.MaxTokens > 0 {
+		payload["max_tokens"] = opts.MaxTokens
+	}
+	if opts.Temperature > 0 {
+		payload["temperature"] = opts.Temperature
+	}
+	switch opts.ToolChoice {
+	case "":
+	case ToolChoiceAuto, ToolChoiceRequired, ToolChoiceNone:
+		payload["tool_choice"] = opts.ToolChoice
+	default:
+		payload["tool_choice"] = map[string]interface{}{
+			"type":     "function",
+			"function": map[string]string{"name": opts.ToolChoice},
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(requestContext(opts), "POST", o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	o.setHeaders(req)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &APIError{Provider: "openai", StatusCode: resp.StatusCode, Message: errResp.Error.Message}
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content   string `json:"content"`
+				ToolCalls []struct {
+					ID       string `json:"id"`
+					Function struct {
+						Name      string `json:"name"`
+						Arguments string `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("empty response from openai")
+	}
+
+	choice := result.Choices[0]
+	generated := &GenerateResult{
+		Content: choice.Message.Content,
+		Usage:   Usage{InputTokens: result.Usage.PromptTokens, OutputTokens: result.Usage.CompletionTokens},
+	}
+	for _, call := range choice.Message.ToolCalls {
+		args := json.RawMessage(call.Function.Arguments)
+		if !json.Valid(args) {
+			args = json.RawMessage("{}")
+		}
+		generated.ToolCalls = append(generated.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: args})
+	}
+
+	switch choice.FinishReason {
+	case "tool_calls":
+		generated.FinishReason = FinishReasonToolCall
+	case "length":
+		generated.FinishReason = FinishReasonLength
+	default:
+		generated.FinishReason = FinishReasonStop
+	}
+
+	return generated, nil
+}
+
+// convertToolMessages converts messages to OpenAI's format including tool calls and results
+func (o *OpenAIAdapter) convertToolMessages(messages []Message, systemPrompt string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(messages)+1)
+
+	if systemPrompt != "" {
+		result = append(result, map[string]interface{}{
+			"role":    "system",
+			"content": systemPrompt,
+		})
+	}
+
+	for _, m := range messages {
+		msg := map[string]interface{}{
+			"role":    m.Role,
+			"content": m.Content,
+		}
+		if m.Role == "tool" {
+			msg["tool_call_id"] = m.ToolCallID
+		}
+		if len(m.ToolCalls) > 0 {
+			calls := make([]map[string]interface{}, 0, len(m.ToolCalls))
+			for _, call := range m.ToolCalls {
+				calls = append(calls, map[string]interface{}{
+					"id":   call.ID,
+					"type": "function",
+					"function": map[string]string{
+						"name":      call.Name,
+						"arguments": string(call.Arguments),
+					},
+				})
+			}
+			msg["tool_calls"] = calls
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+// GenerateStructured requests JSON output constrained by response_format json_schema
+func (o *OpenAIAdapter) GenerateStructured(prompt string, schema *JSONSchema, opts *GenerateOptions) (string, Usage, error) {
+	if opts == nil {
+		opts = DefaultGenerateOptions()
+	}
+
+	payload := map[string]interface{}{
+		"model":    o.model,
+		"messages": o.convertMessages([]Message{{Role: "user", Content: prompt}}, opts.SystemPrompt),
+		"response_format": map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   schema.Name,
+				"schema": schema.Schema,
+			},
+		},
+	}
+	if opts.MaxTokens > 0 {
+		payload["max_tokens"] = opts.MaxTokens
+	}
+	if opts.Temperature > 0 {
+		payload["temperature"] = opts.Temperature
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(requestContext(opts), "POST", o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	o.setHeaders(req)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return "", Usage{}, &APIError{Provider: "openai", StatusCode: resp.StatusCode, Message: errResp.Error.Message}
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", Usage{}, fmt.Errorf("empty response from openai")
+	}
+
+	return result.Choices[0].Message.Content, Usage{InputTokens: result.Usage.PromptTokens, OutputTokens: result.Usage.CompletionTokens}, nil
+}
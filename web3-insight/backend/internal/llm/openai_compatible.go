@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAICompatibleAdapter implements LLMAdapter for any server speaking the OpenAI
+// chat-completions protocol (vLLM, LM Studio, OpenRouter, ...)
+type OpenAICompatibleAdapter struct {
+	*OpenAIAdapter
+	name        string
+	adapterType string
+	inputPrice  float64 // USD per 1K input tokens
+	outputPrice float64 // USD per 1K output tokens
+}
+
+// NewOpenAICompatibleAdapter creates an adapter for an OpenAI-compatible endpoint.
+// baseURL is the API root, e.g. "http://localhost:8000/v1" or "https://openrouter.ai/api/v1".
+func NewOpenAICompatibleAdapter(name, baseURL, apiKey, model string) *OpenAICompatibleAdapter {
+	if name == "" {
+		name = model
+	}
+	return &OpenAICompatibleAdapter{
+		OpenAIAdapter: &OpenAIAdapter{
+			apiKey:   apiKey,
+			model:    model,
+			endpoint: strings.TrimRight(baseURL, "/") + "/chat/completions",
+			client: &http.Client{
+				Timeout: 5 * time.Minute,
+			},
+		},
+		name:        name,
+		adapterType: "cloud",
+	}
+}
+
+// WithType sets whether the adapter is reported as "local" or "cloud"
+func (a *OpenAICompatibleAdapter) WithType(adapterType string) *OpenAICompatibleAdapter {
+	if adapterType != "" {
+		a.adapterType = adapterType
+	}
+	return a
+}
+
+// WithHeaders sets extra headers sent with every request (e.g. OpenRouter's HTTP-Referer)
+func (a *OpenAICompatibleAdapter) WithHeaders(headers map[string]string) *OpenAICompatibleAdapter {
+	a.headers = headers
+	return a
+}
+
+// WithPricing sets the per-1K-token prices used for cost estimation
+func (a *OpenAICompatibleAdapter) WithPricing(inputPrice, outputPrice float64) *OpenAICompatibleAdapter {
+	a.inputPrice = inputPrice
+	a.outputPrice = outputPrice
+	return a
+}
+
+func (a *OpenAICompatibleAdapter) Name() string { return a.name }
+func (a *OpenAICompatibleAdapter) Type() string { return a.adapterType }
+
+// IsAvailable checks if an endpoint is configured; self-hosted servers often need no API key
+func (a *OpenAICompatibleAdapter) IsAvailable() bool {
+	return a.endpoint != "" && a.model != ""
+}
+
+// EstimateCost estimates the cost from the configured pricing (free if unset)
+func (a *OpenAICompatibleAdapter) EstimateCost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)/1000)*a.inputPrice + (float64(outputTokens)/1000)*a.outputPrice
+}
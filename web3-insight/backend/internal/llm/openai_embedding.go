@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	openaiEmbeddingsURL = "https://api.openai.com/v1/embeddings"
+
+	// DefaultOpenAIEmbeddingModel matches the 1536-dimension vector columns without truncation
+	DefaultOpenAIEmbeddingModel = "text-embedding-3-small"
+	defaultEmbeddingDimensions  = 1536
+	openaiEmbeddingBatchSize    = 100
+)
+
+// OpenAIEmbeddingAdapter generates embeddings using the OpenAI embeddings API
+type OpenAIEmbeddingAdapter struct {
+	apiKey     string
+	model      string
+	dimensions int
+	endpoint   string
+	client     *http.Client
+}
+
+// NewOpenAIEmbeddingAdapter creates a new OpenAI embedding adapter. text-embedding-3 models are
+// shortened to dimensions by the API, so text-embedding-3-large fits the same vector columns.
+func NewOpenAIEmbeddingAdapter(apiKey, model string, dimensions int) *OpenAIEmbeddingAdapter {
+	if model == "" {
+		model = DefaultOpenAIEmbeddingModel
+	}
+	if dimensions <= 0 {
+		dimensions = defaultEmbeddingDimensions
+	}
+	return &OpenAIEmbeddingAdapter{
+		apiKey:     apiKey,
+		model:      model,
+		dimensions: dimensions,
+		endpoint:   openaiEmbeddingsURL,
+		client: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// WithBaseURL points the adapter at an OpenAI-compatible API, e.g. https://example.com/v1
+func (o *OpenAIEmbeddingAdapter) WithBaseURL(baseURL string) *OpenAIEmbeddingAdapter {
+	if baseURL != "" {
+		o.endpoint = strings.TrimRight(baseURL, "/") + "/embeddings"
+	}
+	return o
+}
+
+func (o *OpenAIEmbeddingAdapter) Name() string    { return o.model }
+func (o *OpenAIEmbeddingAdapter) Dimensions() int { return o.dimensions }
+
+// GenerateEmbedding generates embedding for a single text
+func (o *OpenAIEmbeddingAdapter) GenerateEmbedding(text string) ([]float32, error) {
+	embeddings, err := o.embed([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// GenerateBatchEmbeddings generates embeddings for multiple texts, batching requests
+func (o *OpenAIEmbeddingAdapter) GenerateBatchEmbeddings(texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += openaiEmbeddingBatchSize {
+		end := start + openaiEmbeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batch, err := o.embed(texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings for texts %d-%d: %w", start, end-1, err)
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+// IsAvailable reports whether the adapter has credentials; the API itself is not probed
+func (o *OpenAIEmbeddingAdapter) IsAvailable() bool {
+	return o.apiKey != ""
+}
+
+// embed sends one embeddings request and returns the vectors in input order
+func (o *OpenAIEmbeddingAdapter) embed(texts []string) ([][]float32, error) {
+	payload := map[string]interface{}{
+		"model": o.model,
+		"input": texts,
+	}
+	// Only text-embedding-3 and later accept a custom size
+	if !strings.HasPrefix(o.model, "text-embedding-ada") {
+		payload["dimensions"] = o.dimensions
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &APIError{Provider: "openai", StatusCode: resp.StatusCode, Message: errResp.Error.Message}
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d inputs", len(result.Data), len(texts))
+	}
+
+	sort.Slice(result.Data, func(i, j int) bool { return result.Data[i].Index < result.Data[j].Index })
+	embeddings := make([][]float32, len(result.Data))
+	for i, d := range result.Data {
+		embeddings[i] = d.Embedding
+	}
+	return embeddings, nil
+}
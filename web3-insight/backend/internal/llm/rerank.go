@@ -0,0 +1,229 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+const (
+	cohereRerankURL          = "https://api.cohere.com/v2/rerank"
+	defaultCohereRerankModel = "rerank-v3.5" // Multilingual, handles Chinese queries
+	// DefaultRerankCandidates is how many vector hits are re-scored
+	DefaultRerankCandidates = 50
+	// ollamaRerankParallelism caps concurrent scoring calls to a local model
+	ollamaRerankParallelism = 4
+)
+
+// RerankResult is the relevance of one document to the query
+type RerankResult struct {
+	Index int     `json:"index"` // Position in the documents passed to Rerank
+	Score float64 `json:"score"` // Higher is more relevant; scales differ between rerankers
+}
+
+// Reranker re-scores documents by relevance to a query
+type Reranker interface {
+	Name() string
+	// Rerank returns results for all documents, most relevant first
+	Rerank(query string, documents []string) ([]RerankResult, error)
+}
+
+// NewRerankerFromConfig creates the configured reranker, or nil if reranking is disabled
+func NewRerankerFromConfig(cfg *config.LLMConfig) Reranker {
+	r := cfg.Rerank
+	if !r.Enabled {
+		return nil
+	}
+	switch r.Provider {
+	case "cohere":
+		if r.APIKey == "" {
+			log.Printf("Warning: cohere rerank enabled without api_key, reranking disabled")
+			return nil
+		}
+		return NewCohereReranker(r.APIKey, r.Model).WithBaseURL(r.BaseURL)
+	case "", "ollama":
+		if r.Model == "" {
+			log.Printf("Warning: ollama rerank enabled without a model, reranking disabled")
+			return nil
+		}
+		return NewOllamaReranker(NewOllamaAdapter(cfg.OllamaHost, r.Model))
+	default:
+		log.Printf("Warning: unknown rerank provider %q, reranking disabled", r.Provider)
+		return nil
+	}
+}
+
+// CohereReranker uses the Cohere Rerank API
+type CohereReranker struct {
+	apiKey   string
+	model    string
+	endpoint string
+	client   *http.Client
+}
+
+// NewCohereReranker creates a new Cohere reranker
+func NewCohereReranker(apiKey, model string) *CohereReranker {
+	if model == "" {
+		model = defaultCohereRerankModel
+	}
+	return &CohereReranker{
+		apiKey:   apiKey,
+		model:    model,
+		endpoint: cohereRerankURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// WithBaseURL points the reranker at another Cohere-compatible API, e.g. https://example.com/v2
+func (c *CohereReranker) WithBaseURL(baseURL string) *CohereReranker {
+	if baseURL != "" {
+		c.endpoint = strings.TrimRight(baseURL, "/") + "/rerank"
+	}
+	return c
+}
+
+func (c *CohereReranker) Name() string { return c.model }
+
+// Rerank scores the documents with the rerank model
+func (c *CohereReranker) Rerank(query string, documents []string) ([]RerankResult, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":     c.model,
+		"query":     query,
+		"documents": documents,
+		"top_n":     len(documents),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cohere rerank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &APIError{Provider: "cohere", StatusCode: resp.StatusCode, Message: errResp.Message}
+	}
+
+	var result struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	results := make([]RerankResult, 0, len(result.Results))
+	for _, r := range result.Results {
+		if r.Index >= 0 && r.Index < len(documents) {
+			results = append(results, RerankResult{Index: r.Index, Score: r.RelevanceScore})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
+
+// rerankScoreSchema is the JSON schema of a local relevance judgement
+var rerankScoreSchema = MustJSONSchema("relevance_score", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"score": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 10},
+	},
+	"required": []interface{}{"score"},
+})
+
+// rerankPrompt asks a local model to judge one query-document pair, cross-encoder style
+const rerankPrompt = `判断文档与搜索查询的相关程度，给出 0 到 10 的分数：10 表示文档直接回答了查询，0 表示完全无关。只输出 JSON。
+
+查询：%s
+
+文档：
+%s`
+
+// OllamaReranker scores each query-document pair with a local model. Dedicated reranker
+// models (e.g. Qwen3-Reranker or bge-reranker builds for Ollama) work best.
+type OllamaReranker struct {
+	adapter *OllamaAdapter
+}
+
+// NewOllamaReranker creates a reranker using a local model
+func NewOllamaReranker(adapter *OllamaAdapter) *OllamaReranker {
+	return &OllamaReranker{adapter: adapter}
+}
+
+func (o *OllamaReranker) Name() string { return o.adapter.Name() }
+
+// Rerank scores the documents concurrently. Documents that fail to score rank last,
+// in their original order; it only fails if no document could be scored.
+func (o *OllamaReranker) Rerank(query string, documents []string) ([]RerankResult, error) {
+	results := make([]RerankResult, len(documents))
+	errs := make([]error, len(documents))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, ollamaRerankParallelism)
+	for i, doc := range documents {
+		wg.Add(1)
+		go func(i int, doc string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = RerankResult{Index: i, Score: -1}
+			content, _, err := o.adapter.GenerateStructured(fmt.Sprintf(rerankPrompt, query, doc), rerankScoreSchema, &GenerateOptions{Temperature: 0, MaxTokens: 32})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			var judgement struct {
+				Score float64 `json:"score"`
+			}
+			if err := json.Unmarshal([]byte(content), &judgement); err != nil {
+				errs[i] = err
+				return
+			}
+			results[i].Score = judgement.Score
+		}(i, doc)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 && failed == len(documents) {
+		return nil, fmt.Errorf("rerank failed for all %d documents: %w", failed, errs[0])
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
@@ -0,0 +1,200 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Retry defaults used when no policy is configured
+const (
+	defaultMaxRetries     = 2
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// APIError is returned by adapters when a provider responds with a non-200 status
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s returned status %d", e.Provider, e.StatusCode)
+	}
+	return fmt.Sprintf("%s returned status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// ErrDeadlineExceeded is returned when a single attempt runs past the route's timeout
+var ErrDeadlineExceeded = errors.New("llm request deadline exceeded")
+
+// RetryPolicy controls how often one model is retried before the router falls back
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration // Per attempt; 0 relies on the adapter's HTTP timeout
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// retryPolicyFromConfig converts a configured policy, filling unset backoffs with defaults
+func retryPolicyFromConfig(cfg config.RetryPolicy) RetryPolicy {
+	policy := RetryPolicy{
+		MaxRetries:     cfg.MaxRetries,
+		InitialBackoff: time.Duration(cfg.InitialBackoff) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoff) * time.Millisecond,
+		Timeout:        time.Duration(cfg.Timeout) * time.Second,
+	}
+	if policy.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultInitialBackoff
+	}
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	return policy
+}
+
+// backoff returns the delay before the given retry (1-based), with up to 20% jitter
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff << (retry - 1)
+	if delay <= 0 || delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay - time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// IsRetryable reports whether an error is transient: rate limits, server errors or network failures.
+// Deadline overruns and client timeouts are not retried; the router moves on to the next model instead.
+// Requests cancelled through GenerateOptions.Context are not retried either.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout, 529: // 529: Anthropic overloaded
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, ErrDeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && !netErr.Timeout()
+}
+
+// SetRetryPolicy sets the default retry policy and per-model overrides
+func (r *Router) SetRetryPolicy(defaultPolicy RetryPolicy, models map[string]RetryPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retryDefault = defaultPolicy
+	r.retryModels = models
+}
+
+// retryPolicy returns the policy for a model
+func (r *Router) retryPolicy(modelName string) RetryPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if policy, ok := r.retryModels[modelName]; ok {
+		return policy
+	}
+	return r.retryDefault
+}
+
+// callWithRetry runs one adapter call under the model's deadline, retrying transient
+// errors with exponential backoff. It returns the number of retries made.
+func callWithRetry[T any](r *Router, modelName string, call func() (T, error)) (T, int, error) {
+	policy := r.retryPolicy(modelName)
+
+	var value T
+	var err error
+	for attempt := 0; ; attempt++ {
+		// Hold a concurrency slot per attempt, not across backoff sleeps. The slot is released
+		// when the call returns, even if the deadline already gave up on it.
+		release, slotErr := r.acquireSlot(modelName)
+		if slotErr != nil {
+			return value, attempt, slotErr
+		}
+		value, err = callWithDeadline(policy.Timeout, func() (T, error) {
+			defer release()
+			return call()
+		})
+		if err == nil || attempt >= policy.MaxRetries || !IsRetryable(err) {
+			return value, attempt, err
+		}
+
+		delay := policy.backoff(attempt + 1)
+		log.Printf("retrying %s in %v (retry %d/%d): %v", modelName, delay, attempt+1, policy.MaxRetries, err)
+		time.Sleep(delay)
+	}
+}
+
+// callWithDeadline runs call, giving up after timeout. Adapters only stop early when the
+// caller cancels GenerateOptions.Context, so an abandoned call finishes in the background
+// and is bounded by its HTTP client timeout.
+func callWithDeadline[T any](timeout time.Duration, call func() (T, error)) (T, error) {
+	if timeout <= 0 {
+		return call()
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := call()
+		done <- outcome{value, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-timer.C:
+		var zero T
+		return zero, fmt.Errorf("%w after %v", ErrDeadlineExceeded, timeout)
+	}
+}
+
+// generation is the output of one text-generating adapter call
+type generation struct {
+	content string
+	usage   Usage
+}
+
+// generateWithRetry wraps callWithRetry for adapter methods returning content and usage,
+// recording the call in the audit log under task with input as its prompt
+func (r *Router) generateWithRetry(task, modelName, input string, call func() (string, Usage, error)) (string, Usage, int, error) {
+	startedAt := time.Now()
+	out, retries, err := callWithRetry(r, modelName, func() (generation, error) {
+		content, usage, err := call()
+		return generation{content, usage}, err
+	})
+	r.recordCall(task, modelName, input, out.content, out.usage, startedAt, retries, false, err)
+	return out.content, out.usage, retries, err
+}
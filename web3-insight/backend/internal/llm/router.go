@@ -1,25 +1,39 @@
 package llm
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/user/web3-insight/internal/config"
 )
 
+// ErrModelNotFound is returned when a request names a model that is not registered
+var ErrModelNotFound = errors.New("model not found")
+
 // Router manages LLM adapters and routes tasks to appropriate models
 type Router struct {
 	adapters map[string]LLMAdapter
 	routes   map[string][]string // task -> [primary, fallback...]
+	cache    *ResponseCache
+	budget   *BudgetTracker
 	mu       sync.RWMutex
+
+	callLogger CallLogger
+
+	retryDefault RetryPolicy
+	retryModels  map[string]RetryPolicy
 }
 
 // NewRouter creates a new LLM router
 func NewRouter() *Router {
 	return &Router{
-		adapters: make(map[string]LLMAdapter),
-		routes:   make(map[string][]string),
+		adapters:     make(map[string]LLMAdapter),
+		routes:       make(map[string][]string),
+		retryDefault: DefaultRetryPolicy(),
 	}
 }
 
@@ -46,9 +60,43 @@ func NewRouterFromConfig(cfg *config.LLMConfig) *Router {
 		r.RegisterAdapter("gpt-4o-mini", NewOpenAIAdapter(cfg.OpenAI.APIKey, "gpt-4o-mini"))
 	}
 
+	// Register Bedrock adapters if enabled
+	if cfg.Bedrock.Enabled && cfg.Bedrock.AccessKeyID != "" && cfg.Bedrock.DefaultModel != "" {
+		b := cfg.Bedrock
+		r.RegisterAdapter(b.DefaultModel, NewBedrockAdapter(b.Region, b.AccessKeyID, b.SecretAccessKey, b.SessionToken, b.DefaultModel))
+		for _, model := range b.Models {
+			r.RegisterAdapter(model, NewBedrockAdapter(b.Region, b.AccessKeyID, b.SecretAccessKey, b.SessionToken, model))
+		}
+	}
+
+	// Register generic OpenAI-compatible adapters (vLLM, LM Studio, OpenRouter, ...)
+	for _, oc := range cfg.OpenAICompatible {
+		if oc.BaseURL == "" || oc.Model == "" {
+			continue
+		}
+		adapter := NewOpenAICompatibleAdapter(oc.Name, oc.BaseURL, oc.APIKey, oc.Model).
+			WithType(oc.Type).
+			WithHeaders(oc.Headers).
+			WithPricing(oc.InputPrice, oc.OutputPrice)
+		r.RegisterAdapter(adapter.Name(), adapter)
+	}
+
 	// Set up default routes based on config
 	r.setupDefaultRoutes(cfg)
 
+	// Retry policies; an unset default keeps DefaultRetryPolicy
+	retryDefault := DefaultRetryPolicy()
+	if cfg.Retry.Default != (config.RetryPolicy{}) {
+		retryDefault = retryPolicyFromConfig(cfg.Retry.Default)
+	}
+	retryModels := make(map[string]RetryPolicy, len(cfg.Retry.Models))
+	for name, policy := range cfg.Retry.Models {
+		retryModels[name] = retryPolicyFromConfig(policy)
+	}
+	r.SetRetryPolicy(retryDefault, retryModels)
+
+	r.SetConcurrencyLimits(concurrencyLimitsFromConfig(cfg.Concurrency))
+
 	return r
 }
 
@@ -65,6 +113,9 @@ func (r *Router) setupDefaultRoutes(cfg *config.LLMConfig) {
 	if cfg.OpenAI.Enabled {
 		contentRoute = append(contentRoute, cfg.OpenAI.DefaultModel)
 	}
+	if cfg.Bedrock.Enabled && cfg.Bedrock.DefaultModel != "" {
+		contentRoute = append(contentRoute, cfg.Bedrock.DefaultModel)
+	}
 	if len(contentRoute) > 0 {
 		r.SetRoute(TaskContentGeneration, contentRoute)
 	}
@@ -80,6 +131,9 @@ func (r *Router) setupDefaultRoutes(cfg *config.LLMConfig) {
 	if cfg.OpenAI.Enabled {
 		summaryRoute = append(summaryRoute, "gpt-4o-mini")
 	}
+	if cfg.Bedrock.Enabled && cfg.Bedrock.DefaultModel != "" {
+		summaryRoute = append(summaryRoute, cfg.Bedrock.DefaultModel)
+	}
 	if len(summaryRoute) > 0 {
 		r.SetRoute(TaskSummarization, summaryRoute)
 	}
@@ -100,10 +154,31 @@ func (r *Router) setupDefaultRoutes(cfg *config.LLMConfig) {
 	if cfg.OpenAI.Enabled {
 		chatRoute = append(chatRoute, cfg.OpenAI.DefaultModel)
 	}
+	if cfg.Bedrock.Enabled && cfg.Bedrock.DefaultModel != "" {
+		chatRoute = append(chatRoute, cfg.Bedrock.DefaultModel)
+	}
 	if len(chatRoute) > 0 {
 		r.SetRoute(TaskChat, chatRoute)
 	}
 
+	// Chat follow-up suggestions: short and latency-sensitive, so small cloud models first
+	followUpRoute := []string{}
+	if cfg.Claude.Enabled {
+		followUpRoute = append(followUpRoute, "claude-haiku")
+	}
+	if cfg.OpenAI.Enabled {
+		followUpRoute = append(followUpRoute, "gpt-4o-mini")
+	}
+	if cfg.DefaultLocal != "" {
+		followUpRoute = append(followUpRoute, cfg.DefaultLocal)
+	}
+	if cfg.Bedrock.Enabled && cfg.Bedrock.DefaultModel != "" {
+		followUpRoute = append(followUpRoute, cfg.Bedrock.DefaultModel)
+	}
+	if len(followUpRoute) > 0 {
+		r.SetRoute(TaskChatFollowUp, followUpRoute)
+	}
+
 	// Translation: prefer models good at Chinese
 	translationRoute := []string{}
 	if cfg.DefaultLocal != "" {
@@ -115,9 +190,109 @@ func (r *Router) setupDefaultRoutes(cfg *config.LLMConfig) {
 	if cfg.OpenAI.Enabled {
 		translationRoute = append(translationRoute, "gpt-4o-mini")
 	}
+	if cfg.Bedrock.Enabled && cfg.Bedrock.DefaultModel != "" {
+		translationRoute = append(translationRoute, cfg.Bedrock.DefaultModel)
+	}
 	if len(translationRoute) > 0 {
 		r.SetRoute(TaskTranslation, translationRoute)
 	}
+
+	// Relevance scoring runs on every incoming item, so it only uses the cheapest models
+	relevanceRoute := []string{}
+	if cfg.DefaultLocal != "" {
+		relevanceRoute = append(relevanceRoute, cfg.DefaultLocal)
+	}
+	if cfg.Claude.Enabled {
+		relevanceRoute = append(relevanceRoute, "claude-haiku")
+	}
+	if cfg.OpenAI.Enabled {
+		relevanceRoute = append(relevanceRoute, "gpt-4o-mini")
+	}
+	if len(relevanceRoute) > 0 {
+		r.SetRoute(TaskRelevance, relevanceRoute)
+	}
+
+	// OpenAI-compatible adapters join the routes they opt into as fallbacks
+	for _, oc := range cfg.OpenAICompatible {
+		if oc.BaseURL == "" || oc.Model == "" {
+			continue
+		}
+		name := oc.Name
+		if name == "" {
+			name = oc.Model
+		}
+		for _, task := range oc.Tasks {
+			r.routes[task] = append(r.routes[task], name)
+		}
+	}
+}
+
+// SetCache enables response caching (nil disables it)
+func (r *Router) SetCache(cache *ResponseCache) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = cache
+}
+
+// SetBudget enables spend limits (nil disables them)
+func (r *Router) SetBudget(budget *BudgetTracker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budget = budget
+}
+
+// Budget returns the budget tracker, or nil if budgets are disabled
+func (r *Router) Budget() *BudgetTracker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.budget
+}
+
+// budgetedModels drops cloud models whose provider or task budget is exhausted.
+// When anything was dropped, the remaining registered local models are added as fallbacks.
+func (r *Router) budgetedModels(task string, models []string) []string {
+	r.mu.RLock()
+	budget := r.budget
+	r.mu.RUnlock()
+	if budget == nil {
+		return models
+	}
+
+	allowed := make([]string, 0, len(models))
+	blocked := false
+	for _, modelName := range models {
+		adapter, ok := r.adapters[modelName]
+		if ok && adapter.Type() != "local" {
+			if exhausted, reason := budget.Exhausted(ProviderOf(adapter), task); exhausted {
+				log.Printf("skipping %s for %s: %s exhausted", modelName, task, reason)
+				blocked = true
+				continue
+			}
+		}
+		allowed = append(allowed, modelName)
+	}
+	if !blocked {
+		return allowed
+	}
+
+	var locals []string
+	for name, adapter := range r.ListAdapters() {
+		if adapter.Type() == "local" && !containsModel(allowed, name) {
+			locals = append(locals, name)
+		}
+	}
+	sort.Strings(locals)
+	return append(allowed, locals...)
+}
+
+// containsModel reports whether models contains name
+func containsModel(models []string, name string) bool {
+	for _, m := range models {
+		if m == name {
+			return true
+		}
+	}
+	return false
 }
 
 // RegisterAdapter registers an LLM adapter
@@ -167,17 +342,86 @@ func (r *Router) ListAvailableAdapters() []string {
 	return result
 }
 
+// ModelInfo describes a registered model for model pickers
+type ModelInfo struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"` // "local" or "cloud"
+	Provider  string `json:"provider"`
+	CostClass string `json:"costClass"` // free, low, medium or high
+}
+
+// Cost class bounds in USD for one million input plus one million output tokens
+const (
+	lowCostMaxUSD    = 5.0
+	mediumCostMaxUSD = 25.0
+)
+
+// ListModels describes the available adapters, sorted by name
+func (r *Router) ListModels() []ModelInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	models := make([]ModelInfo, 0, len(r.adapters))
+	for name, adapter := range r.adapters {
+		if !adapter.IsAvailable() {
+			continue
+		}
+		models = append(models, ModelInfo{
+			Name:      name,
+			Type:      adapter.Type(),
+			Provider:  ProviderOf(adapter),
+			CostClass: costClass(adapter),
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models
+}
+
+// costClass buckets an adapter by its price per million tokens each way
+func costClass(adapter LLMAdapter) string {
+	cost := adapter.EstimateCost(1_000_000, 1_000_000)
+	switch {
+	case cost == 0:
+		return "free"
+	case cost < lowCostMaxUSD:
+		return "low"
+	case cost < mediumCostMaxUSD:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// AvailableModels returns the models routed for a task that are registered, available
+// and within budget, in routing order
+func (r *Router) AvailableModels(task string) []string {
+	r.mu.RLock()
+	models := r.routes[task]
+	r.mu.RUnlock()
+
+	var available []string
+	for _, modelName := range r.budgetedModels(task, models) {
+		if adapter, ok := r.GetAdapter(modelName); ok && adapter.IsAvailable() {
+			available = append(available, modelName)
+		}
+	}
+	return available
+}
+
 // Generate routes a generation request to the appropriate model
-// Returns the result, the model used, and any error
-func (r *Router) Generate(task, prompt string, opts *GenerateOptions) (string, string, error) {
+// Returns the content along with the model used, token usage and estimated cost
+func (r *Router) Generate(task, prompt string, opts *GenerateOptions) (*GenerateResult, error) {
 	r.mu.RLock()
 	models := r.routes[task]
 	r.mu.RUnlock()
+	models = r.budgetedModels(task, models)
 
 	if len(models) == 0 {
-		return "", "", fmt.Errorf("no models configured for task: %s", task)
+		return nil, fmt.Errorf("no models configured for task: %s", task)
 	}
 
+	input := promptText(prompt, opts)
+
+	retries := 0
 	for _, modelName := range models {
 		adapter, ok := r.adapters[modelName]
 		if !ok {
@@ -190,16 +434,32 @@ func (r *Router) Generate(task, prompt string, opts *GenerateOptions) (string, s
 			continue
 		}
 
-		result, err := adapter.Generate(prompt, opts)
+		cacheKey, cacheable := r.cache.key(task, modelName, prompt, opts)
+		if cacheable {
+			if cached, ok := r.cache.get(cacheKey); ok {
+				return &GenerateResult{Content: cached, Model: modelName, Cached: true}, nil
+			}
+		}
+
+		content, usage, n, err := r.generateWithRetry(task, modelName, input, func() (string, Usage, error) {
+			return adapter.Generate(prompt, opts)
+		})
+		retries += n
 		if err != nil {
-			log.Printf("generation failed with %s: %v", modelName, err)
+			log.Printf("generation failed with %s after %d retries: %v", modelName, n, err)
 			continue
 		}
 
-		return result, modelName, nil
+		if cacheable {
+			r.cache.set(cacheKey, content)
+		}
+		result := buildResult(adapter, modelName, input, content, usage)
+		result.Retries = retries
+		r.budget.Record(ProviderOf(adapter), task, result.CostUSD)
+		return result, nil
 	}
 
-	return "", "", fmt.Errorf("all models failed for task: %s", task)
+	return nil, fmt.Errorf("all models failed for task: %s (%d retries)", task, retries)
 }
 
 // GenerateStream routes a streaming generation request
@@ -207,6 +467,7 @@ func (r *Router) GenerateStream(task, prompt string, opts *GenerateOptions) (<-c
 	r.mu.RLock()
 	models := r.routes[task]
 	r.mu.RUnlock()
+	models = r.budgetedModels(task, models)
 
 	if len(models) == 0 {
 		return nil, "", fmt.Errorf("no models configured for task: %s", task)
@@ -222,28 +483,39 @@ func (r *Router) GenerateStream(task, prompt string, opts *GenerateOptions) (<-c
 			continue
 		}
 
+		release, err := r.acquireSlot(modelName)
+		if err != nil {
+			log.Printf("stream generation skipped %s: %v", modelName, err)
+			continue
+		}
+
 		stream, err := adapter.GenerateStream(prompt, opts)
 		if err != nil {
+			release()
 			log.Printf("stream generation failed with %s: %v", modelName, err)
 			continue
 		}
 
-		return stream, modelName, nil
+		input := promptText(prompt, opts)
+		stream = r.budgetStream(task, adapter, input, releaseAfterStream(stream, release))
+		return r.auditStream(task, modelName, input, stream), modelName, nil
 	}
 
 	return nil, "", fmt.Errorf("all models failed for task: %s", task)
 }
 
 // GenerateChat routes a chat request to the appropriate model
-func (r *Router) GenerateChat(task string, messages []Message, opts *GenerateOptions) (string, string, error) {
+func (r *Router) GenerateChat(task string, messages []Message, opts *GenerateOptions) (*GenerateResult, error) {
 	r.mu.RLock()
 	models := r.routes[task]
 	r.mu.RUnlock()
+	models = r.budgetedModels(task, models)
 
 	if len(models) == 0 {
-		return "", "", fmt.Errorf("no models configured for task: %s", task)
+		return nil, fmt.Errorf("no models configured for task: %s", task)
 	}
 
+	retries := 0
 	for _, modelName := range models {
 		adapter, ok := r.adapters[modelName]
 		if !ok {
@@ -254,16 +526,32 @@ func (r *Router) GenerateChat(task string, messages []Message, opts *GenerateOpt
 			continue
 		}
 
-		result, err := adapter.GenerateChat(messages, opts)
+		cacheKey, cacheable := r.cache.key(task, modelName, messages, opts)
+		if cacheable {
+			if cached, ok := r.cache.get(cacheKey); ok {
+				return &GenerateResult{Content: cached, Model: modelName, Cached: true}, nil
+			}
+		}
+
+		content, usage, n, err := r.generateWithRetry(task, modelName, messagesText(messages, opts), func() (string, Usage, error) {
+			return adapter.GenerateChat(messages, opts)
+		})
+		retries += n
 		if err != nil {
-			log.Printf("chat generation failed with %s: %v", modelName, err)
+			log.Printf("chat generation failed with %s after %d retries: %v", modelName, n, err)
 			continue
 		}
 
-		return result, modelName, nil
+		if cacheable {
+			r.cache.set(cacheKey, content)
+		}
+		result := buildResult(adapter, modelName, messagesText(messages, opts), content, usage)
+		result.Retries = retries
+		r.budget.Record(ProviderOf(adapter), task, result.CostUSD)
+		return result, nil
 	}
 
-	return "", "", fmt.Errorf("all models failed for task: %s", task)
+	return nil, fmt.Errorf("all models failed for task: %s (%d retries)", task, retries)
 }
 
 // GenerateChatStream routes a streaming chat request
@@ -271,6 +559,7 @@ func (r *Router) GenerateChatStream(task string, messages []Message, opts *Gener
 	r.mu.RLock()
 	models := r.routes[task]
 	r.mu.RUnlock()
+	models = r.budgetedModels(task, models)
 
 	if len(models) == 0 {
 		return nil, "", fmt.Errorf("no models configured for task: %s", task)
@@ -286,30 +575,137 @@ func (r *Router) GenerateChatStream(task string, messages []Message, opts *Gener
 			continue
 		}
 
+		release, err := r.acquireSlot(modelName)
+		if err != nil {
+			log.Printf("stream chat generation skipped %s: %v", modelName, err)
+			continue
+		}
+
 		stream, err := adapter.GenerateChatStream(messages, opts)
 		if err != nil {
+			release()
 			log.Printf("stream chat generation failed with %s: %v", modelName, err)
 			continue
 		}
 
-		return stream, modelName, nil
+		input := messagesText(messages, opts)
+		stream = r.budgetStream(task, adapter, input, releaseAfterStream(stream, release))
+		return r.auditStream(task, modelName, input, stream), modelName, nil
 	}
 
 	return nil, "", fmt.Errorf("all models failed for task: %s", task)
 }
 
-// GenerateWithModel generates using a specific model (bypasses routing)
-func (r *Router) GenerateWithModel(modelName, prompt string, opts *GenerateOptions) (string, error) {
-	adapter, ok := r.adapters[modelName]
-	if !ok {
-		return "", fmt.Errorf("model not found: %s", modelName)
+// GenerateWithModel generates using a specific model, bypassing routing.
+// The model still counts against the task's budget and is audited under the task.
+func (r *Router) GenerateWithModel(task, modelName, prompt string, opts *GenerateOptions) (*GenerateResult, error) {
+	adapter, err := r.checkModel(task, modelName)
+	if err != nil {
+		return nil, err
 	}
 
+	input := promptText(prompt, opts)
+	content, usage, retries, err := r.generateWithRetry(task, modelName, input, func() (string, Usage, error) {
+		return adapter.Generate(prompt, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := buildResult(adapter, modelName, input, content, usage)
+	result.Retries = retries
+	r.budget.Record(ProviderOf(adapter), task, result.CostUSD)
+	return result, nil
+}
+
+// GenerateStreamWithModel streams a generation from a specific model, bypassing routing.
+// The model still counts against the task's budget and is audited under the task.
+func (r *Router) GenerateStreamWithModel(task, modelName, prompt string, opts *GenerateOptions) (<-chan StreamChunk, error) {
+	adapter, release, err := r.selectModel(task, modelName)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := adapter.GenerateStream(prompt, opts)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	input := promptText(prompt, opts)
+	stream = r.budgetStream(task, adapter, input, releaseAfterStream(stream, release))
+	return r.auditStream(task, modelName, input, stream), nil
+}
+
+// GenerateChatStreamWithModel streams a chat generation from a specific model, bypassing routing.
+// The model still counts against the task's budget and is audited under the task.
+func (r *Router) GenerateChatStreamWithModel(task, modelName string, messages []Message, opts *GenerateOptions) (<-chan StreamChunk, error) {
+	adapter, release, err := r.selectModel(task, modelName)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := adapter.GenerateChatStream(messages, opts)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	input := messagesText(messages, opts)
+	stream = r.budgetStream(task, adapter, input, releaseAfterStream(stream, release))
+	return r.auditStream(task, modelName, input, stream), nil
+}
+
+// selectModel checks that a model can take a request for a task and acquires its concurrency slot
+func (r *Router) selectModel(task, modelName string) (LLMAdapter, func(), error) {
+	adapter, err := r.checkModel(task, modelName)
+	if err != nil {
+		return nil, nil, err
+	}
+	release, err := r.acquireSlot(modelName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return adapter, release, nil
+}
+
+// checkModel returns the adapter of a model that is available and within the task's budget
+func (r *Router) checkModel(task, modelName string) (LLMAdapter, error) {
+	adapter, ok := r.GetAdapter(modelName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrModelNotFound, modelName)
+	}
 	if !adapter.IsAvailable() {
-		return "", fmt.Errorf("model not available: %s", modelName)
+		return nil, fmt.Errorf("model not available: %s", modelName)
+	}
+	r.mu.RLock()
+	budget := r.budget
+	r.mu.RUnlock()
+	if budget != nil && adapter.Type() != "local" {
+		if exhausted, reason := budget.Exhausted(ProviderOf(adapter), task); exhausted {
+			return nil, fmt.Errorf("model %s is over budget for task %s: %s exhausted", modelName, task, reason)
+		}
+	}
+	return adapter, nil
+}
+
+// budgetStream relays a stream and, once it closes, records the cost of the tokens sent and
+// streamed back against the budget. Streams report no usage, so the tokens are estimated.
+func (r *Router) budgetStream(task string, adapter LLMAdapter, input string, stream <-chan StreamChunk) <-chan StreamChunk {
+	r.mu.RLock()
+	budget := r.budget
+	r.mu.RUnlock()
+	if budget == nil {
+		return stream
 	}
 
-	return adapter.Generate(prompt, opts)
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		var content strings.Builder
+		for chunk := range stream {
+			content.WriteString(chunk.Content)
+			out <- chunk
+		}
+		budget.Record(ProviderOf(adapter), task, adapter.EstimateCost(EstimateTokens(input), EstimateTokens(content.String())))
+	}()
+	return out
 }
 
 // EstimateCost estimates the cost for a specific model
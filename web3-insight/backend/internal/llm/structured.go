@@ -0,0 +1,199 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// maxStructuredAttempts is how many times one model is asked before trying the next
+const maxStructuredAttempts = 3
+
+var jsonFencePattern = regexp.MustCompile("(?s)```(?:json)?\\s*")
+
+// JSONSchema is a named JSON schema that structured output must satisfy
+type JSONSchema struct {
+	Name     string
+	Schema   map[string]interface{}
+	compiled *jsonschema.Schema
+}
+
+// NewJSONSchema compiles a schema; name must be a valid identifier (it becomes a tool/format name)
+func NewJSONSchema(name string, schema map[string]interface{}) (*JSONSchema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(name+".json", bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	compiled, err := compiler.Compile(name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return &JSONSchema{Name: name, Schema: schema, compiled: compiled}, nil
+}
+
+// MustJSONSchema is like NewJSONSchema but panics on error; for package-level schemas
+func MustJSONSchema(name string, schema map[string]interface{}) *JSONSchema {
+	s, err := NewJSONSchema(name, schema)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate checks that data is JSON matching the schema
+func (s *JSONSchema) Validate(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return s.compiled.Validate(value)
+}
+
+// StructuredAdapter is implemented by adapters with a provider-native JSON output mode
+type StructuredAdapter interface {
+	LLMAdapter
+	// GenerateStructured asks for output conforming to schema and returns the raw JSON
+	GenerateStructured(prompt string, schema *JSONSchema, opts *GenerateOptions) (string, Usage, error)
+}
+
+// GenerateStructured routes a request whose output must be JSON matching schema.
+// Adapters with a native JSON mode use it; others are prompted with the schema.
+// Invalid output is retried with the validation error fed back to the model.
+// The returned result's Content is the validated JSON document.
+func (r *Router) GenerateStructured(task, prompt string, schema *JSONSchema, opts *GenerateOptions) (*GenerateResult, error) {
+	r.mu.RLock()
+	models := r.routes[task]
+	r.mu.RUnlock()
+	models = r.budgetedModels(task, models)
+
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models configured for task: %s", task)
+	}
+
+	cacheInput := struct {
+		Prompt string                 `json:"prompt"`
+		Schema map[string]interface{} `json:"schema"`
+	}{prompt, schema.Schema}
+
+	var lastErr error
+	retries := 0
+	for _, modelName := range models {
+		adapter, ok := r.adapters[modelName]
+		if !ok || !adapter.IsAvailable() {
+			continue
+		}
+
+		cacheKey, cacheable := r.cache.key(task, modelName, cacheInput, opts)
+		if cacheable {
+			if cached, ok := r.cache.get(cacheKey); ok && schema.Validate([]byte(cached)) == nil {
+				return &GenerateResult{Content: cached, Model: modelName, Cached: true}, nil
+			}
+		}
+
+		var total Usage
+		attemptPrompt := prompt
+		for attempt := 1; attempt <= maxStructuredAttempts; attempt++ {
+			currentPrompt := attemptPrompt
+			content, usage, n, err := r.generateWithRetry(task, modelName, promptText(currentPrompt, opts), func() (string, Usage, error) {
+				return r.generateJSON(adapter, currentPrompt, schema, opts)
+			})
+			retries += n
+			total.InputTokens += usage.InputTokens
+			total.OutputTokens += usage.OutputTokens
+			if err != nil {
+				lastErr = err
+				log.Printf("structured generation failed with %s: %v", modelName, err)
+				break
+			}
+
+			document := ExtractJSON(content)
+			if err := schema.Validate([]byte(document)); err != nil {
+				lastErr = fmt.Errorf("output does not match schema %s: %w", schema.Name, err)
+				log.Printf("structured output from %s invalid (attempt %d/%d): %v", modelName, attempt, maxStructuredAttempts, err)
+				attemptPrompt = prompt + fmt.Sprintf("\n\n上一次输出不符合要求的 JSON 格式：%v\n请只输出符合 schema 的 JSON。", err)
+				continue
+			}
+
+			if cacheable {
+				r.cache.set(cacheKey, document)
+			}
+			result := buildResult(adapter, modelName, prompt, document, total)
+			result.Retries = retries
+			r.budget.Record(ProviderOf(adapter), task, result.CostUSD)
+			return result, nil
+		}
+
+		// Charge for failed attempts too
+		if total.InputTokens > 0 || total.OutputTokens > 0 {
+			r.budget.Record(ProviderOf(adapter), task, adapter.EstimateCost(total.InputTokens, total.OutputTokens))
+		}
+	}
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("structured generation failed for task %s: %w", task, lastErr)
+	}
+	return nil, fmt.Errorf("all models failed for task: %s", task)
+}
+
+// generateJSON asks one adapter for JSON, using its native mode when it has one
+func (r *Router) generateJSON(adapter LLMAdapter, prompt string, schema *JSONSchema, opts *GenerateOptions) (string, Usage, error) {
+	if structured, ok := adapter.(StructuredAdapter); ok {
+		return structured.GenerateStructured(prompt, schema, opts)
+	}
+
+	schemaJSON, _ := json.MarshalIndent(schema.Schema, "", "  ")
+	return adapter.Generate(prompt+"\n\n请只输出符合以下 JSON Schema 的 JSON，不要包含其他文字：\n"+string(schemaJSON), opts)
+}
+
+// ExtractJSON strips markdown fences and surrounding prose from a JSON response
+func ExtractJSON(content string) string {
+	content = strings.TrimSpace(jsonFencePattern.ReplaceAllString(content, ""))
+
+	start := strings.IndexAny(content, "{[")
+	if start == -1 {
+		return content
+	}
+	closing := "}"
+	if content[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(content, closing)
+	if end < start {
+		return content
+	}
+	return content[start : end+1]
+}
+
+// GenerateJSONWithModel makes a single JSON-mode call to a specific model (bypasses routing).
+// The extracted document is returned unvalidated so callers can measure raw schema adherence.
+func (r *Router) GenerateJSONWithModel(modelName, prompt string, schema *JSONSchema, opts *GenerateOptions) (*GenerateResult, error) {
+	adapter, ok := r.GetAdapter(modelName)
+	if !ok {
+		return nil, fmt.Errorf("model not found: %s", modelName)
+	}
+	if !adapter.IsAvailable() {
+		return nil, fmt.Errorf("model not available: %s", modelName)
+	}
+
+	content, usage, retries, err := r.generateWithRetry("", modelName, promptText(prompt, opts), func() (string, Usage, error) {
+		return r.generateJSON(adapter, prompt, schema, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := buildResult(adapter, modelName, prompt, ExtractJSON(content), usage)
+	result.Retries = retries
+	return result, nil
+}
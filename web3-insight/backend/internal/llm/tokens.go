@@ -0,0 +1,208 @@
+package llm
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// defaultContextWindow is assumed for models not in contextWindows, matching Ollama's default num_ctx
+const defaultContextWindow = 4096
+
+// contextWindows maps model name prefixes to context sizes in tokens; the longest matching prefix wins
+var contextWindows = map[string]int{
+	"gpt-4o":                 128000,
+	"gpt-4.1":                1047576,
+	"gpt-4-turbo":            128000,
+	"gpt-4":                  8192,
+	"gpt-3.5-turbo":          16385,
+	"o1":                     200000,
+	"o3":                     200000,
+	"claude":                 200000,
+	"anthropic.claude":       200000,
+	"text-embedding-3":       8191,
+	"text-embedding-ada-002": 8191,
+	"nomic-embed-text":       8192,
+	"mxbai-embed-large":      512,
+	"bge-m3":                 8192,
+}
+
+func init() {
+	// Use the encodings bundled with the loader instead of downloading them at runtime
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+// Tokenizer counts and truncates text in one model's tokens
+type Tokenizer interface {
+	Count(text string) int
+	// Truncate returns the longest prefix of text within maxTokens, cut on a rune boundary
+	Truncate(text string, maxTokens int) string
+}
+
+// ContextWindow returns the context size in tokens of a model
+func ContextWindow(modelName string) int {
+	best, window := "", defaultContextWindow
+	for prefix, size := range contextWindows {
+		if strings.HasPrefix(modelName, prefix) && len(prefix) > len(best) {
+			best, window = prefix, size
+		}
+	}
+	return window
+}
+
+// TaskContextWindow returns the smallest context window among the models routed for a task,
+// so prompts sized with it fit whichever model the router falls back to
+func (r *Router) TaskContextWindow(task string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	window := 0
+	for _, modelName := range r.routes[task] {
+		if size := ContextWindow(modelName); window == 0 || size < window {
+			window = size
+		}
+	}
+	if window == 0 {
+		return defaultContextWindow
+	}
+	return window
+}
+
+// PrimaryModel returns the first model routed for a task, whose tokenizer prompts are measured with
+func (r *Router) PrimaryModel(task string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if models := r.routes[task]; len(models) > 0 {
+		return models[0]
+	}
+	return ""
+}
+
+var (
+	encodingsMu sync.Mutex
+	encodings   = make(map[string]*tiktoken.Tiktoken)
+)
+
+// TokenizerFor returns the tokenizer of a model: its tiktoken encoding for OpenAI models,
+// otherwise a conservative heuristic that counts CJK characters individually
+func TokenizerFor(modelName string) Tokenizer {
+	encodingName, ok := tiktoken.MODEL_TO_ENCODING[modelName]
+	if !ok {
+		for prefix, name := range tiktoken.MODEL_PREFIX_TO_ENCODING {
+			if strings.HasPrefix(modelName, prefix) {
+				encodingName, ok = name, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return heuristicTokenizer{}
+	}
+
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	encoding, cached := encodings[encodingName]
+	if !cached {
+		var err error
+		if encoding, err = tiktoken.GetEncoding(encodingName); err != nil {
+			return heuristicTokenizer{}
+		}
+		encodings[encodingName] = encoding
+	}
+	return tiktokenTokenizer{encoding}
+}
+
+// CountTokens counts the tokens of text for a model
+func CountTokens(modelName, text string) int {
+	return TokenizerFor(modelName).Count(text)
+}
+
+// TruncateToTokens cuts text to at most maxTokens of a model's tokens, preferring to end
+// at a paragraph or line break. It reports whether anything was cut.
+func TruncateToTokens(modelName, text string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 {
+		return "", text != ""
+	}
+
+	truncated := TokenizerFor(modelName).Truncate(text, maxTokens)
+	if len(truncated) == len(text) {
+		return text, false
+	}
+	return cutAtBreak(truncated), true
+}
+
+// cutAtBreak drops a trailing partial paragraph or line when that keeps most of the text
+func cutAtBreak(text string) string {
+	minKeep := len(text) * 4 / 5
+	for _, sep := range []string{"\n\n", "\n"} {
+		if i := strings.LastIndex(text, sep); i >= minKeep {
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// tiktokenTokenizer counts with an exact BPE encoding
+type tiktokenTokenizer struct {
+	encoding *tiktoken.Tiktoken
+}
+
+func (t tiktokenTokenizer) Count(text string) int {
+	return len(t.encoding.EncodeOrdinary(text))
+}
+
+func (t tiktokenTokenizer) Truncate(text string, maxTokens int) string {
+	tokens := t.encoding.EncodeOrdinary(text)
+	if len(tokens) <= maxTokens {
+		return text
+	}
+
+	// A CJK character can span several tokens, so the prefix may end mid-rune
+	prefix := t.encoding.Decode(tokens[:maxTokens])
+	for len(prefix) > 0 && !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix
+}
+
+// heuristicTokenizer estimates tokens in quarter-token units: CJK characters cost 1.5 tokens,
+// other letters and spaces a quarter, punctuation a whole token. It errs on the high side.
+type heuristicTokenizer struct{}
+
+func (heuristicTokenizer) Count(text string) int {
+	quarters := 0
+	for _, r := range text {
+		quarters += runeQuarters(r)
+	}
+	return (quarters + 3) / 4
+}
+
+func (heuristicTokenizer) Truncate(text string, maxTokens int) string {
+	budget := maxTokens * 4
+	quarters := 0
+	for i, r := range text {
+		quarters += runeQuarters(r)
+		if quarters > budget {
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// runeQuarters returns the estimated cost of one rune in quarter tokens
+func runeQuarters(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return 6
+	case r >= 0x3000 && r <= 0x303F, r >= 0xFF00 && r <= 0xFFEF: // CJK and fullwidth punctuation
+		return 4
+	case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsSpace(r):
+		return 1
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Tool describes a function the model may call
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"` // JSON schema of the arguments object
+}
+
+// ToolCall is a tool invocation requested by the model
+type ToolCall struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// Tool choice modes
+const (
+	ToolChoiceAuto     = "auto"
+	ToolChoiceRequired = "required"
+	ToolChoiceNone     = "none"
+)
+
+// Finish reasons reported in GenerateResult
+const (
+	FinishReasonStop     = "stop"
+	FinishReasonToolCall = "tool_calls"
+	FinishReasonLength   = "length"
+)
+
+// ToolAdapter is implemented by adapters whose provider supports native tool calling
+type ToolAdapter interface {
+	LLMAdapter
+	// GenerateChatWithTools runs one chat turn with opts.Tools available.
+	// The result carries either text content, tool calls, or both.
+	GenerateChatWithTools(messages []Message, opts *GenerateOptions) (*GenerateResult, error)
+}
+
+// toolCallText renders a tool-calling result's content and calls for the audit log
+func toolCallText(result *GenerateResult) string {
+	text := result.Content
+	for _, call := range result.ToolCalls {
+		text += fmt.Sprintf("\n[tool call %s] %s", call.Name, string(call.Arguments))
+	}
+	return text
+}
+
+// ToolResultMessage builds the message that returns a tool's output to the model
+func ToolResultMessage(call ToolCall, output string) Message {
+	return Message{Role: "tool", Content: output, ToolCallID: call.ID}
+}
+
+// GenerateWithTools routes one tool-calling chat turn to the first available model
+// that supports tools. The caller executes any returned ToolCalls, appends the
+// assistant message and ToolResultMessage replies, and calls again.
+func (r *Router) GenerateWithTools(task string, messages []Message, opts *GenerateOptions) (*GenerateResult, error) {
+	if opts == nil || len(opts.Tools) == 0 {
+		return nil, fmt.Errorf("no tools provided")
+	}
+
+	r.mu.RLock()
+	models := r.routes[task]
+	r.mu.RUnlock()
+	models = r.budgetedModels(task, models)
+
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models configured for task: %s", task)
+	}
+
+	retries := 0
+	for _, modelName := range models {
+		adapter, ok := r.adapters[modelName]
+		if !ok || !adapter.IsAvailable() {
+			continue
+		}
+
+		toolAdapter, ok := adapter.(ToolAdapter)
+		if !ok {
+			continue
+		}
+
+		startedAt := time.Now()
+		result, n, err := callWithRetry(r, modelName, func() (*GenerateResult, error) {
+			return toolAdapter.GenerateChatWithTools(messages, opts)
+		})
+		retries += n
+		if err != nil {
+			r.recordCall(task, modelName, messagesText(messages, opts), "", Usage{}, startedAt, n, false, err)
+			log.Printf("tool generation failed with %s after %d retries: %v", modelName, n, err)
+			continue
+		}
+		r.recordCall(task, modelName, messagesText(messages, opts), toolCallText(result), result.Usage, startedAt, n, false, nil)
+
+		result.Model = modelName
+		result.Retries = retries
+		if result.Usage.InputTokens == 0 && result.Usage.OutputTokens == 0 {
+			result.Usage = Usage{
+				InputTokens:  EstimateTokens(messagesText(messages, opts)),
+				OutputTokens: EstimateTokens(result.Content),
+			}
+		}
+		result.CostUSD = adapter.EstimateCost(result.Usage.InputTokens, result.Usage.OutputTokens)
+		r.budget.Record(ProviderOf(adapter), task, result.CostUSD)
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("no tool-capable models available for task: %s", task)
+}
@@ -1,12 +1,14 @@
 package llm
 
+import "context"
+
 // LLMAdapter defines the interface for all LLM providers
 type LLMAdapter interface {
 	Name() string
 	Type() string // "local" or "cloud"
-	Generate(prompt string, opts *GenerateOptions) (string, error)
+	Generate(prompt string, opts *GenerateOptions) (string, Usage, error)
 	GenerateStream(prompt string, opts *GenerateOptions) (<-chan StreamChunk, error)
-	GenerateChat(messages []Message, opts *GenerateOptions) (string, error)
+	GenerateChat(messages []Message, opts *GenerateOptions) (string, Usage, error)
 	GenerateChatStream(messages []Message, opts *GenerateOptions) (<-chan StreamChunk, error)
 	IsAvailable() bool
 	EstimateCost(inputTokens, outputTokens int) float64
@@ -19,12 +21,18 @@ type GenerateOptions struct {
 	Temperature  float64
 	TopP         float64
 	StopWords    []string
+	NoCache      bool // Bypass the response cache for this request
+	Tools        []Tool // Tools the model may call (GenerateWithTools only)
+	ToolChoice   string // "auto" (default), "required", "none" or the name of a tool to force
+	Context      context.Context // Cancels the provider request, e.g. when a chat client stops a reply; nil never cancels
 }
 
 // Message represents a chat message
 type Message struct {
 	Role    string `json:"role"`    // "system", "user", "assistant"
 	Content string `json:"content"`
+	ToolCalls  []ToolCall `json:"toolCalls,omitempty"`  // Tool calls requested by an assistant message
+	ToolCallID string     `json:"toolCallId,omitempty"` // For role "tool": the call this message answers
 }
 
 // StreamChunk represents a chunk in streaming response
@@ -48,6 +56,8 @@ type GenerateResult struct {
 	CostUSD   float64
 	Cached    bool
 	FinishReason string
+	ToolCalls []ToolCall // Tool calls requested by the model, if any
+	Retries   int        // Retries on transient errors across all models tried
 }
 
 // Task types for routing
@@ -56,10 +66,20 @@ const (
 	TaskSummarization     = "summarization"
 	TaskClassification    = "classification"
 	TaskChat              = "chat"
+	TaskChatFollowUp      = "chat_follow_up" // Suggested follow-up questions after a chat reply
 	TaskTranslation       = "translation"
+	TaskRelevance         = "relevance" // Relevance and quality scoring of incoming news
 	TaskEmbedding         = "embedding"
 )
 
+// requestContext returns the context provider requests for opts are made with
+func requestContext(opts *GenerateOptions) context.Context {
+	if opts == nil || opts.Context == nil {
+		return context.Background()
+	}
+	return opts.Context
+}
+
 // Default options
 func DefaultGenerateOptions() *GenerateOptions {
 	return &GenerateOptions{
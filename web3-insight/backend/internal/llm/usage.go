@@ -0,0 +1,57 @@
+package llm
+
+import "unicode/utf8"
+
+// EstimateTokens gives a rough token count (about 4 characters per token, 1 per CJK rune)
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return ascii/4 + other
+}
+
+// TotalTokens returns input plus output tokens
+func (u Usage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// buildResult assembles a generation result, estimating usage when the provider didn't report it
+func buildResult(adapter LLMAdapter, modelName, input, content string, usage Usage) *GenerateResult {
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		usage = Usage{
+			InputTokens:  EstimateTokens(input),
+			OutputTokens: EstimateTokens(content),
+		}
+	}
+	return &GenerateResult{
+		Content: content,
+		Model:   modelName,
+		Usage:   usage,
+		CostUSD: adapter.EstimateCost(usage.InputTokens, usage.OutputTokens),
+	}
+}
+
+// promptText joins the system prompt and prompt as sent to the model
+func promptText(prompt string, opts *GenerateOptions) string {
+	if opts == nil {
+		return prompt
+	}
+	return opts.SystemPrompt + "\n" + prompt
+}
+
+// messagesText concatenates chat messages for token estimation
+func messagesText(messages []Message, opts *GenerateOptions) string {
+	var text string
+	if opts != nil {
+		text = opts.SystemPrompt
+	}
+	for _, m := range messages {
+		text += "\n" + m.Content
+	}
+	return text
+}
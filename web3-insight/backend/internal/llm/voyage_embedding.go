@@ -0,0 +1,155 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	voyageEmbeddingsURL = "https://api.voyageai.com/v1/embeddings"
+
+	// DefaultVoyageEmbeddingModel is Voyage's general-purpose multilingual model
+	DefaultVoyageEmbeddingModel = "voyage-3"
+	voyageEmbeddingBatchSize    = 128
+)
+
+// voyageFlexibleDimensions are the models accepting output_dimension
+var voyageFlexibleDimensions = map[string]bool{
+	"voyage-3-large": true,
+	"voyage-3.5":     true,
+	"voyage-code-3":  true,
+}
+
+// VoyageEmbeddingAdapter generates embeddings using the Voyage AI embeddings API
+type VoyageEmbeddingAdapter struct {
+	apiKey     string
+	model      string
+	dimensions int
+	endpoint   string
+	client     *http.Client
+}
+
+// NewVoyageEmbeddingAdapter creates a new Voyage embedding adapter. dimensions is sent as
+// output_dimension for models that support it and otherwise defaults to the model's size.
+func NewVoyageEmbeddingAdapter(apiKey, model string, dimensions int) *VoyageEmbeddingAdapter {
+	if model == "" {
+		model = DefaultVoyageEmbeddingModel
+	}
+	if dimensions <= 0 {
+		dimensions = EmbeddingModelDimensions(model)
+	}
+	return &VoyageEmbeddingAdapter{
+		apiKey:     apiKey,
+		model:      model,
+		dimensions: dimensions,
+		endpoint:   voyageEmbeddingsURL,
+		client: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// WithBaseURL points the adapter at another Voyage-compatible API, e.g. https://example.com/v1
+func (v *VoyageEmbeddingAdapter) WithBaseURL(baseURL string) *VoyageEmbeddingAdapter {
+	if baseURL != "" {
+		v.endpoint = strings.TrimRight(baseURL, "/") + "/embeddings"
+	}
+	return v
+}
+
+func (v *VoyageEmbeddingAdapter) Name() string    { return v.model }
+func (v *VoyageEmbeddingAdapter) Dimensions() int { return v.dimensions }
+
+// GenerateEmbedding generates embedding for a single text
+func (v *VoyageEmbeddingAdapter) GenerateEmbedding(text string) ([]float32, error) {
+	embeddings, err := v.embed([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// GenerateBatchEmbeddings generates embeddings for multiple texts, batching requests
+func (v *VoyageEmbeddingAdapter) GenerateBatchEmbeddings(texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += voyageEmbeddingBatchSize {
+		end := start + voyageEmbeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batch, err := v.embed(texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings for texts %d-%d: %w", start, end-1, err)
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+// IsAvailable reports whether the adapter has credentials; the API itself is not probed
+func (v *VoyageEmbeddingAdapter) IsAvailable() bool {
+	return v.apiKey != ""
+}
+
+// embed sends one embeddings request and returns the vectors in input order
+func (v *VoyageEmbeddingAdapter) embed(texts []string) ([][]float32, error) {
+	payload := map[string]interface{}{
+		"model":      v.model,
+		"input":      texts,
+		"truncation": true,
+	}
+	if voyageFlexibleDimensions[v.model] {
+		payload["output_dimension"] = v.dimensions
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", v.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+v.apiKey)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("voyage embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Detail string `json:"detail"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &APIError{Provider: "voyage", StatusCode: resp.StatusCode, Message: errResp.Detail}
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("voyage returned %d embeddings for %d inputs", len(result.Data), len(texts))
+	}
+
+	sort.Slice(result.Data, func(i, j int) bool { return result.Data[i].Index < result.Data[j].Index })
+	embeddings := make([][]float32, len(result.Data))
+	for i, d := range result.Data {
+		embeddings[i] = d.Embedding
+	}
+	return embeddings, nil
+}
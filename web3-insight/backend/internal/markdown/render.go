@@ -0,0 +1,64 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// articleMarkdown renders article markdown as GFM with footnotes. Soft line breaks between
+// CJK characters are dropped, since Chinese text wraps without spaces; raw HTML is omitted.
+var articleMarkdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+		extension.NewCJK(extension.WithEastAsianLineBreaks(), extension.WithEscapedSpace()),
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// htmlPolicy is what rendered article HTML may contain: user-generated content markup,
+// language-* classes on code for syntax highlighting, heading anchors and task list
+// checkboxes
+var htmlPolicy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_:-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}()
+
+// RenderHTML renders article markdown to sanitized HTML. Headings get the same anchors as
+// ExtractTOC gives them, so table of contents links resolve.
+func RenderHTML(content string) (string, error) {
+	var buf bytes.Buffer
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{seen: make(map[string]int)}))
+	if err := articleMarkdown.Convert([]byte(content), &buf, parser.WithContext(ctx)); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return htmlPolicy.Sanitize(buf.String()), nil
+}
+
+// headingIDs generates heading ids the way anchor does
+type headingIDs struct {
+	seen map[string]int
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	id := anchor(plainTitle(string(value)), ids.seen)
+	if id == "" {
+		id = anchor("heading", ids.seen)
+	}
+	return []byte(id)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.seen[string(value)]++
+}
@@ -0,0 +1,162 @@
+package markdown
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reading speeds for reading time estimates
+const (
+	cjkCharsPerMinute = 400 // Chinese, Japanese and Korean characters
+	wordsPerMinute    = 200 // Words in alphabetic scripts, and code
+	// MaxDescriptionRunes is the longest meta description search engines show in full
+	MaxDescriptionRunes = 150
+)
+
+var (
+	inlineImage  = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?[^)]*\)`)
+	listMarker   = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+	tableDivider = regexp.MustCompile(`^\|?[\s:|-]+\|?$`)
+)
+
+// ReadingMinutes estimates how long reading markdown takes, counting CJK characters one by
+// one and other text by word. Returns at least 1 for markdown with any text.
+func ReadingMinutes(content string) int {
+	cjk, words := 0, 0
+	inWord := false
+	for _, r := range content {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	if cjk+words == 0 {
+		return 0
+	}
+	minutes := float64(cjk)/cjkCharsPerMinute + float64(words)/wordsPerMinute
+	return max(1, int(math.Ceil(minutes)))
+}
+
+// ContentHash identifies markdown by its text, ignoring markdown syntax, case, whitespace and
+// punctuation, so copies differing only in formatting hash the same
+func ContentHash(content string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		line = plainLine(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		for _, r := range strings.ToLower(line) {
+			if unicode.IsLetter(r) || unicode.IsNumber(r) {
+				b.WriteRune(r)
+			}
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// isCJK reports whether r is a Chinese, Japanese or Korean character
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// Description builds a meta description from a summary, or from the opening paragraphs of
+// the markdown without one, cut to MaxDescriptionRunes at a sentence end where possible
+func Description(summary, content string) string {
+	text := strings.Join(strings.Fields(summary), " ")
+	if text == "" {
+		text = openingText(content, MaxDescriptionRunes*2)
+	}
+	return Truncate(text, MaxDescriptionRunes)
+}
+
+// Truncate shortens text to at most maxRunes, at the last sentence end that keeps at least
+// half of it, or else with an ellipsis
+func Truncate(text string, maxRunes int) string {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)[:maxRunes]
+	for i := len(runes) - 1; i >= maxRunes/2; i-- {
+		switch runes[i] {
+		case '。', '！', '？', '.', '!', '?':
+			return string(runes[:i+1])
+		}
+	}
+	return strings.TrimSpace(string(runes[:maxRunes-1])) + "…"
+}
+
+// FirstImage returns the URL of the first linked image in markdown, or "" if it has none.
+// Inlined data: URIs are skipped, since crawlers can't fetch them as preview images.
+func FirstImage(content string) string {
+	for _, m := range inlineImage.FindAllStringSubmatch(content, -1) {
+		if !strings.HasPrefix(strings.ToLower(m[1]), "data:") {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// Images returns the URLs of the images in markdown, in order and without duplicates
+func Images(content string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, m := range inlineImage.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			urls = append(urls, m[1])
+		}
+	}
+	return urls
+}
+
+// openingText returns the plain text of the paragraphs at the start of markdown, skipping
+// headings, code blocks and tables, until it has at least minRunes
+func openingText(content string, minRunes int) string {
+	var parts []string
+	length := 0
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || trimmed == "" || strings.HasPrefix(trimmed, "#") || tableDivider.MatchString(trimmed) || strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		text := plainLine(trimmed)
+		if text == "" {
+			continue
+		}
+		parts = append(parts, text)
+		length += utf8.RuneCountInString(text)
+		if length >= minRunes {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// plainLine strips markdown syntax from one line of text
+func plainLine(line string) string {
+	line = strings.TrimLeft(line, "> ")
+	line = listMarker.ReplaceAllString(line, "")
+	line = inlineImage.ReplaceAllString(line, "")
+	line = inlineLink.ReplaceAllString(line, "$1")
+	line = inlineMarks.Replace(line)
+	return strings.Join(strings.Fields(line), " ")
+}
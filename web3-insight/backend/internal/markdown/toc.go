@@ -0,0 +1,110 @@
+// Package markdown derives structure from article markdown
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TOCEntry is a heading in an article's table of contents
+type TOCEntry struct {
+	Level    int        `json:"level"` // 1-6, from the number of #
+	Title    string     `json:"title"`
+	Anchor   string     `json:"anchor"` // Fragment the rendered heading is linked by, GitHub style
+	Children []TOCEntry `json:"children,omitempty"`
+}
+
+var (
+	inlineLink  = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	inlineMarks = strings.NewReplacer("**", "", "__", "", "`", "", "~~", "")
+)
+
+// ExtractTOC parses the ATX headings of markdown ("## Overview") into a nested table of
+// contents. A heading nests under the closest heading before it with a lower level; headings
+// in code blocks are ignored. Returns an empty slice for markdown without headings.
+func ExtractTOC(content string) []TOCEntry {
+	var flat []TOCEntry
+	seen := make(map[string]int)
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		level, title, ok := parseHeading(trimmed)
+		if !ok {
+			continue
+		}
+		flat = append(flat, TOCEntry{Level: level, Title: title, Anchor: anchor(title, seen)})
+	}
+	return nest(flat)
+}
+
+// parseHeading splits an ATX heading line into its level and plain-text title
+func parseHeading(line string) (int, string, bool) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level < 1 || level > 6 || (len(line) > level && line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(line[level:])
+	// A closing sequence of #s is not part of the title
+	if stripped := strings.TrimRight(title, "#"); stripped != title && (stripped == "" || strings.HasSuffix(stripped, " ")) {
+		title = strings.TrimSpace(stripped)
+	}
+	title = plainTitle(title)
+	if title == "" {
+		return 0, "", false
+	}
+	return level, title, true
+}
+
+// plainTitle strips links and emphasis from a heading title
+func plainTitle(title string) string {
+	title = inlineLink.ReplaceAllString(title, "$1")
+	return strings.TrimSpace(inlineMarks.Replace(title))
+}
+
+// anchor builds the fragment a heading is linked by: lowercased, punctuation removed, spaces
+// as hyphens, and a -1, -2... suffix for repeated titles
+func anchor(title string, seen map[string]int) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	slug := b.String()
+	n := seen[slug]
+	seen[slug] = n + 1
+	if n > 0 {
+		return slug + "-" + strconv.Itoa(n)
+	}
+	return slug
+}
+
+// nest turns headings in document order into a tree by level
+func nest(flat []TOCEntry) []TOCEntry {
+	root := []TOCEntry{}
+	for i := 0; i < len(flat); {
+		entry := flat[i]
+		j := i + 1
+		for j < len(flat) && flat[j].Level > entry.Level {
+			j++
+		}
+		if j > i+1 {
+			entry.Children = nest(flat[i+1 : j])
+		}
+		root = append(root, entry)
+		i = j
+	}
+	return root
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// PipelineCollector exports content pipeline stage counts as Prometheus gauges.
+// Values are read from the database on every scrape.
+type PipelineCollector struct {
+	repo *repository.PipelineRepository
+
+	completed *prometheus.Desc
+	pending   *prometheus.Desc
+	last24h   *prometheus.Desc
+	oldestAge *prometheus.Desc
+}
+
+// NewPipelineCollector creates a new pipeline collector
+func NewPipelineCollector(repo *repository.PipelineRepository) *PipelineCollector {
+	labels := []string{"stage"}
+	return &PipelineCollector{
+		repo:      repo,
+		completed: prometheus.NewDesc("web3insight_pipeline_items_completed", "Items that have reached the pipeline stage.", labels, nil),
+		pending:   prometheus.NewDesc("web3insight_pipeline_items_pending", "Items waiting to enter the pipeline stage.", labels, nil),
+		last24h:   prometheus.NewDesc("web3insight_pipeline_items_last_24h", "Items that entered the upstream stage in the last 24 hours.", labels, nil),
+		oldestAge: prometheus.NewDesc("web3insight_pipeline_oldest_pending_age_seconds", "Age of the oldest item waiting to enter the pipeline stage.", labels, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *PipelineCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.completed
+	ch <- c.pending
+	ch <- c.last24h
+	ch <- c.oldestAge
+}
+
+// Collect implements prometheus.Collector
+func (c *PipelineCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.repo.GetStats()
+	if err != nil {
+		log.Printf("Failed to collect pipeline metrics: %v", err)
+		return
+	}
+
+	for _, s := range stats.Stages {
+		ch <- prometheus.MustNewConstMetric(c.completed, prometheus.GaugeValue, float64(s.Completed), s.Stage)
+		ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(s.Pending), s.Stage)
+		ch <- prometheus.MustNewConstMetric(c.last24h, prometheus.GaugeValue, float64(s.Last24h), s.Stage)
+		ch <- prometheus.MustNewConstMetric(c.oldestAge, prometheus.GaugeValue, s.OldestPendingAge, s.Stage)
+	}
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// NewHandler returns the /metrics handler with Go runtime and pipeline metrics
+func NewHandler(pipelineRepo *repository.PipelineRepository) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		NewPipelineCollector(pipelineRepo),
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
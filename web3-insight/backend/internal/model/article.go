@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
+	"gorm.io/datatypes"
 )
 
 type Article struct {
@@ -14,17 +15,37 @@ type Article struct {
 	Slug             string          `gorm:"size:500;uniqueIndex;not null" json:"slug"`
 	Content          string          `gorm:"type:text;not null" json:"content"`
 	ContentHTML      string          `gorm:"type:text" json:"contentHtml"`
+	TOC              datatypes.JSON  `gorm:"type:jsonb" json:"toc"` // []markdown.TOCEntry, derived from Content on save
 	Summary          string          `gorm:"type:text" json:"summary"`
+	ReadingMinutes   int             `gorm:"default:0" json:"readingMinutes"` // Estimated reading time, derived from Content on save
+	ContentHash      string          `gorm:"size:64;index" json:"contentHash,omitempty"` // Normalized hash of Content for finding duplicates, derived on save
+	MetaDescription  string          `gorm:"size:500" json:"metaDescription"`
+	OGTitle          string          `gorm:"size:500" json:"ogTitle"`
+	OGDescription    string          `gorm:"size:500" json:"ogDescription"`
+	OGImage          string          `gorm:"type:text" json:"ogImage,omitempty"`
+	SEOSource        string          `gorm:"size:20;default:'auto'" json:"seoSource"` // Source of the meta and OpenGraph fields, one of the SEOSource constants
 	CategoryID       *uuid.UUID      `gorm:"type:uuid" json:"categoryId"`
 	Category         *Category       `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
 	Tags             pq.StringArray  `gorm:"type:text[]" json:"tags"`
 	Status           string          `gorm:"size:20;default:'published'" json:"status"`
+	ReviewNote       string          `gorm:"type:text" json:"reviewNote,omitempty"` // Note of the latest review transition
+	PublishAt        *time.Time      `gorm:"index" json:"publishAt,omitempty"` // When a scheduled article is published
+	Difficulty       string          `gorm:"size:20;index" json:"difficulty"` // beginner, intermediate, advanced; empty until assessed
 	SourceURLs       pq.StringArray  `gorm:"type:text[]" json:"sourceUrls"`
 	SourceLanguage   string          `gorm:"size:10" json:"sourceLanguage"`
 	ModelUsed        string          `gorm:"size:50" json:"modelUsed"`
 	GenerationPrompt string          `gorm:"type:text" json:"generationPrompt"`
+	EditedBy         string          `gorm:"size:20;default:'ai'" json:"editedBy"` // Source of the current content, one of the EditedBy constants
+	License          string          `gorm:"size:50" json:"license,omitempty"`     // Reuse license of the source content; empty for original content
+	LicenseURL       string          `gorm:"size:500" json:"licenseUrl,omitempty"`
+	Attribution      string          `gorm:"type:text" json:"attribution,omitempty"` // Credit line shown with reused content
 	ViewCount        int             `gorm:"default:0" json:"viewCount"`
 	Embedding        *pgvector.Vector `gorm:"type:vector(1536)" json:"-"`
+	Prerequisites    []ArticlePrerequisite `gorm:"-" json:"prerequisites,omitempty"`  // Loaded on demand
+	Series           []SeriesPosition      `gorm:"-" json:"series,omitempty"`         // Loaded on demand
+	DeepDives        []Article             `gorm:"-" json:"deepDives,omitempty"`      // Articles going deeper into this one; loaded on demand
+	DeepDiveOf       []Article             `gorm:"-" json:"deepDiveOf,omitempty"`     // Articles this one goes deeper into; loaded on demand
+	RedirectedFrom   string                `gorm:"-" json:"redirectedFrom,omitempty"` // Old slug the article was requested by
 	CreatedAt        time.Time       `json:"createdAt"`
 	UpdatedAt        time.Time       `json:"updatedAt"`
 }
@@ -33,12 +54,102 @@ func (Article) TableName() string {
 	return "articles"
 }
 
+// Article statuses
+const (
+	ArticleStatusDraft            = "draft"
+	ArticleStatusInReview         = "in_review"
+	ArticleStatusChangesRequested = "changes_requested"
+	ArticleStatusApproved         = "approved"
+	ArticleStatusPublished        = "published"
+	ArticleStatusScheduled        = "scheduled" // Published by the worker once PublishAt arrives
+)
+
+// articleStatusTransitions lists the statuses the review workflow can move an article to
+// from each status. An article under review has to be approved before it is published.
+var articleStatusTransitions = map[string][]string{
+	ArticleStatusDraft:            {ArticleStatusInReview},
+	ArticleStatusInReview:         {ArticleStatusApproved, ArticleStatusChangesRequested, ArticleStatusDraft},
+	ArticleStatusChangesRequested: {ArticleStatusInReview, ArticleStatusDraft},
+	ArticleStatusApproved:         {ArticleStatusPublished, ArticleStatusScheduled, ArticleStatusInReview, ArticleStatusDraft},
+	ArticleStatusScheduled:        {ArticleStatusPublished, ArticleStatusDraft},
+	ArticleStatusPublished:        {ArticleStatusInReview, ArticleStatusDraft},
+}
+
+// IsValidArticleStatus reports whether s is a known article status
+func IsValidArticleStatus(s string) bool {
+	_, ok := articleStatusTransitions[s]
+	return ok
+}
+
+// IsReviewStatus reports whether s is a status only the review workflow sets
+func IsReviewStatus(s string) bool {
+	return s == ArticleStatusInReview || s == ArticleStatusChangesRequested || s == ArticleStatusApproved
+}
+
+// CanTransitionArticle reports whether the review workflow can move an article from one
+// status to another
+func CanTransitionArticle(from, to string) bool {
+	if from == "" {
+		from = ArticleStatusPublished
+	}
+	for _, allowed := range articleStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources of an article's meta description and OpenGraph fields. Only auto fields are
+// rederived from the content when it changes.
+const (
+	SEOSourceAuto   = "auto"   // Derived from the summary and content on save
+	SEOSourceLLM    = "llm"    // Written by the summarization model
+	SEOSourceManual = "manual" // Set by an editor
+)
+
+// Well-known license identifiers; Creative Commons licenses use their SPDX form, e.g. CC-BY-4.0
+const (
+	LicenseCC0               = "CC0-1.0"
+	LicenseAllRightsReserved = "all-rights-reserved"
+)
+
+// Article difficulty levels, in learning order
+const (
+	DifficultyBeginner     = "beginner"
+	DifficultyIntermediate = "intermediate"
+	DifficultyAdvanced     = "advanced"
+)
+
+// Difficulties lists the difficulty levels from easiest to hardest
+var Difficulties = []string{DifficultyBeginner, DifficultyIntermediate, DifficultyAdvanced}
+
+// IsValidDifficulty reports whether d is a known difficulty level
+func IsValidDifficulty(d string) bool {
+	for _, known := range Difficulties {
+		if d == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources of article content, recorded on articles and their versions
+const (
+	EditedByAI      = "ai"      // Generated by the pipeline or extended by research updates
+	EditedByManual  = "manual"  // Edited through the API
+	EditedByImport  = "import"  // Imported from files or a wiki
+	EditedByRestore = "restore" // Restored from an earlier version
+)
+
+// ArticleVersion is the content of an article before an update replaced it
 type ArticleVersion struct {
 	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ArticleID     uuid.UUID `gorm:"type:uuid;not null" json:"articleId"`
+	ArticleID     uuid.UUID `gorm:"type:uuid;not null;index:idx_article_versions_article_version,priority:1" json:"articleId"`
 	Article       *Article  `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"article,omitempty"`
-	Content       string    `gorm:"type:text;not null" json:"content"`
-	EditedBy      string    `gorm:"size:20;default:'ai'" json:"editedBy"`
+	Version       int       `gorm:"not null;default:0;index:idx_article_versions_article_version,priority:2" json:"version"` // 1 for the oldest version of the article
+	Content       string    `gorm:"type:text;not null" json:"content,omitempty"`
+	EditedBy      string    `gorm:"size:20;default:'ai'" json:"editedBy"` // Who or what produced this content
 	ChangeSummary string    `gorm:"type:text" json:"changeSummary"`
 	CreatedAt     time.Time `json:"createdAt"`
 }
@@ -46,3 +157,189 @@ type ArticleVersion struct {
 func (ArticleVersion) TableName() string {
 	return "article_versions"
 }
+
+// ArticleReview records a move of an article through the review workflow, with the reviewer's note
+type ArticleReview struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID  uuid.UUID `gorm:"type:uuid;not null;index" json:"articleId"`
+	Article    *Article  `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	FromStatus string    `gorm:"size:20" json:"fromStatus"`
+	ToStatus   string    `gorm:"size:20;not null" json:"toStatus"`
+	Reviewer   string    `gorm:"size:100" json:"reviewer,omitempty"`
+	Note       string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (ArticleReview) TableName() string {
+	return "article_reviews"
+}
+
+// ArticleRevision is a change to an article proposed by the news pipeline or a research
+// schedule. It is applied to the article only once a reviewer accepts it.
+type ArticleRevision struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"articleId"`
+	Article    *Article       `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	NewsID     *uuid.UUID     `gorm:"type:uuid;index" json:"newsId,omitempty"`     // News item the change was written from
+	ScheduleID *uuid.UUID     `gorm:"type:uuid;index" json:"scheduleId,omitempty"` // Research schedule the change was written from
+	Section    string         `gorm:"type:text;not null" json:"section"`           // Markdown appended to the article when applied
+	SourceURL  string         `gorm:"size:1000" json:"sourceUrl,omitempty"`        // Added to the article's sources when applied
+	SourceURLs pq.StringArray `gorm:"type:text[]" json:"sourceUrls,omitempty"`     // Further sources added when applied
+	ModelUsed  string         `gorm:"size:50" json:"modelUsed,omitempty"`
+	Status     string         `gorm:"size:20;not null;default:'pending';index" json:"status"` // One of the RevisionStatus constants
+	Reviewer   string         `gorm:"size:100" json:"reviewer,omitempty"`
+	Note       string         `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+func (ArticleRevision) TableName() string {
+	return "article_revisions"
+}
+
+// Statuses of a proposed article revision
+const (
+	RevisionStatusPending  = "pending"
+	RevisionStatusApplied  = "applied"
+	RevisionStatusRejected = "rejected"
+)
+
+// SlugRedirect maps a slug an article no longer has to the article, so links to it keep working
+type SlugRedirect struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OldSlug   string    `gorm:"size:500;uniqueIndex;not null" json:"oldSlug"`
+	ArticleID uuid.UUID `gorm:"type:uuid;not null;index" json:"articleId"`
+	Article   *Article  `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (SlugRedirect) TableName() string {
+	return "slug_redirects"
+}
+
+// ArticlePrerequisite records that the prerequisite should be read before the article
+type ArticlePrerequisite struct {
+	ArticleID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"articleId"`
+	Article        *Article  `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	PrerequisiteID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"prerequisiteId"`
+	Prerequisite   *Article  `gorm:"foreignKey:PrerequisiteID;constraint:OnDelete:CASCADE" json:"prerequisite,omitempty"`
+	Source         string    `gorm:"size:20;default:'manual'" json:"source"` // manual or llm
+	Confidence     float64   `json:"confidence,omitempty"`
+	Reason         string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func (ArticlePrerequisite) TableName() string {
+	return "article_prerequisites"
+}
+
+// Prerequisite sources
+const (
+	PrerequisiteSourceManual = "manual"
+	PrerequisiteSourceLLM    = "llm"
+)
+
+// ArticleRelation links articles other than as prerequisites: membership of an ordered
+// series, or an article that goes deeper into another
+type ArticleRelation struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Type      string     `gorm:"size:20;not null" json:"type"` // series or deep_dive
+	ArticleID uuid.UUID  `gorm:"type:uuid;not null;index" json:"articleId"`
+	Article   *Article   `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"article,omitempty"`
+	RelatedID *uuid.UUID `gorm:"type:uuid;index" json:"relatedId,omitempty"` // deep_dive: the article gone deeper into
+	Related   *Article   `gorm:"foreignKey:RelatedID;constraint:OnDelete:CASCADE" json:"related,omitempty"`
+	Series    string     `gorm:"size:200;index" json:"series,omitempty"` // series: name of the series
+	Position  int        `json:"position,omitempty"`                     // series: order within the series, from 1
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func (ArticleRelation) TableName() string {
+	return "article_relations"
+}
+
+// Article relation types; prerequisites have their own table
+const (
+	RelationTypeSeries   = "series"
+	RelationTypeDeepDive = "deep_dive"
+)
+
+// ArticleLink is an edge of the concept graph between articles: the article mentions the
+// linked article's concept, depends on it, or contrasts with it
+type ArticleLink struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_article_link" json:"articleId"`
+	Article    *Article  `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"article,omitempty"`
+	LinkedID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_article_link;index" json:"linkedId"`
+	Linked     *Article  `gorm:"foreignKey:LinkedID;constraint:OnDelete:CASCADE" json:"linked,omitempty"`
+	Type       string    `gorm:"size:20;not null;uniqueIndex:idx_article_link" json:"type"` // mentions, depends_on or contrasts_with
+	Source     string    `gorm:"size:20;default:'manual'" json:"source"`                    // manual or llm
+	Confidence float64   `json:"confidence,omitempty"`
+	Reason     string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (ArticleLink) TableName() string {
+	return "article_links"
+}
+
+// Article link types
+const (
+	LinkTypeMentions      = "mentions"
+	LinkTypeDependsOn     = "depends_on"
+	LinkTypeContrastsWith = "contrasts_with"
+)
+
+// Article link sources
+const (
+	LinkSourceManual = "manual"
+	LinkSourceLLM    = "llm"
+)
+
+// LinkTypes lists the article link types
+var LinkTypes = []string{LinkTypeMentions, LinkTypeDependsOn, LinkTypeContrastsWith}
+
+// IsValidLinkType reports whether t is an article link type
+func IsValidLinkType(t string) bool {
+	for _, known := range LinkTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Graph is a part of the concept graph around an article, for visualization
+type Graph struct {
+	Root  uuid.UUID   `json:"root"`
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an article in a graph
+type GraphNode struct {
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	Slug       string     `json:"slug"`
+	Difficulty string     `json:"difficulty,omitempty"`
+	CategoryID *uuid.UUID `json:"categoryId,omitempty"`
+	Status     string     `json:"status"`
+	Depth      int        `json:"depth"` // Number of edges from the root
+}
+
+// GraphEdge is a link between two articles in a graph, pointing from the article to the
+// one it mentions, depends on or contrasts with. Prerequisites appear as depends_on edges.
+type GraphEdge struct {
+	Source     uuid.UUID `json:"source"`
+	Target     uuid.UUID `json:"target"`
+	Type       string    `json:"type"`
+	Confidence float64   `json:"confidence,omitempty"`
+}
+
+// SeriesPosition places an article within a series, with its neighbours for navigation
+type SeriesPosition struct {
+	Series   string   `json:"series"`
+	Position int      `json:"position"` // 1-based rank among the series' articles
+	Total    int      `json:"total"`
+	Previous *Article `json:"previous,omitempty"`
+	Next     *Article `json:"next,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+)
+
+// ArticleChunk is a section of an article embedded on its own, so chat can retrieve the
+// passages relevant to a question instead of the whole article
+type ArticleChunk struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID   uuid.UUID        `gorm:"type:uuid;not null;index" json:"articleId"`
+	Article     *Article         `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	Position    int              `gorm:"not null" json:"position"` // Order within the article
+	Heading     string           `gorm:"size:500" json:"heading"`  // Nearest markdown heading above the chunk
+	Content     string           `gorm:"type:text;not null" json:"content"`
+	ContentHash string           `gorm:"size:64;not null" json:"contentHash"` // Hash of the article text the chunks were built from
+	Embedding   *pgvector.Vector `gorm:"type:vector(1536)" json:"-"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+func (ArticleChunk) TableName() string {
+	return "article_chunks"
+}
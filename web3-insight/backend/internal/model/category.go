@@ -7,22 +7,83 @@ import (
 )
 
 type Category struct {
-	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Name         string     `gorm:"size:100;not null" json:"name"`
-	NameEn       string     `gorm:"size:100" json:"nameEn"`
-	Slug         string     `gorm:"size:100;uniqueIndex;not null" json:"slug"`
-	ParentID     *uuid.UUID `gorm:"type:uuid" json:"parentId"`
-	Parent       *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
-	Children     []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
-	Description  string     `gorm:"type:text" json:"description"`
-	Icon         string     `gorm:"size:50" json:"icon"`
-	SortOrder    int        `gorm:"default:0" json:"sortOrder"`
-	AutoCreated  bool       `gorm:"default:false" json:"autoCreated"`
-	ArticleCount int        `gorm:"default:0" json:"articleCount"`
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name           string     `gorm:"size:100;not null" json:"name"`
+	NameEn         string     `gorm:"size:100" json:"nameEn"`
+	Slug           string     `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+	ParentID       *uuid.UUID `gorm:"type:uuid" json:"parentId"`
+	Parent         *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Children       []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Description    string     `gorm:"type:text" json:"description"`
+	Icon           string     `gorm:"size:50" json:"icon"`
+	SortOrder      int        `gorm:"default:0" json:"sortOrder"`
+	AutoCreated    bool       `gorm:"default:false" json:"autoCreated"`
+	Archived       bool       `gorm:"default:false;index" json:"archived"` // Hidden from the tree and never assigned by the classifier; its articles keep it
+	ArticleCount   int        `gorm:"default:0" json:"articleCount"`
+	RedirectedFrom string     `gorm:"-" json:"redirectedFrom,omitempty"` // ID or slug of a merged category the category was requested by
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
 func (Category) TableName() string {
 	return "categories"
 }
+
+// CategoryIcons lists the icon names the frontend can show for a category (Lucide icons)
+var CategoryIcons = []string{
+	"layers", "coins", "image", "shield", "diamond", "zap", "globe", "lock", "clock", "percent",
+	"repeat", "book", "code", "cpu", "database", "key", "link", "network", "scale", "users",
+	"vote", "wallet", "landmark", "trending-up", "gamepad-2", "bot", "box", "file-text",
+}
+
+// IsValidCategoryIcon reports whether icon is one of CategoryIcons
+func IsValidCategoryIcon(icon string) bool {
+	for _, known := range CategoryIcons {
+		if icon == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryMerge records a category merged into another, so links to the merged category
+// resolve to the one that replaced it
+type CategoryMerge struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SourceID      uuid.UUID `gorm:"type:uuid;not null;index" json:"sourceId"`
+	SourceSlug    string    `gorm:"size:100;index;not null" json:"sourceSlug"`
+	SourceName    string    `gorm:"size:100" json:"sourceName"`
+	TargetID      uuid.UUID `gorm:"type:uuid;not null;index" json:"targetId"` // Follows later merges of the target
+	Target        *Category `gorm:"foreignKey:TargetID;constraint:OnDelete:CASCADE" json:"-"`
+	ArticlesMoved int       `json:"articlesMoved"`
+	ChildrenMoved int       `json:"childrenMoved"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func (CategoryMerge) TableName() string {
+	return "category_merges"
+}
+
+// CategoryDuplicate is a pair of categories whose names and descriptions embed so close
+// together that they are likely synonyms. CategoryID sorts before DuplicateID.
+type CategoryDuplicate struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CategoryID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_duplicate_pair" json:"categoryId"`
+	Category    *Category `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE" json:"category,omitempty"`
+	DuplicateID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_duplicate_pair;index" json:"duplicateId"`
+	Duplicate   *Category `gorm:"foreignKey:DuplicateID;constraint:OnDelete:CASCADE" json:"duplicate,omitempty"`
+	Similarity  float64   `json:"similarity"` // Cosine similarity of the embeddings, 0 to 1
+	Status      string    `gorm:"size:20;default:'open';index" json:"status"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (CategoryDuplicate) TableName() string {
+	return "category_duplicates"
+}
+
+// Category duplicate statuses; merging a pair deletes it with the merged category
+const (
+	DuplicateStatusOpen      = "open"
+	DuplicateStatusDismissed = "dismissed"
+)
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContentChange is one entry in the article and category change log that
+// downstream consumers poll for incremental sync
+type ContentChange struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"` // Monotonic, usable as a sync cursor
+	EntityType string    `gorm:"size:20;not null;index:idx_content_changes_entity" json:"type"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index:idx_content_changes_entity" json:"entityId"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	Slug       string    `gorm:"size:500" json:"slug"` // Kept for deletions, when the entity can no longer be looked up
+	CreatedAt  time.Time `gorm:"index" json:"changedAt"`
+}
+
+func (ContentChange) TableName() string {
+	return "content_changes"
+}
+
+// Change log entity types
+const (
+	ChangeEntityArticle  = "article"
+	ChangeEntityCategory = "category"
+)
+
+// Change log actions
+const (
+	ChangeActionCreated = "created"
+	ChangeActionUpdated = "updated"
+	ChangeActionDeleted = "deleted"
+)
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ConsistencyReport records one run of the consistency checker
+type ConsistencyReport struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AutoFix    bool           `json:"autoFix"`
+	IssueCount int            `json:"issueCount"`
+	FixedCount int            `json:"fixedCount"`
+	Checks     datatypes.JSON `gorm:"type:jsonb" json:"checks"` // []service.ConsistencyCheckResult
+	StartedAt  time.Time      `json:"startedAt"`
+	FinishedAt time.Time      `gorm:"index" json:"finishedAt"`
+}
+
+func (ConsistencyReport) TableName() string {
+	return "consistency_reports"
+}
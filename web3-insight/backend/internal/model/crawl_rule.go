@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CrawlRule tells the content parser how to extract articles of one site. Rules are loaded
+// by the crawler at runtime, so new sites need no deploy.
+type CrawlRule struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Domain          string         `gorm:"size:255;not null;uniqueIndex" json:"domain"` // Host without www., e.g. blog.ethereum.org
+	ContentSelector string         `gorm:"size:500" json:"contentSelector"`             // CSS selector of the article body
+	TitleSelector   string         `gorm:"size:500" json:"titleSelector"`
+	RemoveSelectors pq.StringArray `gorm:"type:text[]" json:"removeSelectors"` // Elements removed before extraction
+	RenderMode      string         `gorm:"size:20;default:'static'" json:"renderMode"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+}
+
+func (CrawlRule) TableName() string {
+	return "crawl_rules"
+}
+
+// Crawl rule render modes
+const (
+	RenderModeStatic = "static" // Parse the fetched HTML
+	RenderModeAMP    = "amp"    // Follow the page's rel="amphtml" link, for sites that render articles client-side
+)
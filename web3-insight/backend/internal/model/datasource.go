@@ -4,12 +4,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/datatypes"
 )
 
 type DataSource struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Name          string         `gorm:"size:100;not null" json:"name"`
+	Name          string         `gorm:"size:100;not null;uniqueIndex" json:"name"` // Unique: news items refer to their source by name
 	Type          string         `gorm:"size:50;not null" json:"type"`
 	URL           string         `gorm:"size:1000" json:"url"`
 	Config        datatypes.JSON `gorm:"type:jsonb" json:"config"`
@@ -29,4 +30,30 @@ const (
 	DataSourceTypeRSS   = "rss"
 	DataSourceTypeAPI   = "api"
 	DataSourceTypeCrawl = "crawl"
+	// Mirror or Paragraph publication, by its URL
+	DataSourceTypePublication = "publication"
+	// arXiv API query or IACR ePrint feed
+	DataSourceTypeResearch = "research"
+	// Discourse forum, such as a governance forum
+	DataSourceTypeDiscourse = "discourse"
+	// Snapshot space or Tally organization
+	DataSourceTypeGovernance = "governance"
+	// IMAP mailbox receiving newsletters
+	DataSourceTypeEmail = "email"
 )
+
+// FetchValidator holds the HTTP cache validators of a fetched feed, page or sitemap, sent back
+// as If-None-Match and If-Modified-Since on the next fetch
+type FetchValidator struct {
+	URL          string `gorm:"size:1000;primaryKey" json:"url"`
+	ETag         string `gorm:"column:etag;size:500" json:"etag"`
+	LastModified string `gorm:"size:100" json:"lastModified"`
+	ContentHash  string `gorm:"size:64" json:"contentHash"` // Of the last body, for servers without validators
+	// For sitemap indexes, the child sitemaps followed, read again while the index is unchanged
+	Children  pq.StringArray `gorm:"type:text[]" json:"children,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+func (FetchValidator) TableName() string {
+	return "fetch_validators"
+}
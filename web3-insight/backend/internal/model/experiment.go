@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Experiment splits traffic for an LLM task between prompt or model variants
+type Experiment struct {
+	ID          uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string              `gorm:"size:200;not null" json:"name"`
+	Description string              `gorm:"type:text" json:"description"`
+	Task        string              `gorm:"size:50;not null;index" json:"task"` // LLM routing task, e.g. content_generation
+	Status      string              `gorm:"size:20;default:'active';index" json:"status"`
+	Variants    []ExperimentVariant `gorm:"foreignKey:ExperimentID;constraint:OnDelete:CASCADE" json:"variants"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+}
+
+func (Experiment) TableName() string {
+	return "experiments"
+}
+
+// Experiment statuses
+const (
+	ExperimentStatusActive    = "active"
+	ExperimentStatusPaused    = "paused"
+	ExperimentStatusCompleted = "completed"
+)
+
+// ExperimentVariant is one arm of an experiment. An empty Prompt keeps the default
+// prompt and an empty Model keeps the task's normal routing.
+type ExperimentVariant struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ExperimentID uuid.UUID `gorm:"type:uuid;not null;index" json:"experimentId"`
+	Name         string    `gorm:"size:100;not null" json:"name"`
+	Prompt       string    `gorm:"type:text" json:"prompt"`
+	Model        string    `gorm:"size:100" json:"model"`
+	Traffic      int       `gorm:"not null" json:"traffic"` // Percentage of requests, 0-100
+}
+
+func (ExperimentVariant) TableName() string {
+	return "experiment_variants"
+}
+
+// ExperimentRun records one generation served by an experiment variant
+type ExperimentRun struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ExperimentID uuid.UUID       `gorm:"type:uuid;not null;index" json:"experimentId"`
+	VariantID    uuid.UUID       `gorm:"type:uuid;not null;index" json:"variantId"`
+	ArticleID    *uuid.UUID      `gorm:"type:uuid" json:"articleId"`
+	ModelUsed    string          `gorm:"size:100" json:"modelUsed"`
+	QualityScore *float64        `json:"qualityScore"` // 0-1; automatic heuristic, may be overridden by a reviewer
+	TokensUsed   int             `json:"tokensUsed"`
+	CostUSD      decimal.Decimal `gorm:"type:decimal(10,6)" json:"costUsd"`
+	DurationMs   int64           `json:"durationMs"`
+	Error        string          `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt    time.Time       `gorm:"index" json:"createdAt"`
+}
+
+func (ExperimentRun) TableName() string {
+	return "experiment_runs"
+}
@@ -26,6 +26,8 @@ type ExplorerResearch struct {
 	PopularityScore float64        `gorm:"default:0" json:"popularityScore"`
 	ResearchStatus  string         `gorm:"size:20;default:'pending'" json:"researchStatus"` // pending, in_progress, completed
 	ResearchNotes   string         `gorm:"type:text" json:"researchNotes"`
+	Assignee        string         `gorm:"size:100;index" json:"assignee"` // Team member who owns the research
+	DueDate         *time.Time     `json:"dueDate"`
 	LastUpdated     time.Time      `gorm:"default:now()" json:"lastUpdated"`
 	CreatedAt       time.Time      `json:"createdAt"`
 }
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// LLMCall is one audited request to a model and its response
+type LLMCall struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Task         string          `gorm:"size:50;index" json:"task"` // Routing task; empty for direct model calls
+	Model        string          `gorm:"size:100;index" json:"model"`
+	Provider     string          `gorm:"size:50;index" json:"provider"`
+	PromptHash   string          `gorm:"size:64;index" json:"promptHash"` // SHA-256 of the prompt, for finding repeats
+	Prompt       string          `gorm:"type:text" json:"prompt,omitempty"`
+	Response     string          `gorm:"type:text" json:"response,omitempty"`
+	Status       string          `gorm:"size:20;index" json:"status"`
+	Error        string          `gorm:"type:text" json:"error,omitempty"`
+	DurationMs   int64           `json:"durationMs"`
+	InputTokens  int             `json:"inputTokens"`
+	OutputTokens int             `json:"outputTokens"`
+	CostUSD      decimal.Decimal `gorm:"type:decimal(10,6)" json:"costUsd"`
+	Retries      int             `json:"retries"`
+	Stream       bool            `json:"stream"`
+	CreatedAt    time.Time       `gorm:"index" json:"createdAt"`
+}
+
+func (LLMCall) TableName() string {
+	return "llm_calls"
+}
+
+// LLM call statuses
+const (
+	LLMCallStatusSuccess = "success"
+	LLMCallStatusError   = "error"
+)
@@ -17,14 +17,67 @@ type NewsItem struct {
 	SourceURL      string          `gorm:"size:1000;uniqueIndex;not null" json:"sourceUrl"`
 	SourceName     string          `gorm:"size:100" json:"sourceName"`
 	SourceLanguage string          `gorm:"size:10" json:"sourceLanguage"`
+	Author         string          `gorm:"size:200" json:"author,omitempty"`
+	License        string          `gorm:"size:50" json:"license,omitempty"` // Detected from the page or feed; empty if unknown
+	LicenseURL     string          `gorm:"size:500" json:"licenseUrl,omitempty"`
+	ContentBlock   string          `gorm:"size:20" json:"contentBlock,omitempty"` // Set when the page hid its content behind a consent wall or paywall
+	UpdateOf       *uuid.UUID      `gorm:"type:uuid;index" json:"updateOf,omitempty"` // For "updated" items, the item whose source page changed
+	ExtractionConfidence *float64  `gorm:"type:real" json:"extractionConfidence,omitempty"` // 0-1, for crawled pages: how likely the content is the article
+	PipelineDecision string        `gorm:"size:20;index" json:"pipelineDecision,omitempty"` // Outcome of the news-to-article pipeline, one of the PipelineDecision constants
+	PipelineAttempts int           `gorm:"default:0" json:"pipelineAttempts"`
+	PipelineClaimedAt *time.Time   `json:"pipelineClaimedAt,omitempty"` // When a pipeline run last claimed the item
+	ArticleID      *uuid.UUID      `gorm:"type:uuid;index" json:"articleId,omitempty"` // Article the pipeline created or updated from this item
+	StoryID        *uuid.UUID      `gorm:"type:uuid;index" json:"storyId,omitempty"` // Story of near-duplicate items from other feeds, if any
+	TranslationID  *uuid.UUID      `gorm:"type:uuid" json:"translationId,omitempty"` // Full Chinese translation of the content, if made
+	BackfilledAt   *time.Time      `json:"backfilledAt,omitempty"` // When thin feed content was last replaced by a crawl of the source page, or tried to be
+	RecrawledAt    *time.Time      `json:"recrawledAt,omitempty"` // When a crawled page was last checked for changes, whatever the outcome
+	RelevanceScore *float64        `gorm:"type:real" json:"relevanceScore,omitempty"` // 0-1, relevance and quality as scored before summarization
+	RelevanceReason string         `gorm:"size:500" json:"relevanceReason,omitempty"`
+	Filtered       string          `gorm:"size:20;index" json:"filtered,omitempty"` // Set when the item scored too low, one of the NewsFiltered constants
 	Category       string          `gorm:"size:50" json:"category"`
 	Tags           pq.StringArray  `gorm:"type:text[]" json:"tags"`
 	PublishedAt    *time.Time      `json:"publishedAt"`
 	FetchedAt      time.Time       `gorm:"default:now()" json:"fetchedAt"`
 	Processed      bool            `gorm:"default:false" json:"processed"`
+	SummaryAttempts int            `gorm:"default:0" json:"summaryAttempts"`
+	SummaryError   string          `gorm:"type:text" json:"summaryError,omitempty"`
 	Embedding      *pgvector.Vector `gorm:"type:vector(1536)" json:"-"`
+	UpdatedAt      time.Time       `gorm:"index;default:now()" json:"updatedAt"`
 }
 
 func (NewsItem) TableName() string {
 	return "news_items"
 }
+
+// LowExtractionConfidence is the extraction confidence below which crawled content is
+// flagged for review
+const LowExtractionConfidence = 0.5
+
+// Actions on news items scoring below the relevance threshold
+const (
+	NewsFilteredQuarantine = "quarantined" // Kept out of summarization until an editor releases it
+	NewsFilteredDiscard    = "discarded"   // Kept only so the feed doesn't collect it again
+)
+
+// Outcomes of the news-to-article pipeline
+const (
+	PipelineDecisionCreate = "create" // The item became a new article
+	PipelineDecisionUpdate = "update" // The item was worked into an existing article
+	PipelineDecisionSkip   = "skip"   // The item did not warrant an article
+
+	PipelineDecisionRunning = "running" // Claimed by a pipeline run that has not finished
+	PipelineDecisionFailed  = "failed"  // Gave up after MaxPipelineAttempts failed runs
+)
+
+// MaxPipelineAttempts is the number of failed pipeline runs after which an item is given up
+const MaxPipelineAttempts = 3
+
+// Reasons a crawled page did not show its full content
+const (
+	// ContentBlockChallenge is an anti-bot challenge such as Cloudflare's "Just a moment..."
+	ContentBlockChallenge = "challenge"
+	// ContentBlockConsent is a cookie or privacy consent wall in front of the article
+	ContentBlockConsent = "consent"
+	// ContentBlockPaywall is a paywall; only a snippet of the article is stored
+	ContentBlockPaywall = "paywall"
+)
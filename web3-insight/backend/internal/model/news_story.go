@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// NewsStory groups news items from different feeds that report the same event. The first
+// item collected is kept as the canonical one; the others point to the story through StoryID.
+type NewsStory struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CanonicalID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"canonicalId"`
+	SourceURLs  pq.StringArray `gorm:"type:text[]" json:"sourceUrls"` // Source URLs of every item in the story, canonical first
+	ItemCount   int            `gorm:"default:0" json:"itemCount"`
+	Canonical   *NewsItem      `gorm:"-" json:"canonical,omitempty"` // Loaded on demand
+	Items       []NewsItem     `gorm:"-" json:"items,omitempty"`     // Loaded on demand
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+func (NewsStory) TableName() string {
+	return "news_stories"
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewsTranslation is the full Chinese translation of a foreign-language news item, kept
+// alongside the original content. The item points back to it through TranslationID.
+type NewsTranslation struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	NewsID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"newsId"`
+	Language   string    `gorm:"size:10;not null" json:"language"`
+	Title      string    `gorm:"size:500" json:"title"`
+	Content    string    `gorm:"type:text" json:"content"`
+	ModelUsed  string    `gorm:"size:50" json:"modelUsed"`
+	TokensUsed int       `json:"tokensUsed"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func (NewsTranslation) TableName() string {
+	return "news_translations"
+}
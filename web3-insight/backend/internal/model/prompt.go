@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// PromptTemplate overrides a built-in prompt for one persona.
+// Prompts without an override use the persona's built-in text.
+type PromptTemplate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Persona   string    `gorm:"size:50;not null;uniqueIndex:idx_prompt_templates_persona_name" json:"persona"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex:idx_prompt_templates_persona_name" json:"name"` // e.g. chat_general, knowledge_article
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
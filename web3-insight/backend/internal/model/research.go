@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// ResearchSession stores a single research run so it can be reviewed and re-run later
+type ResearchSession struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Query       string          `gorm:"type:text;not null" json:"query"`
+	Parameters  datatypes.JSON  `gorm:"type:jsonb" json:"parameters"`
+	Status      string          `gorm:"size:20;default:'running';index" json:"status"`
+	Sources     pq.StringArray  `gorm:"type:text[]" json:"sources"`
+	Output      string          `gorm:"type:text" json:"output"`
+	Citations   datatypes.JSON  `gorm:"type:jsonb" json:"citations"`
+	ModelUsed   string          `gorm:"size:50" json:"modelUsed"`
+	TokensUsed  int             `json:"tokensUsed"`
+	CostUSD     decimal.Decimal `gorm:"type:decimal(10,6)" json:"costUsd"`
+	DurationMs  int64           `json:"durationMs"`
+	Error       string          `gorm:"type:text" json:"error"`
+	ArticleID   *uuid.UUID      `gorm:"type:uuid" json:"articleId"`
+	Article     *Article        `gorm:"foreignKey:ArticleID;constraint:OnDelete:SET NULL" json:"article,omitempty"`
+	RerunOfID   *uuid.UUID      `gorm:"type:uuid" json:"rerunOfId"`
+	ScheduleID  *uuid.UUID      `gorm:"type:uuid;index" json:"scheduleId"`
+	CreatedAt   time.Time       `gorm:"index" json:"createdAt"`
+	CompletedAt *time.Time      `json:"completedAt"`
+}
+
+func (ResearchSession) TableName() string {
+	return "research_sessions"
+}
+
+// Research session statuses
+const (
+	ResearchStatusRunning   = "running"
+	ResearchStatusCompleted = "completed"
+	ResearchStatusFailed    = "failed"
+)
+
+// ResearchSchedule runs a research query on a cron schedule, optionally proposing
+// each result as an update section revision for a target article
+type ResearchSchedule struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name            string     `gorm:"size:200;not null" json:"name"`
+	Query           string     `gorm:"type:text;not null" json:"query"`
+	CronExpr        string     `gorm:"size:100;not null" json:"cronExpr"`
+	UseWebSearch    bool       `json:"useWebSearch"`
+	CitationMode    string     `gorm:"size:20" json:"citationMode"`
+	TargetArticleID *uuid.UUID `gorm:"type:uuid" json:"targetArticleId"`
+	TargetArticle   *Article   `gorm:"foreignKey:TargetArticleID;constraint:OnDelete:SET NULL" json:"targetArticle,omitempty"`
+	Enabled         bool       `gorm:"index" json:"enabled"`
+	LastRunAt       *time.Time `json:"lastRunAt"`
+	LastSessionID   *uuid.UUID `gorm:"type:uuid" json:"lastSessionId"`
+	LastError       string     `gorm:"type:text" json:"lastError"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+func (ResearchSchedule) TableName() string {
+	return "research_schedules"
+}
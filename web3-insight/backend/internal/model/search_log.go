@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchLog is one search request, kept to learn what readers look for and fail to find
+type SearchLog struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"` // Assigned before saving so responses can return it for click tracking
+	Query      string    `gorm:"size:500;not null" json:"query"`
+	Normalized string    `gorm:"size:500;index" json:"normalized"` // Lowercased and whitespace-collapsed, for grouping
+	Kind       string    `gorm:"size:20;index" json:"kind"`        // keyword or semantic
+	Mode       string    `gorm:"size:20" json:"mode"`              // Backend that answered: database, engine name, semantic, hybrid
+	HitCount   int       `json:"hitCount"`
+	ZeroResult bool      `gorm:"index" json:"zeroResult"`
+	ClickCount int       `gorm:"default:0" json:"clickCount"`
+	CreatedAt  time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (SearchLog) TableName() string {
+	return "search_logs"
+}
+
+// Search log kinds
+const (
+	SearchKindKeyword  = "keyword"
+	SearchKindSemantic = "semantic"
+)
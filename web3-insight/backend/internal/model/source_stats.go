@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SourceStats holds one day of a data source's reliability figures, counted over the news
+// items it collected that day
+type SourceStats struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SourceID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_source_stats_day" json:"sourceId"`
+	Date         time.Time `gorm:"type:date;not null;uniqueIndex:idx_source_stats_day" json:"date"`
+	Items        int       `gorm:"default:0" json:"items"`        // Items collected, not counting re-crawl updates
+	Duplicates   int       `gorm:"default:0" json:"duplicates"`   // Items that joined a story another feed reported first
+	Discarded    int       `gorm:"default:0" json:"discarded"`    // Items discarded for low relevance
+	Quarantined  int       `gorm:"default:0" json:"quarantined"`  // Items still quarantined for low relevance
+	Scored       int       `gorm:"default:0" json:"scored"`       // Items with a relevance score
+	AvgRelevance *float64  `gorm:"type:real" json:"avgRelevance"` // Mean relevance score of the scored items
+	Corrections  int       `gorm:"default:0" json:"corrections"`  // Re-crawls that found a page changed after collection
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func (SourceStats) TableName() string {
+	return "source_stats"
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Tag is the canonical form of an article tag. Articles store canonical tag names; a tag
+// written as one of the aliases is saved as the tag's name.
+type Tag struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"size:100;uniqueIndex;not null" json:"name"`
+	Slug       string         `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+	Aliases    pq.StringArray `gorm:"type:text[]" json:"aliases"`        // Other spellings, and names of renamed or merged tags
+	UsageCount int            `gorm:"default:0;index" json:"usageCount"` // Articles with the tag
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// NormalizeTagName trims a tag, drops a leading # and collapses inner whitespace
+func NormalizeTagName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -27,18 +27,52 @@ func (Task) TableName() string {
 	return "tasks"
 }
 
+// ImportBatchData holds the articles of an import task record, kept out of the task's payload
+// so listing tasks does not load whole imports
+type ImportBatchData struct {
+	TaskID    uuid.UUID      `gorm:"type:uuid;primaryKey" json:"taskId"`
+	Data      datatypes.JSON `gorm:"type:jsonb" json:"data"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+func (ImportBatchData) TableName() string {
+	return "import_batches"
+}
+
 // Task types
 const (
-	TaskTypeRSSSync         = "rss_sync"
-	TaskTypeWebCrawl        = "web_crawl"
-	TaskTypeContentGenerate = "content_generate"
-	TaskTypeClassify        = "classify"
+	TaskTypeRSSSync          = "rss_sync"
+	TaskTypeWebCrawl         = "web_crawl"
+	TaskTypeContentGenerate  = "content_generate"
+	TaskTypeClassify         = "classify"
+	TaskTypeSummarize        = "summarize"
+	TaskTypeResearch         = "research"
+	TaskTypeSourceDiscovery  = "source_discovery"
+	TaskTypePrerequisites    = "prerequisites"
+	TaskTypeEmbeddingReindex = "embedding_reindex"
+	TaskTypeSEOMetadata      = "seo_metadata"
+	TaskTypeGlossary         = "glossary"
+	TaskTypeConceptLinks     = "concept_links"
+	TaskTypeCategoryEnrich   = "category_enrich"
+	TaskTypeImport           = "import"
+	TaskTypeNewsPipeline     = "news_pipeline" // One step of the news-to-article pipeline
+	TaskTypeTranslate        = "translate"
+	TaskTypeContentBackfill  = "content_backfill" // A run filling in RSS items that hold only a summary
+	TaskTypeRelevance        = "relevance"
 )
 
+// CrawlTaskPayload is the payload of a pending web_crawl task queued by a sitemap source
+type CrawlTaskPayload struct {
+	URL      string     `json:"url"`
+	SourceID uuid.UUID  `json:"sourceId"`
+	LastMod  *time.Time `json:"lastMod,omitempty"`
+}
+
 // Task statuses
 const (
 	TaskStatusPending   = "pending"
 	TaskStatusRunning   = "running"
 	TaskStatusCompleted = "completed"
 	TaskStatusFailed    = "failed"
+	TaskStatusCancelled = "cancelled"
 )
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Term is a glossary entry for Web3 jargon, shown as a hover definition in articles
+type Term struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	NameZh     string         `gorm:"size:200;index" json:"nameZh"` // 中文名, e.g. 零知识证明
+	NameEn     string         `gorm:"size:200;index" json:"nameEn"` // English term, e.g. Zero-Knowledge Proof
+	Slug       string         `gorm:"size:200;uniqueIndex;not null" json:"slug"`
+	Aliases    pq.StringArray `gorm:"type:text[]" json:"aliases"` // Abbreviations and other spellings, e.g. ZKP
+	Definition string         `gorm:"type:text;not null" json:"definition"`
+	Source     string         `gorm:"size:20;default:'manual'" json:"source"` // manual or llm
+	Articles   []Article      `gorm:"-" json:"articles,omitempty"`            // Related articles; loaded on demand
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+func (Term) TableName() string {
+	return "terms"
+}
+
+// Term sources; extraction never overwrites manual terms
+const (
+	TermSourceManual = "manual"
+	TermSourceLLM    = "llm"
+)
+
+// TermArticle links a glossary term to an article that explains or uses it
+type TermArticle struct {
+	TermID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"termId"`
+	Term      *Term     `gorm:"foreignKey:TermID;constraint:OnDelete:CASCADE" json:"-"`
+	ArticleID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"articleId"`
+	Article   *Article  `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (TermArticle) TableName() string {
+	return "term_articles"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TrendingTopic is a topic many recent news items are about, as ranked by the latest trend
+// analysis. Each analysis replaces the whole list.
+type TrendingTopic struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Rank       int            `gorm:"index" json:"rank"`
+	Topic      string         `gorm:"size:200;not null;uniqueIndex" json:"topic"`
+	Tags       pq.StringArray `gorm:"type:text[]" json:"tags"` // Most frequent tags of the topic's news
+	Score      float64        `json:"score"`
+	Mentions   int            `json:"mentions"`                             // Distinct stories about the topic in the window
+	Sources    int            `json:"sources"`                              // Distinct feeds reporting on it
+	Velocity   float64        `json:"velocity"`                             // Mentions in the last day over the daily rate before it
+	NewsIDs    pq.StringArray `gorm:"type:text[]" json:"newsIds"`           // Most recent news items about the topic
+	ArticleID  *uuid.UUID     `gorm:"type:uuid" json:"articleId,omitempty"` // Article generated from the topic
+	WindowDays int            `json:"windowDays"`
+	ComputedAt time.Time      `json:"computedAt"`
+}
+
+func (TrendingTopic) TableName() string {
+	return "trending_topics"
+}
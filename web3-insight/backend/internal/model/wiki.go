@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WikiPage maps an article to the page it was exported to in an external wiki,
+// so later exports update the same page instead of creating a new one
+type WikiPage struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_wiki_pages_article_target" json:"articleId"`
+	Target      string    `gorm:"size:20;not null;uniqueIndex:idx_wiki_pages_article_target" json:"target"` // confluence or feishu
+	RemoteID    string    `gorm:"size:100;not null" json:"remoteId"`                                        // Confluence page ID or Feishu wiki node token
+	RemoteURL   string    `gorm:"size:1000" json:"remoteUrl"`
+	ContentHash string    `gorm:"size:64" json:"contentHash"` // Hash of the exported title and content, to skip unchanged articles
+	ExportedAt  time.Time `json:"exportedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (WikiPage) TableName() string {
+	return "wiki_pages"
+}
+
+// Wiki export targets
+const (
+	WikiTargetConfluence = "confluence"
+	WikiTargetFeishu     = "feishu"
+)
@@ -1,10 +1,18 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
+	"github.com/user/web3-insight/internal/markdown"
 	"github.com/user/web3-insight/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ArticleRepository struct {
@@ -16,12 +24,34 @@ func NewArticleRepository(db *gorm.DB) *ArticleRepository {
 }
 
 type ArticleListParams struct {
-	CategoryID *uuid.UUID
-	Status     string
-	Tags       []string
-	Search     string
-	Page       int
-	PageSize   int
+	CategoryID   *uuid.UUID
+	Status       string
+	Statuses     []string // Any of these statuses, e.g. the review statuses for a review queue
+	Tags         []string // Articles with all of these tags; aliases match their tag
+	Search       string
+	Difficulties []string
+	UpdatedSince *time.Time // Articles changed after this time
+	CreatedSince *time.Time // Articles created after this time
+	Sort         string     // ArticleSortNewest (default) or ArticleSortDifficulty
+	Page         int
+	PageSize     int
+}
+
+// Article list sort orders
+const (
+	ArticleSortNewest     = "newest"
+	ArticleSortDifficulty = "difficulty" // Easiest first, unassessed last
+)
+
+// difficultyOrder ranks difficulty levels in learning order
+const difficultyOrder = "CASE difficulty WHEN 'beginner' THEN 1 WHEN 'intermediate' THEN 2 WHEN 'advanced' THEN 3 ELSE 4 END"
+
+// withDifficulties restricts a query to the given difficulty levels, if any
+func withDifficulties(query *gorm.DB, difficulties []string) *gorm.DB {
+	if len(difficulties) == 0 {
+		return query
+	}
+	return query.Where("difficulty IN ?", difficulties)
 }
 
 type ArticleListResult struct {
@@ -40,9 +70,22 @@ func (r *ArticleRepository) List(params ArticleListParams) (*ArticleListResult,
 	if params.Status != "" {
 		query = query.Where("status = ?", params.Status)
 	}
+	if len(params.Statuses) > 0 {
+		query = query.Where("status IN ?", params.Statuses)
+	}
+	for _, tag := range params.Tags {
+		query = query.Where("? = ANY(tags)", canonicalTag(r.db, tag))
+	}
 	if params.Search != "" {
 		query = query.Where("title ILIKE ? OR summary ILIKE ?", "%"+params.Search+"%", "%"+params.Search+"%")
 	}
+	query = withDifficulties(query, params.Difficulties)
+	if params.UpdatedSince != nil {
+		query = query.Where("updated_at > ?", params.UpdatedSince)
+	}
+	if params.CreatedSince != nil {
+		query = query.Where("created_at > ?", params.CreatedSince)
+	}
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -58,6 +101,10 @@ func (r *ArticleRepository) List(params ArticleListParams) (*ArticleListResult,
 
 	offset := (params.Page - 1) * params.PageSize
 
+	if params.Sort == ArticleSortDifficulty {
+		query = query.Order(difficultyOrder)
+	}
+
 	var articles []model.Article
 	if err := query.Order("created_at DESC").Offset(offset).Limit(params.PageSize).Find(&articles).Error; err != nil {
 		return nil, err
@@ -87,33 +134,242 @@ func (r *ArticleRepository) GetBySlug(slug string) (*model.Article, error) {
 	return &article, nil
 }
 
+// FindByIDs returns the articles with the given IDs, in no particular order
+func (r *ArticleRepository) FindByIDs(ids []uuid.UUID) ([]model.Article, error) {
+	var articles []model.Article
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	err := r.db.Preload("Category").Omit("embedding").Where("id IN ?", ids).Find(&articles).Error
+	return articles, err
+}
+
+// ListAfterID returns up to limit articles with IDs greater than after, in ID order, for full scans
+func (r *ArticleRepository) ListAfterID(after uuid.UUID, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.Omit("embedding").Where("id > ?", after).Order("id ASC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// ListPublished returns up to limit published articles, most recently updated first. With
+// categoryIDs, only articles in those categories are returned.
+func (r *ArticleRepository) ListPublished(categoryIDs []uuid.UUID, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	query := r.db.Preload("Category").Omit("embedding").Where("status = ?", model.ArticleStatusPublished)
+	if categoryIDs != nil {
+		query = query.Where("category_id IN ?", categoryIDs)
+	}
+	err := query.Order("updated_at DESC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
 func (r *ArticleRepository) Create(article *model.Article) error {
-	// Omit embedding field if nil to avoid pgvector empty dimension error
-	if article.Embedding == nil {
-		return r.db.Omit("Embedding").Create(article).Error
+	if err := deriveContentFields(article); err != nil {
+		return err
 	}
-	return r.db.Create(article).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, article.Tags)
+		if err != nil {
+			return err
+		}
+		article.Tags = tags
+		// Omit embedding field if nil to avoid pgvector empty dimension error
+		query := tx
+		if article.Embedding == nil {
+			query = tx.Omit("Embedding")
+		}
+		if err := query.Create(article).Error; err != nil {
+			return err
+		}
+		if err := releaseSlug(tx, article.Slug); err != nil {
+			return err
+		}
+		if err := recountTags(tx, article.Tags); err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityArticle, article.ID, article.Slug, model.ChangeActionCreated)
+	})
 }
 
+// Update saves an article, keeping the content it replaces as a version
 func (r *ArticleRepository) Update(article *model.Article) error {
-	// Omit embedding field if nil to avoid pgvector empty dimension error
-	if article.Embedding == nil {
-		return r.db.Omit("Embedding").Save(article).Error
+	if err := deriveContentFields(article); err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := snapshotVersion(tx, article); err != nil {
+			return err
+		}
+		if err := redirectSlug(tx, article); err != nil {
+			return err
+		}
+		var previous model.Article
+		if err := tx.Select("tags").First(&previous, "id = ?", article.ID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		tags, err := resolveTags(tx, article.Tags)
+		if err != nil {
+			return err
+		}
+		article.Tags = tags
+		// Omit embedding field if nil to avoid pgvector empty dimension error
+		query := tx
+		if article.Embedding == nil {
+			query = tx.Omit("Embedding")
+		}
+		if err := query.Save(article).Error; err != nil {
+			return err
+		}
+		if err := recountTags(tx, append(previous.Tags, article.Tags...)); err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityArticle, article.ID, article.Slug, model.ChangeActionUpdated)
+	})
+}
+
+// LoadTOC fills the table of contents of an article saved before it was derived on save,
+// and stores it
+func (r *ArticleRepository) LoadTOC(article *model.Article) error {
+	if article.TOC != nil {
+		return nil
+	}
+	if err := deriveContentFields(article); err != nil {
+		return err
+	}
+	return r.db.Model(&model.Article{}).Where("id = ?", article.ID).UpdateColumn("toc", article.TOC).Error
+}
+
+// deriveContentFields recomputes the fields derived from an article's content. Meta and
+// OpenGraph fields written by the LLM or an editor are kept.
+func deriveContentFields(article *model.Article) error {
+	toc, err := json.Marshal(markdown.ExtractTOC(article.Content))
+	if err != nil {
+		return fmt.Errorf("failed to encode table of contents: %w", err)
+	}
+	article.TOC = toc
+	// HTML arriving with imported or crawled articles is never trusted; it is always
+	// rendered from the markdown
+	if article.ContentHTML, err = markdown.RenderHTML(article.Content); err != nil {
+		return err
+	}
+	article.ReadingMinutes = markdown.ReadingMinutes(article.Content)
+	article.ContentHash = markdown.ContentHash(article.Content)
+
+	if article.SEOSource == "" || article.SEOSource == model.SEOSourceAuto {
+		article.SEOSource = model.SEOSourceAuto
+		article.MetaDescription = markdown.Description(article.Summary, article.Content)
+		article.OGTitle = article.Title
+		article.OGDescription = article.MetaDescription
+		article.OGImage = markdown.FirstImage(article.Content)
+	}
+	return nil
+}
+
+// FindByContentHash returns the oldest article with the content hash
+func (r *ArticleRepository) FindByContentHash(hash string) (*model.Article, error) {
+	var article model.Article
+	if err := r.db.Where("content_hash = ?", hash).Order("created_at").First(&article).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// FillContentHashes stores the content hash of articles saved before it was derived on save.
+// Returns the number of articles filled.
+func (r *ArticleRepository) FillContentHashes() (int, error) {
+	filled := 0
+	for {
+		var articles []model.Article
+		err := r.db.Select("id", "content").
+			Where("content_hash IS NULL OR content_hash = ''").
+			Limit(200).Find(&articles).Error
+		if err != nil {
+			return filled, err
+		}
+		if len(articles) == 0 {
+			return filled, nil
+		}
+		for _, article := range articles {
+			hash := markdown.ContentHash(article.Content)
+			if err := r.db.Model(&model.Article{}).Where("id = ?", article.ID).UpdateColumn("content_hash", hash).Error; err != nil {
+				return filled, err
+			}
+			filled++
+		}
 	}
-	return r.db.Save(article).Error
 }
 
 func (r *ArticleRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&model.Article{}, "id = ?", id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var article model.Article
+		if err := tx.Select("id", "slug", "tags").First(&article, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Delete(&model.Article{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := recountTags(tx, article.Tags); err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityArticle, id, article.Slug, model.ChangeActionDeleted)
+	})
 }
 
 func (r *ArticleRepository) IncrementViewCount(id uuid.UUID) error {
 	return r.db.Model(&model.Article{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
 }
 
-func (r *ArticleRepository) Search(query string, limit int) ([]model.Article, error) {
+// AddViewCounts applies buffered view increments in one transaction
+func (r *ArticleRepository) AddViewCounts(counts map[uuid.UUID]int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, n := range counts {
+			if err := tx.Model(&model.Article{}).Where("id = ?", id).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PublishDue publishes the scheduled articles whose publish time has passed and returns them.
+// Rows another worker is publishing are skipped.
+func (r *ArticleRepository) PublishDue(now time.Time) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id", "slug", "category_id", "status", "publish_at").
+			Where("status = ? AND publish_at <= ?", model.ArticleStatusScheduled, now).
+			Order("publish_at ASC").
+			Find(&articles).Error; err != nil {
+			return err
+		}
+		for i := range articles {
+			article := &articles[i]
+			if err := tx.Model(&model.Article{}).Where("id = ?", article.ID).
+				Update("status", model.ArticleStatusPublished).Error; err != nil {
+				return err
+			}
+			article.Status = model.ArticleStatusPublished
+			if err := recordChange(tx, model.ChangeEntityArticle, article.ID, article.Slug, model.ChangeActionUpdated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// Search finds articles by keyword, optionally restricted to the given difficulty levels
+func (r *ArticleRepository) Search(query string, limit int, difficulties ...string) ([]model.Article, error) {
 	var articles []model.Article
-	err := r.db.Preload("Category").
+	err := withDifficulties(r.db.Preload("Category"), difficulties).
 		Where("title ILIKE ? OR content ILIKE ? OR summary ILIKE ?", "%"+query+"%", "%"+query+"%", "%"+query+"%").
 		Order("view_count DESC, created_at DESC").
 		Limit(limit).
@@ -121,6 +377,21 @@ func (r *ArticleRepository) Search(query string, limit int) ([]model.Article, er
 	return articles, err
 }
 
+// TitleContains reports whether any article title contains text, ignoring case
+func (r *ArticleRepository) TitleContains(text string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Article{}).Where("title ILIKE ?", "%"+escapeLike(text)+"%").Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes s for use inside a LIKE pattern, so it only matches literally
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 // CountBySlugPrefix counts articles with slugs starting with prefix
 func (r *ArticleRepository) CountBySlugPrefix(prefix string) int64 {
 	var count int64
@@ -164,6 +435,20 @@ func (r *ArticleRepository) ListSimple(page, pageSize int, status string, catego
 	return articles, total, nil
 }
 
+// UpdateDifficulty sets the assessed difficulty level of an article
+func (r *ArticleRepository) UpdateDifficulty(id uuid.UUID, difficulty string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var article model.Article
+		if err := tx.Select("id", "slug").First(&article, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Article{}).Where("id = ?", id).Update("difficulty", difficulty).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityArticle, id, article.Slug, model.ChangeActionUpdated)
+	})
+}
+
 // UpdateEmbedding updates the embedding vector for an article
 func (r *ArticleRepository) UpdateEmbedding(id uuid.UUID, embedding *pgvector.Vector) error {
 	return r.db.Model(&model.Article{}).Where("id = ?", id).Update("embedding", embedding).Error
@@ -213,7 +498,7 @@ func (r *ArticleRepository) FindRelatedArticles(articleID uuid.UUID, limit int)
 }
 
 // SemanticSearch performs semantic search using vector similarity
-func (r *ArticleRepository) SemanticSearch(embedding *pgvector.Vector, limit int, categoryID *uuid.UUID, status string) ([]model.Article, error) {
+func (r *ArticleRepository) SemanticSearch(embedding *pgvector.Vector, limit int, categoryID *uuid.UUID, status string, difficulties []string) ([]model.Article, error) {
 	var articles []model.Article
 
 	query := r.db.Preload("Category").
@@ -225,6 +510,7 @@ func (r *ArticleRepository) SemanticSearch(embedding *pgvector.Vector, limit int
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	query = withDifficulties(query, difficulties)
 
 	// Use gorm.Expr for vector ordering
 	err := query.Order(gorm.Expr("embedding <=> ?", embedding)).
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type ArticleChunkRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleChunkRepository(db *gorm.DB) *ArticleChunkRepository {
+	return &ArticleChunkRepository{db: db}
+}
+
+// ChunkMatch is a chunk found by vector similarity, with the title of its article
+type ChunkMatch struct {
+	model.ArticleChunk
+	ArticleTitle string  `json:"articleTitle"`
+	ArticleSlug  string  `json:"articleSlug"`
+	Distance     float64 `json:"distance"` // Cosine distance to the query; lower is closer
+}
+
+// Replace swaps the chunks of an article for new ones
+func (r *ArticleChunkRepository) Replace(articleID uuid.UUID, chunks []model.ArticleChunk) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", articleID).Delete(&model.ArticleChunk{}).Error; err != nil {
+			return err
+		}
+		if len(chunks) == 0 {
+			return nil
+		}
+		return tx.Create(&chunks).Error
+	})
+}
+
+// IndexedHash returns the content hash the article's chunks were built from, or "" if the
+// article has no chunks or some lack an embedding
+func (r *ArticleChunkRepository) IndexedHash(articleID uuid.UUID) (string, error) {
+	var row struct {
+		ContentHash string
+		Missing     int64
+	}
+	err := r.db.Model(&model.ArticleChunk{}).
+		Select("COALESCE(MIN(content_hash), '') AS content_hash, COUNT(*) FILTER (WHERE embedding IS NULL) AS missing").
+		Where("article_id = ?", articleID).
+		Scan(&row).Error
+	if err != nil {
+		return "", err
+	}
+	if row.Missing > 0 {
+		return "", nil
+	}
+	return row.ContentHash, nil
+}
+
+// Nearest returns the chunks of the given articles closest to an embedding, closest first
+func (r *ArticleChunkRepository) Nearest(articleIDs []uuid.UUID, embedding *pgvector.Vector, limit int) ([]ChunkMatch, error) {
+	if len(articleIDs) == 0 {
+		return nil, nil
+	}
+	if embedding == nil {
+		return nil, errors.New("query embedding is required")
+	}
+	var matches []ChunkMatch
+	err := r.db.Raw(`SELECT c.id, c.article_id, c.position, c.heading, c.content, c.content_hash, c.created_at,
+			a.title AS article_title, a.slug AS article_slug, c.embedding <=> ? AS distance
+		FROM article_chunks c JOIN articles a ON a.id = c.article_id
+		WHERE c.article_id IN ? AND c.embedding IS NOT NULL
+		ORDER BY distance ASC LIMIT ?`, embedding, articleIDs, limit).
+		Scan(&matches).Error
+	return matches, err
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// graphColumns are the article columns loaded for graph nodes
+var graphColumns = []string{"id", "title", "slug", "difficulty", "category_id", "status"}
+
+// ListLinks returns the concept graph links of an article, in either direction
+func (r *ArticleRepository) ListLinks(articleID uuid.UUID) ([]model.ArticleLink, error) {
+	var links []model.ArticleLink
+	err := r.db.Preload("Article", preloadReference).
+		Preload("Linked", preloadReference).
+		Where("article_id = ? OR linked_id = ?", articleID, articleID).
+		Order("type ASC, confidence DESC, created_at ASC").
+		Find(&links).Error
+	return links, err
+}
+
+// AddLink saves a concept graph link. An existing link of the same type between the two
+// articles is updated.
+func (r *ArticleRepository) AddLink(link *model.ArticleLink) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "linked_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source", "confidence", "reason"}),
+	}).Create(link).Error
+}
+
+// RemoveLink deletes a link from or to an article
+func (r *ArticleRepository) RemoveLink(articleID, linkID uuid.UUID) error {
+	return r.db.Where("id = ? AND (article_id = ? OR linked_id = ?)", linkID, articleID, articleID).
+		Delete(&model.ArticleLink{}).Error
+}
+
+// SetLinks replaces the links from an article that came from one source. Links from other
+// sources are kept and win over new links of the same type between the same articles.
+func (r *ArticleRepository) SetLinks(articleID uuid.UUID, source string, links []model.ArticleLink) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ? AND source = ?", articleID, source).Delete(&model.ArticleLink{}).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		for i := range links {
+			links[i].ArticleID = articleID
+			links[i].Source = source
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// Graph returns the articles within depth links of the root, following links and
+// prerequisites in either direction, with the edges among them. Only edges of the given
+// types are followed, or all types if none are given. At most maxNodes articles are
+// returned, nearest first.
+func (r *ArticleRepository) Graph(rootID uuid.UUID, depth int, types []string, maxNodes int) (*model.Graph, error) {
+	depths := map[uuid.UUID]int{rootID: 0}
+	order := []uuid.UUID{rootID}
+	seen := make(map[model.GraphEdge]bool)
+	var edges []model.GraphEdge
+
+	frontier := []uuid.UUID{rootID}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		found, err := r.graphEdges(frontier, types)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []uuid.UUID
+		for _, edge := range found {
+			key := model.GraphEdge{Source: edge.Source, Target: edge.Target, Type: edge.Type}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			edges = append(edges, edge)
+
+			for _, id := range []uuid.UUID{edge.Source, edge.Target} {
+				if _, ok := depths[id]; ok || len(depths) >= maxNodes {
+					continue
+				}
+				depths[id] = d
+				order = append(order, id)
+				next = append(next, id)
+			}
+		}
+		frontier = next
+	}
+
+	var articles []model.Article
+	if err := r.db.Select(graphColumns).Where("id IN ?", order).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]model.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	graph := &model.Graph{Root: rootID, Nodes: []model.GraphNode{}, Edges: []model.GraphEdge{}}
+	for _, id := range order {
+		a, ok := byID[id]
+		if !ok {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, model.GraphNode{
+			ID:         a.ID,
+			Title:      a.Title,
+			Slug:       a.Slug,
+			Difficulty: a.Difficulty,
+			CategoryID: a.CategoryID,
+			Status:     a.Status,
+			Depth:      depths[id],
+		})
+	}
+	// Edges to articles cut off by maxNodes are dropped
+	for _, edge := range edges {
+		if _, ok := byID[edge.Source]; !ok {
+			continue
+		}
+		if _, ok := byID[edge.Target]; !ok {
+			continue
+		}
+		graph.Edges = append(graph.Edges, edge)
+	}
+	return graph, nil
+}
+
+// graphEdges returns the links and prerequisites touching any of the articles, strongest first
+func (r *ArticleRepository) graphEdges(ids []uuid.UUID, types []string) ([]model.GraphEdge, error) {
+	var edges []model.GraphEdge
+
+	query := r.db.Model(&model.ArticleLink{}).
+		Select("article_id AS source, linked_id AS target, type, confidence").
+		Where("article_id IN ? OR linked_id IN ?", ids, ids)
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+	if err := query.Order("confidence DESC, created_at ASC").Scan(&edges).Error; err != nil {
+		return nil, err
+	}
+
+	if len(types) == 0 || slices.Contains(types, model.LinkTypeDependsOn) {
+		var prerequisites []model.GraphEdge
+		if err := r.db.Model(&model.ArticlePrerequisite{}).
+			Select("article_id AS source, prerequisite_id AS target, ? AS type, confidence", model.LinkTypeDependsOn).
+			Where("article_id IN ? OR prerequisite_id IN ?", ids, ids).
+			Order("confidence DESC, created_at ASC").
+			Scan(&prerequisites).Error; err != nil {
+			return nil, err
+		}
+		edges = append(edges, prerequisites...)
+	}
+	return edges, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+// preloadReference preloads an article association with the short reference columns
+func preloadReference(db *gorm.DB) *gorm.DB {
+	return db.Select(prerequisiteColumns)
+}
+
+// ListRelations returns the series memberships and deep-dive links of an article, in
+// either direction
+func (r *ArticleRepository) ListRelations(articleID uuid.UUID) ([]model.ArticleRelation, error) {
+	var relations []model.ArticleRelation
+	err := r.db.Preload("Article", preloadReference).
+		Preload("Related", preloadReference).
+		Where("article_id = ? OR related_id = ?", articleID, articleID).
+		Order("type ASC, series ASC, created_at ASC").
+		Find(&relations).Error
+	return relations, err
+}
+
+// GetRelation returns one article relation
+func (r *ArticleRepository) GetRelation(id uuid.UUID) (*model.ArticleRelation, error) {
+	var relation model.ArticleRelation
+	if err := r.db.First(&relation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// SaveRelation creates or updates an article relation. An article is in a series once and
+// deep-dives into another once, so an existing relation between the same articles is
+// updated instead of duplicated. A series position of 0 appends the article to the series.
+func (r *ArticleRepository) SaveRelation(relation *model.ArticleRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if relation.ID == uuid.Nil {
+			var existing model.ArticleRelation
+			query := tx.Where("type = ? AND article_id = ?", relation.Type, relation.ArticleID)
+			if relation.Type == model.RelationTypeSeries {
+				query = query.Where("series = ?", relation.Series)
+			} else {
+				query = query.Where("related_id = ?", relation.RelatedID)
+			}
+			err := query.First(&existing).Error
+			if err == nil {
+				relation.ID = existing.ID
+				relation.CreatedAt = existing.CreatedAt
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if relation.Type == model.RelationTypeSeries && relation.Position <= 0 {
+			var last int
+			if err := tx.Model(&model.ArticleRelation{}).
+				Where("type = ? AND series = ? AND id <> ?", model.RelationTypeSeries, relation.Series, relation.ID).
+				Select("COALESCE(MAX(position), 0)").
+				Scan(&last).Error; err != nil {
+				return err
+			}
+			relation.Position = last + 1
+		}
+
+		if relation.ID == uuid.Nil {
+			return tx.Create(relation).Error
+		}
+		return tx.Save(relation).Error
+	})
+}
+
+// DeleteRelation deletes one article relation
+func (r *ArticleRepository) DeleteRelation(id uuid.UUID) error {
+	return r.db.Delete(&model.ArticleRelation{}, "id = ?", id).Error
+}
+
+// ListSeries returns the articles of a series in reading order, without their content
+func (r *ArticleRepository) ListSeries(series string) ([]model.ArticleRelation, error) {
+	var relations []model.ArticleRelation
+	err := r.db.Preload("Article", preloadReference).
+		Where("type = ? AND series = ?", model.RelationTypeSeries, series).
+		Order("position ASC, created_at ASC").
+		Find(&relations).Error
+	return relations, err
+}
+
+// LoadRelations fills article.Series with the article's place in each of its series, and
+// article.DeepDives and article.DeepDiveOf with short references to the linked articles
+func (r *ArticleRepository) LoadRelations(article *model.Article) error {
+	relations, err := r.ListRelations(article.ID)
+	if err != nil {
+		return err
+	}
+
+	article.Series, article.DeepDives, article.DeepDiveOf = nil, nil, nil
+	for _, relation := range relations {
+		switch {
+		case relation.Type == model.RelationTypeSeries && relation.ArticleID == article.ID:
+			position, err := r.seriesPosition(relation.Series, article.ID)
+			if err != nil {
+				return err
+			}
+			if position != nil {
+				article.Series = append(article.Series, *position)
+			}
+		case relation.Type == model.RelationTypeDeepDive && relation.ArticleID == article.ID && relation.Related != nil:
+			article.DeepDiveOf = append(article.DeepDiveOf, *relation.Related)
+		case relation.Type == model.RelationTypeDeepDive && relation.Article != nil:
+			article.DeepDives = append(article.DeepDives, *relation.Article)
+		}
+	}
+	return nil
+}
+
+// seriesPosition finds an article's place in a series and the articles before and after it
+func (r *ArticleRepository) seriesPosition(series string, articleID uuid.UUID) (*model.SeriesPosition, error) {
+	members, err := r.ListSeries(series)
+	if err != nil {
+		return nil, err
+	}
+	for i, member := range members {
+		if member.ArticleID != articleID {
+			continue
+		}
+		position := &model.SeriesPosition{Series: series, Position: i + 1, Total: len(members)}
+		if i > 0 {
+			position.Previous = members[i-1].Article
+		}
+		if i+1 < len(members) {
+			position.Next = members[i+1].Article
+		}
+		return position, nil
+	}
+	return nil, nil
+}
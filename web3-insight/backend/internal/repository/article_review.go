@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidTransition is returned when the review workflow cannot move an article to a status
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// TransitionStatus moves an article through the review workflow and records the move with
+// the reviewer's note. review.ToStatus is the new status; article.PublishAt is stored with
+// it, for scheduling. The stored status is checked under a row lock, so concurrent
+// reviewers cannot both move the same article.
+func (r *ArticleRepository) TransitionStatus(article *model.Article, review *model.ArticleReview) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Article
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "slug", "status").
+			First(&current, "id = ?", article.ID).Error; err != nil {
+			return err
+		}
+		if !model.CanTransitionArticle(current.Status, review.ToStatus) {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current.Status, review.ToStatus)
+		}
+
+		review.ArticleID = article.ID
+		review.FromStatus = current.Status
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Article{}).Where("id = ?", article.ID).Updates(map[string]interface{}{
+			"status":      review.ToStatus,
+			"review_note": review.Note,
+			"publish_at":  article.PublishAt,
+		}).Error; err != nil {
+			return err
+		}
+		article.Status = review.ToStatus
+		article.ReviewNote = review.Note
+		return recordChange(tx, model.ChangeEntityArticle, article.ID, current.Slug, model.ChangeActionUpdated)
+	})
+}
+
+// ListReviews returns the review history of an article, oldest first
+func (r *ArticleRepository) ListReviews(articleID uuid.UUID) ([]model.ArticleReview, error) {
+	var reviews []model.ArticleReview
+	err := r.db.Where("article_id = ?", articleID).Order("created_at ASC").Find(&reviews).Error
+	return reviews, err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRevisionResolved is returned when a revision was already applied or rejected
+var ErrRevisionResolved = errors.New("revision already resolved")
+
+// CreateRevision stores a proposed change to an article. A news item proposes at most one
+// change per article, so a retried pipeline run does not propose its change twice.
+func (r *ArticleRepository) CreateRevision(revision *model.ArticleRevision) error {
+	if revision.NewsID != nil {
+		var existing model.ArticleRevision
+		err := r.db.Where("article_id = ? AND news_id = ?", revision.ArticleID, revision.NewsID).First(&existing).Error
+		if err == nil {
+			*revision = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return r.db.Create(revision).Error
+}
+
+// ListRevisions returns the proposed changes to an article, newest first, optionally only
+// those with a given status
+func (r *ArticleRepository) ListRevisions(articleID uuid.UUID, status string) ([]model.ArticleRevision, error) {
+	var revisions []model.ArticleRevision
+	query := r.db.Where("article_id = ?", articleID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision returns one proposed change to an article
+func (r *ArticleRepository) GetRevision(articleID, id uuid.UUID) (*model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	if err := r.db.Where("article_id = ? AND id = ?", articleID, id).First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ResolveRevision applies or rejects a pending revision, as revision.Status says, and records
+// the reviewer. An applied revision's section is appended to the article, which is saved as
+// any update is, keeping the replaced content as a version. The revision row stays locked
+// until the article is saved, so a revision is never applied twice.
+func (r *ArticleRepository) ResolveRevision(revision *model.ArticleRevision) (*model.Article, error) {
+	var article *model.Article
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.ArticleRevision
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			First(&current, "id = ?", revision.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != model.RevisionStatusPending {
+			return fmt.Errorf("%w: %s", ErrRevisionResolved, current.Status)
+		}
+
+		if revision.Status == model.RevisionStatusApplied {
+			var err error
+			if article, err = applyRevision(tx, revision); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.ArticleRevision{}).Where("id = ?", revision.ID).Updates(map[string]interface{}{
+			"status":   revision.Status,
+			"reviewer": revision.Reviewer,
+			"note":     revision.Note,
+		}).Error
+	})
+	return article, err
+}
+
+// applyRevision appends a revision's section to its article and saves the article
+func applyRevision(tx *gorm.DB, revision *model.ArticleRevision) (*model.Article, error) {
+	repo := &ArticleRepository{db: tx}
+	article, err := repo.GetByID(revision.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	article.Content = strings.TrimRight(article.Content, "\n") + "\n\n" + strings.TrimSpace(revision.Section) + "\n"
+	article.EditedBy = model.EditedByAI
+	for _, url := range append([]string{revision.SourceURL}, revision.SourceURLs...) {
+		if url != "" && !containsURL(article.SourceURLs, url) {
+			article.SourceURLs = append(article.SourceURLs, url)
+		}
+	}
+	if err := repo.Update(article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// containsURL reports whether urls contains url
+func containsURL(urls []string, url string) bool {
+	for _, u := range urls {
+		if u == url {
+			return true
+		}
+	}
+	return false
+}
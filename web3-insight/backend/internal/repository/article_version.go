@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListVersions returns the earlier versions of an article, newest first, without their content
+func (r *ArticleRepository) ListVersions(articleID uuid.UUID) ([]model.ArticleVersion, error) {
+	var versions []model.ArticleVersion
+	err := r.db.Select("id", "article_id", "version", "edited_by", "change_summary", "created_at").
+		Where("article_id = ?", articleID).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// GetVersion returns one earlier version of an article by its number
+func (r *ArticleRepository) GetVersion(articleID uuid.UUID, version int) (*model.ArticleVersion, error) {
+	var v model.ArticleVersion
+	err := r.db.Where("article_id = ? AND version = ?", articleID, version).First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// snapshotVersion saves the stored content of an article as its next version before an
+// update replaces it. Updates that leave the content as it was are not versioned. The
+// article row stays locked until the transaction ends, so concurrent updates number their
+// versions in turn.
+func snapshotVersion(tx *gorm.DB, article *model.Article) error {
+	var previous model.Article
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "content", "edited_by").
+		First(&previous, "id = ?", article.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // Nothing stored yet to keep
+	}
+	if err != nil {
+		return err
+	}
+	if previous.Content == article.Content {
+		return nil
+	}
+
+	var latest int
+	if err := tx.Model(&model.ArticleVersion{}).
+		Where("article_id = ?", article.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	editedBy := previous.EditedBy
+	if editedBy == "" {
+		editedBy = model.EditedByAI
+	}
+	return tx.Create(&model.ArticleVersion{
+		ArticleID: article.ID,
+		Version:   latest + 1,
+		Content:   previous.Content,
+		EditedBy:  editedBy,
+	}).Error
+}
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/cache"
 	"github.com/user/web3-insight/internal/model"
 	"gorm.io/gorm"
 )
@@ -21,47 +23,95 @@ type LLMCategoryInfo struct {
 	Description string
 }
 
+// ErrCategoryArchived is returned when the classifier would create a category below an
+// archived one
+var ErrCategoryArchived = errors.New("category is archived")
+
+// Shared cache keys for category reads
+const (
+	categoryListCacheKey     = "categories:list"
+	categoryTreeCacheKey     = "categories:tree"
+	categoryFullTreeCacheKey = "categories:tree:all"
+)
+
 type CategoryRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache *cache.Cache
 }
 
 func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+// SetCache enables caching of the category list and tree; every category write invalidates them
+func (r *CategoryRepository) SetCache(c *cache.Cache) {
+	r.cache = c
+}
+
 func (r *CategoryRepository) List() ([]model.Category, error) {
 	var categories []model.Category
+	if r.cache.Get(categoryListCacheKey, &categories) {
+		return categories, nil
+	}
+
 	if err := r.db.Order("sort_order ASC, name ASC").Find(&categories).Error; err != nil {
 		return nil, err
 	}
+	r.cache.Set(categoryListCacheKey, categories)
 	return categories, nil
 }
 
-func (r *CategoryRepository) GetTree() ([]model.Category, error) {
+// GetTree returns the root categories with their descendants. Archived categories and
+// everything below them are left out unless includeArchived is set.
+func (r *CategoryRepository) GetTree(includeArchived bool) ([]model.Category, error) {
+	cacheKey := categoryTreeCacheKey
+	if includeArchived {
+		cacheKey = categoryFullTreeCacheKey
+	}
+
 	var rootCategories []model.Category
-	if err := r.db.Where("parent_id IS NULL").Order("sort_order ASC").Find(&rootCategories).Error; err != nil {
+	if r.cache.Get(cacheKey, &rootCategories) {
+		return rootCategories, nil
+	}
+
+	if err := r.treeLevel(includeArchived).Where("parent_id IS NULL").Find(&rootCategories).Error; err != nil {
 		return nil, err
 	}
 
 	for i := range rootCategories {
-		if err := r.loadChildren(&rootCategories[i]); err != nil {
+		if err := r.loadChildren(&rootCategories[i], includeArchived); err != nil {
 			return nil, err
 		}
 	}
 
+	r.cache.Set(cacheKey, rootCategories)
 	return rootCategories, nil
 }
 
-func (r *CategoryRepository) loadChildren(category *model.Category) error {
+// treeLevel returns a query for one level of the category tree
+func (r *CategoryRepository) treeLevel(includeArchived bool) *gorm.DB {
+	query := r.db.Order("sort_order ASC")
+	if !includeArchived {
+		query = query.Where("NOT archived")
+	}
+	return query
+}
+
+// invalidateCache drops cached category reads after a write
+func (r *CategoryRepository) invalidateCache() {
+	r.cache.Invalidate(categoryListCacheKey, categoryTreeCacheKey, categoryFullTreeCacheKey)
+}
+
+func (r *CategoryRepository) loadChildren(category *model.Category, includeArchived bool) error {
 	var children []model.Category
-	if err := r.db.Where("parent_id = ?", category.ID).Order("sort_order ASC").Find(&children).Error; err != nil {
+	if err := r.treeLevel(includeArchived).Where("parent_id = ?", category.ID).Find(&children).Error; err != nil {
 		return err
 	}
 
 	category.Children = children
 
 	for i := range children {
-		if err := r.loadChildren(&children[i]); err != nil {
+		if err := r.loadChildren(&children[i], includeArchived); err != nil {
 			return err
 		}
 	}
@@ -86,37 +136,81 @@ func (r *CategoryRepository) GetBySlug(slug string) (*model.Category, error) {
 }
 
 func (r *CategoryRepository) Create(category *model.Category) error {
-	return r.db.Create(category).Error
+	return r.create(category)
 }
 
+// Update saves a category. Fails with ErrCategoryCycle if its parent is the category itself
+// or below it.
 func (r *CategoryRepository) Update(category *model.Category) error {
-	return r.db.Save(category).Error
+	defer r.invalidateCache()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkParent(tx, category.ID, category.ParentID); err != nil {
+			return err
+		}
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityCategory, category.ID, category.Slug, model.ChangeActionUpdated)
+	})
 }
 
 func (r *CategoryRepository) Delete(id uuid.UUID) error {
-	// Recursively delete all descendant categories
-	if err := r.deleteDescendants(id); err != nil {
-		return err
-	}
-	return r.db.Delete(&model.Category{}, "id = ?", id).Error
+	defer r.invalidateCache()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Recursively delete all descendant categories
+		if err := r.deleteDescendants(tx, id); err != nil {
+			return err
+		}
+
+		var category model.Category
+		if err := tx.Select("id", "slug").First(&category, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Delete(&model.Category{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityCategory, id, category.Slug, model.ChangeActionDeleted)
+	})
 }
 
-func (r *CategoryRepository) deleteDescendants(parentID uuid.UUID) error {
+func (r *CategoryRepository) deleteDescendants(tx *gorm.DB, parentID uuid.UUID) error {
 	// Find all direct children
 	var children []model.Category
-	if err := r.db.Where("parent_id = ?", parentID).Find(&children).Error; err != nil {
+	if err := tx.Where("parent_id = ?", parentID).Find(&children).Error; err != nil {
 		return err
 	}
 
 	// Recursively delete each child's descendants first
 	for _, child := range children {
-		if err := r.deleteDescendants(child.ID); err != nil {
+		if err := r.deleteDescendants(tx, child.ID); err != nil {
 			return err
 		}
 	}
 
 	// Delete direct children
-	return r.db.Where("parent_id = ?", parentID).Delete(&model.Category{}).Error
+	if err := tx.Where("parent_id = ?", parentID).Delete(&model.Category{}).Error; err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := recordChange(tx, model.ChangeEntityCategory, child.ID, child.Slug, model.ChangeActionDeleted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create inserts a category and logs its creation
+func (r *CategoryRepository) create(category *model.Category) error {
+	defer r.invalidateCache()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityCategory, category.ID, category.Slug, model.ChangeActionCreated)
+	})
 }
 
 func (r *CategoryRepository) Search(query string, limit int) ([]model.Category, error) {
@@ -133,9 +227,46 @@ func (r *CategoryRepository) UpdateArticleCount(id uuid.UUID) error {
 	if err := r.db.Model(&model.Article{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
 		return err
 	}
+	defer r.invalidateCache()
 	return r.db.Model(&model.Category{}).Where("id = ?", id).Update("article_count", count).Error
 }
 
+// FindBlank returns up to limit auto-created categories missing a description or an icon,
+// oldest first
+func (r *CategoryRepository) FindBlank(limit int) ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Where("auto_created AND NOT archived AND (COALESCE(description, '') = '' OR COALESCE(icon, '') = '')").
+		Order("created_at ASC").Limit(limit).
+		Find(&categories).Error
+	return categories, err
+}
+
+// SetDetails updates the description and icon of a category
+func (r *CategoryRepository) SetDetails(category *model.Category) error {
+	defer r.invalidateCache()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Category{}).Where("id = ?", category.ID).
+			Updates(map[string]interface{}{"description": category.Description, "icon": category.Icon}).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityCategory, category.ID, category.Slug, model.ChangeActionUpdated)
+	})
+}
+
+// IsArchived reports whether a category or one of its ancestors is archived
+func (r *CategoryRepository) IsArchived(id uuid.UUID) (bool, error) {
+	var archived bool
+	err := r.db.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, archived FROM categories WHERE id = ?
+			UNION
+			SELECT c.id, c.parent_id, c.archived FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE archived)`, id).
+		Scan(&archived).Error
+	return archived, err
+}
+
 // FindAll returns all categories
 func (r *CategoryRepository) FindAll() ([]model.Category, error) {
 	var categories []model.Category
@@ -209,6 +340,9 @@ func (r *CategoryRepository) CreateCategoryFromLLM(info *LLMCategoryInfo) (*mode
 
 	if err := query.First(&existing).Error; err == nil {
 		// Category already exists
+		if existing.Archived {
+			return nil, false, fmt.Errorf("%w: %s", ErrCategoryArchived, existing.Name)
+		}
 		return &existing, false, nil
 	}
 
@@ -233,7 +367,7 @@ func (r *CategoryRepository) CreateCategoryFromLLM(info *LLMCategoryInfo) (*mode
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := r.db.Create(category).Error; err != nil {
+	if err := r.create(category); err != nil {
 		return nil, false, fmt.Errorf("failed to create category: %w", err)
 	}
 
@@ -283,10 +417,12 @@ func (r *CategoryRepository) FindOrCreateByPath(path string) (*model.Category, b
 				UpdatedAt:   time.Now(),
 			}
 
-			if err := r.db.Create(&cat).Error; err != nil {
+			if err := r.create(&cat); err != nil {
 				return nil, created, fmt.Errorf("failed to create category '%s': %w", name, err)
 			}
 			created = true
+		} else if cat.Archived {
+			return nil, created, fmt.Errorf("%w: %s", ErrCategoryArchived, name)
 		}
 
 		current = &cat
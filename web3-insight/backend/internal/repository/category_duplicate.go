@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListDuplicates returns the duplicate category pairs with a status, most similar first
+func (r *CategoryRepository) ListDuplicates(status string) ([]model.CategoryDuplicate, error) {
+	var duplicates []model.CategoryDuplicate
+	err := r.db.Preload("Category").Preload("Duplicate").
+		Where("status = ?", status).
+		Order("similarity DESC").
+		Find(&duplicates).Error
+	return duplicates, err
+}
+
+func (r *CategoryRepository) GetDuplicate(id uuid.UUID) (*model.CategoryDuplicate, error) {
+	var duplicate model.CategoryDuplicate
+	if err := r.db.Preload("Category").Preload("Duplicate").First(&duplicate, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &duplicate, nil
+}
+
+// ReplaceOpenDuplicates replaces the open duplicate pairs with the pairs found by a new
+// detection run. Dismissed pairs stay dismissed.
+func (r *CategoryRepository) ReplaceOpenDuplicates(duplicates []model.CategoryDuplicate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ?", model.DuplicateStatusOpen).Delete(&model.CategoryDuplicate{}).Error; err != nil {
+			return err
+		}
+		if len(duplicates) == 0 {
+			return nil
+		}
+		for i := range duplicates {
+			duplicates[i].Status = model.DuplicateStatusOpen
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}, {Name: "duplicate_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"similarity", "updated_at"}),
+		}).Create(&duplicates).Error
+	})
+}
+
+// DismissDuplicate marks a pair as not duplicates, so detection no longer reports it
+func (r *CategoryRepository) DismissDuplicate(id uuid.UUID) error {
+	result := r.db.Model(&model.CategoryDuplicate{}).Where("id = ?", id).
+		Update("status", model.DuplicateStatusDismissed)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidMerge is returned when a category cannot be merged into the given target
+var ErrInvalidMerge = errors.New("invalid category merge")
+
+// Merge moves the articles and child categories of the source category into the target,
+// deletes the source and records the merge so links to the source resolve to the target.
+// Earlier merges into the source are pointed at the target too. Runs in one transaction.
+func (r *CategoryRepository) Merge(sourceID, targetID uuid.UUID) (*model.CategoryMerge, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: a category cannot be merged into itself", ErrInvalidMerge)
+	}
+
+	defer r.invalidateCache()
+	merge := &model.CategoryMerge{SourceID: sourceID, TargetID: targetID}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var categories []model.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uuid.UUID{sourceID, targetID}).
+			Find(&categories).Error; err != nil {
+			return err
+		}
+		if len(categories) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+		source, target := categories[0], categories[1]
+		if source.ID != sourceID {
+			source, target = target, source
+		}
+
+		// Moving the source's children under one of its own descendants would orphan the tree
+		inside, err := isDescendant(tx, target.ID, source.ID)
+		if err != nil {
+			return err
+		}
+		if inside {
+			return fmt.Errorf("%w: the target is inside the source category", ErrInvalidMerge)
+		}
+		merge.SourceSlug, merge.SourceName = source.Slug, source.Name
+
+		var articles []model.Article
+		if err := tx.Select("id", "slug").Where("category_id = ?", source.ID).Find(&articles).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Article{}).Where("category_id = ?", source.ID).
+			Update("category_id", target.ID).Error; err != nil {
+			return err
+		}
+		for _, article := range articles {
+			if err := recordChange(tx, model.ChangeEntityArticle, article.ID, article.Slug, model.ChangeActionUpdated); err != nil {
+				return err
+			}
+		}
+		merge.ArticlesMoved = len(articles)
+
+		var children []model.Category
+		if err := tx.Select("id", "slug").Where("parent_id = ?", source.ID).Find(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Category{}).Where("parent_id = ?", source.ID).
+			Update("parent_id", target.ID).Error; err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := recordChange(tx, model.ChangeEntityCategory, child.ID, child.Slug, model.ChangeActionUpdated); err != nil {
+				return err
+			}
+		}
+		merge.ChildrenMoved = len(children)
+
+		if err := tx.Model(&model.CategoryMerge{}).Where("target_id = ?", source.ID).
+			Update("target_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(merge).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Category{}).Where("id = ?", target.ID).
+			Update("article_count", gorm.Expr("(SELECT COUNT(*) FROM articles WHERE category_id = ?)", target.ID)).Error; err != nil {
+			return err
+		}
+		if err := recordChange(tx, model.ChangeEntityCategory, target.ID, target.Slug, model.ChangeActionUpdated); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&model.Category{}, "id = ?", source.ID).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityCategory, source.ID, source.Slug, model.ChangeActionDeleted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// GetByMergedRef returns the category a merged category's ID or slug now resolves to
+func (r *CategoryRepository) GetByMergedRef(ref string) (*model.Category, error) {
+	var merge model.CategoryMerge
+	query := r.db.Order("created_at DESC")
+	if id, err := uuid.Parse(ref); err == nil {
+		query = query.Where("source_id = ?", id)
+	} else {
+		query = query.Where("source_slug = ?", ref)
+	}
+	if err := query.First(&merge).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByID(merge.TargetID)
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCategoryCycle is returned when a move would place a category below itself
+var ErrCategoryCycle = errors.New("category would become its own ancestor")
+
+// CategoryOrder is the sort position of one category
+type CategoryOrder struct {
+	ID        uuid.UUID `json:"id" binding:"required"`
+	SortOrder int       `json:"sortOrder"`
+}
+
+// Move changes the parent of a category; a nil parent makes it a root category. The
+// category's sort order is set to place it last among its new siblings unless sortOrder
+// is given. Fails with ErrCategoryCycle if the parent is the category or below it.
+func (r *CategoryRepository) Move(id uuid.UUID, parentID *uuid.UUID, sortOrder *int) (*model.Category, error) {
+	defer r.invalidateCache()
+	var category model.Category
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := checkParent(tx, id, parentID); err != nil {
+			return err
+		}
+
+		if sortOrder != nil {
+			category.SortOrder = *sortOrder
+		} else if !sameParent(category.ParentID, parentID) {
+			siblings := tx.Model(&model.Category{}).Where("id <> ?", id)
+			if parentID == nil {
+				siblings = siblings.Where("parent_id IS NULL")
+			} else {
+				siblings = siblings.Where("parent_id = ?", *parentID)
+			}
+			var last int
+			if err := siblings.Select("COALESCE(MAX(sort_order), -1)").Scan(&last).Error; err != nil {
+				return err
+			}
+			category.SortOrder = last + 1
+		}
+		category.ParentID = parentID
+
+		if err := tx.Model(&category).Updates(map[string]interface{}{
+			"parent_id":  category.ParentID,
+			"sort_order": category.SortOrder,
+		}).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, model.ChangeEntityCategory, category.ID, category.Slug, model.ChangeActionUpdated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Reorder sets the sort order of several categories in one transaction. Fails without
+// changing anything if any of the categories does not exist.
+func (r *CategoryRepository) Reorder(orders []CategoryOrder) error {
+	defer r.invalidateCache()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, order := range orders {
+			var category model.Category
+			if err := tx.Select("id", "slug").First(&category, "id = ?", order.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("category %s not found: %w", order.ID, err)
+				}
+				return err
+			}
+			if err := tx.Model(&model.Category{}).Where("id = ?", order.ID).
+				Update("sort_order", order.SortOrder).Error; err != nil {
+				return err
+			}
+			if err := recordChange(tx, model.ChangeEntityCategory, category.ID, category.Slug, model.ChangeActionUpdated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkParent returns ErrCategoryCycle if a parent is the category itself or below it
+func checkParent(tx *gorm.DB, id uuid.UUID, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return ErrCategoryCycle
+	}
+	below, err := isDescendant(tx, *parentID, id)
+	if err != nil {
+		return err
+	}
+	if below {
+		return ErrCategoryCycle
+	}
+	return nil
+}
+
+// sameParent reports whether two parent references point at the same category
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// SubtreeIDs returns the ID of a category and of every category below it
+func (r *CategoryRepository) SubtreeIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`, id).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// isDescendant reports whether a category is below another in the tree
+func isDescendant(tx *gorm.DB, id, ancestorID uuid.UUID) (bool, error) {
+	var found bool
+	err := tx.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id FROM categories WHERE id = ?
+			UNION
+			SELECT c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE parent_id = ?)`, id, ancestorID).
+		Scan(&found).Error
+	return found, err
+}
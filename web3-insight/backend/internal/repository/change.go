@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type ChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewChangeRepository(db *gorm.DB) *ChangeRepository {
+	return &ChangeRepository{db: db}
+}
+
+type ChangeListParams struct {
+	Since      *time.Time
+	After      uint64 // Only changes with a greater ID
+	EntityType string
+	Limit      int
+}
+
+type ChangeListResult struct {
+	Changes []model.ContentChange `json:"changes"`
+	Cursor  uint64                `json:"cursor"` // Pass as cursor to fetch the next page
+	HasMore bool                  `json:"hasMore"`
+}
+
+// List returns the latest change of every entity changed in the window, oldest first.
+// Repeated changes to one entity collapse into its most recent one.
+func (r *ChangeRepository) List(params ChangeListParams) (*ChangeListResult, error) {
+	if params.Limit <= 0 {
+		params.Limit = 500
+	}
+	if params.Limit > 5000 {
+		params.Limit = 5000
+	}
+
+	window := r.db.Model(&model.ContentChange{}).Where("id > ?", params.After)
+	if params.Since != nil {
+		window = window.Where("created_at > ?", params.Since)
+	}
+	if params.EntityType != "" {
+		window = window.Where("entity_type = ?", params.EntityType)
+	}
+	latest := window.Select("DISTINCT ON (entity_type, entity_id) *").Order("entity_type, entity_id, id DESC")
+
+	var changes []model.ContentChange
+	if err := r.db.Table("(?) AS latest", latest).Order("id ASC").Limit(params.Limit + 1).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	result := &ChangeListResult{Changes: changes, Cursor: params.After}
+	if len(changes) > params.Limit {
+		result.Changes = changes[:params.Limit]
+		result.HasMore = true
+	}
+	if n := len(result.Changes); n > 0 {
+		result.Cursor = result.Changes[n-1].ID
+	}
+	return result, nil
+}
+
+// LatestID returns the ID of the newest change, or 0 if the log is empty
+func (r *ChangeRepository) LatestID() (uint64, error) {
+	var id uint64
+	err := r.db.Model(&model.ContentChange{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// recordChange appends an entry to the content change log
+func recordChange(tx *gorm.DB, entityType string, id uuid.UUID, slug, action string) error {
+	return tx.Create(&model.ContentChange{
+		EntityType: entityType,
+		EntityID:   id,
+		Action:     action,
+		Slug:       slug,
+	}).Error
+}
@@ -3,19 +3,29 @@ package repository
 import (
 	"encoding/json"
 
+	"github.com/user/web3-insight/internal/cache"
 	"github.com/user/web3-insight/internal/model"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+// configMapCacheKey is the shared cache key of the config map
+const configMapCacheKey = "config:map"
+
 type ConfigRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache *cache.Cache
 }
 
 func NewConfigRepository(db *gorm.DB) *ConfigRepository {
 	return &ConfigRepository{db: db}
 }
 
+// SetCache enables caching of the config map; every config write invalidates it
+func (r *ConfigRepository) SetCache(c *cache.Cache) {
+	r.cache = c
+}
+
 func (r *ConfigRepository) GetAll() ([]model.Config, error) {
 	var configs []model.Config
 	if err := r.db.Order("key ASC").Find(&configs).Error; err != nil {
@@ -33,6 +43,8 @@ func (r *ConfigRepository) Get(key string) (*model.Config, error) {
 }
 
 func (r *ConfigRepository) Set(key, value, description string) error {
+	defer r.cache.Invalidate(configMapCacheKey)
+
 	var config model.Config
 	result := r.db.First(&config, "key = ?", key)
 
@@ -62,10 +74,16 @@ func (r *ConfigRepository) Set(key, value, description string) error {
 }
 
 func (r *ConfigRepository) Delete(key string) error {
+	defer r.cache.Invalidate(configMapCacheKey)
 	return r.db.Delete(&model.Config{}, "key = ?", key).Error
 }
 
 func (r *ConfigRepository) GetMap() (map[string]string, error) {
+	var cached map[string]string
+	if r.cache.Get(configMapCacheKey, &cached) {
+		return cached, nil
+	}
+
 	configs, err := r.GetAll()
 	if err != nil {
 		return nil, err
@@ -81,10 +99,12 @@ func (r *ConfigRepository) GetMap() (map[string]string, error) {
 			result[c.Key] = value
 		}
 	}
+	r.cache.Set(configMapCacheKey, result)
 	return result, nil
 }
 
 func (r *ConfigRepository) SetMultiple(configs map[string]string) error {
+	defer r.cache.Invalidate(configMapCacheKey)
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		for key, value := range configs {
 			var config model.Config
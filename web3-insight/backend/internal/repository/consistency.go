@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type ConsistencyReportRepository struct {
+	db *gorm.DB
+}
+
+func NewConsistencyReportRepository(db *gorm.DB) *ConsistencyReportRepository {
+	return &ConsistencyReportRepository{db: db}
+}
+
+func (r *ConsistencyReportRepository) Create(report *model.ConsistencyReport) error {
+	return r.db.Create(report).Error
+}
+
+func (r *ConsistencyReportRepository) GetByID(id uuid.UUID) (*model.ConsistencyReport, error) {
+	var report model.ConsistencyReport
+	if err := r.db.First(&report, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// List returns the most recent reports, newest first
+func (r *ConsistencyReportRepository) List(limit int) ([]model.ConsistencyReport, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	var reports []model.ConsistencyReport
+	err := r.db.Order("finished_at DESC").Limit(limit).Find(&reports).Error
+	return reports, err
+}
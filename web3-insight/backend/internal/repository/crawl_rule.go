@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type CrawlRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCrawlRuleRepository(db *gorm.DB) *CrawlRuleRepository {
+	return &CrawlRuleRepository{db: db}
+}
+
+func (r *CrawlRuleRepository) List() ([]model.CrawlRule, error) {
+	var rules []model.CrawlRule
+	err := r.db.Order("domain").Find(&rules).Error
+	return rules, err
+}
+
+func (r *CrawlRuleRepository) FindByID(id uuid.UUID) (*model.CrawlRule, error) {
+	var rule model.CrawlRule
+	if err := r.db.First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *CrawlRuleRepository) FindByDomain(domain string) (*model.CrawlRule, error) {
+	var rule model.CrawlRule
+	if err := r.db.First(&rule, "domain = ?", domain).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *CrawlRuleRepository) Create(rule *model.CrawlRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *CrawlRuleRepository) Update(rule *model.CrawlRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *CrawlRuleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.CrawlRule{}, "id = ?", id).Error
+}
@@ -24,6 +24,15 @@ func (r *DataSourceRepository) FindByID(id uuid.UUID) (*model.DataSource, error)
 	return &source, nil
 }
 
+// FindByName returns the data source with the given name, which news items store as their source
+func (r *DataSourceRepository) FindByName(name string) (*model.DataSource, error) {
+	var source model.DataSource
+	if err := r.db.First(&source, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
 func (r *DataSourceRepository) FindByType(sourceType string) ([]model.DataSource, error) {
 	var sources []model.DataSource
 	if err := r.db.Where("type = ? AND enabled = ?", sourceType, true).Find(&sources).Error; err != nil {
@@ -69,8 +78,14 @@ func (r *DataSourceRepository) Update(source *model.DataSource) error {
 	return r.db.Save(source).Error
 }
 
+// Delete removes a data source and its stats
 func (r *DataSourceRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&model.DataSource{}, "id = ?", id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.SourceStats{}, "source_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.DataSource{}, "id = ?", id).Error
+	})
 }
 
 func (r *DataSourceRepository) List() ([]model.DataSource, error) {
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type ExperimentRepository struct {
+	db *gorm.DB
+}
+
+func NewExperimentRepository(db *gorm.DB) *ExperimentRepository {
+	return &ExperimentRepository{db: db}
+}
+
+// VariantResult aggregates the runs of one experiment variant
+type VariantResult struct {
+	VariantID       uuid.UUID `json:"variantId"`
+	Name            string    `json:"name"`
+	Prompt          string    `json:"prompt,omitempty"`
+	Model           string    `json:"model,omitempty"`
+	Traffic         int       `json:"traffic"`
+	Runs            int64     `json:"runs"`
+	Failures        int64     `json:"failures"`
+	Scored          int64     `json:"scored"`
+	AvgQualityScore *float64  `json:"avgQualityScore"`
+	AvgTokens       float64   `json:"avgTokens"`
+	AvgCostUSD      float64   `json:"avgCostUsd"`
+	AvgDurationMs   float64   `json:"avgDurationMs"`
+}
+
+// ExperimentResults is an experiment with per-variant aggregates and its latest runs
+type ExperimentResults struct {
+	Experiment *model.Experiment     `json:"experiment"`
+	Variants   []VariantResult       `json:"variants"`
+	RecentRuns []model.ExperimentRun `json:"recentRuns"`
+}
+
+func (r *ExperimentRepository) List(status string) ([]model.Experiment, error) {
+	var experiments []model.Experiment
+	query := r.db.Preload("Variants")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&experiments).Error; err != nil {
+		return nil, err
+	}
+	return experiments, nil
+}
+
+func (r *ExperimentRepository) GetByID(id uuid.UUID) (*model.Experiment, error) {
+	var experiment model.Experiment
+	if err := r.db.Preload("Variants").First(&experiment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+// FindActiveByTask returns the most recent active experiment for a task
+func (r *ExperimentRepository) FindActiveByTask(task string) (*model.Experiment, error) {
+	var experiment model.Experiment
+	if err := r.db.Preload("Variants").
+		Where("task = ? AND status = ?", task, model.ExperimentStatusActive).
+		Order("created_at DESC").
+		First(&experiment).Error; err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+// Create stores an experiment together with its variants
+func (r *ExperimentRepository) Create(experiment *model.Experiment) error {
+	return r.db.Create(experiment).Error
+}
+
+func (r *ExperimentRepository) UpdateStatus(id uuid.UUID, status string) error {
+	return r.db.Model(&model.Experiment{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// Delete removes an experiment, its variants and its runs
+func (r *ExperimentRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ExperimentRun{}, "experiment_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.ExperimentVariant{}, "experiment_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Experiment{}, "id = ?", id).Error
+	})
+}
+
+func (r *ExperimentRepository) CreateRun(run *model.ExperimentRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *ExperimentRepository) GetRun(id uuid.UUID) (*model.ExperimentRun, error) {
+	var run model.ExperimentRun
+	if err := r.db.First(&run, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// SetRunScore overrides the quality score of a run
+func (r *ExperimentRepository) SetRunScore(id uuid.UUID, score float64) error {
+	return r.db.Model(&model.ExperimentRun{}).Where("id = ?", id).Update("quality_score", score).Error
+}
+
+// GetResults aggregates runs per variant
+func (r *ExperimentRepository) GetResults(id uuid.UUID) (*ExperimentResults, error) {
+	experiment, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		VariantID       uuid.UUID
+		Runs            int64
+		Failures        int64
+		Scored          int64
+		AvgQualityScore *float64
+		AvgTokens       float64
+		AvgCostUSD      float64
+		AvgDurationMs   float64
+	}
+	if err := r.db.Model(&model.ExperimentRun{}).
+		Select(`variant_id,
+			COUNT(*) AS runs,
+			COUNT(*) FILTER (WHERE error <> '') AS failures,
+			COUNT(quality_score) AS scored,
+			AVG(quality_score) AS avg_quality_score,
+			COALESCE(AVG(tokens_used) FILTER (WHERE error = ''), 0) AS avg_tokens,
+			COALESCE(AVG(cost_usd) FILTER (WHERE error = ''), 0) AS avg_cost_usd,
+			COALESCE(AVG(duration_ms), 0) AS avg_duration_ms`).
+		Where("experiment_id = ?", id).
+		Group("variant_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	results := &ExperimentResults{Experiment: experiment}
+	for _, variant := range experiment.Variants {
+		result := VariantResult{
+			VariantID: variant.ID,
+			Name:      variant.Name,
+			Prompt:    variant.Prompt,
+			Model:     variant.Model,
+			Traffic:   variant.Traffic,
+		}
+		for _, row := range rows {
+			if row.VariantID == variant.ID {
+				result.Runs = row.Runs
+				result.Failures = row.Failures
+				result.Scored = row.Scored
+				result.AvgQualityScore = row.AvgQualityScore
+				result.AvgTokens = row.AvgTokens
+				result.AvgCostUSD = row.AvgCostUSD
+				result.AvgDurationMs = row.AvgDurationMs
+			}
+		}
+		results.Variants = append(results.Variants, result)
+	}
+
+	if err := r.db.Where("experiment_id = ?", id).
+		Order("created_at DESC").
+		Limit(20).
+		Find(&results.RecentRuns).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
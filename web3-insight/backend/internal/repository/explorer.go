@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/model"
 	"gorm.io/gorm"
@@ -14,24 +16,105 @@ func NewExplorerRepository(db *gorm.DB) *ExplorerRepository {
 	return &ExplorerRepository{db: db}
 }
 
-// List returns all explorer research entries with optional filters
-func (r *ExplorerRepository) List(chainName, status string, limit int) ([]model.ExplorerResearch, error) {
-	var explorers []model.ExplorerResearch
+// Explorer list sort keys
+const (
+	ExplorerSortChain       = "chain"
+	ExplorerSortPopularity  = "popularity"
+	ExplorerSortLastUpdated = "last_updated"
+	ExplorerSortDueDate     = "due_date"
+)
+
+// explorerSortColumns maps sort keys to columns and their default direction
+var explorerSortColumns = map[string]struct {
+	column string
+	desc   bool
+}{
+	ExplorerSortChain:       {"chain_name", false},
+	ExplorerSortPopularity:  {"popularity_score", true},
+	ExplorerSortLastUpdated: {"last_updated", true},
+	ExplorerSortDueDate:     {"due_date", false},
+}
+
+type ExplorerListParams struct {
+	ChainNames []string
+	ChainTypes []string // L1, L2, sidechain
+	Statuses   []string // pending, in_progress, completed
+	Assignees  []string
+	Sort       string // chain (default), popularity, last_updated or due_date
+	Order      string // asc or desc; defaults to the sort key's natural direction
+	Page       int
+	PageSize   int
+}
+
+type ExplorerListResult struct {
+	Explorers []model.ExplorerResearch `json:"explorers"`
+	Total     int64                    `json:"total"`
+	Page      int                      `json:"page"`
+	PageSize  int                      `json:"pageSize"`
+}
 
+// List returns a page of explorer research entries with optional multi-value filters
+func (r *ExplorerRepository) List(params ExplorerListParams) (*ExplorerListResult, error) {
 	query := r.db.Model(&model.ExplorerResearch{})
 
-	if chainName != "" {
-		query = query.Where("chain_name = ?", chainName)
+	if len(params.ChainNames) > 0 {
+		query = query.Where("chain_name IN ?", params.ChainNames)
+	}
+	if len(params.ChainTypes) > 0 {
+		query = query.Where("chain_type IN ?", params.ChainTypes)
 	}
-	if status != "" {
-		query = query.Where("research_status = ?", status)
+	if len(params.Statuses) > 0 {
+		query = query.Where("research_status IN ?", params.Statuses)
 	}
-	if limit > 0 {
-		query = query.Limit(limit)
+	if len(params.Assignees) > 0 {
+		query = query.Where("assignee IN ?", params.Assignees)
 	}
 
-	err := query.Order("chain_name ASC, popularity_score DESC").Find(&explorers).Error
-	return explorers, err
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = 50
+	}
+	if params.PageSize > 200 {
+		params.PageSize = 200
+	}
+
+	sort, ok := explorerSortColumns[params.Sort]
+	if !ok {
+		sort = explorerSortColumns[ExplorerSortChain]
+	}
+	desc := sort.desc
+	switch params.Order {
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	// Secondary keys keep the order stable across pages
+	order := sort.column + " " + direction + ", popularity_score DESC, id ASC"
+
+	var explorers []model.ExplorerResearch
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.Order(order).Offset(offset).Limit(params.PageSize).Find(&explorers).Error; err != nil {
+		return nil, err
+	}
+
+	return &ExplorerListResult{
+		Explorers: explorers,
+		Total:     total,
+		Page:      params.Page,
+		PageSize:  params.PageSize,
+	}, nil
 }
 
 // GetByID returns a single explorer research entry
@@ -93,6 +176,30 @@ func (r *ExplorerRepository) UpdateStatus(id uuid.UUID, status string) error {
 		Update("research_status", status).Error
 }
 
+// BulkAssign sets the assignee and due date of several explorers; an empty assignee
+// or nil due date clears them. Returns the number of entries updated.
+func (r *ExplorerRepository) BulkAssign(ids []uuid.UUID, assignee string, dueDate *time.Time) (int64, error) {
+	result := r.db.Model(&model.ExplorerResearch{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"assignee":     assignee,
+			"due_date":     dueDate,
+			"last_updated": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// BulkUpdateStatus moves several explorers to a research status
+func (r *ExplorerRepository) BulkUpdateStatus(ids []uuid.UUID, status string) (int64, error) {
+	result := r.db.Model(&model.ExplorerResearch{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"research_status": status,
+			"last_updated":    time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
 // UpdateFeatures updates the features JSON of an explorer
 func (r *ExplorerRepository) UpdateFeatures(id uuid.UUID, features interface{}) error {
 	return r.db.Model(&model.ExplorerResearch{}).
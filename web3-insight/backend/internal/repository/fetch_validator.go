@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FetchValidatorRepository struct {
+	db *gorm.DB
+}
+
+func NewFetchValidatorRepository(db *gorm.DB) *FetchValidatorRepository {
+	return &FetchValidatorRepository{db: db}
+}
+
+// FindByURL returns the validators stored for a URL
+func (r *FetchValidatorRepository) FindByURL(url string) (*model.FetchValidator, error) {
+	var validator model.FetchValidator
+	if err := r.db.First(&validator, "url = ?", url).Error; err != nil {
+		return nil, err
+	}
+	return &validator, nil
+}
+
+// Save creates or replaces the validators of a URL
+func (r *FetchValidatorRepository) Save(validator *model.FetchValidator) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url"}},
+		DoUpdates: clause.AssignmentColumns([]string{"etag", "last_modified", "content_hash", "updated_at"}),
+	}).Create(validator).Error
+}
+
+// Touch records that a URL was confirmed unchanged, e.g. by 304 Not Modified
+func (r *FetchValidatorRepository) Touch(url string) error {
+	return r.db.Model(&model.FetchValidator{}).Where("url = ?", url).Update("updated_at", time.Now()).Error
+}
+
+// SetChildren records the child sitemaps a sitemap index lists
+func (r *FetchValidatorRepository) SetChildren(url string, children []string) error {
+	return r.db.Model(&model.FetchValidator{}).Where("url = ?", url).Update("children", pq.StringArray(children)).Error
+}
+
+// Delete removes the validators of a URL, so its next fetch is unconditional
+func (r *FetchValidatorRepository) Delete(url string) error {
+	return r.db.Delete(&model.FetchValidator{}, "url = ?", url).Error
+}
+
+// DeleteCheckedBefore removes the validators of URLs not fetched or confirmed since before,
+// returning how many were removed
+func (r *FetchValidatorRepository) DeleteCheckedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("updated_at < ?", before).Delete(&model.FetchValidator{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type LLMCallRepository struct {
+	db *gorm.DB
+}
+
+func NewLLMCallRepository(db *gorm.DB) *LLMCallRepository {
+	return &LLMCallRepository{db: db}
+}
+
+type LLMCallListParams struct {
+	Task       string
+	Model      string
+	Provider   string
+	Status     string
+	PromptHash string
+	Since      *time.Time
+	Until      *time.Time
+	Page       int
+	PageSize   int
+}
+
+type LLMCallListResult struct {
+	Calls    []model.LLMCall `json:"calls"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"pageSize"`
+}
+
+// LLMLatencyStats aggregates calls of one provider and model
+type LLMLatencyStats struct {
+	Provider      string  `json:"provider"`
+	Model         string  `json:"model"`
+	Calls         int64   `json:"calls"`
+	Errors        int64   `json:"errors"`
+	AvgDurationMs float64 `json:"avgDurationMs"`
+	P50DurationMs float64 `json:"p50DurationMs"`
+	P95DurationMs float64 `json:"p95DurationMs"`
+	InputTokens   int64   `json:"inputTokens"`
+	OutputTokens  int64   `json:"outputTokens"`
+	CostUSD       float64 `json:"costUsd"`
+}
+
+func (r *LLMCallRepository) Create(call *model.LLMCall) error {
+	return r.db.Create(call).Error
+}
+
+// List returns calls newest first, without prompt and response bodies
+func (r *LLMCallRepository) List(params LLMCallListParams) (*LLMCallListResult, error) {
+	query := r.filter(r.db.Model(&model.LLMCall{}), params)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = 50
+	}
+	if params.PageSize > 200 {
+		params.PageSize = 200
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+
+	var calls []model.LLMCall
+	if err := query.Omit("prompt", "response").Order("created_at DESC").Offset(offset).Limit(params.PageSize).Find(&calls).Error; err != nil {
+		return nil, err
+	}
+
+	return &LLMCallListResult{
+		Calls:    calls,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}, nil
+}
+
+func (r *LLMCallRepository) GetByID(id uuid.UUID) (*model.LLMCall, error) {
+	var call model.LLMCall
+	if err := r.db.First(&call, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &call, nil
+}
+
+// LatencyStats aggregates duration, errors, tokens and cost per provider and model
+func (r *LLMCallRepository) LatencyStats(params LLMCallListParams) ([]LLMLatencyStats, error) {
+	var stats []LLMLatencyStats
+	err := r.filter(r.db.Model(&model.LLMCall{}), params).
+		Select(`provider, model,
+			COUNT(*) AS calls,
+			COUNT(*) FILTER (WHERE status = ?) AS errors,
+			COALESCE(AVG(duration_ms), 0) AS avg_duration_ms,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY duration_ms), 0) AS p50_duration_ms,
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY duration_ms), 0) AS p95_duration_ms,
+			COALESCE(SUM(input_tokens), 0) AS input_tokens,
+			COALESCE(SUM(output_tokens), 0) AS output_tokens,
+			COALESCE(SUM(cost_usd), 0) AS cost_usd`, model.LLMCallStatusError).
+		Group("provider, model").
+		Order("provider, model").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// DeleteOlderThan removes calls created before cutoff and returns how many were deleted
+func (r *LLMCallRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&model.LLMCall{})
+	return result.RowsAffected, result.Error
+}
+
+// filter applies the list filters to a query
+func (r *LLMCallRepository) filter(query *gorm.DB, params LLMCallListParams) *gorm.DB {
+	if params.Task != "" {
+		query = query.Where("task = ?", params.Task)
+	}
+	if params.Model != "" {
+		query = query.Where("model = ?", params.Model)
+	}
+	if params.Provider != "" {
+		query = query.Where("provider = ?", params.Provider)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.PromptHash != "" {
+		query = query.Where("prompt_hash = ?", params.PromptHash)
+	}
+	if params.Since != nil {
+		query = query.Where("created_at >= ?", params.Since)
+	}
+	if params.Until != nil {
+		query = query.Where("created_at < ?", params.Until)
+	}
+	return query
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/model"
 	"gorm.io/gorm"
@@ -66,9 +68,38 @@ func (r *NewsRepository) FindBySourceURL(url string) (*model.NewsItem, error) {
 	return &item, nil
 }
 
+// ExistingSourceURLs returns which of the given URLs are already stored
+func (r *NewsRepository) ExistingSourceURLs(urls []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for start := 0; start < len(urls); start += 500 {
+		end := min(start+500, len(urls))
+		var found []string
+		if err := r.db.Model(&model.NewsItem{}).Where("source_url IN ?", urls[start:end]).Pluck("source_url", &found).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range found {
+			existing[u] = true
+		}
+	}
+	return existing, nil
+}
+
 func (r *NewsRepository) FindUnprocessed(limit int) ([]model.NewsItem, error) {
 	var items []model.NewsItem
-	if err := r.db.Where("processed = ?", false).
+	if err := r.db.Where("processed = ? AND COALESCE(filtered, '') = ''", false).
+		Order("fetched_at ASC").
+		Limit(limit).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindPendingSummary returns unprocessed items that have failed summarization fewer than
+// maxAttempts times, leaving out items filtered for low relevance
+func (r *NewsRepository) FindPendingSummary(limit, maxAttempts int) ([]model.NewsItem, error) {
+	var items []model.NewsItem
+	if err := r.db.Where("processed = ? AND summary_attempts < ? AND COALESCE(filtered, '') = ''", false, maxAttempts).
 		Order("fetched_at ASC").
 		Limit(limit).
 		Find(&items).Error; err != nil {
@@ -77,6 +108,122 @@ func (r *NewsRepository) FindUnprocessed(limit int) ([]model.NewsItem, error) {
 	return items, nil
 }
 
+// SetRelevance records the relevance score of an item and, when it scored too low, how it
+// was filtered. Discarded items lose their content.
+func (r *NewsRepository) SetRelevance(id uuid.UUID, score float64, reason, filtered string) error {
+	updates := map[string]interface{}{
+		"relevance_score":  score,
+		"relevance_reason": reason,
+		"filtered":         filtered,
+	}
+	if filtered == model.NewsFilteredDiscard {
+		updates["content"] = ""
+	}
+	return r.db.Model(&model.NewsItem{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+// Release lets a quarantined item through to summarization
+func (r *NewsRepository) Release(id uuid.UUID) error {
+	return r.db.Model(&model.NewsItem{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"filtered": "", "updated_at": time.Now()}).Error
+}
+
+// RecordSummaryFailure increments the failed summarization attempts of an item
+func (r *NewsRepository) RecordSummaryFailure(id uuid.UUID, summaryErr error) error {
+	return r.db.Model(&model.NewsItem{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"summary_attempts": gorm.Expr("summary_attempts + 1"),
+			"summary_error":    summaryErr.Error(),
+		}).Error
+}
+
+// UpdateContent replaces the content of an item whose source changed and queues it to be
+// summarized again
+func (r *NewsRepository) UpdateContent(id uuid.UUID, content string) error {
+	return r.db.Model(&model.NewsItem{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"content":          content,
+			"processed":        false,
+			"summary_attempts": 0,
+			"summary_error":    "",
+			"updated_at":       time.Now(),
+		}).Error
+}
+
+// RefreshContent replaces an item's content without queueing it for summarization again, for
+// changes that leave its summary accurate
+func (r *NewsRepository) RefreshContent(id uuid.UUID, content string) error {
+	return r.db.Model(&model.NewsItem{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"content":    content,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// FindThinContent returns RSS items fetched after since whose content is shorter than
+// minChars and whose source page was never crawled for more, newest first
+func (r *NewsRepository) FindThinContent(since time.Time, minChars, limit int) ([]model.NewsItem, error) {
+	var items []model.NewsItem
+	err := r.db.Where("backfilled_at IS NULL AND update_of IS NULL AND fetched_at > ?", since).
+		Where("char_length(COALESCE(content, '')) < ?", minChars).
+		Where("source_name IN (?)", r.db.Model(&model.DataSource{}).Select("name").Where("type = ?", model.DataSourceTypeRSS)).
+		Order("fetched_at DESC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+// FinishBackfill records a crawl of a thin item's source page. A non-empty content replaces
+// the item's and queues it for summarization again.
+func (r *NewsRepository) FinishBackfill(id uuid.UUID, content, contentBlock string) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"backfilled_at": now,
+		"content_block": contentBlock,
+	}
+	if content != "" {
+		updates["content"] = content
+		updates["processed"] = false
+		updates["summary_attempts"] = 0
+		updates["summary_error"] = ""
+		updates["updated_at"] = now
+	}
+	return r.db.Model(&model.NewsItem{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+// FindRecrawlCandidates returns crawled items not checked for changes since before, oldest
+// check first. Crawled items are those of crawl sources and manual crawls.
+func (r *NewsRepository) FindRecrawlCandidates(before time.Time, limit int) ([]model.NewsItem, error) {
+	var items []model.NewsItem
+	err := r.db.Where("update_of IS NULL AND COALESCE(content_block, '') = ''").
+		Where("source_name = ? OR source_name IN (?)", "manual",
+			r.db.Model(&model.DataSource{}).Select("name").Where("type = ?", model.DataSourceTypeCrawl)).
+		Where("COALESCE(recrawled_at, fetched_at) < ?", before).
+		Order("COALESCE(recrawled_at, fetched_at) ASC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+// FinishRecrawl records a check of a crawled item's page for changes. A non-empty content
+// replaces the item's without queueing it for summarization again, since the change itself
+// is reported by an "updated" item.
+func (r *NewsRepository) FinishRecrawl(id uuid.UUID, content string) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"recrawled_at": now,
+	}
+	if content != "" {
+		updates["content"] = content
+		updates["updated_at"] = now
+	}
+	return r.db.Model(&model.NewsItem{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
 func (r *NewsRepository) MarkProcessed(id uuid.UUID) error {
 	return r.db.Model(&model.NewsItem{}).
 		Where("id = ?", id).
@@ -87,25 +234,143 @@ func (r *NewsRepository) UpdateSummary(id uuid.UUID, summary string, category st
 	return r.db.Model(&model.NewsItem{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"summary":   summary,
-			"category":  category,
-			"tags":      tags,
-			"processed": true,
+			"summary":       summary,
+			"category":      category,
+			"tags":          tags,
+			"processed":     true,
+			"summary_error": "",
 		}).Error
 }
 
-func (r *NewsRepository) List(page, limit int, sourceName string, processed *bool) ([]model.NewsItem, int64, error) {
+// FindPendingPipeline returns summarized items fetched after since that the news-to-article
+// pipeline has not decided on yet, oldest first. Items hiding their content and near-duplicates
+// of a story's canonical item are left out.
+func (r *NewsRepository) FindPendingPipeline(since time.Time, limit int) ([]model.NewsItem, error) {
 	var items []model.NewsItem
-	var total int64
+	err := r.db.Where("processed = ? AND COALESCE(content_block, '') = ''", true).
+		Where(pipelineClaimable, model.PipelineDecisionRunning, time.Now().Add(-pipelineClaimTimeout)).
+		Where("fetched_at > ?", since).
+		Where(canonicalOrUnclustered).
+		Order("fetched_at ASC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+// pipelineClaimTimeout is how long a pipeline run may hold an item before another run can
+// take it over, so items claimed by a worker that died are picked up again
+const pipelineClaimTimeout = time.Hour
+
+// pipelineClaimable matches items no pipeline run has decided on or is running on; its
+// arguments are PipelineDecisionRunning and the claim timeout cutoff
+const pipelineClaimable = "(COALESCE(pipeline_decision, '') = '' OR (pipeline_decision = ? AND pipeline_claimed_at < ?))"
+
+// ClaimPipeline marks an item as running through the pipeline and counts the attempt. It
+// reports false if the item was already decided or another run holds it, so concurrent
+// runs never work on the same item.
+func (r *NewsRepository) ClaimPipeline(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&model.NewsItem{}).
+		Where("id = ?", id).
+		Where(pipelineClaimable, model.PipelineDecisionRunning, time.Now().Add(-pipelineClaimTimeout)).
+		UpdateColumns(map[string]interface{}{
+			"pipeline_decision":   model.PipelineDecisionRunning,
+			"pipeline_attempts":   gorm.Expr("pipeline_attempts + 1"),
+			"pipeline_claimed_at": time.Now(),
+			"updated_at":          time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReleasePipeline hands a claimed item back after a failed run, so a later run retries it.
+// Items that used up model.MaxPipelineAttempts are marked failed instead.
+func (r *NewsRepository) ReleasePipeline(id uuid.UUID) error {
+	return r.db.Model(&model.NewsItem{}).
+		Where("id = ? AND pipeline_decision = ?", id, model.PipelineDecisionRunning).
+		UpdateColumns(map[string]interface{}{
+			"pipeline_decision": gorm.Expr("CASE WHEN pipeline_attempts >= ? THEN ? ELSE '' END", model.MaxPipelineAttempts, model.PipelineDecisionFailed),
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+// SetPipelineDecision records the pipeline outcome of an item and the article it fed into
+func (r *NewsRepository) SetPipelineDecision(id uuid.UUID, decision string, articleID *uuid.UUID) error {
+	return r.db.Model(&model.NewsItem{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"pipeline_decision": decision,
+			"article_id":        articleID,
+		}).Error
+}
+
+// FindForTrending returns up to limit summarized items fetched after since, newest first,
+// with only the fields trend analysis uses. Re-crawl update items are left out.
+func (r *NewsRepository) FindForTrending(since time.Time, limit int) ([]model.NewsItem, error) {
+	var items []model.NewsItem
+	err := r.db.Select("id", "title", "tags", "source_name", "story_id", "fetched_at", "embedding").
+		Where("processed = ? AND update_of IS NULL AND fetched_at > ?", true, since).
+		Order("fetched_at DESC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
 
-	query := r.db.Model(&model.NewsItem{})
+// NewsListFilter narrows a news item listing
+type NewsListFilter struct {
+	SourceName  string
+	Processed   *bool
+	NeedsReview bool   // Only crawled items with low extraction confidence or blocked content
+	Collapse    bool   // Only one item per story: near-duplicates of a canonical item are left out
+	Search      string // Keyword matched against titles, summary and content
+	Category    string
+	Tags        []string   // Items must have all of them, ignoring case
+	From        *time.Time // Published (or else fetched) at or after
+	To          *time.Time // Published (or else fetched) before
+	Filtered    string     // Only items filtered this way; by default filtered items are left out
+}
 
-	if sourceName != "" {
-		query = query.Where("source_name = ?", sourceName)
+// apply adds the filter's conditions to a news item query
+func (f NewsListFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.SourceName != "" {
+		query = query.Where("source_name = ?", f.SourceName)
+	}
+	if f.Filtered != "" {
+		query = query.Where("filtered = ?", f.Filtered)
+	} else {
+		query = query.Where("COALESCE(filtered, '') = ''")
+	}
+	if f.Processed != nil {
+		query = query.Where("processed = ?", *f.Processed)
+	}
+	if f.NeedsReview {
+		query = query.Where("extraction_confidence < ? OR COALESCE(content_block, '') <> ''", model.LowExtractionConfidence)
+	}
+	if f.Collapse {
+		query = query.Where(canonicalOrUnclustered)
+	}
+	if f.Search != "" {
+		pattern := "%" + escapeLike(f.Search) + "%"
+		query = query.Where("title ILIKE ? OR original_title ILIKE ? OR summary ILIKE ? OR content ILIKE ?", pattern, pattern, pattern, pattern)
 	}
-	if processed != nil {
-		query = query.Where("processed = ?", *processed)
+	if f.Category != "" {
+		query = query.Where("category = ?", f.Category)
 	}
+	for _, tag := range f.Tags {
+		query = query.Where("EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE lower(tag) = lower(?))", tag)
+	}
+	if f.From != nil {
+		query = query.Where("COALESCE(published_at, fetched_at) >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("COALESCE(published_at, fetched_at) < ?", *f.To)
+	}
+	return query
+}
+
+func (r *NewsRepository) List(page, limit int, filter NewsListFilter) ([]model.NewsItem, int64, error) {
+	var items []model.NewsItem
+	var total int64
+
+	query := filter.apply(r.db.Model(&model.NewsItem{}))
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -122,6 +387,24 @@ func (r *NewsRepository) List(page, limit int, sourceName string, processed *boo
 	return items, total, nil
 }
 
+// FindByIDs returns the news items with the given IDs, in no particular order
+func (r *NewsRepository) FindByIDs(ids []uuid.UUID) ([]model.NewsItem, error) {
+	var items []model.NewsItem
+	if len(ids) == 0 {
+		return items, nil
+	}
+	err := r.db.Omit("embedding").Where("id IN ?", ids).Find(&items).Error
+	return items, err
+}
+
 func (r *NewsRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&model.NewsItem{}, "id = ?", id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := leaveStory(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.NewsTranslation{}, "news_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.NewsItem{}, "id = ?", id).Error
+	})
 }
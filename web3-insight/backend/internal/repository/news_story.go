@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+// SimilarNewsItem is a news item found by embedding similarity
+type SimilarNewsItem struct {
+	model.NewsItem
+	Similarity float64 `json:"similarity"`
+}
+
+// canonicalOrUnclustered keeps items that are not near-duplicates of another story's item
+const canonicalOrUnclustered = "news_items.story_id IS NULL OR news_items.id IN (SELECT canonical_id FROM news_stories)"
+
+// FindWithoutEmbeddings returns items fetched after since that have no embedding, oldest first
+func (r *NewsRepository) FindWithoutEmbeddings(since time.Time, limit int) ([]model.NewsItem, error) {
+	var items []model.NewsItem
+	err := r.db.Where("embedding IS NULL AND fetched_at > ?", since).
+		Order("fetched_at ASC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+// UpdateEmbedding stores the embedding of an item. The update time is left alone, since
+// embeddings are not synced to the search engine.
+func (r *NewsRepository) UpdateEmbedding(id uuid.UUID, embedding *pgvector.Vector) error {
+	return r.db.Model(&model.NewsItem{}).Where("id = ?", id).UpdateColumn("embedding", embedding).Error
+}
+
+// FindSimilar returns up to limit embedded items fetched between from and to, most similar
+// to the embedding first, leaving out excludeID
+func (r *NewsRepository) FindSimilar(embedding *pgvector.Vector, from, to time.Time, excludeID uuid.UUID, limit int) ([]SimilarNewsItem, error) {
+	var items []SimilarNewsItem
+	err := r.db.Model(&model.NewsItem{}).
+		Select("news_items.*, 1 - (embedding <=> ?) AS similarity", embedding).
+		Where("embedding IS NOT NULL AND id <> ?", excludeID).
+		Where("fetched_at BETWEEN ? AND ?", from, to).
+		Order(gorm.Expr("embedding <=> ?", embedding)).
+		Limit(limit).
+		Scan(&items).Error
+	return items, err
+}
+
+// SearchSimilar returns a page of embedded items matching the filter, most similar to the
+// embedding first, and the number of embedded items matching it
+func (r *NewsRepository) SearchSimilar(embedding *pgvector.Vector, filter NewsListFilter, page, limit int) ([]SimilarNewsItem, int64, error) {
+	var items []SimilarNewsItem
+	var total int64
+
+	query := filter.apply(r.db.Model(&model.NewsItem{}).Where("embedding IS NOT NULL"))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Select("news_items.*, 1 - (embedding <=> ?) AS similarity", embedding).
+		Order(gorm.Expr("embedding <=> ?", embedding)).
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// CreateStory starts a story from two near-duplicate items, keeping the first as canonical
+func (r *NewsRepository) CreateStory(canonical, duplicate *model.NewsItem) (*model.NewsStory, error) {
+	story := &model.NewsStory{
+		CanonicalID: canonical.ID,
+		SourceURLs:  []string{canonical.SourceURL, duplicate.SourceURL},
+		ItemCount:   2,
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(story).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.NewsItem{}).
+			Where("id IN ?", []uuid.UUID{canonical.ID, duplicate.ID}).
+			UpdateColumn("story_id", story.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return story, nil
+}
+
+// AddToStory adds an item to a story and its source URL to the story's sources
+func (r *NewsRepository) AddToStory(storyID uuid.UUID, item *model.NewsItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.NewsItem{}).Where("id = ?", item.ID).UpdateColumn("story_id", storyID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.NewsStory{}).
+			Where("id = ?", storyID).
+			Updates(map[string]interface{}{
+				"source_urls": gorm.Expr("array_append(source_urls, ?)", item.SourceURL),
+				"item_count":  gorm.Expr("item_count + 1"),
+			}).Error
+	})
+}
+
+// ListStories returns stories with their canonical items, most recently grown first
+func (r *NewsRepository) ListStories(page, limit int) ([]model.NewsStory, int64, error) {
+	var stories []model.NewsStory
+	var total int64
+
+	if err := r.db.Model(&model.NewsStory{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+	if err := r.db.Order("updated_at DESC").Offset(offset).Limit(limit).Find(&stories).Error; err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uuid.UUID, len(stories))
+	for i, s := range stories {
+		ids[i] = s.CanonicalID
+	}
+	canonicals, err := r.FindByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uuid.UUID]*model.NewsItem, len(canonicals))
+	for i := range canonicals {
+		byID[canonicals[i].ID] = &canonicals[i]
+	}
+	for i := range stories {
+		stories[i].Canonical = byID[stories[i].CanonicalID]
+	}
+	return stories, total, nil
+}
+
+// GetStory returns a story with all its items, the canonical one first and then by fetch time
+func (r *NewsRepository) GetStory(id uuid.UUID) (*model.NewsStory, error) {
+	var story model.NewsStory
+	if err := r.db.First(&story, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	err := r.db.Omit("embedding").
+		Where("story_id = ?", id).
+		Order(gorm.Expr("id = ? DESC, fetched_at ASC", story.CanonicalID)).
+		Find(&story.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(story.Items) > 0 && story.Items[0].ID == story.CanonicalID {
+		story.Canonical = &story.Items[0]
+	}
+	return &story, nil
+}
+
+// leaveStory removes an item about to be deleted from its story. A story losing its
+// canonical item is handed to the next item collected; one left with a single item is
+// dissolved.
+func leaveStory(tx *gorm.DB, id uuid.UUID) error {
+	var item model.NewsItem
+	err := tx.Select("id", "source_url", "story_id").First(&item, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && item.StoryID == nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var story model.NewsStory
+	if err := tx.First(&story, "id = ?", *item.StoryID).Error; err != nil {
+		return err
+	}
+	var rest []model.NewsItem
+	if err := tx.Select("id", "source_url").
+		Where("story_id = ? AND id <> ?", story.ID, item.ID).
+		Order("fetched_at ASC").
+		Find(&rest).Error; err != nil {
+		return err
+	}
+
+	if len(rest) < 2 {
+		if err := tx.Model(&model.NewsItem{}).Where("story_id = ?", story.ID).UpdateColumn("story_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&story).Error
+	}
+
+	canonical := story.CanonicalID
+	if canonical == item.ID {
+		canonical = rest[0].ID
+	}
+	urls := make(pq.StringArray, 0, len(rest))
+	for _, other := range rest {
+		if other.ID == canonical {
+			urls = append(pq.StringArray{other.SourceURL}, urls...)
+		} else {
+			urls = append(urls, other.SourceURL)
+		}
+	}
+	return tx.Model(&story).Updates(map[string]interface{}{
+		"canonical_id": canonical,
+		"source_urls":  urls,
+		"item_count":   len(rest),
+	}).Error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveTranslation creates or replaces the translation of a news item and links the item to it
+func (r *NewsRepository) SaveTranslation(translation *model.NewsTranslation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "news_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"language", "title", "content", "model_used", "tokens_used", "updated_at"}),
+		}).Create(translation).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.NewsItem{}).
+			Where("id = ?", translation.NewsID).
+			UpdateColumn("translation_id", translation.ID).Error
+	})
+}
+
+// FindTranslation returns the translation of a news item
+func (r *NewsRepository) FindTranslation(newsID uuid.UUID) (*model.NewsTranslation, error) {
+	var translation model.NewsTranslation
+	if err := r.db.First(&translation, "news_id = ?", newsID).Error; err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
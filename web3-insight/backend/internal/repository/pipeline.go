@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pipeline stages, in processing order
+const (
+	PipelineStageIngested   = "ingested"
+	PipelineStageSummarized = "summarized"
+	PipelineStageEmbedded   = "embedded"
+	PipelineStagePublished  = "published"
+)
+
+type PipelineRepository struct {
+	db *gorm.DB
+}
+
+func NewPipelineRepository(db *gorm.DB) *PipelineRepository {
+	return &PipelineRepository{db: db}
+}
+
+// PipelineStage reports how many items reached a stage and how many are waiting to enter it
+type PipelineStage struct {
+	Stage            string     `json:"stage"`
+	Completed        int64      `json:"completed"`
+	Pending          int64      `json:"pending"`
+	Last24h          int64      `json:"last24h"` // Items that entered the upstream stage in the last 24 hours
+	OldestPendingAt  *time.Time `json:"oldestPendingAt"`
+	OldestPendingAge float64    `json:"oldestPendingAgeSeconds"`
+}
+
+type PipelineStats struct {
+	Stages      []PipelineStage `json:"stages"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+}
+
+// stageQuery describes how to measure one stage
+type stageQuery struct {
+	stage     string
+	table     string
+	timeCol   string
+	completed string // Condition for items that reached the stage
+	pending   string // Condition for items waiting to enter the stage
+}
+
+// pipelineStages maps news items (ingested → summarized) and articles (embedded → published)
+var pipelineStages = []stageQuery{
+	{PipelineStageIngested, "news_items", "fetched_at", "TRUE", "FALSE"},
+	{PipelineStageSummarized, "news_items", "fetched_at", "processed = TRUE", "processed = FALSE"},
+	{PipelineStageEmbedded, "articles", "created_at", "embedding IS NOT NULL", "embedding IS NULL"},
+	{PipelineStagePublished, "articles", "created_at", "status = 'published'", "status = 'draft'"},
+}
+
+// GetStats returns per-stage counts and the age of the oldest item waiting at each stage
+func (r *PipelineRepository) GetStats() (*PipelineStats, error) {
+	now := time.Now()
+	stats := &PipelineStats{
+		Stages:      make([]PipelineStage, 0, len(pipelineStages)),
+		GeneratedAt: now,
+	}
+
+	for _, q := range pipelineStages {
+		var row struct {
+			Completed     int64
+			Pending       int64
+			Last24h       int64
+			OldestPending *time.Time
+		}
+		err := r.db.Table(q.table).Select(
+			"COUNT(*) FILTER (WHERE "+q.completed+") AS completed, "+
+				"COUNT(*) FILTER (WHERE "+q.pending+") AS pending, "+
+				"COUNT(*) FILTER (WHERE "+q.timeCol+" > ?) AS last24h, "+
+				"MIN("+q.timeCol+") FILTER (WHERE "+q.pending+") AS oldest_pending",
+			now.Add(-24*time.Hour),
+		).Scan(&row).Error
+		if err != nil {
+			return nil, err
+		}
+
+		stage := PipelineStage{
+			Stage:           q.stage,
+			Completed:       row.Completed,
+			Pending:         row.Pending,
+			Last24h:         row.Last24h,
+			OldestPendingAt: row.OldestPending,
+		}
+		if row.OldestPending != nil {
+			stage.OldestPendingAge = now.Sub(*row.OldestPending).Seconds()
+		}
+		stats.Stages = append(stats.Stages, stage)
+	}
+
+	return stats, nil
+}
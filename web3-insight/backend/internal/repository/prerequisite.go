@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPrerequisiteCycle is returned when a prerequisite would make an article transitively require itself
+var ErrPrerequisiteCycle = errors.New("prerequisite would create a cycle")
+
+// prerequisiteColumns are the article columns loaded for prerequisite references
+var prerequisiteColumns = []string{"id", "title", "slug", "summary", "difficulty", "category_id", "status"}
+
+// ListPrerequisites returns the prerequisites of an article with a short reference to each
+func (r *ArticleRepository) ListPrerequisites(articleID uuid.UUID) ([]model.ArticlePrerequisite, error) {
+	var prerequisites []model.ArticlePrerequisite
+	err := r.db.Preload("Prerequisite", func(db *gorm.DB) *gorm.DB {
+		return db.Select(prerequisiteColumns)
+	}).
+		Where("article_id = ?", articleID).
+		Order("created_at ASC").
+		Find(&prerequisites).Error
+	return prerequisites, err
+}
+
+// LoadPrerequisites fills article.Prerequisites
+func (r *ArticleRepository) LoadPrerequisites(article *model.Article) error {
+	prerequisites, err := r.ListPrerequisites(article.ID)
+	if err != nil {
+		return err
+	}
+	article.Prerequisites = prerequisites
+	return nil
+}
+
+// AddPrerequisite records that prerequisite.PrerequisiteID should be read before prerequisite.ArticleID.
+// An existing link between the two is updated.
+func (r *ArticleRepository) AddPrerequisite(prerequisite *model.ArticlePrerequisite) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		cycle, err := wouldCreateCycle(tx, prerequisite.ArticleID, prerequisite.PrerequisiteID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrPrerequisiteCycle
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "article_id"}, {Name: "prerequisite_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"source", "confidence", "reason"}),
+		}).Create(prerequisite).Error
+	})
+}
+
+// RemovePrerequisite deletes one prerequisite link
+func (r *ArticleRepository) RemovePrerequisite(articleID, prerequisiteID uuid.UUID) error {
+	return r.db.Where("article_id = ? AND prerequisite_id = ?", articleID, prerequisiteID).
+		Delete(&model.ArticlePrerequisite{}).Error
+}
+
+// SetPrerequisites replaces the prerequisites of an article from one source, leaving
+// links from other sources alone. A manual link takes over a link another source already
+// set, so refreshing that source keeps it; other sources never override an existing link.
+// Links that would create a cycle are skipped and returned.
+func (r *ArticleRepository) SetPrerequisites(articleID uuid.UUID, source string, prerequisites []model.ArticlePrerequisite) ([]uuid.UUID, error) {
+	conflict := clause.OnConflict{DoNothing: true}
+	if source == model.PrerequisiteSourceManual {
+		conflict = clause.OnConflict{
+			Columns:   []clause.Column{{Name: "article_id"}, {Name: "prerequisite_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"source", "confidence", "reason"}),
+		}
+	}
+
+	var skipped []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ? AND source = ?", articleID, source).Delete(&model.ArticlePrerequisite{}).Error; err != nil {
+			return err
+		}
+
+		for i := range prerequisites {
+			p := &prerequisites[i]
+			p.ArticleID = articleID
+			p.Source = source
+
+			cycle, err := wouldCreateCycle(tx, p.ArticleID, p.PrerequisiteID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				skipped = append(skipped, p.PrerequisiteID)
+				continue
+			}
+			if err := tx.Clauses(conflict).Create(p).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return skipped, err
+}
+
+// ListPrerequisitesAmong returns the prerequisite links between the given articles
+func (r *ArticleRepository) ListPrerequisitesAmong(ids []uuid.UUID) ([]model.ArticlePrerequisite, error) {
+	var prerequisites []model.ArticlePrerequisite
+	if len(ids) == 0 {
+		return prerequisites, nil
+	}
+	err := r.db.Where("article_id IN ? AND prerequisite_id IN ?", ids, ids).Find(&prerequisites).Error
+	return prerequisites, err
+}
+
+// FindTransitivePrerequisiteIDs returns the IDs of every article the given ones transitively require
+func (r *ArticleRepository) FindTransitivePrerequisiteIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	if len(ids) == 0 {
+		return result, nil
+	}
+	err := r.db.Raw(`
+		WITH RECURSIVE chain(id) AS (
+			SELECT prerequisite_id FROM article_prerequisites WHERE article_id IN ?
+			UNION
+			SELECT ap.prerequisite_id FROM article_prerequisites ap JOIN chain ON ap.article_id = chain.id
+		)
+		SELECT id FROM chain`, ids).Scan(&result).Error
+	return result, err
+}
+
+// FindForLearningPath returns the articles with the given IDs plus those in the category,
+// optionally restricted to difficulty levels, without their content
+func (r *ArticleRepository) FindForLearningPath(ids []uuid.UUID, categoryID *uuid.UUID, difficulties []string) ([]model.Article, error) {
+	var articles []model.Article
+	if len(ids) == 0 && categoryID == nil {
+		return articles, nil
+	}
+
+	query := r.db.Model(&model.Article{}).Preload("Category").Omit("content", "content_html", "embedding")
+	switch {
+	case len(ids) > 0 && categoryID != nil:
+		query = query.Where("id IN ? OR category_id = ?", ids, categoryID)
+	case len(ids) > 0:
+		query = query.Where("id IN ?", ids)
+	default:
+		query = query.Where("category_id = ?", categoryID)
+	}
+	err := withDifficulties(query, difficulties).Order("created_at ASC").Find(&articles).Error
+	return articles, err
+}
+
+// wouldCreateCycle reports whether prerequisiteID already (transitively) requires articleID
+func wouldCreateCycle(tx *gorm.DB, articleID, prerequisiteID uuid.UUID) (bool, error) {
+	if articleID == prerequisiteID {
+		return true, nil
+	}
+	var exists bool
+	err := tx.Raw(`
+		WITH RECURSIVE chain(id) AS (
+			SELECT prerequisite_id FROM article_prerequisites WHERE article_id = ?
+			UNION
+			SELECT ap.prerequisite_id FROM article_prerequisites ap JOIN chain ON ap.article_id = chain.id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = ?)`, prerequisiteID, articleID).Scan(&exists).Error
+	return exists, err
+}
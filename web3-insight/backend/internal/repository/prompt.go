@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromptTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewPromptTemplateRepository(db *gorm.DB) *PromptTemplateRepository {
+	return &PromptTemplateRepository{db: db}
+}
+
+// Get returns the override of a prompt for a persona, or gorm.ErrRecordNotFound
+func (r *PromptTemplateRepository) Get(persona, name string) (*model.PromptTemplate, error) {
+	var template model.PromptTemplate
+	err := r.db.Where("persona = ? AND name = ?", persona, name).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// List returns the overrides of a persona, or of all personas if persona is empty
+func (r *PromptTemplateRepository) List(persona string) ([]model.PromptTemplate, error) {
+	query := r.db.Model(&model.PromptTemplate{})
+	if persona != "" {
+		query = query.Where("persona = ?", persona)
+	}
+
+	var templates []model.PromptTemplate
+	err := query.Order("persona, name").Find(&templates).Error
+	return templates, err
+}
+
+// Personas returns the personas that have at least one override
+func (r *PromptTemplateRepository) Personas() ([]string, error) {
+	var personas []string
+	err := r.db.Model(&model.PromptTemplate{}).Distinct("persona").Order("persona").Pluck("persona", &personas).Error
+	return personas, err
+}
+
+// Save creates or replaces the override of a prompt
+func (r *PromptTemplateRepository) Save(template *model.PromptTemplate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "persona"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "updated_at"}),
+	}).Create(template).Error
+}
+
+// Delete removes the override of a prompt, restoring the built-in text
+func (r *PromptTemplateRepository) Delete(persona, name string) error {
+	return r.db.Where("persona = ? AND name = ?", persona, name).Delete(&model.PromptTemplate{}).Error
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type ResearchSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewResearchSessionRepository(db *gorm.DB) *ResearchSessionRepository {
+	return &ResearchSessionRepository{db: db}
+}
+
+type ResearchSessionListParams struct {
+	Status     string
+	Search     string
+	ScheduleID *uuid.UUID
+	Page       int
+	Limit      int
+}
+
+type ResearchSessionListResult struct {
+	Sessions []model.ResearchSession `json:"sessions"`
+	Total    int64                   `json:"total"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+}
+
+func (r *ResearchSessionRepository) Create(session *model.ResearchSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *ResearchSessionRepository) Update(session *model.ResearchSession) error {
+	return r.db.Omit("Article").Save(session).Error
+}
+
+func (r *ResearchSessionRepository) GetByID(id uuid.UUID) (*model.ResearchSession, error) {
+	var session model.ResearchSession
+	if err := r.db.Preload("Article").First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *ResearchSessionRepository) List(params ResearchSessionListParams) (*ResearchSessionListResult, error) {
+	query := r.db.Model(&model.ResearchSession{})
+
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.Search != "" {
+		query = query.Where("query ILIKE ?", "%"+escapeLike(params.Search)+"%")
+	}
+	if params.ScheduleID != nil {
+		query = query.Where("schedule_id = ?", *params.ScheduleID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Omit the full output in listings to keep responses small
+	var sessions []model.ResearchSession
+	if err := query.Omit("output").Order("created_at DESC").Offset(offset).Limit(params.Limit).Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return &ResearchSessionListResult{
+		Sessions: sessions,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.Limit,
+	}, nil
+}
+
+func (r *ResearchSessionRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.ResearchSession{}, "id = ?", id).Error
+}
+
+type ResearchScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewResearchScheduleRepository(db *gorm.DB) *ResearchScheduleRepository {
+	return &ResearchScheduleRepository{db: db}
+}
+
+func (r *ResearchScheduleRepository) List() ([]model.ResearchSchedule, error) {
+	var schedules []model.ResearchSchedule
+	if err := r.db.Order("created_at DESC").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// ListEnabled returns all schedules that should be registered with the cron scheduler
+func (r *ResearchScheduleRepository) ListEnabled() ([]model.ResearchSchedule, error) {
+	var schedules []model.ResearchSchedule
+	if err := r.db.Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ResearchScheduleRepository) GetByID(id uuid.UUID) (*model.ResearchSchedule, error) {
+	var schedule model.ResearchSchedule
+	if err := r.db.First(&schedule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *ResearchScheduleRepository) Create(schedule *model.ResearchSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+func (r *ResearchScheduleRepository) Update(schedule *model.ResearchSchedule) error {
+	return r.db.Omit("TargetArticle").Save(schedule).Error
+}
+
+func (r *ResearchScheduleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.ResearchSchedule{}, "id = ?", id).Error
+}
+
+// RecordRun stores the outcome of a scheduled run
+func (r *ResearchScheduleRepository) RecordRun(id uuid.UUID, sessionID *uuid.UUID, runErr error) error {
+	updates := map[string]interface{}{
+		"last_run_at":     time.Now(),
+		"last_session_id": sessionID,
+		"last_error":      "",
+	}
+	if runErr != nil {
+		updates["last_error"] = runErr.Error()
+	}
+	return r.db.Model(&model.ResearchSchedule{}).Where("id = ?", id).Updates(updates).Error
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type SearchLogRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchLogRepository(db *gorm.DB) *SearchLogRepository {
+	return &SearchLogRepository{db: db}
+}
+
+// SearchQueryStats aggregates the searches of one normalized query
+type SearchQueryStats struct {
+	Query          string    `json:"query"`
+	Searches       int64     `json:"searches"`
+	ZeroResults    int64     `json:"zeroResults"`
+	AvgHits        float64   `json:"avgHits"`
+	Clicks         int64     `json:"clicks"`
+	ClickThrough   float64   `json:"clickThrough"` // Share of searches with at least one click
+	LastSearchedAt time.Time `json:"lastSearchedAt"`
+}
+
+// SearchTrendPoint counts the searches of one UTC day
+type SearchTrendPoint struct {
+	Day          time.Time `json:"day"`
+	Searches     int64     `json:"searches"`
+	ZeroResults  int64     `json:"zeroResults"`
+	ClickThrough float64   `json:"clickThrough"`
+}
+
+// SearchAnalytics summarizes searches since a time
+type SearchAnalytics struct {
+	Since             time.Time          `json:"since"`
+	Searches          int64              `json:"searches"`
+	ZeroResults       int64              `json:"zeroResults"`
+	ClickThrough      float64            `json:"clickThrough"`
+	TopQueries        []SearchQueryStats `json:"topQueries"`
+	ZeroResultQueries []SearchQueryStats `json:"zeroResultQueries"` // Queries that never returned a hit: content to write next
+	Trend             []SearchTrendPoint `json:"trend"`
+}
+
+func (r *SearchLogRepository) Create(entry *model.SearchLog) error {
+	return r.db.Create(entry).Error
+}
+
+// RecordClick counts a click on a result of a logged search
+func (r *SearchLogRepository) RecordClick(id uuid.UUID) error {
+	result := r.db.Model(&model.SearchLog{}).Where("id = ?", id).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Analytics returns totals, the most frequent queries, queries without results and a daily
+// trend for searches since a time, optionally of one kind
+func (r *SearchLogRepository) Analytics(since time.Time, kind string, limit int) (*SearchAnalytics, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	window := func() *gorm.DB {
+		query := r.db.Model(&model.SearchLog{}).Where("created_at >= ?", since)
+		if kind != "" {
+			query = query.Where("kind = ?", kind)
+		}
+		return query
+	}
+	const queryStats = `normalized AS query,
+		COUNT(*) AS searches,
+		COUNT(*) FILTER (WHERE zero_result) AS zero_results,
+		COALESCE(AVG(hit_count), 0) AS avg_hits,
+		COALESCE(SUM(click_count), 0) AS clicks,
+		COALESCE(AVG(CASE WHEN click_count > 0 THEN 1.0 ELSE 0 END), 0) AS click_through,
+		MAX(created_at) AS last_searched_at`
+
+	analytics := &SearchAnalytics{
+		Since:             since,
+		TopQueries:        []SearchQueryStats{},
+		ZeroResultQueries: []SearchQueryStats{},
+		Trend:             []SearchTrendPoint{},
+	}
+	var totals struct {
+		Searches     int64
+		ZeroResults  int64
+		ClickThrough float64
+	}
+	err := window().Select(`COUNT(*) AS searches,
+		COUNT(*) FILTER (WHERE zero_result) AS zero_results,
+		COALESCE(AVG(CASE WHEN click_count > 0 THEN 1.0 ELSE 0 END), 0) AS click_through`).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	analytics.Searches = totals.Searches
+	analytics.ZeroResults = totals.ZeroResults
+	analytics.ClickThrough = totals.ClickThrough
+
+	if err := window().Select(queryStats).Group("normalized").
+		Order("searches DESC, query").Limit(limit).
+		Scan(&analytics.TopQueries).Error; err != nil {
+		return nil, err
+	}
+
+	if err := window().Select(queryStats).Group("normalized").
+		Having("COUNT(*) FILTER (WHERE NOT zero_result) = 0").
+		Order("searches DESC, query").Limit(limit).
+		Scan(&analytics.ZeroResultQueries).Error; err != nil {
+		return nil, err
+	}
+
+	if err := window().Select(`date_trunc('day', created_at AT TIME ZONE 'UTC') AS day,
+		COUNT(*) AS searches,
+		COUNT(*) FILTER (WHERE zero_result) AS zero_results,
+		COALESCE(AVG(CASE WHEN click_count > 0 THEN 1.0 ELSE 0 END), 0) AS click_through`).
+		Group("day").Order("day").
+		Scan(&analytics.Trend).Error; err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// redirectSlug records the stored slug of an article as a redirect when the article is
+// saved with a new one. Earlier redirects keep pointing at the article, so links to any
+// of its past slugs resolve.
+func redirectSlug(tx *gorm.DB, article *model.Article) error {
+	var previous model.Article
+	err := tx.Select("id", "slug").First(&previous, "id = ?", article.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if previous.Slug == article.Slug {
+		return nil
+	}
+
+	if err := releaseSlug(tx, article.Slug); err != nil {
+		return err
+	}
+	// A slug another article was renamed away from now redirects here instead
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "old_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"article_id", "created_at"}),
+	}).Create(&model.SlugRedirect{OldSlug: previous.Slug, ArticleID: article.ID}).Error
+}
+
+// releaseSlug removes the redirect of a slug an article now uses
+func releaseSlug(tx *gorm.DB, slug string) error {
+	return tx.Delete(&model.SlugRedirect{}, "old_slug = ?", slug).Error
+}
+
+// GetBySlugRedirect returns the article an old slug redirects to
+func (r *ArticleRepository) GetBySlugRedirect(slug string) (*model.Article, error) {
+	var redirect model.SlugRedirect
+	if err := r.db.First(&redirect, "old_slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByID(redirect.ArticleID)
+}
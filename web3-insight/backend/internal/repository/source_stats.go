@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SourceStatsRepository struct {
+	db *gorm.DB
+}
+
+func NewSourceStatsRepository(db *gorm.DB) *SourceStatsRepository {
+	return &SourceStatsRepository{db: db}
+}
+
+// sourceStatsQuery counts the news of each data source by UTC day of collection. Items
+// belong to the source whose name they carry, which is unique. Corrections are counted
+// apart from the items they correct, not as discarded or quarantined items.
+const sourceStatsQuery = `
+SELECT ds.id AS source_id,
+	(n.fetched_at AT TIME ZONE 'UTC')::date AS date,
+	COUNT(*) FILTER (WHERE n.update_of IS NULL) AS items,
+	COUNT(*) FILTER (WHERE n.update_of IS NULL AND n.story_id IS NOT NULL
+		AND n.id NOT IN (SELECT canonical_id FROM news_stories)) AS duplicates,
+	COUNT(*) FILTER (WHERE n.update_of IS NULL AND n.filtered = ?) AS discarded,
+	COUNT(*) FILTER (WHERE n.update_of IS NULL AND n.filtered = ?) AS quarantined,
+	COUNT(n.relevance_score) AS scored,
+	AVG(n.relevance_score) AS avg_relevance,
+	COUNT(*) FILTER (WHERE n.update_of IS NOT NULL) AS corrections
+FROM news_items n
+JOIN data_sources ds ON ds.name = n.source_name
+WHERE n.fetched_at >= ?
+GROUP BY ds.id, (n.fetched_at AT TIME ZONE 'UTC')::date`
+
+// Refresh recounts the stats of every day since the given one and stores them, replacing
+// the earlier counts of those days. Older days keep their counts, even when their news is
+// deleted later. Returns the number of source-days stored.
+func (r *SourceStatsRepository) Refresh(since time.Time) (int, error) {
+	var stats []model.SourceStats
+	err := r.db.Raw(sourceStatsQuery, model.NewsFilteredDiscard, model.NewsFilteredQuarantine, startOfDay(since)).Scan(&stats).Error
+	if err != nil || len(stats) == 0 {
+		return 0, err
+	}
+
+	now := time.Now()
+	for i := range stats {
+		stats[i].UpdatedAt = now
+	}
+	err = r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "source_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"items", "duplicates", "discarded", "quarantined", "scored", "avg_relevance", "corrections", "updated_at",
+		}),
+	}).CreateInBatches(stats, 200).Error
+	return len(stats), err
+}
+
+// ListBySource returns a source's daily stats from the day of since on, oldest first
+func (r *SourceStatsRepository) ListBySource(sourceID uuid.UUID, since time.Time) ([]model.SourceStats, error) {
+	var stats []model.SourceStats
+	err := r.db.Where("source_id = ? AND date >= ?", sourceID, startOfDay(since).Format("2006-01-02")).
+		Order("date ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+// startOfDay returns midnight UTC of a time's UTC day, the days stats are counted by
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SourceStatsSummary totals a source's daily stats, with rates over the items collected
+type SourceStatsSummary struct {
+	Items          int      `json:"items"`
+	Duplicates     int      `json:"duplicates"`
+	Discarded      int      `json:"discarded"`
+	Quarantined    int      `json:"quarantined"`
+	Scored         int      `json:"scored"`
+	Corrections    int      `json:"corrections"`
+	DuplicateRate  float64  `json:"duplicateRate"`
+	DiscardRate    float64  `json:"discardRate"` // Discarded and quarantined items
+	CorrectionRate float64  `json:"correctionRate"`
+	AvgRelevance   *float64 `json:"avgRelevance"`
+}
+
+// SummarizeSourceStats totals daily stats, averaging relevance over all scored items
+func SummarizeSourceStats(days []model.SourceStats) SourceStatsSummary {
+	var summary SourceStatsSummary
+	relevanceSum := 0.0
+	for _, day := range days {
+		summary.Items += day.Items
+		summary.Duplicates += day.Duplicates
+		summary.Discarded += day.Discarded
+		summary.Quarantined += day.Quarantined
+		summary.Corrections += day.Corrections
+		if day.AvgRelevance != nil {
+			summary.Scored += day.Scored
+			relevanceSum += *day.AvgRelevance * float64(day.Scored)
+		}
+	}
+	if summary.Items > 0 {
+		items := float64(summary.Items)
+		summary.DuplicateRate = float64(summary.Duplicates) / items
+		summary.DiscardRate = float64(summary.Discarded+summary.Quarantined) / items
+		summary.CorrectionRate = float64(summary.Corrections) / items
+	}
+	if summary.Scored > 0 {
+		avg := relevanceSum / float64(summary.Scored)
+		summary.AvgRelevance = &avg
+	}
+	return summary
+}
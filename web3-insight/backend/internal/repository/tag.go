@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"github.com/lib/pq"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errors returned by tag changes
+var (
+	ErrTagExists    = errors.New("a tag with this name already exists")
+	ErrInvalidTagOp = errors.New("invalid tag operation")
+)
+
+// tagBackfillBatch is the number of articles normalized per query by NormalizeArticleTags
+const tagBackfillBatch = 200
+
+// matchesTag is the condition for a tag whose name or an alias equals a lowercased name
+const matchesTag = "(lower(name) = ? OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE lower(alias) = ?))"
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// List returns tags matching a search in their name or aliases, most used first
+func (r *TagRepository) List(search string, limit int) ([]model.Tag, error) {
+	var tags []model.Tag
+	query := r.db.Order("usage_count DESC, name ASC")
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("name ILIKE ? OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE alias ILIKE ?)", pattern, pattern)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) GetByID(id uuid.UUID) (*model.Tag, error) {
+	var tag model.Tag
+	if err := r.db.First(&tag, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *TagRepository) GetBySlug(slug string) (*model.Tag, error) {
+	var tag model.Tag
+	if err := r.db.First(&tag, "slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Rename changes the name of a tag and replaces it in every article. The old name becomes
+// an alias, so it keeps resolving to the tag. Fails with ErrTagExists if another tag has the
+// new name; merge the tags instead.
+func (r *TagRepository) Rename(id uuid.UUID, name string) (*model.Tag, error) {
+	name = model.NormalizeTagName(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: the name is empty", ErrInvalidTagOp)
+	}
+
+	var tag model.Tag
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tag, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if tag.Name == name {
+			return nil
+		}
+
+		var clash int64
+		if err := tx.Model(&model.Tag{}).Where("id <> ?", id).
+			Where(matchesTag, strings.ToLower(name), strings.ToLower(name)).
+			Count(&clash).Error; err != nil {
+			return err
+		}
+		if clash > 0 {
+			return ErrTagExists
+		}
+
+		tagSlug, err := uniqueTagSlug(tx, name, id)
+		if err != nil {
+			return err
+		}
+		oldName := tag.Name
+		tag.Aliases = withoutTag(append(tag.Aliases, oldName), name)
+		tag.Name, tag.Slug = name, tagSlug
+		if err := tx.Save(&tag).Error; err != nil {
+			return err
+		}
+		return replaceArticleTag(tx, oldName, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// SetAliases replaces the aliases of a tag. An alias that is the name or an alias of another
+// tag fails with ErrTagExists.
+func (r *TagRepository) SetAliases(id uuid.UUID, aliases []string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&tag, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		normalized := pq.StringArray{}
+		for _, alias := range aliases {
+			alias = model.NormalizeTagName(alias)
+			if alias == "" || strings.EqualFold(alias, tag.Name) || containsTag(normalized, alias) {
+				continue
+			}
+			var clash int64
+			if err := tx.Model(&model.Tag{}).Where("id <> ?", id).
+				Where(matchesTag, strings.ToLower(alias), strings.ToLower(alias)).
+				Count(&clash).Error; err != nil {
+				return err
+			}
+			if clash > 0 {
+				return fmt.Errorf("%w: %s", ErrTagExists, alias)
+			}
+			normalized = append(normalized, alias)
+		}
+
+		tag.Aliases = normalized
+		if err := tx.Save(&tag).Error; err != nil {
+			return err
+		}
+		// Articles tagged with a new alias get the canonical name
+		for _, alias := range normalized {
+			if err := replaceArticleTag(tx, alias, tag.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Merge replaces the source tag with the target in every article and deletes the source.
+// The source's name and aliases become aliases of the target.
+func (r *TagRepository) Merge(sourceID, targetID uuid.UUID) (*model.Tag, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: a tag cannot be merged into itself", ErrInvalidTagOp)
+	}
+
+	var target model.Tag
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source model.Tag
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&source, "id = ?", sourceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&target, "id = ?", targetID).Error; err != nil {
+			return err
+		}
+
+		aliases := target.Aliases
+		for _, alias := range append([]string{source.Name}, source.Aliases...) {
+			if !strings.EqualFold(alias, target.Name) && !containsTag(aliases, alias) {
+				aliases = append(aliases, alias)
+			}
+		}
+		target.Aliases = aliases
+
+		if err := tx.Delete(&model.Tag{}, "id = ?", source.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&target).Error; err != nil {
+			return err
+		}
+		if err := replaceArticleTag(tx, source.Name, target.Name); err != nil {
+			return err
+		}
+		return tx.First(&target, "id = ?", target.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// NormalizeArticleTags rewrites the tags of every article in canonical form, creating tags
+// for names not seen before, and recounts tag usage. Returns the number of articles changed.
+func (r *TagRepository) NormalizeArticleTags() (int, error) {
+	changed := 0
+	var articles []model.Article
+	err := r.db.Select("id", "slug", "tags").Where("cardinality(tags) > 0").
+		FindInBatches(&articles, tagBackfillBatch, func(_ *gorm.DB, _ int) error {
+			return r.db.Transaction(func(tx *gorm.DB) error {
+				for _, article := range articles {
+					tags, err := resolveTags(tx, article.Tags)
+					if err != nil {
+						return err
+					}
+					if slices.Equal(tags, article.Tags) {
+						continue
+					}
+					if err := tx.Model(&model.Article{}).Where("id = ?", article.ID).
+						UpdateColumn("tags", tags).Error; err != nil {
+						return err
+					}
+					if err := recordChange(tx, model.ChangeEntityArticle, article.ID, article.Slug, model.ChangeActionUpdated); err != nil {
+						return err
+					}
+					changed++
+				}
+				return nil
+			})
+		}).Error
+	if err != nil {
+		return changed, err
+	}
+	return changed, recountTags(r.db, nil)
+}
+
+// resolveTags turns tag names into canonical tag names: normalized, with aliases replaced by
+// their tag's name and duplicates dropped. Tags are created for names not seen before.
+func resolveTags(tx *gorm.DB, names []string) (pq.StringArray, error) {
+	resolved := pq.StringArray{}
+	for _, name := range names {
+		name = model.NormalizeTagName(name)
+		if name == "" {
+			continue
+		}
+
+		var tag model.Tag
+		err := tx.Where(matchesTag, strings.ToLower(name), strings.ToLower(name)).First(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tag = model.Tag{Name: name}
+			if tag.Slug, err = uniqueTagSlug(tx, name, uuid.Nil); err != nil {
+				return nil, err
+			}
+			// Another writer may have created the tag since the lookup
+			err = tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&tag).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !containsTag(resolved, tag.Name) {
+			resolved = append(resolved, tag.Name)
+		}
+	}
+	return resolved, nil
+}
+
+// canonicalTag returns the name of the tag a name or alias refers to, or the normalized name
+// if no tag matches. Nothing is created.
+func canonicalTag(db *gorm.DB, name string) string {
+	name = model.NormalizeTagName(name)
+	var tag model.Tag
+	if err := db.Select("name").Where(matchesTag, strings.ToLower(name), strings.ToLower(name)).First(&tag).Error; err == nil {
+		return tag.Name
+	}
+	return name
+}
+
+// recountTags updates the usage count of the named tags, or of all tags if names is nil
+func recountTags(tx *gorm.DB, names []string) error {
+	query := tx.Model(&model.Tag{})
+	if names == nil {
+		query = query.Where("1 = 1")
+	} else if len(names) == 0 {
+		return nil
+	} else {
+		query = query.Where("name IN ?", names)
+	}
+	return query.UpdateColumn("usage_count", gorm.Expr("(SELECT COUNT(*) FROM articles WHERE tags.name = ANY(articles.tags))")).Error
+}
+
+// replaceArticleTag replaces a tag name in the tags of every article, keeping the order and
+// dropping the duplicate when an article already has the new name, and recounts both tags
+func replaceArticleTag(tx *gorm.DB, oldName, newName string) error {
+	var articles []model.Article
+	if err := tx.Select("id", "slug").Where("? = ANY(tags)", oldName).Find(&articles).Error; err != nil {
+		return err
+	}
+	if len(articles) > 0 {
+		if err := tx.Exec(`
+			UPDATE articles SET tags = ARRAY(
+				SELECT tag FROM unnest(array_replace(tags, ?, ?)) WITH ORDINALITY AS t(tag, i)
+				GROUP BY tag ORDER BY MIN(i)
+			)
+			WHERE ? = ANY(tags)`, oldName, newName, oldName).Error; err != nil {
+			return err
+		}
+	}
+	for _, article := range articles {
+		if err := recordChange(tx, model.ChangeEntityArticle, article.ID, article.Slug, model.ChangeActionUpdated); err != nil {
+			return err
+		}
+	}
+	return recountTags(tx, []string{oldName, newName})
+}
+
+// uniqueTagSlug builds a slug for a tag name that no other tag uses
+func uniqueTagSlug(tx *gorm.DB, name string, id uuid.UUID) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "tag"
+	}
+	candidate := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Model(&model.Tag{}).Where("slug = ? AND id <> ?", candidate, id).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// containsTag reports whether a tag list has a name, ignoring case
+func containsTag(tags []string, name string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutTag returns the tag list without a name, ignoring case
+func withoutTag(tags []string, name string) pq.StringArray {
+	kept := pq.StringArray{}
+	for _, tag := range tags {
+		if !strings.EqualFold(tag, name) {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/user/web3-insight/internal/model"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -76,6 +77,18 @@ func (r *TaskRepository) GetByID(id uuid.UUID) (*model.Task, error) {
 	return &task, nil
 }
 
+// FindActive returns the newest pending or running task of a type
+func (r *TaskRepository) FindActive(taskType string) (*model.Task, error) {
+	var task model.Task
+	err := r.db.Where("type = ? AND status IN ?", taskType, []string{model.TaskStatusPending, model.TaskStatusRunning}).
+		Order("created_at DESC").
+		First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
 func (r *TaskRepository) Create(task *model.Task) error {
 	return r.db.Create(task).Error
 }
@@ -84,8 +97,73 @@ func (r *TaskRepository) Update(task *model.Task) error {
 	return r.db.Save(task).Error
 }
 
+// UpdateUnlessCancelled saves a task unless it was cancelled meanwhile, reporting whether it
+// was saved
+func (r *TaskRepository) UpdateUnlessCancelled(task *model.Task) (bool, error) {
+	result := r.db.Model(task).Where("status <> ?", model.TaskStatusCancelled).Select("*").Updates(task)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateImport creates an import task record along with the batch it imports
+func (r *TaskRepository) CreateImport(task *model.Task, batch datatypes.JSON) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.ImportBatchData{TaskID: task.ID, Data: batch}).Error
+	})
+}
+
+// GetImportBatch returns the batch an import task record imports
+func (r *TaskRepository) GetImportBatch(taskID uuid.UUID) (datatypes.JSON, error) {
+	var data model.ImportBatchData
+	if err := r.db.First(&data, "task_id = ?", taskID).Error; err != nil {
+		return nil, err
+	}
+	return data.Data, nil
+}
+
+// DeleteImportBatch drops the batch of an import that will not run again
+func (r *TaskRepository) DeleteImportBatch(taskID uuid.UUID) error {
+	return r.db.Delete(&model.ImportBatchData{}, "task_id = ?", taskID).Error
+}
+
+// QueuedCrawlURLs returns which of the given URLs already have a web_crawl task, in any status
+func (r *TaskRepository) QueuedCrawlURLs(urls []string) (map[string]bool, error) {
+	queued := make(map[string]bool)
+	for start := 0; start < len(urls); start += 500 {
+		end := min(start+500, len(urls))
+		var found []string
+		err := r.db.Model(&model.Task{}).
+			Where("type = ? AND payload->>'url' IN ?", model.TaskTypeWebCrawl, urls[start:end]).
+			Pluck("payload->>'url'", &found).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range found {
+			queued[u] = true
+		}
+	}
+	return queued, nil
+}
+
+// FindPendingCrawls returns the oldest pending web_crawl tasks of a data source
+func (r *TaskRepository) FindPendingCrawls(sourceID uuid.UUID, limit int) ([]model.Task, error) {
+	var tasks []model.Task
+	err := r.db.Where("type = ? AND status = ? AND payload->>'sourceId' = ?", model.TaskTypeWebCrawl, model.TaskStatusPending, sourceID.String()).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// UpdateResult stores a running task's progress without touching its status
+func (r *TaskRepository) UpdateResult(id uuid.UUID, result datatypes.JSON) error {
+	return r.db.Model(&model.Task{}).Where("id = ?", id).Update("result", result).Error
+}
+
 func (r *TaskRepository) Cancel(id uuid.UUID) error {
-	return r.db.Model(&model.Task{}).Where("id = ? AND status IN ?", id, []string{model.TaskStatusPending, model.TaskStatusRunning}).Update("status", "cancelled").Error
+	return r.db.Model(&model.Task{}).Where("id = ? AND status IN ?", id, []string{model.TaskStatusPending, model.TaskStatusRunning}).Update("status", model.TaskStatusCancelled).Error
 }
 
 type TaskStats struct {
@@ -97,6 +175,16 @@ type TaskStats struct {
 	TotalCostUSD   decimal.Decimal `json:"totalCostUsd"`
 	TotalTokens    int64           `json:"totalTokens"`
 	TasksByType    map[string]int  `json:"tasksByType"`
+	UsageByType    []UsageSummary  `json:"usageByType"`
+	UsageByModel   []UsageSummary  `json:"usageByModel"`
+}
+
+// UsageSummary aggregates LLM token usage and cost for one task type or model
+type UsageSummary struct {
+	Key     string          `json:"key"`
+	Calls   int64           `json:"calls"`
+	Tokens  int64           `json:"tokens"`
+	CostUSD decimal.Decimal `json:"costUsd"`
 }
 
 func (r *TaskRepository) GetStats() (*TaskStats, error) {
@@ -153,10 +241,38 @@ func (r *TaskRepository) GetStats() (*TaskStats, error) {
 		stats.TasksByType[tc.Type] = tc.Count
 	}
 
+	// LLM usage by task type and by model
+	usageByType, err := r.usageGroupedBy("type")
+	if err != nil {
+		return nil, err
+	}
+	stats.UsageByType = usageByType
+
+	usageByModel, err := r.usageGroupedBy("model_used")
+	if err != nil {
+		return nil, err
+	}
+	stats.UsageByModel = usageByModel
+
 	return &stats, nil
 }
 
+// usageGroupedBy sums tokens and cost of tasks that used a model, grouped by column
+func (r *TaskRepository) usageGroupedBy(column string) ([]UsageSummary, error) {
+	var summaries []UsageSummary
+	err := r.db.Model(&model.Task{}).
+		Select(column + " as key, COUNT(*) as calls, COALESCE(SUM(tokens_used), 0) as tokens, COALESCE(SUM(cost_usd), 0) as cost_usd").
+		Where("model_used <> ''").
+		Group(column).
+		Order("cost_usd DESC").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
 func (r *TaskRepository) CleanupOldTasks(olderThan time.Duration) error {
 	cutoff := time.Now().Add(-olderThan)
-	return r.db.Where("status IN ? AND created_at < ?", []string{model.TaskStatusCompleted, model.TaskStatusFailed}, cutoff).Delete(&model.Task{}).Error
+	if err := r.db.Where("status IN ? AND created_at < ?", []string{model.TaskStatusCompleted, model.TaskStatusFailed}, cutoff).Delete(&model.Task{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("task_id NOT IN (?)", r.db.Model(&model.Task{}).Select("id")).Delete(&model.ImportBatchData{}).Error
 }
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTermExists is returned when a term's name or alias is already used by another term
+var ErrTermExists = errors.New("a term with this name already exists")
+
+// matchesTerm is the condition for a term whose Chinese or English name or an alias equals a
+// lowercased name
+const matchesTerm = "(lower(name_zh) = ? OR lower(name_en) = ? OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE lower(alias) = ?))"
+
+type TermRepository struct {
+	db *gorm.DB
+}
+
+func NewTermRepository(db *gorm.DB) *TermRepository {
+	return &TermRepository{db: db}
+}
+
+type TermListResult struct {
+	Terms    []model.Term `json:"terms"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"pageSize"`
+}
+
+// List returns terms matching a search in their names, aliases or definition, by name
+func (r *TermRepository) List(search string, page, pageSize int) (*TermListResult, error) {
+	query := r.db.Model(&model.Term{})
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("name_zh ILIKE ? OR name_en ILIKE ? OR definition ILIKE ? OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE alias ILIKE ?)",
+			pattern, pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	var terms []model.Term
+	if err := query.Order("lower(COALESCE(NULLIF(name_en, ''), name_zh)) ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&terms).Error; err != nil {
+		return nil, err
+	}
+
+	return &TermListResult{Terms: terms, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (r *TermRepository) GetByID(id uuid.UUID) (*model.Term, error) {
+	var term model.Term
+	if err := r.db.First(&term, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (r *TermRepository) GetBySlug(slug string) (*model.Term, error) {
+	var term model.Term
+	if err := r.db.First(&term, "slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// FindByName returns the term whose Chinese or English name or an alias matches a name,
+// ignoring case
+func (r *TermRepository) FindByName(name string) (*model.Term, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	var term model.Term
+	if err := r.db.Where(matchesTerm, key, key, key).First(&term).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// Lookup resolves names to the terms they refer to, keyed by the name as given. Names
+// without a term are left out.
+func (r *TermRepository) Lookup(names []string) (map[string]model.Term, error) {
+	found := make(map[string]model.Term, len(names))
+	for _, name := range names {
+		term, err := r.FindByName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found[name] = *term
+	}
+	return found, nil
+}
+
+// ListForArticle returns the terms linked to an article, longest names first so that
+// highlighting "零知识证明" wins over "证明"
+func (r *TermRepository) ListForArticle(articleID uuid.UUID) ([]model.Term, error) {
+	var terms []model.Term
+	err := r.db.Where("id IN (SELECT term_id FROM term_articles WHERE article_id = ?)", articleID).
+		Order("char_length(name_zh) DESC, name_en ASC").
+		Find(&terms).Error
+	return terms, err
+}
+
+// Create saves a new term. Fails with ErrTermExists if one of its names is taken.
+func (r *TermRepository) Create(term *model.Term) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkTermNames(tx, term); err != nil {
+			return err
+		}
+		termSlug, err := uniqueTermSlug(tx, term)
+		if err != nil {
+			return err
+		}
+		term.Slug = termSlug
+		return tx.Create(term).Error
+	})
+}
+
+// Update saves a term. Fails with ErrTermExists if one of its names is taken by another term.
+func (r *TermRepository) Update(term *model.Term) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkTermNames(tx, term); err != nil {
+			return err
+		}
+		return tx.Save(term).Error
+	})
+}
+
+func (r *TermRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.Term{}, "id = ?", id).Error
+}
+
+// LinkArticles links a term to articles; existing links are kept
+func (r *TermRepository) LinkArticles(termID uuid.UUID, articleIDs ...uuid.UUID) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	links := make([]model.TermArticle, 0, len(articleIDs))
+	for _, articleID := range articleIDs {
+		links = append(links, model.TermArticle{TermID: termID, ArticleID: articleID})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// SetArticles replaces the articles linked to a term
+func (r *TermRepository) SetArticles(termID uuid.UUID, articleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.TermArticle{}, "term_id = ?", termID).Error; err != nil {
+			return err
+		}
+		if len(articleIDs) == 0 {
+			return nil
+		}
+		links := make([]model.TermArticle, 0, len(articleIDs))
+		for _, articleID := range articleIDs {
+			links = append(links, model.TermArticle{TermID: termID, ArticleID: articleID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// LoadArticles fills term.Articles with short references to its related articles
+func (r *TermRepository) LoadArticles(term *model.Term) error {
+	var articles []model.Article
+	if err := r.db.Select(prerequisiteColumns).
+		Where("id IN (SELECT article_id FROM term_articles WHERE term_id = ?)", term.ID).
+		Order("title ASC").
+		Find(&articles).Error; err != nil {
+		return err
+	}
+	term.Articles = articles
+	return nil
+}
+
+// checkTermNames returns ErrTermExists if a name or alias of the term belongs to another term
+func checkTermNames(tx *gorm.DB, term *model.Term) error {
+	names := append([]string{term.NameZh, term.NameEn}, term.Aliases...)
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			continue
+		}
+		var clash model.Term
+		err := tx.Select("id", "name_zh", "name_en").Where("id <> ?", term.ID).
+			Where(matchesTerm, key, key, key).First(&clash).Error
+		if err == nil {
+			return fmt.Errorf("%w: %s", ErrTermExists, name)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueTermSlug builds a slug from the English name, or the Chinese name without one,
+// that no other term uses
+func uniqueTermSlug(tx *gorm.DB, term *model.Term) (string, error) {
+	name := term.NameEn
+	if name == "" {
+		name = term.NameZh
+	}
+	base := slug.Make(name)
+	if base == "" {
+		base = "term"
+	}
+	candidate := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Model(&model.Term{}).Where("slug = ? AND id <> ?", candidate, term.ID).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
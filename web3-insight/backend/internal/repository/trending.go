@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+)
+
+type TrendingTopicRepository struct {
+	db *gorm.DB
+}
+
+func NewTrendingTopicRepository(db *gorm.DB) *TrendingTopicRepository {
+	return &TrendingTopicRepository{db: db}
+}
+
+// List returns up to limit trending topics by rank
+func (r *TrendingTopicRepository) List(limit int) ([]model.TrendingTopic, error) {
+	var topics []model.TrendingTopic
+	err := r.db.Order("rank ASC").Limit(limit).Find(&topics).Error
+	return topics, err
+}
+
+// Replace replaces the trending topics with the result of a new analysis. Topics that were
+// already listed keep the article generated from them.
+func (r *TrendingTopicRepository) Replace(topics []model.TrendingTopic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous []model.TrendingTopic
+		if err := tx.Select("topic", "article_id").Where("article_id IS NOT NULL").Find(&previous).Error; err != nil {
+			return err
+		}
+		articles := make(map[string]*uuid.UUID, len(previous))
+		for _, p := range previous {
+			articles[strings.ToLower(p.Topic)] = p.ArticleID
+		}
+
+		if err := tx.Where("1 = 1").Delete(&model.TrendingTopic{}).Error; err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return nil
+		}
+		for i := range topics {
+			if topics[i].ArticleID == nil {
+				topics[i].ArticleID = articles[strings.ToLower(topics[i].Topic)]
+			}
+		}
+		return tx.Create(&topics).Error
+	})
+}
+
+// SetArticle records the article generated from a topic. Topics are matched by name, as
+// Replace recreates the rows of topics that stay listed.
+func (r *TrendingTopicRepository) SetArticle(topic string, articleID uuid.UUID) error {
+	return r.db.Model(&model.TrendingTopic{}).Where("LOWER(topic) = LOWER(?)", topic).Update("article_id", articleID).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WikiPageRepository struct {
+	db *gorm.DB
+}
+
+func NewWikiPageRepository(db *gorm.DB) *WikiPageRepository {
+	return &WikiPageRepository{db: db}
+}
+
+// Get returns the page an article was exported to, or gorm.ErrRecordNotFound
+func (r *WikiPageRepository) Get(articleID uuid.UUID, target string) (*model.WikiPage, error) {
+	var page model.WikiPage
+	err := r.db.Where("article_id = ? AND target = ?", articleID, target).First(&page).Error
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Save creates or replaces the mapping of an article to a wiki page
+func (r *WikiPageRepository) Save(page *model.WikiPage) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "target"}},
+		DoUpdates: clause.AssignmentColumns([]string{"remote_id", "remote_url", "content_hash", "exported_at", "updated_at"}),
+	}).Create(page).Error
+}
+
+// List returns exported pages, optionally filtered by target and article
+func (r *WikiPageRepository) List(target string, articleID *uuid.UUID) ([]model.WikiPage, error) {
+	query := r.db.Model(&model.WikiPage{})
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+	if articleID != nil {
+		query = query.Where("article_id = ?", *articleID)
+	}
+
+	var pages []model.WikiPage
+	err := query.Order("exported_at DESC").Find(&pages).Error
+	return pages, err
+}
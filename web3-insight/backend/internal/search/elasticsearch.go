@@ -0,0 +1,254 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Elasticsearch indexes documents through the Elasticsearch REST API (7.x and 8.x)
+type Elasticsearch struct {
+	baseURL  string
+	apiKey   string
+	username string
+	password string
+	prefix   string
+	analyzer string
+	client   *http.Client
+}
+
+// NewElasticsearch creates an Elasticsearch engine. Text fields use the configured analyzer,
+// the built-in cjk analyzer by default.
+func NewElasticsearch(cfg *config.SearchEngineConfig) *Elasticsearch {
+	analyzer := cfg.Analyzer
+	if analyzer == "" {
+		analyzer = "cjk"
+	}
+	return &Elasticsearch{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		apiKey:   cfg.APIKey,
+		username: cfg.Username,
+		password: cfg.Password,
+		prefix:   cfg.IndexPrefix,
+		analyzer: analyzer,
+		client:   newHTTPClient(),
+	}
+}
+
+func (e *Elasticsearch) Name() string { return ProviderElasticsearch }
+
+// EnsureIndex creates the index with its mappings unless it exists
+func (e *Elasticsearch) EnsureIndex(ctx context.Context, index string) error {
+	path := "/" + url.PathEscape(e.prefix+index)
+	status, err := e.request(ctx, http.MethodHead, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNotFound {
+		return nil
+	}
+
+	text := map[string]string{"type": "text", "analyzer": e.analyzer}
+	keyword := map[string]string{"type": "keyword"}
+	mappings := map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"id":            keyword,
+				"title":         text,
+				"summary":       text,
+				"content":       text,
+				"tags":          text,
+				FieldStatus:     keyword,
+				FieldDifficulty: keyword,
+				"publishedAt":   map[string]string{"type": "date", "format": "epoch_second"},
+			},
+		},
+	}
+	return e.do(ctx, http.MethodPut, path, mappings, nil)
+}
+
+// Upsert indexes documents in one bulk request
+func (e *Elasticsearch) Upsert(ctx context.Context, index string, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		enc.Encode(map[string]interface{}{"index": map[string]string{"_index": e.prefix + index, "_id": doc.ID}})
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("failed to marshal document %s: %w", doc.ID, err)
+		}
+	}
+	return e.bulk(ctx, &body)
+}
+
+// Delete removes documents in one bulk request
+func (e *Elasticsearch) Delete(ctx context.Context, index string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, id := range ids {
+		enc.Encode(map[string]interface{}{"delete": map[string]string{"_index": e.prefix + index, "_id": id}})
+	}
+	return e.bulk(ctx, &body)
+}
+
+// Search returns matching document IDs, best match first. Fuzzy matching gives typo tolerance.
+func (e *Elasticsearch) Search(ctx context.Context, index string, query Query) ([]string, error) {
+	filters := []interface{}{}
+	for field, values := range query.Filters {
+		if len(values) > 0 {
+			filters = append(filters, map[string]interface{}{"terms": map[string][]string{field: values}})
+		}
+	}
+	request := map[string]interface{}{
+		"size":    query.Limit,
+		"_source": false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query":     query.Text,
+						"fields":    []string{"title^3", "tags^2", "summary^2", "content"},
+						"fuzziness": "AUTO",
+					},
+				},
+				"filter": filters,
+			},
+		},
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := e.do(ctx, http.MethodPost, "/"+url.PathEscape(e.prefix+index)+"/_search", request, &response); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(response.Hits.Hits))
+	for i, hit := range response.Hits.Hits {
+		ids[i] = hit.ID
+	}
+	return ids, nil
+}
+
+// bulk sends an NDJSON bulk request and fails if any item failed, except deletes of missing documents
+func (e *Elasticsearch) bulk(ctx context.Context, body io.Reader) error {
+	var response struct {
+		Errors bool                               `json:"errors"`
+		Items  []map[string]elasticsearchBulkItem `json:"items"`
+	}
+	if err := e.send(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body, &response); err != nil {
+		return err
+	}
+	if !response.Errors {
+		return nil
+	}
+	for _, item := range response.Items {
+		for action, result := range item {
+			if action == "delete" && result.Status == http.StatusNotFound {
+				continue
+			}
+			if result.Status >= 300 {
+				return fmt.Errorf("elasticsearch %s of %s failed: %s", action, result.ID, result.Error.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+// elasticsearchBulkItem is the result of one bulk action
+type elasticsearchBulkItem struct {
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  struct {
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+func (e *Elasticsearch) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return e.send(ctx, method, path, "application/json", bytes.NewReader(data), out)
+}
+
+func (e *Elasticsearch) send(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) error {
+	status, err := e.request(ctx, method, path, &requestBody{contentType, body}, out)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("elasticsearch returned status %d", status)
+	}
+	return nil
+}
+
+// requestBody is a request body with its content type
+type requestBody struct {
+	contentType string
+	reader      io.Reader
+}
+
+// request sends a request and decodes a successful response into out. Error responses
+// are returned as an error, except 404 which is left to the caller as a status.
+func (e *Elasticsearch) request(ctx context.Context, method, path string, body *requestBody, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = body.reader
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", body.contentType)
+	}
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+e.apiKey)
+	} else if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("elasticsearch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Error struct {
+				Reason string `json:"reason"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return resp.StatusCode, fmt.Errorf("elasticsearch returned status %d: %s", resp.StatusCode, errResp.Error.Reason)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,154 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Meilisearch indexes documents through the Meilisearch REST API. Writes are asynchronous
+// tasks on the Meilisearch side, so they become searchable shortly after returning.
+type Meilisearch struct {
+	baseURL string
+	apiKey  string
+	prefix  string
+	client  *http.Client
+}
+
+// NewMeilisearch creates a Meilisearch engine
+func NewMeilisearch(cfg *config.SearchEngineConfig) *Meilisearch {
+	return &Meilisearch{
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		apiKey:  cfg.APIKey,
+		prefix:  cfg.IndexPrefix,
+		client:  newHTTPClient(),
+	}
+}
+
+func (m *Meilisearch) Name() string { return ProviderMeilisearch }
+
+// EnsureIndex creates the index and applies its settings. Creating an existing index fails
+// only as a Meilisearch task, so it is safe to repeat.
+func (m *Meilisearch) EnsureIndex(ctx context.Context, index string) error {
+	uid := m.prefix + index
+	if err := m.do(ctx, http.MethodPost, "/indexes", map[string]string{"uid": uid, "primaryKey": "id"}, nil); err != nil {
+		return err
+	}
+
+	// Attribute order is ranking order: title matches outrank content matches
+	settings := map[string]interface{}{
+		"searchableAttributes": []string{"title", "tags", "summary", "content"},
+		"filterableAttributes": []string{FieldStatus, FieldDifficulty},
+		"sortableAttributes":   []string{"publishedAt"},
+	}
+	return m.do(ctx, http.MethodPatch, "/indexes/"+url.PathEscape(uid)+"/settings", settings, nil)
+}
+
+// Upsert adds or replaces documents
+func (m *Meilisearch) Upsert(ctx context.Context, index string, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(m.prefix+index)+"/documents", docs, nil)
+}
+
+// Delete removes documents by ID
+func (m *Meilisearch) Delete(ctx context.Context, index string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(m.prefix+index)+"/documents/delete-batch", ids, nil)
+}
+
+// Search returns matching document IDs, best match first
+func (m *Meilisearch) Search(ctx context.Context, index string, query Query) ([]string, error) {
+	request := map[string]interface{}{
+		"q":                    query.Text,
+		"limit":                query.Limit,
+		"attributesToRetrieve": []string{"id"},
+	}
+	if filter := meilisearchFilter(query.Filters); len(filter) > 0 {
+		request["filter"] = filter
+	}
+
+	var response struct {
+		Hits []struct {
+			ID string `json:"id"`
+		} `json:"hits"`
+	}
+	if err := m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(m.prefix+index)+"/search", request, &response); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(response.Hits))
+	for i, hit := range response.Hits {
+		ids[i] = hit.ID
+	}
+	return ids, nil
+}
+
+// meilisearchFilter builds an array filter: the outer array is AND, inner arrays are OR
+func meilisearchFilter(filters map[string][]string) [][]string {
+	var filter [][]string
+	for field, values := range filters {
+		if len(values) == 0 {
+			continue
+		}
+		anyOf := make([]string, len(values))
+		for i, value := range values {
+			anyOf[i] = fmt.Sprintf("%s = %q", field, value)
+		}
+		filter = append(filter, anyOf)
+	}
+	return filter
+}
+
+func (m *Meilisearch) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("meilisearch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("meilisearch returned status %d: %s", resp.StatusCode, errResp.Message)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
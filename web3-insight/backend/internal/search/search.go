@@ -0,0 +1,83 @@
+// Package search indexes articles in an external full-text search engine
+// such as Meilisearch or Elasticsearch
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Supported search engine providers
+const (
+	ProviderMeilisearch   = "meilisearch"
+	ProviderElasticsearch = "elasticsearch"
+)
+
+// Index names; engines prepend the configured index prefix
+const (
+	IndexArticles = "articles"
+)
+
+// ErrUnknownProvider is returned for a provider other than the supported ones
+var ErrUnknownProvider = errors.New("unknown search engine provider")
+
+// Document is the indexed form of an article
+type Document struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Summary     string   `json:"summary"`
+	Content     string   `json:"content"`
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status,omitempty"`
+	Difficulty  string   `json:"difficulty,omitempty"`
+	PublishedAt int64    `json:"publishedAt"` // Unix seconds
+}
+
+// Filterable document fields
+const (
+	FieldStatus     = "status"
+	FieldDifficulty = "difficulty"
+)
+
+// Query is a full-text query. Filters restrict a field to any of the given values.
+type Query struct {
+	Text    string
+	Limit   int
+	Filters map[string][]string
+}
+
+// Engine stores documents in named indexes and searches them
+type Engine interface {
+	Name() string
+	// EnsureIndex creates the index with the document settings if it does not exist
+	EnsureIndex(ctx context.Context, index string) error
+	// Upsert adds documents, replacing those with the same ID
+	Upsert(ctx context.Context, index string, docs []Document) error
+	// Delete removes documents by ID; missing IDs are ignored
+	Delete(ctx context.Context, index string, ids []string) error
+	// Search returns the IDs of matching documents, best match first
+	Search(ctx context.Context, index string, query Query) ([]string, error)
+}
+
+// NewEngineFromConfig creates the configured engine, or returns nil when search runs on the database
+func NewEngineFromConfig(cfg *config.SearchEngineConfig) (Engine, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "":
+		return nil, nil
+	case ProviderMeilisearch:
+		return NewMeilisearch(cfg), nil
+	case ProviderElasticsearch:
+		return NewElasticsearch(cfg), nil
+	default:
+		return nil, ErrUnknownProvider
+	}
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/user/web3-insight/internal/model"
+)
+
+// ErrLicenseRequired is returned when crawled content whose license is unknown would be republished
+var ErrLicenseRequired = errors.New("a source license is required to promote crawled content")
+
+// ErrLicenseDisallowed is returned when crawled content's license does not allow adapting it
+var ErrLicenseDisallowed = errors.New("the source license does not allow reusing crawled content")
+
+// AttributeNews records that an article reuses a news item's content: it copies the item's
+// license unless the caller already set one, adds the source URL and builds the credit line.
+// Content without a known license can't be promoted until someone sets it explicitly, and
+// content whose license forbids adaptations can't be promoted at all.
+func AttributeNews(article *model.Article, item *model.NewsItem) error {
+	if article.License == "" {
+		article.License = item.License
+		article.LicenseURL = item.LicenseURL
+	}
+	if article.License == "" {
+		return fmt.Errorf("%w: %s", ErrLicenseRequired, item.SourceURL)
+	}
+	if !LicenseAllowsReuse(article.License) {
+		return fmt.Errorf("%w: %s is %s", ErrLicenseDisallowed, item.SourceURL, article.License)
+	}
+
+	if !containsString(article.SourceURLs, item.SourceURL) {
+		article.SourceURLs = append(article.SourceURLs, item.SourceURL)
+	}
+
+	title := item.OriginalTitle
+	if title == "" {
+		title = item.Title
+	}
+	article.Attribution = FormatAttribution(title, item.Author, item.SourceName, item.SourceURL, article.License)
+	return nil
+}
+
+// LicenseAllowsReuse reports whether content under a license can be adapted into articles.
+// Articles rewrite their sources, so all-rights-reserved and NoDerivatives content is excluded.
+func LicenseAllowsReuse(license string) bool {
+	if license == model.LicenseAllRightsReserved {
+		return false
+	}
+	return !strings.Contains(strings.ToUpper(license), "-ND")
+}
+
+// FormatAttribution builds the credit line shown with reused content, skipping unknown parts
+func FormatAttribution(title, author, sourceName, sourceURL, license string) string {
+	var parts []string
+	origin := "原文"
+	if sourceName != "" {
+		origin = sourceName
+	}
+	if title != "" {
+		parts = append(parts, fmt.Sprintf("本文内容来自 %s《%s》", origin, title))
+	} else {
+		parts = append(parts, fmt.Sprintf("本文内容来自 %s", origin))
+	}
+	if author != "" {
+		parts = append(parts, "作者："+author)
+	}
+	if sourceURL != "" {
+		parts = append(parts, "原文链接："+sourceURL)
+	}
+	if license != "" {
+		parts = append(parts, "许可协议："+license)
+	}
+	return strings.Join(parts, "，")
+}
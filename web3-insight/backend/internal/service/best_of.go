@@ -0,0 +1,172 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// Generation quality levels
+const (
+	QualityStandard = "standard"
+	QualityHigh     = "high" // Best-of-N: several candidates, picked by a judge
+)
+
+// Best-of-N limits
+const (
+	defaultBestOfCandidates = 3
+	maxBestOfCandidates     = 5
+	judgeReplyTokens        = 1024
+	judgePromptReserve      = 1024
+)
+
+// articleJudgeSchema is the JSON schema the judge's verdict must match
+var articleJudgeSchema = llm.MustJSONSchema("article_judgement", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"best": map[string]interface{}{"type": "integer", "minimum": 1},
+		"scores": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 10},
+		},
+		"reason": map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"best"},
+})
+
+// CandidateSelection describes how a high-quality article was picked among candidates
+type CandidateSelection struct {
+	Candidates int       `json:"candidates"` // Candidates generated successfully
+	Models     []string  `json:"models"`
+	Scores     []float64 `json:"scores,omitempty"` // Judge scores, in candidate order
+	Selected   int       `json:"selected"`         // 0-based index of the chosen candidate
+	Reason     string    `json:"reason,omitempty"`
+	Judged     bool      `json:"judged"` // False if the judge failed and the heuristic quality score decided
+	TokensUsed int       `json:"tokensUsed"`
+}
+
+// generateBestOf generates n candidates concurrently, spread over the available content models
+// (repeating models as extra samples), and returns the one the judge prefers
+func (g *Generator) generateBestOf(topic, prompt string, n int, opts *llm.GenerateOptions) (*llm.GenerateResult, *CandidateSelection, error) {
+	if n <= 0 {
+		n = defaultBestOfCandidates
+	}
+	if n > maxBestOfCandidates {
+		n = maxBestOfCandidates
+	}
+
+	models := g.llmRouter.AvailableModels(llm.TaskContentGeneration)
+	if len(models) == 0 {
+		return nil, nil, fmt.Errorf("no models available for task: %s", llm.TaskContentGeneration)
+	}
+
+	results := make([]*llm.GenerateResult, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			modelName := models[i%len(models)]
+			startedAt := time.Now()
+			result, err := g.llmRouter.GenerateWithModel(llm.TaskContentGeneration, modelName, prompt, opts)
+			g.usage.Record(model.TaskTypeContentGenerate, map[string]interface{}{"topic": topic, "candidate": i + 1, "model": modelName}, startedAt, result, err)
+			if err != nil {
+				log.Printf("Candidate %d with %s failed: %v", i+1, modelName, err)
+				return
+			}
+			result.Content = g.cleanGeneratedContent(result.Content)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	var candidates []*llm.GenerateResult
+	selection := &CandidateSelection{}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		candidates = append(candidates, result)
+		selection.Models = append(selection.Models, result.Model)
+		selection.TokensUsed += result.Usage.TotalTokens()
+	}
+	selection.Candidates = len(candidates)
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("all %d candidates failed", n)
+	}
+	if len(candidates) == 1 {
+		return candidates[0], selection, nil
+	}
+
+	if err := g.judgeCandidates(topic, candidates, selection); err != nil {
+		log.Printf("Candidate judging failed, falling back to quality score: %v", err)
+		best := 0
+		for i, c := range candidates {
+			if g.qualityScore(c.Content) > g.qualityScore(candidates[best].Content) {
+				best = i
+			}
+		}
+		selection.Selected = best
+	}
+
+	log.Printf("Selected candidate %d of %d (%s) for '%s'", selection.Selected+1, len(candidates), candidates[selection.Selected].Model, topic)
+	return candidates[selection.Selected], selection, nil
+}
+
+// judgeCandidates asks the judge model to pick the best candidate, filling selection
+func (g *Generator) judgeCandidates(topic string, candidates []*llm.GenerateResult, selection *CandidateSelection) error {
+	// Give every candidate an equal share of the judge's context window
+	judgeModel := g.llmRouter.PrimaryModel(llm.TaskContentGeneration)
+	budget := (g.llmRouter.TaskContextWindow(llm.TaskContentGeneration) - judgeReplyTokens - judgePromptReserve) / len(candidates)
+	if budget <= 0 {
+		return fmt.Errorf("context window too small to judge %d candidates", len(candidates))
+	}
+
+	var list strings.Builder
+	for i, c := range candidates {
+		content, truncated := llm.TruncateToTokens(judgeModel, c.Content, budget)
+		fmt.Fprintf(&list, "### 候选 %d\n\n%s\n", i+1, content)
+		if truncated {
+			list.WriteString("\n（内容过长，已截断）\n")
+		}
+		list.WriteString("\n")
+	}
+	prompt := fmt.Sprintf(PromptArticleJudge, len(candidates), topic, list.String())
+
+	startedAt := time.Now()
+	generated, err := g.llmRouter.GenerateStructured(llm.TaskContentGeneration, prompt, articleJudgeSchema, &llm.GenerateOptions{
+		Temperature: 0,
+		MaxTokens:   judgeReplyTokens,
+	})
+	g.usage.Record(model.TaskTypeContentGenerate, map[string]interface{}{"topic": topic, "judge": true}, startedAt, generated, err)
+	if err != nil {
+		return err
+	}
+	selection.TokensUsed += generated.Usage.TotalTokens()
+
+	var verdict struct {
+		Best   int       `json:"best"`
+		Scores []float64 `json:"scores"`
+		Reason string    `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &verdict); err != nil {
+		return fmt.Errorf("failed to parse verdict: %w", err)
+	}
+	if verdict.Best < 1 || verdict.Best > len(candidates) {
+		return fmt.Errorf("judge picked invalid candidate %d", verdict.Best)
+	}
+
+	selection.Selected = verdict.Best - 1
+	selection.Reason = verdict.Reason
+	selection.Judged = true
+	if len(verdict.Scores) == len(candidates) {
+		selection.Scores = verdict.Scores
+	}
+	return nil
+}
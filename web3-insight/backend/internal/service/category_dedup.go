@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// DefaultDuplicateSimilarity is the cosine similarity above which two categories are
+// reported as likely duplicates
+const DefaultDuplicateSimilarity = 0.88
+
+// CategoryDeduper finds categories that are likely synonyms by embedding their names and
+// descriptions
+type CategoryDeduper struct {
+	categoryRepo *repository.CategoryRepository
+	adapter      llm.EmbeddingAdapter
+}
+
+// NewCategoryDeduper creates a new category deduper
+func NewCategoryDeduper(categoryRepo *repository.CategoryRepository, adapter llm.EmbeddingAdapter) *CategoryDeduper {
+	return &CategoryDeduper{categoryRepo: categoryRepo, adapter: adapter}
+}
+
+// Detect embeds every category that is not archived and replaces the open duplicate pairs
+// with the pairs at least threshold similar. Pairs dismissed earlier are not reopened.
+// Returns the open pairs.
+func (d *CategoryDeduper) Detect(ctx context.Context, threshold float64) ([]model.CategoryDuplicate, error) {
+	if threshold <= 0 {
+		threshold = DefaultDuplicateSimilarity
+	}
+
+	all, err := d.categoryRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	var categories []model.Category
+	var texts []string
+	for _, c := range all {
+		if c.Archived {
+			continue
+		}
+		categories = append(categories, c)
+		texts = append(texts, categoryEmbeddingText(&c))
+	}
+	if len(categories) < 2 {
+		return []model.CategoryDuplicate{}, d.categoryRepo.ReplaceOpenDuplicates(nil)
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	embeddings, err := d.adapter.GenerateBatchEmbeddings(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed categories: %w", err)
+	}
+	if len(embeddings) != len(categories) {
+		return nil, fmt.Errorf("got %d embeddings for %d categories", len(embeddings), len(categories))
+	}
+
+	var duplicates []model.CategoryDuplicate
+	for i := range categories {
+		for j := i + 1; j < len(categories); j++ {
+			similarity := cosineSimilarity(embeddings[i], embeddings[j])
+			if similarity < threshold {
+				continue
+			}
+			first, second := categories[i].ID, categories[j].ID
+			if second.String() < first.String() {
+				first, second = second, first
+			}
+			duplicates = append(duplicates, model.CategoryDuplicate{
+				CategoryID:  first,
+				DuplicateID: second,
+				Similarity:  similarity,
+			})
+		}
+	}
+
+	if err := d.categoryRepo.ReplaceOpenDuplicates(duplicates); err != nil {
+		return nil, fmt.Errorf("failed to save duplicates: %w", err)
+	}
+	return d.categoryRepo.ListDuplicates(model.DuplicateStatusOpen)
+}
+
+// MergeTarget picks which category of a duplicate pair to keep: the one with more
+// articles, or the one created first on a tie
+func MergeTarget(duplicate *model.CategoryDuplicate) (source, target uuid.UUID) {
+	a, b := duplicate.Category, duplicate.Duplicate
+	if b.ArticleCount > a.ArticleCount || (b.ArticleCount == a.ArticleCount && b.CreatedAt.Before(a.CreatedAt)) {
+		a, b = b, a
+	}
+	return b.ID, a.ID
+}
+
+// categoryEmbeddingText is the text embedded for a category
+func categoryEmbeddingText(category *model.Category) string {
+	parts := []string{category.Name}
+	if category.NameEn != "" && category.NameEn != category.Name {
+		parts = append(parts, category.NameEn)
+	}
+	text := strings.Join(parts, " / ")
+	if category.Description != "" {
+		text += "：" + category.Description
+	}
+	return text
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 if either is zero
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
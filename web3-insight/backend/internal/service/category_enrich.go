@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Limits for LLM category enrichment
+const (
+	enrichSampleArticles = 15
+	enrichArticleSummary = 120
+)
+
+// categoryEnrichSchema is the JSON schema category descriptions and icons must match
+var categoryEnrichSchema = llm.MustJSONSchema("category_enrich", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"description": map[string]interface{}{"type": "string"},
+		"icon":        map[string]interface{}{"type": "string", "enum": stringsToInterfaces(model.CategoryIcons)},
+	},
+	"required": []interface{}{"description", "icon"},
+})
+
+// CategoryEnricher fills in descriptions and icons of categories from their articles with the LLM
+type CategoryEnricher struct {
+	llmRouter    *llm.Router
+	categoryRepo *repository.CategoryRepository
+	articleRepo  *repository.ArticleRepository
+	usage        *UsageRecorder
+}
+
+// NewCategoryEnricher creates a new category enricher
+func NewCategoryEnricher(router *llm.Router, categoryRepo *repository.CategoryRepository, articleRepo *repository.ArticleRepository) *CategoryEnricher {
+	return &CategoryEnricher{
+		llmRouter:    router,
+		categoryRepo: categoryRepo,
+		articleRepo:  articleRepo,
+	}
+}
+
+// SetUsageRecorder enables persisting token usage of category enrichment
+func (e *CategoryEnricher) SetUsageRecorder(usage *UsageRecorder) {
+	e.usage = usage
+}
+
+// Enrich summarizes a category's articles into a description and picks an icon. Only an
+// empty description or icon is filled in, unless force is set.
+func (e *CategoryEnricher) Enrich(ctx context.Context, categoryID uuid.UUID, force bool) (*model.Category, error) {
+	category, err := e.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+	if !force && category.Description != "" && category.Icon != "" {
+		return category, nil
+	}
+
+	articles, err := e.articleRepo.List(repository.ArticleListParams{
+		CategoryID: &category.ID,
+		PageSize:   enrichSampleArticles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list articles: %w", err)
+	}
+
+	var list strings.Builder
+	for _, a := range articles.Articles {
+		fmt.Fprintf(&list, "- %s：%s\n", a.Title, truncateString(a.Summary, enrichArticleSummary))
+	}
+	for _, child := range category.Children {
+		fmt.Fprintf(&list, "- 子分类 %s\n", child.Name)
+	}
+	if list.Len() == 0 {
+		list.WriteString("（暂无文章）\n")
+	}
+
+	parent := "无"
+	if category.ParentID != nil {
+		if p, err := e.categoryRepo.GetByID(*category.ParentID); err == nil {
+			parent = p.Name
+		}
+	}
+	prompt := fmt.Sprintf(PromptCategoryEnrich, category.Name, parent, list.String(), strings.Join(model.CategoryIcons, ", "))
+
+	startedAt := time.Now()
+	generated, err := e.llmRouter.GenerateStructured(llm.TaskSummarization, prompt, categoryEnrichSchema, &llm.GenerateOptions{
+		Temperature: 0.3,
+		MaxTokens:   400,
+		Context:     ctx,
+	})
+	e.usage.Record(model.TaskTypeCategoryEnrich, map[string]interface{}{"categoryId": category.ID}, startedAt, generated, err)
+	if err != nil {
+		return nil, fmt.Errorf("LLM category enrichment failed: %w", err)
+	}
+
+	var result struct {
+		Description string `json:"description"`
+		Icon        string `json:"icon"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse category details: %w", err)
+	}
+
+	if description := strings.TrimSpace(result.Description); description != "" && (force || category.Description == "") {
+		category.Description = description
+	}
+	if model.IsValidCategoryIcon(result.Icon) && (force || category.Icon == "") {
+		category.Icon = result.Icon
+	}
+	if err := e.categoryRepo.SetDetails(category); err != nil {
+		return nil, fmt.Errorf("failed to save category: %w", err)
+	}
+	return category, nil
+}
+
+// EnrichBlank enriches up to limit auto-created categories missing a description or an
+// icon. Categories that fail are logged and skipped. Returns the number enriched.
+func (e *CategoryEnricher) EnrichBlank(ctx context.Context, limit int) (int, error) {
+	categories, err := e.categoryRepo.FindBlank(limit)
+	if err != nil {
+		return 0, err
+	}
+
+	enriched := 0
+	for _, category := range categories {
+		if ctx.Err() != nil {
+			return enriched, ctx.Err()
+		}
+		if _, err := e.Enrich(ctx, category.ID, false); err != nil {
+			log.Printf("Failed to enrich category %s: %v", category.Name, err)
+			continue
+		}
+		enriched++
+	}
+	return enriched, nil
+}
+
+// stringsToInterfaces converts strings for use in a JSON schema
+func stringsToInterfaces(values []string) []interface{} {
+	converted := make([]interface{}, len(values))
+	for i, v := range values {
+		converted[i] = v
+	}
+	return converted
+}
@@ -1,106 +1,240 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/config"
 	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
 	"gorm.io/gorm"
 )
 
+// Token budget for article context in chat prompts
+const (
+	chatReplyTokens      = 2048
+	chatPromptReserve    = 1024  // System prompt template and user message overhead
+	maxChatArticleTokens = 16000 // Cap even for large context windows to bound cost
+	chatRetrievalTimeout = 20 * time.Second
+	chatFollowUpCount    = 3
+	chatFollowUpTokens   = 1500 // Start of the answer the follow-up questions are based on
+)
+
+// ErrChatTooLong is returned when a conversation leaves no room for the reply in the chat
+// model's context window
+var ErrChatTooLong = errors.New("conversation too long for the chat model's context window")
+
+// followUpSchema is the JSON schema follow-up suggestions must match
+var followUpSchema = llm.MustJSONSchema("follow_ups", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"questions": map[string]interface{}{
+			"type":     "array",
+			"maxItems": chatFollowUpCount,
+			"items":    map[string]interface{}{"type": "string"},
+		},
+	},
+	"required": []interface{}{"questions"},
+})
+
 // ChatService handles chat interactions with articles
 type ChatService struct {
 	llmRouter   *llm.Router
 	articleRepo *repository.ArticleRepository
+	retriever   *ChatRetriever
+	prompts     *PromptStore
 }
 
-// NewChatService creates a new chat service
-func NewChatService(db *gorm.DB, llmCfg *config.LLMConfig) *ChatService {
+// NewChatService creates a new chat service. The router is shared with the other services,
+// so chat counts against the same budgets and concurrency limits.
+func NewChatService(router *llm.Router, db *gorm.DB, llmCfg *config.LLMConfig) *ChatService {
+	articleRepo := repository.NewArticleRepository(db)
 	return &ChatService{
-		llmRouter:   llm.NewRouterFromConfig(llmCfg),
-		articleRepo: repository.NewArticleRepository(db),
+		llmRouter:   router,
+		articleRepo: articleRepo,
+		retriever:   NewChatRetriever(articleRepo, repository.NewArticleChunkRepository(db), llm.NewEmbeddingAdapterFromConfig(llmCfg)),
 	}
 }
 
-// Chat handles a chat request about an article
-// Returns a channel of streaming chunks, the model name used, and any error
-func (s *ChatService) Chat(articleID, message, selectedText string) (<-chan llm.StreamChunk, string, error) {
+// SetPromptStore sets the persona prompts used for chat
+func (s *ChatService) SetPromptStore(prompts *PromptStore) {
+	s.prompts = prompts
+}
+
+// Chat handles a chat request about an article. An empty modelName routes the request like
+// any chat; otherwise the named model is used without fallback. Cancelling ctx stops the
+// reply mid-stream.
+// Returns a channel of streaming chunks, the model name used, the sources the reply may cite, and any error
+func (s *ChatService) Chat(ctx context.Context, articleID, message, selectedText, modelName string) (<-chan llm.StreamChunk, string, []ChatSource, error) {
 	var systemPrompt string
+	var sources []ChatSource
+
+	conversationTokens := llm.CountTokens(s.chatModel(modelName), message+selectedText)
+	if s.articleTokenBudget(modelName, conversationTokens) <= 0 {
+		return nil, "", nil, ErrChatTooLong
+	}
 
 	// If articleID is provided, fetch article context
 	if articleID != "" {
 		id, err := uuid.Parse(articleID)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid article ID: %w", err)
+			return nil, "", nil, fmt.Errorf("invalid article ID: %w", err)
 		}
 
 		article, err := s.articleRepo.GetByID(id)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to get article: %w", err)
+			return nil, "", nil, fmt.Errorf("failed to get article: %w", err)
 		}
 
-		systemPrompt = buildChatSystemPrompt(article.Title, article.Content)
+		systemPrompt, sources = s.articleSystemPrompt(ctx, article, strings.TrimSpace(selectedText+"\n"+message), modelName, conversationTokens)
 	} else {
-		systemPrompt = buildGeneralSystemPrompt()
+		systemPrompt = s.prompts.Get(PromptNameChatGeneral)
 	}
 
 	// Build user prompt
 	userPrompt := message
 	if selectedText != "" {
-		userPrompt = fmt.Sprintf("关于「%s」这部分内容：%s", selectedText, message)
+		userPrompt = fmt.Sprintf(s.prompts.Get(PromptNameChatSelection), selectedText, message)
 	}
 
 	// Configure generation options
 	opts := &llm.GenerateOptions{
 		SystemPrompt: systemPrompt,
-		MaxTokens:    2048,
+		MaxTokens:    chatReplyTokens,
 		Temperature:  0.7,
+		Context:      ctx,
 	}
 
 	// Use router to stream response
-	stream, model, err := s.llmRouter.GenerateStream(llm.TaskChat, userPrompt, opts)
+	stream, model, err := s.generateStream(modelName, userPrompt, opts)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	return stream, model, sources, nil
+}
+
+// ChatCorpus answers a question from the whole knowledge base instead of one article, using
+// the most relevant excerpts of the published articles closest to the question. Without
+// matching articles it answers like chat without an article. Cancelling ctx stops the
+// reply mid-stream.
+// Returns a channel of streaming chunks, the model name used, the sources the reply may cite, and any error
+func (s *ChatService) ChatCorpus(ctx context.Context, message, modelName string) (<-chan llm.StreamChunk, string, []ChatSource, error) {
+	retrievalCtx, cancel := context.WithTimeout(ctx, chatRetrievalTimeout)
+	defer cancel()
+	tokenModel := s.chatModel(modelName)
+	budget := min(s.articleTokenBudget(modelName, llm.CountTokens(tokenModel, message)), maxChatContextTokens)
+	if budget <= 0 {
+		return nil, "", nil, ErrChatTooLong
+	}
+	grounded, err := s.retriever.RetrieveCorpus(retrievalCtx, message, tokenModel, budget)
 	if err != nil {
-		return nil, "", fmt.Errorf("LLM generation failed: %w", err)
+		return nil, "", nil, fmt.Errorf("knowledge base search failed: %w", err)
 	}
 
-	return stream, model, nil
+	systemPrompt := s.prompts.Get(PromptNameChatGeneral)
+	sources := grounded.Sources
+	if len(sources) > 0 {
+		systemPrompt = fmt.Sprintf(s.prompts.Get(PromptNameChatCorpus), grounded.Text)
+	}
+
+	opts := &llm.GenerateOptions{
+		SystemPrompt: systemPrompt,
+		MaxTokens:    chatReplyTokens,
+		Temperature:  0.7,
+		Context:      ctx,
+	}
+
+	stream, model, err := s.generateStream(modelName, message, opts)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	return stream, model, sources, nil
 }
 
-// ChatWithMessages handles multi-turn chat with message history
-func (s *ChatService) ChatWithMessages(articleID string, messages []llm.Message) (<-chan llm.StreamChunk, string, error) {
+// ChatWithMessages handles multi-turn chat with message history, optionally with a specific model.
+// Cancelling ctx stops the reply mid-stream.
+func (s *ChatService) ChatWithMessages(ctx context.Context, articleID string, messages []llm.Message, modelName string) (<-chan llm.StreamChunk, string, []ChatSource, error) {
 	var systemPrompt string
+	var sources []ChatSource
+
+	conversationTokens := countMessageTokens(s.chatModel(modelName), messages)
+	if s.articleTokenBudget(modelName, conversationTokens) <= 0 {
+		return nil, "", nil, ErrChatTooLong
+	}
 
 	// If articleID is provided, fetch article context
 	if articleID != "" {
 		id, err := uuid.Parse(articleID)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid article ID: %w", err)
+			return nil, "", nil, fmt.Errorf("invalid article ID: %w", err)
 		}
 
 		article, err := s.articleRepo.GetByID(id)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to get article: %w", err)
+			return nil, "", nil, fmt.Errorf("failed to get article: %w", err)
 		}
 
-		systemPrompt = buildChatSystemPrompt(article.Title, article.Content)
+		systemPrompt, sources = s.articleSystemPrompt(ctx, article, lastUserMessage(messages), modelName, conversationTokens)
 	} else {
-		systemPrompt = buildGeneralSystemPrompt()
+		systemPrompt = s.prompts.Get(PromptNameChatGeneral)
 	}
 
 	opts := &llm.GenerateOptions{
 		SystemPrompt: systemPrompt,
-		MaxTokens:    2048,
+		MaxTokens:    chatReplyTokens,
 		Temperature:  0.7,
+		Context:      ctx,
+	}
+
+	var stream <-chan llm.StreamChunk
+	model := modelName
+	var err error
+	if modelName == "" {
+		stream, model, err = s.llmRouter.GenerateChatStream(llm.TaskChat, messages, opts)
+	} else {
+		stream, err = s.llmRouter.GenerateChatStreamWithModel(llm.TaskChat, modelName, messages, opts)
+	}
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("LLM chat generation failed: %w", err)
 	}
 
-	stream, model, err := s.llmRouter.GenerateChatStream(llm.TaskChat, messages, opts)
+	return stream, model, sources, nil
+}
+
+// SuggestFollowUps asks a small model for questions the user might ask after a chat reply
+func (s *ChatService) SuggestFollowUps(question, answer string) ([]string, error) {
+	answer, _ = llm.TruncateToTokens(s.llmRouter.PrimaryModel(llm.TaskChatFollowUp), answer, chatFollowUpTokens)
+	prompt := fmt.Sprintf(s.prompts.Get(PromptNameChatFollowUps), question, answer)
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskChatFollowUp, prompt, followUpSchema, &llm.GenerateOptions{
+		Temperature: 0.7,
+		MaxTokens:   300,
+	})
 	if err != nil {
-		return nil, "", fmt.Errorf("LLM chat generation failed: %w", err)
+		return nil, fmt.Errorf("LLM follow-up suggestion failed: %w", err)
 	}
 
-	return stream, model, nil
+	var result struct {
+		Questions []string `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse follow-up questions: %w", err)
+	}
+	questions := make([]string, 0, chatFollowUpCount)
+	for _, q := range result.Questions {
+		if q = strings.TrimSpace(q); q != "" && len(questions) < chatFollowUpCount {
+			questions = append(questions, q)
+		}
+	}
+	return questions, nil
 }
 
 // GetAvailableModels returns the list of available LLM adapters
@@ -108,31 +242,88 @@ func (s *ChatService) GetAvailableModels() []string {
 	return s.llmRouter.ListAvailableAdapters()
 }
 
-// buildChatSystemPrompt builds the system prompt for article-based chat
-func buildChatSystemPrompt(title, content string) string {
-	// Truncate content if too long to avoid token limits
-	maxContentLen := 8000
-	truncatedContent := content
-	if len(content) > maxContentLen {
-		truncatedContent = content[:maxContentLen] + "\n\n[内容已截断...]"
+// ListModels describes the models a chat request can select
+func (s *ChatService) ListModels() []llm.ModelInfo {
+	return s.llmRouter.ListModels()
+}
+
+// generateStream streams a reply from the requested model, or the chat route without one
+func (s *ChatService) generateStream(modelName, prompt string, opts *llm.GenerateOptions) (<-chan llm.StreamChunk, string, error) {
+	if modelName == "" {
+		return s.llmRouter.GenerateStream(llm.TaskChat, prompt, opts)
+	}
+	stream, err := s.llmRouter.GenerateStreamWithModel(llm.TaskChat, modelName, prompt, opts)
+	return stream, modelName, err
+}
+
+// chatModel returns the model a chat request's tokens are counted for
+func (s *ChatService) chatModel(requested string) string {
+	if requested != "" {
+		return requested
 	}
+	return s.llmRouter.PrimaryModel(llm.TaskChat)
+}
+
+// articleSystemPrompt builds the system prompt for article-based chat from the excerpts of
+// the article and related articles most relevant to the question. The truncated article is
+// used instead when retrieval fails, e.g. because the embedding model is unavailable; the
+// prompt then has no sources to cite.
+func (s *ChatService) articleSystemPrompt(ctx context.Context, article *model.Article, question, requested string, conversationTokens int) (string, []ChatSource) {
+	if s.retriever != nil {
+		retrievalCtx, cancel := context.WithTimeout(ctx, chatRetrievalTimeout)
+		defer cancel()
+		budget := min(s.articleTokenBudget(requested, conversationTokens), maxChatContextTokens)
+		grounded, err := s.retriever.Retrieve(retrievalCtx, article, question, s.chatModel(requested), budget)
+		if err != nil {
+			log.Printf("Chat retrieval failed for article %s, using the article text: %v", article.ID, err)
+		} else if len(grounded.Sources) > 0 {
+			return fmt.Sprintf(s.prompts.Get(PromptNameChatSources), article.Title, grounded.Text), grounded.Sources
+		}
+	}
+	return s.buildChatSystemPrompt(article.Title, article.Content, requested, conversationTokens), nil
+}
+
+// articleTokenBudget returns the tokens left for article context in the chat model's
+// context next to the conversation and reply, never below zero. Zero means the
+// conversation does not fit. Without a requested model, the smallest context window on
+// the chat route is assumed.
+func (s *ChatService) articleTokenBudget(requested string, conversationTokens int) int {
+	window := s.llmRouter.TaskContextWindow(llm.TaskChat)
+	if requested != "" {
+		window = llm.ContextWindow(requested)
+	}
+	budget := window - chatReplyTokens - chatPromptReserve - conversationTokens
+	return max(min(budget, maxChatArticleTokens), 0)
+}
 
-	return fmt.Sprintf(`你是一个 Web3 技术助手。用户正在阅读一篇关于「%s」的文章，并对内容有疑问。
+// buildChatSystemPrompt builds the system prompt for article-based chat, truncating the
+// article to what fits in the chat model's context next to the conversation and reply
+func (s *ChatService) buildChatSystemPrompt(title, content, requested string, conversationTokens int) string {
+	budget := s.articleTokenBudget(requested, conversationTokens)
 
-文章内容：
-%s
+	truncatedContent, truncated := llm.TruncateToTokens(s.chatModel(requested), content, budget)
+	if truncated {
+		truncatedContent += "\n\n[内容已截断...]"
+	}
 
-请基于文章内容回答用户的问题。如果问题超出文章范围，可以补充相关知识。
-使用中文回答，保持专业术语的一致性。回答应该准确、清晰、有帮助。`, title, truncatedContent)
+	return fmt.Sprintf(s.prompts.Get(PromptNameChatArticle), title, truncatedContent)
 }
 
-// buildGeneralSystemPrompt builds the system prompt for general Web3 questions
-func buildGeneralSystemPrompt() string {
-	return `你是一个 Web3 技术助手，专门帮助用户理解区块链、加密货币、DeFi、NFT 等 Web3 相关技术。
+// lastUserMessage returns the latest user message of a conversation, the question to retrieve context for
+func lastUserMessage(messages []llm.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
 
-请用中文回答用户的问题，保持专业术语的一致性。回答应该：
-1. 准确、清晰、有帮助
-2. 使用通俗易懂的语言解释复杂概念
-3. 在适当的时候提供示例
-4. 如果涉及风险，请提醒用户注意`
+// countMessageTokens counts the tokens of a conversation's message contents
+func countMessageTokens(modelName string, messages []llm.Message) int {
+	total := 0
+	for _, m := range messages {
+		total += llm.CountTokens(modelName, m.Content)
+	}
+	return total
 }
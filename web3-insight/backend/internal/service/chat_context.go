@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Retrieval limits for chat context
+const (
+	chatArticleChunks    = 6    // Chunks retrieved from the article being read
+	chatRelatedArticles  = 3    // Related articles searched for further chunks
+	chatRelatedChunks    = 3    // Chunks retrieved from related articles, in total
+	chatCorpusArticles   = 5    // Published articles searched for excerpts in knowledge base chat
+	chatCorpusChunks     = 8    // Chunks retrieved across those articles
+	maxChatContextTokens = 4000 // Retrieved excerpts rarely need more; keeps chat cheap on large context windows
+)
+
+// ChatSource is an excerpt given to the model as chat context, cited by its marker
+type ChatSource struct {
+	Marker    int       `json:"marker"` // The [n] the excerpt is labelled with in the prompt
+	ArticleID uuid.UUID `json:"articleId"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	URL       string    `json:"url"` // Site path of the article page
+	Heading   string    `json:"heading,omitempty"`
+}
+
+// ChatContext is the grounded context retrieved for a question
+type ChatContext struct {
+	Text    string       // Excerpts labelled with source markers
+	Sources []ChatSource // In marker order
+}
+
+// ChatRetriever finds the passages most relevant to a chat question, in an article and the
+// articles related to it or across the whole knowledge base
+type ChatRetriever struct {
+	articleRepo *repository.ArticleRepository
+	chunkRepo   *repository.ArticleChunkRepository
+	indexer     *ChunkIndexer
+	adapter     llm.EmbeddingAdapter
+}
+
+// NewChatRetriever creates a chat retriever for an embedding adapter
+func NewChatRetriever(articleRepo *repository.ArticleRepository, chunkRepo *repository.ArticleChunkRepository, adapter llm.EmbeddingAdapter) *ChatRetriever {
+	return &ChatRetriever{
+		articleRepo: articleRepo,
+		chunkRepo:   chunkRepo,
+		indexer:     NewChunkIndexer(chunkRepo, adapter),
+		adapter:     adapter,
+	}
+}
+
+// Retrieve builds the context for a question about an article within maxTokens of the chat
+// model. The article is chunked first if its chunks are missing or out of date; related
+// articles contribute only chunks they already have.
+func (r *ChatRetriever) Retrieve(ctx context.Context, article *model.Article, question, modelName string, maxTokens int) (*ChatContext, error) {
+	if err := r.indexer.EnsureIndexed(ctx, article); err != nil {
+		return nil, fmt.Errorf("failed to index article: %w", err)
+	}
+	vector, err := r.adapter.GenerateEmbedding(question)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed question: %w", err)
+	}
+	query := llm.Float32ToVector(vector)
+
+	matches, err := r.chunkRepo.Nearest([]uuid.UUID{article.ID}, query, chatArticleChunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chunks: %w", err)
+	}
+
+	// Related articles fill in background the article assumes; failures only lose that
+	related, err := r.articleRepo.FindRelatedArticles(article.ID, chatRelatedArticles)
+	if err != nil {
+		log.Printf("Failed to find articles related to %s: %v", article.ID, err)
+	}
+	if len(related) > 0 {
+		ids := make([]uuid.UUID, len(related))
+		for i, a := range related {
+			ids[i] = a.ID
+		}
+		relatedMatches, err := r.chunkRepo.Nearest(ids, query, chatRelatedChunks)
+		if err != nil {
+			log.Printf("Failed to search chunks of related articles: %v", err)
+		}
+		matches = append(matches, relatedMatches...)
+	}
+
+	return buildChatContext(article.ID, matches, modelName, maxTokens), nil
+}
+
+// RetrieveCorpus builds the context for a question about the whole knowledge base within
+// maxTokens of the chat model, from the published articles closest to the question.
+// Articles without current chunks are chunked first.
+func (r *ChatRetriever) RetrieveCorpus(ctx context.Context, question, modelName string, maxTokens int) (*ChatContext, error) {
+	vector, err := r.adapter.GenerateEmbedding(question)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed question: %w", err)
+	}
+	query := llm.Float32ToVector(vector)
+
+	articles, err := r.articleRepo.SemanticSearch(query, chatCorpusArticles, nil, "published", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+	ids := make([]uuid.UUID, 0, len(articles))
+	for i := range articles {
+		if err := r.indexer.EnsureIndexed(ctx, &articles[i]); err != nil {
+			log.Printf("Failed to index article %s for chat: %v", articles[i].ID, err)
+			continue
+		}
+		ids = append(ids, articles[i].ID)
+	}
+
+	matches, err := r.chunkRepo.Nearest(ids, query, chatCorpusChunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chunks: %w", err)
+	}
+	return buildChatContext(uuid.Nil, matches, modelName, maxTokens), nil
+}
+
+// buildChatContext labels the closest chunks that fit in maxTokens with source markers.
+// Excerpts of the article being read come first, in reading order, then the other articles'
+// grouped by article; uuid.Nil means no article is being read.
+func buildChatContext(articleID uuid.UUID, matches []repository.ChunkMatch, modelName string, maxTokens int) *ChatContext {
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	var selected []repository.ChunkMatch
+	used := 0
+	for _, match := range matches {
+		n := llm.CountTokens(modelName, match.Content) + llm.CountTokens(modelName, match.ArticleTitle+match.Heading) + 8
+		if used+n > maxTokens {
+			continue
+		}
+		selected = append(selected, match)
+		used += n
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i], selected[j]
+		if (a.ArticleID == articleID) != (b.ArticleID == articleID) {
+			return a.ArticleID == articleID
+		}
+		if a.ArticleID != b.ArticleID {
+			return a.ArticleTitle < b.ArticleTitle
+		}
+		return a.Position < b.Position
+	})
+
+	result := &ChatContext{}
+	excerpts := make([]string, len(selected))
+	for i, match := range selected {
+		label := fmt.Sprintf("[%d] %s", i+1, match.ArticleTitle)
+		if match.Heading != "" {
+			label += " > " + match.Heading
+		}
+		excerpts[i] = label + "\n" + match.Content
+		result.Sources = append(result.Sources, ChatSource{
+			Marker:    i + 1,
+			ArticleID: match.ArticleID,
+			Title:     match.ArticleTitle,
+			Slug:      match.ArticleSlug,
+			URL:       "/knowledge/" + match.ArticleSlug,
+			Heading:   match.Heading,
+		})
+	}
+	result.Text = strings.Join(excerpts, "\n\n")
+	return result
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Chunk sizing for chat retrieval
+const (
+	chunkMaxTokens      = 400 // Small enough to match one idea, large enough to read on its own
+	chunkEmbedBatchSize = 32  // Chunks embedded per request to the embedding provider
+)
+
+// ChunkIndexer splits articles into sections by markdown heading and paragraph and embeds
+// each one, so chat can retrieve the passages relevant to a question
+type ChunkIndexer struct {
+	chunkRepo *repository.ArticleChunkRepository
+	adapter   llm.EmbeddingAdapter
+}
+
+// NewChunkIndexer creates a chunk indexer for an embedding adapter
+func NewChunkIndexer(chunkRepo *repository.ArticleChunkRepository, adapter llm.EmbeddingAdapter) *ChunkIndexer {
+	return &ChunkIndexer{
+		chunkRepo: chunkRepo,
+		adapter:   adapter,
+	}
+}
+
+// EnsureIndexed indexes an article unless its chunks were built from its current text
+func (c *ChunkIndexer) EnsureIndexed(ctx context.Context, article *model.Article) error {
+	hash, err := c.chunkRepo.IndexedHash(article.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check chunks: %w", err)
+	}
+	if hash == articleContentHash(article) {
+		return nil
+	}
+	return c.Index(ctx, article)
+}
+
+// Index rebuilds and embeds the chunks of an article
+func (c *ChunkIndexer) Index(ctx context.Context, article *model.Article) error {
+	sections := splitIntoChunks(c.adapter.Name(), article.Content, chunkMaxTokens)
+	hash := articleContentHash(article)
+
+	chunks := make([]model.ArticleChunk, len(sections))
+	for start := 0; start < len(sections); start += chunkEmbedBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+chunkEmbedBatchSize, len(sections))
+		texts := make([]string, 0, end-start)
+		for _, section := range sections[start:end] {
+			texts = append(texts, chunkEmbeddingText(article.Title, section))
+		}
+
+		vectors, err := c.adapter.GenerateBatchEmbeddings(texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed chunks: %w", err)
+		}
+		if len(vectors) != len(texts) {
+			return fmt.Errorf("embedding provider returned %d vectors for %d chunks", len(vectors), len(texts))
+		}
+		for i, vector := range vectors {
+			section := sections[start+i]
+			chunks[start+i] = model.ArticleChunk{
+				ArticleID:   article.ID,
+				Position:    start + i,
+				Heading:     section.heading,
+				Content:     section.content,
+				ContentHash: hash,
+				Embedding:   llm.Float32ToVector(vector),
+			}
+		}
+	}
+
+	if err := c.chunkRepo.Replace(article.ID, chunks); err != nil {
+		return fmt.Errorf("failed to store chunks: %w", err)
+	}
+	return nil
+}
+
+// articleContentHash identifies the article text chunks are built from
+func articleContentHash(article *model.Article) string {
+	sum := sha256.Sum256([]byte(article.Title + "\n" + article.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// chunkEmbeddingText prefixes a chunk with its article title and heading, which often name
+// the topic the chunk's text only refers to
+func chunkEmbeddingText(title string, section textChunk) string {
+	parts := []string{"标题: " + title}
+	if section.heading != "" {
+		parts = append(parts, "章节: "+section.heading)
+	}
+	return strings.Join(append(parts, section.content), "\n\n")
+}
+
+// textChunk is a run of paragraphs under one heading
+type textChunk struct {
+	heading string
+	content string
+}
+
+// splitIntoChunks splits markdown into chunks of at most maxTokens. A chunk never spans a
+// heading; paragraphs are kept whole unless one alone exceeds the limit. Code blocks count
+// as one paragraph.
+func splitIntoChunks(modelName, content string, maxTokens int) []textChunk {
+	var chunks []textChunk
+	var heading string
+	var paragraphs []string
+	tokens := 0
+
+	flush := func() {
+		if len(paragraphs) > 0 {
+			chunks = append(chunks, textChunk{heading: heading, content: strings.Join(paragraphs, "\n\n")})
+		}
+		paragraphs, tokens = nil, 0
+	}
+	add := func(paragraph string) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			return
+		}
+		n := llm.CountTokens(modelName, paragraph)
+		if tokens+n > maxTokens {
+			flush()
+		}
+		// An oversized paragraph becomes several chunks of its own
+		for n > maxTokens {
+			part, _ := llm.TruncateToTokens(modelName, paragraph, maxTokens)
+			if part == "" || !strings.HasPrefix(paragraph, part) {
+				break
+			}
+			chunks = append(chunks, textChunk{heading: heading, content: strings.TrimSpace(part)})
+			paragraph = strings.TrimSpace(paragraph[len(part):])
+			n = llm.CountTokens(modelName, paragraph)
+		}
+		if paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+			tokens += n
+		}
+	}
+
+	var current []string
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+		}
+		switch {
+		case inCode || strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			current = append(current, line)
+		case trimmed == "":
+			add(strings.Join(current, "\n"))
+			current = nil
+		case isMarkdownHeading(trimmed):
+			add(strings.Join(current, "\n"))
+			current = nil
+			flush()
+			heading = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		default:
+			current = append(current, line)
+		}
+	}
+	add(strings.Join(current, "\n"))
+	flush()
+	return chunks
+}
+
+// isMarkdownHeading reports whether a line is an ATX heading such as "## Overview"
+func isMarkdownHeading(line string) bool {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	return level >= 1 && level <= 6 && (len(line) == level || line[level] == ' ')
+}
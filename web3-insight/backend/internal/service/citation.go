@@ -0,0 +1,216 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Citation verification modes
+const (
+	CitationModeFlag  = "flag"  // Keep unverified citations but mark them
+	CitationModeStrip = "strip" // Remove unverified links, keeping the link text
+	CitationModeOff   = "off"   // Skip verification entirely
+)
+
+// unverifiedCitationMark is appended after citations that could not be verified
+const unverifiedCitationMark = "（来源未验证）"
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\((https?://[^\s)]+)\)`)
+	bareURLPattern      = regexp.MustCompile(`https?://[^\s)\]>"'，。；）]+`)
+	quotePattern        = regexp.MustCompile(`[“"「]([^”"」]{8,200})[”"」]`)
+)
+
+// CitationCheck describes the verification result of a single cited URL
+type CitationCheck struct {
+	URL        string `json:"url"`
+	Verified   bool   `json:"verified"`
+	Quote      string `json:"quote,omitempty"`
+	QuoteFound *bool  `json:"quoteFound,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// CitationReport summarizes citation verification for a research answer
+type CitationReport struct {
+	Mode       string          `json:"mode"`
+	Citations  []CitationCheck `json:"citations"`
+	Verified   int             `json:"verified"`
+	Unverified int             `json:"unverified"`
+}
+
+// CitationVerifier checks that URLs cited in generated content were actually retrieved
+type CitationVerifier struct {
+	// sources maps normalized URL -> retrieved content (may be empty)
+	sources map[string]string
+}
+
+// NewCitationVerifier creates a verifier from the retrieved sources (URL -> content)
+func NewCitationVerifier(sources map[string]string) *CitationVerifier {
+	normalized := make(map[string]string, len(sources))
+	for u, content := range sources {
+		normalized[normalizeCitationURL(u)] = content
+	}
+	return &CitationVerifier{sources: normalized}
+}
+
+// Verify checks every cited URL in content and returns the rewritten content with the report.
+// In flag mode unverified citations are marked, in strip mode their links are removed.
+func (v *CitationVerifier) Verify(content, mode string) (string, *CitationReport) {
+	if mode == "" {
+		mode = CitationModeFlag
+	}
+	report := &CitationReport{Mode: mode, Citations: []CitationCheck{}}
+	if mode == CitationModeOff {
+		return content, report
+	}
+
+	checked := make(map[string]*CitationCheck)
+
+	check := func(rawURL, context string) *CitationCheck {
+		key := normalizeCitationURL(rawURL)
+		if c, ok := checked[key]; ok {
+			return c
+		}
+
+		c := &CitationCheck{URL: rawURL}
+		sourceContent, ok := v.sources[key]
+		if !ok {
+			c.Reason = "url not among retrieved sources"
+		} else {
+			c.Verified = true
+			// Optionally verify a quoted passage near the citation
+			if m := quotePattern.FindStringSubmatch(context); m != nil && sourceContent != "" {
+				found := containsNormalized(sourceContent, m[1])
+				c.Quote = m[1]
+				c.QuoteFound = &found
+				if !found {
+					c.Verified = false
+					c.Reason = "quoted text not found in source content"
+				}
+			}
+		}
+
+		checked[key] = c
+		report.Citations = append(report.Citations, *c)
+		if c.Verified {
+			report.Verified++
+		} else {
+			report.Unverified++
+		}
+		return c
+	}
+
+	// First pass: markdown links
+	result := replaceWithContext(content, markdownLinkPattern, func(match []string, context string) string {
+		c := check(match[2], context)
+		if c.Verified {
+			return match[0]
+		}
+		if mode == CitationModeStrip {
+			return match[1]
+		}
+		return match[0] + unverifiedCitationMark
+	})
+
+	// Second pass: bare URLs outside markdown links
+	result = replaceBareURLs(result, func(rawURL, context string) string {
+		rawURL = strings.TrimRight(rawURL, ".,;:")
+		c := check(rawURL, context)
+		if c.Verified {
+			return rawURL
+		}
+		if mode == CitationModeStrip {
+			return ""
+		}
+		return rawURL + unverifiedCitationMark
+	})
+
+	return result, report
+}
+
+// replaceWithContext replaces regex matches, passing the surrounding line as context
+func replaceWithContext(content string, re *regexp.Regexp, fn func(match []string, context string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(content, -1) {
+		match := make([]string, len(loc)/2)
+		for i := range match {
+			if loc[2*i] >= 0 {
+				match[i] = content[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		sb.WriteString(content[last:loc[0]])
+		sb.WriteString(fn(match, lineAround(content, loc[0], loc[1])))
+		last = loc[1]
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// replaceBareURLs replaces URLs that are not already part of a markdown link
+func replaceBareURLs(content string, fn func(rawURL, context string) string) string {
+	linkSpans := markdownLinkPattern.FindAllStringIndex(content, -1)
+	inLink := func(start int) bool {
+		for _, span := range linkSpans {
+			if start >= span[0] && start < span[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range bareURLPattern.FindAllStringIndex(content, -1) {
+		if inLink(loc[0]) {
+			continue
+		}
+		sb.WriteString(content[last:loc[0]])
+		sb.WriteString(fn(content[loc[0]:loc[1]], lineAround(content, loc[0], loc[1])))
+		last = loc[1]
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// lineAround returns the line containing the given span
+func lineAround(content string, start, end int) string {
+	lineStart := strings.LastIndex(content[:start], "\n") + 1
+	lineEnd := strings.Index(content[end:], "\n")
+	if lineEnd < 0 {
+		return content[lineStart:]
+	}
+	return content[lineStart : end+lineEnd]
+}
+
+// normalizeCitationURL normalizes a URL for comparison (scheme, www, trailing slash, fragment, tracking params)
+func normalizeCitationURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return strings.TrimRight(strings.ToLower(raw), "/")
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+
+	q := u.Query()
+	for key := range q {
+		if strings.HasPrefix(key, "utm_") {
+			q.Del(key)
+		}
+	}
+
+	normalized := host + strings.TrimRight(u.Path, "/")
+	if encoded := q.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
+
+// containsNormalized reports whether needle appears in haystack ignoring case and whitespace
+func containsNormalized(haystack, needle string) bool {
+	squash := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	return strings.Contains(squash(haystack), squash(needle))
+}
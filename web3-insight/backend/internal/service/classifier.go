@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/llm"
@@ -19,6 +19,7 @@ type Classifier struct {
 	llmRouter    *llm.Router
 	articleRepo  *repository.ArticleRepository
 	categoryRepo *repository.CategoryRepository
+	usage        *UsageRecorder
 }
 
 // NewClassifier creates a new classifier service
@@ -30,12 +31,18 @@ func NewClassifier(router *llm.Router, articleRepo *repository.ArticleRepository
 	}
 }
 
+// SetUsageRecorder enables persisting token usage of classification calls
+func (c *Classifier) SetUsageRecorder(usage *UsageRecorder) {
+	c.usage = usage
+}
+
 // ClassificationResult represents the LLM classification response
 type ClassificationResult struct {
 	Decision      string           `json:"decision"` // "use_existing" or "create_new"
 	CategoryPath  string           `json:"categoryPath"`
 	NewCategory   *NewCategoryInfo `json:"newCategory,omitempty"`
 	SuggestedTags []string         `json:"suggestedTags"`
+	Difficulty    string           `json:"difficulty"` // beginner, intermediate or advanced
 	Confidence    float64          `json:"confidence"`
 	Reasoning     string           `json:"reasoning"`
 }
@@ -49,6 +56,47 @@ type NewCategoryInfo struct {
 	Description string  `json:"description"`
 }
 
+// ClassificationSchema is the JSON schema classification output must match
+var ClassificationSchema = llm.MustJSONSchema("classification", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"decision":     map[string]interface{}{"type": "string", "enum": []interface{}{"use_existing", "create_new"}},
+		"categoryPath": map[string]interface{}{"type": "string"},
+		"newCategory": map[string]interface{}{
+			"type": []interface{}{"object", "null"},
+			"properties": map[string]interface{}{
+				"name":        map[string]interface{}{"type": "string"},
+				"nameEn":      map[string]interface{}{"type": "string"},
+				"parentPath":  map[string]interface{}{"type": []interface{}{"string", "null"}},
+				"icon":        map[string]interface{}{"type": "string"},
+				"description": map[string]interface{}{"type": "string"},
+			},
+			"required": []interface{}{"name"},
+		},
+		"suggestedTags": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"difficulty":    difficultyProperty,
+		"confidence":    map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+		"reasoning":     map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"decision", "categoryPath", "difficulty", "confidence"},
+})
+
+// difficultyProperty is the schema of a difficulty level
+var difficultyProperty = map[string]interface{}{
+	"type": "string",
+	"enum": []interface{}{model.DifficultyBeginner, model.DifficultyIntermediate, model.DifficultyAdvanced},
+}
+
+// difficultySchema is the JSON schema standalone difficulty assessments must match
+var difficultySchema = llm.MustJSONSchema("difficulty", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"difficulty": difficultyProperty,
+		"reasoning":  map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"difficulty"},
+})
+
 // ClassifyArticle classifies an article and returns the suggested category
 func (c *Classifier) ClassifyArticle(ctx context.Context, article *model.Article) (*ClassificationResult, string, error) {
 	// Get category tree for prompt
@@ -68,50 +116,27 @@ func (c *Classifier) ClassifyArticle(ctx context.Context, article *model.Article
 	prompt := fmt.Sprintf(PromptClassification, categoryTree, article.Title, contentSummary)
 
 	// Call LLM
-	response, modelUsed, err := c.llmRouter.Generate(llm.TaskClassification, prompt, &llm.GenerateOptions{
+	startedAt := time.Now()
+	generated, err := c.llmRouter.GenerateStructured(llm.TaskClassification, prompt, ClassificationSchema, &llm.GenerateOptions{
 		Temperature: 0.2, // Very low temperature for consistent classification
 		MaxTokens:   500,
 	})
+	c.usage.Record(model.TaskTypeClassify, map[string]interface{}{"articleId": article.ID}, startedAt, generated, err)
 	if err != nil {
 		return nil, "", fmt.Errorf("LLM classification failed: %w", err)
 	}
 
-	// Parse response
-	result, err := c.parseClassificationResponse(response)
-	if err != nil {
-		return nil, modelUsed, fmt.Errorf("failed to parse classification: %w", err)
-	}
-
-	return result, modelUsed, nil
-}
-
-// parseClassificationResponse extracts JSON from LLM response
-func (c *Classifier) parseClassificationResponse(response string) (*ClassificationResult, error) {
-	response = strings.TrimSpace(response)
-
-	// Remove markdown code blocks
-	response = regexp.MustCompile("(?s)```json\\s*").ReplaceAllString(response, "")
-	response = regexp.MustCompile("(?s)```\\s*").ReplaceAllString(response, "")
-	response = strings.TrimSpace(response)
-
-	// Find JSON object
-	start := strings.Index(response, "{")
-	end := strings.LastIndex(response, "}")
-	if start == -1 || end == -1 || end <= start {
-		return nil, fmt.Errorf("no JSON object found")
-	}
-
-	jsonStr := response[start : end+1]
-
+	// Output is already validated against the schema
 	var result ClassificationResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, generated.Model, fmt.Errorf("failed to parse classification: %w", err)
 	}
 
-	return &result, nil
+	return &result, generated.Model, nil
 }
 
-// getCategoryTreeString returns categories formatted for the prompt
+// getCategoryTreeString returns categories formatted for the prompt, leaving out archived
+// categories and everything below them
 func (c *Classifier) getCategoryTreeString() (string, error) {
 	categories, err := c.categoryRepo.FindAll()
 	if err != nil {
@@ -126,6 +151,9 @@ func (c *Classifier) getCategoryTreeString() (string, error) {
 	}
 
 	for _, cat := range categories {
+		if c.isArchived(&cat, categoryMap) {
+			continue
+		}
 		path := c.buildCategoryPath(&cat, categoryMap)
 		paths = append(paths, "- "+path)
 	}
@@ -147,6 +175,20 @@ func (c *Classifier) buildCategoryPath(cat *model.Category, categoryMap map[uuid
 	return c.buildCategoryPath(parent, categoryMap) + "/" + cat.Name
 }
 
+// isArchived reports whether a category or one of its ancestors is archived
+func (c *Classifier) isArchived(cat *model.Category, categoryMap map[uuid.UUID]*model.Category) bool {
+	for cat != nil {
+		if cat.Archived {
+			return true
+		}
+		if cat.ParentID == nil {
+			return false
+		}
+		cat = categoryMap[*cat.ParentID]
+	}
+	return false
+}
+
 // ClassifyAndUpdate classifies an article and updates it in the database
 func (c *Classifier) ClassifyAndUpdate(ctx context.Context, articleID uuid.UUID) error {
 	article, err := c.articleRepo.GetByID(articleID)
@@ -210,6 +252,18 @@ func (c *Classifier) ClassifyAndUpdate(ctx context.Context, articleID uuid.UUID)
 		}
 	}
 
+	// Archived categories keep their articles but get no new ones
+	if category != nil {
+		archived, err := c.categoryRepo.IsArchived(category.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check category: %w", err)
+		}
+		if archived {
+			log.Printf("Category %s is archived, keeping current category", category.Name)
+			category = nil
+		}
+	}
+
 	// Update article with category if found
 	if category != nil {
 		article.CategoryID = &category.ID
@@ -220,6 +274,10 @@ func (c *Classifier) ClassifyAndUpdate(ctx context.Context, articleID uuid.UUID)
 		article.Tags = result.SuggestedTags
 	}
 
+	if model.IsValidDifficulty(result.Difficulty) {
+		article.Difficulty = result.Difficulty
+	}
+
 	// Log reasoning if provided
 	if result.Reasoning != "" {
 		log.Printf("Classification reasoning: %s", result.Reasoning)
@@ -228,6 +286,47 @@ func (c *Classifier) ClassifyAndUpdate(ctx context.Context, articleID uuid.UUID)
 	return c.articleRepo.Update(article)
 }
 
+// AssessDifficulty asks the LLM how much background an article expects from its reader
+func (c *Classifier) AssessDifficulty(ctx context.Context, article *model.Article) (string, error) {
+	contentSummary := article.Summary
+	if contentSummary == "" {
+		contentSummary = truncateString(article.Content, 500)
+	}
+	prompt := fmt.Sprintf(PromptDifficulty, article.Title, contentSummary)
+
+	startedAt := time.Now()
+	generated, err := c.llmRouter.GenerateStructured(llm.TaskClassification, prompt, difficultySchema, &llm.GenerateOptions{
+		Temperature: 0.2,
+		MaxTokens:   200,
+	})
+	c.usage.Record(model.TaskTypeClassify, map[string]interface{}{"articleId": article.ID, "difficulty": true}, startedAt, generated, err)
+	if err != nil {
+		return "", fmt.Errorf("LLM difficulty assessment failed: %w", err)
+	}
+
+	var result struct {
+		Difficulty string `json:"difficulty"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return "", fmt.Errorf("failed to parse difficulty: %w", err)
+	}
+	return result.Difficulty, nil
+}
+
+// AssessAndUpdateDifficulty assesses an article's difficulty and stores it
+func (c *Classifier) AssessAndUpdateDifficulty(ctx context.Context, articleID uuid.UUID) error {
+	article, err := c.articleRepo.GetByID(articleID)
+	if err != nil {
+		return fmt.Errorf("article not found: %w", err)
+	}
+
+	difficulty, err := c.AssessDifficulty(ctx, article)
+	if err != nil {
+		return err
+	}
+	return c.articleRepo.UpdateDifficulty(articleID, difficulty)
+}
+
 // truncateString truncates a string to maxLen characters
 func truncateString(s string, maxLen int) string {
 	runes := []rune(s)
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Limits for LLM concept link extraction
+const (
+	maxLinkCandidates    = 20
+	minLinkConfidence    = 0.5
+	linkCandidateSummary = 150
+)
+
+// conceptLinkSchema is the JSON schema extracted concept links must match
+var conceptLinkSchema = llm.MustJSONSchema("concept_links", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"links": map[string]interface{}{
+			"type":     "array",
+			"maxItems": 10,
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"index":      map[string]interface{}{"type": "integer", "minimum": 1},
+					"type":       map[string]interface{}{"type": "string", "enum": []interface{}{model.LinkTypeMentions, model.LinkTypeDependsOn, model.LinkTypeContrastsWith}},
+					"confidence": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+					"reason":     map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"index", "type", "confidence"},
+			},
+		},
+	},
+	"required": []interface{}{"links"},
+})
+
+// ConceptLinkService extracts concept graph links between articles with the LLM
+type ConceptLinkService struct {
+	llmRouter   *llm.Router
+	articleRepo *repository.ArticleRepository
+	usage       *UsageRecorder
+}
+
+// NewConceptLinkService creates a new concept link service
+func NewConceptLinkService(router *llm.Router, articleRepo *repository.ArticleRepository) *ConceptLinkService {
+	return &ConceptLinkService{
+		llmRouter:   router,
+		articleRepo: articleRepo,
+	}
+}
+
+// SetUsageRecorder enables persisting token usage of concept link extraction
+func (s *ConceptLinkService) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// ExtractAndUpdate asks the LLM how an article relates to similar articles and stores the
+// answer as LLM-sourced links, replacing earlier extractions. Manual links are kept.
+func (s *ConceptLinkService) ExtractAndUpdate(ctx context.Context, articleID uuid.UUID) ([]model.ArticleLink, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("article not found: %w", err)
+	}
+
+	candidates, err := candidateArticles(s.articleRepo, article, maxLinkCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candidate articles: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []model.ArticleLink{}, nil
+	}
+
+	var list strings.Builder
+	for i, c := range candidates {
+		fmt.Fprintf(&list, "%d. %s：%s\n", i+1, c.Title, truncateString(c.Summary, linkCandidateSummary))
+	}
+	summary := article.Summary
+	if summary == "" {
+		summary = truncateString(article.Content, 500)
+	}
+	prompt := fmt.Sprintf(PromptConceptLinks, article.Title, summary, list.String())
+
+	startedAt := time.Now()
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskClassification, prompt, conceptLinkSchema, &llm.GenerateOptions{
+		Temperature: 0.2,
+		MaxTokens:   1200,
+		Context:     ctx,
+	})
+	s.usage.Record(model.TaskTypeConceptLinks, map[string]interface{}{"articleId": article.ID}, startedAt, generated, err)
+	if err != nil {
+		return nil, fmt.Errorf("LLM concept link extraction failed: %w", err)
+	}
+
+	var result struct {
+		Links []struct {
+			Index      int     `json:"index"`
+			Type       string  `json:"type"`
+			Confidence float64 `json:"confidence"`
+			Reason     string  `json:"reason"`
+		} `json:"links"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse concept links: %w", err)
+	}
+
+	var links []model.ArticleLink
+	seen := make(map[int]bool)
+	for _, l := range result.Links {
+		if l.Index < 1 || l.Index > len(candidates) || seen[l.Index] || !model.IsValidLinkType(l.Type) || l.Confidence < minLinkConfidence {
+			continue
+		}
+		seen[l.Index] = true
+		links = append(links, model.ArticleLink{
+			LinkedID:   candidates[l.Index-1].ID,
+			Type:       l.Type,
+			Confidence: l.Confidence,
+			Reason:     l.Reason,
+		})
+	}
+
+	if err := s.articleRepo.SetLinks(article.ID, model.LinkSourceLLM, links); err != nil {
+		return nil, fmt.Errorf("failed to save concept links: %w", err)
+	}
+
+	return s.articleRepo.ListLinks(article.ID)
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+// consistencySampleSize caps the offending IDs listed per check in a report
+const consistencySampleSize = 20
+
+// ConsistencyCheckResult is the outcome of one invariant check
+type ConsistencyCheckResult struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Count       int      `json:"count"`
+	Fixed       int64    `json:"fixed"`
+	Fixable     bool     `json:"fixable"`           // The check has a safe automatic fix
+	Fix         string   `json:"fix,omitempty"`     // What the automatic fix does
+	Samples     []string `json:"samples,omitempty"` // Up to 20 offending IDs or keys
+	Error       string   `json:"error,omitempty"`
+}
+
+// consistencyCheck detects one broken invariant. find returns the offending IDs or keys;
+// fix repairs them and returns how many rows changed, and is nil when no fix is safe.
+type consistencyCheck struct {
+	name        string
+	description string
+	fixSummary  string
+	find        func(db *gorm.DB) ([]string, error)
+	fix         func(db *gorm.DB, keys []string) (int64, error)
+}
+
+// ConsistencyChecker detects broken invariants in stored content and repairs the safe cases
+type ConsistencyChecker struct {
+	db           *gorm.DB
+	reports      *repository.ConsistencyReportRepository
+	categoryRepo *repository.CategoryRepository
+	dimensions   int
+}
+
+// NewConsistencyChecker creates a consistency checker. dimensions is the configured embedding size.
+func NewConsistencyChecker(db *gorm.DB, reports *repository.ConsistencyReportRepository, categoryRepo *repository.CategoryRepository, dimensions int) *ConsistencyChecker {
+	return &ConsistencyChecker{
+		db:           db,
+		reports:      reports,
+		categoryRepo: categoryRepo,
+		dimensions:   dimensions,
+	}
+}
+
+// Run executes every check, applies safe fixes if autoFix is set, and saves the report.
+// A failing check is recorded in the report and does not stop the others.
+func (c *ConsistencyChecker) Run(ctx context.Context, autoFix bool) (*model.ConsistencyReport, error) {
+	report := &model.ConsistencyReport{AutoFix: autoFix, StartedAt: time.Now()}
+	db := c.db.WithContext(ctx)
+
+	var results []ConsistencyCheckResult
+	for _, check := range c.checks() {
+		result := ConsistencyCheckResult{
+			Name:        check.name,
+			Description: check.description,
+			Fixable:     check.fix != nil,
+			Fix:         check.fixSummary,
+		}
+
+		keys, err := check.find(db)
+		if err != nil {
+			result.Error = err.Error()
+			log.Printf("Consistency check %s failed: %v", check.name, err)
+			results = append(results, result)
+			continue
+		}
+		result.Count = len(keys)
+		result.Samples = keys
+		if len(keys) > consistencySampleSize {
+			result.Samples = keys[:consistencySampleSize]
+		}
+
+		if autoFix && check.fix != nil && len(keys) > 0 {
+			fixed, err := check.fix(db, keys)
+			if err != nil {
+				result.Error = fmt.Sprintf("fix failed: %v", err)
+				log.Printf("Consistency fix %s failed: %v", check.name, err)
+			}
+			result.Fixed = fixed
+			report.FixedCount += int(fixed)
+		}
+
+		report.IssueCount += result.Count
+		results = append(results, result)
+	}
+
+	checks, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode results: %w", err)
+	}
+	report.Checks = checks
+	report.FinishedAt = time.Now()
+
+	if err := c.reports.Create(report); err != nil {
+		return nil, fmt.Errorf("failed to save report: %w", err)
+	}
+	return report, nil
+}
+
+// checks lists the invariants, cheapest first
+func (c *ConsistencyChecker) checks() []consistencyCheck {
+	return []consistencyCheck{
+		{
+			name:        "article_missing_category",
+			description: "Articles whose category no longer exists",
+			fixSummary:  "Clear the category so the article shows as uncategorized",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT a.id::text FROM articles a
+					WHERE a.category_id IS NOT NULL
+					AND NOT EXISTS (SELECT 1 FROM categories c WHERE c.id = a.category_id)`)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				return inBatches(keys, func(batch []string) (int64, error) {
+					result := db.Model(&model.Article{}).Where("id IN ?", batch).Update("category_id", nil)
+					return result.RowsAffected, result.Error
+				})
+			},
+		},
+		{
+			name:        "category_article_count",
+			description: "Categories whose article count differs from their articles",
+			fixSummary:  "Recount the category's articles",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT c.id::text FROM categories c
+					WHERE c.article_count <> (SELECT COUNT(*) FROM articles a WHERE a.category_id = c.id)`)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				var fixed int64
+				for _, key := range keys {
+					id, err := uuid.Parse(key)
+					if err != nil {
+						return fixed, err
+					}
+					// Through the repository so cached category trees are invalidated
+					if err := c.categoryRepo.UpdateArticleCount(id); err != nil {
+						return fixed, err
+					}
+					fixed++
+				}
+				return fixed, nil
+			},
+		},
+		{
+			name:        "article_embedding_dimensions",
+			description: fmt.Sprintf("Article embeddings that are not %d-dimensional", c.dimensions),
+			fixSummary:  "Clear the embedding; regenerate it with the embedding task",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT id::text FROM articles
+					WHERE embedding IS NOT NULL AND vector_dims(embedding) <> ?`, c.dimensions)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				return inBatches(keys, func(batch []string) (int64, error) {
+					result := db.Model(&model.Article{}).Where("id IN ?", batch).Update("embedding", nil)
+					return result.RowsAffected, result.Error
+				})
+			},
+		},
+		{
+			name:        "news_embedding_dimensions",
+			description: fmt.Sprintf("News embeddings that are not %d-dimensional", c.dimensions),
+			fixSummary:  "Clear the embedding",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT id::text FROM news_items
+					WHERE embedding IS NOT NULL AND vector_dims(embedding) <> ?`, c.dimensions)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				return inBatches(keys, func(batch []string) (int64, error) {
+					result := db.Model(&model.NewsItem{}).Where("id IN ?", batch).Update("embedding", nil)
+					return result.RowsAffected, result.Error
+				})
+			},
+		},
+		{
+			name:        "news_processed_without_summary",
+			description: "News items marked processed without a summary",
+			fixSummary:  "Mark the item unprocessed so the summarizer picks it up again",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT id::text FROM news_items
+					WHERE processed AND COALESCE(TRIM(summary), '') = ''`)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				return inBatches(keys, func(batch []string) (int64, error) {
+					result := db.Model(&model.NewsItem{}).Where("id IN ?", batch).
+						Updates(map[string]interface{}{"processed": false, "summary_attempts": 0})
+					return result.RowsAffected, result.Error
+				})
+			},
+		},
+		{
+			name:        "article_slug_collision",
+			description: "Article slugs that differ only in letter case; rename all but one by hand",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT LOWER(slug) FROM articles
+					GROUP BY LOWER(slug) HAVING COUNT(*) > 1 ORDER BY LOWER(slug)`)
+			},
+		},
+		{
+			name:        "category_slug_collision",
+			description: "Category slugs that differ only in letter case; merge or rename them by hand",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT LOWER(slug) FROM categories
+					GROUP BY LOWER(slug) HAVING COUNT(*) > 1 ORDER BY LOWER(slug)`)
+			},
+		},
+		{
+			name:        "orphaned_article_versions",
+			description: "Article versions whose article no longer exists",
+			fixSummary:  "Delete the version",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT v.id::text FROM article_versions v
+					WHERE NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = v.article_id)`)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				return inBatches(keys, func(batch []string) (int64, error) {
+					result := db.Where("id IN ?", batch).Delete(&model.ArticleVersion{})
+					return result.RowsAffected, result.Error
+				})
+			},
+		},
+		{
+			name:        "orphaned_prerequisites",
+			description: "Prerequisite links to or from articles that no longer exist",
+			fixSummary:  "Delete the link",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT p.article_id::text || '>' || p.prerequisite_id::text FROM article_prerequisites p
+					WHERE NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = p.article_id)
+					OR NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = p.prerequisite_id)`)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				result := db.Exec(`DELETE FROM article_prerequisites p
+					WHERE NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = p.article_id)
+					OR NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = p.prerequisite_id)`)
+				return result.RowsAffected, result.Error
+			},
+		},
+		{
+			name:        "orphaned_wiki_pages",
+			description: "Wiki page mappings whose article no longer exists; the remote pages are kept",
+			fixSummary:  "Delete the mapping",
+			find: func(db *gorm.DB) ([]string, error) {
+				return pluckKeys(db, `SELECT w.id::text FROM wiki_pages w
+					WHERE NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = w.article_id)`)
+			},
+			fix: func(db *gorm.DB, keys []string) (int64, error) {
+				return inBatches(keys, func(batch []string) (int64, error) {
+					result := db.Where("id IN ?", batch).Delete(&model.WikiPage{})
+					return result.RowsAffected, result.Error
+				})
+			},
+		},
+	}
+}
+
+// inBatches applies fn to keys in batches that stay within the query parameter limit
+func inBatches(keys []string, fn func(batch []string) (int64, error)) (int64, error) {
+	const batchSize = 1000
+	var total int64
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		n, err := fn(keys[start:end])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// pluckKeys runs a query returning one text column
+func pluckKeys(db *gorm.DB, query string, args ...interface{}) ([]string, error) {
+	var keys []string
+	err := db.Raw(query, args...).Scan(&keys).Error
+	return keys, err
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	displayMu       sync.RWMutex
+	displayLocation = time.UTC
+)
+
+// SetDisplayLocation sets the timezone used for dates written into generated content
+func SetDisplayLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	displayMu.Lock()
+	displayLocation = loc
+	displayMu.Unlock()
+}
+
+// displayDate formats t as a calendar date in the display timezone
+func displayDate(t time.Time) string {
+	displayMu.RLock()
+	loc := displayLocation
+	displayMu.RUnlock()
+	return t.In(loc).Format("2006-01-02")
+}
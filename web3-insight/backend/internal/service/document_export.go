@@ -0,0 +1,409 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"html"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Document export formats
+const (
+	DocumentFormatPDF  = "pdf"
+	DocumentFormatEPUB = "epub"
+)
+
+// ErrPDFNotConfigured is returned for a PDF export without a renderer in the config
+var ErrPDFNotConfigured = errors.New("PDF rendering is not configured")
+
+// documentStyle styles exported documents. Fonts fall back through the CJK faces of common
+// platforms and of the renderer image.
+const documentStyle = `body { font-family: "Noto Serif CJK SC", "Source Han Serif SC", "Songti SC", "SimSun", serif; line-height: 1.7; color: #222; }
+h1, h2, h3, h4, nav { font-family: "Noto Sans CJK SC", "Source Han Sans SC", "PingFang SC", "Microsoft YaHei", sans-serif; }
+h1 { font-size: 1.8em; border-bottom: 1px solid #ddd; padding-bottom: 0.3em; }
+section.chapter { page-break-before: always; break-before: page; }
+pre { background: #f6f8fa; padding: 0.8em; overflow-x: auto; white-space: pre-wrap; word-wrap: break-word; font-size: 0.85em; }
+code { font-family: "JetBrains Mono", "Noto Sans Mono CJK SC", "Menlo", "Consolas", monospace; }
+blockquote { border-left: 4px solid #ddd; margin-left: 0; padding-left: 1em; color: #555; }
+table { border-collapse: collapse; } th, td { border: 1px solid #ddd; padding: 0.3em 0.6em; }
+img { max-width: 100%; }
+nav ol { list-style: none; padding-left: 1.2em; }
+.meta { color: #777; font-size: 0.9em; }
+@page { size: A4; margin: 2cm 1.8cm; }
+`
+
+// DocumentExporter renders articles and categories as PDF or EPUB documents, with a table of
+// contents built from article titles and headings
+type DocumentExporter struct {
+	articleRepo    *repository.ArticleRepository
+	categoryRepo   *repository.CategoryRepository
+	pdfRendererURL string
+	client         *http.Client
+}
+
+// NewDocumentExporter creates a new document exporter. PDF export needs the URL of a Gotenberg
+// instance; EPUB export works without one.
+func NewDocumentExporter(articleRepo *repository.ArticleRepository, categoryRepo *repository.CategoryRepository, pdfRendererURL string) *DocumentExporter {
+	return &DocumentExporter{
+		articleRepo:    articleRepo,
+		categoryRepo:   categoryRepo,
+		pdfRendererURL: strings.TrimRight(pdfRendererURL, "/"),
+		client:         &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Document is an export of one or more articles, one chapter each
+type Document struct {
+	ID       uuid.UUID
+	Title    string
+	Subtitle string
+	Chapters []DocumentChapter
+}
+
+// DocumentChapter is an article rendered for export
+type DocumentChapter struct {
+	Title  string
+	Anchor string
+	HTML   string // Well-formed XHTML body of the article
+	TOC    []markdown.TOCEntry
+}
+
+// IsValidDocumentFormat reports whether format is a supported export format
+func IsValidDocumentFormat(format string) bool {
+	return format == DocumentFormatPDF || format == DocumentFormatEPUB
+}
+
+// ArticleDocument builds a document of one article, whatever its status
+func (s *DocumentExporter) ArticleDocument(id uuid.UUID) (*Document, error) {
+	article, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{ID: article.ID, Title: article.Title, Subtitle: article.Summary}
+	chapter, err := documentChapter(article, 1)
+	if err != nil {
+		return nil, err
+	}
+	doc.Chapters = append(doc.Chapters, *chapter)
+	return doc, nil
+}
+
+// CategoryDocument builds a document of the published articles in a category and its
+// subcategories, ordered so prerequisites come first
+func (s *DocumentExporter) CategoryDocument(id uuid.UUID) (*Document, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs, err := s.categoryRepo.SubtreeIDs(id)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := s.articleRepo.ListPublished(categoryIDs, -1)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := s.articleRepo.ListPrerequisitesAmong(articleIDs(articles))
+	if err != nil {
+		return nil, err
+	}
+	articles = OrderByPrerequisites(articles, edges)
+
+	doc := &Document{ID: category.ID, Title: category.Name, Subtitle: category.Description}
+	for i := range articles {
+		chapter, err := documentChapter(&articles[i], i+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", articles[i].Title, err)
+		}
+		doc.Chapters = append(doc.Chapters, *chapter)
+	}
+	return doc, nil
+}
+
+// documentChapter renders an article as chapter n. Heading anchors are prefixed with the
+// chapter's so they stay unique when chapters share a page.
+func documentChapter(article *model.Article, n int) (*DocumentChapter, error) {
+	rendered, err := markdown.RenderHTML(article.Content)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("ch%d-", n)
+	body, err := toXHTML(rendered, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &DocumentChapter{
+		Title:  article.Title,
+		Anchor: fmt.Sprintf("ch%d", n),
+		HTML:   body,
+		TOC:    prefixAnchors(markdown.ExtractTOC(article.Content), prefix),
+	}, nil
+}
+
+// PDF renders a document as PDF through the configured renderer
+func (s *DocumentExporter) PDF(ctx context.Context, doc *Document) ([]byte, error) {
+	if s.pdfRendererURL == "" {
+		return nil, ErrPDFNotConfigured
+	}
+
+	var page strings.Builder
+	fmt.Fprintf(&page, "<!DOCTYPE html>\n<html lang=\"zh-CN\"><head><meta charset=\"utf-8\"/><title>%s</title><style>%s</style></head><body>\n",
+		html.EscapeString(doc.Title), documentStyle)
+	writeTitlePage(&page, doc)
+	page.WriteString("<nav><h2>目录</h2>\n")
+	writeTOC(&page, doc, "")
+	page.WriteString("</nav>\n")
+	for _, c := range doc.Chapters {
+		fmt.Fprintf(&page, "<section class=\"chapter\" id=\"%s\">\n<h1>%s</h1>\n%s\n</section>\n", c.Anchor, html.EscapeString(c.Title), c.HTML)
+	}
+	page.WriteString("</body></html>\n")
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("files", "index.html")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(file, page.String()); err != nil {
+		return nil, err
+	}
+	for field, value := range map[string]string{"printBackground": "true", "preferCssPageSize": "true", "generateDocumentOutline": "true"} {
+		if err := form.WriteField(field, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.pdfRendererURL+"/forms/chromium/convert/html", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("PDF renderer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("PDF renderer returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// EPUB packages a document as an EPUB 3 book with one XHTML file per chapter
+func (s *DocumentExporter) EPUB(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	book := zip.NewWriter(&buf)
+	add := func(name, content string, method uint16) error {
+		w, err := book.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, content)
+		return err
+	}
+
+	// The mimetype entry must come first, stored uncompressed with no extra field or data
+	// descriptor, so readers find the media type at a fixed offset. CreateRaw writes the
+	// header as given, where CreateHeader would add a timestamp field and flag 0x8.
+	mimetype := []byte("application/epub+zip")
+	w, err := book.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(mimetype),
+		CompressedSize64:   uint64(len(mimetype)),
+		UncompressedSize64: uint64(len(mimetype)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(mimetype); err != nil {
+		return nil, err
+	}
+	type entry struct{ name, content string }
+	files := []entry{
+		{"META-INF/container.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>
+`},
+		{"OEBPS/style.css", documentStyle},
+	}
+
+	var title strings.Builder
+	writeTitlePage(&title, doc)
+	files = append(files, entry{"OEBPS/title.xhtml", epubPage(doc.Title, title.String())})
+
+	var nav strings.Builder
+	nav.WriteString("<nav epub:type=\"toc\" id=\"toc\"><h1>目录</h1>\n")
+	writeTOC(&nav, doc, ".xhtml")
+	nav.WriteString("</nav>\n")
+	files = append(files, entry{"OEBPS/nav.xhtml", epubPage("目录", nav.String())})
+
+	var manifest, spine strings.Builder
+	manifest.WriteString(`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="style" href="style.css" media-type="text/css"/>
+<item id="title" href="title.xhtml" media-type="application/xhtml+xml"/>
+`)
+	spine.WriteString("<itemref idref=\"title\"/>\n<itemref idref=\"nav\"/>\n")
+	for _, c := range doc.Chapters {
+		files = append(files, entry{"OEBPS/" + c.Anchor + ".xhtml", epubPage(c.Title, fmt.Sprintf("<section id=\"%s\">\n<h1>%s</h1>\n%s\n</section>", c.Anchor, html.EscapeString(c.Title), c.HTML))})
+		properties := ""
+		if strings.Contains(c.HTML, "src=\"http") {
+			properties = ` properties="remote-resources"`
+		}
+		fmt.Fprintf(&manifest, "<item id=\"%s\" href=\"%s.xhtml\" media-type=\"application/xhtml+xml\"%s/>\n", c.Anchor, c.Anchor, properties)
+		fmt.Fprintf(&spine, "<itemref idref=\"%s\"/>\n", c.Anchor)
+	}
+
+	opf := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="zh-CN">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="book-id">urn:uuid:%s</dc:identifier>
+<dc:title>%s</dc:title>
+<dc:language>zh-CN</dc:language>
+<dc:creator>Web3 Insight</dc:creator>
+<meta property="dcterms:modified">%s</meta>
+</metadata>
+<manifest>
+%s</manifest>
+<spine>
+%s</spine>
+</package>
+`, doc.ID, html.EscapeString(doc.Title), time.Now().UTC().Format("2006-01-02T15:04:05Z"), manifest.String(), spine.String())
+
+	files = append(files, entry{"OEBPS/content.opf", opf})
+
+	for _, f := range files {
+		if err := add(f.name, f.content, zip.Deflate); err != nil {
+			return nil, err
+		}
+	}
+	if err := book.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// epubPage wraps a body in an XHTML content document
+func epubPage(title, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="zh-CN" xml:lang="zh-CN">
+<head><meta charset="utf-8"/><title>%s</title><link rel="stylesheet" type="text/css" href="style.css"/></head>
+<body>
+%s
+</body>
+</html>
+`, html.EscapeString(title), body)
+}
+
+// writeTitlePage writes the title and export date of a document
+func writeTitlePage(w *strings.Builder, doc *Document) {
+	fmt.Fprintf(w, "<h1>%s</h1>\n", html.EscapeString(doc.Title))
+	if doc.Subtitle != "" {
+		fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(doc.Subtitle))
+	}
+	fmt.Fprintf(w, "<p class=\"meta\">Web3 Insight · %s</p>\n", time.Now().UTC().Format("2006-01-02"))
+}
+
+// writeTOC writes the chapters and their headings as nested lists. With a file suffix,
+// chapters are linked as separate files; otherwise as anchors on the same page.
+func writeTOC(w *strings.Builder, doc *Document, fileSuffix string) {
+	href := func(chapter, anchor string) string {
+		if fileSuffix == "" {
+			if anchor == "" {
+				return "#" + chapter
+			}
+			return "#" + anchor
+		}
+		if anchor == "" {
+			return chapter + fileSuffix
+		}
+		return chapter + fileSuffix + "#" + anchor
+	}
+
+	var entries func(chapter string, toc []markdown.TOCEntry)
+	entries = func(chapter string, toc []markdown.TOCEntry) {
+		if len(toc) == 0 {
+			return
+		}
+		w.WriteString("<ol>\n")
+		for _, e := range toc {
+			fmt.Fprintf(w, "<li><a href=\"%s\">%s</a>", html.EscapeString(href(chapter, e.Anchor)), html.EscapeString(e.Title))
+			entries(chapter, e.Children)
+			w.WriteString("</li>\n")
+		}
+		w.WriteString("</ol>\n")
+	}
+
+	w.WriteString("<ol>\n")
+	for _, c := range doc.Chapters {
+		fmt.Fprintf(w, "<li><a href=\"%s\">%s</a>", html.EscapeString(href(c.Anchor, "")), html.EscapeString(c.Title))
+		entries(c.Anchor, c.TOC)
+		w.WriteString("</li>\n")
+	}
+	w.WriteString("</ol>\n")
+}
+
+// toXHTML re-serializes rendered HTML as well-formed XHTML, prefixing element ids and
+// in-page links so several articles can share a document
+func toXHTML(fragment, idPrefix string) (string, error) {
+	parent := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := xhtml.ParseFragment(strings.NewReader(fragment), parent)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		prefixIDs(n, idPrefix)
+		if err := xhtml.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// prefixIDs prefixes the id attributes and fragment links of a node tree
+func prefixIDs(n *xhtml.Node, prefix string) {
+	if n.Type == xhtml.ElementNode {
+		for i, a := range n.Attr {
+			switch {
+			case a.Key == "id":
+				n.Attr[i].Val = prefix + a.Val
+			case a.Key == "href" && strings.HasPrefix(a.Val, "#"):
+				n.Attr[i].Val = "#" + prefix + a.Val[1:]
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		prefixIDs(c, prefix)
+	}
+}
+
+// prefixAnchors prefixes the anchors of a table of contents
+func prefixAnchors(toc []markdown.TOCEntry, prefix string) []markdown.TOCEntry {
+	for i := range toc {
+		toc[i].Anchor = prefix + toc[i].Anchor
+		toc[i].Children = prefixAnchors(toc[i].Children, prefix)
+	}
+	return toc
+}
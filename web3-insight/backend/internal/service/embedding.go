@@ -13,16 +13,22 @@ import (
 	"github.com/user/web3-insight/internal/repository"
 )
 
+// Token budget for article content in embedding input
+const (
+	embeddingTokenMargin      = 64   // Room for the "内容:" label, separators and tokenizer estimate error
+	maxEmbeddingContentTokens = 2048 // Longer content dilutes the embedding of the article's main topic
+)
+
 // EmbeddingService handles embedding generation and management
 type EmbeddingService struct {
 	articleRepo *repository.ArticleRepository
 	adapter     llm.EmbeddingAdapter
+	chunks      *ChunkIndexer
 }
 
 // NewEmbeddingService creates a new embedding service
 func NewEmbeddingService(articleRepo *repository.ArticleRepository, cfg *config.LLMConfig) *EmbeddingService {
-	// Use Ollama for embeddings by default
-	adapter := llm.DefaultOllamaEmbeddingAdapter(cfg.OllamaHost)
+	adapter := llm.NewEmbeddingAdapterFromConfig(cfg)
 
 	return &EmbeddingService{
 		articleRepo: articleRepo,
@@ -38,6 +44,11 @@ func NewEmbeddingServiceWithAdapter(articleRepo *repository.ArticleRepository, a
 	}
 }
 
+// SetChunkIndexer also rebuilds an article's chat retrieval chunks whenever its embedding is generated
+func (s *EmbeddingService) SetChunkIndexer(chunks *ChunkIndexer) {
+	s.chunks = chunks
+}
+
 // GenerateForArticle generates and stores embedding for an article
 func (s *EmbeddingService) GenerateForArticle(ctx context.Context, articleID uuid.UUID) error {
 	article, err := s.articleRepo.GetByID(articleID)
@@ -66,6 +77,13 @@ func (s *EmbeddingService) GenerateAndStore(ctx context.Context, article *model.
 	}
 
 	log.Printf("Generated embedding for article: %s (dimensions: %d)", article.Title, len(embedding))
+
+	// Chat builds missing chunks on demand, so a failure here only delays them
+	if s.chunks != nil {
+		if err := s.chunks.Index(ctx, article); err != nil {
+			log.Printf("Failed to index chunks for article %s: %v", article.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -88,14 +106,20 @@ func (s *EmbeddingService) prepareTextForEmbedding(article *model.Article) strin
 		parts = append(parts, "标签: "+strings.Join(article.Tags, ", "))
 	}
 
-	// Content - truncate if too long (embedding models have limits)
+	// Content fills what is left of the embedding model's context window
 	if article.Content != "" {
-		content := article.Content
-		// Truncate to ~4000 chars to keep within typical embedding model limits
-		if len(content) > 4000 {
-			content = content[:4000] + "..."
+		modelName := s.adapter.Name()
+		header := strings.Join(parts, "\n\n")
+		budget := llm.ContextWindow(modelName) - llm.CountTokens(modelName, header) - embeddingTokenMargin
+		if budget > maxEmbeddingContentTokens {
+			budget = maxEmbeddingContentTokens
+		}
+		if content, truncated := llm.TruncateToTokens(modelName, article.Content, budget); content != "" {
+			if truncated {
+				content += "..."
+			}
+			parts = append(parts, "内容: "+content)
 		}
-		parts = append(parts, "内容: "+content)
 	}
 
 	return strings.Join(parts, "\n\n")
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/user/web3-insight/internal/database"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// defaultReindexBatchSize is the number of articles embedded per request to the embedding provider
+const defaultReindexBatchSize = 32
+
+// ErrReindexCancelled is returned when the re-index task record was cancelled mid-run
+var ErrReindexCancelled = errors.New("embedding re-index cancelled")
+
+// EmbeddingReindexProgress is stored as the result of the re-index task record after every batch
+type EmbeddingReindexProgress struct {
+	Model      string `json:"model"`
+	Dimensions int    `json:"dimensions"`
+	Total      int64  `json:"total"`    // Articles to embed when the run started
+	Embedded   int    `json:"embedded"` // Articles embedded by this run
+	Failed     int    `json:"failed"`   // Articles left without an embedding; listed as pending in the pipeline stats
+	Swapped    bool   `json:"swapped"`  // The new vectors replaced the old column
+
+	NewsEmbedded   int `json:"newsEmbedded"`   // News items embedded again after the swap cleared them
+	ChunksEmbedded int `json:"chunksEmbedded"` // Article chunks embedded again after the swap cleared them
+}
+
+// EmbeddingReindexer re-embeds every article with the configured adapter after an embedding
+// model or dimension change. Vectors go into a shadow column while semantic search keeps
+// using the old ones, and the columns are swapped at the end. The swap clears the news and
+// chunk vectors, which are then embedded again.
+type EmbeddingReindexer struct {
+	db        *gorm.DB
+	adapter   llm.EmbeddingAdapter
+	embedding *EmbeddingService
+	taskRepo  *repository.TaskRepository
+}
+
+// NewEmbeddingReindexer creates a re-indexer for an embedding adapter
+func NewEmbeddingReindexer(db *gorm.DB, adapter llm.EmbeddingAdapter, taskRepo *repository.TaskRepository) *EmbeddingReindexer {
+	return &EmbeddingReindexer{
+		db:        db,
+		adapter:   adapter,
+		embedding: NewEmbeddingServiceWithAdapter(repository.NewArticleRepository(db), adapter),
+		taskRepo:  taskRepo,
+	}
+}
+
+// CreateEmbeddingReindexTask records a pending re-index, or returns the one already pending or running
+func CreateEmbeddingReindexTask(taskRepo *repository.TaskRepository) (*model.Task, bool, error) {
+	active, err := taskRepo.FindActive(model.TaskTypeEmbeddingReindex)
+	if err == nil {
+		return active, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	task := &model.Task{Type: model.TaskTypeEmbeddingReindex, Status: model.TaskStatusPending}
+	if err := taskRepo.Create(task); err != nil {
+		return nil, false, err
+	}
+	return task, true, nil
+}
+
+// Run re-embeds all articles and swaps the vector columns, recording progress on the task.
+// Articles created during the run are picked up by a final pass; a run that stopped early
+// resumes from the articles still without a new vector.
+func (r *EmbeddingReindexer) Run(ctx context.Context, taskID uuid.UUID, batchSize int) (*EmbeddingReindexProgress, error) {
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatchSize
+	}
+	task, err := r.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task: %w", err)
+	}
+
+	progress := &EmbeddingReindexProgress{Model: r.adapter.Name(), Dimensions: r.adapter.Dimensions()}
+	now := time.Now()
+	task.Status = model.TaskStatusRunning
+	task.StartedAt = &now
+	task.ModelUsed = progress.Model
+	task.Error = ""
+	if err := r.save(task, progress); err != nil {
+		return nil, err
+	}
+
+	err = r.run(ctx, task, progress, batchSize)
+	completed := time.Now()
+	task.CompletedAt = &completed
+	switch {
+	case errors.Is(err, ErrReindexCancelled):
+		task.Status = model.TaskStatusCancelled
+	case err != nil:
+		task.Status = model.TaskStatusFailed
+		task.Error = err.Error()
+	default:
+		task.Status = model.TaskStatusCompleted
+	}
+	if saveErr := r.save(task, progress); saveErr != nil {
+		log.Printf("Failed to save re-index task %s: %v", task.ID, saveErr)
+	}
+	return progress, err
+}
+
+func (r *EmbeddingReindexer) run(ctx context.Context, task *model.Task, progress *EmbeddingReindexProgress, batchSize int) error {
+	if err := database.PrepareEmbeddingReindex(r.db, progress.Dimensions); err != nil {
+		return err
+	}
+	pending := r.db.Model(&model.Article{}).Where(database.EmbeddingReindexColumn + " IS NULL")
+	if err := pending.Count(&progress.Total).Error; err != nil {
+		return fmt.Errorf("failed to count articles: %w", err)
+	}
+	log.Printf("Re-indexing %d articles with %s (%d dimensions)", progress.Total, progress.Model, progress.Dimensions)
+
+	start := time.Now()
+	if err := r.pass(ctx, task, progress, batchSize, nil); err != nil {
+		return err
+	}
+	// Articles created during the first pass were embedded with the old model or not at all
+	if err := r.pass(ctx, task, progress, batchSize, &start); err != nil {
+		return err
+	}
+
+	if err := database.SwapEmbeddingColumns(r.db, progress.Dimensions); err != nil {
+		return err
+	}
+	progress.Swapped = true
+	log.Printf("Re-index complete: %d embedded, %d failed; vector columns are now %d-dimensional", progress.Embedded, progress.Failed, progress.Dimensions)
+
+	if err := r.reembedNews(ctx, task, progress, batchSize); err != nil {
+		return err
+	}
+	if err := r.reembedChunks(ctx, task, progress, batchSize); err != nil {
+		return err
+	}
+	log.Printf("Re-embedded %d news items and %d article chunks", progress.NewsEmbedded, progress.ChunksEmbedded)
+	return nil
+}
+
+// reembedNews embeds the news items the swap left without a vector, in ID order. A batch
+// that fails is logged and skipped; clustering embeds recent items it finds without one.
+func (r *EmbeddingReindexer) reembedNews(ctx context.Context, task *model.Task, progress *EmbeddingReindexProgress, batchSize int) error {
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.checkCancelled(task.ID); err != nil {
+			return err
+		}
+
+		var items []model.NewsItem
+		err := r.db.Omit("embedding").
+			Where("embedding IS NULL AND id > ?", after).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&items).Error
+		if err != nil {
+			return fmt.Errorf("failed to load news: %w", err)
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		after = items[len(items)-1].ID
+
+		texts := make([]string, len(items))
+		for i := range items {
+			texts[i] = newsEmbeddingText(&items[i])
+		}
+		vectors := r.embedTexts(texts, progress.Dimensions)
+		for i, item := range items {
+			if vectors[i] == nil {
+				continue
+			}
+			if err := r.db.Table("news_items").Where("id = ?", item.ID).UpdateColumn("embedding", vectors[i]).Error; err != nil {
+				log.Printf("Failed to store embedding for news %s: %v", item.ID, err)
+				continue
+			}
+			progress.NewsEmbedded++
+		}
+		if err := r.saveProgress(task.ID, progress); err != nil {
+			return err
+		}
+	}
+}
+
+// reembedChunks embeds the article chunks the swap left without a vector, in ID order. A
+// batch that fails is logged and skipped; chat re-indexes articles whose chunks lack one.
+func (r *EmbeddingReindexer) reembedChunks(ctx context.Context, task *model.Task, progress *EmbeddingReindexProgress, batchSize int) error {
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.checkCancelled(task.ID); err != nil {
+			return err
+		}
+
+		var chunks []struct {
+			ID      uuid.UUID
+			Heading string
+			Content string
+			Title   string
+		}
+		err := r.db.Table("article_chunks c").
+			Select("c.id, c.heading, c.content, a.title").
+			Joins("JOIN articles a ON a.id = c.article_id").
+			Where("c.embedding IS NULL AND c.id > ?", after).
+			Order("c.id ASC").
+			Limit(batchSize).
+			Scan(&chunks).Error
+		if err != nil {
+			return fmt.Errorf("failed to load chunks: %w", err)
+		}
+		if len(chunks) == 0 {
+			return nil
+		}
+		after = chunks[len(chunks)-1].ID
+
+		texts := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			texts[i] = chunkEmbeddingText(chunk.Title, textChunk{heading: chunk.Heading, content: chunk.Content})
+		}
+		vectors := r.embedTexts(texts, progress.Dimensions)
+		for i, chunk := range chunks {
+			if vectors[i] == nil {
+				continue
+			}
+			if err := r.db.Table("article_chunks").Where("id = ?", chunk.ID).UpdateColumn("embedding", vectors[i]).Error; err != nil {
+				log.Printf("Failed to store embedding for chunk %s: %v", chunk.ID, err)
+				continue
+			}
+			progress.ChunksEmbedded++
+		}
+		if err := r.saveProgress(task.ID, progress); err != nil {
+			return err
+		}
+	}
+}
+
+// embedTexts embeds texts in one request, returning nil for every text if the request fails
+// and for each vector that does not have the expected size
+func (r *EmbeddingReindexer) embedTexts(texts []string, dimensions int) []*pgvector.Vector {
+	result := make([]*pgvector.Vector, len(texts))
+	vectors, err := r.adapter.GenerateBatchEmbeddings(texts)
+	if err != nil || len(vectors) != len(texts) {
+		log.Printf("Failed to embed a batch of %d texts: %v", len(texts), err)
+		return result
+	}
+	for i, vector := range vectors {
+		if len(vector) == dimensions {
+			result[i] = llm.Float32ToVector(vector)
+		}
+	}
+	return result
+}
+
+// pass embeds every article still without a new vector, in ID order, optionally only those
+// created since a time
+func (r *EmbeddingReindexer) pass(ctx context.Context, task *model.Task, progress *EmbeddingReindexProgress, batchSize int, since *time.Time) error {
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.checkCancelled(task.ID); err != nil {
+			return err
+		}
+
+		query := r.db.Omit("embedding").Where(database.EmbeddingReindexColumn+" IS NULL AND id > ?", after)
+		if since != nil {
+			query = query.Where("created_at >= ?", *since)
+		}
+		var articles []model.Article
+		err := query.Order("id ASC").
+			Limit(batchSize).
+			Find(&articles).Error
+		if err != nil {
+			return fmt.Errorf("failed to load articles: %w", err)
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+		after = articles[len(articles)-1].ID
+
+		r.embedBatch(articles, progress)
+		if err := r.saveProgress(task.ID, progress); err != nil {
+			return err
+		}
+	}
+}
+
+// embedBatch embeds articles in one request, falling back to one request per article so a
+// single bad input does not fail the batch
+func (r *EmbeddingReindexer) embedBatch(articles []model.Article, progress *EmbeddingReindexProgress) {
+	texts := make([]string, len(articles))
+	for i := range articles {
+		texts[i] = r.embedding.prepareTextForEmbedding(&articles[i])
+	}
+
+	vectors, err := r.adapter.GenerateBatchEmbeddings(texts)
+	if err != nil || len(vectors) != len(articles) {
+		vectors = make([][]float32, len(articles))
+		for i, text := range texts {
+			if vectors[i], err = r.adapter.GenerateEmbedding(text); err != nil {
+				log.Printf("Failed to embed article %s: %v", articles[i].ID, err)
+			}
+		}
+	}
+
+	for i, article := range articles {
+		if len(vectors[i]) != progress.Dimensions {
+			progress.Failed++
+			continue
+		}
+		err := r.db.Table("articles").Where("id = ?", article.ID).
+			UpdateColumn(database.EmbeddingReindexColumn, llm.Float32ToVector(vectors[i])).Error
+		if err != nil {
+			log.Printf("Failed to store embedding for article %s: %v", article.ID, err)
+			progress.Failed++
+			continue
+		}
+		progress.Embedded++
+	}
+}
+
+// checkCancelled stops the run when the task was cancelled through the task API
+func (r *EmbeddingReindexer) checkCancelled(taskID uuid.UUID) error {
+	task, err := r.taskRepo.GetByID(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to load task: %w", err)
+	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrReindexCancelled
+	}
+	return nil
+}
+
+// saveProgress stores the progress without overwriting a cancellation
+func (r *EmbeddingReindexer) saveProgress(taskID uuid.UUID, progress *EmbeddingReindexProgress) error {
+	result, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	if err := r.taskRepo.UpdateResult(taskID, datatypes.JSON(result)); err != nil {
+		return fmt.Errorf("failed to save progress: %w", err)
+	}
+	return nil
+}
+
+// save stores the task record with its progress
+func (r *EmbeddingReindexer) save(task *model.Task, progress *EmbeddingReindexProgress) error {
+	result, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	task.Result = datatypes.JSON(result)
+	if err := r.taskRepo.Update(task); err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	return nil
+}
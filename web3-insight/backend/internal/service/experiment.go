@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// experimentPrompts lists the tasks that support experiments and the default prompt
+// a variant's prompt replaces; variants must keep the same %s placeholders
+var experimentPrompts = map[string]string{
+	llm.TaskContentGeneration: PromptKnowledgeArticle,
+}
+
+// ExperimentService assigns LLM requests to experiment variants and records their outcomes
+type ExperimentService struct {
+	repo *repository.ExperimentRepository
+}
+
+// NewExperimentService creates a new experiment service
+func NewExperimentService(repo *repository.ExperimentRepository) *ExperimentService {
+	return &ExperimentService{repo: repo}
+}
+
+// ExperimentAssignment is the variant chosen for one request
+type ExperimentAssignment struct {
+	ExperimentID uuid.UUID
+	Variant      model.ExperimentVariant
+}
+
+// Prompt returns the variant's prompt template, or defaultPrompt if it doesn't override it
+func (a *ExperimentAssignment) Prompt(defaultPrompt string) string {
+	if a == nil || a.Variant.Prompt == "" {
+		return defaultPrompt
+	}
+	return a.Variant.Prompt
+}
+
+// Model returns the variant's model override, or "" to use normal routing
+func (a *ExperimentAssignment) Model() string {
+	if a == nil {
+		return ""
+	}
+	return a.Variant.Model
+}
+
+// Validate checks an experiment before it is created
+func (s *ExperimentService) Validate(experiment *model.Experiment) error {
+	defaultPrompt, ok := experimentPrompts[experiment.Task]
+	if !ok {
+		return fmt.Errorf("experiments are not supported for task: %s", experiment.Task)
+	}
+	if len(experiment.Variants) < 2 {
+		return fmt.Errorf("an experiment needs at least two variants")
+	}
+
+	placeholders := strings.Count(defaultPrompt, "%s")
+	total := 0
+	for _, variant := range experiment.Variants {
+		if variant.Traffic < 0 || variant.Traffic > 100 {
+			return fmt.Errorf("variant %s: traffic must be between 0 and 100", variant.Name)
+		}
+		if variant.Prompt != "" && strings.Count(variant.Prompt, "%s") != placeholders {
+			return fmt.Errorf("variant %s: prompt must contain exactly %d %%s placeholders", variant.Name, placeholders)
+		}
+		total += variant.Traffic
+	}
+	if total > 100 {
+		return fmt.Errorf("variant traffic adds up to %d%%, must be at most 100%%", total)
+	}
+	return nil
+}
+
+// Assign picks a variant of the task's active experiment by traffic share. Traffic not
+// allocated to any variant gets nil and uses the defaults. A nil service never assigns.
+func (s *ExperimentService) Assign(task string) *ExperimentAssignment {
+	if s == nil {
+		return nil
+	}
+
+	experiment, err := s.repo.FindActiveByTask(task)
+	if err != nil {
+		return nil
+	}
+
+	roll := rand.Intn(100)
+	for _, variant := range experiment.Variants {
+		if roll < variant.Traffic {
+			return &ExperimentAssignment{ExperimentID: experiment.ID, Variant: variant}
+		}
+		roll -= variant.Traffic
+	}
+	return nil
+}
+
+// RecordRun stores the outcome of a request served by a variant. A nil assignment is a no-op.
+func (s *ExperimentService) RecordRun(assignment *ExperimentAssignment, articleID *uuid.UUID, result *llm.GenerateResult, duration time.Duration, qualityScore *float64, callErr error) {
+	if s == nil || assignment == nil {
+		return
+	}
+
+	run := &model.ExperimentRun{
+		ExperimentID: assignment.ExperimentID,
+		VariantID:    assignment.Variant.ID,
+		ArticleID:    articleID,
+		QualityScore: qualityScore,
+		DurationMs:   duration.Milliseconds(),
+	}
+	if result != nil {
+		run.ModelUsed = result.Model
+		run.TokensUsed = result.Usage.TotalTokens()
+		run.CostUSD = decimal.NewFromFloat(result.CostUSD)
+	}
+	if callErr != nil {
+		run.Error = callErr.Error()
+	}
+
+	if err := s.repo.CreateRun(run); err != nil {
+		log.Printf("Failed to record experiment run for variant %s: %v", assignment.Variant.Name, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"regexp"
 	"strings"
 	"time"
@@ -22,6 +23,10 @@ type Generator struct {
 	articleRepo *repository.ArticleRepository
 	newsRepo    *repository.NewsRepository
 	classifier  *Classifier
+	usage       *UsageRecorder
+	hooks       *ArticleHooks
+	experiments *ExperimentService
+	prompts     *PromptStore
 }
 
 // NewGenerator creates a new generator service
@@ -34,13 +39,38 @@ func NewGenerator(router *llm.Router, articleRepo *repository.ArticleRepository,
 	}
 }
 
+// SetUsageRecorder enables persisting token usage of generation calls
+func (g *Generator) SetUsageRecorder(usage *UsageRecorder) {
+	g.usage = usage
+}
+
+// SetArticleHooks enables enqueueing classification and embedding for generated articles
+func (g *Generator) SetArticleHooks(hooks *ArticleHooks) {
+	g.hooks = hooks
+}
+
+// SetExperiments enables prompt and model experiments for article generation
+func (g *Generator) SetExperiments(experiments *ExperimentService) {
+	g.experiments = experiments
+}
+
+// SetPromptStore sets the persona prompts used for generation
+func (g *Generator) SetPromptStore(prompts *PromptStore) {
+	g.prompts = prompts
+}
+
 // GenerationRequest represents a request to generate an article
 type GenerationRequest struct {
 	Topic       string
 	CategoryID  *uuid.UUID
-	Style       string   // "detailed", "concise", "beginner-friendly"
-	References  []string // URLs or content snippets for reference
-	ModelPrefer string   // Preferred model (optional)
+	Style       string          // "detailed", "concise", "beginner-friendly"
+	References  []string        // URLs or content snippets for reference
+	SourceURLs  []string        // Stored as the article's sources (optional)
+	ModelPrefer string          // Preferred model (optional)
+	Quality     string          // QualityStandard (default) or QualityHigh
+	Candidates  int             // Candidates generated for QualityHigh (default 3)
+	Status      string          // Status of the created article (default in_review)
+	Source      *model.NewsItem // News item the article is written from, credited with AttributeNews (optional)
 }
 
 // GenerationResult represents the result of article generation
@@ -49,31 +79,58 @@ type GenerationResult struct {
 	ModelUsed  string
 	TokensUsed int
 	Duration   time.Duration
+	Selection  *CandidateSelection // Set for QualityHigh
 }
 
 // GenerateArticle generates a knowledge article on a topic
 func (g *Generator) GenerateArticle(ctx context.Context, req *GenerationRequest) (*GenerationResult, error) {
 	startTime := time.Now()
 
+	// Sources that can't be reused are refused before anything is generated from them
+	if req.Source != nil {
+		if err := AttributeNews(&model.Article{}, req.Source); err != nil {
+			return nil, err
+		}
+	}
+
 	// Gather reference materials
 	references := g.gatherReferences(ctx, req.Topic, req.References)
 
-	// Build prompt
-	prompt := fmt.Sprintf(PromptKnowledgeArticle, req.Topic, references)
-
 	// Determine which LLM task to use based on complexity
 	task := llm.TaskContentGeneration
 
+	// Build prompt, using the experiment variant's prompt if this request was assigned one.
+	// Best-of-N runs stay out of experiments so variant metrics compare single generations.
+	var experiment *ExperimentAssignment
+	if req.Quality != QualityHigh {
+		experiment = g.experiments.Assign(task)
+	}
+	prompt := fmt.Sprintf(experiment.Prompt(g.prompts.Get(PromptNameKnowledgeArticle)), req.Topic, references)
+
 	opts := &llm.GenerateOptions{
 		Temperature: 0.7,
 		MaxTokens:   8000, // Allow for long articles
 	}
 
 	// Generate article content
-	content, modelUsed, err := g.llmRouter.Generate(task, prompt, opts)
+	var generated *llm.GenerateResult
+	var selection *CandidateSelection
+	var err error
+	if req.Quality == QualityHigh {
+		generated, selection, err = g.generateBestOf(req.Topic, prompt, req.Candidates, opts)
+	} else {
+		if modelName := experiment.Model(); modelName != "" {
+			generated, err = g.llmRouter.GenerateWithModel(task, modelName, prompt, opts)
+		} else {
+			generated, err = g.llmRouter.Generate(task, prompt, opts)
+		}
+		g.usage.Record(model.TaskTypeContentGenerate, map[string]interface{}{"topic": req.Topic}, startTime, generated, err)
+	}
 	if err != nil {
+		g.experiments.RecordRun(experiment, nil, generated, time.Since(startTime), nil, err)
 		return nil, fmt.Errorf("content generation failed: %w", err)
 	}
+	content, modelUsed := generated.Content, generated.Model
 
 	// Clean up content
 	content = g.cleanGeneratedContent(content)
@@ -91,11 +148,22 @@ func (g *Generator) GenerateArticle(ctx context.Context, req *GenerationRequest)
 		Content:          content,
 		Summary:          g.extractSummary(content),
 		CategoryID:       req.CategoryID,
-		Status:           "published",
+		Status:           req.Status,
 		SourceLanguage:   "zh",
 		ModelUsed:        modelUsed,
 		GenerationPrompt: prompt,
 		Tags:             g.extractTags(content, req.Topic),
+		SourceURLs:       req.SourceURLs,
+	}
+	// Generated articles are published only once an editor approves them
+	if article.Status == "" {
+		article.Status = model.ArticleStatusInReview
+	}
+	if req.Source != nil {
+		AttributeNews(article, req.Source) // Checked above
+	}
+	if req.Style == "beginner-friendly" {
+		article.Difficulty = model.DifficultyBeginner
 	}
 
 	// Save to database
@@ -103,8 +171,13 @@ func (g *Generator) GenerateArticle(ctx context.Context, req *GenerationRequest)
 		return nil, fmt.Errorf("failed to save article: %w", err)
 	}
 
-	// Trigger classification if no category specified
-	if req.CategoryID == nil && g.classifier != nil {
+	score := g.qualityScore(content)
+	g.experiments.RecordRun(experiment, &article.ID, generated, time.Since(startTime), &score, nil)
+
+	// Hand off to the post-create pipeline, or classify in-process when no task queue is configured
+	if g.hooks != nil {
+		g.hooks.AfterCreate(article)
+	} else if req.CategoryID == nil && g.classifier != nil {
 		go func() {
 			ctx := context.Background()
 			if err := g.classifier.ClassifyAndUpdate(ctx, article.ID); err != nil {
@@ -113,10 +186,26 @@ func (g *Generator) GenerateArticle(ctx context.Context, req *GenerationRequest)
 		}()
 	}
 
+	// Classification assesses difficulty; articles filed under a given category skip it
+	if req.CategoryID != nil && article.Difficulty == "" && g.classifier != nil {
+		go func() {
+			if err := g.classifier.AssessAndUpdateDifficulty(context.Background(), article.ID); err != nil {
+				log.Printf("Difficulty assessment failed: %v", err)
+			}
+		}()
+	}
+
+	tokensUsed := generated.Usage.TotalTokens()
+	if selection != nil {
+		tokensUsed = selection.TokensUsed
+	}
+
 	return &GenerationResult{
-		Article:   article,
-		ModelUsed: modelUsed,
-		Duration:  time.Since(startTime),
+		Article:    article,
+		ModelUsed:  modelUsed,
+		TokensUsed: tokensUsed,
+		Duration:   time.Since(startTime),
+		Selection:  selection,
 	}, nil
 }
 
@@ -185,6 +274,18 @@ func (g *Generator) validateQuality(content string) error {
 	return nil
 }
 
+// qualityScore rates generated content from 0 to 1 by length, section structure and
+// terminology formatting, the same signals validateQuality checks
+func (g *Generator) qualityScore(content string) float64 {
+	lengthScore := math.Min(float64(utf8.RuneCountInString(content))/3000, 1)
+	sectionScore := math.Min(float64(strings.Count(content, "\n## "))/6, 1)
+	termScore := 0.0
+	if regexp.MustCompile(`[A-Za-z]+\s*[（(][^）)]+[）)]`).MatchString(content) {
+		termScore = 1
+	}
+	return math.Round((0.4*lengthScore+0.4*sectionScore+0.2*termScore)*100) / 100
+}
+
 // extractTitle extracts or generates a title from content
 func (g *Generator) extractTitle(content string, topic string) string {
 	// Try to extract from first line if it's a heading
@@ -294,10 +395,11 @@ func (g *Generator) extractTags(content string, topic string) []string {
 // GenerateStream generates an article with streaming output
 func (g *Generator) GenerateStream(ctx context.Context, req *GenerationRequest) (<-chan llm.StreamChunk, string, error) {
 	references := g.gatherReferences(ctx, req.Topic, req.References)
-	prompt := fmt.Sprintf(PromptKnowledgeArticle, req.Topic, references)
+	prompt := fmt.Sprintf(g.prompts.Get(PromptNameKnowledgeArticle), req.Topic, references)
 
 	return g.llmRouter.GenerateStream(llm.TaskContentGeneration, prompt, &llm.GenerateOptions{
 		Temperature: 0.7,
 		MaxTokens:   8000,
+		Context:     ctx,
 	})
 }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+// glossaryContentExcerpt is the number of runes of an article shown to the LLM for term extraction
+const glossaryContentExcerpt = 6000
+
+// glossarySchema is the JSON schema extracted glossary terms must match
+var glossarySchema = llm.MustJSONSchema("glossary_terms", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"terms": map[string]interface{}{
+			"type":     "array",
+			"maxItems": 10,
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"nameZh":     map[string]interface{}{"type": "string"},
+					"nameEn":     map[string]interface{}{"type": "string"},
+					"aliases":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					"definition": map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"nameZh", "nameEn", "definition"},
+			},
+		},
+	},
+	"required": []interface{}{"terms"},
+})
+
+// GlossaryService harvests glossary terms from articles with the LLM
+type GlossaryService struct {
+	llmRouter   *llm.Router
+	articleRepo *repository.ArticleRepository
+	termRepo    *repository.TermRepository
+	usage       *UsageRecorder
+}
+
+// NewGlossaryService creates a new glossary service
+func NewGlossaryService(router *llm.Router, articleRepo *repository.ArticleRepository, termRepo *repository.TermRepository) *GlossaryService {
+	return &GlossaryService{
+		llmRouter:   router,
+		articleRepo: articleRepo,
+		termRepo:    termRepo,
+	}
+}
+
+// SetUsageRecorder enables persisting token usage of term extraction
+func (s *GlossaryService) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// ExtractAndSave asks the LLM for the jargon in an article, adds terms not in the glossary
+// yet and links every term found to the article. Existing definitions are kept. Returns
+// the terms linked to the article.
+func (s *GlossaryService) ExtractAndSave(ctx context.Context, articleID uuid.UUID) ([]model.Term, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("article not found: %w", err)
+	}
+
+	prompt := fmt.Sprintf(PromptGlossaryTerms, article.Title, truncateString(article.Content, glossaryContentExcerpt))
+
+	startedAt := time.Now()
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskSummarization, prompt, glossarySchema, &llm.GenerateOptions{
+		Temperature: 0.2,
+		MaxTokens:   2000,
+		Context:     ctx,
+	})
+	s.usage.Record(model.TaskTypeGlossary, map[string]interface{}{"articleId": article.ID}, startedAt, generated, err)
+	if err != nil {
+		return nil, fmt.Errorf("LLM term extraction failed: %w", err)
+	}
+
+	var result struct {
+		Terms []struct {
+			NameZh     string   `json:"nameZh"`
+			NameEn     string   `json:"nameEn"`
+			Aliases    []string `json:"aliases"`
+			Definition string   `json:"definition"`
+		} `json:"terms"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse terms: %w", err)
+	}
+
+	for _, extracted := range result.Terms {
+		term := &model.Term{
+			NameZh:     strings.TrimSpace(extracted.NameZh),
+			NameEn:     strings.TrimSpace(extracted.NameEn),
+			Definition: strings.TrimSpace(extracted.Definition),
+			Source:     model.TermSourceLLM,
+		}
+		for _, alias := range extracted.Aliases {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				term.Aliases = append(term.Aliases, alias)
+			}
+		}
+		if (term.NameZh == "" && term.NameEn == "") || term.Definition == "" {
+			continue
+		}
+
+		existing, err := s.findExisting(term)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			if err := s.termRepo.Create(term); err != nil {
+				// A partial match with another term; the glossary keeps the term it has
+				if errors.Is(err, repository.ErrTermExists) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to save term: %w", err)
+			}
+			existing = term
+		}
+		if err := s.termRepo.LinkArticles(existing.ID, article.ID); err != nil {
+			return nil, fmt.Errorf("failed to link term: %w", err)
+		}
+	}
+
+	return s.termRepo.ListForArticle(article.ID)
+}
+
+// findExisting returns the glossary term matching any name of an extracted term, or nil
+func (s *GlossaryService) findExisting(term *model.Term) (*model.Term, error) {
+	for _, name := range append([]string{term.NameEn, term.NameZh}, term.Aliases...) {
+		if name == "" {
+			continue
+		}
+		existing, err := s.termRepo.FindByName(name)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
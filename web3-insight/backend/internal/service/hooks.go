@@ -0,0 +1,51 @@
+package service
+
+import (
+	"log"
+
+	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// ArticleTaskEnqueuer enqueues background processing tasks for articles
+type ArticleTaskEnqueuer interface {
+	EnqueueClassify(articleID string) (*asynq.TaskInfo, error)
+	EnqueueEmbedding(articleID string) (*asynq.TaskInfo, error)
+}
+
+// ArticleHooks runs the post-create pipeline shared by every article creation path
+type ArticleHooks struct {
+	enqueuer ArticleTaskEnqueuer
+}
+
+// NewArticleHooks creates article hooks that enqueue tasks through the given enqueuer
+func NewArticleHooks(enqueuer ArticleTaskEnqueuer) *ArticleHooks {
+	return &ArticleHooks{enqueuer: enqueuer}
+}
+
+// AfterCreate enqueues classification for uncategorized articles and embedding for all.
+// Scheduled articles are left for AfterPublish. Enqueue failures are logged so article
+// creation itself never fails; a nil receiver is a no-op.
+func (h *ArticleHooks) AfterCreate(article *model.Article) {
+	if article != nil && article.Status == model.ArticleStatusScheduled {
+		return
+	}
+	h.AfterPublish(article)
+}
+
+// AfterPublish runs the post-create pipeline for a scheduled article once it is published
+func (h *ArticleHooks) AfterPublish(article *model.Article) {
+	if h == nil || h.enqueuer == nil || article == nil {
+		return
+	}
+
+	articleID := article.ID.String()
+	if article.CategoryID == nil {
+		if _, err := h.enqueuer.EnqueueClassify(articleID); err != nil {
+			log.Printf("Failed to enqueue classification for article %s: %v", articleID, err)
+		}
+	}
+	if _, err := h.enqueuer.EnqueueEmbedding(articleID); err != nil {
+		log.Printf("Failed to enqueue embedding for article %s: %v", articleID, err)
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/datatypes"
+)
+
+// ErrImportCancelled is returned when the import task record was cancelled mid-run
+var ErrImportCancelled = errors.New("import cancelled")
+
+// importProgressInterval is how many articles are imported between cancellation checks
+const importProgressInterval = 10
+
+// importJobPayload is stored as the payload of an import task record. The batch itself is
+// stored apart, as the task's ImportBatchData.
+type importJobPayload struct {
+	Articles    int           `json:"articles"`
+	ParseErrors []ImportError `json:"parseErrors,omitempty"` // Markdown files that could not be parsed
+}
+
+// ImportJobRunner imports batches in the background, keeping the ImportResult on the task
+// record up to date after every article
+type ImportJobRunner struct {
+	importer *ArticleImporter
+	taskRepo *repository.TaskRepository
+}
+
+// NewImportJobRunner creates a runner for import task records
+func NewImportJobRunner(importer *ArticleImporter, taskRepo *repository.TaskRepository) *ImportJobRunner {
+	return &ImportJobRunner{importer: importer, taskRepo: taskRepo}
+}
+
+// CreateImportTask records a pending import of a batch. parseErrors are reported as failed
+// articles of the import.
+func CreateImportTask(taskRepo *repository.TaskRepository, batch ImportBatch, parseErrors []ImportError) (*model.Task, error) {
+	payload, err := json.Marshal(importJobPayload{Articles: len(batch.Articles), ParseErrors: parseErrors})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+	result, err := json.Marshal(newJobResult(len(batch.Articles), parseErrors))
+	if err != nil {
+		return nil, err
+	}
+	task := &model.Task{
+		Type:    model.TaskTypeImport,
+		Status:  model.TaskStatusPending,
+		Payload: datatypes.JSON(payload),
+		Result:  datatypes.JSON(result),
+	}
+	if err := taskRepo.CreateImport(task, datatypes.JSON(data)); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// newJobResult returns the result of an import job before any article is imported
+func newJobResult(articles int, parseErrors []ImportError) *ImportResult {
+	return &ImportResult{
+		TotalCount:     articles + len(parseErrors),
+		ErrorCount:     len(parseErrors),
+		ProcessedCount: len(parseErrors),
+		Errors:         append([]ImportError{}, parseErrors...),
+		ImportedIDs:    []uuid.UUID{},
+	}
+}
+
+// Run imports the batch of an import task record. A run that stopped early, e.g. on a worker
+// restart, resumes after the last article recorded in the result.
+func (r *ImportJobRunner) Run(ctx context.Context, taskID uuid.UUID) (*ImportResult, error) {
+	task, err := r.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task: %w", err)
+	}
+	if task.Status == model.TaskStatusCancelled || task.Status == model.TaskStatusCompleted {
+		r.dropBatch(task.ID)
+		return nil, nil
+	}
+
+	var payload importJobPayload
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("invalid import payload: %w", err)
+	}
+	data, err := r.taskRepo.GetImportBatch(task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load import batch: %w", err)
+	}
+	var batch ImportBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("invalid import batch: %w", err)
+	}
+	result := newJobResult(len(batch.Articles), payload.ParseErrors)
+	if len(task.Result) > 0 {
+		if err := json.Unmarshal(task.Result, result); err != nil {
+			return nil, fmt.Errorf("invalid import progress: %w", err)
+		}
+	}
+
+	now := time.Now()
+	task.Status = model.TaskStatusRunning
+	if task.StartedAt == nil {
+		task.StartedAt = &now
+	}
+	task.Error = ""
+	if saved, err := r.save(task, result); err != nil {
+		return nil, err
+	} else if !saved {
+		r.dropBatch(task.ID)
+		return nil, nil
+	}
+
+	from := result.ProcessedCount - len(payload.ParseErrors)
+	err = r.importer.importArticles(batch, from, result, func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if result.ProcessedCount%importProgressInterval == 0 {
+			if err := r.checkCancelled(task.ID); err != nil {
+				return err
+			}
+		}
+		return r.saveProgress(task.ID, result)
+	})
+
+	completed := time.Now()
+	task.CompletedAt = &completed
+	switch {
+	case errors.Is(err, ErrImportCancelled):
+		task.Status = model.TaskStatusCancelled
+	case err != nil:
+		task.Status = model.TaskStatusFailed
+		task.Error = err.Error()
+	default:
+		task.Status = model.TaskStatusCompleted
+	}
+	// A cancel arriving after the last check still wins
+	saved, saveErr := r.save(task, result)
+	if saveErr != nil {
+		log.Printf("Failed to save import task %s: %v", task.ID, saveErr)
+	}
+	if task.Status != model.TaskStatusFailed || (saveErr == nil && !saved) {
+		r.dropBatch(task.ID)
+	}
+	return result, err
+}
+
+// dropBatch deletes the batch of an import that will not run again; a failed import keeps it
+// so a retry can resume
+func (r *ImportJobRunner) dropBatch(taskID uuid.UUID) {
+	if err := r.taskRepo.DeleteImportBatch(taskID); err != nil {
+		log.Printf("Failed to delete batch of import task %s: %v", taskID, err)
+	}
+}
+
+// checkCancelled stops the run when the task was cancelled through the task API
+func (r *ImportJobRunner) checkCancelled(taskID uuid.UUID) error {
+	task, err := r.taskRepo.GetByID(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to load task: %w", err)
+	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrImportCancelled
+	}
+	return nil
+}
+
+// saveProgress stores the result so far without overwriting a cancellation
+func (r *ImportJobRunner) saveProgress(taskID uuid.UUID, result *ImportResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := r.taskRepo.UpdateResult(taskID, datatypes.JSON(data)); err != nil {
+		return fmt.Errorf("failed to save progress: %w", err)
+	}
+	return nil
+}
+
+// save stores the task record with its result unless the task was cancelled meanwhile,
+// reporting whether it was stored
+func (r *ImportJobRunner) save(task *model.Task, result *ImportResult) (bool, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return false, err
+	}
+	task.Result = datatypes.JSON(data)
+	saved, err := r.taskRepo.UpdateUnlessCancelled(task)
+	if err != nil {
+		return false, fmt.Errorf("failed to save task: %w", err)
+	}
+	return saved, nil
+}
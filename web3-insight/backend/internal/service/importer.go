@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
+	"github.com/user/web3-insight/internal/markdown"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
 )
@@ -15,6 +18,7 @@ import (
 type ArticleImporter struct {
 	articleRepo  *repository.ArticleRepository
 	categoryRepo *repository.CategoryRepository
+	hooks        *ArticleHooks
 }
 
 // ImportArticle represents the JSON structure for importing an article
@@ -29,6 +33,10 @@ type ImportArticle struct {
 	Status       string   `json:"status,omitempty"` // draft, published
 	SourceURLs   []string `json:"sourceUrls,omitempty"`
 	Slug         string   `json:"slug,omitempty"` // Custom slug, auto-generated if empty
+	License      string   `json:"license,omitempty"`     // Reuse license of the source content
+	LicenseURL   string   `json:"licenseUrl,omitempty"`
+	Attribution  string   `json:"attribution,omitempty"` // Credit line shown with reused content
+	File         string   `json:"file,omitempty"`        // Source file of a markdown import
 }
 
 // ImportBatch represents a batch of articles to import
@@ -43,8 +51,15 @@ type ImportOptions struct {
 	UpdateExisting  bool `json:"updateExisting"`  // Update if slug exists
 	GenerateSummary bool `json:"generateSummary"` // Generate summary if empty
 	DefaultStatus   string `json:"defaultStatus"` // Default status for articles
+	ContentDuplicates string `json:"contentDuplicates,omitempty"` // What to do with articles whose content already exists under another slug: skip or merge
 }
 
+// How imports handle articles whose content hash matches an existing article
+const (
+	ContentDuplicatesSkip  = "skip"
+	ContentDuplicatesMerge = "merge" // Add the import's tags and sources to the existing article
+)
+
 // ImportResult represents the result of an import operation
 type ImportResult struct {
 	TotalCount   int            `json:"totalCount"`
@@ -52,14 +67,27 @@ type ImportResult struct {
 	SkippedCount int            `json:"skippedCount"`
 	UpdatedCount int            `json:"updatedCount"`
 	ErrorCount   int            `json:"errorCount"`
+	ProcessedCount int          `json:"processedCount"` // Articles handled so far, for import jobs in progress
 	Errors       []ImportError  `json:"errors,omitempty"`
 	ImportedIDs  []uuid.UUID    `json:"importedIds,omitempty"`
+	ContentMatches []ContentMatch `json:"contentMatches,omitempty"` // Articles skipped or merged as content duplicates
+}
+
+// ContentMatch is an imported article whose content matched an existing article
+type ContentMatch struct {
+	Index     int       `json:"index"`
+	Title     string    `json:"title"`
+	File      string    `json:"file,omitempty"`
+	ArticleID uuid.UUID `json:"articleId"`
+	Slug      string    `json:"slug"`   // Slug of the existing article
+	Action    string    `json:"action"` // skip or merge
 }
 
 // ImportError describes an error during import
 type ImportError struct {
-	Index   int    `json:"index"`
+	Index   int    `json:"index"` // Position in the batch; -1 for a markdown file that could not be parsed
 	Title   string `json:"title"`
+	File    string `json:"file,omitempty"` // Path of the source file in a markdown archive
 	Message string `json:"message"`
 }
 
@@ -71,6 +99,11 @@ func NewArticleImporter(articleRepo *repository.ArticleRepository, categoryRepo
 	}
 }
 
+// SetArticleHooks enables enqueueing classification and embedding for imported articles
+func (i *ArticleImporter) SetArticleHooks(hooks *ArticleHooks) {
+	i.hooks = hooks
+}
+
 // Import imports a batch of articles
 func (i *ArticleImporter) Import(batch ImportBatch) (*ImportResult, error) {
 	result := &ImportResult{
@@ -79,22 +112,38 @@ func (i *ArticleImporter) Import(batch ImportBatch) (*ImportResult, error) {
 		ImportedIDs: []uuid.UUID{},
 	}
 
-	for idx, importArticle := range batch.Articles {
-		if err := i.importSingle(importArticle, batch.Options, result); err != nil {
+	i.importArticles(batch, 0, result, nil)
+
+	return result, nil
+}
+
+// importArticles imports the batch articles from index from on, counting each in result and
+// calling after (if set) once it is handled. Stops with the error after returns.
+func (i *ArticleImporter) importArticles(batch ImportBatch, from int, result *ImportResult, after func() error) error {
+	i.fillContentHashes(batch.Options)
+	for idx := from; idx < len(batch.Articles); idx++ {
+		importArticle := batch.Articles[idx]
+		if err := i.importSingle(idx, importArticle, batch.Options, result); err != nil {
 			result.Errors = append(result.Errors, ImportError{
 				Index:   idx,
 				Title:   importArticle.Title,
+				File:    importArticle.File,
 				Message: err.Error(),
 			})
 			result.ErrorCount++
 		}
+		result.ProcessedCount++
+		if after != nil {
+			if err := after(); err != nil {
+				return err
+			}
+		}
 	}
-
-	return result, nil
+	return nil
 }
 
-// importSingle imports a single article
-func (i *ArticleImporter) importSingle(importArticle ImportArticle, opts ImportOptions, result *ImportResult) error {
+// importSingle imports a single article, the idx-th of its batch
+func (i *ArticleImporter) importSingle(idx int, importArticle ImportArticle, opts ImportOptions, result *ImportResult) error {
 	// Validate required fields
 	if importArticle.Title == "" {
 		return fmt.Errorf("title is required")
@@ -103,47 +152,39 @@ func (i *ArticleImporter) importSingle(importArticle ImportArticle, opts ImportO
 		return fmt.Errorf("content is required")
 	}
 
-	// Generate or use provided slug
-	articleSlug := importArticle.Slug
-	if articleSlug == "" {
-		articleSlug = slug.Make(importArticle.Title)
-	}
-
-	// Check for existing article by slug
-	existing, err := i.articleRepo.GetBySlug(articleSlug)
-	if err == nil && existing != nil {
-		if opts.SkipDuplicates {
+	if duplicate := i.matchContent(importArticle, opts); duplicate != nil {
+		if opts.ContentDuplicates == ContentDuplicatesMerge {
+			mergeImportDuplicate(duplicate, importArticle)
+			if err := i.articleRepo.Update(duplicate); err != nil {
+				return fmt.Errorf("failed to merge into article: %w", err)
+			}
+			result.UpdatedCount++
+		} else {
 			result.SkippedCount++
-			return nil
 		}
-		if opts.UpdateExisting {
-			// Update existing article
-			existing.Title = importArticle.Title
-			existing.Content = importArticle.Content
-			if importArticle.ContentHTML != "" {
-				existing.ContentHTML = importArticle.ContentHTML
-			}
-			if importArticle.Summary != "" {
-				existing.Summary = importArticle.Summary
-			}
-			if len(importArticle.Tags) > 0 {
-				existing.Tags = importArticle.Tags
-			}
-			if len(importArticle.SourceURLs) > 0 {
-				existing.SourceURLs = importArticle.SourceURLs
-			}
-			if importArticle.Status != "" {
-				existing.Status = importArticle.Status
-			}
+		result.ContentMatches = append(result.ContentMatches, ContentMatch{
+			Index:     idx,
+			Title:     importArticle.Title,
+			File:      importArticle.File,
+			ArticleID: duplicate.ID,
+			Slug:      duplicate.Slug,
+			Action:    opts.ContentDuplicates,
+		})
+		return nil
+	}
 
-			if err := i.articleRepo.Update(existing); err != nil {
-				return fmt.Errorf("failed to update article: %w", err)
-			}
-			result.UpdatedCount++
-			return nil
+	action, articleSlug, existing := i.matchExisting(importArticle, opts)
+	switch action {
+	case ImportActionSkip:
+		result.SkippedCount++
+		return nil
+	case ImportActionUpdate:
+		applyImportUpdate(existing, importArticle)
+		if err := i.articleRepo.Update(existing); err != nil {
+			return fmt.Errorf("failed to update article: %w", err)
 		}
-		// Make slug unique by appending a number
-		articleSlug = i.makeUniqueSlug(articleSlug)
+		result.UpdatedCount++
+		return nil
 	}
 
 	// Resolve category
@@ -183,17 +224,126 @@ func (i *ArticleImporter) importSingle(importArticle ImportArticle, opts ImportO
 		Tags:        importArticle.Tags,
 		Status:      status,
 		SourceURLs:  importArticle.SourceURLs,
+		License:     importArticle.License,
+		LicenseURL:  importArticle.LicenseURL,
+		Attribution: importArticle.Attribution,
+		EditedBy:    model.EditedByImport,
 	}
 
 	if err := i.articleRepo.Create(article); err != nil {
 		return fmt.Errorf("failed to create article: %w", err)
 	}
+	i.hooks.AfterCreate(article)
 
 	result.ImportedCount++
 	result.ImportedIDs = append(result.ImportedIDs, article.ID)
 	return nil
 }
 
+// matchExisting decides what importing an article does: with an article already at its
+// slug, it is skipped or updated per the options, or created under a numbered slug.
+// Returns the action, the slug the article gets and the existing article if any.
+func (i *ArticleImporter) matchExisting(importArticle ImportArticle, opts ImportOptions) (string, string, *model.Article) {
+	articleSlug := importSlug(importArticle)
+	existing, err := i.articleRepo.GetBySlug(articleSlug)
+	if err != nil || existing == nil {
+		return ImportActionCreate, articleSlug, nil
+	}
+	if opts.SkipDuplicates {
+		return ImportActionSkip, articleSlug, existing
+	}
+	if opts.UpdateExisting {
+		return ImportActionUpdate, articleSlug, existing
+	}
+	return ImportActionCreate, i.makeUniqueSlug(articleSlug), existing
+}
+
+// matchContent returns the existing article with the same content as an imported one under
+// another slug, if content duplicates are handled. Articles at the same slug are left to the
+// slug options.
+func (i *ArticleImporter) matchContent(importArticle ImportArticle, opts ImportOptions) *model.Article {
+	if opts.ContentDuplicates != ContentDuplicatesSkip && opts.ContentDuplicates != ContentDuplicatesMerge {
+		return nil
+	}
+	existing, err := i.articleRepo.FindByContentHash(markdown.ContentHash(importArticle.Content))
+	if err != nil || existing.Slug == importSlug(importArticle) {
+		return nil
+	}
+	return existing
+}
+
+// fillContentHashes hashes articles saved before content hashes were stored, so content
+// duplicates among them are found
+func (i *ArticleImporter) fillContentHashes(opts ImportOptions) {
+	if opts.ContentDuplicates == "" {
+		return
+	}
+	if filled, err := i.articleRepo.FillContentHashes(); err != nil {
+		log.Printf("Failed to fill article content hashes: %v", err)
+	} else if filled > 0 {
+		log.Printf("Filled content hashes of %d articles", filled)
+	}
+}
+
+// mergeImportDuplicate adds the tags and sources of an imported article to an existing
+// article with the same content, and fills in a missing summary
+func mergeImportDuplicate(existing *model.Article, importArticle ImportArticle) {
+	for _, tag := range importArticle.Tags {
+		if !slices.Contains(existing.Tags, tag) {
+			existing.Tags = append(existing.Tags, tag)
+		}
+	}
+	for _, url := range importArticle.SourceURLs {
+		if !slices.Contains(existing.SourceURLs, url) {
+			existing.SourceURLs = append(existing.SourceURLs, url)
+		}
+	}
+	if existing.Summary == "" {
+		existing.Summary = importArticle.Summary
+	}
+}
+
+// importSlug returns the custom slug of an article, or the one generated from its title
+func importSlug(importArticle ImportArticle) string {
+	if importArticle.Slug != "" {
+		return importArticle.Slug
+	}
+	return slug.Make(importArticle.Title)
+}
+
+// applyImportUpdate overwrites an existing article with the fields an import sets. The
+// status only changes as the review workflow allows, so a re-import cannot publish an
+// article that is still under review; such an article keeps its status.
+func applyImportUpdate(existing *model.Article, importArticle ImportArticle) {
+	existing.Title = importArticle.Title
+	if existing.Content != importArticle.Content {
+		existing.Content = importArticle.Content
+		existing.EditedBy = model.EditedByImport
+	}
+	if importArticle.ContentHTML != "" {
+		existing.ContentHTML = importArticle.ContentHTML
+	}
+	if importArticle.Summary != "" {
+		existing.Summary = importArticle.Summary
+	}
+	if len(importArticle.Tags) > 0 {
+		existing.Tags = importArticle.Tags
+	}
+	if len(importArticle.SourceURLs) > 0 {
+		existing.SourceURLs = importArticle.SourceURLs
+	}
+	if importArticle.Status != "" && importArticle.Status != existing.Status && model.CanTransitionArticle(existing.Status, importArticle.Status) {
+		existing.Status = importArticle.Status
+	}
+	if importArticle.License != "" {
+		existing.License = importArticle.License
+		existing.LicenseURL = importArticle.LicenseURL
+	}
+	if importArticle.Attribution != "" {
+		existing.Attribution = importArticle.Attribution
+	}
+}
+
 // makeUniqueSlug creates a unique slug by appending a number
 func (i *ArticleImporter) makeUniqueSlug(baseSlug string) string {
 	count := i.articleRepo.CountBySlugPrefix(baseSlug)
@@ -291,6 +441,9 @@ func (i *ArticleImporter) ExportArticles(articleIDs []uuid.UUID) (*ImportBatch,
 			Status:      article.Status,
 			SourceURLs:  article.SourceURLs,
 			Slug:        article.Slug,
+			License:     article.License,
+			LicenseURL:  article.LicenseURL,
+			Attribution: article.Attribution,
 		}
 
 		// Get category path if available
@@ -358,13 +511,24 @@ func (i *ArticleImporter) ImportFromMarkdown(title, markdown string, categoryPat
 	return i.articleRepo.GetByID(result.ImportedIDs[0])
 }
 
+// ExportFilter selects the articles of a JSON export. The since filters let mirrors pull only
+// the articles changed after their last export.
+type ExportFilter struct {
+	CategoryID   *uuid.UUID
+	Status       string
+	UpdatedSince *time.Time
+	CreatedSince *time.Time
+}
+
 // BatchExportToJSON exports multiple articles to JSON
-func (i *ArticleImporter) BatchExportToJSON(categoryID *uuid.UUID, status string) ([]byte, error) {
+func (i *ArticleImporter) BatchExportToJSON(filter ExportFilter) ([]byte, error) {
 	// Fetch articles based on filters
 	params := repository.ArticleListParams{
-		CategoryID: categoryID,
-		Status:     status,
-		PageSize:   1000, // Export limit
+		CategoryID:   filter.CategoryID,
+		Status:       filter.Status,
+		UpdatedSince: filter.UpdatedSince,
+		CreatedSince: filter.CreatedSince,
+		PageSize:     1000, // Export limit
 	}
 
 	result, err := i.articleRepo.List(params)
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/markdown"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// What importing an article does
+const (
+	ImportActionCreate  = "create"
+	ImportActionUpdate  = "update"
+	ImportActionSkip    = "skip"
+	ImportActionInvalid = "invalid" // Fails validation and would be reported as an error
+)
+
+// ImportPlanEntry is what importing one article of a batch would do
+type ImportPlanEntry struct {
+	Index           int           `json:"index"`
+	Title           string        `json:"title"`
+	File            string        `json:"file,omitempty"`
+	Action          string        `json:"action"` // create, update, skip or invalid
+	Slug            string        `json:"slug,omitempty"`
+	ExistingID      *uuid.UUID    `json:"existingId,omitempty"`      // Article already at the slug
+	SlugChanged     bool          `json:"slugChanged,omitempty"`     // Created under a numbered slug since the slug is taken
+	DuplicateOf     *int          `json:"duplicateOf,omitempty"`     // Earlier article of the batch with the same slug or content
+	ContentMatch    bool          `json:"contentMatch,omitempty"`    // Matched by content hash rather than slug
+	CreatesCategory bool          `json:"createsCategory,omitempty"` // The category path does not exist yet
+	Changes         []FieldChange `json:"changes,omitempty"`         // Fields an update changes
+}
+
+// FieldChange is a field an import would change on an existing article. Content fields report
+// changed line counts instead of values.
+type FieldChange struct {
+	Field        string      `json:"field"`
+	Old          interface{} `json:"old,omitempty"`
+	New          interface{} `json:"new,omitempty"`
+	LinesAdded   int         `json:"linesAdded,omitempty"`
+	LinesRemoved int         `json:"linesRemoved,omitempty"`
+}
+
+// ImportDryRun reports what importing a batch would do without writing anything
+type ImportDryRun struct {
+	Plan        []ImportPlanEntry `json:"plan"`
+	CreateCount int               `json:"createCount"`
+	UpdateCount int               `json:"updateCount"`
+	SkipCount   int               `json:"skipCount"`
+	// Updates that change nothing are counted as updates with no changes
+	UnchangedCount int `json:"unchangedCount"`
+	InvalidCount   int `json:"invalidCount"`
+}
+
+// DryRun works out per article whether an import would create, update or skip it, with the
+// fields updates change. Articles matched by slug or content are compared the way Import
+// applies them. Only missing content hashes of existing articles are stored.
+func (i *ArticleImporter) DryRun(batch ImportBatch) *ImportDryRun {
+	report := &ImportDryRun{Plan: make([]ImportPlanEntry, 0, len(batch.Articles))}
+	invalid := make(map[int]bool)
+	for _, e := range i.ValidateImport(batch) {
+		invalid[e.Index] = true
+	}
+	bySlug := make(map[string]int) // Slug -> first batch index creating or updating it
+	byHash := make(map[string]int) // Content hash -> first batch index creating it
+	i.fillContentHashes(batch.Options)
+	categories := make(map[string]bool)
+
+	for idx, importArticle := range batch.Articles {
+		entry := ImportPlanEntry{Index: idx, Title: importArticle.Title, File: importArticle.File}
+		if invalid[idx] {
+			entry.Action = ImportActionInvalid
+			report.InvalidCount++
+			report.Plan = append(report.Plan, entry)
+			continue
+		}
+
+		requested := importSlug(importArticle)
+		hash := markdown.ContentHash(importArticle.Content)
+		if duplicate := i.matchContent(importArticle, batch.Options); duplicate != nil {
+			entry.ContentMatch = true
+			entry.ExistingID = &duplicate.ID
+			entry.Slug = duplicate.Slug
+			entry.Action = ImportActionSkip
+			if batch.Options.ContentDuplicates == ContentDuplicatesMerge {
+				entry.Action = ImportActionUpdate
+				entry.Changes = importChanges(duplicate, func(a *model.Article) { mergeImportDuplicate(a, importArticle) })
+			}
+		} else if first, ok := byHash[hash]; ok && batch.Options.ContentDuplicates != "" && importSlug(batch.Articles[first]) != requested {
+			// The earlier article is created first and then matches by content
+			entry.ContentMatch = true
+			entry.DuplicateOf = &first
+			entry.Action = ImportActionSkip
+			if batch.Options.ContentDuplicates == ContentDuplicatesMerge {
+				entry.Action = ImportActionUpdate
+			}
+		} else if first, ok := bySlug[requested]; ok {
+			// Importing the earlier article puts one at this slug first
+			entry.DuplicateOf = &first
+			entry.Slug = requested
+			switch {
+			case batch.Options.SkipDuplicates:
+				entry.Action = ImportActionSkip
+			case batch.Options.UpdateExisting:
+				entry.Action = ImportActionUpdate
+			default:
+				entry.Action = ImportActionCreate
+				entry.Slug = i.makeUniqueSlug(requested)
+				entry.SlugChanged = true
+			}
+		} else {
+			action, articleSlug, existing := i.matchExisting(importArticle, batch.Options)
+			entry.Action = action
+			entry.Slug = articleSlug
+			entry.SlugChanged = articleSlug != requested
+			if existing != nil {
+				entry.ExistingID = &existing.ID
+			}
+			if action == ImportActionUpdate {
+				entry.Changes = importChanges(existing, func(a *model.Article) { applyImportUpdate(a, importArticle) })
+			}
+			bySlug[requested] = idx
+			if action == ImportActionCreate {
+				byHash[hash] = idx
+			}
+		}
+
+		if entry.Action == ImportActionCreate && importArticle.CategoryID == "" && importArticle.CategoryPath != "" {
+			exists, seen := categories[importArticle.CategoryPath]
+			if !seen {
+				_, err := i.categoryRepo.FindByPath(importArticle.CategoryPath)
+				exists = err == nil
+				categories[importArticle.CategoryPath] = exists
+			}
+			entry.CreatesCategory = !exists
+		}
+
+		switch entry.Action {
+		case ImportActionCreate:
+			report.CreateCount++
+		case ImportActionSkip:
+			report.SkipCount++
+		case ImportActionUpdate:
+			report.UpdateCount++
+			if entry.DuplicateOf == nil && len(entry.Changes) == 0 {
+				report.UnchangedCount++
+			}
+		}
+		report.Plan = append(report.Plan, entry)
+	}
+	return report
+}
+
+// importChanges lists the fields apply would change on an existing article
+func importChanges(existing *model.Article, apply func(*model.Article)) []FieldChange {
+	updated := *existing
+	updated.Tags = slices.Clone(existing.Tags)
+	updated.SourceURLs = slices.Clone(existing.SourceURLs)
+	apply(&updated)
+
+	var changes []FieldChange
+	compare := func(field string, old, new string) {
+		if old != new {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	compareText := func(field string, old, new string) {
+		if old == new {
+			return
+		}
+		added, removed := lineChanges(old, new)
+		changes = append(changes, FieldChange{Field: field, LinesAdded: added, LinesRemoved: removed})
+	}
+	compareList := func(field string, old, new []string) {
+		if !slices.Equal(old, new) {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	compare("title", existing.Title, updated.Title)
+	compareText("content", existing.Content, updated.Content)
+	compareText("contentHtml", existing.ContentHTML, updated.ContentHTML)
+	compare("summary", existing.Summary, updated.Summary)
+	compareList("tags", existing.Tags, updated.Tags)
+	compareList("sourceUrls", existing.SourceURLs, updated.SourceURLs)
+	compare("status", existing.Status, updated.Status)
+	compare("license", existing.License, updated.License)
+	compare("licenseUrl", existing.LicenseURL, updated.LicenseURL)
+	compare("attribution", existing.Attribution, updated.Attribution)
+	return changes
+}
+
+// lineChanges counts the lines of new missing from old and the lines of old missing from new
+func lineChanges(old, new string) (added, removed int) {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(old, "\n") {
+		counts[line]++
+	}
+	for _, line := range strings.Split(new, "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+	for _, n := range counts {
+		removed += n
+	}
+	return added, removed
+}
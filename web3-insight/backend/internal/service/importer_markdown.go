@@ -0,0 +1,225 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Limits for markdown archive import
+const (
+	maxMarkdownFileSize  = 10 << 20 // Per uncompressed .md file
+	maxEmbeddedImageSize = 2 << 20  // Larger images are left as links
+)
+
+// markdownImageLink matches the destination of an inline markdown image
+var markdownImageLink = regexp.MustCompile(`(!\[[^\]]*\]\(\s*)(<[^>]+>|[^)\s]+)`)
+
+// MarkdownImportOptions configures how markdown files map to articles
+type MarkdownImportOptions struct {
+	ImageBaseURL     string // Rewrite relative image links to this URL plus their path in the archive; embed them when empty
+	DirsAsCategories bool   // Use the directory of a file as category path when frontmatter has none
+}
+
+// markdownFrontmatter is the YAML frontmatter of an imported markdown file
+type markdownFrontmatter struct {
+	Title    string     `yaml:"title"`
+	Slug     string     `yaml:"slug"`
+	Tags     stringList `yaml:"tags"`
+	Category string     `yaml:"category"` // Category path, e.g. "基础技术/区块链原理"
+	Status   string     `yaml:"status"`
+	Summary  string     `yaml:"summary"`
+	Sources  stringList `yaml:"sources"`
+}
+
+// stringList accepts a YAML sequence or a comma-separated string
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, s := range strings.Split(value.Value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				*l = append(*l, s)
+			}
+		}
+		return nil
+	}
+	var items []string
+	if err := value.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+// ParseMarkdownZip builds an import batch from the .md files of a zip archive, one article per
+// file in path order. Files that cannot be parsed are returned as errors with index -1.
+func ParseMarkdownZip(data []byte, mdOpts MarkdownImportOptions) (*ImportBatch, []ImportError, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	var paths []string
+	for _, f := range archive.File {
+		name := path.Clean(strings.TrimPrefix(f.Name, "/"))
+		if f.FileInfo().IsDir() || isHiddenPath(name) {
+			continue
+		}
+		files[name] = f
+		if strings.EqualFold(path.Ext(name), ".md") || strings.EqualFold(path.Ext(name), ".markdown") {
+			paths = append(paths, name)
+		}
+	}
+	sort.Strings(paths)
+
+	batch := &ImportBatch{}
+	var parseErrors []ImportError
+	for _, name := range paths {
+		content, err := readZipFile(files[name], maxMarkdownFileSize)
+		if err == nil {
+			var article *ImportArticle
+			article, err = ParseMarkdownArticle(name, content, mdOpts)
+			if err == nil {
+				article.Content = resolveImageLinks(article.Content, name, files, mdOpts.ImageBaseURL)
+				batch.Articles = append(batch.Articles, *article)
+				continue
+			}
+		}
+		parseErrors = append(parseErrors, ImportError{Index: -1, Title: name, File: name, Message: err.Error()})
+	}
+	return batch, parseErrors, nil
+}
+
+// ParseMarkdownArticle builds an import article from a markdown file with optional YAML
+// frontmatter. Without a frontmatter title, a leading "# " heading or the file name is used.
+func ParseMarkdownArticle(name string, data []byte, mdOpts MarkdownImportOptions) (*ImportArticle, error) {
+	text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\ufeff"), "\r\n", "\n")
+
+	var meta markdownFrontmatter
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		end := strings.Index(rest, "\n---")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated frontmatter")
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+			return nil, fmt.Errorf("invalid frontmatter: %w", err)
+		}
+		text = rest[end+len("\n---"):]
+		if nl := strings.IndexByte(text, '\n'); nl >= 0 {
+			text = text[nl+1:]
+		} else {
+			text = ""
+		}
+	}
+	text = strings.TrimSpace(text)
+
+	title := strings.TrimSpace(meta.Title)
+	if title == "" {
+		if heading, ok := strings.CutPrefix(text, "# "); ok {
+			line, body, _ := strings.Cut(heading, "\n")
+			title = strings.TrimSpace(line)
+			text = strings.TrimSpace(body)
+		} else {
+			title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+		}
+	}
+
+	categoryPath := strings.Trim(meta.Category, "/ ")
+	if categoryPath == "" && mdOpts.DirsAsCategories {
+		if dir := path.Dir(name); dir != "." {
+			categoryPath = dir
+		}
+	}
+
+	return &ImportArticle{
+		Title:        title,
+		Content:      text,
+		Summary:      meta.Summary,
+		CategoryPath: categoryPath,
+		Tags:         meta.Tags,
+		Status:       meta.Status,
+		SourceURLs:   meta.Sources,
+		Slug:         meta.Slug,
+		File:         name,
+	}, nil
+}
+
+// resolveImageLinks rewrites image links relative to the markdown file. With a base URL they
+// point at the file's path under it; otherwise images found in the archive are embedded as
+// data URIs. Absolute and missing links are left unchanged.
+func resolveImageLinks(content, name string, files map[string]*zip.File, baseURL string) string {
+	return markdownImageLink.ReplaceAllStringFunc(content, func(match string) string {
+		parts := markdownImageLink.FindStringSubmatch(match)
+		link := strings.TrimSuffix(strings.TrimPrefix(parts[2], "<"), ">")
+		if link == "" || strings.Contains(link, ":") || strings.HasPrefix(link, "/") || strings.HasPrefix(link, "#") {
+			return match
+		}
+		if unescaped, err := url.PathUnescape(link); err == nil {
+			link = unescaped
+		}
+		target := path.Join(path.Dir(name), link)
+		if strings.HasPrefix(target, "../") {
+			return match
+		}
+
+		if baseURL != "" {
+			return parts[1] + strings.TrimRight(baseURL, "/") + "/" + (&url.URL{Path: target}).EscapedPath()
+		}
+		f, ok := files[target]
+		if !ok {
+			return match
+		}
+		mediaType := mime.TypeByExtension(strings.ToLower(path.Ext(target)))
+		if !strings.HasPrefix(mediaType, "image/") {
+			return match
+		}
+		image, err := readZipFile(f, maxEmbeddedImageSize)
+		if err != nil {
+			return match
+		}
+		return parts[1] + "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(image)
+	})
+}
+
+// readZipFile reads an archive entry of at most limit bytes
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("file exceeds %d MB", limit>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("file exceeds %d MB", limit>>20)
+	}
+	return data, nil
+}
+
+// isHiddenPath reports whether any element of an archive path is hidden or macOS metadata
+func isHiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
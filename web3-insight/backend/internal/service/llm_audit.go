@@ -0,0 +1,85 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Audit log defaults used when the config leaves them unset
+const (
+	DefaultLLMCallRetentionDays = 30
+	defaultAuditMaxTextLength   = 20000
+)
+
+// LLMCallLogger writes every LLM call made through a router to the llm_calls table
+type LLMCallLogger struct {
+	repo          *repository.LLMCallRepository
+	storePrompts  bool
+	maxTextLength int
+}
+
+// NewLLMCallLogger creates an audit logger; prompt and response text is kept only if storePrompts is set
+func NewLLMCallLogger(repo *repository.LLMCallRepository, storePrompts bool, maxTextLength int) *LLMCallLogger {
+	if maxTextLength <= 0 {
+		maxTextLength = defaultAuditMaxTextLength
+	}
+	return &LLMCallLogger{
+		repo:          repo,
+		storePrompts:  storePrompts,
+		maxTextLength: maxTextLength,
+	}
+}
+
+// NewLLMCallLoggerFromConfig creates an audit logger, or returns nil if auditing is disabled
+func NewLLMCallLoggerFromConfig(repo *repository.LLMCallRepository, auditCfg *config.LLMAuditConfig) llm.CallLogger {
+	if !auditCfg.Enabled {
+		return nil
+	}
+	return NewLLMCallLogger(repo, auditCfg.StorePrompts, auditCfg.MaxTextLength)
+}
+
+// LogCall stores one call; failures are logged so the LLM call itself is unaffected
+func (l *LLMCallLogger) LogCall(record llm.CallRecord) {
+	sum := sha256.Sum256([]byte(record.Prompt))
+	call := &model.LLMCall{
+		Task:         record.Task,
+		Model:        record.Model,
+		Provider:     record.Provider,
+		PromptHash:   hex.EncodeToString(sum[:]),
+		Status:       model.LLMCallStatusSuccess,
+		DurationMs:   record.Duration.Milliseconds(),
+		InputTokens:  record.Usage.InputTokens,
+		OutputTokens: record.Usage.OutputTokens,
+		CostUSD:      decimal.NewFromFloat(record.CostUSD),
+		Retries:      record.Retries,
+		Stream:       record.Stream,
+	}
+	if l.storePrompts {
+		call.Prompt = truncateString(record.Prompt, l.maxTextLength)
+		call.Response = truncateString(record.Response, l.maxTextLength)
+	}
+	if record.Err != nil {
+		call.Status = model.LLMCallStatusError
+		call.Error = record.Err.Error()
+	}
+
+	if err := l.repo.Create(call); err != nil {
+		log.Printf("Failed to record LLM call to %s: %v", record.Model, err)
+	}
+}
+
+// PurgeLLMCalls deletes audited calls older than the retention period
+func PurgeLLMCalls(repo *repository.LLMCallRepository, retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		retentionDays = DefaultLLMCallRetentionDays
+	}
+	return repo.DeleteOlderThan(time.Now().AddDate(0, 0, -retentionDays))
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// DefaultStorySimilarity is the cosine similarity above which two news items are filed as
+// the same story
+const DefaultStorySimilarity = 0.9
+
+const (
+	storyWindow         = 72 * time.Hour     // Largest fetch time gap between items of a story
+	newsClusterLookback = 7 * 24 * time.Hour // Older items are not embedded for clustering
+	newsEmbeddingChars  = 2000
+)
+
+// NewsClusterer embeds news items and groups near-duplicates from different feeds into
+// stories
+type NewsClusterer struct {
+	newsRepo *repository.NewsRepository
+	adapter  llm.EmbeddingAdapter
+}
+
+// NewNewsClusterer creates a new news clusterer
+func NewNewsClusterer(newsRepo *repository.NewsRepository, adapter llm.EmbeddingAdapter) *NewsClusterer {
+	return &NewsClusterer{newsRepo: newsRepo, adapter: adapter}
+}
+
+// NewsClusterResult counts the outcome of a clustering run
+type NewsClusterResult struct {
+	Embedded       int
+	Clustered      int // Items filed into a story
+	StoriesCreated int
+}
+
+// ClusterNew embeds up to limit recent items that have no embedding yet, oldest first, and
+// files each one at least threshold similar to an item fetched within three days into that
+// item's story, starting one if needed. The item collected first stays canonical.
+func (c *NewsClusterer) ClusterNew(ctx context.Context, threshold float64, limit int) (*NewsClusterResult, error) {
+	if threshold <= 0 {
+		threshold = DefaultStorySimilarity
+	}
+	if !c.adapter.IsAvailable() {
+		return nil, errors.New("embedding adapter not available")
+	}
+
+	items, err := c.newsRepo.FindWithoutEmbeddings(time.Now().Add(-newsClusterLookback), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find news to embed: %w", err)
+	}
+	result := &NewsClusterResult{}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	texts := make([]string, len(items))
+	for i := range items {
+		texts[i] = newsEmbeddingText(&items[i])
+	}
+	embeddings, err := c.adapter.GenerateBatchEmbeddings(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed news: %w", err)
+	}
+	if len(embeddings) != len(items) {
+		return nil, fmt.Errorf("got %d embeddings for %d news items", len(embeddings), len(items))
+	}
+
+	// An item's embedding is stored after it is filed, so it is never matched before then
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		item := &items[i]
+		vec := llm.Float32ToVector(embeddings[i])
+
+		similar, err := c.newsRepo.FindSimilar(vec, item.FetchedAt.Add(-storyWindow), item.FetchedAt.Add(storyWindow), item.ID, 1)
+		if err != nil {
+			return result, fmt.Errorf("failed to find similar news: %w", err)
+		}
+		if len(similar) > 0 && similar[0].Similarity >= threshold {
+			created, err := c.file(item, &similar[0].NewsItem)
+			if err != nil {
+				log.Printf("Failed to file news %s into a story: %v", item.ID, err)
+				continue
+			}
+			result.Clustered++
+			if created {
+				result.StoriesCreated++
+			}
+		}
+
+		if err := c.newsRepo.UpdateEmbedding(item.ID, vec); err != nil {
+			return result, fmt.Errorf("failed to save embedding of news %s: %w", item.ID, err)
+		}
+		result.Embedded++
+	}
+	return result, nil
+}
+
+// file adds an item to the story of its nearest neighbour, or starts a story of the two with
+// the one fetched first as canonical. Reports whether a story was created.
+func (c *NewsClusterer) file(item, neighbour *model.NewsItem) (bool, error) {
+	if neighbour.StoryID != nil {
+		return false, c.newsRepo.AddToStory(*neighbour.StoryID, item)
+	}
+	canonical, duplicate := neighbour, item
+	if item.FetchedAt.Before(neighbour.FetchedAt) {
+		canonical, duplicate = item, neighbour
+	}
+	_, err := c.newsRepo.CreateStory(canonical, duplicate)
+	return err == nil, err
+}
+
+// newsEmbeddingText is the text embedded for a news item: its original title and the start
+// of its content
+func newsEmbeddingText(item *model.NewsItem) string {
+	title := item.OriginalTitle
+	if title == "" {
+		title = item.Title
+	}
+	body := item.Content
+	if body == "" {
+		body = item.Summary
+	}
+	return strings.TrimSpace(title + "\n\n" + truncateString(body, newsEmbeddingChars))
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// ErrNewsPipelineDone is returned when the pipeline already decided on a news item
+var ErrNewsPipelineDone = errors.New("news item already went through the pipeline")
+
+// Limits for the news-to-article pipeline
+const (
+	pipelineCandidates       = 5
+	pipelineCandidateSummary = 150
+	pipelineArticleExcerpt   = 3000
+	pipelineNewsContent      = 4000
+)
+
+// Steps of the news-to-article pipeline, stored in the payload of their task rows
+const (
+	pipelineStepSummarize = "summarize"
+	pipelineStepDecide    = "decide"
+	pipelineStepGenerate  = "generate"
+	pipelineStepUpdate    = "update"
+	pipelineStepClassify  = "classify"
+	pipelineStepEmbed     = "embed"
+)
+
+// newsArticleDecisionSchema is the JSON schema pipeline decisions must match
+var newsArticleDecisionSchema = llm.MustJSONSchema("news_article_decision", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"action": map[string]interface{}{"type": "string", "enum": []interface{}{model.PipelineDecisionCreate, model.PipelineDecisionUpdate, model.PipelineDecisionSkip}},
+		"index":  map[string]interface{}{"type": "integer", "minimum": 0},
+		"topic":  map[string]interface{}{"type": "string"},
+		"reason": map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"action"},
+})
+
+// NewsPipeline turns collected news into knowledge articles: it summarizes an item, asks the
+// LLM whether the item warrants a new article or an update to a related one, then writes the
+// new article for review, classifying and embedding it, or proposes the update as a revision
+// of the related article. Each step is recorded as a news_pipeline task row.
+type NewsPipeline struct {
+	llmRouter   *llm.Router
+	newsRepo    *repository.NewsRepository
+	articleRepo *repository.ArticleRepository
+	taskRepo    *repository.TaskRepository
+	summarizer  *Summarizer
+	search      *SemanticSearchService
+	generator   *Generator
+	classifier  *Classifier
+	embedding   *EmbeddingService
+}
+
+// NewNewsPipeline creates a news-to-article pipeline. The generator should have no article
+// hooks, since the pipeline classifies and embeds the articles it writes itself.
+func NewNewsPipeline(
+	router *llm.Router,
+	newsRepo *repository.NewsRepository,
+	articleRepo *repository.ArticleRepository,
+	taskRepo *repository.TaskRepository,
+	summarizer *Summarizer,
+	search *SemanticSearchService,
+	generator *Generator,
+	classifier *Classifier,
+	embedding *EmbeddingService,
+) *NewsPipeline {
+	return &NewsPipeline{
+		llmRouter:   router,
+		newsRepo:    newsRepo,
+		articleRepo: articleRepo,
+		taskRepo:    taskRepo,
+		summarizer:  summarizer,
+		search:      search,
+		generator:   generator,
+		classifier:  classifier,
+		embedding:   embedding,
+	}
+}
+
+// NewsPipelineResult is the outcome of the pipeline for one news item
+type NewsPipelineResult struct {
+	NewsID     uuid.UUID
+	Decision   string     // One of the PipelineDecision constants
+	ArticleID  *uuid.UUID // Article created or updated
+	RevisionID *uuid.UUID // Revision proposed for the updated article
+	Reason     string
+	Embedded   bool // The article's embedding was regenerated
+}
+
+// newsArticleDecision is the parsed LLM decision on a news item
+type newsArticleDecision struct {
+	Action string `json:"action"`
+	Index  int    `json:"index"`
+	Topic  string `json:"topic"`
+	Reason string `json:"reason"`
+}
+
+// Process runs a news item through the pipeline. The item is claimed first, so concurrent
+// runs never work on the same item; a run that fails before the article is written hands it
+// back for a retry, up to model.MaxPipelineAttempts runs. The decision is stored on the item
+// once the article is written, so classification or embedding failures are only recorded in
+// their steps and do not fail the run.
+func (p *NewsPipeline) Process(ctx context.Context, newsID uuid.UUID) (result *NewsPipelineResult, err error) {
+	claimed, err := p.newsRepo.ClaimPipeline(newsID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim news item: %w", err)
+	}
+	if !claimed {
+		return nil, ErrNewsPipelineDone
+	}
+	written := false
+	defer func() {
+		// Once the article is written a retry would write it again, so the claim is kept
+		if err != nil && !written {
+			if releaseErr := p.newsRepo.ReleasePipeline(newsID); releaseErr != nil {
+				log.Printf("Failed to release news %s from the pipeline: %v", newsID, releaseErr)
+			}
+		}
+	}()
+
+	item, err := p.newsRepo.FindByID(newsID)
+	if err != nil {
+		return nil, fmt.Errorf("news item not found: %w", err)
+	}
+
+	result = &NewsPipelineResult{NewsID: item.ID}
+	if item.ContentBlock != "" {
+		result.Decision = model.PipelineDecisionSkip
+		result.Reason = "content is behind a " + item.ContentBlock
+		return result, p.newsRepo.SetPipelineDecision(item.ID, result.Decision, nil)
+	}
+	// Every article the pipeline writes or updates reuses the item, so its license has to allow it
+	if err := AttributeNews(&model.Article{}, item); err != nil {
+		result.Decision = model.PipelineDecisionSkip
+		result.Reason = err.Error()
+		return result, p.newsRepo.SetPipelineDecision(item.ID, result.Decision, nil)
+	}
+
+	if !item.Processed {
+		filtered := false
+		err := p.runStep(item.ID, pipelineStepSummarize, func() (interface{}, *llm.GenerateResult, error) {
+			err := p.summarizer.SummarizeByID(ctx, item.ID)
+			if errors.Is(err, ErrNewsFiltered) {
+				filtered = true
+				return map[string]interface{}{"filtered": true}, nil, nil
+			}
+			return nil, nil, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("summarization failed: %w", err)
+		}
+		// An item that scored too low for summarization is not worth an article either
+		if filtered {
+			result.Decision = model.PipelineDecisionSkip
+			result.Reason = ErrNewsFiltered.Error()
+			return result, p.newsRepo.SetPipelineDecision(item.ID, result.Decision, nil)
+		}
+		if item, err = p.newsRepo.FindByID(newsID); err != nil {
+			return nil, fmt.Errorf("news item not found: %w", err)
+		}
+	}
+
+	var decision *newsArticleDecision
+	var target *model.Article
+	err = p.runStep(item.ID, pipelineStepDecide, func() (interface{}, *llm.GenerateResult, error) {
+		candidates, err := p.relatedArticles(ctx, item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find related articles: %w", err)
+		}
+		var generated *llm.GenerateResult
+		decision, generated, err = p.decide(ctx, item, candidates)
+		if err != nil {
+			return nil, generated, err
+		}
+		stepResult := map[string]interface{}{"action": decision.Action, "reason": decision.Reason, "candidates": len(candidates)}
+		switch decision.Action {
+		case model.PipelineDecisionUpdate:
+			target = &candidates[decision.Index-1]
+			stepResult["articleId"] = target.ID
+		case model.PipelineDecisionCreate:
+			stepResult["topic"] = decision.Topic
+		}
+		return stepResult, generated, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decision failed: %w", err)
+	}
+	result.Decision = decision.Action
+	result.Reason = decision.Reason
+
+	var article *model.Article
+	switch decision.Action {
+	case model.PipelineDecisionCreate:
+		err = p.runStep(item.ID, pipelineStepGenerate, func() (interface{}, *llm.GenerateResult, error) {
+			generation, err := p.generator.GenerateArticle(ctx, &GenerationRequest{
+				Topic:      decision.Topic,
+				References: newsReferences(item),
+				SourceURLs: []string{item.SourceURL},
+				Status:     model.ArticleStatusInReview,
+				Source:     item,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			article = generation.Article
+			return map[string]interface{}{"articleId": article.ID, "title": article.Title}, nil, nil
+		})
+	case model.PipelineDecisionUpdate:
+		result.ArticleID = &target.ID
+		err = p.runStep(item.ID, pipelineStepUpdate, func() (interface{}, *llm.GenerateResult, error) {
+			revision, generated, err := p.proposeUpdate(ctx, item, target)
+			if err != nil {
+				return map[string]interface{}{"articleId": target.ID}, generated, err
+			}
+			result.RevisionID = &revision.ID
+			return map[string]interface{}{"articleId": target.ID, "revisionId": revision.ID}, generated, nil
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("article %s failed: %w", decision.Action, err)
+	}
+	written = true
+
+	if article != nil {
+		result.ArticleID = &article.ID
+	}
+	if err := p.newsRepo.SetPipelineDecision(item.ID, result.Decision, result.ArticleID); err != nil {
+		return result, fmt.Errorf("failed to save pipeline decision: %w", err)
+	}
+	// Updates wait for review as revisions; the article is reclassified and embedded as it is
+	if article == nil {
+		return result, nil
+	}
+
+	if article.CategoryID == nil && p.classifier != nil {
+		err := p.runStep(item.ID, pipelineStepClassify, func() (interface{}, *llm.GenerateResult, error) {
+			return map[string]interface{}{"articleId": article.ID}, nil, p.classifier.ClassifyAndUpdate(ctx, article.ID)
+		})
+		if err != nil {
+			log.Printf("Pipeline classification of article %s failed: %v", article.ID, err)
+		}
+	}
+	if p.embedding != nil && p.embedding.IsAvailable() {
+		err := p.runStep(item.ID, pipelineStepEmbed, func() (interface{}, *llm.GenerateResult, error) {
+			return map[string]interface{}{"articleId": article.ID}, nil, p.embedding.GenerateForArticle(ctx, article.ID)
+		})
+		if err != nil {
+			log.Printf("Pipeline embedding of article %s failed: %v", article.ID, err)
+		} else {
+			result.Embedded = true
+		}
+	}
+
+	return result, nil
+}
+
+// ProcessPending runs the pipeline on up to limit summarized items fetched after since. It
+// returns the results of the items that went through and the number that failed.
+func (p *NewsPipeline) ProcessPending(ctx context.Context, since time.Time, limit int) ([]*NewsPipelineResult, int, error) {
+	items, err := p.newsRepo.FindPendingPipeline(since, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find pending news: %w", err)
+	}
+
+	var results []*NewsPipelineResult
+	failed := 0
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return results, failed, err
+		}
+		result, err := p.Process(ctx, item.ID)
+		if err != nil {
+			if !errors.Is(err, ErrNewsPipelineDone) {
+				log.Printf("Pipeline failed for news %s: %v", item.ID, err)
+				failed++
+			}
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, failed, nil
+}
+
+// relatedArticles finds the articles a news item could update
+func (p *NewsPipeline) relatedArticles(ctx context.Context, item *model.NewsItem) ([]model.Article, error) {
+	query := strings.TrimSpace(item.Title + "\n" + item.Summary)
+	if query == "" {
+		return nil, nil
+	}
+	return p.search.HybridSearch(ctx, query, pipelineCandidates, nil, nil)
+}
+
+// decide asks the LLM what a news item should become. An update naming no candidate is
+// treated as a skip, and a new article without a topic takes the news title.
+func (p *NewsPipeline) decide(ctx context.Context, item *model.NewsItem, candidates []model.Article) (*newsArticleDecision, *llm.GenerateResult, error) {
+	var list strings.Builder
+	for i, c := range candidates {
+		fmt.Fprintf(&list, "%d. %s：%s\n", i+1, c.Title, truncateString(c.Summary, pipelineCandidateSummary))
+	}
+	if len(candidates) == 0 {
+		list.WriteString("（无）\n")
+	}
+	summary := item.Summary
+	if summary == "" {
+		summary = truncateString(item.Content, 500)
+	}
+	prompt := fmt.Sprintf(PromptNewsArticleDecision, item.Title, summary, list.String())
+
+	generated, err := p.llmRouter.GenerateStructured(llm.TaskClassification, prompt, newsArticleDecisionSchema, &llm.GenerateOptions{
+		Temperature: 0.2,
+		MaxTokens:   500,
+		Context:     ctx,
+	})
+	if err != nil {
+		return nil, generated, fmt.Errorf("LLM decision failed: %w", err)
+	}
+
+	decision := &newsArticleDecision{}
+	if err := json.Unmarshal([]byte(generated.Content), decision); err != nil {
+		return nil, generated, fmt.Errorf("failed to parse decision: %w", err)
+	}
+	switch decision.Action {
+	case model.PipelineDecisionUpdate:
+		if decision.Index < 1 || decision.Index > len(candidates) {
+			decision.Action = model.PipelineDecisionSkip
+			decision.Reason = strings.TrimSpace(decision.Reason + " (update named no related article)")
+		}
+	case model.PipelineDecisionCreate:
+		if strings.TrimSpace(decision.Topic) == "" {
+			decision.Topic = item.Title
+		}
+	}
+	return decision, generated, nil
+}
+
+// proposeUpdate has the LLM write a section on the news and proposes appending it to the
+// article as a revision, which a reviewer applies or rejects
+func (p *NewsPipeline) proposeUpdate(ctx context.Context, item *model.NewsItem, article *model.Article) (*model.ArticleRevision, *llm.GenerateResult, error) {
+	prompt := fmt.Sprintf(PromptNewsArticleUpdate,
+		article.Title, truncateString(article.Content, pipelineArticleExcerpt),
+		item.Title, truncateString(item.Content, pipelineNewsContent))
+
+	generated, err := p.llmRouter.Generate(llm.TaskContentGeneration, prompt, &llm.GenerateOptions{
+		Temperature: 0.5,
+		MaxTokens:   2000,
+		Context:     ctx,
+	})
+	if err != nil {
+		return nil, generated, fmt.Errorf("LLM update failed: %w", err)
+	}
+
+	var section strings.Builder
+	section.WriteString(fmt.Sprintf("## 最新进展（%s）\n\n", displayDate(time.Now())))
+	section.WriteString(strings.TrimSpace(generated.Content))
+	section.WriteString(fmt.Sprintf("\n\n来源：[%s](%s)\n", item.Title, item.SourceURL))
+
+	revision := &model.ArticleRevision{
+		ArticleID: article.ID,
+		NewsID:    &item.ID,
+		Section:   section.String(),
+		SourceURL: item.SourceURL,
+		ModelUsed: generated.Model,
+		Status:    model.RevisionStatusPending,
+	}
+	if err := p.articleRepo.CreateRevision(revision); err != nil {
+		return nil, generated, fmt.Errorf("failed to save revision: %w", err)
+	}
+	return revision, generated, nil
+}
+
+// newsReferences are the generation references taken from a news item
+func newsReferences(item *model.NewsItem) []string {
+	refs := []string{fmt.Sprintf("%s（%s）", item.Title, item.SourceURL)}
+	if item.Summary != "" {
+		refs = append(refs, item.Summary)
+	}
+	if item.Content != "" {
+		refs = append(refs, truncateString(item.Content, pipelineNewsContent))
+	}
+	return refs
+}
+
+// runStep records one pipeline step as a task row, running while fn runs and then completed
+// or failed with fn's result. LLM calls made directly by a step are billed to its row; a
+// row that cannot be saved is logged and never stops the pipeline.
+func (p *NewsPipeline) runStep(newsID uuid.UUID, step string, fn func() (interface{}, *llm.GenerateResult, error)) error {
+	startedAt := time.Now()
+	payload, _ := json.Marshal(map[string]interface{}{"newsId": newsID, "step": step})
+	task := &model.Task{
+		Type:      model.TaskTypeNewsPipeline,
+		Status:    model.TaskStatusRunning,
+		Payload:   payload,
+		StartedAt: &startedAt,
+	}
+	if err := p.taskRepo.Create(task); err != nil {
+		log.Printf("Failed to record pipeline step %s of news %s: %v", step, newsID, err)
+		task = nil
+	}
+
+	result, generated, err := fn()
+	if task == nil {
+		return err
+	}
+
+	completedAt := time.Now()
+	task.CompletedAt = &completedAt
+	task.Status = model.TaskStatusCompleted
+	if err != nil {
+		task.Status = model.TaskStatusFailed
+		task.Error = err.Error()
+	}
+	if result != nil {
+		task.Result, _ = json.Marshal(result)
+	}
+	if generated != nil {
+		task.ModelUsed = generated.Model
+		task.TokensUsed = generated.Usage.TotalTokens()
+		task.CostUSD = decimal.NewFromFloat(generated.CostUSD)
+	}
+	if saveErr := p.taskRepo.Update(task); saveErr != nil {
+		log.Printf("Failed to record pipeline step %s of news %s: %v", step, newsID, saveErr)
+	}
+	return err
+}
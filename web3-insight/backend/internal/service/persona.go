@@ -0,0 +1,359 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Prompts that a persona can override
+const (
+	PromptNameChatGeneral      = "chat_general"      // System prompt for chat without an article
+	PromptNameChatArticle      = "chat_article"      // System prompt for chat about an article: title, content
+	PromptNameChatSources      = "chat_sources"      // System prompt for chat about an article with retrieved excerpts: title, excerpts
+	PromptNameChatCorpus       = "chat_corpus"       // System prompt for chat about the whole knowledge base: excerpts
+	PromptNameChatFollowUps    = "chat_follow_ups"   // Suggested follow-up questions: question, answer
+	PromptNameChatSelection    = "chat_selection"    // User message about selected text: selection, question
+	PromptNameKnowledgeArticle = "knowledge_article" // Article generation: topic, references
+	PromptNameInstantResearch  = "instant_research"  // Instant research: query, context
+)
+
+// Built-in personas
+const (
+	PersonaDefault = "default" // Chinese explanations for developers new to Web3
+	PersonaEnglish = "english" // English-first deployments
+	PersonaTrading = "trading" // Market and trading focus, in Chinese
+)
+
+// Prompt store errors
+var (
+	ErrUnknownPrompt = errors.New("unknown prompt")
+	ErrInvalidPrompt = errors.New("invalid prompt")
+)
+
+// builtinPersonas holds the prompts of each built-in persona. Personas may leave prompts out;
+// missing prompts and personas fall back to the default persona.
+var builtinPersonas = map[string]map[string]string{
+	PersonaDefault: {
+		PromptNameChatGeneral:      PromptChatGeneral,
+		PromptNameChatArticle:      PromptChatArticle,
+		PromptNameChatSources:      PromptChatArticleSources,
+		PromptNameChatCorpus:       PromptChatCorpus,
+		PromptNameChatFollowUps:    PromptChatFollowUps,
+		PromptNameChatSelection:    PromptChatSelection,
+		PromptNameKnowledgeArticle: PromptKnowledgeArticle,
+		PromptNameInstantResearch:  PromptInstantResearch,
+	},
+	PersonaEnglish: {
+		PromptNameChatGeneral: `You are a Web3 technical assistant who helps users understand blockchains, cryptocurrencies, DeFi, NFTs and related technology.
+
+Answer in English and keep terminology consistent. Your answers should:
+1. Be accurate, clear and helpful
+2. Explain complex concepts in plain language
+3. Give examples where they help
+4. Point out risks where relevant`,
+		PromptNameChatArticle: `You are a Web3 technical assistant. The user is reading an article about "%s" and has questions about it.
+
+Article:
+%s
+
+Answer based on the article. If the question goes beyond it, you may add related knowledge.
+Answer in English and keep terminology consistent. Be accurate, clear and helpful.`,
+		PromptNameChatSources: `You are a Web3 technical assistant. The user is reading an article about "%s" and has questions about it.
+
+These are the passages of the article and related articles most relevant to the question, each starting with a [number]:
+%s
+
+Answer based on these passages and cite them by adding their [number] after the sentences that use them. If they are not enough to answer, you may add related knowledge, but do not cite a passage for it.
+Answer in English and keep terminology consistent. Be accurate, clear and helpful.`,
+		PromptNameChatCorpus: `You are a Web3 technical assistant answering questions from the articles in a knowledge base.
+
+These are the passages of the knowledge base most relevant to the question, each starting with a [number] and its article title:
+%s
+
+Answer based on these passages and cite them by adding their [number] after the sentences that use them. Point out where passages disagree. If they are not enough to answer, say what the knowledge base is missing; you may add related knowledge, but do not cite a passage for it.
+Answer in English and keep terminology consistent. Be accurate, clear and helpful.`,
+		PromptNameChatFollowUps: `A user asked a question in a Web3 knowledge base and got an answer. Suggest 3 questions the user might want to ask next.
+
+Question:
+%s
+
+Answer:
+%s
+
+Requirements:
+1. Go deeper into or beyond the answer; do not repeat what was already answered
+2. Keep each question short and specific, under 15 words
+3. Write in English
+
+Return this JSON format (no markdown code fences):
+{"questions": ["First question", "Second question", "Third question"]}`,
+		PromptNameChatSelection: `About this passage: "%s"
+
+%s`,
+		PromptNameKnowledgeArticle: `You are a Web3 expert writing technical documentation for a software engineer who has just joined a blockchain company.
+
+Requirements:
+1. Write in English; be precise and professional
+2. Expand abbreviations on first use, e.g. "EVM (Ethereum Virtual Machine)"
+3. Structure:
+   - ## Overview (one paragraph)
+   - ## How It Works (the mechanism in detail)
+   - ## Technical Details (in depth)
+   - ## Strengths and Limitations (balanced)
+   - ## Real-World Use (with examples)
+   - ## Related Technologies (links to other concepts)
+4. Length: 1500-3000 words
+5. Put code examples in markdown code blocks
+
+Topic: %s
+
+References:
+%s
+
+Output the article as markdown only.`,
+		PromptNameInstantResearch: `You are a Web3 research assistant. The user wants to understand a technical concept; give a thorough, in-depth explanation.
+
+Requirements:
+1. Answer in English
+2. Use clear structure with markdown headings and lists
+3. For recent concepts, explain their background and current state
+4. Offer practical ways to think about the concept
+
+Question: %s
+
+%s
+
+Give a detailed explanation.`,
+	},
+	PersonaTrading: {
+		PromptNameChatGeneral: `你是一个 Web3 交易研究助手，帮助用户理解加密资产、DeFi 协议和链上数据对市场的影响。
+
+请用中文回答用户的问题，保持专业术语的一致性。回答应该：
+1. 准确、清晰，区分事实与观点
+2. 关注代币经济、流动性、链上指标和协议收入等与交易相关的因素
+3. 在适当的时候说明相关的市场机制和历史案例
+4. 不提供具体的买卖建议，并提醒用户注意市场、合约和流动性风险`,
+		PromptNameChatArticle: `你是一个 Web3 交易研究助手。用户正在阅读一篇关于「%s」的文章，并想了解其中内容对市场和交易的影响。
+
+文章内容：
+%s
+
+请基于文章内容回答用户的问题，重点说明代币经济、流动性和风险等与交易相关的方面。如果问题超出文章范围，可以补充相关知识。
+使用中文回答，区分事实与观点，不提供具体的买卖建议。`,
+		PromptNameChatSources: `你是一个 Web3 交易研究助手。用户正在阅读一篇关于「%s」的文章，并想了解其中内容对市场和交易的影响。
+
+以下是文章及相关文章中与问题最相关的片段，每个片段以 [编号] 开头：
+%s
+
+请基于这些片段回答用户的问题，重点说明代币经济、流动性和风险等与交易相关的方面，并在引用片段内容的句子后用 [编号] 标注来源。如果片段不足以回答，可以补充相关知识，但不要为补充的内容标注来源。
+使用中文回答，区分事实与观点，不提供具体的买卖建议。`,
+		PromptNameKnowledgeArticle: `你是一个 Web3 交易研究员，正在为加密资产交易团队撰写研究文档。
+
+要求：
+1. 使用中文撰写，保持专业性和准确性，区分事实与观点
+2. 专业术语格式：英文术语 (中文翻译)，如 "Funding Rate (资金费率)"
+3. 首次出现的缩写需要展开，如 "TVL (Total Value Locked, 总锁仓价值)"
+4. 内容结构：
+   - ## 概述（一段话介绍）
+   - ## 工作原理（机制及其对价格和流动性的影响）
+   - ## 代币经济与价值捕获（供应、释放、收入来源）
+   - ## 关键指标（值得跟踪的链上和市场数据）
+   - ## 风险（市场、合约、流动性和监管风险）
+   - ## 相关标的与技术（关联其他协议和概念）
+5. 文章长度：2000-4000 字
+6. 不提供具体的买卖建议
+
+主题：%s
+
+参考资料：
+%s
+
+请直接输出 markdown 格式的文章内容。`,
+		PromptNameInstantResearch: `你是一个 Web3 交易研究助手。用户想了解一个概念对市场和交易的影响，请提供全面且深入的解释。
+
+要求：
+1. 使用中文回答，区分事实与观点
+2. 专业术语格式：英文术语 (中文翻译)
+3. 结构清晰，使用 markdown 标题和列表
+4. 说明相关的代币经济、流动性和关键指标
+5. 列出主要风险，不提供具体的买卖建议
+
+用户问题：%s
+
+%s
+
+请提供详细的解释。`,
+	},
+}
+
+// PromptInfo describes the prompt a persona uses for one feature
+type PromptInfo struct {
+	Name         string `json:"name"`
+	Persona      string `json:"persona"`
+	Content      string `json:"content"`
+	Builtin      string `json:"builtin"`      // The persona's built-in text, used when there is no override
+	Overridden   bool   `json:"overridden"`   // Content comes from the prompt template store
+	Placeholders int    `json:"placeholders"` // Number of %s placeholders the prompt must keep
+}
+
+// PromptStore resolves prompts for the deployment's persona: overrides saved in the
+// database first, then the persona's built-in prompts, then the default persona's.
+// A nil store returns the default persona's built-in prompts.
+type PromptStore struct {
+	repo    *repository.PromptTemplateRepository
+	persona string
+}
+
+// NewPromptStore creates a prompt store for a persona; an empty persona uses the default
+func NewPromptStore(repo *repository.PromptTemplateRepository, persona string) *PromptStore {
+	if persona == "" {
+		persona = PersonaDefault
+	}
+	if _, ok := builtinPersonas[persona]; !ok {
+		log.Printf("Persona %s has no built-in prompts; using overrides and default prompts", persona)
+	}
+	return &PromptStore{repo: repo, persona: persona}
+}
+
+// Persona returns the deployment's persona
+func (s *PromptStore) Persona() string {
+	if s == nil {
+		return PersonaDefault
+	}
+	return s.persona
+}
+
+// Get returns the deployment persona's prompt. Lookup failures fall back to the built-in prompt.
+func (s *PromptStore) Get(name string) string {
+	if s == nil {
+		return builtinPrompt(PersonaDefault, name)
+	}
+	info, err := s.Resolve(s.persona, name)
+	if err != nil {
+		log.Printf("Failed to load prompt %s for persona %s: %v", name, s.persona, err)
+		return builtinPrompt(s.persona, name)
+	}
+	return info.Content
+}
+
+// Resolve returns the prompt a persona uses for a feature
+func (s *PromptStore) Resolve(persona, name string) (*PromptInfo, error) {
+	if _, ok := builtinPersonas[PersonaDefault][name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+	}
+	builtin := builtinPrompt(persona, name)
+	info := &PromptInfo{
+		Name:         name,
+		Persona:      persona,
+		Content:      builtin,
+		Builtin:      builtin,
+		Placeholders: strings.Count(builtin, "%s"),
+	}
+
+	template, err := s.repo.Get(persona, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.Content = template.Content
+	info.Overridden = true
+	return info, nil
+}
+
+// List returns every prompt a persona uses, sorted by name
+func (s *PromptStore) List(persona string) ([]PromptInfo, error) {
+	names := PromptNames()
+	prompts := make([]PromptInfo, 0, len(names))
+	for _, name := range names {
+		info, err := s.Resolve(persona, name)
+		if err != nil {
+			return nil, err
+		}
+		prompts = append(prompts, *info)
+	}
+	return prompts, nil
+}
+
+// Set saves an override of a persona's prompt. It must keep the built-in prompt's placeholders.
+func (s *PromptStore) Set(persona, name, content string) (*PromptInfo, error) {
+	info, err := s.Resolve(persona, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(persona) > 50 {
+		return nil, fmt.Errorf("%w: persona name longer than 50 characters", ErrInvalidPrompt)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("%w: content is empty", ErrInvalidPrompt)
+	}
+	if n := strings.Count(content, "%s"); n != info.Placeholders {
+		return nil, fmt.Errorf("%w: must contain exactly %d %%s placeholders, found %d", ErrInvalidPrompt, info.Placeholders, n)
+	}
+
+	if err := s.repo.Save(&model.PromptTemplate{Persona: persona, Name: name, Content: content}); err != nil {
+		return nil, err
+	}
+	info.Content = content
+	info.Overridden = true
+	return info, nil
+}
+
+// Reset removes the override of a persona's prompt, restoring the built-in text
+func (s *PromptStore) Reset(persona, name string) (*PromptInfo, error) {
+	if _, ok := builtinPersonas[PersonaDefault][name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+	}
+	if err := s.repo.Delete(persona, name); err != nil {
+		return nil, err
+	}
+	return s.Resolve(persona, name)
+}
+
+// Personas returns the built-in personas and those defined only by overrides, sorted
+func (s *PromptStore) Personas() ([]string, error) {
+	seen := make(map[string]bool)
+	for persona := range builtinPersonas {
+		seen[persona] = true
+	}
+	stored, err := s.repo.Personas()
+	if err != nil {
+		return nil, err
+	}
+	for _, persona := range stored {
+		seen[persona] = true
+	}
+
+	personas := make([]string, 0, len(seen))
+	for persona := range seen {
+		personas = append(personas, persona)
+	}
+	sort.Strings(personas)
+	return personas, nil
+}
+
+// PromptNames returns the names of all overridable prompts, sorted
+func PromptNames() []string {
+	names := make([]string, 0, len(builtinPersonas[PersonaDefault]))
+	for name := range builtinPersonas[PersonaDefault] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinPrompt returns a persona's built-in prompt, falling back to the default persona
+func builtinPrompt(persona, name string) string {
+	if prompt, ok := builtinPersonas[persona][name]; ok {
+		return prompt
+	}
+	return builtinPersonas[PersonaDefault][name]
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Limits for LLM prerequisite suggestions
+const (
+	maxPrerequisiteCandidates    = 20
+	minPrerequisiteConfidence    = 0.5
+	prerequisiteCandidateSummary = 150
+)
+
+// prerequisiteSchema is the JSON schema prerequisite suggestions must match
+var prerequisiteSchema = llm.MustJSONSchema("prerequisites", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"prerequisites": map[string]interface{}{
+			"type":     "array",
+			"maxItems": 5,
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"index":      map[string]interface{}{"type": "integer", "minimum": 1},
+					"confidence": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+					"reason":     map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"index"},
+			},
+		},
+	},
+	"required": []interface{}{"prerequisites"},
+})
+
+// PrerequisiteService suggests prerequisite links with the LLM and orders articles into learning paths
+type PrerequisiteService struct {
+	llmRouter   *llm.Router
+	articleRepo *repository.ArticleRepository
+	usage       *UsageRecorder
+}
+
+// NewPrerequisiteService creates a new prerequisite service
+func NewPrerequisiteService(router *llm.Router, articleRepo *repository.ArticleRepository) *PrerequisiteService {
+	return &PrerequisiteService{
+		llmRouter:   router,
+		articleRepo: articleRepo,
+	}
+}
+
+// SetUsageRecorder enables persisting token usage of prerequisite suggestions
+func (s *PrerequisiteService) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// SuggestAndUpdate asks the LLM which related articles should be read first and stores
+// them as LLM-sourced prerequisites, replacing earlier suggestions. Manual links are kept.
+func (s *PrerequisiteService) SuggestAndUpdate(ctx context.Context, articleID uuid.UUID) ([]model.ArticlePrerequisite, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("article not found: %w", err)
+	}
+
+	candidates, err := candidateArticles(s.articleRepo, article, maxPrerequisiteCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candidate articles: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []model.ArticlePrerequisite{}, nil
+	}
+
+	var list strings.Builder
+	for i, c := range candidates {
+		difficulty := c.Difficulty
+		if difficulty == "" {
+			difficulty = "unknown"
+		}
+		fmt.Fprintf(&list, "%d. %s [%s]：%s\n", i+1, c.Title, difficulty, truncateString(c.Summary, prerequisiteCandidateSummary))
+	}
+	summary := article.Summary
+	if summary == "" {
+		summary = truncateString(article.Content, 500)
+	}
+	prompt := fmt.Sprintf(PromptPrerequisites, article.Title, summary, list.String())
+
+	startedAt := time.Now()
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskClassification, prompt, prerequisiteSchema, &llm.GenerateOptions{
+		Temperature: 0.2,
+		MaxTokens:   800,
+	})
+	s.usage.Record(model.TaskTypePrerequisites, map[string]interface{}{"articleId": article.ID}, startedAt, generated, err)
+	if err != nil {
+		return nil, fmt.Errorf("LLM prerequisite suggestion failed: %w", err)
+	}
+
+	var result struct {
+		Prerequisites []struct {
+			Index      int     `json:"index"`
+			Confidence float64 `json:"confidence"`
+			Reason     string  `json:"reason"`
+		} `json:"prerequisites"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse prerequisites: %w", err)
+	}
+
+	var suggestions []model.ArticlePrerequisite
+	seen := make(map[int]bool)
+	for _, p := range result.Prerequisites {
+		if p.Index < 1 || p.Index > len(candidates) || seen[p.Index] || p.Confidence < minPrerequisiteConfidence {
+			continue
+		}
+		seen[p.Index] = true
+		suggestions = append(suggestions, model.ArticlePrerequisite{
+			PrerequisiteID: candidates[p.Index-1].ID,
+			Confidence:     p.Confidence,
+			Reason:         p.Reason,
+		})
+	}
+
+	skipped, err := s.articleRepo.SetPrerequisites(article.ID, model.PrerequisiteSourceLLM, suggestions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save prerequisites: %w", err)
+	}
+	if len(skipped) > 0 {
+		log.Printf("Skipped %d suggested prerequisites of '%s' that would create a cycle", len(skipped), article.Title)
+	}
+
+	return s.articleRepo.ListPrerequisites(article.ID)
+}
+
+// candidateArticles returns up to limit articles that may relate to an article: semantically
+// related ones first, then others in the same category
+func candidateArticles(repo *repository.ArticleRepository, article *model.Article, limit int) ([]model.Article, error) {
+	related, err := repo.FindRelatedArticles(article.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]model.Article, 0, limit)
+	seen := map[uuid.UUID]bool{article.ID: true}
+	add := func(articles []model.Article) {
+		for _, a := range articles {
+			if len(candidates) >= limit || seen[a.ID] {
+				continue
+			}
+			seen[a.ID] = true
+			candidates = append(candidates, a)
+		}
+	}
+	add(related)
+
+	if article.CategoryID != nil && len(candidates) < limit {
+		sameCategory, err := repo.List(repository.ArticleListParams{
+			CategoryID: article.CategoryID,
+			PageSize:   limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		add(sameCategory.Articles)
+	}
+	return candidates, nil
+}
+
+// LearningPath returns the articles with the given IDs and/or in a category ordered so every
+// article comes after its prerequisites. With includePrerequisites, missing prerequisites are added.
+func (s *PrerequisiteService) LearningPath(ids []uuid.UUID, categoryID *uuid.UUID, difficulties []string, includePrerequisites bool) ([]model.Article, error) {
+	articles, err := s.articleRepo.FindForLearningPath(ids, categoryID, difficulties)
+	if err != nil {
+		return nil, err
+	}
+
+	if includePrerequisites && len(articles) > 0 {
+		present := make(map[uuid.UUID]bool, len(articles))
+		for _, a := range articles {
+			present[a.ID] = true
+		}
+		required, err := s.articleRepo.FindTransitivePrerequisiteIDs(articleIDs(articles))
+		if err != nil {
+			return nil, err
+		}
+		var missing []uuid.UUID
+		for _, id := range required {
+			if !present[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			extra, err := s.articleRepo.FindForLearningPath(missing, nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			articles = append(articles, extra...)
+		}
+	}
+
+	edges, err := s.articleRepo.ListPrerequisitesAmong(articleIDs(articles))
+	if err != nil {
+		return nil, err
+	}
+	return OrderByPrerequisites(articles, edges), nil
+}
+
+// OrderByPrerequisites sorts articles topologically so prerequisites come first. Among articles
+// that are ready at the same time, easier and older ones come first. Articles caught in a cycle
+// are appended in the same tie-break order.
+func OrderByPrerequisites(articles []model.Article, edges []model.ArticlePrerequisite) []model.Article {
+	index := make(map[uuid.UUID]int, len(articles))
+	for i, a := range articles {
+		index[a.ID] = i
+	}
+
+	pending := make([]int, len(articles))      // Unmet prerequisites per article
+	dependents := make([][]int, len(articles)) // Articles waiting on each article
+	for _, e := range edges {
+		from, okFrom := index[e.PrerequisiteID]
+		to, okTo := index[e.ArticleID]
+		if !okFrom || !okTo || from == to {
+			continue
+		}
+		pending[to]++
+		dependents[from] = append(dependents[from], to)
+	}
+
+	before := func(i, j int) bool {
+		ri, rj := difficultyRank(articles[i].Difficulty), difficultyRank(articles[j].Difficulty)
+		if ri != rj {
+			return ri < rj
+		}
+		return articles[i].CreatedAt.Before(articles[j].CreatedAt)
+	}
+
+	var ready []int
+	for i := range articles {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]model.Article, 0, len(articles))
+	placed := make([]bool, len(articles))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(a, b int) bool { return before(ready[a], ready[b]) })
+		next := ready[0]
+		ready = ready[1:]
+
+		ordered = append(ordered, articles[next])
+		placed[next] = true
+		for _, d := range dependents[next] {
+			if pending[d]--; pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	var rest []int
+	for i := range articles {
+		if !placed[i] {
+			rest = append(rest, i)
+		}
+	}
+	sort.Slice(rest, func(a, b int) bool { return before(rest[a], rest[b]) })
+	for _, i := range rest {
+		ordered = append(ordered, articles[i])
+	}
+	return ordered
+}
+
+// difficultyRank orders difficulty levels for learning, with unassessed articles last
+func difficultyRank(difficulty string) int {
+	for i, d := range model.Difficulties {
+		if d == difficulty {
+			return i
+		}
+	}
+	return len(model.Difficulties)
+}
+
+// articleIDs returns the IDs of articles
+func articleIDs(articles []model.Article) []uuid.UUID {
+	ids := make([]uuid.UUID, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	return ids
+}
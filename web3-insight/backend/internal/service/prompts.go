@@ -31,15 +31,150 @@ const PromptClassification = `你是一个 Web3 内容分类专家。根据文
 文章标题：%s
 文章内容摘要：%s
 
+如果现有分类都不合适，可以建议新建分类。
+
+同时评估文章难度：
+- beginner：面向刚接触 Web3 的程序员，只需基本编程知识
+- intermediate：需要了解区块链基础概念（交易、区块、智能合约等）
+- advanced：涉及协议实现细节、密码学推导或需要大量前置知识
+
 请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
 {
-  "primaryCategory": "分类路径，如 '基础技术/区块链原理/共识机制'",
-  "secondaryCategories": ["可选的次要分类路径"],
+  "decision": "use_existing 或 create_new",
+  "categoryPath": "分类路径，如 '基础技术/区块链原理/共识机制'",
+  "newCategory": {
+    "name": "新分类中文名（仅 create_new 时提供）",
+    "nameEn": "English name",
+    "parentPath": "父分类路径，顶级分类为 null",
+    "icon": "图标名",
+    "description": "分类描述"
+  },
   "suggestedTags": ["标签1", "标签2"],
+  "difficulty": "beginner/intermediate/advanced 中的一个",
   "confidence": 0.85,
   "reasoning": "分类理由简述"
 }`
 
+const PromptDifficulty = `你是一个 Web3 技术编辑。请评估以下文章对读者的难度要求。
+
+难度等级：
+- beginner：面向刚接触 Web3 的程序员，只需基本编程知识
+- intermediate：需要了解区块链基础概念（交易、区块、智能合约等）
+- advanced：涉及协议实现细节、密码学推导或需要大量前置知识
+
+文章标题：%s
+文章内容摘要：%s
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "difficulty": "beginner/intermediate/advanced 中的一个",
+  "reasoning": "评估理由简述"
+}`
+
+const PromptPrerequisites = `你是一个 Web3 课程设计专家。请判断阅读目标文章之前，读者应该先读哪些候选文章。
+
+目标文章：
+标题：%s
+摘要：%s
+
+候选文章（编号. 标题 [难度]：摘要）：
+%s
+
+要求：
+1. 只选择理解目标文章确实需要的前置知识，不要选择仅仅主题相关的文章
+2. 前置文章通常更基础，不应比目标文章更难
+3. 最多选择 5 篇，没有合适的可以返回空数组
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "prerequisites": [
+    {"index": 1, "confidence": 0.8, "reason": "需要先理解的概念"}
+  ]
+}`
+
+const PromptConceptLinks = `你是一个 Web3 知识图谱编辑。请判断目标文章与每篇候选文章之间的概念关系。
+
+目标文章：
+标题：%s
+摘要：%s
+
+候选文章（编号. 标题：摘要）：
+%s
+
+关系类型（从目标文章指向候选文章）：
+- mentions：目标文章提到了候选文章讲解的概念
+- depends_on：理解目标文章需要先掌握候选文章的概念
+- contrasts_with：两篇文章讲解的是可以相互对比的方案或概念，如 Optimistic Rollup 与 ZK Rollup
+
+要求：
+1. 只在关系明确时给出，仅仅同属一个领域不算关系
+2. 同一篇候选文章最多选择一种最贴切的关系
+3. 最多给出 10 条关系，没有合适的可以返回空数组
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "links": [
+    {"index": 1, "type": "mentions", "confidence": 0.8, "reason": "关系说明"}
+  ]
+}`
+
+const PromptCategoryEnrich = `你是一个 Web3 知识库的分类编辑。请根据分类下的文章，为这个分类写简介并选择图标。
+
+分类名称：%s
+上级分类：%s
+分类下的文章（标题：摘要）：
+%s
+
+要求：
+1. description：用中文一到两句话说明这个分类涵盖的内容，面向刚入门的读者，不要逐篇罗列文章
+2. icon：从以下图标中选择最贴切的一个：%s
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "description": "分类简介",
+  "icon": "图标名"
+}`
+
+const PromptSEOMetadata = `你是一个 Web3 技术内容的 SEO 编辑。请为下面的文章撰写搜索引擎和社交分享使用的元数据。
+
+文章标题：%s
+文章摘要：%s
+文章开头：
+%s
+
+要求：
+1. metaDescription：一到两句话概括文章内容，不超过 150 个字符，不要堆砌关键词
+2. ogTitle：适合社交分享的标题，不超过 60 个字符，可以与文章标题相同
+3. ogDescription：吸引读者点击的一句话介绍，不超过 150 个字符
+4. 使用与文章相同的语言，保留英文专业术语
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "metaDescription": "元描述",
+  "ogTitle": "分享标题",
+  "ogDescription": "分享描述"
+}`
+
+const PromptGlossaryTerms = `你是一个 Web3 术语表编辑。请从下面的文章中挑出初学者需要解释的专业术语，并为每个术语写一个简短定义。
+
+文章标题：%s
+文章内容：
+%s
+
+要求：
+1. 只选择 Web3、区块链、密码学领域的专业术语（如 MEV、零知识证明、Rollup），不要选择普通词汇
+2. nameZh 为中文名，nameEn 为英文全称；没有通用中文译名时 nameZh 可以为空
+3. aliases 列出常用缩写或其他写法，如 "ZKP"
+4. definition 用中文写一到两句话，让刚入行的程序员能看懂，不要照抄文章原句
+5. 最多 10 个术语，没有合适的可以返回空数组
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "terms": [
+    {"nameZh": "最大可提取价值", "nameEn": "Maximal Extractable Value", "aliases": ["MEV"], "definition": "简短定义"}
+  ]
+}`
+
 const PromptKnowledgeArticle = `你是一个 Web3 技术专家，正在为一位刚入职区块链公司的程序员撰写技术文档。
 
 要求：
@@ -63,6 +198,27 @@ const PromptKnowledgeArticle = `你是一个 Web3 技术专家，正在为一位
 
 请直接输出 markdown 格式的文章内容。`
 
+const PromptArticleJudge = `你是一个严格的 Web3 技术文档主编。以下是针对同一主题生成的 %d 篇候选文章，请选出最好的一篇。
+
+评判标准（按重要性排序）：
+1. 技术准确性：概念、机制和数据没有错误
+2. 深度与完整性：覆盖工作原理、技术细节、优势与局限、实际应用
+3. 可读性：结构清晰，适合刚入职区块链公司的程序员阅读
+4. 术语格式：英文术语 (中文翻译)，缩写首次出现时展开
+
+主题：%s
+
+%s
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "best": 1,
+  "scores": [8.5, 7.0],
+  "reason": "选择理由简述"
+}
+
+其中 best 为最佳候选的编号，scores 按候选编号顺序给出 0-10 分。`
+
 const PromptInstantResearch = `你是一个 Web3 技术研究助手。用户想了解一个技术概念，请提供全面且深入的解释。
 
 要求：
@@ -77,3 +233,160 @@ const PromptInstantResearch = `你是一个 Web3 技术研究助手。用户想
 %s
 
 请提供详细的解释。`
+
+const PromptSourceDiscovery = `你是一个 Web3 信息源研究员。用户希望持续关注一个主题，请根据搜索结果推荐高质量的信息源。
+
+主题：%s
+
+搜索结果：
+%s
+
+要求：
+1. 优先推荐有 RSS/Atom 订阅的博客、研究机构、项目官方博客和 Newsletter
+2. 也可以推荐值得关注的 X (Twitter) 账号
+3. 只推荐与主题高度相关、持续更新的来源，不要推荐聚合站或单篇文章
+4. 最多推荐 %d 个
+
+请返回以下 JSON 数组格式（不要包含 markdown 代码块标记）：
+[
+  {
+    "name": "来源名称",
+    "kind": "rss/blog/x 中的一个",
+    "url": "网站首页或 X 账号主页 URL",
+    "feedUrl": "RSS/Atom 地址（如已知，否则留空）",
+    "description": "一句话介绍",
+    "reason": "推荐理由"
+  }
+]`
+
+const PromptChatArticle = `你是一个 Web3 技术助手。用户正在阅读一篇关于「%s」的文章，并对内容有疑问。
+
+文章内容：
+%s
+
+请基于文章内容回答用户的问题。如果问题超出文章范围，可以补充相关知识。
+使用中文回答，保持专业术语的一致性。回答应该准确、清晰、有帮助。`
+
+const PromptChatArticleSources = `你是一个 Web3 技术助手。用户正在阅读一篇关于「%s」的文章，并对内容有疑问。
+
+以下是文章及相关文章中与问题最相关的片段，每个片段以 [编号] 开头：
+%s
+
+请基于这些片段回答用户的问题，并在引用片段内容的句子后用 [编号] 标注来源。如果片段不足以回答，可以补充相关知识，但不要为补充的内容标注来源。
+使用中文回答，保持专业术语的一致性。回答应该准确、清晰、有帮助。`
+
+const PromptChatCorpus = `你是一个 Web3 技术助手，基于知识库中的文章回答用户的问题。
+
+以下是知识库中与问题最相关的文章片段，每个片段以 [编号] 和文章标题开头：
+%s
+
+请基于这些片段回答用户的问题，并在引用片段内容的句子后用 [编号] 标注来源。如果片段之间有出入，请指出。如果片段不足以回答，请说明知识库中缺少哪些内容，可以补充相关知识，但不要为补充的内容标注来源。
+使用中文回答，保持专业术语的一致性。回答应该准确、清晰、有帮助。`
+
+const PromptChatFollowUps = `用户在 Web3 知识库中提出了一个问题，并得到了回答。请推荐 3 个用户接下来可能想问的问题。
+
+用户的问题：
+%s
+
+回答：
+%s
+
+要求：
+1. 问题应该深入或延伸回答中的内容，不要重复已经回答的问题
+2. 每个问题简短具体，不超过 30 个字
+3. 使用中文
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{"questions": ["问题一", "问题二", "问题三"]}`
+
+const PromptChatGeneral = `你是一个 Web3 技术助手，专门帮助用户理解区块链、加密货币、DeFi、NFT 等 Web3 相关技术。
+
+请用中文回答用户的问题，保持专业术语的一致性。回答应该：
+1. 准确、清晰、有帮助
+2. 使用通俗易懂的语言解释复杂概念
+3. 在适当的时候提供示例
+4. 如果涉及风险，请提醒用户注意`
+
+const PromptChatSelection = `关于「%s」这部分内容：%s`
+
+const PromptNewsArticleDecision = `你是一个 Web3 知识库的主编。知识库收录的是讲解技术概念和项目的长期有效的文章，而不是新闻快讯。请判断下面这条新闻应该如何处理。
+
+新闻标题：%s
+新闻摘要：%s
+
+知识库中可能相关的文章（编号. 标题：摘要）：
+%s
+
+可选处理方式：
+- create：新闻涉及知识库还没有讲解的重要概念、协议或项目，值得新写一篇文章
+- update：新闻是某篇已有文章所讲内容的重要进展（如协议升级、重大安全事件、机制变更），应补充进该文章
+- skip：价格波动、融资、人事变动、营销活动等短期信息，或者已有文章已经覆盖，不需要处理
+
+要求：
+1. 拿不准时选择 skip，宁缺毋滥
+2. 选择 update 时，index 为要更新的文章编号
+3. 选择 create 时，topic 为新文章的主题，应是一个概念或项目，而不是新闻标题
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "action": "create/update/skip 中的一个",
+  "index": 1,
+  "topic": "新文章主题（仅 create 时提供）",
+  "reason": "判断理由简述"
+}`
+
+const PromptNewsArticleUpdate = `你是一个 Web3 技术文档编辑。一篇知识库文章所讲的内容有了新进展，请为文章撰写一个补充章节。
+
+文章标题：%s
+文章内容（节选）：
+%s
+
+新闻标题：%s
+新闻内容：
+%s
+
+要求：
+1. 使用中文撰写，专业术语格式：英文术语 (中文翻译)
+2. 说明发生了什么变化，以及它对文章所讲内容的影响
+3. 不要重复文章中已有的内容，长度 200-600 字
+4. 不要输出章节标题，直接输出 markdown 正文`
+
+const PromptNewsTranslation = `你是一个 Web3 领域的专业译者。请将下面的英文新闻全文翻译为中文。%s
+
+要求：
+1. 完整翻译，不要总结、删减或添加内容
+2. 保留数字、公司名、项目名和代码原样
+3. 专业术语首次出现时使用「英文 (中文)」格式，如 "Layer 2 (二层网络)"
+4. 保留原文的段落划分和 markdown 格式
+5. 只输出译文，不要任何解释
+
+原文：
+%s`
+
+// PromptNewsTranslationTitle asks for the title, given as the first line of the first part
+const PromptNewsTranslationTitle = `
+原文第一行是标题，译文第一行也请以 "# " 开头输出译后的标题。`
+
+// PromptNewsTranslationPart marks a part of a news item too long to translate at once
+const PromptNewsTranslationPart = `
+这是全文的第 %d/%d 部分，请只翻译这一部分。`
+
+const PromptNewsRelevance = `你是一个 Web3 技术知识库的信息源编辑。知识库面向想了解区块链技术、协议、项目和行业动态的程序员。请为下面这条新抓取的内容打分，判断它是否值得收录。
+
+评分标准：
+- relevance（0-10）：与 Web3 技术、协议、项目、安全、监管或行业动态的相关程度
+- quality（0-10）：信息量和可信度。以下情况给低分：价格喊单、拉盘推广、空投引流、纯行情播报、营销软文、标题党、与 Web3 无关的内容、几乎没有正文
+- spam：是否为垃圾内容（喊单、推广、诈骗、引流）
+
+来源：%s
+标题：%s
+内容：
+%s
+
+请返回以下 JSON 格式（不要包含 markdown 代码块标记）：
+{
+  "relevance": 0-10 的整数,
+  "quality": 0-10 的整数,
+  "spam": true 或 false,
+  "reason": "一句话说明理由"
+}`
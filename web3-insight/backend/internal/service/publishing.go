@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// ScheduledPublisher publishes scheduled articles once their publish time arrives
+type ScheduledPublisher struct {
+	articleRepo *repository.ArticleRepository
+	hooks       *ArticleHooks
+}
+
+// NewScheduledPublisher creates a scheduled publisher; hooks may be nil
+func NewScheduledPublisher(articleRepo *repository.ArticleRepository, hooks *ArticleHooks) *ScheduledPublisher {
+	return &ScheduledPublisher{
+		articleRepo: articleRepo,
+		hooks:       hooks,
+	}
+}
+
+// PublishDue publishes the articles scheduled up to now and starts their classification and
+// embedding. Returns the number of articles published.
+func (p *ScheduledPublisher) PublishDue(now time.Time) (int, error) {
+	articles, err := p.articleRepo.PublishDue(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish scheduled articles: %w", err)
+	}
+	for i := range articles {
+		p.hooks.AfterPublish(&articles[i])
+	}
+	return len(articles), nil
+}
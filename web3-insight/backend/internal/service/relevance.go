@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/datatypes"
+)
+
+// DefaultRelevanceThreshold is the score below which news is filtered when no threshold is
+// configured
+const DefaultRelevanceThreshold = 0.3
+
+// relevanceContentChars is how much of an item's content is shown to the scoring model
+const relevanceContentChars = 1500
+
+// ErrNewsFiltered is returned when a news item scored below its source's relevance threshold
+var ErrNewsFiltered = errors.New("news item filtered for low relevance")
+
+// SourceRelevanceConfig is the "relevance" section of a data source config. Unset fields
+// fall back to the worker's relevance settings.
+type SourceRelevanceConfig struct {
+	Enabled   *bool    `json:"enabled,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty"`
+	Action    string   `json:"action,omitempty"` // quarantine or discard
+}
+
+// ParseRelevanceConfig reads the "relevance" section of a data source config
+func ParseRelevanceConfig(config datatypes.JSON) (*SourceRelevanceConfig, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+
+	var wrapper struct {
+		Relevance *SourceRelevanceConfig `json:"relevance"`
+	}
+	if err := json.Unmarshal(config, &wrapper); err != nil {
+		return nil, fmt.Errorf("invalid source config: %w", err)
+	}
+	rc := wrapper.Relevance
+	if rc == nil {
+		return nil, nil
+	}
+	if rc.Threshold != nil && (*rc.Threshold < 0 || *rc.Threshold > 1) {
+		return nil, fmt.Errorf("relevance threshold must be between 0 and 1")
+	}
+	if rc.Action != "" && relevanceAction(rc.Action) == "" {
+		return nil, fmt.Errorf("invalid relevance action %q, expected quarantine or discard", rc.Action)
+	}
+	return rc, nil
+}
+
+// relevanceAction maps a configured action to the NewsFiltered value it sets, or "" if unknown
+func relevanceAction(action string) string {
+	switch action {
+	case "quarantine":
+		return model.NewsFilteredQuarantine
+	case "discard":
+		return model.NewsFilteredDiscard
+	}
+	return ""
+}
+
+// RelevanceScorer scores incoming news for relevance and quality with the cheapest models
+// and filters out spam and off-topic posts
+type RelevanceScorer struct {
+	llmRouter *llm.Router
+	newsRepo  *repository.NewsRepository
+	dsRepo    *repository.DataSourceRepository
+	defaults  config.RelevanceConfig
+	usage     *UsageRecorder
+}
+
+// NewRelevanceScorer creates a new relevance scorer with the worker's default settings
+func NewRelevanceScorer(router *llm.Router, newsRepo *repository.NewsRepository, dsRepo *repository.DataSourceRepository, defaults config.RelevanceConfig) *RelevanceScorer {
+	return &RelevanceScorer{llmRouter: router, newsRepo: newsRepo, dsRepo: dsRepo, defaults: defaults}
+}
+
+// SetUsageRecorder enables persisting token usage of scoring calls
+func (s *RelevanceScorer) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// RelevanceResult represents the parsed LLM response
+type RelevanceResult struct {
+	Relevance int    `json:"relevance"`
+	Quality   int    `json:"quality"`
+	Spam      bool   `json:"spam"`
+	Reason    string `json:"reason"`
+}
+
+// Score combines relevance and quality into 0-1; spam scores 0
+func (r *RelevanceResult) Score() float64 {
+	if r.Spam {
+		return 0
+	}
+	score := math.Sqrt(float64(max(r.Relevance, 0)*max(r.Quality, 0))) / 10
+	return math.Round(math.Min(score, 1)*100) / 100
+}
+
+var relevanceSchema = llm.MustJSONSchema("news_relevance", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"relevance": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 10},
+		"quality":   map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 10},
+		"spam":      map[string]interface{}{"type": "boolean"},
+		"reason":    map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"relevance", "quality", "spam", "reason"},
+})
+
+// settings returns whether scoring is on for a source, its threshold and the NewsFiltered
+// value set on items below it
+func (s *RelevanceScorer) settings(sourceName string) (bool, float64, string) {
+	enabled, threshold, action := s.defaults.Enabled, s.defaults.Threshold, relevanceAction(s.defaults.Action)
+	if threshold <= 0 {
+		threshold = DefaultRelevanceThreshold
+	}
+	if action == "" {
+		action = model.NewsFilteredQuarantine
+	}
+
+	if source, err := s.dsRepo.FindByName(sourceName); err == nil {
+		if rc, err := ParseRelevanceConfig(source.Config); err == nil && rc != nil {
+			if rc.Enabled != nil {
+				enabled = *rc.Enabled
+			}
+			if rc.Threshold != nil {
+				threshold = *rc.Threshold
+			}
+			if rc.Action != "" {
+				action = relevanceAction(rc.Action)
+			}
+		}
+	}
+	// Discarding drops the content, which for what a user crawled or uploaded exists nowhere else
+	if userSubmitted(sourceName) && action == model.NewsFilteredDiscard {
+		action = model.NewsFilteredQuarantine
+	}
+	return enabled, threshold, action
+}
+
+// userSubmitted reports whether items of a source were crawled or uploaded by a user
+// rather than collected from a configured data source
+func userSubmitted(sourceName string) bool {
+	return sourceName == "manual" || sourceName == "upload"
+}
+
+// Check scores a news item that has not been scored yet and filters it when it scores below
+// its source's threshold, returning ErrNewsFiltered. Items of sources with scoring off pass
+// unscored; items scored before keep their outcome.
+func (s *RelevanceScorer) Check(ctx context.Context, item *model.NewsItem) error {
+	if item.RelevanceScore != nil {
+		if item.Filtered != "" {
+			return ErrNewsFiltered
+		}
+		return nil
+	}
+	enabled, threshold, action := s.settings(item.SourceName)
+	if !enabled {
+		return nil
+	}
+
+	title := item.OriginalTitle
+	if title == "" {
+		title = item.Title
+	}
+	content := item.Content
+	if content == "" {
+		content = item.Summary
+	}
+	prompt := fmt.Sprintf(PromptNewsRelevance, item.SourceName, title, truncateString(content, relevanceContentChars))
+
+	startedAt := time.Now()
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskRelevance, prompt, relevanceSchema, &llm.GenerateOptions{
+		Temperature: 0.1,
+		MaxTokens:   300,
+		Context:     ctx,
+	})
+	s.usage.Record(model.TaskTypeRelevance, map[string]interface{}{"newsId": item.ID}, startedAt, generated, err)
+	if err != nil {
+		return fmt.Errorf("relevance scoring failed: %w", err)
+	}
+
+	result := &RelevanceResult{}
+	if err := json.Unmarshal([]byte(generated.Content), result); err != nil {
+		return fmt.Errorf("failed to parse relevance score: %w", err)
+	}
+
+	score, reason := result.Score(), truncateString(result.Reason, 400)
+	filtered := ""
+	if score < threshold {
+		filtered = action
+	}
+	if err := s.newsRepo.SetRelevance(item.ID, score, reason, filtered); err != nil {
+		return fmt.Errorf("failed to save relevance score: %w", err)
+	}
+	item.RelevanceScore, item.RelevanceReason, item.Filtered = &score, reason, filtered
+	if filtered != "" {
+		return ErrNewsFiltered
+	}
+	return nil
+}
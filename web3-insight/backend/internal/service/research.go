@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/user/web3-insight/internal/collector"
 	"github.com/user/web3-insight/internal/llm"
 	"github.com/user/web3-insight/internal/model"
@@ -20,6 +22,10 @@ type ResearchService struct {
 	articleRepo  *repository.ArticleRepository
 	searchRouter *collector.SearchRouter
 	generator    *Generator
+	sessionRepo  *repository.ResearchSessionRepository
+	usage        *UsageRecorder
+	hooks        *ArticleHooks
+	prompts      *PromptStore
 }
 
 // NewResearchService creates a new research service
@@ -28,20 +34,39 @@ func NewResearchService(
 	articleRepo *repository.ArticleRepository,
 	searchRouter *collector.SearchRouter,
 	generator *Generator,
+	sessionRepo *repository.ResearchSessionRepository,
 ) *ResearchService {
 	return &ResearchService{
 		llmRouter:    router,
 		articleRepo:  articleRepo,
 		searchRouter: searchRouter,
 		generator:    generator,
+		sessionRepo:  sessionRepo,
 	}
 }
 
+// SetUsageRecorder enables persisting token usage of research calls
+func (s *ResearchService) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// SetArticleHooks enables enqueueing classification and embedding for saved research articles
+func (s *ResearchService) SetArticleHooks(hooks *ArticleHooks) {
+	s.hooks = hooks
+}
+
+// SetPromptStore sets the persona prompts used for research
+func (s *ResearchService) SetPromptStore(prompts *PromptStore) {
+	s.prompts = prompts
+}
+
 // ResearchRequest represents an instant research request
 type ResearchRequest struct {
-	Query        string
-	SaveArticle  bool // Whether to save the result as an article
-	UseWebSearch bool // Whether to use web search for additional context
+	Query        string     `json:"query"`
+	SaveArticle  bool       `json:"saveArticle"`            // Whether to save the result as an article
+	UseWebSearch bool       `json:"useWebSearch"`           // Whether to use web search for additional context
+	CitationMode string     `json:"citationMode,omitempty"` // Citation verification: "flag" (default), "strip", "off"
+	ScheduleID   *uuid.UUID `json:"-"`                      // Set when triggered by a research schedule
 }
 
 // ResearchResponse represents the research result
@@ -51,14 +76,88 @@ type ResearchResponse struct {
 	Sources         []string
 	RelatedArticles []model.Article
 	SavedArticleID  *string
+	Citations       *CitationReport
+	SessionID       *uuid.UUID
 	Duration        time.Duration
 }
 
-// Research performs instant research on a topic
+// Research performs instant research on a topic and records it as a research session
 func (s *ResearchService) Research(ctx context.Context, req *ResearchRequest) (*ResearchResponse, error) {
+	return s.research(ctx, req, nil)
+}
+
+// RerunSession re-executes a past research session with its original parameters
+func (s *ResearchService) RerunSession(ctx context.Context, sessionID uuid.UUID) (*ResearchResponse, error) {
+	if s.sessionRepo == nil {
+		return nil, fmt.Errorf("research sessions not available")
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("research session not found: %w", err)
+	}
+
+	var req ResearchRequest
+	if len(session.Parameters) > 0 {
+		if err := json.Unmarshal(session.Parameters, &req); err != nil {
+			return nil, fmt.Errorf("invalid session parameters: %w", err)
+		}
+	}
+	req.Query = session.Query
+
+	return s.research(ctx, &req, &session.ID)
+}
+
+// RunSchedule executes a scheduled research query and, if the schedule targets an
+// article, proposes the result as a dated update section for a reviewer to apply
+func (s *ResearchService) RunSchedule(ctx context.Context, schedule *model.ResearchSchedule) (*ResearchResponse, error) {
+	resp, err := s.Research(ctx, &ResearchRequest{
+		Query:        schedule.Query,
+		UseWebSearch: schedule.UseWebSearch,
+		CitationMode: schedule.CitationMode,
+		ScheduleID:   &schedule.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.TargetArticleID != nil {
+		if err := s.proposeUpdateSection(schedule, resp); err != nil {
+			return resp, fmt.Errorf("failed to propose an update to the target article: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// proposeUpdateSection proposes a dated research update to a schedule's target article as a
+// revision. Like the news pipeline's changes, it is appended only once a reviewer applies it.
+func (s *ResearchService) proposeUpdateSection(schedule *model.ResearchSchedule, resp *ResearchResponse) error {
+	if _, err := s.articleRepo.GetByID(*schedule.TargetArticleID); err != nil {
+		return err
+	}
+
+	section := fmt.Sprintf("## 研究更新（%s）\n\n%s", displayDate(time.Now()), strings.TrimSpace(resp.Content))
+	return s.articleRepo.CreateRevision(&model.ArticleRevision{
+		ArticleID:  *schedule.TargetArticleID,
+		ScheduleID: &schedule.ID,
+		Section:    section,
+		SourceURLs: resp.Sources,
+		ModelUsed:  resp.ModelUsed,
+		Status:     model.RevisionStatusPending,
+	})
+}
+
+// research runs the research pipeline, persisting the run when a session repository is configured
+func (s *ResearchService) research(ctx context.Context, req *ResearchRequest, rerunOf *uuid.UUID) (*ResearchResponse, error) {
 	startTime := time.Now()
 	response := &ResearchResponse{}
 
+	session := s.startSession(req, rerunOf)
+	if session != nil {
+		response.SessionID = &session.ID
+	}
+
 	// 1. Check for existing related articles
 	related, err := s.findRelatedArticles(req.Query)
 	if err == nil && len(related) > 0 {
@@ -67,12 +166,14 @@ func (s *ResearchService) Research(ctx context.Context, req *ResearchRequest) (*
 
 	// 2. Optionally gather web search results
 	var webContext string
+	retrieved := make(map[string]string)
 	if req.UseWebSearch && s.searchRouter != nil {
 		searchResult, err := s.searchRouter.Search(ctx, req.Query+" Web3 blockchain", 5)
 		if err == nil && searchResult != nil {
 			webContext = s.formatSearchResults(searchResult)
 			for _, r := range searchResult.Results {
 				response.Sources = append(response.Sources, r.URL)
+				retrieved[r.URL] = r.Content
 			}
 		}
 	}
@@ -97,15 +198,26 @@ func (s *ResearchService) Research(ctx context.Context, req *ResearchRequest) (*
 		contextStr = "（无额外上下文，请基于通用知识回答）"
 	}
 
-	prompt := fmt.Sprintf(PromptInstantResearch, req.Query, contextStr)
+	prompt := fmt.Sprintf(s.prompts.Get(PromptNameInstantResearch), req.Query, contextStr)
 
-	content, modelUsed, err := s.llmRouter.Generate(llm.TaskContentGeneration, prompt, &llm.GenerateOptions{
+	generateStart := time.Now()
+	generated, err := s.llmRouter.Generate(llm.TaskContentGeneration, prompt, &llm.GenerateOptions{
 		Temperature: 0.7,
 		MaxTokens:   4000,
 	})
+	s.usage.Record(model.TaskTypeResearch, map[string]interface{}{"query": req.Query}, generateStart, generated, err)
 	if err != nil {
+		s.failSession(session, startTime, err)
 		return nil, fmt.Errorf("research generation failed: %w", err)
 	}
+	content, modelUsed := generated.Content, generated.Model
+
+	// Verify cited URLs against the sources we actually retrieved
+	content, response.Citations = NewCitationVerifier(retrieved).Verify(content, req.CitationMode)
+	if response.Citations.Unverified > 0 {
+		log.Printf("Research citations: %d verified, %d unverified (mode: %s)",
+			response.Citations.Verified, response.Citations.Unverified, response.Citations.Mode)
+	}
 
 	response.Content = content
 	response.ModelUsed = modelUsed
@@ -122,9 +234,78 @@ func (s *ResearchService) Research(ctx context.Context, req *ResearchRequest) (*
 		}
 	}
 
+	s.completeSession(session, response, generated)
+
 	return response, nil
 }
 
+// startSession records a new running research session
+func (s *ResearchService) startSession(req *ResearchRequest, rerunOf *uuid.UUID) *model.ResearchSession {
+	if s.sessionRepo == nil {
+		return nil
+	}
+
+	params, _ := json.Marshal(req)
+	session := &model.ResearchSession{
+		Query:      req.Query,
+		Parameters: params,
+		Status:     model.ResearchStatusRunning,
+		RerunOfID:  rerunOf,
+		ScheduleID: req.ScheduleID,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		log.Printf("Failed to create research session: %v", err)
+		return nil
+	}
+	return session
+}
+
+// completeSession stores the research output, sources and cost on the session
+func (s *ResearchService) completeSession(session *model.ResearchSession, response *ResearchResponse, generated *llm.GenerateResult) {
+	if session == nil {
+		return
+	}
+
+	now := time.Now()
+	session.Status = model.ResearchStatusCompleted
+	session.Sources = response.Sources
+	session.Output = response.Content
+	session.ModelUsed = response.ModelUsed
+	session.TokensUsed = generated.Usage.TotalTokens()
+	session.CostUSD = decimal.NewFromFloat(generated.CostUSD)
+	session.DurationMs = response.Duration.Milliseconds()
+	session.CompletedAt = &now
+	if response.Citations != nil {
+		session.Citations, _ = json.Marshal(response.Citations)
+	}
+	if response.SavedArticleID != nil {
+		if id, err := uuid.Parse(*response.SavedArticleID); err == nil {
+			session.ArticleID = &id
+		}
+	}
+
+	if err := s.sessionRepo.Update(session); err != nil {
+		log.Printf("Failed to update research session %s: %v", session.ID, err)
+	}
+}
+
+// failSession marks a research session as failed
+func (s *ResearchService) failSession(session *model.ResearchSession, startTime time.Time, cause error) {
+	if session == nil {
+		return
+	}
+
+	now := time.Now()
+	session.Status = model.ResearchStatusFailed
+	session.Error = cause.Error()
+	session.DurationMs = time.Since(startTime).Milliseconds()
+	session.CompletedAt = &now
+
+	if err := s.sessionRepo.Update(session); err != nil {
+		log.Printf("Failed to update research session %s: %v", session.ID, err)
+	}
+}
+
 // ResearchStream performs research with streaming output
 func (s *ResearchService) ResearchStream(ctx context.Context, req *ResearchRequest) (<-chan llm.StreamChunk, string, error) {
 	// Gather context (simplified for streaming)
@@ -141,11 +322,12 @@ func (s *ResearchService) ResearchStream(ctx context.Context, req *ResearchReque
 		contextStr = "（无额外上下文，请基于通用知识回答）"
 	}
 
-	prompt := fmt.Sprintf(PromptInstantResearch, req.Query, contextStr)
+	prompt := fmt.Sprintf(s.prompts.Get(PromptNameInstantResearch), req.Query, contextStr)
 
 	return s.llmRouter.GenerateStream(llm.TaskContentGeneration, prompt, &llm.GenerateOptions{
 		Temperature: 0.7,
 		MaxTokens:   4000,
+		Context:     ctx,
 	})
 }
 
@@ -170,7 +352,7 @@ func (s *ResearchService) formatSearchResults(results *collector.SearchResponse)
 		if i >= 5 {
 			break
 		}
-		sb.WriteString(fmt.Sprintf("- %s\n  %s\n", r.Title, r.Content))
+		sb.WriteString(fmt.Sprintf("- %s (%s)\n  %s\n", r.Title, r.URL, r.Content))
 	}
 
 	return sb.String()
@@ -213,7 +395,7 @@ func (s *ResearchService) saveAsArticle(ctx context.Context, query, content stri
 		Slug:       s.generator.generateSlug(query),
 		Content:    content,
 		Summary:    s.generator.extractSummary(content),
-		Status:     "published",
+		Status:     model.ArticleStatusInReview, // Published once an editor approves it
 		SourceURLs: sources,
 		Tags:       s.generator.extractTags(content, query),
 	}
@@ -221,6 +403,17 @@ func (s *ResearchService) saveAsArticle(ctx context.Context, query, content stri
 	if err := s.articleRepo.Create(article); err != nil {
 		return nil, err
 	}
+	s.hooks.AfterCreate(article)
 
 	return &article.ID, nil
 }
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
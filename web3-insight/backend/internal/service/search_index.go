@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/search"
+	"gorm.io/gorm"
+)
+
+// Sync cursors are kept in the config table so every worker replica resumes from the same point
+const (
+	searchArticleCursorKey = "search_index.article_cursor" // Last synced content change ID
+	searchSyncBatchSize    = 200
+)
+
+// SearchSyncResult counts the documents one sync wrote to the search engine
+type SearchSyncResult struct {
+	ArticlesIndexed int `json:"articlesIndexed"`
+	ArticlesDeleted int `json:"articlesDeleted"`
+}
+
+// SearchIndexer keeps an external search engine in sync with articles and runs keyword
+// searches on it. Articles follow the content change log, so creates, updates and deletes
+// from any code path are picked up.
+type SearchIndexer struct {
+	engine      search.Engine
+	articleRepo *repository.ArticleRepository
+	changeRepo  *repository.ChangeRepository
+	configRepo  *repository.ConfigRepository
+
+	mu    sync.Mutex
+	ready bool // Indexes have been created
+}
+
+// NewSearchIndexer creates a search indexer for an engine
+func NewSearchIndexer(engine search.Engine, articleRepo *repository.ArticleRepository, changeRepo *repository.ChangeRepository, configRepo *repository.ConfigRepository) *SearchIndexer {
+	return &SearchIndexer{
+		engine:      engine,
+		articleRepo: articleRepo,
+		changeRepo:  changeRepo,
+		configRepo:  configRepo,
+	}
+}
+
+// NewSearchIndexerFromConfig creates a search indexer for the configured engine, or returns nil
+// if no engine is configured
+func NewSearchIndexerFromConfig(cfg *config.SearchEngineConfig, db *gorm.DB) *SearchIndexer {
+	engine, err := search.NewEngineFromConfig(cfg)
+	if err != nil {
+		log.Printf("Search engine %q not supported, searching the database: %v", cfg.Provider, err)
+		return nil
+	}
+	if engine == nil {
+		return nil
+	}
+	return NewSearchIndexer(engine,
+		repository.NewArticleRepository(db),
+		repository.NewChangeRepository(db),
+		repository.NewConfigRepository(db))
+}
+
+// Engine returns the name of the search engine
+func (s *SearchIndexer) Engine() string {
+	return s.engine.Name()
+}
+
+// SearchArticles runs a keyword search on the engine and returns the matching articles,
+// best match first, optionally restricted to the given difficulty levels
+func (s *SearchIndexer) SearchArticles(ctx context.Context, query string, limit int, difficulties []string) ([]model.Article, error) {
+	q := search.Query{Text: query, Limit: limit}
+	if len(difficulties) > 0 {
+		q.Filters = map[string][]string{search.FieldDifficulty: difficulties}
+	}
+	hits, err := s.engine.Search(ctx, search.IndexArticles, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(hits))
+	for _, hit := range hits {
+		if id, err := uuid.Parse(hit); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	found, err := s.articleRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the engine's ranking; hits deleted since the last sync are dropped
+	byID := make(map[uuid.UUID]model.Article, len(found))
+	for _, article := range found {
+		byID[article.ID] = article
+	}
+	articles := make([]model.Article, 0, len(found))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+// Sync writes articles changed since the last sync to the engine. The first sync indexes
+// every article.
+func (s *SearchIndexer) Sync(ctx context.Context) (*SearchSyncResult, error) {
+	if err := s.ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	result := &SearchSyncResult{}
+	if err := s.syncArticles(ctx, result); err != nil {
+		return result, fmt.Errorf("article sync failed: %w", err)
+	}
+	return result, nil
+}
+
+// ensureIndexes creates the indexes once per process
+func (s *SearchIndexer) ensureIndexes(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready {
+		return nil
+	}
+	if err := s.engine.EnsureIndex(ctx, search.IndexArticles); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", search.IndexArticles, err)
+	}
+	s.ready = true
+	return nil
+}
+
+// syncArticles applies the change log after the saved cursor
+func (s *SearchIndexer) syncArticles(ctx context.Context, result *SearchSyncResult) error {
+	raw, err := s.loadCursor(searchArticleCursorKey)
+	if err != nil {
+		return err
+	}
+	if raw == "" {
+		return s.indexAllArticles(ctx, result)
+	}
+	var cursor uint64
+	if _, err := fmt.Sscan(raw, &cursor); err != nil {
+		return fmt.Errorf("invalid article cursor %q: %w", raw, err)
+	}
+
+	for ctx.Err() == nil {
+		page, err := s.changeRepo.List(repository.ChangeListParams{
+			After:      cursor,
+			EntityType: model.ChangeEntityArticle,
+			Limit:      searchSyncBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(page.Changes) == 0 {
+			return nil
+		}
+
+		var changed []uuid.UUID
+		var deleted []string
+		for _, change := range page.Changes {
+			if change.Action == model.ChangeActionDeleted {
+				deleted = append(deleted, change.EntityID.String())
+			} else {
+				changed = append(changed, change.EntityID)
+			}
+		}
+		articles, err := s.articleRepo.FindByIDs(changed)
+		if err != nil {
+			return err
+		}
+		// Articles deleted after their last logged update are removed too
+		exists := make(map[uuid.UUID]bool, len(articles))
+		for _, article := range articles {
+			exists[article.ID] = true
+		}
+		for _, id := range changed {
+			if !exists[id] {
+				deleted = append(deleted, id.String())
+			}
+		}
+
+		if err := s.engine.Upsert(ctx, search.IndexArticles, articleDocuments(articles)); err != nil {
+			return err
+		}
+		if err := s.engine.Delete(ctx, search.IndexArticles, deleted); err != nil {
+			return err
+		}
+		result.ArticlesIndexed += len(articles)
+		result.ArticlesDeleted += len(deleted)
+
+		cursor = page.Cursor
+		if err := s.saveCursor(searchArticleCursorKey, fmt.Sprint(cursor)); err != nil {
+			return err
+		}
+		if !page.HasMore {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// indexAllArticles indexes every article, then starts following the change log from the
+// point the scan began, so changes made during the scan are applied on the next sync
+func (s *SearchIndexer) indexAllArticles(ctx context.Context, result *SearchSyncResult) error {
+	start, err := s.changeRepo.LatestID()
+	if err != nil {
+		return err
+	}
+
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		articles, err := s.articleRepo.ListAfterID(after, searchSyncBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		if err := s.engine.Upsert(ctx, search.IndexArticles, articleDocuments(articles)); err != nil {
+			return err
+		}
+		result.ArticlesIndexed += len(articles)
+		after = articles[len(articles)-1].ID
+	}
+	return s.saveCursor(searchArticleCursorKey, fmt.Sprint(start))
+}
+
+// loadCursor returns a saved cursor, or "" if there is none
+func (s *SearchIndexer) loadCursor(key string) (string, error) {
+	cfg, err := s.configRepo.Get(key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var value string
+	if err := json.Unmarshal(cfg.Value, &value); err != nil {
+		return "", fmt.Errorf("invalid cursor %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// saveCursor stores a cursor
+func (s *SearchIndexer) saveCursor(key, value string) error {
+	return s.configRepo.Set(key, value, "Search engine sync position (internal)")
+}
+
+// articleDocuments converts articles to search documents
+func articleDocuments(articles []model.Article) []search.Document {
+	docs := make([]search.Document, len(articles))
+	for i, article := range articles {
+		docs[i] = search.Document{
+			ID:          article.ID.String(),
+			Title:       article.Title,
+			Summary:     article.Summary,
+			Content:     article.Content,
+			Tags:        article.Tags,
+			Status:      article.Status,
+			Difficulty:  article.Difficulty,
+			PublishedAt: article.CreatedAt.Unix(),
+		}
+	}
+	return docs
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/config"
@@ -13,10 +14,15 @@ import (
 
 // SemanticSearchService handles semantic search operations
 type SemanticSearchService struct {
-	articleRepo *repository.ArticleRepository
-	adapter     llm.EmbeddingAdapter
+	articleRepo      *repository.ArticleRepository
+	adapter          llm.EmbeddingAdapter
+	reranker         llm.Reranker // Optional; nil keeps cosine ordering
+	rerankCandidates int
 }
 
+// rerankDocumentChars caps the article text sent to the reranker per candidate
+const rerankDocumentChars = 1500
+
 // SearchResult represents a semantic search result
 type SearchResult struct {
 	Article model.Article `json:"article"`
@@ -25,19 +31,26 @@ type SearchResult struct {
 
 // SearchRequest represents a semantic search request
 type SearchRequest struct {
-	Query      string     `json:"query"`
-	CategoryID *uuid.UUID `json:"categoryId,omitempty"`
-	Status     string     `json:"status,omitempty"`
-	Limit      int        `json:"limit,omitempty"`
+	Query        string     `json:"query"`
+	CategoryID   *uuid.UUID `json:"categoryId,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	Difficulties []string   `json:"difficulties,omitempty"`
+	Limit        int        `json:"limit,omitempty"`
 }
 
 // NewSemanticSearchService creates a new semantic search service
 func NewSemanticSearchService(articleRepo *repository.ArticleRepository, cfg *config.LLMConfig) *SemanticSearchService {
-	adapter := llm.DefaultOllamaEmbeddingAdapter(cfg.OllamaHost)
+	adapter := llm.NewEmbeddingAdapterFromConfig(cfg)
 
+	candidates := cfg.Rerank.Candidates
+	if candidates <= 0 {
+		candidates = llm.DefaultRerankCandidates
+	}
 	return &SemanticSearchService{
-		articleRepo: articleRepo,
-		adapter:     adapter,
+		articleRepo:      articleRepo,
+		adapter:          adapter,
+		reranker:         llm.NewRerankerFromConfig(cfg),
+		rerankCandidates: candidates,
 	}
 }
 
@@ -59,15 +72,66 @@ func (s *SemanticSearchService) Search(ctx context.Context, req SearchRequest) (
 
 	vec := llm.Float32ToVector(embedding)
 
+	// Fetch a wider candidate pool when a reranker will pick the top results
+	fetch := req.Limit
+	if s.reranker != nil && s.rerankCandidates > fetch {
+		fetch = s.rerankCandidates
+	}
+
 	// Perform semantic search
-	articles, err := s.articleRepo.SemanticSearch(vec, req.Limit, req.CategoryID, req.Status)
+	articles, err := s.articleRepo.SemanticSearch(vec, fetch, req.CategoryID, req.Status, req.Difficulties)
 	if err != nil {
 		return nil, fmt.Errorf("semantic search failed: %w", err)
 	}
 
+	if s.reranker != nil && len(articles) > 1 {
+		articles = s.rerank(req.Query, articles)
+	}
+	if len(articles) > req.Limit {
+		articles = articles[:req.Limit]
+	}
 	return articles, nil
 }
 
+// rerank reorders articles by reranker relevance, keeping vector order if reranking fails
+func (s *SemanticSearchService) rerank(query string, articles []model.Article) []model.Article {
+	documents := make([]string, len(articles))
+	for i, a := range articles {
+		documents[i] = rerankDocument(&a)
+	}
+
+	results, err := s.reranker.Rerank(query, documents)
+	if err != nil {
+		log.Printf("Rerank with %s failed, using vector order: %v", s.reranker.Name(), err)
+		return articles
+	}
+
+	reranked := make([]model.Article, 0, len(articles))
+	seen := make(map[int]bool, len(results))
+	for _, r := range results {
+		if !seen[r.Index] {
+			seen[r.Index] = true
+			reranked = append(reranked, articles[r.Index])
+		}
+	}
+	// Keep any candidates the reranker didn't return, in vector order
+	for i, a := range articles {
+		if !seen[i] {
+			reranked = append(reranked, a)
+		}
+	}
+	return reranked
+}
+
+// rerankDocument is the text of an article judged by the reranker: title, summary and the start of the content
+func rerankDocument(article *model.Article) string {
+	text := article.Title + "\n" + article.Summary + "\n" + article.Content
+	if runes := []rune(text); len(runes) > rerankDocumentChars {
+		text = string(runes[:rerankDocumentChars])
+	}
+	return text
+}
+
 // GetRelatedArticles finds articles related to a given article
 func (s *SemanticSearchService) GetRelatedArticles(ctx context.Context, articleID uuid.UUID, limit int) ([]model.Article, error) {
 	if limit <= 0 {
@@ -82,8 +146,8 @@ func (s *SemanticSearchService) GetRelatedArticles(ctx context.Context, articleI
 	return articles, nil
 }
 
-// HybridSearch combines semantic search with keyword search
-func (s *SemanticSearchService) HybridSearch(ctx context.Context, query string, limit int, categoryID *uuid.UUID) ([]model.Article, error) {
+// HybridSearch combines semantic search with keyword search, optionally restricted to difficulty levels
+func (s *SemanticSearchService) HybridSearch(ctx context.Context, query string, limit int, categoryID *uuid.UUID, difficulties []string) ([]model.Article, error) {
 	if query == "" {
 		return nil, fmt.Errorf("query cannot be empty")
 	}
@@ -94,18 +158,19 @@ func (s *SemanticSearchService) HybridSearch(ctx context.Context, query string,
 
 	// Perform semantic search
 	semanticResults, err := s.Search(ctx, SearchRequest{
-		Query:      query,
-		CategoryID: categoryID,
-		Limit:      limit,
+		Query:        query,
+		CategoryID:   categoryID,
+		Difficulties: difficulties,
+		Limit:        limit,
 	})
 	if err != nil {
 		// Fall back to keyword search if semantic search fails
-		return s.articleRepo.Search(query, limit)
+		return s.articleRepo.Search(query, limit, difficulties...)
 	}
 
 	// If semantic search returns few results, supplement with keyword search
 	if len(semanticResults) < limit {
-		keywordResults, _ := s.articleRepo.Search(query, limit-len(semanticResults))
+		keywordResults, _ := s.articleRepo.Search(query, limit-len(semanticResults), difficulties...)
 
 		// Deduplicate results
 		resultMap := make(map[uuid.UUID]model.Article)
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Lengths of generated SEO metadata, in runes
+const (
+	maxOGTitleRunes   = 60
+	seoContentExcerpt = 1500
+)
+
+// seoSchema is the JSON schema generated SEO metadata must match
+var seoSchema = llm.MustJSONSchema("seo_metadata", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"metaDescription": map[string]interface{}{"type": "string"},
+		"ogTitle":         map[string]interface{}{"type": "string"},
+		"ogDescription":   map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"metaDescription", "ogTitle", "ogDescription"},
+})
+
+// SEOService writes article meta descriptions and OpenGraph fields with the summarization model
+type SEOService struct {
+	llmRouter   *llm.Router
+	articleRepo *repository.ArticleRepository
+	usage       *UsageRecorder
+}
+
+// NewSEOService creates a new SEO service
+func NewSEOService(router *llm.Router, articleRepo *repository.ArticleRepository) *SEOService {
+	return &SEOService{
+		llmRouter:   router,
+		articleRepo: articleRepo,
+	}
+}
+
+// SetUsageRecorder enables persisting token usage of SEO metadata generation
+func (s *SEOService) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// GenerateAndUpdate asks the summarization model for an article's meta description and
+// OpenGraph title and description, and stores them as LLM-sourced so later content edits
+// keep them. The OpenGraph image stays the first image of the content.
+func (s *SEOService) GenerateAndUpdate(ctx context.Context, articleID uuid.UUID) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("article not found: %w", err)
+	}
+
+	prompt := fmt.Sprintf(PromptSEOMetadata, article.Title, article.Summary, truncateString(article.Content, seoContentExcerpt))
+
+	startedAt := time.Now()
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskSummarization, prompt, seoSchema, &llm.GenerateOptions{
+		Temperature: 0.3,
+		MaxTokens:   500,
+		Context:     ctx,
+	})
+	s.usage.Record(model.TaskTypeSEOMetadata, map[string]interface{}{"articleId": article.ID}, startedAt, generated, err)
+	if err != nil {
+		return nil, fmt.Errorf("LLM SEO metadata generation failed: %w", err)
+	}
+
+	var result struct {
+		MetaDescription string `json:"metaDescription"`
+		OGTitle         string `json:"ogTitle"`
+		OGDescription   string `json:"ogDescription"`
+	}
+	if err := json.Unmarshal([]byte(generated.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse SEO metadata: %w", err)
+	}
+	if strings.TrimSpace(result.MetaDescription) == "" {
+		return nil, fmt.Errorf("LLM returned an empty meta description")
+	}
+
+	article.MetaDescription = markdown.Truncate(result.MetaDescription, markdown.MaxDescriptionRunes)
+	article.OGTitle = markdown.Truncate(result.OGTitle, maxOGTitleRunes)
+	if article.OGTitle == "" {
+		article.OGTitle = article.Title
+	}
+	article.OGDescription = markdown.Truncate(result.OGDescription, markdown.MaxDescriptionRunes)
+	if article.OGDescription == "" {
+		article.OGDescription = article.MetaDescription
+	}
+	article.OGImage = markdown.FirstImage(article.Content)
+	article.SEOSource = model.SEOSourceLLM
+
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, fmt.Errorf("failed to save SEO metadata: %w", err)
+	}
+	return article, nil
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/markdown"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"go.yaml.in/yaml/v3"
+)
+
+// uncategorizedSection holds published articles without a category
+const uncategorizedSection = "uncategorized"
+
+// SiteExporter builds a Hugo site content directory from published articles: one section per
+// category, a page per article, tag pages and a manifest of the images articles refer to
+type SiteExporter struct {
+	articleRepo  *repository.ArticleRepository
+	categoryRepo *repository.CategoryRepository
+	baseURL      string
+	title        string
+}
+
+// NewSiteExporter creates a new static site exporter. baseURL is the URL the site is published at.
+func NewSiteExporter(articleRepo *repository.ArticleRepository, categoryRepo *repository.CategoryRepository, baseURL, title string) *SiteExporter {
+	return &SiteExporter{
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		baseURL:      strings.TrimRight(baseURL, "/") + "/",
+		title:        title,
+	}
+}
+
+// SiteFile is a file of an exported site, with a slash-separated path relative to the site root
+type SiteFile struct {
+	Path string
+	Data []byte
+}
+
+// SiteAsset is an image referenced by exported articles, to be downloaded into the site if wanted
+type SiteAsset struct {
+	URL   string   `json:"url"`
+	Pages []string `json:"pages"` // Content files referring to the image
+}
+
+// SiteManifest describes an exported site; it is written to assets.json in the bundle
+type SiteManifest struct {
+	GeneratedAt time.Time   `json:"generatedAt"`
+	Articles    int         `json:"articles"`
+	Sections    int         `json:"sections"`
+	Tags        int         `json:"tags"`
+	Assets      []SiteAsset `json:"assets"`
+}
+
+// SiteBundle is an exported site
+type SiteBundle struct {
+	Files    []SiteFile
+	Manifest SiteManifest
+}
+
+// hugoFrontmatter is the YAML frontmatter of an article page
+type hugoFrontmatter struct {
+	Title       string         `yaml:"title"`
+	Slug        string         `yaml:"slug,omitempty"`
+	Date        time.Time      `yaml:"date"`
+	Lastmod     time.Time      `yaml:"lastmod"`
+	Description string         `yaml:"description,omitempty"`
+	Summary     string         `yaml:"summary,omitempty"`
+	Tags        []string       `yaml:"tags,omitempty"`
+	Categories  []string       `yaml:"categories,omitempty"`
+	Params      map[string]any `yaml:"params,omitempty"`
+}
+
+// hugoSection is the YAML frontmatter of a section or taxonomy term page
+type hugoSection struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+	Weight      int    `yaml:"weight,omitempty"`
+}
+
+// Build exports the published articles, only those in the category's subtree if categoryID is set
+func (s *SiteExporter) Build(categoryID *uuid.UUID) (*SiteBundle, error) {
+	var categoryIDs []uuid.UUID
+	if categoryID != nil {
+		ids, err := s.categoryRepo.SubtreeIDs(*categoryID)
+		if err != nil {
+			return nil, err
+		}
+		categoryIDs = ids
+	}
+	articles, err := s.articleRepo.ListPublished(categoryIDs, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load articles: %w", err)
+	}
+	all, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load categories: %w", err)
+	}
+	categories := make(map[uuid.UUID]model.Category, len(all))
+	for _, c := range all {
+		categories[c.ID] = c
+	}
+
+	bundle := &SiteBundle{Manifest: SiteManifest{GeneratedAt: time.Now().UTC()}}
+	add := func(name string, frontmatter any, body string) error {
+		data, err := pageFile(frontmatter, body)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		bundle.Files = append(bundle.Files, SiteFile{Path: name, Data: data})
+		return nil
+	}
+
+	if err := add("content/_index.md", hugoSection{Title: s.title}, ""); err != nil {
+		return nil, err
+	}
+
+	sections := make(map[string]bool)
+	tags := make(map[string]string) // Term directory -> tag name
+	assets := make(map[string][]string)
+	var assetOrder []string
+	for i := range articles {
+		article := &articles[i]
+
+		section := uncategorizedSection
+		var categoryNames []string
+		if article.CategoryID != nil {
+			if chain := categoryChain(categories, *article.CategoryID); len(chain) > 0 {
+				slugs := make([]string, len(chain))
+				for j, c := range chain {
+					slugs[j] = c.Slug
+					dir := "content/" + strings.Join(slugs[:j+1], "/")
+					if !sections[dir] {
+						sections[dir] = true
+						if err := add(dir+"/_index.md", hugoSection{Title: c.Name, Description: c.Description, Weight: c.SortOrder}, ""); err != nil {
+							return nil, err
+						}
+					}
+				}
+				section = strings.Join(slugs, "/")
+				categoryNames = []string{chain[len(chain)-1].Name}
+			}
+		}
+		if section == uncategorizedSection && !sections["content/"+section] {
+			sections["content/"+section] = true
+			if err := add("content/"+section+"/_index.md", hugoSection{Title: "未分类"}, ""); err != nil {
+				return nil, err
+			}
+		}
+
+		name := "content/" + section + "/" + article.Slug + ".md"
+		if err := add(name, s.articleFrontmatter(article, categoryNames), article.Content); err != nil {
+			return nil, err
+		}
+
+		for _, tag := range article.Tags {
+			if term := hugoTerm(tag); term != "" {
+				tags[term] = tag
+			}
+		}
+		for _, url := range markdown.Images(article.Content) {
+			if strings.HasPrefix(url, "data:") {
+				continue
+			}
+			if _, ok := assets[url]; !ok {
+				assetOrder = append(assetOrder, url)
+			}
+			assets[url] = append(assets[url], name)
+		}
+	}
+
+	terms := make([]string, 0, len(tags))
+	for term := range tags {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	if len(terms) > 0 {
+		if err := add("content/tags/_index.md", hugoSection{Title: "标签"}, ""); err != nil {
+			return nil, err
+		}
+	}
+	for _, term := range terms {
+		if err := add("content/tags/"+term+"/_index.md", hugoSection{Title: tags[term]}, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	bundle.Manifest.Articles = len(articles)
+	bundle.Manifest.Sections = len(sections)
+	bundle.Manifest.Tags = len(terms)
+	bundle.Manifest.Assets = make([]SiteAsset, 0, len(assetOrder))
+	for _, url := range assetOrder {
+		bundle.Manifest.Assets = append(bundle.Manifest.Assets, SiteAsset{URL: url, Pages: assets[url]})
+	}
+	manifest, err := json.MarshalIndent(bundle.Manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	bundle.Files = append(bundle.Files,
+		SiteFile{Path: "assets.json", Data: manifest},
+		SiteFile{Path: "hugo.toml", Data: []byte(s.hugoConfig())},
+	)
+	return bundle, nil
+}
+
+// articleFrontmatter maps an article onto Hugo page variables, with the rest under params
+func (s *SiteExporter) articleFrontmatter(article *model.Article, categoryNames []string) hugoFrontmatter {
+	date := article.CreatedAt
+	if article.PublishAt != nil {
+		date = *article.PublishAt
+	}
+	params := map[string]any{"id": article.ID.String()}
+	if article.Difficulty != "" {
+		params["difficulty"] = article.Difficulty
+	}
+	if article.ReadingMinutes > 0 {
+		params["readingMinutes"] = article.ReadingMinutes
+	}
+	if len(article.SourceURLs) > 0 {
+		params["sources"] = []string(article.SourceURLs)
+	}
+	if article.License != "" {
+		params["license"] = article.License
+		params["licenseUrl"] = article.LicenseURL
+	}
+	if article.Attribution != "" {
+		params["attribution"] = article.Attribution
+	}
+	if article.OGImage != "" {
+		params["images"] = []string{article.OGImage}
+	}
+
+	return hugoFrontmatter{
+		Title:       article.Title,
+		Slug:        article.Slug,
+		Date:        date.UTC(),
+		Lastmod:     article.UpdatedAt.UTC(),
+		Description: article.MetaDescription,
+		Summary:     article.Summary,
+		Tags:        article.Tags,
+		Categories:  categoryNames,
+		Params:      params,
+	}
+}
+
+// hugoConfig returns a minimal site configuration declaring the taxonomies used by the pages
+func (s *SiteExporter) hugoConfig() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "baseURL = %q\n", s.baseURL)
+	fmt.Fprintf(&b, "title = %q\n", s.title)
+	b.WriteString("hasCJKLanguage = true\n\n")
+	b.WriteString("[taxonomies]\n  tag = \"tags\"\n  category = \"categories\"\n")
+	return b.String()
+}
+
+// WriteZip writes the site as a zip archive
+func (b *SiteBundle) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	for _, f := range b.Files {
+		fw, err := archive.CreateHeader(&zip.FileHeader{Name: f.Path, Method: zip.Deflate, Modified: b.Manifest.GeneratedAt})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// WriteDir writes the site's files under dir, replacing files of earlier exports
+func (b *SiteBundle) WriteDir(dir string) error {
+	for _, f := range b.Files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, f.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageFile renders a content file with YAML frontmatter
+func pageFile(frontmatter any, body string) ([]byte, error) {
+	meta, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.Write(meta)
+	b.WriteString("---\n")
+	if body != "" {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimRight(body, "\n"))
+		b.WriteString("\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// categoryChain returns a category and its ancestors, root first
+func categoryChain(categories map[uuid.UUID]model.Category, id uuid.UUID) []model.Category {
+	var chain []model.Category
+	seen := make(map[uuid.UUID]bool)
+	for current := &id; current != nil && !seen[*current]; {
+		c, ok := categories[*current]
+		if !ok {
+			break
+		}
+		seen[c.ID] = true
+		chain = append([]model.Category{c}, chain...)
+		current = c.ParentID
+	}
+	return chain
+}
+
+// hugoTerm returns the directory Hugo gives a taxonomy term: lower-cased, with spaces as
+// hyphens and path separators dropped
+func hugoTerm(name string) string {
+	term := strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	return strings.Trim(strings.NewReplacer("/", "", "\\", "", "#", "", "?", "").Replace(term), ".")
+}
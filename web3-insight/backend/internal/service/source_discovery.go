@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/web3-insight/internal/collector"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/datatypes"
+)
+
+// Suggested source kinds
+const (
+	SourceKindRSS  = "rss"
+	SourceKindBlog = "blog"
+	SourceKindX    = "x"
+)
+
+// commonFeedPaths are tried when a suggested site has no known feed URL
+var commonFeedPaths = []string{"/feed", "/rss", "/feed.xml", "/rss.xml", "/atom.xml", "/index.xml"}
+
+// SourceDiscoveryService proposes new data sources for a topic using search and the LLM
+type SourceDiscoveryService struct {
+	llmRouter    *llm.Router
+	searchRouter *collector.SearchRouter
+	rssCollector *collector.RSSCollector
+	dsRepo       *repository.DataSourceRepository
+	usage        *UsageRecorder
+}
+
+// NewSourceDiscoveryService creates a new source discovery service
+func NewSourceDiscoveryService(
+	router *llm.Router,
+	searchRouter *collector.SearchRouter,
+	rssCollector *collector.RSSCollector,
+	dsRepo *repository.DataSourceRepository,
+) *SourceDiscoveryService {
+	return &SourceDiscoveryService{
+		llmRouter:    router,
+		searchRouter: searchRouter,
+		rssCollector: rssCollector,
+		dsRepo:       dsRepo,
+	}
+}
+
+// SetUsageRecorder enables persisting token usage of discovery calls
+func (s *SourceDiscoveryService) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// SourceSuggestion is a candidate source with validation results and a ready-to-create draft
+type SourceSuggestion struct {
+	Name            string            `json:"name"`
+	Kind            string            `json:"kind"`
+	URL             string            `json:"url"`
+	FeedURL         string            `json:"feedUrl,omitempty"`
+	Description     string            `json:"description"`
+	Reason          string            `json:"reason"`
+	Validated       bool              `json:"validated"`
+	FeedTitle       string            `json:"feedTitle,omitempty"`
+	ItemCount       int               `json:"itemCount,omitempty"`
+	ValidationError string            `json:"validationError,omitempty"`
+	AlreadyExists   bool              `json:"alreadyExists"`
+	Draft           *model.DataSource `json:"draft"`
+}
+
+// Discover searches for sources about topic and returns validated DataSource drafts
+func (s *SourceDiscoveryService) Discover(ctx context.Context, topic string, limit int) ([]SourceSuggestion, string, error) {
+	if limit <= 0 || limit > 20 {
+		limit = 10
+	}
+
+	searchContext := "（无搜索结果）"
+	if s.searchRouter != nil {
+		results, err := s.searchRouter.Search(ctx, topic+" blog RSS feed research newsletter", 10)
+		if err != nil {
+			log.Printf("Source discovery search failed: %v", err)
+		} else if results != nil && len(results.Results) > 0 {
+			var sb strings.Builder
+			for _, r := range results.Results {
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n  %s\n", r.Title, r.URL, truncateString(r.Content, 200)))
+			}
+			searchContext = sb.String()
+		}
+	}
+
+	prompt := fmt.Sprintf(PromptSourceDiscovery, topic, searchContext, limit)
+	startedAt := time.Now()
+	generated, err := s.llmRouter.Generate(llm.TaskClassification, prompt, &llm.GenerateOptions{
+		Temperature: 0.3,
+		MaxTokens:   2000,
+	})
+	s.usage.Record(model.TaskTypeSourceDiscovery, map[string]interface{}{"topic": topic}, startedAt, generated, err)
+	if err != nil {
+		return nil, "", fmt.Errorf("LLM source discovery failed: %w", err)
+	}
+	response, modelUsed := generated.Content, generated.Model
+
+	candidates, err := parseSourceSuggestions(response)
+	if err != nil {
+		return nil, modelUsed, fmt.Errorf("failed to parse suggestions: %w", err)
+	}
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	existing := s.existingSourceURLs()
+	suggestions := make([]SourceSuggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.URL == "" && candidate.FeedURL == "" {
+			continue
+		}
+		suggestion := candidate
+		s.validate(ctx, &suggestion)
+		suggestion.AlreadyExists = existing[normalizeCitationURL(suggestion.URL)] ||
+			(suggestion.FeedURL != "" && existing[normalizeCitationURL(suggestion.FeedURL)])
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, modelUsed, nil
+}
+
+// validate checks feed URLs and fills in the DataSource draft
+func (s *SourceDiscoveryService) validate(ctx context.Context, suggestion *SourceSuggestion) {
+	if suggestion.Kind == SourceKindX {
+		// No X collector yet; keep the account as a disabled API source draft
+		handle := xHandle(suggestion.URL)
+		config, _ := json.Marshal(map[string]string{"platform": "x", "handle": handle})
+		suggestion.Draft = &model.DataSource{
+			Name:    suggestion.Name,
+			Type:    model.DataSourceTypeAPI,
+			URL:     suggestion.URL,
+			Config:  datatypes.JSON(config),
+			Enabled: false,
+		}
+		suggestion.ValidationError = "X accounts are not collected automatically yet"
+		return
+	}
+
+	candidates := []string{}
+	if suggestion.FeedURL != "" {
+		candidates = append(candidates, suggestion.FeedURL)
+	}
+	if base, err := url.Parse(suggestion.URL); err == nil && base.Host != "" {
+		for _, path := range commonFeedPaths {
+			candidates = append(candidates, base.Scheme+"://"+base.Host+path)
+		}
+	}
+
+	for _, feedURL := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		feed, err := s.rssCollector.ValidateFeedURL(feedURL)
+		if err != nil {
+			suggestion.ValidationError = err.Error()
+			continue
+		}
+		suggestion.Validated = true
+		suggestion.ValidationError = ""
+		suggestion.FeedURL = feedURL
+		suggestion.FeedTitle = feed.Title
+		suggestion.ItemCount = len(feed.Items)
+		suggestion.Draft = &model.DataSource{
+			Name:          suggestion.Name,
+			Type:          model.DataSourceTypeRSS,
+			URL:           feedURL,
+			Enabled:       true,
+			FetchInterval: 3600,
+		}
+		return
+	}
+
+	// No working feed: fall back to a crawl source for the site itself
+	suggestion.Draft = &model.DataSource{
+		Name:          suggestion.Name,
+		Type:          model.DataSourceTypeCrawl,
+		URL:           suggestion.URL,
+		Enabled:       true,
+		FetchInterval: 3600,
+	}
+}
+
+// existingSourceURLs returns normalized URLs of already configured sources
+func (s *SourceDiscoveryService) existingSourceURLs() map[string]bool {
+	result := make(map[string]bool)
+	sources, err := s.dsRepo.List()
+	if err != nil {
+		return result
+	}
+	for _, source := range sources {
+		result[normalizeCitationURL(source.URL)] = true
+	}
+	return result
+}
+
+// parseSourceSuggestions extracts the JSON array from an LLM response
+func parseSourceSuggestions(response string) ([]SourceSuggestion, error) {
+	response = strings.TrimSpace(response)
+	response = regexp.MustCompile("(?s)```json\\s*").ReplaceAllString(response, "")
+	response = regexp.MustCompile("(?s)```\\s*").ReplaceAllString(response, "")
+
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start == -1 || end == -1 || end <= start {
+		return nil, fmt.Errorf("no JSON array found")
+	}
+
+	var suggestions []SourceSuggestion
+	if err := json.Unmarshal([]byte(response[start:end+1]), &suggestions); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return suggestions, nil
+}
+
+// xHandle extracts the account handle from an X/Twitter profile URL
+func xHandle(profileURL string) string {
+	u, err := url.Parse(profileURL)
+	if err != nil {
+		return strings.TrimPrefix(profileURL, "@")
+	}
+	return strings.Trim(strings.Split(strings.Trim(u.Path, "/"), "/")[0], "@")
+}
@@ -3,10 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"regexp"
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/user/web3-insight/internal/llm"
@@ -14,20 +14,48 @@ import (
 	"github.com/user/web3-insight/internal/repository"
 )
 
+// DefaultSummaryMaxAttempts is how many times a news item is tried before it is skipped
+const DefaultSummaryMaxAttempts = 3
+
+// ErrSummaryAttemptsExhausted is returned when a news item has failed summarization too often
+var ErrSummaryAttemptsExhausted = errors.New("summary attempts exhausted")
+
 // Summarizer handles news summarization and translation
 type Summarizer struct {
-	llmRouter *llm.Router
-	newsRepo  *repository.NewsRepository
+	llmRouter   *llm.Router
+	newsRepo    *repository.NewsRepository
+	usage       *UsageRecorder
+	relevance   *RelevanceScorer // Filters out irrelevant items before they are summarized; nil disables
+	maxAttempts int
 }
 
 // NewSummarizer creates a new summarizer service
 func NewSummarizer(router *llm.Router, newsRepo *repository.NewsRepository) *Summarizer {
 	return &Summarizer{
-		llmRouter: router,
-		newsRepo:  newsRepo,
+		llmRouter:   router,
+		newsRepo:    newsRepo,
+		maxAttempts: DefaultSummaryMaxAttempts,
+	}
+}
+
+// SetMaxAttempts sets how many failed attempts a news item gets before it is skipped
+func (s *Summarizer) SetMaxAttempts(maxAttempts int) {
+	if maxAttempts > 0 {
+		s.maxAttempts = maxAttempts
 	}
 }
 
+// SetUsageRecorder enables persisting token usage of summarization calls
+func (s *Summarizer) SetUsageRecorder(usage *UsageRecorder) {
+	s.usage = usage
+}
+
+// SetRelevanceScorer makes the summarizer score items for relevance first, leaving out the
+// ones that score too low
+func (s *Summarizer) SetRelevanceScorer(relevance *RelevanceScorer) {
+	s.relevance = relevance
+}
+
 // SummaryResult represents the parsed LLM response
 type SummaryResult struct {
 	Title    string   `json:"title"`
@@ -36,23 +64,38 @@ type SummaryResult struct {
 	Tags     []string `json:"tags"`
 }
 
+// SummarySchema is the JSON schema news summaries must match
+var SummarySchema = llm.MustJSONSchema("news_summary", map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"title":    map[string]interface{}{"type": "string"},
+		"summary":  map[string]interface{}{"type": "string", "minLength": 1},
+		"category": map[string]interface{}{"type": "string", "enum": []interface{}{"tech", "finance", "product", "company", "regulation"}},
+		"tags":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+	},
+	"required": []interface{}{"title", "summary", "category", "tags"},
+})
+
 // SummarizeNews generates a Chinese summary for a news item
 func (s *Summarizer) SummarizeNews(ctx context.Context, item *model.NewsItem) (*SummaryResult, string, error) {
 	// Build prompt
 	prompt := fmt.Sprintf(PromptNewsSummary, item.OriginalTitle, item.Content)
 
 	// Call LLM
-	response, modelUsed, err := s.llmRouter.Generate(llm.TaskSummarization, prompt, &llm.GenerateOptions{
+	startedAt := time.Now()
+	generated, err := s.llmRouter.GenerateStructured(llm.TaskSummarization, prompt, SummarySchema, &llm.GenerateOptions{
 		Temperature: 0.3, // Lower temperature for more consistent output
 		MaxTokens:   1000,
 	})
+	s.usage.Record(model.TaskTypeSummarize, map[string]interface{}{"newsId": item.ID}, startedAt, generated, err)
 	if err != nil {
 		return nil, "", fmt.Errorf("LLM generation failed: %w", err)
 	}
+	response, modelUsed := generated.Content, generated.Model
 
-	// Parse JSON response
-	result, err := s.parseSummaryResponse(response)
-	if err != nil {
+	// Output is already validated against the schema
+	result := &SummaryResult{}
+	if err := json.Unmarshal([]byte(response), result); err != nil {
 		log.Printf("Failed to parse LLM response, using raw output: %v", err)
 		// Fallback: use the raw response as summary
 		result = &SummaryResult{
@@ -66,36 +109,9 @@ func (s *Summarizer) SummarizeNews(ctx context.Context, item *model.NewsItem) (*
 	return result, modelUsed, nil
 }
 
-// parseSummaryResponse extracts JSON from LLM response
-func (s *Summarizer) parseSummaryResponse(response string) (*SummaryResult, error) {
-	// Try to find JSON in the response
-	response = strings.TrimSpace(response)
-
-	// Remove markdown code blocks if present
-	response = regexp.MustCompile("(?s)```json\\s*").ReplaceAllString(response, "")
-	response = regexp.MustCompile("(?s)```\\s*").ReplaceAllString(response, "")
-	response = strings.TrimSpace(response)
-
-	// Find JSON object
-	start := strings.Index(response, "{")
-	end := strings.LastIndex(response, "}")
-	if start == -1 || end == -1 || end <= start {
-		return nil, fmt.Errorf("no JSON object found in response")
-	}
-
-	jsonStr := response[start : end+1]
-
-	var result SummaryResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	return &result, nil
-}
-
 // ProcessUnprocessedNews processes all unprocessed news items
 func (s *Summarizer) ProcessUnprocessedNews(ctx context.Context, batchSize int) (int, error) {
-	items, err := s.newsRepo.FindUnprocessed(batchSize)
+	items, err := s.newsRepo.FindPendingSummary(batchSize, s.maxAttempts)
 	if err != nil {
 		return 0, fmt.Errorf("failed to find unprocessed items: %w", err)
 	}
@@ -108,9 +124,18 @@ func (s *Summarizer) ProcessUnprocessedNews(ctx context.Context, batchSize int)
 		default:
 		}
 
+		if err := s.checkRelevance(ctx, &item); err != nil {
+			if !errors.Is(err, ErrNewsFiltered) {
+				log.Printf("Failed to score relevance of news %s: %v", item.ID, err)
+				s.recordFailure(item.ID, err)
+			}
+			continue
+		}
+
 		result, modelUsed, err := s.SummarizeNews(ctx, &item)
 		if err != nil {
-			log.Printf("Failed to summarize news %s: %v", item.ID, err)
+			log.Printf("Failed to summarize news %s (attempt %d/%d): %v", item.ID, item.SummaryAttempts+1, s.maxAttempts, err)
+			s.recordFailure(item.ID, err)
 			continue
 		}
 
@@ -118,6 +143,7 @@ func (s *Summarizer) ProcessUnprocessedNews(ctx context.Context, batchSize int)
 		err = s.newsRepo.UpdateSummary(item.ID, result.Summary, result.Category, result.Tags)
 		if err != nil {
 			log.Printf("Failed to update news %s: %v", item.ID, err)
+			s.recordFailure(item.ID, err)
 			continue
 		}
 
@@ -139,11 +165,45 @@ func (s *Summarizer) SummarizeByID(ctx context.Context, id uuid.UUID) error {
 	if err != nil {
 		return fmt.Errorf("news item not found: %w", err)
 	}
+	if item.SummaryAttempts >= s.maxAttempts {
+		return ErrSummaryAttemptsExhausted
+	}
+	if err := s.checkRelevance(ctx, item); err != nil {
+		if !errors.Is(err, ErrNewsFiltered) {
+			s.recordFailure(id, err)
+		}
+		return err
+	}
 
 	result, _, err := s.SummarizeNews(ctx, item)
 	if err != nil {
+		s.recordFailure(id, err)
 		return err
 	}
 
-	return s.newsRepo.UpdateSummary(id, result.Summary, result.Category, result.Tags)
+	if err := s.newsRepo.UpdateSummary(id, result.Summary, result.Category, result.Tags); err != nil {
+		s.recordFailure(id, err)
+		return err
+	}
+	return nil
+}
+
+// checkRelevance scores an item for relevance when a scorer is set, returning
+// ErrNewsFiltered for items that scored too low
+func (s *Summarizer) checkRelevance(ctx context.Context, item *model.NewsItem) error {
+	if s.relevance == nil {
+		return nil
+	}
+	err := s.relevance.Check(ctx, item)
+	if errors.Is(err, ErrNewsFiltered) {
+		log.Printf("Filtered news %s (%s, score %.2f): %s", item.ID, item.Filtered, *item.RelevanceScore, item.RelevanceReason)
+	}
+	return err
+}
+
+// recordFailure counts a failed attempt against a news item
+func (s *Summarizer) recordFailure(id uuid.UUID, summaryErr error) {
+	if err := s.newsRepo.RecordSummaryFailure(id, summaryErr); err != nil {
+		log.Printf("Failed to record summary failure for news %s: %v", id, err)
+	}
 }
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// translationChunkChars is the most content translated in one LLM call; longer news is split
+// at paragraph breaks
+const translationChunkChars = 3000
+
+// ErrNothingToTranslate is returned for news items that have no content or are already Chinese
+var ErrNothingToTranslate = errors.New("nothing to translate")
+
+// Translator makes full Chinese translations of foreign-language news
+type Translator struct {
+	llmRouter *llm.Router
+	newsRepo  *repository.NewsRepository
+	usage     *UsageRecorder
+}
+
+// NewTranslator creates a new translator
+func NewTranslator(router *llm.Router, newsRepo *repository.NewsRepository) *Translator {
+	return &Translator{llmRouter: router, newsRepo: newsRepo}
+}
+
+// SetUsageRecorder enables persisting token usage of translation calls
+func (t *Translator) SetUsageRecorder(usage *UsageRecorder) {
+	t.usage = usage
+}
+
+// TranslateNews translates the title and full content of a news item into Chinese through
+// the translation route and stores the translation alongside the item, replacing any
+// earlier one. Long content is translated a few thousand characters at a time.
+func (t *Translator) TranslateNews(ctx context.Context, id uuid.UUID) (*model.NewsTranslation, error) {
+	item, err := t.newsRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("news item not found: %w", err)
+	}
+	if strings.TrimSpace(item.Content) == "" || item.SourceLanguage == "zh" {
+		return nil, ErrNothingToTranslate
+	}
+
+	title := item.OriginalTitle
+	if title == "" {
+		title = item.Title
+	}
+	chunks := splitForTranslation(item.Content, translationChunkChars)
+
+	translation := &model.NewsTranslation{NewsID: item.ID, Language: "zh"}
+	var parts []string
+	for i, chunk := range chunks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		notes := ""
+		if i == 0 {
+			notes = PromptNewsTranslationTitle
+			chunk = "# " + title + "\n\n" + chunk
+		}
+		if len(chunks) > 1 {
+			notes += fmt.Sprintf(PromptNewsTranslationPart, i+1, len(chunks))
+		}
+		prompt := fmt.Sprintf(PromptNewsTranslation, notes, chunk)
+
+		startedAt := time.Now()
+		result, err := t.llmRouter.Generate(llm.TaskTranslation, prompt, &llm.GenerateOptions{
+			Temperature: 0.2,
+			MaxTokens:   4000,
+		})
+		t.usage.Record(model.TaskTypeTranslate, map[string]interface{}{"newsId": item.ID, "part": i + 1}, startedAt, result, err)
+		if err != nil {
+			return nil, fmt.Errorf("translation of part %d/%d failed: %w", i+1, len(chunks), err)
+		}
+
+		text := strings.TrimSpace(result.Content)
+		if i == 0 {
+			translation.Title, text = splitTranslatedTitle(text)
+		}
+		parts = append(parts, text)
+		translation.ModelUsed = result.Model
+		translation.TokensUsed += result.Usage.InputTokens + result.Usage.OutputTokens
+	}
+	if translation.Title == "" {
+		translation.Title = item.Title
+	}
+	translation.Content = strings.Join(parts, "\n\n")
+
+	if err := t.newsRepo.SaveTranslation(translation); err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return translation, nil
+}
+
+// splitForTranslation splits content into chunks of at most maxChars characters, breaking
+// between paragraphs where possible
+func splitForTranslation(content string, maxChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if currentLen > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, paragraph := range strings.Split(strings.TrimSpace(content), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		runes := []rune(paragraph)
+		if len(runes) == 0 {
+			continue
+		}
+		if currentLen > 0 && currentLen+len(runes)+2 > maxChars {
+			flush()
+		}
+		// A paragraph longer than a chunk is cut into chunk-sized pieces
+		for len(runes) > maxChars {
+			flush()
+			chunks = append(chunks, string(runes[:maxChars]))
+			runes = runes[maxChars:]
+		}
+		if currentLen > 0 {
+			current.WriteString("\n\n")
+			currentLen += 2
+		}
+		current.WriteString(string(runes))
+		currentLen += len(runes)
+	}
+	flush()
+	return chunks
+}
+
+// splitTranslatedTitle takes the "# " title line off the start of a translation
+func splitTranslatedTitle(text string) (string, string) {
+	if !strings.HasPrefix(text, "# ") {
+		return "", text
+	}
+	title, rest, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(strings.TrimPrefix(title, "# ")), strings.TrimSpace(rest)
+}
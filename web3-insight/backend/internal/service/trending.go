@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// Defaults of trend analysis
+const (
+	DefaultTrendDays  = 7
+	DefaultTrendLimit = 20
+)
+
+const (
+	trendSimilarity  = 0.75           // Cosine similarity to a cluster's centroid for news to join it
+	trendMinMentions = 3              // Fewest distinct stories a trending topic needs
+	trendRecent      = 24 * time.Hour // Window whose rate is compared to the rest for velocity
+	trendMaxItems    = 2000           // News items analyzed per run, newest first
+	trendTopicTags   = 5
+	trendTopicNews   = 10
+)
+
+// TrendDetector ranks the topics of recent news by how much, how widely and how suddenly
+// they are reported
+type TrendDetector struct {
+	newsRepo    *repository.NewsRepository
+	trendRepo   *repository.TrendingTopicRepository
+	articleRepo *repository.ArticleRepository
+}
+
+// NewTrendDetector creates a new trend detector
+func NewTrendDetector(newsRepo *repository.NewsRepository, trendRepo *repository.TrendingTopicRepository, articleRepo *repository.ArticleRepository) *TrendDetector {
+	return &TrendDetector{newsRepo: newsRepo, trendRepo: trendRepo, articleRepo: articleRepo}
+}
+
+// trendCluster is a group of news items about one topic
+type trendCluster struct {
+	centroid []float32 // Sum of the members' embeddings; nil for clusters of unembedded news
+	items    []*model.NewsItem
+	label    string // Lower-cased most frequent tag, unique across clusters
+	name     string // The tag as first spelled
+}
+
+// Detect analyzes the summarized news of the last days and replaces the trending topics
+// with the top limit. News is grouped by embedding similarity, and news without embeddings
+// by tag; groups are named by their most frequent tag and merged by name. A topic scores by
+// its distinct stories, the log of its distinct feeds and the square root of its velocity.
+func (d *TrendDetector) Detect(ctx context.Context, days, limit int) ([]model.TrendingTopic, error) {
+	if days <= 0 {
+		days = DefaultTrendDays
+	}
+	if limit <= 0 {
+		limit = DefaultTrendLimit
+	}
+
+	now := time.Now()
+	items, err := d.newsRepo.FindForTrending(now.AddDate(0, 0, -days), trendMaxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent news: %w", err)
+	}
+
+	// Cluster embedded news oldest first, so early coverage seeds the clusters
+	var clusters []*trendCluster
+	var unembedded []*model.NewsItem
+	for i := len(items) - 1; i >= 0; i-- {
+		if i%200 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		item := &items[i]
+		if item.Embedding == nil {
+			unembedded = append(unembedded, item)
+			continue
+		}
+		clusters = addToTrendCluster(clusters, item, item.Embedding.Slice())
+	}
+
+	// Name clusters by tag and merge the ones with the same name
+	byLabel := make(map[string]*trendCluster)
+	var named []*trendCluster
+	for _, c := range clusters {
+		tags := topTags(c.items, 1)
+		if len(tags) == 0 {
+			continue
+		}
+		c.label, c.name = strings.ToLower(tags[0]), tags[0]
+		if existing, ok := byLabel[c.label]; ok {
+			existing.items = append(existing.items, c.items...)
+			continue
+		}
+		byLabel[c.label] = c
+		named = append(named, c)
+	}
+	for _, item := range unembedded {
+		placed := false
+		for _, tag := range item.Tags {
+			if c, ok := byLabel[strings.ToLower(strings.TrimSpace(tag))]; ok {
+				c.items = append(c.items, item)
+				placed = true
+				break
+			}
+		}
+		if !placed && len(item.Tags) > 0 {
+			name := strings.TrimSpace(item.Tags[0])
+			label := strings.ToLower(name)
+			if label == "" {
+				continue
+			}
+			c := &trendCluster{label: label, name: name, items: []*model.NewsItem{item}}
+			byLabel[label] = c
+			named = append(named, c)
+		}
+	}
+
+	var topics []model.TrendingTopic
+	for _, c := range named {
+		topic, ok := scoreTrendCluster(c, now, days)
+		if ok {
+			topics = append(topics, topic)
+		}
+	}
+	sort.SliceStable(topics, func(i, j int) bool { return topics[i].Score > topics[j].Score })
+	if len(topics) > limit {
+		topics = topics[:limit]
+	}
+	for i := range topics {
+		topics[i].Rank = i + 1
+	}
+
+	if err := d.trendRepo.Replace(topics); err != nil {
+		return nil, fmt.Errorf("failed to save trending topics: %w", err)
+	}
+	return topics, nil
+}
+
+// addToTrendCluster adds an embedded item to the most similar cluster, or starts a new one
+func addToTrendCluster(clusters []*trendCluster, item *model.NewsItem, embedding []float32) []*trendCluster {
+	var best *trendCluster
+	bestSimilarity := trendSimilarity
+	for _, c := range clusters {
+		if similarity := cosineSimilarity(c.centroid, embedding); similarity >= bestSimilarity {
+			best, bestSimilarity = c, similarity
+		}
+	}
+	if best == nil {
+		centroid := make([]float32, len(embedding))
+		copy(centroid, embedding)
+		return append(clusters, &trendCluster{centroid: centroid, items: []*model.NewsItem{item}})
+	}
+	for i := 0; i < len(best.centroid) && i < len(embedding); i++ {
+		best.centroid[i] += embedding[i]
+	}
+	best.items = append(best.items, item)
+	return clusters
+}
+
+// scoreTrendCluster turns a cluster into a trending topic, reporting false when it has too
+// few distinct stories. Items of the same story count once.
+func scoreTrendCluster(c *trendCluster, now time.Time, days int) (model.TrendingTopic, bool) {
+	stories := make(map[uuid.UUID]bool)
+	sources := make(map[string]bool)
+	recent := 0
+	sort.Slice(c.items, func(i, j int) bool { return c.items[i].FetchedAt.After(c.items[j].FetchedAt) })
+
+	var newsIDs []string
+	for _, item := range c.items {
+		key := item.ID
+		if item.StoryID != nil {
+			key = *item.StoryID
+		}
+		if item.SourceName != "" {
+			sources[item.SourceName] = true
+		}
+		if stories[key] {
+			continue
+		}
+		stories[key] = true
+		if now.Sub(item.FetchedAt) <= trendRecent {
+			recent++
+		}
+		if len(newsIDs) < trendTopicNews {
+			newsIDs = append(newsIDs, item.ID.String())
+		}
+	}
+
+	mentions := len(stories)
+	if mentions < trendMinMentions {
+		return model.TrendingTopic{}, false
+	}
+
+	// Daily rate of the last day against the days before it, smoothed for quiet topics
+	baseline := 0.0
+	if days > 1 {
+		baseline = float64(mentions-recent) / float64(days-1)
+	}
+	velocity := (float64(recent) + 1) / (baseline + 1)
+	score := float64(mentions) * (1 + math.Log(float64(max(len(sources), 1)))) * math.Sqrt(velocity)
+
+	return model.TrendingTopic{
+		Topic:      c.name,
+		Tags:       topTags(c.items, trendTopicTags),
+		Score:      math.Round(score*100) / 100,
+		Mentions:   mentions,
+		Sources:    len(sources),
+		Velocity:   math.Round(velocity*100) / 100,
+		NewsIDs:    newsIDs,
+		WindowDays: days,
+		ComputedAt: now,
+	}, true
+}
+
+// topTags returns the n tags most used by the items, ignoring case, in the spelling seen first
+func topTags(items []*model.NewsItem, n int) []string {
+	counts := make(map[string]int)
+	spelling := make(map[string]string)
+	var order []string
+	for _, item := range items {
+		for _, tag := range item.Tags {
+			tag = strings.TrimSpace(tag)
+			key := strings.ToLower(tag)
+			if key == "" {
+				continue
+			}
+			if _, ok := spelling[key]; !ok {
+				spelling[key] = tag
+				order = append(order, key)
+			}
+			counts[key]++
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > n {
+		order = order[:n]
+	}
+	tags := make([]string, len(order))
+	for i, key := range order {
+		tags[i] = spelling[key]
+	}
+	return tags
+}
+
+// List returns up to limit trending topics from the latest analysis
+func (d *TrendDetector) List(limit int) ([]model.TrendingTopic, error) {
+	if limit <= 0 {
+		limit = DefaultTrendLimit
+	}
+	return d.trendRepo.List(limit)
+}
+
+// NextArticleTopic returns the highest ranked trending topic no article covers yet: none
+// was generated from it and no article title contains it. Returns nil when there is none.
+func (d *TrendDetector) NextArticleTopic() (*model.TrendingTopic, error) {
+	topics, err := d.trendRepo.List(DefaultTrendLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range topics {
+		if topics[i].ArticleID != nil {
+			continue
+		}
+		covered, err := d.articleRepo.TitleContains(topics[i].Topic)
+		if err != nil {
+			return nil, err
+		}
+		if !covered {
+			return &topics[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// References returns the news behind a topic as generation references: each item's title,
+// source URL and summary
+func (d *TrendDetector) References(topic *model.TrendingTopic) []string {
+	ids := make([]uuid.UUID, 0, len(topic.NewsIDs))
+	for _, s := range topic.NewsIDs {
+		if id, err := uuid.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	items, err := d.newsRepo.FindByIDs(ids)
+	if err != nil {
+		return nil
+	}
+	refs := make([]string, 0, len(items))
+	for _, item := range items {
+		ref := fmt.Sprintf("%s（%s）", item.Title, item.SourceURL)
+		if item.Summary != "" {
+			ref += "：" + item.Summary
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// MarkGenerated records the article generated from a topic
+func (d *TrendDetector) MarkGenerated(topic *model.TrendingTopic, articleID uuid.UUID) error {
+	return d.trendRepo.SetArticle(topic.Topic, articleID)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/user/web3-insight/internal/llm"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+// UsageRecorder persists the token usage and cost of LLM calls to the tasks table
+type UsageRecorder struct {
+	taskRepo *repository.TaskRepository
+}
+
+// NewUsageRecorder creates a new usage recorder
+func NewUsageRecorder(taskRepo *repository.TaskRepository) *UsageRecorder {
+	return &UsageRecorder{taskRepo: taskRepo}
+}
+
+// usageResult is stored in the task result column
+type usageResult struct {
+	InputTokens  int  `json:"inputTokens"`
+	OutputTokens int  `json:"outputTokens"`
+	Cached       bool `json:"cached"`
+	Retries      int  `json:"retries,omitempty"`
+}
+
+// Record stores one LLM call as a completed or failed task. A nil recorder is a no-op.
+func (u *UsageRecorder) Record(taskType string, payload interface{}, startedAt time.Time, result *llm.GenerateResult, callErr error) {
+	if u == nil || u.taskRepo == nil {
+		return
+	}
+
+	now := time.Now()
+	task := &model.Task{
+		Type:        taskType,
+		Status:      model.TaskStatusCompleted,
+		StartedAt:   &startedAt,
+		CompletedAt: &now,
+	}
+	if payload != nil {
+		task.Payload, _ = json.Marshal(payload)
+	}
+
+	if callErr != nil {
+		task.Status = model.TaskStatusFailed
+		task.Error = callErr.Error()
+	}
+	if result != nil {
+		task.ModelUsed = result.Model
+		task.TokensUsed = result.Usage.TotalTokens()
+		task.CostUSD = decimal.NewFromFloat(result.CostUSD)
+		task.Result, _ = json.Marshal(usageResult{
+			InputTokens:  result.Usage.InputTokens,
+			OutputTokens: result.Usage.OutputTokens,
+			Cached:       result.Cached,
+			Retries:      result.Retries,
+		})
+	}
+
+	if err := u.taskRepo.Create(task); err != nil {
+		log.Printf("Failed to record LLM usage for %s: %v", taskType, err)
+	}
+}
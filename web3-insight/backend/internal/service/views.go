@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/repository"
+)
+
+const (
+	viewsPendingKey  = "article:views:pending"
+	viewsFlushingKey = "article:views:flushing"
+)
+
+// ViewCounter buffers article view increments in Redis; the worker flushes them to Postgres.
+// Without a Redis client views are written to the database directly.
+type ViewCounter struct {
+	client      *redis.Client
+	articleRepo *repository.ArticleRepository
+}
+
+// NewViewCounter creates a view counter
+func NewViewCounter(client *redis.Client, articleRepo *repository.ArticleRepository) *ViewCounter {
+	return &ViewCounter{
+		client:      client,
+		articleRepo: articleRepo,
+	}
+}
+
+// NewViewCounterFromConfig creates a view counter with its own Redis client
+func NewViewCounterFromConfig(redisCfg *config.RedisConfig, articleRepo *repository.ArticleRepository) *ViewCounter {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisCfg.Host, redisCfg.Port),
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+	return NewViewCounter(client, articleRepo)
+}
+
+// NewDirectViewCounter creates a view counter that writes every view to the database, for
+// deployments running without Redis
+func NewDirectViewCounter(articleRepo *repository.ArticleRepository) *ViewCounter {
+	return NewViewCounter(nil, articleRepo)
+}
+
+// Increment records one view. If Redis is unavailable the view is written to the database directly.
+func (v *ViewCounter) Increment(articleID uuid.UUID) {
+	if v.client == nil {
+		go v.write(articleID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := v.client.HIncrBy(ctx, viewsPendingKey, articleID.String(), 1).Err(); err != nil {
+		log.Printf("View counter buffer failed, writing directly: %v", err)
+		go v.write(articleID)
+	}
+}
+
+// write adds one view to an article in the database
+func (v *ViewCounter) write(articleID uuid.UUID) {
+	if err := v.articleRepo.IncrementViewCount(articleID); err != nil {
+		log.Printf("Failed to increment view count for %s: %v", articleID, err)
+	}
+}
+
+// Flush moves buffered views to the database and returns how many articles were updated.
+// The pending hash is renamed first so views arriving during the flush are not lost.
+func (v *ViewCounter) Flush(ctx context.Context) (int, error) {
+	if v.client == nil {
+		return 0, nil // Nothing is buffered
+	}
+
+	// A previous flush that failed midway leaves its snapshot behind; apply it first
+	exists, err := v.client.Exists(ctx, viewsFlushingKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check view snapshot: %w", err)
+	}
+	if exists == 0 {
+		if err := v.client.Rename(ctx, viewsPendingKey, viewsFlushingKey).Err(); err != nil {
+			if err.Error() == "ERR no such key" {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to snapshot view counts: %w", err)
+		}
+	}
+
+	raw, err := v.client.HGetAll(ctx, viewsFlushingKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read view counts: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int64, len(raw))
+	for field, value := range raw {
+		id, err := uuid.Parse(field)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		counts[id] = n
+	}
+
+	if err := v.articleRepo.AddViewCounts(counts); err != nil {
+		// Keep the snapshot so the next flush retries it
+		return 0, fmt.Errorf("failed to write view counts: %w", err)
+	}
+	if err := v.client.Del(ctx, viewsFlushingKey).Err(); err != nil {
+		log.Printf("Failed to clear view snapshot: %v", err)
+	}
+
+	return len(counts), nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/web3-insight/internal/model"
+	"github.com/user/web3-insight/internal/repository"
+	"github.com/user/web3-insight/internal/wiki"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownWikiTarget is returned for an export target that isn't configured
+var ErrUnknownWikiTarget = errors.New("wiki target not configured")
+
+// WikiExportService pushes published articles to external wikis, remembering the page of
+// every exported article so that later exports overwrite it
+type WikiExportService struct {
+	articleRepo  *repository.ArticleRepository
+	categoryRepo *repository.CategoryRepository
+	pageRepo     *repository.WikiPageRepository
+	publishers   map[string]wiki.Publisher
+}
+
+// NewWikiExportService creates a new wiki export service
+func NewWikiExportService(articleRepo *repository.ArticleRepository, categoryRepo *repository.CategoryRepository, pageRepo *repository.WikiPageRepository, publishers map[string]wiki.Publisher) *WikiExportService {
+	return &WikiExportService{
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		pageRepo:     pageRepo,
+		publishers:   publishers,
+	}
+}
+
+// WikiExportRequest selects the articles to export. Articles of the category are added to
+// ArticleIDs; only published articles are exported.
+type WikiExportRequest struct {
+	Target               string      `json:"target"`
+	ArticleIDs           []uuid.UUID `json:"articleIds,omitempty"`
+	CategoryID           *uuid.UUID  `json:"categoryId,omitempty"`
+	IncludeSubcategories bool        `json:"includeSubcategories,omitempty"`
+	Force                bool        `json:"force,omitempty"` // Re-export articles unchanged since the last export
+}
+
+// WikiExportFailure describes an article that could not be exported
+type WikiExportFailure struct {
+	ArticleID uuid.UUID `json:"articleId"`
+	Title     string    `json:"title"`
+	Error     string    `json:"error"`
+}
+
+// WikiExportResult summarizes an export run
+type WikiExportResult struct {
+	Target  string              `json:"target"`
+	Created int                 `json:"created"`
+	Updated int                 `json:"updated"`
+	Skipped int                 `json:"skipped"` // Unchanged or unpublished
+	Failed  []WikiExportFailure `json:"failed,omitempty"`
+}
+
+// Targets returns the configured export targets
+func (s *WikiExportService) Targets() []string {
+	return wiki.Targets(s.publishers)
+}
+
+// HasTarget reports whether an export target is configured
+func (s *WikiExportService) HasTarget(target string) bool {
+	_, ok := s.publishers[target]
+	return ok
+}
+
+// Export pushes the selected articles to the target wiki, continuing past failed articles
+func (s *WikiExportService) Export(ctx context.Context, req WikiExportRequest) (*WikiExportResult, error) {
+	publisher, ok := s.publishers[req.Target]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWikiTarget, req.Target)
+	}
+
+	articles, err := s.selectArticles(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WikiExportResult{Target: req.Target}
+	for i := range articles {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		article := &articles[i]
+		if article.Status != "published" {
+			result.Skipped++
+			continue
+		}
+
+		action, err := s.exportArticle(ctx, publisher, article, req.Force)
+		if err != nil {
+			log.Printf("Failed to export article %s to %s: %v", article.ID, req.Target, err)
+			result.Failed = append(result.Failed, WikiExportFailure{ArticleID: article.ID, Title: article.Title, Error: err.Error()})
+			continue
+		}
+		switch action {
+		case model.ChangeActionCreated:
+			result.Created++
+		case model.ChangeActionUpdated:
+			result.Updated++
+		default:
+			result.Skipped++
+		}
+	}
+
+	log.Printf("Wiki export to %s: %d created, %d updated, %d skipped, %d failed",
+		req.Target, result.Created, result.Updated, result.Skipped, len(result.Failed))
+	return result, nil
+}
+
+// exportArticle creates or updates the article's page, returning the change action or "" if skipped
+func (s *WikiExportService) exportArticle(ctx context.Context, publisher wiki.Publisher, article *model.Article, force bool) (string, error) {
+	markdown := wikiMarkdown(article)
+	hash := wikiContentHash(article.Title, markdown)
+
+	existing, err := s.pageRepo.Get(article.ID, publisher.Name())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if existing != nil && existing.ContentHash == hash && !force {
+		return "", nil
+	}
+
+	action := model.ChangeActionCreated
+	var page *wiki.RemotePage
+	if existing != nil {
+		action = model.ChangeActionUpdated
+		page, err = publisher.UpdatePage(ctx, existing.RemoteID, article.Title, markdown)
+		if errors.Is(err, wiki.ErrPageNotFound) {
+			// Deleted in the wiki since the last export
+			action = model.ChangeActionCreated
+			page, err = publisher.CreatePage(ctx, article.Title, markdown)
+		}
+	} else {
+		page, err = publisher.CreatePage(ctx, article.Title, markdown)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = s.pageRepo.Save(&model.WikiPage{
+		ArticleID:   article.ID,
+		Target:      publisher.Name(),
+		RemoteID:    page.ID,
+		RemoteURL:   page.URL,
+		ContentHash: hash,
+		ExportedAt:  time.Now(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("exported as %s but failed to save mapping: %w", page.ID, err)
+	}
+	return action, nil
+}
+
+// selectArticles loads the requested articles and those of the category, without duplicates
+func (s *WikiExportService) selectArticles(req WikiExportRequest) ([]model.Article, error) {
+	seen := make(map[uuid.UUID]bool)
+	var articles []model.Article
+	add := func(article model.Article) {
+		if !seen[article.ID] {
+			seen[article.ID] = true
+			articles = append(articles, article)
+		}
+	}
+
+	for _, id := range req.ArticleIDs {
+		article, err := s.articleRepo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("article %s: %w", id, err)
+		}
+		add(*article)
+	}
+
+	if req.CategoryID != nil {
+		categoryIDs := []uuid.UUID{*req.CategoryID}
+		if req.IncludeSubcategories {
+			descendants, err := s.descendantCategories(*req.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			categoryIDs = append(categoryIDs, descendants...)
+		}
+		for _, categoryID := range categoryIDs {
+			id := categoryID
+			result, err := s.articleRepo.List(repository.ArticleListParams{
+				CategoryID: &id,
+				Status:     "published",
+				PageSize:   1000,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, article := range result.Articles {
+				add(article)
+			}
+		}
+	}
+	return articles, nil
+}
+
+// descendantCategories returns the IDs of all categories below a category
+func (s *WikiExportService) descendantCategories(rootID uuid.UUID) ([]uuid.UUID, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	var ids []uuid.UUID
+	queue := children[rootID]
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		ids = append(ids, id)
+		queue = append(queue, children[id]...)
+	}
+	return ids, nil
+}
+
+// wikiMarkdown is the exported page body: the article with its summary, sources and attribution
+func wikiMarkdown(article *model.Article) string {
+	var b strings.Builder
+	if summary := strings.TrimSpace(article.Summary); summary != "" {
+		b.WriteString("> " + strings.ReplaceAll(summary, "\n", "\n> ") + "\n\n")
+	}
+	b.WriteString(strings.TrimSpace(article.Content))
+	b.WriteString("\n")
+
+	if len(article.SourceURLs) > 0 {
+		b.WriteString("\n## 参考来源\n\n")
+		for _, url := range article.SourceURLs {
+			b.WriteString("- " + url + "\n")
+		}
+	}
+	if article.Attribution != "" {
+		b.WriteString("\n---\n\n" + article.Attribution + "\n")
+	}
+	return b.String()
+}
+
+func wikiContentHash(title, markdown string) string {
+	sum := sha256.Sum256([]byte(title + "\n" + markdown))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,169 @@
+package wiki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// ConfluencePublisher publishes pages through the Confluence REST API (Cloud and Data Center)
+type ConfluencePublisher struct {
+	baseURL      string
+	username     string
+	apiToken     string
+	spaceKey     string
+	parentPageID string
+	client       *http.Client
+}
+
+// NewConfluencePublisher creates a new Confluence publisher
+func NewConfluencePublisher(cfg config.ConfluenceConfig) *ConfluencePublisher {
+	return &ConfluencePublisher{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		username:     cfg.Username,
+		apiToken:     cfg.APIToken,
+		spaceKey:     cfg.SpaceKey,
+		parentPageID: cfg.ParentPageID,
+		client:       newHTTPClient(),
+	}
+}
+
+func (p *ConfluencePublisher) Name() string { return model.WikiTargetConfluence }
+
+// confluenceContent is the subset of a Confluence content object used here
+type confluenceContent struct {
+	ID      string `json:"id"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// CreatePage creates a page in the configured space
+func (p *ConfluencePublisher) CreatePage(ctx context.Context, title, markdown string) (*RemotePage, error) {
+	body, err := MarkdownToConfluenceStorage(markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": p.spaceKey},
+		"body":  storageBody(body),
+	}
+	if p.parentPageID != "" {
+		payload["ancestors"] = []map[string]string{{"id": p.parentPageID}}
+	}
+
+	var created confluenceContent
+	if err := p.do(ctx, "POST", "/rest/api/content", payload, &created); err != nil {
+		return nil, err
+	}
+	return p.remotePage(&created), nil
+}
+
+// UpdatePage replaces the title and body of a page, bumping its version
+func (p *ConfluencePublisher) UpdatePage(ctx context.Context, id, title, markdown string) (*RemotePage, error) {
+	body, err := MarkdownToConfluenceStorage(markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	var current confluenceContent
+	if err := p.do(ctx, "GET", "/rest/api/content/"+id+"?expand=version", nil, &current); err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"id":      id,
+		"type":    "page",
+		"title":   title,
+		"version": map[string]int{"number": current.Version.Number + 1},
+		"body":    storageBody(body),
+	}
+	var updated confluenceContent
+	if err := p.do(ctx, "PUT", "/rest/api/content/"+id, payload, &updated); err != nil {
+		return nil, err
+	}
+	return p.remotePage(&updated), nil
+}
+
+func storageBody(value string) map[string]interface{} {
+	return map[string]interface{}{
+		"storage": map[string]string{"value": value, "representation": "storage"},
+	}
+}
+
+func (p *ConfluencePublisher) remotePage(content *confluenceContent) *RemotePage {
+	base := content.Links.Base
+	if base == "" {
+		base = p.baseURL
+	}
+	page := &RemotePage{ID: content.ID}
+	if content.Links.WebUI != "" {
+		page.URL = base + content.Links.WebUI
+	}
+	return page
+}
+
+// do sends a request to the REST API and decodes the JSON response into out
+func (p *ConfluencePublisher) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Cloud uses email + API token; Data Center uses a personal access token
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.apiToken)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("confluence request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrPageNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("confluence returned status %d: %s", resp.StatusCode, errResp.Message)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package wiki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/model"
+)
+
+const (
+	defaultFeishuBaseURL = "https://open.feishu.cn"
+	// feishuMaxBlocksPerInsert is the API limit on blocks created by one descendant request
+	feishuMaxBlocksPerInsert = 1000
+	// feishuNodeNotFound is the error code for a missing wiki node
+	feishuNodeNotFound = 131005
+)
+
+// FeishuPublisher publishes pages to a 飞书知识库 (Feishu/Lark wiki) space as docx documents.
+// Markdown is converted to document blocks by Feishu's own converter.
+type FeishuPublisher struct {
+	baseURL         string
+	webURL          string
+	appID           string
+	appSecret       string
+	spaceID         string
+	parentNodeToken string
+	client          *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewFeishuPublisher creates a new Feishu publisher
+func NewFeishuPublisher(cfg config.FeishuConfig) *FeishuPublisher {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultFeishuBaseURL
+	}
+	return &FeishuPublisher{
+		baseURL:         baseURL,
+		webURL:          strings.TrimRight(cfg.WebURL, "/"),
+		appID:           cfg.AppID,
+		appSecret:       cfg.AppSecret,
+		spaceID:         cfg.SpaceID,
+		parentNodeToken: cfg.ParentNodeToken,
+		client:          newHTTPClient(),
+	}
+}
+
+func (p *FeishuPublisher) Name() string { return model.WikiTargetFeishu }
+
+// feishuNode is the subset of a wiki node used here
+type feishuNode struct {
+	NodeToken string `json:"node_token"`
+	ObjToken  string `json:"obj_token"` // The docx document ID
+}
+
+// CreatePage creates a docx wiki node and fills it with the converted markdown
+func (p *FeishuPublisher) CreatePage(ctx context.Context, title, markdown string) (*RemotePage, error) {
+	payload := map[string]interface{}{
+		"obj_type":  "docx",
+		"node_type": "origin",
+		"title":     title,
+	}
+	if p.parentNodeToken != "" {
+		payload["parent_node_token"] = p.parentNodeToken
+	}
+
+	var created struct {
+		Node feishuNode `json:"node"`
+	}
+	if err := p.do(ctx, "POST", "/open-apis/wiki/v2/spaces/"+p.spaceID+"/nodes", payload, &created); err != nil {
+		return nil, err
+	}
+
+	if err := p.writeContent(ctx, created.Node.ObjToken, markdown); err != nil {
+		return nil, err
+	}
+	return p.remotePage(created.Node.NodeToken), nil
+}
+
+// UpdatePage renames the wiki node and replaces the document content
+func (p *FeishuPublisher) UpdatePage(ctx context.Context, id, title, markdown string) (*RemotePage, error) {
+	var found struct {
+		Node feishuNode `json:"node"`
+	}
+	if err := p.do(ctx, "GET", "/open-apis/wiki/v2/spaces/get_node?token="+id, nil, &found); err != nil {
+		return nil, err
+	}
+
+	if err := p.do(ctx, "POST", "/open-apis/wiki/v2/spaces/"+p.spaceID+"/nodes/"+id+"/update_title", map[string]string{"title": title}, nil); err != nil {
+		return nil, err
+	}
+	if err := p.clearContent(ctx, found.Node.ObjToken); err != nil {
+		return nil, err
+	}
+	if err := p.writeContent(ctx, found.Node.ObjToken, markdown); err != nil {
+		return nil, err
+	}
+	return p.remotePage(id), nil
+}
+
+func (p *FeishuPublisher) remotePage(nodeToken string) *RemotePage {
+	page := &RemotePage{ID: nodeToken}
+	if p.webURL != "" {
+		page.URL = p.webURL + "/wiki/" + nodeToken
+	}
+	return page
+}
+
+// writeContent converts markdown to blocks and appends them to the document
+func (p *FeishuPublisher) writeContent(ctx context.Context, documentID, markdown string) error {
+	var converted struct {
+		FirstLevelBlockIDs []string                 `json:"first_level_block_ids"`
+		Blocks             []map[string]interface{} `json:"blocks"`
+	}
+	payload := map[string]string{"content_type": "markdown", "content": markdown}
+	if err := p.do(ctx, "POST", "/open-apis/docx/v1/documents/blocks/convert", payload, &converted); err != nil {
+		return err
+	}
+
+	blocks := make(map[string]map[string]interface{}, len(converted.Blocks))
+	for _, block := range converted.Blocks {
+		// Converted tables carry read-only merge info that the insert API rejects
+		if table, ok := block["table"].(map[string]interface{}); ok {
+			if property, ok := table["property"].(map[string]interface{}); ok {
+				delete(property, "merge_info")
+			}
+		}
+		if id, ok := block["block_id"].(string); ok {
+			blocks[id] = block
+		}
+	}
+
+	// Insert top-level blocks with their descendants, in batches under the API limit
+	index := 0
+	var batchIDs []string
+	var batch []map[string]interface{}
+	flush := func() error {
+		if len(batchIDs) == 0 {
+			return nil
+		}
+		insert := map[string]interface{}{
+			"index":       index,
+			"children_id": batchIDs,
+			"descendants": batch,
+		}
+		path := "/open-apis/docx/v1/documents/" + documentID + "/blocks/" + documentID + "/descendant"
+		if err := p.do(ctx, "POST", path, insert, nil); err != nil {
+			return err
+		}
+		index += len(batchIDs)
+		batchIDs, batch = nil, nil
+		return nil
+	}
+	for _, id := range converted.FirstLevelBlockIDs {
+		subtree := collectBlocks(blocks, id)
+		if len(batch)+len(subtree) > feishuMaxBlocksPerInsert {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		batchIDs = append(batchIDs, id)
+		batch = append(batch, subtree...)
+	}
+	return flush()
+}
+
+// collectBlocks returns a block and all its descendants
+func collectBlocks(blocks map[string]map[string]interface{}, id string) []map[string]interface{} {
+	block, ok := blocks[id]
+	if !ok {
+		return nil
+	}
+	subtree := []map[string]interface{}{block}
+	if children, ok := block["children"].([]interface{}); ok {
+		for _, child := range children {
+			if childID, ok := child.(string); ok {
+				subtree = append(subtree, collectBlocks(blocks, childID)...)
+			}
+		}
+	}
+	return subtree
+}
+
+// clearContent deletes every top-level block of the document
+func (p *FeishuPublisher) clearContent(ctx context.Context, documentID string) error {
+	var root struct {
+		Block struct {
+			Children []string `json:"children"`
+		} `json:"block"`
+	}
+	path := "/open-apis/docx/v1/documents/" + documentID + "/blocks/" + documentID
+	if err := p.do(ctx, "GET", path, nil, &root); err != nil {
+		return err
+	}
+	if len(root.Block.Children) == 0 {
+		return nil
+	}
+
+	remove := map[string]int{"start_index": 0, "end_index": len(root.Block.Children)}
+	return p.do(ctx, "DELETE", path+"/children/batch_delete", remove, nil)
+}
+
+// tenantToken returns a cached tenant access token, refreshing it before it expires
+func (p *FeishuPublisher) tenantToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	data, err := json.Marshal(map[string]string{"app_id": p.appID, "app_secret": p.appSecret})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/open-apis/auth/v3/tenant_access_token/internal", bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("feishu token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Code              int    `json:"code"`
+		Msg               string `json:"msg"`
+		TenantAccessToken string `json:"tenant_access_token"`
+		Expire            int    `json:"expire"` // Seconds
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if result.Code != 0 {
+		return "", fmt.Errorf("feishu token error %d: %s", result.Code, result.Msg)
+	}
+
+	p.token = result.TenantAccessToken
+	p.tokenExpiry = time.Now().Add(time.Duration(result.Expire)*time.Second - time.Minute)
+	return p.token, nil
+}
+
+// do sends an authenticated request and decodes the data field of the response into out
+func (p *FeishuPublisher) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	token, err := p.tenantToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("feishu request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.Code == feishuNodeNotFound {
+		return ErrPageNotFound
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("feishu error %d: %s", result.Code, result.Msg)
+	}
+
+	if out != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			return fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package wiki
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// confluenceMarkdown renders GFM as XHTML, with code blocks and images as Confluence macros.
+// Raw HTML in the markdown is dropped, since the storage format rejects anything not well-formed.
+var confluenceMarkdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(
+		goldmarkhtml.WithXHTML(),
+		renderer.WithNodeRenderers(util.Prioritized(&confluenceRenderer{}, 100)),
+	),
+)
+
+// MarkdownToConfluenceStorage converts markdown to the Confluence storage format
+func MarkdownToConfluenceStorage(markdown string) (string, error) {
+	var buf bytes.Buffer
+	if err := confluenceMarkdown.Convert([]byte(markdown), &buf); err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// confluenceRenderer overrides the HTML output of nodes that have a Confluence macro equivalent
+type confluenceRenderer struct{}
+
+func (r *confluenceRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindImage, r.renderImage)
+}
+
+// renderCodeBlock writes a code macro, which keeps highlighting and whitespace
+func (r *confluenceRenderer) renderCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	w.WriteString(`<ac:structured-macro ac:name="code">`)
+	if fenced, ok := node.(*ast.FencedCodeBlock); ok {
+		if lang := fenced.Language(source); len(lang) > 0 {
+			fmt.Fprintf(w, `<ac:parameter ac:name="language">%s</ac:parameter>`, html.EscapeString(string(lang)))
+		}
+	}
+
+	var code strings.Builder
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		code.Write(segment.Value(source))
+	}
+	// "]]>" cannot appear inside CDATA, so split it across two sections
+	body := strings.ReplaceAll(code.String(), "]]>", "]]]]><![CDATA[>")
+	fmt.Fprintf(w, "<ac:plain-text-body><![CDATA[%s]]></ac:plain-text-body></ac:structured-macro>\n", body)
+	return ast.WalkSkipChildren, nil
+}
+
+// renderImage writes an image macro pointing at the original URL
+func (r *confluenceRenderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	image := node.(*ast.Image)
+	var alt bytes.Buffer
+	for child := image.FirstChild(); child != nil; child = child.NextSibling() {
+		if text, ok := child.(*ast.Text); ok {
+			alt.Write(text.Segment.Value(source))
+		}
+	}
+	fmt.Fprintf(w, `<ac:image ac:alt="%s"><ri:url ri:value="%s" /></ac:image>`,
+		html.EscapeString(alt.String()), html.EscapeString(string(image.Destination)))
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,55 @@
+// Package wiki publishes articles to external wikis such as Confluence and 飞书知识库
+package wiki
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/user/web3-insight/internal/config"
+	"github.com/user/web3-insight/internal/model"
+)
+
+// ErrPageNotFound is returned when updating a page that was deleted in the wiki; the caller should create it again
+var ErrPageNotFound = errors.New("wiki page not found")
+
+// RemotePage identifies a page in an external wiki
+type RemotePage struct {
+	ID  string // Confluence page ID or Feishu wiki node token
+	URL string
+}
+
+// Publisher creates and updates pages in an external wiki from markdown
+type Publisher interface {
+	Name() string
+	CreatePage(ctx context.Context, title, markdown string) (*RemotePage, error)
+	UpdatePage(ctx context.Context, id, title, markdown string) (*RemotePage, error)
+}
+
+// NewPublishersFromConfig creates a publisher for every enabled wiki, keyed by target name
+func NewPublishersFromConfig(cfg *config.WikiConfig) map[string]Publisher {
+	publishers := make(map[string]Publisher)
+	if c := cfg.Confluence; c.Enabled && c.BaseURL != "" && c.SpaceKey != "" {
+		publishers[model.WikiTargetConfluence] = NewConfluencePublisher(c)
+	}
+	if f := cfg.Feishu; f.Enabled && f.AppID != "" && f.SpaceID != "" {
+		publishers[model.WikiTargetFeishu] = NewFeishuPublisher(f)
+	}
+	return publishers
+}
+
+// Targets returns the sorted names of the configured publishers
+func Targets(publishers map[string]Publisher) []string {
+	targets := make([]string, 0, len(publishers))
+	for name := range publishers {
+		targets = append(targets, name)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: time.Minute}
+}
@@ -0,0 +1,275 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/robfig/cron/v3"
+)
+
+// Local executor defaults
+const (
+	LocalQueueName          = "local"
+	defaultLocalWorkers     = 2
+	defaultLocalQueueSize   = 1000
+	defaultLocalMaxRetry    = 3 // asynq defaults to 25, far too many for tasks held in memory
+	defaultLocalTaskTimeout = 30 * time.Minute
+)
+
+// ErrLocalQueueFull is returned when the in-process queue has no room for another task
+var ErrLocalQueueFull = errors.New("local task queue is full")
+
+// TaskEnqueuer enqueues tasks. *asynq.Client implements it, as does LocalExecutor for
+// deployments running without Redis.
+type TaskEnqueuer interface {
+	Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+}
+
+// LocalExecutor runs tasks in-process on a bounded pool of goroutines, through the same
+// handlers as the Redis worker. Pending tasks live in memory and are lost on restart.
+type LocalExecutor struct {
+	mux   *asynq.ServeMux
+	queue chan *localTask
+
+	mu     sync.Mutex
+	unique map[string]time.Time // Unique key -> lock expiry
+}
+
+// localTask is a task waiting in the in-process queue
+type localTask struct {
+	task     *asynq.Task
+	id       string
+	uniqueID string
+	maxRetry int
+	retried  int
+	timeout  time.Duration
+	deadline time.Time
+}
+
+// NewLocalExecutor starts an in-process executor with the given number of workers.
+// Call InitWorkerDependencies before enqueueing so the handlers have their services.
+func NewLocalExecutor(workers, queueSize int) *LocalExecutor {
+	if workers <= 0 {
+		workers = defaultLocalWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultLocalQueueSize
+	}
+
+	e := &LocalExecutor{
+		mux:    NewTaskMux(),
+		queue:  make(chan *localTask, queueSize),
+		unique: make(map[string]time.Time),
+	}
+	for i := 0; i < workers; i++ {
+		go e.run()
+	}
+	log.Printf("Local task executor started: workers=%d, queue=%d", workers, queueSize)
+	return e
+}
+
+// Enqueue queues a task for in-process execution. Queue options are ignored; retry, timeout,
+// deadline, unique and delay options behave as they do with asynq.
+func (e *LocalExecutor) Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	t := &localTask{
+		task:     task,
+		id:       uuid.New().String(),
+		maxRetry: defaultLocalMaxRetry,
+		timeout:  defaultLocalTaskTimeout,
+	}
+	var processAt time.Time
+	var uniqueTTL time.Duration
+	for _, opt := range opts {
+		switch opt.Type() {
+		case asynq.MaxRetryOpt:
+			t.maxRetry = opt.Value().(int)
+		case asynq.TimeoutOpt:
+			if timeout := opt.Value().(time.Duration); timeout > 0 {
+				t.timeout = timeout
+			}
+		case asynq.DeadlineOpt:
+			t.deadline = opt.Value().(time.Time)
+		case asynq.TaskIDOpt:
+			t.id = opt.Value().(string)
+		case asynq.UniqueOpt:
+			uniqueTTL = opt.Value().(time.Duration)
+		case asynq.ProcessAtOpt:
+			processAt = opt.Value().(time.Time)
+		case asynq.ProcessInOpt:
+			processAt = time.Now().Add(opt.Value().(time.Duration))
+		}
+	}
+
+	if uniqueTTL > 0 {
+		t.uniqueID = task.Type() + ":" + string(task.Payload())
+		if !e.lockUnique(t.uniqueID, uniqueTTL) {
+			return nil, asynq.ErrDuplicateTask
+		}
+	}
+
+	info := &asynq.TaskInfo{
+		ID:            t.id,
+		Queue:         LocalQueueName,
+		Type:          task.Type(),
+		Payload:       task.Payload(),
+		State:         asynq.TaskStatePending,
+		MaxRetry:      t.maxRetry,
+		Timeout:       t.timeout,
+		Deadline:      t.deadline,
+		NextProcessAt: time.Now(),
+	}
+
+	if delay := time.Until(processAt); delay > 0 {
+		info.State = asynq.TaskStateScheduled
+		info.NextProcessAt = processAt
+		time.AfterFunc(delay, func() {
+			if err := e.submit(t); err != nil {
+				log.Printf("Dropped scheduled local task %s (%s): %v", t.id, task.Type(), err)
+			}
+		})
+		return info, nil
+	}
+
+	if err := e.submit(t); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// submit hands a task to the pool without blocking the caller
+func (e *LocalExecutor) submit(t *localTask) error {
+	select {
+	case e.queue <- t:
+		return nil
+	default:
+		e.unlockUnique(t.uniqueID)
+		return fmt.Errorf("%w (%d tasks)", ErrLocalQueueFull, cap(e.queue))
+	}
+}
+
+// run processes queued tasks until the process exits
+func (e *LocalExecutor) run() {
+	for t := range e.queue {
+		e.process(t)
+	}
+}
+
+// process runs one attempt of a task and schedules a retry on failure
+func (e *LocalExecutor) process(t *localTask) {
+	deadline := time.Now().Add(t.timeout)
+	if !t.deadline.IsZero() && t.deadline.Before(deadline) {
+		deadline = t.deadline
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	err := e.processTask(ctx, t.task)
+	cancel()
+
+	if err == nil {
+		e.unlockUnique(t.uniqueID)
+		return
+	}
+	if t.retried >= t.maxRetry || errors.Is(err, asynq.SkipRetry) || errors.Is(err, asynq.RevokeTask) {
+		log.Printf("Local task %s (%s) failed after %d attempts: %v", t.id, t.task.Type(), t.retried+1, err)
+		e.unlockUnique(t.uniqueID)
+		return
+	}
+
+	t.retried++
+	delay := time.Duration(t.retried*t.retried) * 10 * time.Second
+	log.Printf("Local task %s (%s) failed, retry %d/%d in %v: %v", t.id, t.task.Type(), t.retried, t.maxRetry, delay, err)
+	time.AfterFunc(delay, func() {
+		if err := e.submit(t); err != nil {
+			log.Printf("Dropped retry of local task %s (%s): %v", t.id, t.task.Type(), err)
+		}
+	})
+}
+
+// processTask runs a handler, turning panics into errors like the asynq server does
+func (e *LocalExecutor) processTask(ctx context.Context, task *asynq.Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return e.mux.ProcessTask(ctx, task)
+}
+
+// lockUnique takes the unique lock of a task, reporting false if an identical task holds it
+func (e *LocalExecutor) lockUnique(key string, ttl time.Duration) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	if expiry, ok := e.unique[key]; ok && now.Before(expiry) {
+		return false
+	}
+	for k, expiry := range e.unique {
+		if !now.Before(expiry) {
+			delete(e.unique, k)
+		}
+	}
+	e.unique[key] = now.Add(ttl)
+	return true
+}
+
+// unlockUnique releases a unique lock once its task has finished
+func (e *LocalExecutor) unlockUnique(key string) {
+	if key == "" {
+		return
+	}
+	e.mu.Lock()
+	delete(e.unique, key)
+	e.mu.Unlock()
+}
+
+// NewLocalScheduler creates a scheduler that enqueues periodic tasks on a local executor
+// from an in-process cron, so deployments without Redis still publish scheduled articles,
+// flush views and sync sources. Research schedules are stored in Redis and do not run.
+func NewLocalScheduler(executor *LocalExecutor) *Scheduler {
+	return &Scheduler{
+		scheduler: &localPeriodic{cron: cron.New(), executor: executor},
+		client:    executor,
+	}
+}
+
+// localPeriodic runs periodic tasks with an in-process cron
+type localPeriodic struct {
+	cron     *cron.Cron
+	executor *LocalExecutor
+}
+
+// Register enqueues a task on a local executor on a cron schedule
+func (p *localPeriodic) Register(cronspec string, task *asynq.Task, opts ...asynq.Option) (string, error) {
+	id, err := p.cron.AddFunc(cronspec, func() {
+		if _, err := p.executor.Enqueue(task, opts...); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+			log.Printf("Failed to enqueue periodic task %s: %v", task.Type(), err)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(id), nil
+}
+
+// Start runs the cron in the background
+func (p *localPeriodic) Start() error {
+	p.cron.Start()
+	return nil
+}
+
+// Run runs the cron until the process exits
+func (p *localPeriodic) Run() error {
+	p.cron.Run()
+	return nil
+}
+
+// Shutdown stops the cron; running tasks finish on the executor
+func (p *localPeriodic) Shutdown() {
+	p.cron.Stop()
+}
@@ -0,0 +1,220 @@
+package worker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/config"
+)
+
+// Autoscaling recommendations
+const (
+	ScaleUp   = "scale_up"
+	ScaleDown = "scale_down"
+	ScaleHold = "hold"
+)
+
+// Default autoscaling thresholds
+const (
+	defaultScaleUpLatency        = 60  // Seconds the oldest pending task may wait
+	defaultScaleUpBacklogPerSlot = 10  // Pending tasks per worker slot
+	defaultScaleDownUtilization  = 0.3 // Fraction of busy slots below which replicas are idle
+	// minRateWindow is the shortest interval between samples used for a processing rate
+	minRateWindow = 10 * time.Second
+)
+
+// AutoscaleThresholds decide the scaling recommendation of a queue report
+type AutoscaleThresholds struct {
+	ScaleUpLatency        int     `json:"scaleUpLatencySeconds"` // Scale up when any queue's oldest pending task is older
+	ScaleUpBacklogPerSlot int     `json:"scaleUpBacklogPerSlot"` // Scale up when pending tasks per worker slot exceed this
+	ScaleDownUtilization  float64 `json:"scaleDownUtilization"`  // Scale down when nothing is pending and fewer slots are busy
+}
+
+// QueueStats is the state of one task queue
+type QueueStats struct {
+	Queue              string  `json:"queue"`
+	Pending            int     `json:"pending"`
+	Active             int     `json:"active"` // In flight
+	Scheduled          int     `json:"scheduled"`
+	Retry              int     `json:"retry"`
+	Archived           int     `json:"archived"`       // Failed permanently
+	LatencySeconds     float64 `json:"latencySeconds"` // Age of the oldest pending task
+	ProcessedToday     int     `json:"processedToday"` // Since 00:00 UTC, including failures
+	FailedToday        int     `json:"failedToday"`
+	ProcessedPerMinute float64 `json:"processedPerMinute"`
+	// DrainSeconds estimates how long the pending backlog takes at the current rate; -1 if nothing is being processed
+	DrainSeconds float64 `json:"drainSeconds"`
+	Paused       bool    `json:"paused"`
+}
+
+// WorkerPoolStats describes the running worker processes
+type WorkerPoolStats struct {
+	Servers     int     `json:"servers"`     // Worker replicas
+	Concurrency int     `json:"concurrency"` // Total worker slots
+	Busy        int     `json:"busy"`
+	Utilization float64 `json:"utilization"` // Busy / concurrency
+}
+
+// QueueReport is a snapshot of queue depths with an autoscaling recommendation
+type QueueReport struct {
+	Queues         []QueueStats        `json:"queues"`
+	Workers        WorkerPoolStats     `json:"workers"`
+	Recommendation string              `json:"recommendation"` // scale_up, scale_down or hold
+	Reason         string              `json:"reason"`
+	Thresholds     AutoscaleThresholds `json:"thresholds"`
+	Timestamp      time.Time           `json:"timestamp"`
+}
+
+// QueueMonitor reports queue depths and processing rates from the asynq inspector
+type QueueMonitor struct {
+	inspector  *asynq.Inspector
+	thresholds AutoscaleThresholds
+
+	mu      sync.Mutex
+	samples map[string]rateSample // Previous processed totals, for rates between snapshots
+}
+
+type rateSample struct {
+	processedTotal int
+	at             time.Time
+	perMinute      float64
+}
+
+// NewQueueMonitor creates a queue monitor; zero thresholds use the defaults
+func NewQueueMonitor(redisOpt asynq.RedisConnOpt, cfg config.AutoscaleConfig) *QueueMonitor {
+	thresholds := AutoscaleThresholds{
+		ScaleUpLatency:        cfg.ScaleUpLatency,
+		ScaleUpBacklogPerSlot: cfg.ScaleUpBacklogPerSlot,
+		ScaleDownUtilization:  cfg.ScaleDownUtilization,
+	}
+	if thresholds.ScaleUpLatency <= 0 {
+		thresholds.ScaleUpLatency = defaultScaleUpLatency
+	}
+	if thresholds.ScaleUpBacklogPerSlot <= 0 {
+		thresholds.ScaleUpBacklogPerSlot = defaultScaleUpBacklogPerSlot
+	}
+	if thresholds.ScaleDownUtilization <= 0 {
+		thresholds.ScaleDownUtilization = defaultScaleDownUtilization
+	}
+	return &QueueMonitor{
+		inspector:  asynq.NewInspector(redisOpt),
+		thresholds: thresholds,
+		samples:    make(map[string]rateSample),
+	}
+}
+
+// Snapshot reads every queue and the worker pool and recommends a scaling action
+func (m *QueueMonitor) Snapshot() (*QueueReport, error) {
+	names, err := m.inspector.Queues()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queues: %w", err)
+	}
+	sort.Strings(names)
+
+	report := &QueueReport{Thresholds: m.thresholds, Timestamp: time.Now()}
+	for _, name := range names {
+		info, err := m.inspector.GetQueueInfo(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read queue %s: %w", name, err)
+		}
+
+		stats := QueueStats{
+			Queue:              info.Queue,
+			Pending:            info.Pending,
+			Active:             info.Active,
+			Scheduled:          info.Scheduled,
+			Retry:              info.Retry,
+			Archived:           info.Archived,
+			LatencySeconds:     info.Latency.Seconds(),
+			ProcessedToday:     info.Processed,
+			FailedToday:        info.Failed,
+			ProcessedPerMinute: m.processingRate(info),
+			DrainSeconds:       -1,
+			Paused:             info.Paused,
+		}
+		if stats.Pending == 0 {
+			stats.DrainSeconds = 0
+		} else if stats.ProcessedPerMinute > 0 {
+			stats.DrainSeconds = math.Round(float64(stats.Pending) / stats.ProcessedPerMinute * 60)
+		}
+		report.Queues = append(report.Queues, stats)
+	}
+
+	servers, err := m.inspector.Servers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker servers: %w", err)
+	}
+	for _, s := range servers {
+		report.Workers.Servers++
+		report.Workers.Concurrency += s.Concurrency
+		report.Workers.Busy += len(s.ActiveWorkers)
+	}
+	if report.Workers.Concurrency > 0 {
+		report.Workers.Utilization = float64(report.Workers.Busy) / float64(report.Workers.Concurrency)
+	}
+
+	report.Recommendation, report.Reason = m.recommend(report)
+	return report, nil
+}
+
+// processingRate returns tasks processed per minute since the previous snapshot.
+// Without a recent sample it falls back to today's average.
+func (m *QueueMonitor) processingRate(info *asynq.QueueInfo) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prev, ok := m.samples[info.Queue]
+	elapsed := info.Timestamp.Sub(prev.at)
+	if ok && elapsed < minRateWindow {
+		return prev.perMinute
+	}
+
+	var rate float64
+	if ok && info.ProcessedTotal >= prev.processedTotal {
+		rate = float64(info.ProcessedTotal-prev.processedTotal) / elapsed.Minutes()
+	} else {
+		now := info.Timestamp.UTC()
+		sinceMidnight := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+		if sinceMidnight > 0 {
+			rate = float64(info.Processed) / sinceMidnight.Minutes()
+		}
+	}
+	rate = math.Round(rate*100) / 100
+
+	m.samples[info.Queue] = rateSample{processedTotal: info.ProcessedTotal, at: info.Timestamp, perMinute: rate}
+	return rate
+}
+
+// recommend applies the thresholds to a report
+func (m *QueueMonitor) recommend(report *QueueReport) (string, string) {
+	pending := 0
+	for _, q := range report.Queues {
+		if q.Paused {
+			continue
+		}
+		pending += q.Pending
+		if q.LatencySeconds > float64(m.thresholds.ScaleUpLatency) {
+			return ScaleUp, fmt.Sprintf("oldest pending task in %s has waited %.0fs (threshold %ds)", q.Queue, q.LatencySeconds, m.thresholds.ScaleUpLatency)
+		}
+	}
+
+	if report.Workers.Servers == 0 {
+		if pending > 0 {
+			return ScaleUp, fmt.Sprintf("%d pending tasks and no running workers", pending)
+		}
+		return ScaleHold, "no pending tasks and no running workers"
+	}
+
+	if perSlot := float64(pending) / float64(report.Workers.Concurrency); perSlot > float64(m.thresholds.ScaleUpBacklogPerSlot) {
+		return ScaleUp, fmt.Sprintf("%.1f pending tasks per worker slot (threshold %d)", perSlot, m.thresholds.ScaleUpBacklogPerSlot)
+	}
+
+	if pending == 0 && report.Workers.Servers > 1 && report.Workers.Utilization < m.thresholds.ScaleDownUtilization {
+		return ScaleDown, fmt.Sprintf("no pending tasks and %.0f%% of worker slots busy (threshold %.0f%%)", report.Workers.Utilization*100, m.thresholds.ScaleDownUtilization*100)
+	}
+	return ScaleHold, "backlog within thresholds"
+}
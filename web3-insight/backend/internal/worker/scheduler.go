@@ -1,15 +1,28 @@
 package worker
 
 import (
+	"io"
 	"log"
+	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/repository"
+	"gorm.io/gorm"
 )
 
+// periodicScheduler enqueues registered tasks on their cron schedules. *asynq.Scheduler
+// implements it, as does localPeriodic for deployments running without Redis.
+type periodicScheduler interface {
+	Register(cronspec string, task *asynq.Task, opts ...asynq.Option) (string, error)
+	Start() error
+	Run() error
+	Shutdown()
+}
+
 // Scheduler manages periodic task scheduling
 type Scheduler struct {
-	scheduler *asynq.Scheduler
-	client    *asynq.Client
+	scheduler periodicScheduler
+	client    TaskEnqueuer
 }
 
 // NewScheduler creates a new task scheduler
@@ -24,18 +37,139 @@ func NewScheduler(redisOpt asynq.RedisClientOpt) *Scheduler {
 func (s *Scheduler) RegisterTasks() error {
 	var err error
 
-	// RSS sync every hour
-	task, _ := NewRSSSyncTask(RSSSyncPayload{})
-	_, err = s.scheduler.Register("0 * * * *", task, asynq.Queue("default"))
+	// Sync sources whose fetch interval has elapsed, for every registered collector type
+	task, _ := NewSourceSyncTask(SourceSyncPayload{})
+	_, err = s.scheduler.Register("*/15 * * * *", task, asynq.Queue("default"))
+	if err != nil {
+		log.Printf("Failed to register source sync task: %v", err)
+		return err
+	}
+	log.Println("Registered source sync task: every 15 minutes")
+
+	// Summarize pending news items that were not picked up on ingest
+	task, _ = NewSummarizeTask(SummarizePayload{BatchSize: defaultSummarizeBatchSize})
+	_, err = s.scheduler.Register("*/15 * * * *", task, asynq.Queue("default"))
 	if err != nil {
-		log.Printf("Failed to register RSS sync task: %v", err)
+		log.Printf("Failed to register summarize task: %v", err)
 		return err
 	}
-	log.Println("Registered RSS sync task: every hour")
+	log.Println("Registered summarize task: every 15 minutes")
+
+	// Embed new news items and group near-duplicates from different feeds into stories
+	task, _ = NewNewsClusterTask(NewsClusterPayload{})
+	_, err = s.scheduler.Register("*/10 * * * *", task, asynq.Queue("low"), asynq.MaxRetry(1), asynq.Unique(10*time.Minute))
+	if err != nil {
+		log.Printf("Failed to register news cluster task: %v", err)
+		return err
+	}
+	log.Println("Registered news cluster task: every 10 minutes")
+
+	// Rank the topics of the last week of news; content generation picks suggested topics from them
+	task, _ = NewNewsTrendingTask(NewsTrendingPayload{})
+	_, err = s.scheduler.Register("50 * * * *", task, asynq.Queue("low"), asynq.MaxRetry(1), asynq.Unique(time.Hour))
+	if err != nil {
+		log.Printf("Failed to register news trending task: %v", err)
+		return err
+	}
+	log.Println("Registered news trending task: hourly at :50")
+
+	// Flush buffered article views to the database every minute
+	_, err = s.scheduler.Register("* * * * *", NewViewFlushTask(), asynq.Queue("low"), asynq.Unique(time.Minute))
+	if err != nil {
+		log.Printf("Failed to register view flush task: %v", err)
+		return err
+	}
+	log.Println("Registered view flush task: every minute")
+
+	// Publish scheduled articles whose publish time has passed every minute
+	_, err = s.scheduler.Register("* * * * *", NewScheduledPublishTask(), asynq.Queue("default"), asynq.Unique(time.Minute))
+	if err != nil {
+		log.Printf("Failed to register scheduled publish task: %v", err)
+		return err
+	}
+	log.Println("Registered scheduled publish task: every minute")
+
+	// Sync article and news changes to the search engine every minute; a no-op without one
+	_, err = s.scheduler.Register("* * * * *", NewSearchSyncTask(), asynq.Queue("low"), asynq.Unique(time.Minute))
+	if err != nil {
+		log.Printf("Failed to register search sync task: %v", err)
+		return err
+	}
+	log.Println("Registered search sync task: every minute")
+
+	// Delete audited LLM calls past their retention period once a day
+	_, err = s.scheduler.Register("30 3 * * *", NewLLMCallCleanupTask(), asynq.Queue("low"))
+	if err != nil {
+		log.Printf("Failed to register LLM call cleanup task: %v", err)
+		return err
+	}
+	log.Println("Registered LLM call cleanup task: daily at 03:30")
+
+	// Delete the validators of URLs no longer fetched once a day
+	_, err = s.scheduler.Register("45 3 * * *", NewFetchCleanupTask(), asynq.Queue("low"))
+	if err != nil {
+		log.Printf("Failed to register fetch validator cleanup task: %v", err)
+		return err
+	}
+	log.Println("Registered fetch validator cleanup task: daily at 03:45")
+
+	// Recount today's and yesterday's source reliability stats
+	task, _ = NewSourceStatsTask(SourceStatsPayload{})
+	_, err = s.scheduler.Register("55 * * * *", task, asynq.Queue("low"), asynq.MaxRetry(1), asynq.Unique(time.Hour))
+	if err != nil {
+		log.Printf("Failed to register source stats task: %v", err)
+		return err
+	}
+	log.Println("Registered source stats task: hourly at :55")
+
+	// Check stored content for broken invariants once a night
+	task, _ = NewConsistencyCheckTask(ConsistencyCheckPayload{})
+	_, err = s.scheduler.Register("0 4 * * *", task, asynq.Queue("low"), asynq.MaxRetry(1))
+	if err != nil {
+		log.Printf("Failed to register consistency check task: %v", err)
+		return err
+	}
+	log.Println("Registered consistency check task: daily at 04:00")
+
+	// Describe categories the classifier created without a description or icon
+	task, _ = NewCategoryEnrichTask(CategoryEnrichPayload{Limit: defaultCategoryEnrichLimit})
+	_, err = s.scheduler.Register("45 * * * *", task, asynq.Queue("low"), asynq.MaxRetry(1), asynq.Unique(time.Hour))
+	if err != nil {
+		log.Printf("Failed to register category enrich task: %v", err)
+		return err
+	}
+	log.Println("Registered category enrich task: hourly at :45")
+
+	// Look for categories the classifier created as synonyms of existing ones once a night
+	task, _ = NewCategoryDedupTask(CategoryDedupPayload{})
+	_, err = s.scheduler.Register("15 4 * * *", task, asynq.Queue("low"), asynq.MaxRetry(1))
+	if err != nil {
+		log.Printf("Failed to register category dedup task: %v", err)
+		return err
+	}
+	log.Println("Registered category dedup task: daily at 04:15")
+
+	// Refetch crawled pages not checked for a week, recording changed ones as updates
+	task, _ = NewWebRecrawlTask(WebRecrawlPayload{})
+	_, err = s.scheduler.Register("20 * * * *", task, asynq.Queue("low"), asynq.MaxRetry(0), asynq.Timeout(time.Hour), asynq.Unique(time.Hour))
+	if err != nil {
+		log.Printf("Failed to register re-crawl task: %v", err)
+		return err
+	}
+	log.Println("Registered re-crawl task: hourly at :20")
+
+	// Crawl the pages of feed items that came with only a summary
+	task, _ = NewContentBackfillTask(ContentBackfillPayload{})
+	_, err = s.scheduler.Register("35 * * * *", task, asynq.Queue("low"), asynq.MaxRetry(0), asynq.Timeout(time.Hour), asynq.Unique(time.Hour))
+	if err != nil {
+		log.Printf("Failed to register content backfill task: %v", err)
+		return err
+	}
+	log.Println("Registered content backfill task: hourly at :35")
 
 	// Content generation every 6 hours (for suggested topics)
 	task, _ = NewContentGenerateTask(ContentGeneratePayload{
-		Topic: "suggested",
+		Topic: SuggestedTopic,
 		Style: "auto",
 	})
 	_, err = s.scheduler.Register("0 */6 * * *", task, asynq.Queue("low"))
@@ -48,16 +182,64 @@ func (s *Scheduler) RegisterTasks() error {
 	return nil
 }
 
-// Run starts the scheduler
+// researchScheduleProvider supplies enabled research schedules to the periodic task manager
+type researchScheduleProvider struct {
+	repo *repository.ResearchScheduleRepository
+}
+
+// GetConfigs returns one periodic task per enabled research schedule
+func (p *researchScheduleProvider) GetConfigs() ([]*asynq.PeriodicTaskConfig, error) {
+	schedules, err := p.repo.ListEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*asynq.PeriodicTaskConfig, 0, len(schedules))
+	for _, schedule := range schedules {
+		task, err := NewResearchScheduleTask(ResearchSchedulePayload{ScheduleID: schedule.ID.String()})
+		if err != nil {
+			log.Printf("Failed to create task for research schedule %s: %v", schedule.ID, err)
+			continue
+		}
+		configs = append(configs, &asynq.PeriodicTaskConfig{
+			Cronspec: schedule.CronExpr,
+			Task:     task,
+			Opts:     []asynq.Option{asynq.Queue("low")},
+		})
+	}
+	return configs, nil
+}
+
+// NewResearchScheduleManager creates a periodic task manager that keeps research
+// schedules stored in the database in sync with the cron scheduler. Cron specs are
+// evaluated in loc.
+func NewResearchScheduleManager(redisOpt asynq.RedisClientOpt, database *gorm.DB, loc *time.Location) (*asynq.PeriodicTaskManager, error) {
+	return asynq.NewPeriodicTaskManager(asynq.PeriodicTaskManagerOpts{
+		SchedulerOpts:              &asynq.SchedulerOpts{Location: loc},
+		RedisConnOpt:               redisOpt,
+		PeriodicTaskConfigProvider: &researchScheduleProvider{repo: repository.NewResearchScheduleRepository(database)},
+		SyncInterval:               time.Minute,
+	})
+}
+
+// Run starts the scheduler and blocks until the process is asked to stop
 func (s *Scheduler) Run() error {
 	log.Println("Scheduler starting...")
 	return s.scheduler.Run()
 }
 
+// Start starts the scheduler in the background
+func (s *Scheduler) Start() error {
+	log.Println("Scheduler starting...")
+	return s.scheduler.Start()
+}
+
 // Stop stops the scheduler
 func (s *Scheduler) Stop() {
 	s.scheduler.Shutdown()
-	s.client.Close()
+	if closer, ok := s.client.(io.Closer); ok {
+		closer.Close()
+	}
 }
 
 // EnqueueTask enqueues a task for immediate processing
@@ -90,37 +272,219 @@ func (s *Scheduler) EnqueueRSSSync(feedURL, categoryID string) (*asynq.TaskInfo,
 	return s.client.Enqueue(task, asynq.Queue("default"))
 }
 
+// EnqueueSourceSync enqueues a sync of one data source
+func (s *Scheduler) EnqueueSourceSync(sourceID string) (*asynq.TaskInfo, error) {
+	task, err := NewSourceSyncTask(SourceSyncPayload{SourceID: sourceID})
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Enqueue(task, asynq.Queue("default"))
+}
+
+// EnqueueSummarize enqueues a summarize task for one news item, or a batch when newsID is empty
+func (s *Scheduler) EnqueueSummarize(newsID string) (*asynq.TaskInfo, error) {
+	task, err := NewSummarizeTask(SummarizePayload{NewsID: newsID})
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Enqueue(task, asynq.Queue("default"))
+}
+
 // EnqueueWebCrawl enqueues a web crawl task
 func (s *Scheduler) EnqueueWebCrawl(url, categoryID string, depth int) (*asynq.TaskInfo, error) {
-	task, err := NewWebCrawlTask(WebCrawlPayload{
+	return EnqueueWebCrawl(s.client, WebCrawlPayload{
 		URL:        url,
 		CategoryID: categoryID,
 		Depth:      depth,
 	})
+}
+
+// EnqueueWebCrawl enqueues a web crawl task. Multi-page crawls wait out each domain's rate
+// limit between pages, so they get a long timeout and no retries.
+func EnqueueWebCrawl(client TaskEnqueuer, payload WebCrawlPayload) (*asynq.TaskInfo, error) {
+	task, err := NewWebCrawlTask(payload)
 	if err != nil {
 		return nil, err
 	}
-	return s.client.Enqueue(task, asynq.Queue("default"))
+	if payload.Depth > 0 {
+		return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(0), asynq.Timeout(8*time.Hour))
+	}
+	return client.Enqueue(task, asynq.Queue("default"))
 }
 
 // EnqueueClassify enqueues a classification task
-func (s *Scheduler) EnqueueClassify(articleID string) (*asynq.TaskInfo, error) {
+func EnqueueClassify(client TaskEnqueuer, articleID string) (*asynq.TaskInfo, error) {
 	task, err := NewClassifyTask(ClassifyPayload{
 		ArticleID: articleID,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return s.client.Enqueue(task, asynq.Queue("default"))
+	return client.Enqueue(task, asynq.Queue("default"))
+}
+
+// EnqueueClassify enqueues a classification task
+func (s *Scheduler) EnqueueClassify(articleID string) (*asynq.TaskInfo, error) {
+	return EnqueueClassify(s.client, articleID)
+}
+
+// EnqueueSummarize enqueues summarization of a single news item
+func EnqueueSummarize(client TaskEnqueuer, newsID string) (*asynq.TaskInfo, error) {
+	task, err := NewSummarizeTask(SummarizePayload{NewsID: newsID})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("default"))
+}
+
+// EnqueueNewsPipeline enqueues the news-to-article pipeline for one news item, or for the
+// pending items when the payload has no news ID, on the low-priority queue
+func EnqueueNewsPipeline(client TaskEnqueuer, payload NewsPipelinePayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	task, err := NewNewsPipelineTask(payload)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]asynq.Option{asynq.Queue("low"), asynq.MaxRetry(2), asynq.Timeout(30 * time.Minute)}, opts...)
+	return client.Enqueue(task, opts...)
+}
+
+// EnqueueTranslate enqueues a full-content translation of a news item on the low-priority
+// queue. Long items take one LLM call per few thousand characters.
+func EnqueueTranslate(client TaskEnqueuer, newsID string) (*asynq.TaskInfo, error) {
+	task, err := NewTranslateTask(TranslatePayload{NewsID: newsID})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(2), asynq.Timeout(30*time.Minute))
 }
 
 // EnqueueEmbedding enqueues an embedding generation task
-func (s *Scheduler) EnqueueEmbedding(articleID string) (*asynq.TaskInfo, error) {
+func EnqueueEmbedding(client TaskEnqueuer, articleID string) (*asynq.TaskInfo, error) {
 	task, err := NewEmbeddingTask(EmbeddingPayload{
 		ArticleID: articleID,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return s.client.Enqueue(task, asynq.Queue("default"))
+	return client.Enqueue(task, asynq.Queue("default"))
+}
+
+// EnqueueEmbedding enqueues an embedding generation task
+func (s *Scheduler) EnqueueEmbedding(articleID string) (*asynq.TaskInfo, error) {
+	return EnqueueEmbedding(s.client, articleID)
+}
+
+// EnqueuePrerequisites enqueues a prerequisite suggestion task on the low-priority queue
+func EnqueuePrerequisites(client TaskEnqueuer, articleID string) (*asynq.TaskInfo, error) {
+	task, err := NewPrerequisitesTask(PrerequisitesPayload{
+		ArticleID: articleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(2), asynq.Unique(10*time.Minute))
+}
+
+// EnqueueGlossary enqueues a glossary term extraction task on the low-priority queue
+func EnqueueGlossary(client TaskEnqueuer, articleID string) (*asynq.TaskInfo, error) {
+	task, err := NewGlossaryTask(GlossaryPayload{
+		ArticleID: articleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(2), asynq.Unique(10*time.Minute))
+}
+
+// EnqueueConceptLinks enqueues a concept link extraction task on the low-priority queue
+func EnqueueConceptLinks(client TaskEnqueuer, articleID string) (*asynq.TaskInfo, error) {
+	task, err := NewConceptLinksTask(ConceptLinksPayload{
+		ArticleID: articleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(2), asynq.Unique(10*time.Minute))
+}
+
+// EnqueueWikiExport enqueues a wiki export task on the low-priority queue
+func EnqueueWikiExport(client TaskEnqueuer, payload WikiExportPayload) (*asynq.TaskInfo, error) {
+	task, err := NewWikiExportTask(payload)
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(2))
+}
+
+// EnqueueConsistencyCheck enqueues a consistency check on the low-priority queue.
+// A nil autoFix uses the configured default.
+func EnqueueConsistencyCheck(client TaskEnqueuer, autoFix *bool) (*asynq.TaskInfo, error) {
+	task, err := NewConsistencyCheckTask(ConsistencyCheckPayload{AutoFix: autoFix})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(1), asynq.Unique(10*time.Minute))
+}
+
+// EnqueueEmbeddingReindex enqueues a re-index of all article embeddings on the low-priority
+// queue. Retries resume where the previous attempt stopped.
+func EnqueueEmbeddingReindex(client TaskEnqueuer, taskID string, batchSize int) (*asynq.TaskInfo, error) {
+	task, err := NewEmbeddingReindexTask(EmbeddingReindexPayload{TaskID: taskID, BatchSize: batchSize})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("low"), asynq.MaxRetry(3), asynq.Timeout(12*time.Hour), asynq.TaskID("embedding-reindex:"+taskID))
+}
+
+// EnqueueImport enqueues an article import. Retries resume after the last imported article.
+func EnqueueImport(client TaskEnqueuer, taskID string) (*asynq.TaskInfo, error) {
+	task, err := NewImportTask(ImportPayload{TaskID: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task, asynq.Queue("default"), asynq.MaxRetry(3), asynq.Timeout(2*time.Hour), asynq.TaskID("import:"+taskID))
+}
+
+// ArticleTaskEnqueuer enqueues article processing tasks with a plain client or a local executor,
+// for use by service.ArticleHooks outside the scheduler
+type ArticleTaskEnqueuer struct {
+	client TaskEnqueuer
+}
+
+// NewArticleTaskEnqueuer creates an article task enqueuer
+func NewArticleTaskEnqueuer(client TaskEnqueuer) *ArticleTaskEnqueuer {
+	return &ArticleTaskEnqueuer{client: client}
+}
+
+// EnqueueClassify enqueues a classification task
+func (e *ArticleTaskEnqueuer) EnqueueClassify(articleID string) (*asynq.TaskInfo, error) {
+	return EnqueueClassify(e.client, articleID)
+}
+
+// EnqueueEmbedding enqueues an embedding generation task
+func (e *ArticleTaskEnqueuer) EnqueueEmbedding(articleID string) (*asynq.TaskInfo, error) {
+	return EnqueueEmbedding(e.client, articleID)
+}
+
+// EnqueueSourceBackfill enqueues one page of a backfill series on the low-priority queue,
+// delayed by the payload's politeness interval (except for the first page)
+func EnqueueSourceBackfill(client TaskEnqueuer, payload SourceBackfillPayload) (*asynq.TaskInfo, error) {
+	if payload.Page <= 0 {
+		payload.Page = 1
+	}
+	task, err := NewSourceBackfillTask(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []asynq.Option{asynq.Queue("low"), asynq.MaxRetry(2)}
+	if payload.Page > 1 && payload.DelaySeconds > 0 {
+		opts = append(opts, asynq.ProcessIn(time.Duration(payload.DelaySeconds)*time.Second))
+	}
+	return client.Enqueue(task, opts...)
+}
+
+// EnqueueSourceBackfill starts a backfill series for a data source
+func (s *Scheduler) EnqueueSourceBackfill(payload SourceBackfillPayload) (*asynq.TaskInfo, error) {
+	return EnqueueSourceBackfill(s.client, payload)
 }
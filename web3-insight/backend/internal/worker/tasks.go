@@ -3,34 +3,101 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
+	"github.com/user/web3-insight/internal/cache"
 	"github.com/user/web3-insight/internal/collector"
 	"github.com/user/web3-insight/internal/config"
 	"github.com/user/web3-insight/internal/llm"
 	"github.com/user/web3-insight/internal/model"
 	"github.com/user/web3-insight/internal/repository"
 	"github.com/user/web3-insight/internal/service"
+	"github.com/user/web3-insight/internal/wiki"
 	"gorm.io/gorm"
 )
 
 // Task type constants
 const (
-	TaskTypeContentGenerate = "content:generate"
-	TaskTypeRSSSync         = "rss:sync"
-	TaskTypeWebCrawl        = "web:crawl"
-	TaskTypeClassify        = "content:classify"
-	TaskTypeEmbedding       = "content:embedding"
+	TaskTypeContentGenerate  = "content:generate"
+	TaskTypeRSSSync          = "rss:sync"
+	TaskTypeWebCrawl         = "web:crawl"
+	TaskTypeWebRecrawl       = "web:recrawl"
+	TaskTypeContentBackfill  = "news:backfill_content"
+	TaskTypeClassify         = "content:classify"
+	TaskTypeEmbedding        = "content:embedding"
+	TaskTypeTranslate        = "content:translate"
+	TaskTypeResearchSchedule = "research:schedule"
+	TaskTypeSourceBackfill   = "source:backfill"
+	TaskTypeSourceSync       = "source:sync"
+	TaskTypeSourceStats      = "source:stats"
+	TaskTypeSummarize        = "news:summarize"
+	TaskTypeNewsPipeline     = "news:pipeline"
+	TaskTypeNewsCluster      = "news:cluster"
+	TaskTypeNewsTrending     = "news:trending"
+	TaskTypeViewFlush        = "article:views:flush"
+	TaskTypeLLMCallCleanup   = "llm:calls:cleanup"
+	TaskTypePrerequisites    = "content:prerequisites"
+	TaskTypeGlossary         = "content:glossary"
+	TaskTypeConceptLinks     = "content:links"
+	TaskTypeCategoryEnrich   = "maintenance:category_enrich"
+	TaskTypeCategoryDedup    = "maintenance:category_duplicates"
+	TaskTypeWikiExport       = "wiki:export"
+	TaskTypeConsistencyCheck = "maintenance:consistency"
+	TaskTypeSearchSync       = "search:sync"
+	TaskTypeEmbeddingReindex = "embedding:reindex"
+	TaskTypeScheduledPublish = "article:publish:scheduled"
+	TaskTypeImport           = "content:import"
+	TaskTypeFetchCleanup     = "maintenance:fetch_validators"
 )
 
+// SuggestedTopic as the topic of a content generation task generates an article on the top
+// trending topic no article covers yet
+const SuggestedTopic = "suggested"
+
+// defaultSummarizeBatchSize is used when a batch summarize task has no batch size
+const defaultSummarizeBatchSize = 20
+
+// defaultPipelineBatchSize and defaultPipelineMaxAge are used when the news pipeline config
+// leaves them unset
+const (
+	defaultPipelineBatchSize = 10
+	defaultPipelineMaxAge    = 72 * time.Hour
+)
+
+// defaultNewsClusterLimit is used when a news clustering task has no limit
+const defaultNewsClusterLimit = 100
+
+// defaultRecrawlLimit and defaultRecrawlMinAge are used when a re-crawl task leaves them unset
+const (
+	defaultRecrawlLimit  = 20
+	defaultRecrawlMinAge = 7 * 24 * time.Hour
+)
+
+// defaultContentBackfillLimit and defaultContentBackfillMaxAge are used when a content
+// backfill task leaves them unset
+const (
+	defaultContentBackfillLimit  = 20
+	defaultContentBackfillMaxAge = 3 * 24 * time.Hour
+)
+
+// defaultSourceStatsDays is used when a source stats task has no days; yesterday is
+// recounted so that it is complete
+const defaultSourceStatsDays = 2
+
+// defaultCategoryEnrichLimit is used when a category enrichment task has no limit
+const defaultCategoryEnrichLimit = 20
+
 // ContentGeneratePayload represents the payload for content generation tasks
 type ContentGeneratePayload struct {
 	Topic      string `json:"topic"`
 	CategoryID string `json:"categoryId"`
 	Style      string `json:"style,omitempty"`
+	Quality    string `json:"quality,omitempty"` // "high" picks the best of several candidates
 }
 
 // RSSSyncPayload represents the payload for RSS sync tasks
@@ -41,9 +108,28 @@ type RSSSyncPayload struct {
 
 // WebCrawlPayload represents the payload for web crawl tasks
 type WebCrawlPayload struct {
-	URL        string `json:"url"`
-	CategoryID string `json:"categoryId,omitempty"`
-	Depth      int    `json:"depth,omitempty"`
+	URL        string   `json:"url"`
+	CategoryID string   `json:"categoryId,omitempty"`
+	Depth      int      `json:"depth,omitempty"`    // Link hops to follow within the URL's domain; 0 crawls the URL alone
+	Include    []string `json:"include,omitempty"`  // Regexes; when set, followed URLs must match one
+	Exclude    []string `json:"exclude,omitempty"`  // Regexes of URLs not to follow
+	MaxPages   int      `json:"maxPages,omitempty"` // Pages fetched by a multi-page crawl (default 50, at most 500)
+	SourceName string   `json:"sourceName,omitempty"`
+}
+
+// WebRecrawlPayload represents the payload for re-crawling stored pages to find changes
+type WebRecrawlPayload struct {
+	Limit           int `json:"limit,omitempty"`           // Pages checked per run
+	MinAgeHours     int `json:"minAgeHours,omitempty"`     // Pages checked more recently are skipped
+	MinChangedChars int `json:"minChangedChars,omitempty"` // Smaller changes are not reported
+}
+
+// ContentBackfillPayload represents the payload for crawling the source pages of RSS items
+// that hold only a summary
+type ContentBackfillPayload struct {
+	Limit       int `json:"limit,omitempty"`       // Pages crawled per run
+	MinChars    int `json:"minChars,omitempty"`    // Items with less content are filled in (default 500)
+	MaxAgeHours int `json:"maxAgeHours,omitempty"` // Older items are left alone
 }
 
 // ClassifyPayload represents the payload for content classification tasks
@@ -56,33 +142,260 @@ type EmbeddingPayload struct {
 	ArticleID string `json:"articleId"`
 }
 
+// PrerequisitesPayload represents the payload for prerequisite suggestion tasks
+type PrerequisitesPayload struct {
+	ArticleID string `json:"articleId"`
+}
+
+// GlossaryPayload represents the payload for glossary term extraction tasks
+type GlossaryPayload struct {
+	ArticleID string `json:"articleId"`
+}
+
+// ConceptLinksPayload represents the payload for concept link extraction tasks
+type ConceptLinksPayload struct {
+	ArticleID string `json:"articleId"`
+}
+
+// CategoryEnrichPayload represents the payload for filling in blank auto-created categories
+type CategoryEnrichPayload struct {
+	Limit int `json:"limit,omitempty"` // Categories enriched per run
+}
+
+// CategoryDedupPayload represents the payload for duplicate category detection
+type CategoryDedupPayload struct {
+	Threshold float64 `json:"threshold,omitempty"` // Minimum cosine similarity; the service default when 0
+}
+
+// WikiExportPayload represents the payload for exporting articles to an external wiki
+type WikiExportPayload struct {
+	Target               string   `json:"target"`
+	ArticleIDs           []string `json:"articleIds,omitempty"`
+	CategoryID           string   `json:"categoryId,omitempty"`
+	IncludeSubcategories bool     `json:"includeSubcategories,omitempty"`
+	Force                bool     `json:"force,omitempty"`
+}
+
+// ResearchSchedulePayload represents the payload for scheduled research tasks
+type ResearchSchedulePayload struct {
+	ScheduleID string `json:"scheduleId"`
+}
+
+// SourceBackfillPayload represents one page of an onboarding backfill task series
+type SourceBackfillPayload struct {
+	SourceID        string `json:"sourceId"`
+	PageURL         string `json:"pageUrl,omitempty"` // Empty for the first page (the source URL)
+	Page            int    `json:"page"`
+	MaxPages        int    `json:"maxPages"`
+	ArticlesPerPage int    `json:"articlesPerPage,omitempty"`
+	DelaySeconds    int    `json:"delaySeconds"`
+	PrevFirstURL    string `json:"prevFirstUrl,omitempty"`
+}
+
+// ConsistencyCheckPayload represents the payload for consistency check tasks.
+// AutoFix overrides worker.consistency.auto_fix when set.
+type ConsistencyCheckPayload struct {
+	AutoFix *bool `json:"autoFix,omitempty"`
+}
+
+// EmbeddingReindexPayload represents the payload for embedding re-index tasks.
+// TaskID is the task record that tracks progress.
+type EmbeddingReindexPayload struct {
+	TaskID    string `json:"taskId"`
+	BatchSize int    `json:"batchSize,omitempty"`
+}
+
+// ImportPayload represents the payload for article import tasks.
+// TaskID is the task record holding the batch and its progress.
+type ImportPayload struct {
+	TaskID string `json:"taskId"`
+}
+
+// SourceSyncPayload represents the payload for data source sync tasks.
+// An empty SourceID syncs every source of Type, or every due source when Type is empty too.
+type SourceSyncPayload struct {
+	SourceID string `json:"sourceId,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// SourceStatsPayload represents the payload for recounting data source reliability stats
+type SourceStatsPayload struct {
+	Days int `json:"days,omitempty"` // Days recounted, counting today (default 2)
+}
+
+// SummarizePayload represents the payload for news summarization tasks.
+// An empty NewsID summarizes the oldest pending items, up to BatchSize.
+type SummarizePayload struct {
+	NewsID    string `json:"newsId,omitempty"`
+	BatchSize int    `json:"batchSize,omitempty"`
+}
+
+// NewsPipelinePayload represents the payload for news-to-article pipeline tasks. Without a
+// news ID, the pending summarized items are processed.
+type NewsPipelinePayload struct {
+	NewsID string `json:"newsId,omitempty"`
+}
+
+// TranslatePayload represents the payload for full-content news translation tasks
+type TranslatePayload struct {
+	NewsID string `json:"newsId"`
+}
+
+// NewsClusterPayload represents the payload for embedding news items and grouping
+// near-duplicates into stories
+type NewsClusterPayload struct {
+	Limit     int     `json:"limit,omitempty"`     // Items embedded per run
+	Threshold float64 `json:"threshold,omitempty"` // Minimum cosine similarity; the service default when 0
+}
+
+// NewsTrendingPayload represents the payload for trending topic detection
+type NewsTrendingPayload struct {
+	Days  int `json:"days,omitempty"`  // News analyzed, in days back from now
+	Limit int `json:"limit,omitempty"` // Topics kept
+}
+
 // Global variables for dependency injection
 var (
 	rssCollector     *collector.RSSCollector
 	webCrawler       *collector.WebCrawler
+	collectors       *collector.Registry
 	embeddingService *service.EmbeddingService
 	classifier       *service.Classifier
+	prerequisites    *service.PrerequisiteService
+	glossary         *service.GlossaryService
+	conceptLinks     *service.ConceptLinkService
+	categoryEnricher *service.CategoryEnricher
+	categoryDeduper  *service.CategoryDeduper
+	wikiExport       *service.WikiExportService
+	consistency      *service.ConsistencyChecker
+	consistencyFix   bool
+	searchIndexer    *service.SearchIndexer
+	reindexer        *service.EmbeddingReindexer
+	importRunner     *service.ImportJobRunner
+	summarizer       *service.Summarizer
+	translator       *service.Translator
+	newsPipeline     *service.NewsPipeline
+	newsClusterer    *service.NewsClusterer
+	trendDetector    *service.TrendDetector
+	contentGenerator *service.Generator
+	pipelineConfig   config.NewsPipelineConfig
+	viewCounter      *service.ViewCounter
+	publisher        *service.ScheduledPublisher
+	llmCallRepo      *repository.LLMCallRepository
+	sourceStatsRepo  *repository.SourceStatsRepository
+	researchService  *service.ResearchService
+	scheduleRepo     *repository.ResearchScheduleRepository
+	backfiller       *collector.Backfiller
+	fetchCache       *collector.FetchCache
+	taskClient       TaskEnqueuer
 	db               *gorm.DB
 	llmConfig        *config.LLMConfig
 )
 
-// InitWorkerDependencies initializes worker dependencies
-func InitWorkerDependencies(database *gorm.DB, cfg *config.LLMConfig) {
+// InitWorkerDependencies initializes worker dependencies.
+// Call InitTaskClient first so articles created by tasks enter the post-create pipeline.
+func InitWorkerDependencies(database *gorm.DB, cfg *config.Config) {
 	db = database
-	llmConfig = cfg
+	llmConfig = &cfg.LLM
+	searchCfg := &cfg.Search
 
 	newsRepo := repository.NewNewsRepository(db)
 	dsRepo := repository.NewDataSourceRepository(db)
 	articleRepo := repository.NewArticleRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
+	// The worker creates categories while classifying; invalidate the servers' cached tree
+	if !cfg.Worker.Local() {
+		categoryRepo.SetCache(cache.NewFromConfig(&cfg.Redis))
+	}
 
 	// Initialize LLM router for services that need it
-	llmRouter := llm.NewRouterFromConfig(cfg)
+	llmRouter := llm.NewRouterFromConfig(&cfg.LLM)
+	llmRouter.SetCache(llm.NewResponseCacheFromConfig(&cfg.LLM.Cache, &cfg.Redis))
+	llmRouter.SetBudget(llm.NewBudgetTrackerFromConfig(&cfg.LLM.Budget, &cfg.Redis))
+	llmCallRepo = repository.NewLLMCallRepository(db)
+	sourceStatsRepo = repository.NewSourceStatsRepository(db)
+	llmRouter.SetCallLogger(service.NewLLMCallLoggerFromConfig(llmCallRepo, &cfg.LLM.Audit))
 
-	rssCollector = collector.NewRSSCollector(newsRepo, dsRepo)
-	webCrawler = collector.NewWebCrawler(newsRepo)
-	embeddingService = service.NewEmbeddingService(articleRepo, cfg)
+	fetchCache = collector.NewFetchCache(repository.NewFetchValidatorRepository(db))
+	rssCollector = collector.NewRSSCollector(newsRepo, dsRepo, fetchCache)
+	webCrawler = collector.NewWebCrawler(newsRepo, repository.NewTaskRepository(db), fetchCache)
+	webCrawler.SetCrawlRules(repository.NewCrawlRuleRepository(db))
+	webCrawler.SetPDFExtractor(collector.NewPDFExtractorFromConfig(&cfg.Collector))
+	webCrawler.SetArchiveFetcher(collector.NewArchiveFetcherFromConfig(&cfg.Collector))
+	collectors = collector.NewDefaultRegistry(rssCollector, webCrawler, newsRepo, dsRepo, &cfg.Collector)
+	backfiller = collector.NewBackfiller(rssCollector, webCrawler, newsRepo, dsRepo)
+	embeddingService = service.NewEmbeddingService(articleRepo, &cfg.LLM)
+	embeddingService.SetChunkIndexer(service.NewChunkIndexer(repository.NewArticleChunkRepository(db), llm.NewEmbeddingAdapterFromConfig(&cfg.LLM)))
+	if cfg.Worker.Local() {
+		viewCounter = service.NewDirectViewCounter(articleRepo)
+	} else {
+		viewCounter = service.NewViewCounterFromConfig(&cfg.Redis, articleRepo)
+	}
+	usageRecorder := service.NewUsageRecorder(repository.NewTaskRepository(db))
 	classifier = service.NewClassifier(llmRouter, articleRepo, categoryRepo)
+	classifier.SetUsageRecorder(usageRecorder)
+	prerequisites = service.NewPrerequisiteService(llmRouter, articleRepo)
+	prerequisites.SetUsageRecorder(usageRecorder)
+	glossary = service.NewGlossaryService(llmRouter, articleRepo, repository.NewTermRepository(db))
+	glossary.SetUsageRecorder(usageRecorder)
+	conceptLinks = service.NewConceptLinkService(llmRouter, articleRepo)
+	conceptLinks.SetUsageRecorder(usageRecorder)
+	categoryEnricher = service.NewCategoryEnricher(llmRouter, categoryRepo, articleRepo)
+	categoryEnricher.SetUsageRecorder(usageRecorder)
+	newsClusterer = service.NewNewsClusterer(newsRepo, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM))
+	trendDetector = service.NewTrendDetector(newsRepo, repository.NewTrendingTopicRepository(db), articleRepo)
+	categoryDeduper = service.NewCategoryDeduper(categoryRepo, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM))
+	wikiExport = service.NewWikiExportService(articleRepo, categoryRepo, repository.NewWikiPageRepository(db), wiki.NewPublishersFromConfig(&cfg.Wiki))
+	consistency = service.NewConsistencyChecker(db, repository.NewConsistencyReportRepository(db), categoryRepo, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM).Dimensions())
+	consistencyFix = cfg.Worker.Consistency.AutoFix
+	searchIndexer = service.NewSearchIndexerFromConfig(&cfg.Search.Engine, db)
+	reindexer = service.NewEmbeddingReindexer(db, llm.NewEmbeddingAdapterFromConfig(&cfg.LLM), repository.NewTaskRepository(db))
+	summarizer = service.NewSummarizer(llmRouter, newsRepo)
+	summarizer.SetUsageRecorder(usageRecorder)
+	// Sources can turn relevance scoring on even when it is off by default
+	relevance := service.NewRelevanceScorer(llmRouter, newsRepo, dsRepo, cfg.Worker.Relevance)
+	relevance.SetUsageRecorder(usageRecorder)
+	summarizer.SetRelevanceScorer(relevance)
+	translator = service.NewTranslator(llmRouter, newsRepo)
+	translator.SetUsageRecorder(usageRecorder)
+
+	searchRouter := collector.NewSearchRouter(
+		collector.NewTavilyProvider(searchCfg.Tavily.APIKey, searchCfg.Tavily.Enabled),
+		collector.NewSerpAPIProvider(searchCfg.SerpAPI.APIKey, searchCfg.SerpAPI.Enabled),
+	)
+	var articleHooks *service.ArticleHooks
+	if taskClient != nil {
+		articleHooks = service.NewArticleHooks(NewArticleTaskEnqueuer(taskClient))
+	}
+	generator := service.NewGenerator(llmRouter, articleRepo, newsRepo, classifier)
+	generator.SetUsageRecorder(usageRecorder)
+	generator.SetArticleHooks(articleHooks)
+	generator.SetExperiments(service.NewExperimentService(repository.NewExperimentRepository(db)))
+	prompts := service.NewPromptStore(repository.NewPromptTemplateRepository(db), cfg.LLM.Persona)
+	generator.SetPromptStore(prompts)
+	contentGenerator = generator
+	scheduleRepo = repository.NewResearchScheduleRepository(db)
+	// The pipeline classifies and embeds the articles it writes itself, so its generator has no hooks
+	pipelineGenerator := service.NewGenerator(llmRouter, articleRepo, newsRepo, nil)
+	pipelineGenerator.SetUsageRecorder(usageRecorder)
+	pipelineGenerator.SetExperiments(service.NewExperimentService(repository.NewExperimentRepository(db)))
+	pipelineGenerator.SetPromptStore(prompts)
+	newsPipeline = service.NewNewsPipeline(llmRouter, newsRepo, articleRepo, repository.NewTaskRepository(db),
+		summarizer, service.NewSemanticSearchService(articleRepo, &cfg.LLM), pipelineGenerator, classifier, embeddingService)
+	pipelineConfig = cfg.Worker.NewsPipeline
+	researchService = service.NewResearchService(llmRouter, articleRepo, searchRouter, generator, repository.NewResearchSessionRepository(db))
+	researchService.SetUsageRecorder(usageRecorder)
+	researchService.SetArticleHooks(articleHooks)
+	researchService.SetPromptStore(prompts)
+	publisher = service.NewScheduledPublisher(articleRepo, articleHooks)
+	importer := service.NewArticleImporter(articleRepo, categoryRepo)
+	importer.SetArticleHooks(articleHooks)
+	importRunner = service.NewImportJobRunner(importer, repository.NewTaskRepository(db))
+}
+
+// InitTaskClient sets the client used by handlers that enqueue follow-up tasks
+func InitTaskClient(client TaskEnqueuer) {
+	taskClient = client
 }
 
 // NewTaskMux creates and configures the task multiplexer
@@ -93,8 +406,33 @@ func NewTaskMux() *asynq.ServeMux {
 	mux.HandleFunc(TaskTypeContentGenerate, handleContentGenerate)
 	mux.HandleFunc(TaskTypeRSSSync, handleRSSSync)
 	mux.HandleFunc(TaskTypeWebCrawl, handleWebCrawl)
+	mux.HandleFunc(TaskTypeWebRecrawl, handleWebRecrawl)
+	mux.HandleFunc(TaskTypeContentBackfill, handleContentBackfill)
 	mux.HandleFunc(TaskTypeClassify, handleClassify)
 	mux.HandleFunc(TaskTypeEmbedding, handleEmbedding)
+	mux.HandleFunc(TaskTypeResearchSchedule, handleResearchSchedule)
+	mux.HandleFunc(TaskTypeSourceBackfill, handleSourceBackfill)
+	mux.HandleFunc(TaskTypeSourceSync, handleSourceSync)
+	mux.HandleFunc(TaskTypeSourceStats, handleSourceStats)
+	mux.HandleFunc(TaskTypeSummarize, handleSummarize)
+	mux.HandleFunc(TaskTypeNewsPipeline, handleNewsPipeline)
+	mux.HandleFunc(TaskTypeNewsCluster, handleNewsCluster)
+	mux.HandleFunc(TaskTypeNewsTrending, handleNewsTrending)
+	mux.HandleFunc(TaskTypeTranslate, handleTranslate)
+	mux.HandleFunc(TaskTypeViewFlush, handleViewFlush)
+	mux.HandleFunc(TaskTypeLLMCallCleanup, handleLLMCallCleanup)
+	mux.HandleFunc(TaskTypeFetchCleanup, handleFetchCleanup)
+	mux.HandleFunc(TaskTypePrerequisites, handlePrerequisites)
+	mux.HandleFunc(TaskTypeGlossary, handleGlossary)
+	mux.HandleFunc(TaskTypeConceptLinks, handleConceptLinks)
+	mux.HandleFunc(TaskTypeWikiExport, handleWikiExport)
+	mux.HandleFunc(TaskTypeConsistencyCheck, handleConsistencyCheck)
+	mux.HandleFunc(TaskTypeCategoryEnrich, handleCategoryEnrich)
+	mux.HandleFunc(TaskTypeCategoryDedup, handleCategoryDedup)
+	mux.HandleFunc(TaskTypeSearchSync, handleSearchSync)
+	mux.HandleFunc(TaskTypeEmbeddingReindex, handleEmbeddingReindex)
+	mux.HandleFunc(TaskTypeScheduledPublish, handleScheduledPublish)
+	mux.HandleFunc(TaskTypeImport, handleImport)
 
 	return mux
 }
@@ -126,6 +464,24 @@ func NewWebCrawlTask(payload WebCrawlPayload) (*asynq.Task, error) {
 	return asynq.NewTask(TaskTypeWebCrawl, data), nil
 }
 
+// NewWebRecrawlTask creates a task that re-crawls stored pages to find changes
+func NewWebRecrawlTask(payload WebRecrawlPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeWebRecrawl, data), nil
+}
+
+// NewContentBackfillTask creates a task that fills in RSS items holding only a summary
+func NewContentBackfillTask(payload ContentBackfillPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeContentBackfill, data), nil
+}
+
 // NewClassifyTask creates a new classification task
 func NewClassifyTask(payload ClassifyPayload) (*asynq.Task, error) {
 	data, err := json.Marshal(payload)
@@ -135,6 +491,42 @@ func NewClassifyTask(payload ClassifyPayload) (*asynq.Task, error) {
 	return asynq.NewTask(TaskTypeClassify, data), nil
 }
 
+// NewPrerequisitesTask creates a new prerequisite suggestion task
+func NewPrerequisitesTask(payload PrerequisitesPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypePrerequisites, data), nil
+}
+
+// NewGlossaryTask creates a new glossary term extraction task
+func NewGlossaryTask(payload GlossaryPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeGlossary, data), nil
+}
+
+// NewConceptLinksTask creates a new concept link extraction task
+func NewConceptLinksTask(payload ConceptLinksPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeConceptLinks, data), nil
+}
+
+// NewWikiExportTask creates a new wiki export task
+func NewWikiExportTask(payload WikiExportPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeWikiExport, data), nil
+}
+
 // NewEmbeddingTask creates a new embedding generation task
 func NewEmbeddingTask(payload EmbeddingPayload) (*asynq.Task, error) {
 	data, err := json.Marshal(payload)
@@ -144,6 +536,157 @@ func NewEmbeddingTask(payload EmbeddingPayload) (*asynq.Task, error) {
 	return asynq.NewTask(TaskTypeEmbedding, data), nil
 }
 
+// NewResearchScheduleTask creates a new scheduled research task
+func NewResearchScheduleTask(payload ResearchSchedulePayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeResearchSchedule, data), nil
+}
+
+// NewSourceBackfillTask creates a new source backfill task
+func NewSourceBackfillTask(payload SourceBackfillPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeSourceBackfill, data), nil
+}
+
+// NewSourceSyncTask creates a new data source sync task
+func NewSourceSyncTask(payload SourceSyncPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeSourceSync, data), nil
+}
+
+// NewSourceStatsTask creates a task that recounts data source reliability stats
+func NewSourceStatsTask(payload SourceStatsPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeSourceStats, data), nil
+}
+
+// NewSummarizeTask creates a new news summarization task
+func NewSummarizeTask(payload SummarizePayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeSummarize, data), nil
+}
+
+// NewNewsClusterTask creates a news clustering task
+func NewNewsClusterTask(payload NewsClusterPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeNewsCluster, data), nil
+}
+
+// NewNewsTrendingTask creates a trending topic detection task
+func NewNewsTrendingTask(payload NewsTrendingPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeNewsTrending, data), nil
+}
+
+// NewTranslateTask creates a full-content news translation task
+func NewTranslateTask(payload TranslatePayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeTranslate, data), nil
+}
+
+// NewNewsPipelineTask creates a news-to-article pipeline task
+func NewNewsPipelineTask(payload NewsPipelinePayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeNewsPipeline, data), nil
+}
+
+// NewViewFlushTask creates a task that flushes buffered article views
+func NewViewFlushTask() *asynq.Task {
+	return asynq.NewTask(TaskTypeViewFlush, nil)
+}
+
+// NewScheduledPublishTask creates a task that publishes scheduled articles whose time has come
+func NewScheduledPublishTask() *asynq.Task {
+	return asynq.NewTask(TaskTypeScheduledPublish, nil)
+}
+
+// NewLLMCallCleanupTask creates a task that deletes audited LLM calls past their retention
+func NewLLMCallCleanupTask() *asynq.Task {
+	return asynq.NewTask(TaskTypeLLMCallCleanup, nil)
+}
+
+// NewFetchCleanupTask creates a task that deletes the validators of URLs no longer fetched
+func NewFetchCleanupTask() *asynq.Task {
+	return asynq.NewTask(TaskTypeFetchCleanup, nil)
+}
+
+// NewConsistencyCheckTask creates a consistency check task
+func NewConsistencyCheckTask(payload ConsistencyCheckPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeConsistencyCheck, data), nil
+}
+
+// NewCategoryEnrichTask creates a task that fills in blank auto-created categories
+func NewCategoryEnrichTask(payload CategoryEnrichPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeCategoryEnrich, data), nil
+}
+
+// NewCategoryDedupTask creates a duplicate category detection task
+func NewCategoryDedupTask(payload CategoryDedupPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeCategoryDedup, data), nil
+}
+
+// NewSearchSyncTask creates a task that syncs changed articles and news to the search engine
+func NewSearchSyncTask() *asynq.Task {
+	return asynq.NewTask(TaskTypeSearchSync, nil)
+}
+
+// NewEmbeddingReindexTask creates an embedding re-index task
+func NewEmbeddingReindexTask(payload EmbeddingReindexPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeEmbeddingReindex, data), nil
+}
+
+// NewImportTask creates an article import task
+func NewImportTask(payload ImportPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(TaskTypeImport, data), nil
+}
+
 // handleContentGenerate handles content generation tasks
 func handleContentGenerate(ctx context.Context, t *asynq.Task) error {
 	var payload ContentGeneratePayload
@@ -153,11 +696,54 @@ func handleContentGenerate(ctx context.Context, t *asynq.Task) error {
 
 	log.Printf("Processing content generation task: topic=%s, categoryId=%s", payload.Topic, payload.CategoryID)
 
-	// TODO: Implement in Phase 2
-	// 1. Use LLM router to generate content
-	// 2. Save to database
-	// 3. Trigger embedding generation
+	if contentGenerator == nil {
+		return fmt.Errorf("generator not initialized")
+	}
+
+	req := &service.GenerationRequest{
+		Topic:   payload.Topic,
+		Style:   payload.Style,
+		Quality: payload.Quality,
+	}
+	if payload.CategoryID != "" {
+		categoryID, err := uuid.Parse(payload.CategoryID)
+		if err != nil {
+			return fmt.Errorf("invalid category ID: %w", err)
+		}
+		req.CategoryID = &categoryID
+	}
+
+	// Suggested topics come from the trending news the knowledge base doesn't cover yet
+	var trend *model.TrendingTopic
+	if payload.Topic == SuggestedTopic {
+		if trendDetector == nil {
+			return fmt.Errorf("trend detector not initialized")
+		}
+		var err error
+		if trend, err = trendDetector.NextArticleTopic(); err != nil {
+			return fmt.Errorf("failed to pick a trending topic: %w", err)
+		}
+		if trend == nil {
+			log.Printf("No trending topic without an article; skipping generation")
+			return nil
+		}
+		req.Topic = trend.Topic
+		req.References = trendDetector.References(trend)
+		// Nobody asked for this article, so an editor reviews it before it is published
+		req.Status = model.ArticleStatusInReview
+	}
+
+	result, err := contentGenerator.GenerateArticle(ctx, req)
+	if err != nil {
+		return fmt.Errorf("content generation failed: %w", err)
+	}
+	if trend != nil {
+		if err := trendDetector.MarkGenerated(trend, result.Article.ID); err != nil {
+			log.Printf("Failed to link trending topic %s to article %s: %v", trend.Topic, result.Article.ID, err)
+		}
+	}
 
+	log.Printf("Generated article %q on %s (model: %s)", result.Article.Title, req.Topic, result.ModelUsed)
 	return nil
 }
 
@@ -170,8 +756,8 @@ func handleRSSSync(ctx context.Context, t *asynq.Task) error {
 
 	log.Printf("Processing RSS sync task: feedUrl=%s", payload.FeedURL)
 
-	if rssCollector == nil {
-		return fmt.Errorf("RSS collector not initialized")
+	if collectors == nil {
+		return fmt.Errorf("collector registry not initialized")
 	}
 
 	// If specific feed URL provided, find the source by URL
@@ -184,7 +770,10 @@ func handleRSSSync(ctx context.Context, t *asynq.Task) error {
 
 		for _, source := range sources {
 			if source.URL == payload.FeedURL {
-				_, err := rssCollector.Collect(ctx, source.ID)
+				result, err := collectors.SyncSource(ctx, &source)
+				if result != nil && result.ItemsNew > 0 {
+					enqueueSummarizeBatch()
+				}
 				return err
 			}
 		}
@@ -192,10 +781,55 @@ func handleRSSSync(ctx context.Context, t *asynq.Task) error {
 	}
 
 	// If no specific URL, sync all enabled RSS sources
-	_, err := rssCollector.CollectAll(ctx)
+	results, err := collectors.SyncType(ctx, model.DataSourceTypeRSS)
+	if countNewItems(results) > 0 {
+		enqueueSummarizeBatch()
+	}
 	return err
 }
 
+// handleSourceSync collects data sources of any registered type through the collector registry
+func handleSourceSync(ctx context.Context, t *asynq.Task) error {
+	var payload SourceSyncPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if collectors == nil {
+		return fmt.Errorf("collector registry not initialized")
+	}
+
+	if payload.SourceID != "" {
+		sourceID, err := uuid.Parse(payload.SourceID)
+		if err != nil {
+			return fmt.Errorf("invalid source ID: %w", err)
+		}
+		log.Printf("Processing source sync task: sourceId=%s", sourceID)
+		result, err := collectors.Sync(ctx, sourceID)
+		if result != nil && result.ItemsNew > 0 {
+			enqueueSummarizeBatch()
+		}
+		return err
+	}
+
+	var results []*collector.CollectResult
+	var err error
+	if payload.Type != "" {
+		results, err = collectors.SyncType(ctx, payload.Type)
+	} else {
+		results, err = collectors.SyncDue(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if countNewItems(results) > 0 {
+		enqueueSummarizeBatch()
+	}
+
+	log.Printf("Source sync completed: type=%q, sources=%d", payload.Type, len(results))
+	return nil
+}
+
 // handleWebCrawl handles web crawling tasks
 func handleWebCrawl(ctx context.Context, t *asynq.Task) error {
 	var payload WebCrawlPayload
@@ -209,29 +843,135 @@ func handleWebCrawl(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("web crawler not initialized")
 	}
 
+	sourceName := payload.SourceName
+	if sourceName == "" {
+		sourceName = "manual"
+	}
+
+	// Pages crawled for a source obey its filters like the ones it collects itself
+	var filter *collector.ItemFilter
+	if source, err := repository.NewDataSourceRepository(db).FindByName(sourceName); err == nil {
+		if filter, err = collector.ParseItemFilter(source.Config); err != nil {
+			log.Printf("Warning: ignoring invalid filters for %s: %v", source.Name, err)
+		}
+	}
+
+	if payload.Depth > 0 {
+		scope, err := collector.NewSiteCrawlScope(payload.Depth, payload.Include, payload.Exclude, payload.MaxPages)
+		if err != nil {
+			return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+		}
+		result, err := webCrawler.CrawlSite(ctx, payload.URL, sourceName, scope, filter)
+		if result != nil {
+			log.Printf("Site crawl completed for %s: crawled=%d, new=%d, filtered=%d, failed=%d",
+				payload.URL, result.PagesCrawled, result.ItemsNew, result.PagesFiltered, result.PagesFailed)
+			if result.ItemsNew > 0 {
+				enqueueSummarizeBatch()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("site crawl failed: %w", err)
+		}
+		return nil
+	}
+
 	// Crawl and save
-	_, err := webCrawler.CrawlAndSave(ctx, payload.URL, "manual")
+	item, err := webCrawler.CrawlAndSaveFiltered(ctx, payload.URL, sourceName, filter)
+	if errors.Is(err, collector.ErrItemFiltered) {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("crawl failed: %w", err)
 	}
+	if item != nil && !item.Processed {
+		enqueueSummarize(SummarizePayload{NewsID: item.ID.String()})
+	}
 
 	return nil
 }
 
-// handleClassify handles content classification tasks
-func handleClassify(ctx context.Context, t *asynq.Task) error {
-	var payload ClassifyPayload
+// handleWebRecrawl refetches the crawled pages checked longest ago and records the ones that
+// changed as "updated" news items
+func handleWebRecrawl(ctx context.Context, t *asynq.Task) error {
+	var payload WebRecrawlPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
-	log.Printf("Processing classification task: articleId=%s", payload.ArticleID)
+	if webCrawler == nil {
+		return fmt.Errorf("web crawler not initialized")
+	}
 
-	if classifier == nil {
-		return fmt.Errorf("classifier not initialized")
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = defaultRecrawlLimit
+	}
+	minAge := defaultRecrawlMinAge
+	if payload.MinAgeHours > 0 {
+		minAge = time.Duration(payload.MinAgeHours) * time.Hour
 	}
 
-	articleID, err := uuid.Parse(payload.ArticleID)
+	result, err := webCrawler.RecrawlStale(ctx, time.Now().Add(-minAge), limit, payload.MinChangedChars)
+	if err != nil {
+		return fmt.Errorf("re-crawl failed: %w", err)
+	}
+
+	log.Printf("Re-crawl completed: checked=%d, unchanged=%d, updated=%d, failed=%d",
+		result.Checked, result.Unchanged, result.Updated, result.Failed)
+	if result.Updated > 0 {
+		enqueueSummarizeBatch()
+	}
+	return nil
+}
+
+// handleContentBackfill crawls the source pages of recent RSS items with thin content and
+// stores the full article text
+func handleContentBackfill(ctx context.Context, t *asynq.Task) error {
+	var payload ContentBackfillPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if webCrawler == nil {
+		return fmt.Errorf("web crawler not initialized")
+	}
+
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = defaultContentBackfillLimit
+	}
+	maxAge := defaultContentBackfillMaxAge
+	if payload.MaxAgeHours > 0 {
+		maxAge = time.Duration(payload.MaxAgeHours) * time.Hour
+	}
+
+	result, err := webCrawler.BackfillThinContent(ctx, time.Now().Add(-maxAge), payload.MinChars, limit)
+	if err != nil {
+		return fmt.Errorf("content backfill failed: %w", err)
+	}
+
+	log.Printf("Content backfill completed: checked=%d, filled=%d, thin=%d, blocked=%d, failed=%d, deferred=%d",
+		result.Checked, result.Filled, result.Thin, result.Blocked, result.Failed, result.Deferred)
+	if result.Filled > 0 {
+		enqueueSummarizeBatch()
+	}
+	return nil
+}
+
+// handleClassify handles content classification tasks
+func handleClassify(ctx context.Context, t *asynq.Task) error {
+	var payload ClassifyPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing classification task: articleId=%s", payload.ArticleID)
+
+	if classifier == nil {
+		return fmt.Errorf("classifier not initialized")
+	}
+
+	articleID, err := uuid.Parse(payload.ArticleID)
 	if err != nil {
 		return fmt.Errorf("invalid article ID: %w", err)
 	}
@@ -267,5 +1007,706 @@ func handleEmbedding(ctx context.Context, t *asynq.Task) error {
 	}
 
 	log.Printf("Embedding generated for article: %s", payload.ArticleID)
+	enqueueEmbeddingFollowUps(payload.ArticleID)
+	return nil
+}
+
+// enqueueEmbeddingFollowUps enqueues the tasks that need an article's embedding: prerequisite
+// suggestions, glossary terms and concept links pick candidates by embedding similarity
+func enqueueEmbeddingFollowUps(articleID string) {
+	if taskClient == nil {
+		return
+	}
+	if _, err := EnqueuePrerequisites(taskClient, articleID); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		log.Printf("Failed to enqueue prerequisites task: %v", err)
+	}
+	if _, err := EnqueueGlossary(taskClient, articleID); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		log.Printf("Failed to enqueue glossary task: %v", err)
+	}
+	if _, err := EnqueueConceptLinks(taskClient, articleID); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		log.Printf("Failed to enqueue concept links task: %v", err)
+	}
+}
+
+// handlePrerequisites suggests prerequisites of an article with the LLM
+func handlePrerequisites(ctx context.Context, t *asynq.Task) error {
+	var payload PrerequisitesPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing prerequisites task: articleId=%s", payload.ArticleID)
+
+	if prerequisites == nil {
+		return fmt.Errorf("prerequisite service not initialized")
+	}
+
+	articleID, err := uuid.Parse(payload.ArticleID)
+	if err != nil {
+		return fmt.Errorf("invalid article ID: %w", err)
+	}
+
+	suggested, err := prerequisites.SuggestAndUpdate(ctx, articleID)
+	if err != nil {
+		return fmt.Errorf("prerequisite suggestion failed: %w", err)
+	}
+
+	log.Printf("Article %s has %d prerequisites", payload.ArticleID, len(suggested))
+	return nil
+}
+
+// handleGlossary extracts glossary terms from an article with the LLM
+func handleGlossary(ctx context.Context, t *asynq.Task) error {
+	var payload GlossaryPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing glossary task: articleId=%s", payload.ArticleID)
+
+	if glossary == nil {
+		return fmt.Errorf("glossary service not initialized")
+	}
+
+	articleID, err := uuid.Parse(payload.ArticleID)
+	if err != nil {
+		return fmt.Errorf("invalid article ID: %w", err)
+	}
+
+	terms, err := glossary.ExtractAndSave(ctx, articleID)
+	if err != nil {
+		return fmt.Errorf("glossary extraction failed: %w", err)
+	}
+
+	log.Printf("Article %s has %d glossary terms", payload.ArticleID, len(terms))
+	return nil
+}
+
+// handleConceptLinks extracts concept graph links of an article with the LLM
+func handleConceptLinks(ctx context.Context, t *asynq.Task) error {
+	var payload ConceptLinksPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing concept links task: articleId=%s", payload.ArticleID)
+
+	if conceptLinks == nil {
+		return fmt.Errorf("concept link service not initialized")
+	}
+
+	articleID, err := uuid.Parse(payload.ArticleID)
+	if err != nil {
+		return fmt.Errorf("invalid article ID: %w", err)
+	}
+
+	links, err := conceptLinks.ExtractAndUpdate(ctx, articleID)
+	if err != nil {
+		return fmt.Errorf("concept link extraction failed: %w", err)
+	}
+
+	log.Printf("Article %s has %d concept links", payload.ArticleID, len(links))
+	return nil
+}
+
+// handleWikiExport exports articles to an external wiki. Articles that fail are logged and
+// skipped; re-running the task retries them without duplicating pages.
+func handleWikiExport(ctx context.Context, t *asynq.Task) error {
+	var payload WikiExportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing wiki export task: target=%s, articles=%d, categoryId=%s", payload.Target, len(payload.ArticleIDs), payload.CategoryID)
+
+	if wikiExport == nil {
+		return fmt.Errorf("wiki export service not initialized")
+	}
+
+	req := service.WikiExportRequest{
+		Target:               payload.Target,
+		IncludeSubcategories: payload.IncludeSubcategories,
+		Force:                payload.Force,
+	}
+	for _, raw := range payload.ArticleIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid article ID: %w", err)
+		}
+		req.ArticleIDs = append(req.ArticleIDs, id)
+	}
+	if payload.CategoryID != "" {
+		id, err := uuid.Parse(payload.CategoryID)
+		if err != nil {
+			return fmt.Errorf("invalid category ID: %w", err)
+		}
+		req.CategoryID = &id
+	}
+
+	if _, err := wikiExport.Export(ctx, req); err != nil {
+		return fmt.Errorf("wiki export failed: %w", err)
+	}
+	return nil
+}
+
+// handleResearchSchedule handles scheduled research tasks
+func handleResearchSchedule(ctx context.Context, t *asynq.Task) error {
+	var payload ResearchSchedulePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing scheduled research task: scheduleId=%s", payload.ScheduleID)
+
+	if researchService == nil || scheduleRepo == nil {
+		return fmt.Errorf("research service not initialized")
+	}
+
+	scheduleID, err := uuid.Parse(payload.ScheduleID)
+	if err != nil {
+		return fmt.Errorf("invalid schedule ID: %w", err)
+	}
+
+	schedule, err := scheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		return fmt.Errorf("research schedule not found: %w", err)
+	}
+	if !schedule.Enabled {
+		log.Printf("Research schedule %s is disabled, skipping", schedule.ID)
+		return nil
+	}
+
+	resp, runErr := researchService.RunSchedule(ctx, schedule)
+	var sessionID *uuid.UUID
+	if resp != nil {
+		sessionID = resp.SessionID
+	}
+	if err := scheduleRepo.RecordRun(schedule.ID, sessionID, runErr); err != nil {
+		log.Printf("Failed to record run for research schedule %s: %v", schedule.ID, err)
+	}
+	if runErr != nil {
+		return fmt.Errorf("scheduled research failed: %w", runErr)
+	}
+
+	log.Printf("Scheduled research completed: schedule=%s", schedule.ID)
+	return nil
+}
+
+// handleSourceBackfill ingests one historical page and enqueues the next one after a delay
+func handleSourceBackfill(ctx context.Context, t *asynq.Task) error {
+	var payload SourceBackfillPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing source backfill task: sourceId=%s, page=%d/%d", payload.SourceID, payload.Page, payload.MaxPages)
+
+	if backfiller == nil {
+		return fmt.Errorf("backfiller not initialized")
+	}
+
+	sourceID, err := uuid.Parse(payload.SourceID)
+	if err != nil {
+		return fmt.Errorf("invalid source ID: %w", err)
+	}
+
+	result, err := backfiller.BackfillPage(ctx, sourceID, payload.PageURL, payload.Page, payload.ArticlesPerPage, payload.PrevFirstURL)
+	if err != nil {
+		return fmt.Errorf("backfill failed: %w", err)
+	}
+	if result.ItemsNew > 0 {
+		enqueueSummarizeBatch()
+	}
+
+	if result.NextURL == "" || result.ItemsFound == 0 || result.FirstURL == payload.PrevFirstURL || payload.Page >= payload.MaxPages {
+		log.Printf("Source backfill finished: sourceId=%s, pages=%d", payload.SourceID, payload.Page)
+		return nil
+	}
+
+	if taskClient == nil {
+		return fmt.Errorf("task client not initialized, cannot continue backfill")
+	}
+
+	next := payload
+	next.PageURL = result.NextURL
+	next.Page = payload.Page + 1
+	next.PrevFirstURL = result.FirstURL
+	if _, err := EnqueueSourceBackfill(taskClient, next); err != nil {
+		return fmt.Errorf("failed to enqueue next backfill page: %w", err)
+	}
+
+	return nil
+}
+
+// handleSummarize summarizes one news item, or a batch of pending items
+func handleSummarize(ctx context.Context, t *asynq.Task) error {
+	var payload SummarizePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if summarizer == nil {
+		return fmt.Errorf("summarizer not initialized")
+	}
+
+	if payload.NewsID != "" {
+		log.Printf("Processing summarize task: newsId=%s", payload.NewsID)
+
+		newsID, err := uuid.Parse(payload.NewsID)
+		if err != nil {
+			return fmt.Errorf("invalid news ID: %w", err)
+		}
+
+		if err := summarizer.SummarizeByID(ctx, newsID); err != nil {
+			if errors.Is(err, service.ErrSummaryAttemptsExhausted) || errors.Is(err, service.ErrNewsFiltered) {
+				log.Printf("Skipping news %s: %v", newsID, err)
+				return nil
+			}
+			return fmt.Errorf("summarization failed: %w", err)
+		}
+		if pipelineConfig.Enabled {
+			enqueueNewsPipeline(NewsPipelinePayload{NewsID: payload.NewsID})
+		}
+		return nil
+	}
+
+	batchSize := payload.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSummarizeBatchSize
+	}
+
+	processed, err := summarizer.ProcessUnprocessedNews(ctx, batchSize)
+	if err != nil {
+		return fmt.Errorf("batch summarization failed: %w", err)
+	}
+
+	log.Printf("Batch summarization completed: processed=%d", processed)
+	if processed > 0 && pipelineConfig.Enabled {
+		enqueueNewsPipeline(NewsPipelinePayload{}, asynq.Unique(time.Minute))
+	}
+	return nil
+}
+
+// handleNewsPipeline turns a summarized news item, or the pending ones, into new or updated
+// articles
+func handleNewsPipeline(ctx context.Context, t *asynq.Task) error {
+	var payload NewsPipelinePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if newsPipeline == nil {
+		return fmt.Errorf("news pipeline not initialized")
+	}
+
+	if payload.NewsID != "" {
+		log.Printf("Processing news pipeline task: newsId=%s", payload.NewsID)
+
+		newsID, err := uuid.Parse(payload.NewsID)
+		if err != nil {
+			return fmt.Errorf("invalid news ID: %w", err)
+		}
+
+		result, err := newsPipeline.Process(ctx, newsID)
+		if err != nil {
+			if errors.Is(err, service.ErrNewsPipelineDone) {
+				log.Printf("Skipping news %s: %v", newsID, err)
+				return nil
+			}
+			return fmt.Errorf("news pipeline failed: %w", err)
+		}
+		finishNewsPipeline(result)
+		return nil
+	}
+
+	batchSize := pipelineConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPipelineBatchSize
+	}
+	maxAge := defaultPipelineMaxAge
+	if pipelineConfig.MaxAgeHours > 0 {
+		maxAge = time.Duration(pipelineConfig.MaxAgeHours) * time.Hour
+	}
+
+	results, failed, err := newsPipeline.ProcessPending(ctx, time.Now().Add(-maxAge), batchSize)
+	for _, result := range results {
+		finishNewsPipeline(result)
+	}
+	if err != nil {
+		return fmt.Errorf("batch news pipeline failed: %w", err)
+	}
+
+	log.Printf("Batch news pipeline completed: processed=%d, failed=%d", len(results), failed)
+	return nil
+}
+
+// handleNewsCluster embeds new news items and groups near-duplicates from different feeds
+// into stories
+func handleNewsCluster(ctx context.Context, t *asynq.Task) error {
+	var payload NewsClusterPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if newsClusterer == nil {
+		return fmt.Errorf("news clusterer not initialized")
+	}
+
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = defaultNewsClusterLimit
+	}
+
+	result, err := newsClusterer.ClusterNew(ctx, payload.Threshold, limit)
+	if err != nil {
+		return fmt.Errorf("news clustering failed: %w", err)
+	}
+
+	if result.Embedded > 0 {
+		log.Printf("News clustering completed: embedded=%d, clustered=%d, newStories=%d",
+			result.Embedded, result.Clustered, result.StoriesCreated)
+	}
+	return nil
+}
+
+// handleNewsTrending ranks the topics of recent news and replaces the trending topic list
+func handleNewsTrending(ctx context.Context, t *asynq.Task) error {
+	var payload NewsTrendingPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if trendDetector == nil {
+		return fmt.Errorf("trend detector not initialized")
+	}
+
+	topics, err := trendDetector.Detect(ctx, payload.Days, payload.Limit)
+	if err != nil {
+		return fmt.Errorf("trending topic detection failed: %w", err)
+	}
+
+	log.Printf("Found %d trending topics", len(topics))
+	return nil
+}
+
+// handleTranslate translates the full content of a news item into Chinese
+func handleTranslate(ctx context.Context, t *asynq.Task) error {
+	var payload TranslatePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	log.Printf("Processing translate task: newsId=%s", payload.NewsID)
+
+	if translator == nil {
+		return fmt.Errorf("translator not initialized")
+	}
+
+	newsID, err := uuid.Parse(payload.NewsID)
+	if err != nil {
+		return fmt.Errorf("invalid news ID: %w", err)
+	}
+
+	translation, err := translator.TranslateNews(ctx, newsID)
+	if err != nil {
+		if errors.Is(err, service.ErrNothingToTranslate) {
+			log.Printf("Skipping translation of news %s: %v", newsID, err)
+			return nil
+		}
+		return fmt.Errorf("translation failed: %w", err)
+	}
+
+	log.Printf("Translated news %s: %s (model: %s, %d tokens)", newsID, translation.Title, translation.ModelUsed, translation.TokensUsed)
+	return nil
+}
+
+// finishNewsPipeline logs a pipeline outcome and enqueues the follow-ups of a re-embedded article
+func finishNewsPipeline(result *service.NewsPipelineResult) {
+	if result.ArticleID == nil {
+		log.Printf("News pipeline: news %s %s (%s)", result.NewsID, result.Decision, result.Reason)
+		return
+	}
+	log.Printf("News pipeline: news %s %s article %s (%s)", result.NewsID, result.Decision, result.ArticleID, result.Reason)
+	if result.Embedded {
+		enqueueEmbeddingFollowUps(result.ArticleID.String())
+	}
+	// News worth an article is worth reading in full
+	if pipelineConfig.Translate && taskClient != nil {
+		if _, err := EnqueueTranslate(taskClient, result.NewsID.String()); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+			log.Printf("Failed to enqueue translate task: %v", err)
+		}
+	}
+}
+
+// enqueueSummarize enqueues a summarize task after ingestion; failures are only logged
+func enqueueSummarize(payload SummarizePayload, opts ...asynq.Option) {
+	if taskClient == nil {
+		return
+	}
+	task, err := NewSummarizeTask(payload)
+	if err != nil {
+		log.Printf("Failed to create summarize task: %v", err)
+		return
+	}
+	opts = append([]asynq.Option{asynq.Queue("default")}, opts...)
+	if _, err := taskClient.Enqueue(task, opts...); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		log.Printf("Failed to enqueue summarize task: %v", err)
+	}
+}
+
+// enqueueNewsPipeline enqueues a news pipeline task after summarization; failures are only logged
+func enqueueNewsPipeline(payload NewsPipelinePayload, opts ...asynq.Option) {
+	if taskClient == nil {
+		return
+	}
+	if _, err := EnqueueNewsPipeline(taskClient, payload, opts...); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		log.Printf("Failed to enqueue news pipeline task: %v", err)
+	}
+}
+
+// enqueueSummarizeBatch enqueues a batch summarize task, at most one per minute
+func enqueueSummarizeBatch() {
+	enqueueSummarize(SummarizePayload{}, asynq.Unique(time.Minute))
+}
+
+// countNewItems sums the new items across collect results
+func countNewItems(results []*collector.CollectResult) int {
+	total := 0
+	for _, result := range results {
+		if result != nil {
+			total += result.ItemsNew
+		}
+	}
+	return total
+}
+
+// handleViewFlush writes buffered article view counts to the database
+func handleViewFlush(ctx context.Context, t *asynq.Task) error {
+	if viewCounter == nil {
+		return fmt.Errorf("view counter not initialized")
+	}
+
+	updated, err := viewCounter.Flush(ctx)
+	if err != nil {
+		return fmt.Errorf("view flush failed: %w", err)
+	}
+	if updated > 0 {
+		log.Printf("Flushed view counts for %d articles", updated)
+	}
+	return nil
+}
+
+// handleScheduledPublish publishes scheduled articles whose publish time has passed
+func handleScheduledPublish(ctx context.Context, t *asynq.Task) error {
+	if publisher == nil {
+		return fmt.Errorf("scheduled publisher not initialized")
+	}
+
+	published, err := publisher.PublishDue(time.Now())
+	if err != nil {
+		return err
+	}
+	if published > 0 {
+		log.Printf("Published %d scheduled articles", published)
+	}
+	return nil
+}
+
+// handleLLMCallCleanup applies the LLM call audit log retention policy
+func handleLLMCallCleanup(ctx context.Context, t *asynq.Task) error {
+	if llmCallRepo == nil {
+		return fmt.Errorf("LLM call repository not initialized")
+	}
+
+	deleted, err := service.PurgeLLMCalls(llmCallRepo, llmConfig.Audit.RetentionDays)
+	if err != nil {
+		return fmt.Errorf("LLM call cleanup failed: %w", err)
+	}
+	log.Printf("Deleted %d LLM calls past retention", deleted)
+	return nil
+}
+
+// handleFetchCleanup deletes the conditional-fetch validators of URLs not fetched for a while
+func handleFetchCleanup(ctx context.Context, t *asynq.Task) error {
+	if fetchCache == nil {
+		return fmt.Errorf("fetch cache not initialized")
+	}
+
+	deleted, err := fetchCache.Prune()
+	if err != nil {
+		return fmt.Errorf("fetch validator cleanup failed: %w", err)
+	}
+	log.Printf("Deleted %d stale fetch validators", deleted)
+	return nil
+}
+
+// handleSourceStats recounts the reliability stats of every data source for the last days
+func handleSourceStats(ctx context.Context, t *asynq.Task) error {
+	var payload SourceStatsPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if sourceStatsRepo == nil {
+		return fmt.Errorf("source stats repository not initialized")
+	}
+
+	days := payload.Days
+	if days <= 0 {
+		days = defaultSourceStatsDays
+	}
+
+	stored, err := sourceStatsRepo.Refresh(time.Now().AddDate(0, 0, 1-days))
+	if err != nil {
+		return fmt.Errorf("source stats refresh failed: %w", err)
+	}
+	log.Printf("Refreshed %d days of source stats", stored)
+	return nil
+}
+
+// handleCategoryEnrich fills in descriptions and icons of blank auto-created categories
+func handleCategoryEnrich(ctx context.Context, t *asynq.Task) error {
+	var payload CategoryEnrichPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if categoryEnricher == nil {
+		return fmt.Errorf("category enricher not initialized")
+	}
+
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = defaultCategoryEnrichLimit
+	}
+
+	enriched, err := categoryEnricher.EnrichBlank(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("category enrichment failed: %w", err)
+	}
+
+	if enriched > 0 {
+		log.Printf("Enriched %d auto-created categories", enriched)
+	}
+	return nil
+}
+
+// handleCategoryDedup finds pairs of categories that are likely synonyms
+func handleCategoryDedup(ctx context.Context, t *asynq.Task) error {
+	var payload CategoryDedupPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if categoryDeduper == nil {
+		return fmt.Errorf("category deduper not initialized")
+	}
+
+	duplicates, err := categoryDeduper.Detect(ctx, payload.Threshold)
+	if err != nil {
+		return fmt.Errorf("duplicate category detection failed: %w", err)
+	}
+
+	log.Printf("Found %d likely duplicate category pairs", len(duplicates))
+	return nil
+}
+
+// handleConsistencyCheck checks stored content for broken invariants and saves a report
+func handleConsistencyCheck(ctx context.Context, t *asynq.Task) error {
+	var payload ConsistencyCheckPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if consistency == nil {
+		return fmt.Errorf("consistency checker not initialized")
+	}
+
+	autoFix := consistencyFix
+	if payload.AutoFix != nil {
+		autoFix = *payload.AutoFix
+	}
+
+	report, err := consistency.Run(ctx, autoFix)
+	if err != nil {
+		return fmt.Errorf("consistency check failed: %w", err)
+	}
+	log.Printf("Consistency check completed: report=%s, issues=%d, fixed=%d", report.ID, report.IssueCount, report.FixedCount)
+	return nil
+}
+
+// handleSearchSync syncs changed articles and news to the search engine, if one is configured
+func handleSearchSync(ctx context.Context, t *asynq.Task) error {
+	if searchIndexer == nil {
+		return nil
+	}
+
+	result, err := searchIndexer.Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("search sync failed: %w", err)
+	}
+	if result.ArticlesIndexed+result.ArticlesDeleted > 0 {
+		log.Printf("Synced search index: %d articles indexed, %d deleted",
+			result.ArticlesIndexed, result.ArticlesDeleted)
+	}
+	return nil
+}
+
+// handleEmbeddingReindex re-embeds all articles with the worker's embedding model and swaps the vector columns
+func handleEmbeddingReindex(ctx context.Context, t *asynq.Task) error {
+	var payload EmbeddingReindexPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if reindexer == nil {
+		return fmt.Errorf("embedding reindexer not initialized")
+	}
+
+	taskID, err := uuid.Parse(payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("invalid task ID: %w", err)
+	}
+
+	progress, err := reindexer.Run(ctx, taskID, payload.BatchSize)
+	if errors.Is(err, service.ErrReindexCancelled) {
+		log.Printf("Embedding re-index %s cancelled after %d articles", taskID, progress.Embedded)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("embedding re-index failed: %w", err)
+	}
+	log.Printf("Embedding re-index %s completed: embedded=%d, failed=%d", taskID, progress.Embedded, progress.Failed)
+	return nil
+}
+
+// handleImport imports the article batch of an import task record
+func handleImport(ctx context.Context, t *asynq.Task) error {
+	var payload ImportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if importRunner == nil {
+		return fmt.Errorf("import runner not initialized")
+	}
+
+	taskID, err := uuid.Parse(payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("invalid task ID: %w", err)
+	}
+
+	result, err := importRunner.Run(ctx, taskID)
+	if errors.Is(err, service.ErrImportCancelled) {
+		log.Printf("Import %s cancelled after %d of %d articles", taskID, result.ProcessedCount, result.TotalCount)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	if result != nil {
+		log.Printf("Import %s completed: imported=%d, updated=%d, skipped=%d, errors=%d",
+			taskID, result.ImportedCount, result.UpdatedCount, result.SkippedCount, result.ErrorCount)
+	}
 	return nil
 }
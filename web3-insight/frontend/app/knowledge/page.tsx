@@ -1,3 +1,4 @@
+import { Suspense } from 'react'
 import { MainLayout } from '@/components/layout/main-layout'
 import { ArticleList } from '@/components/knowledge/article-list'
 
@@ -8,7 +9,10 @@ export default function KnowledgePage() {
         <div className="flex items-center justify-between mb-6">
           <h1 className="text-2xl font-semibold">知识库</h1>
         </div>
-        <ArticleList />
+        {/* The list reads the search query from the URL */}
+        <Suspense>
+          <ArticleList />
+        </Suspense>
       </div>
     </MainLayout>
   )
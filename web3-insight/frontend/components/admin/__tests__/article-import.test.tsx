@@ -8,9 +8,30 @@ import { server } from '@/__mocks__/server'
 import { ArticleImport } from '../article-import'
 import { mockCategories } from '@/__mocks__/data'
 
+// Imports run as background jobs: the POST handlers accept a job whose result is then
+// returned by the job status endpoint
+let importResult: object | null = null
+
+function acceptImport(result: object) {
+  importResult = result
+  return HttpResponse.json(
+    { id: 'job-1', status: 'pending', createdAt: '2024-01-01T00:00:00Z' },
+    { status: 202 }
+  )
+}
+
 // Add handlers for API endpoints
 beforeEach(() => {
+  importResult = null
   server.use(
+    http.get('/api/import/jobs/:id', ({ params }) => {
+      return HttpResponse.json({
+        id: params.id,
+        status: 'completed',
+        result: importResult,
+        createdAt: '2024-01-01T00:00:00Z',
+      })
+    }),
     http.get('/api/categories', () => {
       return HttpResponse.json(mockCategories)
     }),
@@ -25,7 +46,7 @@ beforeEach(() => {
     }),
     http.post('/api/import', async ({ request }) => {
       const body = await request.json() as { articles: unknown[] }
-      return HttpResponse.json({
+      return acceptImport({
         totalCount: body.articles?.length || 0,
         importedCount: body.articles?.length || 0,
         skippedCount: 0,
@@ -36,7 +57,7 @@ beforeEach(() => {
       })
     }),
     http.post('/api/import/upload', () => {
-      return HttpResponse.json({
+      return acceptImport({
         totalCount: 2,
         importedCount: 2,
         skippedCount: 0,
@@ -321,7 +342,7 @@ describe('ArticleImport', () => {
       // Override handler to return import with errors
       server.use(
         http.post('/api/import', () => {
-          return HttpResponse.json({
+          return acceptImport({
             totalCount: 2,
             importedCount: 1,
             skippedCount: 0,
@@ -482,7 +503,7 @@ describe('ArticleImport', () => {
       server.use(
         http.post('/api/import', async () => {
           await new Promise(resolve => setTimeout(resolve, 100))
-          return HttpResponse.json({
+          return acceptImport({
             totalCount: 1,
             importedCount: 1,
             skippedCount: 0,
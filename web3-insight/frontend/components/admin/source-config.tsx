@@ -24,7 +24,7 @@ import {
   SelectTrigger,
   SelectValue,
 } from '@/components/ui/select'
-import { dataSourceAPI, DataSource, CreateDataSourceRequest } from '@/lib/api'
+import { dataSourceAPI, DataSource, DataSourceType, CreateDataSourceRequest } from '@/lib/api'
 import { toast } from 'sonner'
 import { formatDistanceToNow } from 'date-fns'
 import { zhCN } from 'date-fns/locale'
@@ -140,6 +140,16 @@ export function SourceConfig() {
         return 'API'
       case 'crawl':
         return '爬虫'
+      case 'publication':
+        return 'Mirror/Paragraph'
+      case 'research':
+        return '论文'
+      case 'discourse':
+        return '论坛'
+      case 'governance':
+        return '治理提案'
+      case 'email':
+        return '邮件订阅'
       default:
         return type
     }
@@ -192,7 +202,7 @@ export function SourceConfig() {
                 <label className="text-sm font-medium">类型</label>
                 <Select
                   value={newSource.type}
-                  onValueChange={(value: 'rss' | 'api' | 'crawl') =>
+                  onValueChange={(value: DataSourceType) =>
                     setNewSource((prev) => ({ ...prev, type: value }))
                   }
                 >
@@ -203,6 +213,11 @@ export function SourceConfig() {
                     <SelectItem value="rss">RSS 订阅</SelectItem>
                     <SelectItem value="api">API</SelectItem>
                     <SelectItem value="crawl">网页爬虫</SelectItem>
+                    <SelectItem value="publication">Mirror / Paragraph</SelectItem>
+                    <SelectItem value="research">论文 (arXiv / ePrint)</SelectItem>
+                    <SelectItem value="discourse">Discourse 论坛</SelectItem>
+                    <SelectItem value="governance">治理提案 (Snapshot / Tally)</SelectItem>
+                    <SelectItem value="email">邮件订阅 (IMAP)</SelectItem>
                   </SelectContent>
                 </Select>
               </div>
@@ -2,13 +2,14 @@
 
 import { useQuery } from '@tanstack/react-query'
 import Link from 'next/link'
+import { useSearchParams } from 'next/navigation'
 import { Badge } from '@/components/ui/badge'
 import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
 import { ScrollArea } from '@/components/ui/scroll-area'
 import { Clock, FileText, AlertCircle, Inbox } from 'lucide-react'
 import { formatDistanceToNow } from 'date-fns'
 import { zhCN } from 'date-fns/locale'
-import { articleAPI, Article as APIArticle } from '@/lib/api'
+import { articleAPI, searchAPI, Article as APIArticle } from '@/lib/api'
 
 interface Article {
   id: string
@@ -38,17 +39,23 @@ function transformArticle(article: APIArticle): Article {
 }
 
 export function ArticleList() {
+  const q = useSearchParams().get('q')?.trim() || ''
   const { data, isLoading, isError } = useQuery({
-    queryKey: ['articles'],
+    queryKey: ['articles', q],
     queryFn: async () => {
+      if (q) {
+        const response = await searchAPI.articles(q)
+        return { articles: response.articles.map(transformArticle), searchId: response.searchId }
+      }
       const response = await articleAPI.list({ limit: 20 })
-      return response.data.map(transformArticle)
+      return { articles: response.data.map(transformArticle), searchId: undefined }
     },
     retry: 1,
     staleTime: 30000,
   })
 
-  const articles = data || []
+  const articles = data?.articles || []
+  const searchId = data?.searchId
 
   if (isLoading) {
     return (
@@ -95,7 +102,11 @@ export function ArticleList() {
       <ScrollArea className="h-[calc(100vh-200px)]">
         <div className="space-y-4">
           {articles.map((article) => (
-            <Link key={article.id} href={`/knowledge/${article.slug}`}>
+            <Link
+              key={article.id}
+              href={`/knowledge/${article.slug}`}
+              onClick={() => searchId && searchAPI.recordClick(searchId)}
+            >
               <Card className="hover:bg-muted/50 transition-colors cursor-pointer">
                 <CardHeader className="pb-2">
                   <div className="flex items-center justify-between">
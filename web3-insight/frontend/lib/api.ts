@@ -63,6 +63,29 @@ export interface ArticleListParams {
   q?: string
 }
 
+export interface SearchResponse {
+  articles: Article[]
+  totalHits: number
+  engine: string
+  searchId?: string // Set when searches are logged; report opened results with searchAPI.recordClick
+}
+
+// Search API
+export const searchAPI = {
+  articles: (q: string, limit = 20) =>
+    fetchAPI<SearchResponse>(`/api/search?${new URLSearchParams({ q, type: 'articles', limit: String(limit) })}`),
+
+  // Click-through analytics only; never let it get in the way of opening the result
+  recordClick: (searchId: string) => {
+    fetch(`${API_BASE}/api/search/click`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ searchId }),
+      keepalive: true,
+    }).catch(() => {})
+  },
+}
+
 // Article API
 export const articleAPI = {
   list: (params?: ArticleListParams) => {
@@ -109,10 +132,12 @@ export const categoryAPI = {
 }
 
 // Data Sources API
+export type DataSourceType = 'rss' | 'api' | 'crawl' | 'publication' | 'research' | 'discourse' | 'governance' | 'email'
+
 export interface DataSource {
   id: string
   name: string
-  type: 'rss' | 'api' | 'crawl'
+  type: DataSourceType
   url: string
   config?: Record<string, unknown>
   enabled: boolean
@@ -124,7 +149,7 @@ export interface DataSource {
 
 export interface CreateDataSourceRequest {
   name: string
-  type: 'rss' | 'api' | 'crawl'
+  type: DataSourceType
   url: string
   config?: Record<string, unknown>
   enabled?: boolean
@@ -200,12 +225,14 @@ export interface ImportBatch {
     updateExisting?: boolean
     generateSummary?: boolean
     defaultStatus?: string
+    contentDuplicates?: 'skip' | 'merge'
   }
 }
 
 export interface ImportError {
   index: number
   title: string
+  file?: string
   message: string
 }
 
@@ -215,8 +242,56 @@ export interface ImportResult {
   skippedCount: number
   updatedCount: number
   errorCount: number
+  processedCount?: number
   errors?: ImportError[]
   importedIds?: string[]
+  contentMatches?: ContentMatch[]
+}
+
+export interface ContentMatch {
+  index: number
+  title: string
+  file?: string
+  articleId: string
+  slug: string
+  action: 'skip' | 'merge'
+}
+
+export interface ImportJob {
+  id: string
+  status: 'pending' | 'running' | 'completed' | 'failed' | 'cancelled'
+  result?: ImportResult
+  error?: string
+  createdAt: string
+  startedAt?: string
+  completedAt?: string
+}
+
+const IMPORT_POLL_INTERVAL_MS = 1000
+const IMPORT_STALL_TIMEOUT_MS = 5 * 60 * 1000
+
+// Imports run in the background; poll the job until it finishes. Give up when it makes no
+// progress for a while, e.g. because no worker picked it up; the job itself keeps its place.
+async function waitForImportJob(job: ImportJob): Promise<ImportResult> {
+  let progress = ''
+  let progressAt = Date.now()
+  for (;;) {
+    job = await fetchAPI<ImportJob>(`/api/import/jobs/${job.id}`)
+    if (job.status === 'failed' || job.status === 'cancelled') {
+      throw new APIError(500, job.error || `Import ${job.status}`)
+    }
+    if (job.status === 'completed' && job.result) {
+      return job.result
+    }
+    const current = `${job.status}:${job.result?.processedCount ?? 0}`
+    if (current !== progress) {
+      progress = current
+      progressAt = Date.now()
+    } else if (Date.now() - progressAt > IMPORT_STALL_TIMEOUT_MS) {
+      throw new APIError(504, `Import ${job.id} made no progress for 5 minutes; check it later under /api/import/jobs/${job.id}`)
+    }
+    await new Promise(resolve => setTimeout(resolve, IMPORT_POLL_INTERVAL_MS))
+  }
 }
 
 export interface ValidationResult {
@@ -227,11 +302,15 @@ export interface ValidationResult {
 }
 
 export const importAPI = {
-  import: (batch: ImportBatch) =>
-    fetchAPI<ImportResult>('/api/import', {
+  import: async (batch: ImportBatch) => {
+    const job = await fetchAPI<ImportJob>('/api/import', {
       method: 'POST',
       body: JSON.stringify(batch),
-    }),
+    })
+    return waitForImportJob(job)
+  },
+
+  getJob: (id: string) => fetchAPI<ImportJob>(`/api/import/jobs/${id}`),
 
   validate: (batch: ImportBatch) =>
     fetchAPI<ValidationResult>('/api/import/validate', {
@@ -265,7 +344,7 @@ export const importAPI = {
       throw new APIError(res.status, errorText)
     }
 
-    return res.json() as Promise<ImportResult>
+    return waitForImportJob(await res.json() as ImportJob)
   },
 }
 
@@ -324,13 +403,23 @@ export interface CreateExplorerRequest {
   researchNotes?: string
 }
 
+const EXPLORER_PAGE_SIZE = 200
+
 export const explorerAPI = {
-  list: (chain?: string, status?: string) => {
-    const params = new URLSearchParams()
+  // The list is paginated; load every page, since the panel searches and counts them all
+  list: async (chain?: string, status?: string) => {
+    const params = new URLSearchParams({ limit: String(EXPLORER_PAGE_SIZE) })
     if (chain) params.set('chain', chain)
     if (status) params.set('status', status)
-    const query = params.toString()
-    return fetchAPI<{ data: ExplorerResearch[]; count: number }>(`/api/explorers${query ? `?${query}` : ''}`)
+
+    const data: ExplorerResearch[] = []
+    for (let page = 1; ; page++) {
+      params.set('page', String(page))
+      const res = await fetchAPI<{ data: ExplorerResearch[]; count: number; total: number }>(`/api/explorers?${params}`)
+      data.push(...res.data)
+      if (res.count < EXPLORER_PAGE_SIZE || data.length >= res.total) break
+    }
+    return { data, count: data.length }
   },
 
   get: (id: string) => fetchAPI<ExplorerResearch>(`/api/explorers/${id}`),